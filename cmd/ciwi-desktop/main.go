@@ -19,10 +19,11 @@ func main() {
 	address := flag.String("addr", strings.TrimSpace(os.Getenv("CIWI_NATIVE_SERVER")), "ciwi CNP endpoint ([quic|tcp]://host:port); discovers with mDNS when omitted")
 	theme := flag.String("theme", envOrDefault("CIWI_NATIVE_THEME", ""), "native UI theme (defaults to the saved preference)")
 	route := flag.String("route", envOrDefault("CIWI_NATIVE_ROUTE", ""), "initial native UI route, for example /projects/1 or /settings")
+	apiToken := strings.TrimSpace(os.Getenv("CIWI_API_TOKEN"))
 	flag.Parse()
 	done := make(chan error, 1)
 	go func() {
-		err := gioadapter.Run(gioadapter.Options{Address: *address, Theme: *theme, Version: version.Current(), Route: *route})
+		err := gioadapter.Run(gioadapter.Options{Address: *address, Theme: *theme, Version: version.Current(), Route: *route, APIToken: apiToken})
 		if finishDarwinRun(runtime.GOOS, err, os.Stderr, os.Exit) {
 			return
 		}
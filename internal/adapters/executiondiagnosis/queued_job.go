@@ -1,15 +1,62 @@
 package executiondiagnosis
 
 import (
+	"fmt"
+	"strings"
+	"time"
+
 	"github.com/izzyreal/ciwi/internal/protocol"
 	"github.com/izzyreal/ciwi/internal/requirements"
 )
 
+// Queue is the scheduler state a queued job is diagnosed against.
+type Queue struct {
+	// Queued holds the queued executions the job competes with for agents.
+	Queued []protocol.JobExecution
+	// Groups holds the executions of the concurrency groups of the diagnosed
+	// jobs, keyed by group.
+	Groups map[string][]protocol.JobExecution
+}
+
+type QueueStore interface {
+	ListQueuedJobExecutions() ([]protocol.JobExecution, error)
+	ListConcurrencyGroupExecutions(group string) ([]protocol.JobExecution, error)
+}
+
+// LoadQueue reads the queue state needed to diagnose jobs.
+func LoadQueue(store QueueStore, jobs []protocol.JobExecution) (Queue, error) {
+	queue := Queue{Groups: map[string][]protocol.JobExecution{}}
+	needsQueue := false
+	for _, job := range jobs {
+		if !protocol.IsQueuedJobExecutionStatus(job.Status) {
+			continue
+		}
+		needsQueue = true
+		group, _, ok := job.Metadata.ConcurrencyGroup()
+		if _, loaded := queue.Groups[group]; !ok || loaded {
+			continue
+		}
+		executions, err := store.ListConcurrencyGroupExecutions(group)
+		if err != nil {
+			return Queue{}, err
+		}
+		queue.Groups[group] = executions
+	}
+	if needsQueue {
+		queued, err := store.ListQueuedJobExecutions()
+		if err != nil {
+			return Queue{}, err
+		}
+		queue.Queued = queued
+	}
+	return queue, nil
+}
+
 // DiagnoseQueuedJob adapts the persisted execution representation to the
 // transport-neutral capability matcher. Keeping this bridge outside
 // requirements prevents presentation code from depending transitively on the
 // execution protocol.
-func DiagnoseQueuedJob(job protocol.JobExecution, agents []requirements.AgentSnapshot) *requirements.SchedulingDiagnosis {
+func DiagnoseQueuedJob(job protocol.JobExecution, agents []requirements.AgentSnapshot, queue Queue) *requirements.SchedulingDiagnosis {
 	if reason := protocol.JobSchedulingBlockedReason(job); reason != "" {
 		if protocol.IsPendingJobExecutionStatus(job.Status) {
 			return &requirements.SchedulingDiagnosis{State: requirements.DiagnosisWaiting, Summary: reason}
@@ -23,5 +70,81 @@ func DiagnoseQueuedJob(job protocol.JobExecution, agents []requirements.AgentSna
 		return nil
 	}
 	diagnosis := requirements.DiagnoseScheduling(job.RequiredCapabilities, agents)
+	if diagnosis.State == requirements.DiagnosisIncompatible {
+		return &diagnosis
+	}
+	group, maxRunning, _ := job.Metadata.ConcurrencyGroup()
+	if holders := protocol.ConcurrencyGroupBlockers(job, queue.Groups[group]); len(holders) > 0 {
+		diagnosis.State = requirements.DiagnosisWaiting
+		diagnosis.Summary = concurrencyGroupLabel(group, maxRunning, holders)
+		return &diagnosis
+	}
+	if preferred, until, started := protocol.AgentAffinityWait(job); started && time.Now().Before(until) && preferredAgentAvailable(job, preferred, agents) {
+		diagnosis.State = requirements.DiagnosisWaiting
+		diagnosis.Summary = fmt.Sprintf("Waiting for preferred agent %s, which last ran this job, until %s; other matching agents may take it after that",
+			preferred, until.UTC().Format(time.RFC3339))
+		return &diagnosis
+	}
+	if ahead := higherPriorityCompetitors(job, agents, queue.Queued); ahead > 0 {
+		diagnosis.State = requirements.DiagnosisWaiting
+		diagnosis.Summary = waitingBehindLabel(ahead) + "; " + diagnosis.Summary
+	}
 	return &diagnosis
 }
+
+// preferredAgentAvailable mirrors the lease handler: a job only waits for a
+// preferred agent that can still take it.
+func preferredAgentAvailable(job protocol.JobExecution, preferred string, agents []requirements.AgentSnapshot) bool {
+	for _, agent := range agents {
+		if strings.TrimSpace(agent.ID) == preferred {
+			return requirements.PreferredAgentAvailable(job.RequiredCapabilities, agent)
+		}
+	}
+	return false
+}
+
+func concurrencyGroupLabel(group string, maxRunning int, holders []protocol.JobExecution) string {
+	ids := make([]string, 0, len(holders))
+	for _, holder := range holders {
+		ids = append(ids, holder.ID)
+	}
+	if maxRunning == 1 {
+		return fmt.Sprintf("Concurrency group %q is held by %s", group, ids[0])
+	}
+	return fmt.Sprintf("Concurrency group %q is full (%d running): held by %s", group, maxRunning, strings.Join(ids, ", "))
+}
+
+// higherPriorityCompetitors counts leasable queued executions with a higher
+// priority than job that at least one of its matching agents could also run.
+func higherPriorityCompetitors(job protocol.JobExecution, agents []requirements.AgentSnapshot, queue []protocol.JobExecution) int {
+	priority := job.Metadata.Priority()
+	matching := make([]requirements.AgentSnapshot, 0, len(agents))
+	for _, agent := range agents {
+		if requirements.MatchAgent(job.RequiredCapabilities, agent).Matches {
+			matching = append(matching, agent)
+		}
+	}
+	count := 0
+	for _, other := range queue {
+		if other.ID == job.ID || other.Metadata.Priority() <= priority || !protocol.IsQueuedJobExecutionStatus(other.Status) {
+			continue
+		}
+		if protocol.IsJobWaitingForPrerequisites(other) || protocol.JobSchedulingBlockedReason(other) != "" {
+			continue
+		}
+		for _, agent := range matching {
+			if requirements.MatchAgent(other.RequiredCapabilities, agent).Matches {
+				count++
+				break
+			}
+		}
+	}
+	return count
+}
+
+func waitingBehindLabel(count int) string {
+	if count == 1 {
+		return "Waiting behind 1 higher-priority execution"
+	}
+	return fmt.Sprintf("Waiting behind %d higher-priority executions", count)
+}
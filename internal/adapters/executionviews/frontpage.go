@@ -17,6 +17,7 @@ import (
 
 type Store interface {
 	ListJobExecutionsContext(context.Context) ([]protocol.JobExecution, error)
+	ListPipelineJobExecutionsContext(ctx context.Context, pipelineRunID, pipelineJobID string) ([]protocol.JobExecution, error)
 	ListJobExecutionTestSummaries(context.Context, []string) (map[string]protocol.JobExecutionTestSummary, error)
 	GetJobExecution(string) (protocol.JobExecution, error)
 	ListJobExecutionTimelineEvents(string) ([]protocol.JobExecutionEvent, error)
@@ -31,6 +32,14 @@ type jobLogStore interface {
 	SearchJobLog(string, string, int64) (domain.JobLogSearchResult, error)
 }
 
+type testHistoryStore interface {
+	ListTestCaseRuns(domain.TestHistoryScope, int) ([]domain.TestCaseRun, error)
+}
+
+type coverageHistoryStore interface {
+	ListCoverageRuns(int64, int) ([]domain.CoverageRun, error)
+}
+
 type SchedulingAgentSource interface {
 	ListSchedulingAgents(context.Context) ([]requirements.AgentSnapshot, error)
 }
@@ -78,6 +87,28 @@ func (r *Repository) SearchJobLog(ctx context.Context, jobID, query string, sele
 	return store.SearchJobLog(jobID, query, selectedIndex)
 }
 
+func (r *Repository) ListTestCaseRuns(ctx context.Context, scope domain.TestHistoryScope, executionLimit int) ([]domain.TestCaseRun, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	store, ok := r.store.(testHistoryStore)
+	if !ok {
+		return nil, fmt.Errorf("test history store unavailable")
+	}
+	return store.ListTestCaseRuns(scope, executionLimit)
+}
+
+func (r *Repository) ListCoverageRuns(ctx context.Context, projectID int64, perScopeLimit int) ([]domain.CoverageRun, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	store, ok := r.store.(coverageHistoryStore)
+	if !ok {
+		return nil, fmt.Errorf("coverage history store unavailable")
+	}
+	return store.ListCoverageRuns(projectID, perScopeLimit)
+}
+
 func (r *Repository) ListJobOutputAfter(ctx context.Context, jobID string, afterEventID int64) (domain.JobOutputBatch, error) {
 	if err := ctx.Err(); err != nil {
 		return domain.JobOutputBatch{}, err
@@ -181,7 +212,18 @@ func (r *Repository) GetJobExecutionDetails(ctx context.Context, jobID string) (
 	if err != nil {
 		return domain.JobExecutionDetails{}, err
 	}
-	return mapJobExecutionDetails(job, events, artifacts, testReport, reportFound), nil
+	shardReports, err := r.shardTestReports(ctx, job, testReport, reportFound)
+	if err != nil {
+		return domain.JobExecutionDetails{}, err
+	}
+	if len(shardReports) > 1 {
+		testReport, reportFound = mergeShardTestReports(shardReports), true
+	}
+	details := mapJobExecutionDetails(job, events, artifacts, testReport, reportFound)
+	if details.TestReport != nil && len(shardReports) > 1 {
+		details.TestReport.Shards = len(shardReports)
+	}
+	return details, nil
 }
 
 func mapJobExecutionDetails(job protocol.JobExecution, events []protocol.JobExecutionEvent, artifacts []protocol.JobExecutionArtifact, testReport protocol.JobExecutionTestReport, reportFound bool) domain.JobExecutionDetails {
@@ -220,7 +262,24 @@ func mapJobExecutionDetails(job protocol.JobExecution, events []protocol.JobExec
 		}
 	}
 	terminal := protocol.IsTerminalJobExecutionStatus(details.Status)
-	for _, item := range protocol.BuildJobExecutionTimeline(job) {
+	timeline := protocol.BuildJobExecutionTimeline(job)
+	if cancellation, cancelled := job.Metadata.Cancellation(); cancelled && terminal {
+		// The item that was running when the server cancelled is the last one
+		// reached. Whatever the dying process reported for it, its outcome is
+		// the cancellation.
+		interruptedID := ""
+		for _, item := range timeline {
+			if states[item.ID].reached {
+				interruptedID = item.ID
+			}
+		}
+		if state, ok := states[interruptedID]; ok && state.status != "succeeded" && state.status != "skipped" {
+			state.status = "cancelled"
+			state.error = cancellation.Summary()
+			states[interruptedID] = state
+		}
+	}
+	for _, item := range timeline {
 		state := states[item.ID]
 		status := state.status
 		if status == "" {
@@ -236,11 +295,23 @@ func mapJobExecutionDetails(job protocol.JobExecution, events []protocol.JobExec
 		} else {
 			expectedDurationMS = job.StepExpectedDuration[item.StepIndex]
 		}
+		description := item.Description
+		if state.skipReason != "" {
+			description = state.skipReason
+		}
+		for _, earlier := range state.earlier {
+			details.Timeline = append(details.Timeline, domain.JobTimelineItem{
+				ID: fmt.Sprintf("%s:attempt:%d", item.ID, earlier.attempt), Kind: item.Kind, Name: item.Name, Description: description,
+				Index: item.Index, Total: item.Total, Reached: true, Status: earlier.status, StartedUTC: earlier.startedUTC,
+				DurationMS: earlier.durationMS, FinishedUTC: earlier.finishedUTC, ExitCode: copyInt(earlier.exitCode), Error: earlier.error,
+				Attempt: earlier.attempt, Retried: true,
+			})
+		}
 		timelineItem := domain.JobTimelineItem{
-			ID: item.ID, Kind: item.Kind, Name: item.Name, Description: item.Description,
+			ID: item.ID, Kind: item.Kind, Name: item.Name, Description: description,
 			Index: item.Index, Total: item.Total, Reached: state.reached, Status: status, StartedUTC: state.startedUTC, DurationMS: state.durationMS,
 			FinishedUTC: state.finishedUTC, ExpectedDurationMS: expectedDurationMS,
-			ExitCode: copyInt(state.exitCode), Error: state.error,
+			ExitCode: copyInt(state.exitCode), Error: state.error, Attempt: state.attempt,
 		}
 		if item.Kind == "step" {
 			if step, ok := stepsByIndex[item.StepIndex]; ok {
@@ -255,18 +326,19 @@ func mapJobExecutionDetails(job protocol.JobExecution, events []protocol.JobExec
 
 func mapJobTestReport(report protocol.JobExecutionTestReport) *domain.JobTestReport {
 	result := &domain.JobTestReport{
-		Total: report.Total, Passed: report.Passed, Failed: report.Failed, Skipped: report.Skipped,
+		Total: report.Total, Passed: report.Passed, Failed: report.Failed, Skipped: report.Skipped, Quarantined: report.Quarantined,
 		Suites: make([]domain.JobTestSuite, 0, len(report.Suites)),
 	}
 	for _, suite := range report.Suites {
 		presentedSuite := domain.JobTestSuite{
 			Name: suite.Name, Format: suite.Format, Total: suite.Total, Passed: suite.Passed, Failed: suite.Failed, Skipped: suite.Skipped,
-			Cases: make([]domain.JobTestCase, 0, len(suite.Cases)),
+			Quarantined: suite.Quarantined, Cases: make([]domain.JobTestCase, 0, len(suite.Cases)),
 		}
 		for _, testCase := range suite.Cases {
 			presentedSuite.Cases = append(presentedSuite.Cases, domain.JobTestCase{
 				Package: testCase.Package, Name: testCase.Name, File: testCase.File, Line: testCase.Line,
 				Status: testCase.Status, DurationSeconds: testCase.DurationSeconds, Output: testCase.Output,
+				Quarantined: testCase.Quarantined,
 			})
 		}
 		result.Suites = append(result.Suites, presentedSuite)
@@ -278,10 +350,20 @@ func mapJobTestReport(report protocol.JobExecutionTestReport) *domain.JobTestRep
 			Percent: report.Coverage.Percent, Files: make([]domain.JobCoverageFile, 0, len(report.Coverage.Files)),
 		}
 		for _, file := range report.Coverage.Files {
-			result.Coverage.Files = append(result.Coverage.Files, domain.JobCoverageFile{
+			coverageFile := domain.JobCoverageFile{
 				Path: file.Path, TotalLines: file.TotalLines, CoveredLines: file.CoveredLines,
 				TotalStatements: file.TotalStatements, CoveredStatements: file.CoveredStatements, Percent: file.Percent,
-			})
+			}
+			if len(file.Lines) > 0 {
+				coverageFile.Lines = make([]domain.JobCoverageLine, 0, len(file.Lines))
+				for _, line := range file.Lines {
+					coverageFile.Lines = append(coverageFile.Lines, domain.JobCoverageLine{Line: line.Line, Hits: line.Hits})
+				}
+			}
+			result.Coverage.Files = append(result.Coverage.Files, coverageFile)
+		}
+		if gate := report.Coverage.Gate; gate != nil {
+			result.Coverage.Gate = &domain.JobCoverageGate{Passed: gate.Passed, Message: gate.Message}
 		}
 	}
 	return result
@@ -306,6 +388,25 @@ type timelineState struct {
 	durationMS  int64
 	exitCode    *int
 	error       string
+	skipReason  string
+	attempt     int
+	// earlier holds the failed attempts of a retried step, oldest first.
+	earlier []timelineState
+}
+
+// skippedStepReason explains a finished step that did not run. Dry-run skips
+// recorded before steps carried a reason only have the event message.
+func skippedStepReason(event protocol.JobExecutionEvent) string {
+	if event.Step == nil {
+		return ""
+	}
+	if reason := strings.TrimSpace(event.Step.SkipReason); reason != "" {
+		return reason
+	}
+	if strings.TrimSpace(event.Step.Kind) == "dryrun_skip" {
+		return strings.TrimSpace(event.Message)
+	}
+	return ""
 }
 
 func timelineStates(events []protocol.JobExecutionEvent) map[string]timelineState {
@@ -321,6 +422,14 @@ func timelineStates(events []protocol.JobExecutionEvent) map[string]timelineStat
 			continue
 		}
 		state := states[id]
+		if event.Step != nil && event.Step.Attempt > state.attempt {
+			if state.attempt > 0 {
+				previous := state
+				previous.earlier = nil
+				state = timelineState{earlier: append(state.earlier, previous)}
+			}
+			state.attempt = event.Step.Attempt
+		}
 		state.reached = true
 		switch event.Type {
 		case protocol.JobExecutionEventTypePhaseStarted, protocol.JobExecutionEventTypeStepStarted:
@@ -330,6 +439,9 @@ func timelineStates(events []protocol.JobExecutionEvent) map[string]timelineStat
 			state.status = "succeeded"
 			if strings.TrimSpace(event.Error) != "" || (event.ExitCode != nil && *event.ExitCode != 0) {
 				state.status = "failed"
+			} else if reason := skippedStepReason(event); reason != "" {
+				state.status = "skipped"
+				state.skipReason = reason
 			}
 			state.durationMS = event.DurationMS
 			state.finishedUTC = event.TimestampUTC
@@ -445,7 +557,8 @@ func mapCards(cards []jobhistory.CardView) []domain.ExecutionCard {
 			JobExecutionIDs: append([]string(nil), card.JobExecutionIDs...),
 			Summary: domain.ExecutionSummary{
 				TotalJobs: card.Summary.TotalJobs, Succeeded: card.Summary.Succeeded,
-				Failed: card.Summary.Failed, InProgress: card.Summary.InProgress, Waiting: card.Summary.Waiting,
+				Failed: card.Summary.Failed, Cancelled: card.Summary.Cancelled, Skipped: card.Summary.Skipped,
+				InProgress: card.Summary.InProgress, Waiting: card.Summary.Waiting,
 			},
 			Sections: mapCardSections(card.Sections), ProgressJobs: mapProgressJobs(card.ProgressJobs),
 		})
@@ -522,6 +635,7 @@ func mapJobTestSummary(summary *protocol.JobExecutionTestSummary) *domain.JobTes
 	}
 	return &domain.JobTestSummary{
 		Total: summary.Total, Passed: summary.Passed, Failed: summary.Failed, Skipped: summary.Skipped,
+		Quarantined: summary.Quarantined,
 	}
 }
 
@@ -605,8 +719,14 @@ func (r *Repository) attachSchedulingDiagnoses(ctx context.Context, jobs []proto
 	if err != nil {
 		return err
 	}
+	var queue executiondiagnosis.Queue
+	if store, ok := r.store.(executiondiagnosis.QueueStore); ok {
+		if queue, err = executiondiagnosis.LoadQueue(store, jobs); err != nil {
+			return err
+		}
+	}
 	for i := range jobs {
-		jobs[i].SchedulingDiagnosis = executiondiagnosis.DiagnoseQueuedJob(jobs[i], agents)
+		jobs[i].SchedulingDiagnosis = executiondiagnosis.DiagnoseQueuedJob(jobs[i], agents, queue)
 	}
 	return nil
 }
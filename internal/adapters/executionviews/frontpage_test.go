@@ -69,6 +69,19 @@ func (s executionStoreStub) ListJobExecutionsContext(ctx context.Context) ([]pro
 	return append([]protocol.JobExecution(nil), s.jobs...), nil
 }
 
+func (s executionStoreStub) ListPipelineJobExecutionsContext(ctx context.Context, pipelineRunID, pipelineJobID string) ([]protocol.JobExecution, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	var jobs []protocol.JobExecution
+	for _, job := range s.jobs {
+		if job.Metadata.Value(domain.ExecutionMetadataPipelineRunID) == pipelineRunID && job.Metadata.Value(domain.ExecutionMetadataPipelineJobID) == pipelineJobID {
+			jobs = append(jobs, job)
+		}
+	}
+	return jobs, nil
+}
+
 func (s executionStoreStub) ListJobExecutionTestSummaries(ctx context.Context, ids []string) (map[string]protocol.JobExecutionTestSummary, error) {
 	if err := ctx.Err(); err != nil {
 		return nil, err
@@ -181,6 +194,90 @@ func TestRepositoryBuildsTransportNeutralJobTimeline(t *testing.T) {
 	}
 }
 
+func TestRepositoryMarksSkippedStepsWithTheirReason(t *testing.T) {
+	now := time.Now().UTC()
+	dryRunStep := protocol.JobStepPlanItem{Index: 1, Total: 3, Name: "Notarize", Kind: "dryrun_skip"}
+	conditionStep := protocol.JobStepPlanItem{Index: 2, Total: 3, Name: "Publish", Kind: "condition_skip", SkipReason: "condition not met: ciwi.source_ref == 'main'"}
+	failureStep := protocol.JobStepPlanItem{Index: 3, Total: 3, Name: "Upload crash dumps", Script: "./upload.sh", RunWhen: protocol.JobStepRunWhenFailure}
+	skippedFailureStep := failureStep
+	skippedFailureStep.SkipReason = "condition not met: failure()"
+	repository := NewRepository(executionStoreStub{
+		jobs: []protocol.JobExecution{{
+			ID: "job-1", Status: "succeeded", CreatedUTC: now, StartedUTC: now, FinishedUTC: now.Add(time.Second),
+			StepPlan: []protocol.JobStepPlanItem{dryRunStep, conditionStep, failureStep},
+		}},
+		events: map[string][]protocol.JobExecutionEvent{"job-1": {
+			{Type: protocol.JobExecutionEventTypeStepStarted, Step: &dryRunStep},
+			{Type: protocol.JobExecutionEventTypeStepFinished, Step: &dryRunStep, Message: "skipped during dry run"},
+			{Type: protocol.JobExecutionEventTypeStepStarted, Step: &conditionStep},
+			{Type: protocol.JobExecutionEventTypeStepFinished, Step: &conditionStep, Message: conditionStep.SkipReason},
+			{Type: protocol.JobExecutionEventTypeStepStarted, Step: &failureStep},
+			{Type: protocol.JobExecutionEventTypeStepFinished, Step: &skippedFailureStep, Message: skippedFailureStep.SkipReason},
+		}},
+	}, 40)
+	details, err := repository.GetJobExecutionDetails(context.Background(), "job-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{
+		"step:1": "skipped during dry run",
+		"step:2": "condition not met: ciwi.source_ref == 'main'",
+		"step:3": "condition not met: failure()",
+	}
+	for _, item := range details.Timeline {
+		reason, ok := want[item.ID]
+		if !ok {
+			continue
+		}
+		if item.Status != "skipped" || item.Description != reason {
+			t.Fatalf("timeline item %s = %+v", item.ID, item)
+		}
+		delete(want, item.ID)
+	}
+	if len(want) != 0 {
+		t.Fatalf("missing timeline items %v in %+v", want, details.Timeline)
+	}
+}
+
+func TestRepositoryListsEachAttemptOfARetriedStep(t *testing.T) {
+	now := time.Now().UTC()
+	planned := protocol.JobStepPlanItem{Index: 1, Total: 1, Name: "Sign", Script: "./sign.ps1", RetryMaxAttempts: 3}
+	first, second := planned, planned
+	first.Attempt, second.Attempt = 1, 2
+	exitCode := 7
+	repository := NewRepository(executionStoreStub{
+		jobs: []protocol.JobExecution{{
+			ID: "job-1", Status: "succeeded", CreatedUTC: now, StartedUTC: now, FinishedUTC: now.Add(3 * time.Second),
+			StepPlan: []protocol.JobStepPlanItem{planned},
+		}},
+		events: map[string][]protocol.JobExecutionEvent{"job-1": {
+			{Type: protocol.JobExecutionEventTypeStepStarted, Step: &first, TimestampUTC: now},
+			{Type: protocol.JobExecutionEventTypeStepFinished, Step: &first, ExitCode: &exitCode, Error: "exit=7", DurationMS: 1000, TimestampUTC: now.Add(time.Second)},
+			{Type: protocol.JobExecutionEventTypeStepStarted, Step: &second, TimestampUTC: now.Add(2 * time.Second)},
+			{Type: protocol.JobExecutionEventTypeStepFinished, Step: &second, DurationMS: 1000, TimestampUTC: now.Add(3 * time.Second)},
+		}},
+	}, 40)
+	details, err := repository.GetJobExecutionDetails(context.Background(), "job-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var steps []domain.JobTimelineItem
+	for _, item := range details.Timeline {
+		if item.Kind == "step" {
+			steps = append(steps, item)
+		}
+	}
+	if len(steps) != 2 {
+		t.Fatalf("expected one timeline entry per attempt, got %+v", steps)
+	}
+	if steps[0].ID != "step:1:attempt:1" || !steps[0].Retried || steps[0].Attempt != 1 || steps[0].Status != "failed" || steps[0].Error != "exit=7" {
+		t.Fatalf("unexpected first attempt entry: %+v", steps[0])
+	}
+	if steps[1].ID != "step:1" || steps[1].Retried || steps[1].Attempt != 2 || steps[1].Status != "succeeded" {
+		t.Fatalf("unexpected final attempt entry: %+v", steps[1])
+	}
+}
+
 func TestRepositoryUsesEstablishedExecutionGrouping(t *testing.T) {
 	now := time.Now().UTC()
 	repository := NewRepository(executionStoreStub{jobs: []protocol.JobExecution{
@@ -0,0 +1,189 @@
+package executionviews
+
+import (
+	"context"
+	"sort"
+
+	"github.com/izzyreal/ciwi/internal/domain"
+	"github.com/izzyreal/ciwi/internal/protocol"
+)
+
+// shardTestReports returns the test reports of every shard of job's matrix
+// entry, ordered by shard index. The job itself stands in for its shard.
+func (r *Repository) shardTestReports(ctx context.Context, job protocol.JobExecution, own protocol.JobExecutionTestReport, ownFound bool) ([]protocol.JobExecutionTestReport, error) {
+	ownIndex, total, ok := job.Metadata.Shard()
+	if !ok {
+		return nil, nil
+	}
+	jobs, err := r.store.ListPipelineJobExecutionsContext(ctx, job.Metadata.Value(domain.ExecutionMetadataPipelineRunID), job.Metadata.Value(domain.ExecutionMetadataPipelineJobID))
+	if err != nil {
+		return nil, err
+	}
+	// Reruns of a shard carry its index, so the newest execution per index
+	// is its latest attempt.
+	latest := map[int]protocol.JobExecution{}
+	for _, candidate := range jobs {
+		if candidate.ID == job.ID || !sameShardedEntry(job.Metadata, candidate.Metadata) {
+			continue
+		}
+		index, _, _ := candidate.Metadata.Shard()
+		if index == ownIndex {
+			continue
+		}
+		if current, ok := latest[index]; !ok || candidate.CreatedUTC.After(current.CreatedUTC) {
+			latest[index] = candidate
+		}
+	}
+	type shardReport struct {
+		index  int
+		report protocol.JobExecutionTestReport
+	}
+	reports := make([]shardReport, 0, total)
+	if ownFound {
+		reports = append(reports, shardReport{index: ownIndex, report: own})
+	}
+	for index, sibling := range latest {
+		report, found, err := r.store.GetJobExecutionTestReport(sibling.ID)
+		if err != nil {
+			return nil, err
+		}
+		if found {
+			reports = append(reports, shardReport{index: index, report: report})
+		}
+	}
+	sort.SliceStable(reports, func(i, j int) bool { return reports[i].index < reports[j].index })
+	out := make([]protocol.JobExecutionTestReport, 0, len(reports))
+	for _, report := range reports {
+		out = append(out, report.report)
+	}
+	return out, nil
+}
+
+func sameShardedEntry(a, b domain.ExecutionMetadata) bool {
+	if _, _, ok := b.Shard(); !ok {
+		return false
+	}
+	for _, key := range []string{
+		domain.ExecutionMetadataProjectID,
+		domain.ExecutionMetadataPipelineID,
+		domain.ExecutionMetadataPipelineRunID,
+		domain.ExecutionMetadataPipelineJobID,
+		domain.ExecutionMetadataMatrixIndex,
+		domain.ExecutionMetadataShardTotal,
+	} {
+		if a.Value(key) != b.Value(key) {
+			return false
+		}
+	}
+	return a.Value(domain.ExecutionMetadataPipelineRunID) != ""
+}
+
+// mergeShardTestReports combines shard reports into the report of the whole
+// job. Suites with the same name and format are joined, and coverage adds up
+// the hits of each line.
+func mergeShardTestReports(reports []protocol.JobExecutionTestReport) protocol.JobExecutionTestReport {
+	var merged protocol.JobExecutionTestReport
+	suiteIndex := map[[2]string]int{}
+	coverage := make([]*protocol.CoverageReport, 0, len(reports))
+	for _, report := range reports {
+		merged.Total += report.Total
+		merged.Passed += report.Passed
+		merged.Failed += report.Failed
+		merged.Skipped += report.Skipped
+		merged.Quarantined += report.Quarantined
+		for _, suite := range report.Suites {
+			key := [2]string{suite.Name, suite.Format}
+			pos, ok := suiteIndex[key]
+			if !ok {
+				suiteIndex[key] = len(merged.Suites)
+				suite.Cases = append([]protocol.TestCase(nil), suite.Cases...)
+				merged.Suites = append(merged.Suites, suite)
+				continue
+			}
+			target := &merged.Suites[pos]
+			target.Total += suite.Total
+			target.Passed += suite.Passed
+			target.Failed += suite.Failed
+			target.Skipped += suite.Skipped
+			target.Quarantined += suite.Quarantined
+			target.Cases = append(target.Cases, suite.Cases...)
+		}
+		if report.Coverage != nil {
+			coverage = append(coverage, report.Coverage)
+		}
+	}
+	merged.Coverage = mergeShardCoverage(coverage)
+	return merged
+}
+
+func mergeShardCoverage(reports []*protocol.CoverageReport) *protocol.CoverageReport {
+	if len(reports) == 0 {
+		return nil
+	}
+	if len(reports) == 1 {
+		return reports[0]
+	}
+	merged := &protocol.CoverageReport{Format: reports[0].Format}
+	files := map[string]*protocol.CoverageFileReport{}
+	hits := map[string]map[int]int{}
+	order := make([]string, 0)
+	for _, report := range reports {
+		if report.Gate != nil && (merged.Gate == nil || (merged.Gate.Passed && !report.Gate.Passed)) {
+			merged.Gate = report.Gate
+		}
+		for _, file := range report.Files {
+			target := files[file.Path]
+			if target == nil {
+				target = &protocol.CoverageFileReport{Path: file.Path}
+				files[file.Path] = target
+				hits[file.Path] = map[int]int{}
+				order = append(order, file.Path)
+			}
+			// Without per-line hits the shards cannot be combined exactly;
+			// the best covered shard is the closest lower bound.
+			target.TotalLines = max(target.TotalLines, file.TotalLines)
+			target.CoveredLines = max(target.CoveredLines, file.CoveredLines)
+			target.TotalStatements = max(target.TotalStatements, file.TotalStatements)
+			target.CoveredStatements = max(target.CoveredStatements, file.CoveredStatements)
+			for _, line := range file.Lines {
+				hits[file.Path][line.Line] += line.Hits
+			}
+		}
+	}
+	for _, path := range order {
+		file := files[path]
+		if lines := hits[path]; len(lines) > 0 {
+			file.Lines = make([]protocol.CoverageLine, 0, len(lines))
+			covered := 0
+			for line, count := range lines {
+				file.Lines = append(file.Lines, protocol.CoverageLine{Line: line, Hits: count})
+				if count > 0 {
+					covered++
+				}
+			}
+			sort.Slice(file.Lines, func(i, j int) bool { return file.Lines[i].Line < file.Lines[j].Line })
+			file.TotalLines = max(file.TotalLines, len(lines))
+			file.CoveredLines = covered
+		}
+		total, covered := file.TotalStatements, file.CoveredStatements
+		if total == 0 {
+			total, covered = file.TotalLines, file.CoveredLines
+		}
+		if total > 0 {
+			file.Percent = 100.0 * float64(covered) / float64(total)
+		}
+		merged.TotalLines += file.TotalLines
+		merged.CoveredLines += file.CoveredLines
+		merged.TotalStatements += file.TotalStatements
+		merged.CoveredStatements += file.CoveredStatements
+		merged.Files = append(merged.Files, *file)
+	}
+	total, covered := merged.TotalStatements, merged.CoveredStatements
+	if total == 0 {
+		total, covered = merged.TotalLines, merged.CoveredLines
+	}
+	if total > 0 {
+		merged.Percent = 100.0 * float64(covered) / float64(total)
+	}
+	return merged
+}
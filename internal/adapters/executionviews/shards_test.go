@@ -0,0 +1,67 @@
+package executionviews
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/izzyreal/ciwi/internal/domain"
+	"github.com/izzyreal/ciwi/internal/protocol"
+)
+
+func TestRepositoryCombinesShardReportsInJobDetails(t *testing.T) {
+	created := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	shardJob := func(id string, index int, createdOffset time.Duration, rerunOf string) protocol.JobExecution {
+		metadata := domain.ExecutionMetadata{
+			domain.ExecutionMetadataProjectID:     "1",
+			domain.ExecutionMetadataPipelineID:    "build",
+			domain.ExecutionMetadataPipelineRunID: "run-1",
+			domain.ExecutionMetadataPipelineJobID: "unit",
+			domain.ExecutionMetadataMatrixIndex:   "0",
+			domain.ExecutionMetadataShardIndex:    strconv.Itoa(index),
+			domain.ExecutionMetadataShardTotal:    "2",
+		}
+		if rerunOf != "" {
+			metadata[protocol.JobMetadataRerunOfJobID] = rerunOf
+		}
+		return protocol.JobExecution{ID: id, Status: "succeeded", Metadata: metadata, CreatedUTC: created.Add(createdOffset)}
+	}
+	suite := func(name string, passed, failed int) protocol.TestSuiteReport {
+		report := protocol.TestSuiteReport{Name: "unit", Format: "go-json", Total: passed + failed, Passed: passed, Failed: failed}
+		report.Cases = append(report.Cases, protocol.TestCase{Name: name, Status: "pass"})
+		return report
+	}
+	coverage := func(hits ...int) *protocol.CoverageReport {
+		file := protocol.CoverageFileReport{Path: "main.go", TotalLines: len(hits)}
+		for i, count := range hits {
+			file.Lines = append(file.Lines, protocol.CoverageLine{Line: i + 1, Hits: count})
+		}
+		return &protocol.CoverageReport{Format: "lcov", Files: []protocol.CoverageFileReport{file}}
+	}
+	repository := NewRepository(executionStoreStub{
+		jobs: []protocol.JobExecution{
+			shardJob("shard-0", 0, 0, ""),
+			shardJob("shard-1", 1, 0, ""),
+			shardJob("shard-1-rerun", 1, time.Minute, "shard-1"),
+		},
+		testReport: map[string]protocol.JobExecutionTestReport{
+			"shard-0":       {Total: 2, Passed: 2, Suites: []protocol.TestSuiteReport{suite("TestA", 2, 0)}, Coverage: coverage(1, 0, 0)},
+			"shard-1":       {Total: 1, Failed: 1, Suites: []protocol.TestSuiteReport{suite("TestB", 0, 1)}},
+			"shard-1-rerun": {Total: 1, Passed: 1, Suites: []protocol.TestSuiteReport{suite("TestB", 1, 0)}, Coverage: coverage(0, 2, 0)},
+		},
+	}, 40)
+	details, err := repository.GetJobExecutionDetails(t.Context(), "shard-0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	report := details.TestReport
+	if report == nil || report.Shards != 2 || report.Total != 3 || report.Passed != 3 || report.Failed != 0 {
+		t.Fatalf("expected the latest attempt of each shard to be combined, got %+v", report)
+	}
+	if len(report.Suites) != 1 || len(report.Suites[0].Cases) != 2 || report.Suites[0].Total != 3 {
+		t.Fatalf("expected one combined suite, got %+v", report.Suites)
+	}
+	if report.Coverage == nil || report.Coverage.CoveredLines != 2 || report.Coverage.TotalLines != 3 || len(report.Coverage.Files[0].Lines) != 3 {
+		t.Fatalf("expected line hits to add up across shards, got %+v", report.Coverage)
+	}
+}
@@ -30,6 +30,8 @@ type Options struct {
 	Theme   string
 	Version string
 	Route   string
+	// APIToken is presented to servers that have user accounts.
+	APIToken string
 }
 
 type commandRequest struct {
@@ -45,10 +47,32 @@ type navigationState struct {
 	jobID          string
 	sourceRef      string
 	agentID        string
+	priority       string
 	agentDetailsID string
 	agentScriptID  string
 	scriptShell    string
 	script         string
+	quarantineForm testQuarantineForm
+	labelsDraft    *string
+}
+
+// agentLabelsDraft is the text of the agent labels editor: what someone typed
+// and has not saved yet, otherwise the labels assigned on the server.
+func (n navigationState) agentLabelsDraft(assigned string) string {
+	if n.labelsDraft != nil {
+		return *n.labelsDraft
+	}
+	return assigned
+}
+
+// testQuarantineForm keeps the project details quarantine form across the
+// refreshes that queue and history changes trigger while someone types.
+type testQuarantineForm struct {
+	pkg, name, owner, reason, expires string
+}
+
+func (f testQuarantineForm) binding() map[string]any {
+	return map[string]any{"package": f.pkg, "name": f.name, "owner": f.owner, "reason": f.reason, "expires": f.expires}
 }
 
 type screenLoadResult struct {
@@ -491,7 +515,7 @@ func Run(options Options) error {
 	if err != nil {
 		return err
 	}
-	ctx, cancel := context.WithCancel(context.Background())
+	ctx, cancel := context.WithCancel(cnpclient.WithAPIToken(context.Background(), options.APIToken))
 	defer cancel()
 	clientBroker := newNativeClientBroker()
 	operationJournal := newNativeOperationJournal(preferencesPath, clientBroker.ServerInstallationID)
@@ -1167,6 +1191,13 @@ func runController(ctx context.Context, window *app.Window, renderer nativeRende
 			renderer.SetRootBinding("settings", field, effect.Message)
 			renderer.SetRootBinding("settings", field+"_tone", "success")
 		}
+		if operation.Command == "quarantine-test" && navigation.screen == "project-details" {
+			navigation.quarantineForm = testQuarantineForm{}
+			renderer.SetRootBinding("projectDetails", "quarantine_form", navigation.quarantineForm.binding())
+		}
+		if operation.Command == "agent-action" && operation.Arguments["action"] == "set-labels" && navigation.screen == "agent-details" {
+			navigation.labelsDraft = nil
+		}
 		if effect.CancelledJob != "" && navigation.screen == "job-details" && navigation.jobID == effect.CancelledJob {
 			pendingCancellations[effect.CancelledJob] = true
 			renderer.SetRootBinding("jobDetails", "can_cancel", false)
@@ -1525,6 +1556,19 @@ func runController(ctx context.Context, window *app.Window, renderer nativeRende
 				}
 				preserveOutput := navigation.screen == "job-details" && result.navigation.screen == "job-details" &&
 					navigation.jobID == result.navigation.jobID && outputBuffer.jobID == result.navigation.jobID
+				if navigation.screen == "project-details" && result.navigation.screen == "project-details" && navigation.projectID == result.navigation.projectID {
+					result.navigation.quarantineForm = navigation.quarantineForm
+					if root, ok := result.data["projectDetails"].(map[string]any); ok {
+						root["quarantine_form"] = navigation.quarantineForm.binding()
+					}
+				}
+				if navigation.screen == "agent-details" && result.navigation.screen == "agent-details" &&
+					navigation.agentDetailsID == result.navigation.agentDetailsID && navigation.labelsDraft != nil {
+					result.navigation.labelsDraft = navigation.labelsDraft
+					if root, ok := result.data["agentDetails"].(map[string]any); ok {
+						root["labels_draft"] = *navigation.labelsDraft
+					}
+				}
 				navigation = result.navigation
 				pendingNavigation = nil
 				screenCache.Put(navigation, result.data)
@@ -2137,6 +2181,29 @@ func handleCommand(renderer nativeRenderer, navigation *navigationState, command
 			return
 		}
 		renderer.SetRootBinding("settings", binding, command.arguments["value"])
+	case "set-agent-labels-draft":
+		value := command.arguments["value"]
+		navigation.labelsDraft = &value
+		renderer.SetRootBinding("agentDetails", "labels_draft", value)
+	case "set-test-quarantine-field":
+		value := command.arguments["value"]
+		field := strings.TrimSpace(command.arguments["field"])
+		switch field {
+		case "package":
+			navigation.quarantineForm.pkg = value
+		case "name":
+			navigation.quarantineForm.name = value
+		case "owner":
+			navigation.quarantineForm.owner = value
+		case "reason":
+			navigation.quarantineForm.reason = value
+		case "expires":
+			navigation.quarantineForm.expires = value
+		default:
+			renderer.ShowAlert("Invalid action", "Unknown test quarantine field.")
+			return
+		}
+		renderer.SetNestedBinding("projectDetails", "quarantine_form", field, value)
 	case "set-managed-yaml-field":
 		if strings.TrimSpace(command.arguments["field"]) != "yaml" {
 			renderer.ShowAlert("Invalid action", "Unknown managed YAML field.")
@@ -2194,6 +2261,10 @@ func applyRunOptionSelection(renderer nativeRenderer, navigation *navigationStat
 		navigation.agentID = value
 		renderer.SetRootBinding("runOptions", "selected_agent_id", value)
 		return false, nil
+	case "priority":
+		navigation.priority = value
+		renderer.SetRootBinding("runOptions", "selected_priority", value)
+		return false, nil
 	default:
 		return false, fmt.Errorf("unsupported run option")
 	}
@@ -2206,7 +2277,19 @@ func pipelineRunSelection(arguments map[string]string) *cnpv1.RunPipelineSelecti
 		SourceRef:     strings.TrimSpace(arguments["sourceRef"]),
 		AgentId:       strings.TrimSpace(arguments["agentId"]),
 		ExecutionMode: strings.TrimSpace(arguments["executionMode"]),
+		Priority:      runPriority(arguments["priority"]),
+	}
+}
+
+// runPriority parses a priority override; empty keeps the configured
+// priorities.
+func runPriority(raw string) *int32 {
+	priority, err := strconv.ParseInt(strings.TrimSpace(raw), 10, 32)
+	if err != nil {
+		return nil
 	}
+	value := int32(priority)
+	return &value
 }
 
 func splitExecutionIDs(raw string) []string {
@@ -37,7 +37,12 @@ func loadScreenData(ctx context.Context, client *cnpclient.Client, navigation na
 		if err != nil {
 			return nil, err
 		}
-		return projectDetailsBindingData(view)
+		data, err := projectDetailsBindingData(view)
+		if err != nil {
+			return nil, err
+		}
+		data["projectDetails"].(map[string]any)["quarantine_form"] = navigation.quarantineForm.binding()
+		return data, nil
 	case "job-details":
 		requestCtx, cancel := context.WithTimeout(ctx, 8*time.Second)
 		defer cancel()
@@ -67,7 +72,12 @@ func loadScreenData(ctx context.Context, client *cnpclient.Client, navigation na
 		if err != nil {
 			return nil, err
 		}
-		return protobufBindingData("agentDetails", "agent details", view)
+		data, err := protobufBindingData("agentDetails", "agent details", view)
+		if err != nil {
+			return nil, err
+		}
+		data["agentDetails"].(map[string]any)["labels_draft"] = navigation.agentLabelsDraft(view.GetAgent().GetAssignedLabels())
+		return data, nil
 	case "agent-script":
 		return loadAgentScriptData(ctx, client, navigation)
 	case "vault":
@@ -212,7 +222,9 @@ func loadRunOptions(ctx context.Context, client *cnpclient.Client, navigation na
 	defer cancel()
 	view, err := client.GetRunOptions(requestCtx, &cnpv1.GetRunOptionsRequest{
 		PipelineDbId: navigation.pipelineDBID, ProjectId: navigation.projectID, ChainId: navigation.chainID,
-		Selection: &cnpv1.RunPipelineSelection{SourceRef: navigation.sourceRef, AgentId: navigation.agentID},
+		Selection: &cnpv1.RunPipelineSelection{
+			SourceRef: navigation.sourceRef, AgentId: navigation.agentID, Priority: runPriority(navigation.priority),
+		},
 	})
 	if err != nil {
 		return nil, err
@@ -230,7 +242,8 @@ func runOptionsLoadingData(navigation navigationState) map[string]any {
 		"project_id": navigation.projectID, "chain_id": navigation.chainID, "supports_dry_run": false,
 		"source_repo": "Fetching source branches and eligible agents…", "default_source_ref": "",
 		"selected_source_ref": navigation.sourceRef, "selected_agent_id": navigation.agentID,
-		"source_refs": []any{}, "eligible_agents": []any{}, "pending_jobs": float64(0),
+		"selected_priority": navigation.priority, "source_refs": []any{}, "eligible_agents": []any{},
+		"priority_options": []any{}, "pending_jobs": float64(0),
 	}}
 }
 
@@ -283,9 +296,14 @@ func screenLoadingBindingData(navigation navigationState, clientVersion, themeNa
 	case "agents":
 		return protobufBindingData("agents", "agents", &cnpv1.AgentsView{})
 	case "agent-details":
-		return protobufBindingData("agentDetails", "agent details", &cnpv1.AgentDetailsView{
+		data, err := protobufBindingData("agentDetails", "agent details", &cnpv1.AgentDetailsView{
 			Agent: &cnpv1.AgentSummary{Id: navigation.agentDetailsID},
 		})
+		if err != nil {
+			return nil, err
+		}
+		data["agentDetails"].(map[string]any)["labels_draft"] = navigation.agentLabelsDraft("")
+		return data, nil
 	case "agent-script":
 		return map[string]any{"agentScript": map[string]any{
 			"agent_id": navigation.agentScriptID, "agent_label": navigation.agentScriptID,
@@ -522,6 +540,11 @@ func decorateProjectDetails(root map[string]any) {
 			project["project_icon_content_type"] = contentType
 		}
 	}
+	quarantine, _ := root["test_quarantine"].([]any)
+	root["test_quarantine_empty"] = len(quarantine) == 0
+	coverageTrends, _ := root["coverage_trends"].([]any)
+	root["coverage_trends_empty"] = len(coverageTrends) == 0
+	root["quarantine_form"] = testQuarantineForm{}.binding()
 	applyProjectStructureFilter(root, "all-pipelines")
 }
 
@@ -643,7 +666,7 @@ func jobDetailsBindingData(view *cnpv1.JobDetailsView) (map[string]any, error) {
 					continue
 				}
 				status := strings.ToLower(fmt.Sprint(entry["status"]))
-				if status == "running" || status == "in progress" || status == "failed" {
+				if status == "running" || status == "in progress" || status == "failed" || status == "cancelled" {
 					selected = item
 					break
 				}
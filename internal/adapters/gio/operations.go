@@ -11,6 +11,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/izzyreal/ciwi/internal/domain"
 	"github.com/izzyreal/ciwi/internal/presentation"
 	"github.com/izzyreal/ciwi/internal/presentation/operations"
 	cnpv1 "github.com/izzyreal/ciwi/pkg/cnp/v1"
@@ -107,6 +108,8 @@ type nativeActionClient interface {
 	AgentAction(context.Context, *cnpv1.AgentActionRequest, string) (*cnpv1.AgentActionResult, error)
 	RunAgentScript(context.Context, *cnpv1.RunAgentScriptRequest, string) (*cnpv1.RunAgentScriptResult, error)
 	ProjectAction(context.Context, int64, string, string) (*cnpv1.ProjectActionResult, error)
+	AddTestQuarantine(context.Context, *cnpv1.AddTestQuarantineRequest, string) (*cnpv1.TestQuarantineEntry, error)
+	RemoveTestQuarantine(context.Context, int64, int64, string) (*cnpv1.RemoveTestQuarantineResult, error)
 	ImportProject(context.Context, *cnpv1.ImportProjectRequest, string) (*cnpv1.ImportProjectResult, error)
 	ValidateManagedYAML(context.Context, *cnpv1.ManagedYAMLRequest) (*cnpv1.ManagedYAMLDefinition, error)
 	SaveManagedYAML(context.Context, *cnpv1.ManagedYAMLRequest, string) (*cnpv1.ManagedYAMLDefinition, error)
@@ -192,6 +195,21 @@ func validateNativeOperation(operation operations.Operation) error {
 			return err
 		}
 		return require("action", "project action")
+	case "quarantine-test":
+		if _, err := positiveInt64(arguments["projectId"], "project identifier"); err != nil {
+			return err
+		}
+		for _, item := range [][2]string{{"name", "test name"}, {"owner", "owner"}, {"expires", "expiry date"}} {
+			if err := require(item[0], item[1]); err != nil {
+				return err
+			}
+		}
+	case "remove-test-quarantine":
+		if _, err := positiveInt64(arguments["projectId"], "project identifier"); err != nil {
+			return err
+		}
+		_, err := positiveInt64(arguments["id"], "quarantine entry identifier")
+		return err
 	case "import-project":
 		return require("repoUrl", "repository URL")
 	case "validate-managed-yaml", "save-managed-yaml":
@@ -333,7 +351,24 @@ func executeNativeOperation(ctx context.Context, client nativeActionClient, oper
 		}
 		commandCtx, cancel := context.WithTimeout(ctx, 15*time.Second)
 		defer cancel()
-		result, err := client.AgentAction(commandCtx, &cnpv1.AgentActionRequest{AgentId: agentID, Action: action}, key)
+		request := &cnpv1.AgentActionRequest{AgentId: agentID, Action: action}
+		if raw := strings.TrimSpace(arguments["slots"]); raw != "" {
+			slots, err := strconv.ParseUint(raw, 10, 32)
+			if err != nil {
+				return nativeOperationEffect{}, fmt.Errorf("agent slots must be a number")
+			}
+			request.Slots = uint32(slots)
+		}
+		if action == "set-labels" {
+			labels, err := domain.ParseAgentLabels(arguments["labels"])
+			if err != nil {
+				return nativeOperationEffect{}, err
+			}
+			for key, value := range labels {
+				request.Labels = append(request.Labels, &cnpv1.AgentLabel{Key: key, Value: value})
+			}
+		}
+		result, err := client.AgentAction(commandCtx, request, key)
 		if err != nil {
 			return nativeOperationEffect{}, fmt.Errorf("agent action: %w", err)
 		}
@@ -380,6 +415,36 @@ func executeNativeOperation(ctx context.Context, client nativeActionClient, oper
 			message = "Reloaded successfully"
 		}
 		return nativeOperationEffect{Message: message, Refresh: true}, nil
+	case "quarantine-test":
+		projectID, err := positiveInt64(arguments["projectId"], "project identifier")
+		if err != nil {
+			return nativeOperationEffect{}, err
+		}
+		commandCtx, cancel := context.WithTimeout(ctx, 15*time.Second)
+		defer cancel()
+		result, err := client.AddTestQuarantine(commandCtx, &cnpv1.AddTestQuarantineRequest{
+			ProjectId: projectID, Package: strings.TrimSpace(arguments["package"]), Name: strings.TrimSpace(arguments["name"]),
+			Owner: strings.TrimSpace(arguments["owner"]), Reason: strings.TrimSpace(arguments["reason"]), Expires: strings.TrimSpace(arguments["expires"]),
+		}, key)
+		if err != nil {
+			return nativeOperationEffect{}, fmt.Errorf("quarantine test: %w", err)
+		}
+		return nativeOperationEffect{Message: "Quarantined " + result.TestLabel, Refresh: true, Notice: true}, nil
+	case "remove-test-quarantine":
+		projectID, err := positiveInt64(arguments["projectId"], "project identifier")
+		if err != nil {
+			return nativeOperationEffect{}, err
+		}
+		id, err := positiveInt64(arguments["id"], "quarantine entry identifier")
+		if err != nil {
+			return nativeOperationEffect{}, err
+		}
+		commandCtx, cancel := context.WithTimeout(ctx, 15*time.Second)
+		defer cancel()
+		if _, err := client.RemoveTestQuarantine(commandCtx, projectID, id, key); err != nil {
+			return nativeOperationEffect{}, fmt.Errorf("remove test quarantine: %w", err)
+		}
+		return nativeOperationEffect{Message: "Removed test from quarantine", Refresh: true, Notice: true}, nil
 	case "import-project":
 		repoURL := strings.TrimSpace(arguments["repoUrl"])
 		if repoURL == "" {
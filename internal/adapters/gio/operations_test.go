@@ -61,6 +61,12 @@ func (c *recordingNativeActionClient) RunAgentScript(_ context.Context, request
 func (c *recordingNativeActionClient) ProjectAction(_ context.Context, _ int64, _ string, key string) (*cnpv1.ProjectActionResult, error) {
 	return &cnpv1.ProjectActionResult{Message: "Project reloaded"}, c.record("project-action", key)
 }
+func (c *recordingNativeActionClient) AddTestQuarantine(_ context.Context, request *cnpv1.AddTestQuarantineRequest, key string) (*cnpv1.TestQuarantineEntry, error) {
+	return &cnpv1.TestQuarantineEntry{ProjectId: request.ProjectId, TestLabel: request.Name}, c.record("quarantine-test", key)
+}
+func (c *recordingNativeActionClient) RemoveTestQuarantine(_ context.Context, projectID, id int64, key string) (*cnpv1.RemoveTestQuarantineResult, error) {
+	return &cnpv1.RemoveTestQuarantineResult{ProjectId: projectID, Id: id}, c.record("remove-test-quarantine", key)
+}
 func (c *recordingNativeActionClient) ImportProject(_ context.Context, _ *cnpv1.ImportProjectRequest, key string) (*cnpv1.ImportProjectResult, error) {
 	return &cnpv1.ImportProjectResult{ProjectName: "ciwi"}, c.record("import-project", key)
 }
@@ -211,8 +217,11 @@ func TestExecuteNativeOperationMapsEveryCommandFamily(t *testing.T) {
 		{command: "rerun-execution", arguments: map[string]string{"jobExecutionId": "job-1"}, wantCall: "rerun-execution", wantNotice: "/jobs/job-1-rerun", wantNoticeEnabled: true},
 		{command: "agent-action", arguments: map[string]string{"agentId": "agent-1", "action": "restart"}, wantCall: "agent-action", wantNoticeEnabled: true},
 		{command: "agent-action", arguments: map[string]string{"agentId": "agent-1", "action": "delete", "successRoute": "/agents"}, wantCall: "agent-action", wantRoute: "/agents", wantNoticeEnabled: true, wantReplaceRoute: true},
+		{command: "agent-action", arguments: map[string]string{"agentId": "agent-1", "action": "set-labels", "labels": "dongle=yes, site=lab"}, wantCall: "agent-action", wantNoticeEnabled: true},
 		{command: "run-agent-script", arguments: map[string]string{"agentId": "agent-1", "shell": "posix", "script": "uname -a"}, wantCall: "run-agent-script", wantRoute: "/jobs/job-script", wantNotice: "/jobs/job-script", wantNoticeEnabled: true},
 		{command: "project-action", arguments: map[string]string{"projectId": "2", "action": "reload"}, wantCall: "project-action"},
+		{command: "quarantine-test", arguments: map[string]string{"projectId": "2", "name": "TestFlaky", "owner": "alice", "expires": "2099-01-31"}, wantCall: "quarantine-test", wantNoticeEnabled: true},
+		{command: "remove-test-quarantine", arguments: map[string]string{"projectId": "2", "id": "5"}, wantCall: "remove-test-quarantine", wantNoticeEnabled: true},
 		{command: "import-project", arguments: map[string]string{"repoUrl": "https://example.com/ciwi.git"}, wantCall: "import-project"},
 		{command: "save-vault-connection", arguments: map[string]string{"name": "home-vault", "url": "https://vault.example", "roleId": "role", "secretIdEnv": "CIWI_VAULT_SECRET_ID"}, wantCall: "save-vault-connection", wantNoticeEnabled: true},
 		{command: "test-vault-connection", arguments: map[string]string{"id": "7"}, wantCall: "test-vault-connection", wantNoticeEnabled: true},
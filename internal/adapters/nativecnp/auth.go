@@ -0,0 +1,41 @@
+package nativecnp
+
+import (
+	"context"
+	"strings"
+
+	"github.com/izzyreal/ciwi/internal/application"
+	cnpv1 "github.com/izzyreal/ciwi/pkg/cnp/v1"
+)
+
+// authenticate attaches the principal of apiToken to ctx once the server has
+// user accounts. The token is resolved per request so revocations and role
+// changes apply to open sessions too.
+func (s *Handler) authenticate(ctx context.Context, apiToken string) (context.Context, error) {
+	if s.services.Auth == nil {
+		return ctx, nil
+	}
+	enabled, err := s.services.Auth.Enabled(ctx)
+	if err != nil || !enabled {
+		return ctx, err
+	}
+	if strings.TrimSpace(apiToken) == "" {
+		return ctx, application.NewError(application.ErrorUnauthenticated, "this server requires an API token", nil)
+	}
+	principal, err := s.services.Auth.AuthenticateToken(ctx, apiToken)
+	if err != nil {
+		return ctx, err
+	}
+	return application.WithPrincipal(ctx, principal), nil
+}
+
+// requestOperation names the operation of request after its oneof field,
+// which is how the application policy table knows it.
+func requestOperation(request *cnpv1.Request) application.Operation {
+	message := request.ProtoReflect()
+	field := message.WhichOneof(message.Descriptor().Oneofs().ByName("operation"))
+	if field == nil {
+		return ""
+	}
+	return application.Operation(field.Name())
+}
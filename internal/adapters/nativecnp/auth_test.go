@@ -0,0 +1,24 @@
+package nativecnp
+
+import (
+	"testing"
+
+	"github.com/izzyreal/ciwi/internal/application"
+	cnpv1 "github.com/izzyreal/ciwi/pkg/cnp/v1"
+)
+
+func TestEveryNativeOperationHasAnAction(t *testing.T) {
+	fields := (&cnpv1.Request{}).ProtoReflect().Descriptor().Oneofs().ByName("operation").Fields()
+	for i := 0; i < fields.Len(); i++ {
+		if op := application.Operation(fields.Get(i).Name()); !application.KnownOperation(op) {
+			t.Errorf("native operation %q is missing from the application policy table", op)
+		}
+	}
+	request := &cnpv1.Request{Operation: &cnpv1.Request_CancelExecution{CancelExecution: &cnpv1.ControlExecutionRequest{}}}
+	if got := application.OperationAction(requestOperation(request)); got != application.ActionControlExecutions {
+		t.Fatalf("cancel_execution action = %q", got)
+	}
+	if got := application.OperationAction(requestOperation(&cnpv1.Request{})); got != application.ActionAdminister {
+		t.Fatalf("empty request action = %q", got)
+	}
+}
@@ -60,6 +60,7 @@ func projectDetailsToProto(view presentation.ProjectDetailsView) *cnpv1.ProjectD
 			DependsOn: append([]string{}, pipeline.DependsOn...), Dependencies: pipeline.Dependencies,
 			JobsCount: uint32(max(pipeline.JobsCount, 0)), SupportsDryRun: pipeline.SupportsDryRun, Jobs: jobs,
 			SummaryLabel: pipeline.SummaryLabel, GraphSummaryLabel: pipeline.GraphSummary,
+			TriggerLabel: pipeline.TriggerLabel, TriggerError: pipeline.TriggerError,
 		})
 	}
 	filters := make([]*cnpv1.ProjectStructureFilter, 0, len(view.StructureFilters))
@@ -78,7 +79,37 @@ func projectDetailsToProto(view presentation.ProjectDetailsView) *cnpv1.ProjectD
 		project.SourceMetadata = view.ProjectLabels.SourceMetadata
 		project.HasPipelineChains = view.ProjectLabels.HasPipelineChains
 	}
-	return &cnpv1.ProjectDetailsView{Project: project, Pipelines: pipelines, StructureFilters: filters, HistoryExecutions: executionCardsToProto(view.HistoryExecutions, false)}
+	quarantine := make([]*cnpv1.TestQuarantineEntry, 0, len(view.TestQuarantine))
+	for _, entry := range view.TestQuarantine {
+		quarantine = append(quarantine, testQuarantineEntryToProto(entry))
+	}
+	coverageTrends := make([]*cnpv1.CoverageTrend, 0, len(view.CoverageTrends))
+	for _, trend := range view.CoverageTrends {
+		coverageTrends = append(coverageTrends, coverageTrendToProto(trend))
+	}
+	return &cnpv1.ProjectDetailsView{
+		Project: project, Pipelines: pipelines, StructureFilters: filters, HistoryExecutions: executionCardsToProto(view.HistoryExecutions, false),
+		TestQuarantine: quarantine, TestQuarantineNotices: append([]string(nil), view.TestQuarantineNotices...),
+		CoverageTrends: coverageTrends,
+	}
+}
+
+func coverageTrendToProto(trend presentation.CoverageTrendView) *cnpv1.CoverageTrend {
+	points := make([]*cnpv1.CoverageTrendPoint, 0, len(trend.Points))
+	for _, point := range trend.Points {
+		points = append(points, &cnpv1.CoverageTrendPoint{
+			JobExecutionId: point.JobExecutionID, PercentLabel: point.PercentLabel, DeltaLabel: point.DeltaLabel,
+			DateLabel: point.DateLabel, Tone: point.Tone, Progress: progressToProto(point.Progress),
+		})
+	}
+	return &cnpv1.CoverageTrend{Key: trend.Key, Label: trend.Label, Summary: trend.Summary, Tone: trend.Tone, Points: points}
+}
+
+func testQuarantineEntryToProto(entry presentation.TestQuarantineEntryView) *cnpv1.TestQuarantineEntry {
+	return &cnpv1.TestQuarantineEntry{
+		Id: entry.ID, ProjectId: entry.ProjectID, Package: entry.Package, Name: entry.Name, TestLabel: entry.TestLabel,
+		Owner: entry.Owner, Reason: entry.Reason, ExpiresLabel: entry.ExpiresLabel, Expired: entry.Expired, Tone: entry.Tone,
+	}
 }
 
 func jobDetailsToProto(view presentation.JobDetailsView) *cnpv1.JobDetailsView {
@@ -152,12 +183,28 @@ func treeNodesToProto(nodes []presentation.TreeNodeView) []*cnpv1.TreeNode {
 		result = append(result, &cnpv1.TreeNode{
 			Key: node.Key, Label: node.Label, Detail: node.Detail, Tone: node.Tone, Link: node.Link,
 			ActionLabel: node.ActionLabel, ActionKind: node.ActionKind, ActionPath: node.ActionPath,
-			DefaultExpanded: node.DefaultExpanded, FilterValues: append([]string(nil), node.FilterValues...), Children: treeNodesToProto(node.Children),
+			DefaultExpanded: node.DefaultExpanded, FilterValues: append([]string(nil), node.FilterValues...), Badge: node.Badge, Children: treeNodesToProto(node.Children),
 		})
 	}
 	return result
 }
 
+func testHistoryToProto(view presentation.TestHistoryView) *cnpv1.TestHistoryView {
+	tests := make([]*cnpv1.TestHistoryTest, 0, len(view.Tests))
+	for _, test := range view.Tests {
+		tests = append(tests, &cnpv1.TestHistoryTest{
+			Key: test.Key, Package: test.Package, Name: test.Name, Status: test.Status, StatusLabel: test.StatusLabel, Tone: test.Tone,
+			Badge: test.Badge, BadgeLabel: test.BadgeLabel, RunsLabel: test.RunsLabel, StreakLabel: test.StreakLabel,
+			FlakinessScore: test.FlakinessScore, FlakinessLabel: test.FlakinessLabel, DurationLabel: test.DurationLabel,
+			RecentStatuses: append([]string(nil), test.RecentStatuses...), LatestJobExecutionId: test.LatestJobExecutionID,
+		})
+	}
+	return &cnpv1.TestHistoryView{
+		ProjectId: view.ProjectID, PipelineId: view.PipelineID, PipelineJobId: view.PipelineJobID, MatrixName: view.MatrixName,
+		Title: view.Title, Summary: view.Summary, EmptyLabel: view.EmptyLabel, Tests: tests,
+	}
+}
+
 func jobRunContextToProto(view protocol.JobExecutionGraphContext) *cnpv1.JobRunContext {
 	pipelines := make([]*cnpv1.JobRunContextPipeline, 0, len(view.Pipelines))
 	for _, pipeline := range view.Pipelines {
@@ -169,6 +216,11 @@ func jobRunContextToProto(view protocol.JobExecutionGraphContext) *cnpv1.JobRunC
 				if matrixLabel == "" && strings.TrimSpace(execution.MatrixIndex) != "" {
 					matrixLabel = "index-" + strings.TrimSpace(execution.MatrixIndex)
 				}
+				if shardLabel := strings.TrimSpace(execution.ShardLabel); shardLabel != "" && matrixLabel != "" {
+					matrixLabel += " · " + shardLabel
+				} else if shardLabel != "" {
+					matrixLabel = shardLabel
+				}
 				if matrixLabel == "" {
 					matrixLabel = "job"
 				}
@@ -184,7 +236,7 @@ func jobRunContextToProto(view protocol.JobExecutionGraphContext) *cnpv1.JobRunC
 			}
 			jobs = append(jobs, &cnpv1.JobRunContextJob{
 				Id: job.PipelineJobID, Needs: append([]string(nil), job.Needs...), Status: job.Status,
-				SummaryLabel: fmt.Sprintf("%s · %d execution(s)", job.Status, len(job.Executions)), Executions: executions,
+				SummaryLabel: job.SummaryLabel(), Executions: executions,
 			})
 		}
 		pipelines = append(pipelines, &cnpv1.JobRunContextPipeline{
@@ -309,7 +361,8 @@ func executionCardsToProto(cards []domain.ExecutionCard, queued bool) []*cnpv1.E
 			JobExecutionIds: append([]string(nil), card.JobExecutionIDs...),
 			Summary: &cnpv1.ExecutionSummary{
 				TotalJobs: uint32(card.Summary.TotalJobs), Succeeded: uint32(card.Summary.Succeeded),
-				Failed: uint32(card.Summary.Failed), InProgress: uint32(card.Summary.InProgress),
+				Failed: uint32(card.Summary.Failed), Cancelled: uint32(card.Summary.Cancelled),
+				Skipped: uint32(card.Summary.Skipped), InProgress: uint32(card.Summary.InProgress),
 				Waiting: uint32(card.Summary.Waiting),
 			},
 			Sections: executionCardSectionsToProto(card.Sections, now), Progress: progressToProto(card.Progress),
@@ -420,6 +473,10 @@ func runPipelineRequestFromProto(request *cnpv1.RunPipelineRequest, idempotencyK
 		result.SourceRef = selection.SourceRef
 		result.AgentID = selection.AgentId
 		result.ExecutionMode = selection.ExecutionMode
+		if selection.Priority != nil {
+			priority := int(selection.GetPriority())
+			result.Priority = &priority
+		}
 	}
 	return result
 }
@@ -442,6 +499,10 @@ func runPipelineChainRequestFromProto(request *cnpv1.RunPipelineChainRequest, id
 		result.SourceRef = selection.SourceRef
 		result.AgentID = selection.AgentId
 		result.ExecutionMode = selection.ExecutionMode
+		if selection.Priority != nil {
+			priority := int(selection.GetPriority())
+			result.Priority = &priority
+		}
 	}
 	return result
 }
@@ -465,6 +526,10 @@ func runOptionsRequestFromProto(request *cnpv1.GetRunOptionsRequest) application
 		result.SourceRef = selection.SourceRef
 		result.AgentID = selection.AgentId
 		result.ExecutionMode = selection.ExecutionMode
+		if selection.Priority != nil {
+			priority := int(selection.GetPriority())
+			result.Priority = &priority
+		}
 	}
 	return result
 }
@@ -476,7 +541,8 @@ func runOptionsToProto(options application.RunOptions) *cnpv1.RunOptionsView {
 		SourceRepo: options.SourceRepo, DefaultSourceRef: options.DefaultSourceRef,
 		SourceRefs: runOptionListToProto(options.SourceRefs), EligibleAgents: runOptionListToProto(options.EligibleAgents),
 		PendingJobs: uint32(max(options.PendingJobs, 0)), SelectedSourceRef: options.SelectedSourceRef,
-		SelectedAgentId: options.SelectedAgentID,
+		SelectedAgentId: options.SelectedAgentID, PriorityOptions: runOptionListToProto(options.PriorityOptions),
+		SelectedPriority: options.SelectedPriority,
 	}
 }
 
@@ -501,6 +567,10 @@ func agentSummaryToProto(agent presentation.AgentView) *cnpv1.AgentSummary {
 	for _, shell := range agent.ScriptShells {
 		shells = append(shells, &cnpv1.AgentScriptShell{Value: shell.Value, Label: shell.Label, ExampleScript: shell.ExampleScript})
 	}
+	slotOptions := make([]*cnpv1.AgentSlotOption, 0, len(agent.SlotOptions))
+	for _, option := range agent.SlotOptions {
+		slotOptions = append(slotOptions, &cnpv1.AgentSlotOption{Value: option.Value, Label: option.Label})
+	}
 	return &cnpv1.AgentSummary{
 		Id: agent.ID, Hostname: agent.Hostname, Platform: agent.Platform, Version: agent.Version,
 		Status: agent.Status, StatusLabel: agent.StatusLabel, Authorization: agent.Authorization,
@@ -509,9 +579,22 @@ func agentSummaryToProto(agent presentation.AgentView) *cnpv1.AgentSummary {
 		RunMode: agent.RunMode, LastSeen: agent.LastSeen, LastSeenUnixMs: agent.LastSeenUnixMS, RecentLog: agent.RecentLog,
 		UpdateLabel: agent.UpdateLabel, CanUpdate: agent.CanUpdate, CanContact: agent.CanContact,
 		CanRunScript: agent.CanRunScript, ScriptShells: shells,
+		Slots: uint32(max(agent.Slots, 0)), ActiveJobs: uint32(max(agent.ActiveJobs, 0)), SlotsLabel: agent.SlotsLabel,
+		SelectedSlots: agent.SelectedSlots, SlotOptions: slotOptions, LabelsLabel: agent.LabelsLabel, AssignedLabels: agent.AssignedLabels,
 	}
 }
 
+func agentLabelsFromProto(labels []*cnpv1.AgentLabel) map[string]string {
+	if len(labels) == 0 {
+		return nil
+	}
+	out := make(map[string]string, len(labels))
+	for _, label := range labels {
+		out[label.GetKey()] = label.GetValue()
+	}
+	return out
+}
+
 func agentDetailsToProto(view presentation.AgentDetailsView) *cnpv1.AgentDetailsView {
 	return &cnpv1.AgentDetailsView{Agent: agentSummaryToProto(view.Agent)}
 }
@@ -524,11 +607,11 @@ func managedYAMLToProto(definition protocol.ManagedYAMLDefinition) *cnpv1.Manage
 	}
 }
 
-func vaultConnectionToProto(connection protocol.VaultConnection) *cnpv1.VaultConnection {
+func vaultConnectionToProto(connection application.VaultConnection) *cnpv1.VaultConnection {
 	return &cnpv1.VaultConnection{
 		Id: connection.ID, Name: connection.Name, Url: connection.URL, AuthMethod: connection.AuthMethod,
 		ApproleMount: connection.AppRoleMount, RoleId: connection.RoleID, SecretIdEnv: connection.SecretIDEnv,
-		Namespace: connection.Namespace, KvDefaultMount: connection.KVDefaultMount, KvDefaultVersion: int32(connection.KVDefaultVer),
+		Namespace: connection.Namespace, KvDefaultMount: connection.KVDefaultMount, KvDefaultVersion: int32(connection.KVDefaultVersion),
 	}
 }
 
@@ -584,6 +667,10 @@ func errorToProto(err error) *cnpv1.ErrorStatus {
 		code = cnpv1.StatusCode_STATUS_CODE_UNAVAILABLE
 	case application.ErrorUnsupported:
 		code = cnpv1.StatusCode_STATUS_CODE_UNSUPPORTED
+	case application.ErrorUnauthenticated:
+		code = cnpv1.StatusCode_STATUS_CODE_UNAUTHENTICATED
+	case application.ErrorPermissionDenied:
+		code = cnpv1.StatusCode_STATUS_CODE_PERMISSION_DENIED
 	}
 	return &cnpv1.ErrorStatus{Code: code, Message: err.Error()}
 }
@@ -33,10 +33,10 @@ type Services struct {
 		SaveManagedYAML(context.Context, int64, string, string, string) (protocol.ManagedYAMLDefinition, error)
 	}
 	Vault interface {
-		ListVaultConnections(context.Context) ([]protocol.VaultConnection, error)
-		UpsertVaultConnection(context.Context, protocol.UpsertVaultConnectionRequest) (protocol.VaultConnection, error)
-		TestVaultConnection(context.Context, int64, string) (protocol.TestVaultConnectionResponse, error)
-		DeleteVaultConnection(context.Context, int64) error
+		List(context.Context) ([]application.VaultConnection, error)
+		Upsert(context.Context, application.UpsertVaultConnectionRequest) (application.VaultConnection, error)
+		Test(context.Context, application.TestVaultConnectionRequest) (application.TestVaultConnectionResult, error)
+		Delete(context.Context, int64) error
 	}
 	Updates interface {
 		Status(context.Context) (application.ServerUpdateStatus, error)
@@ -62,6 +62,13 @@ type Services struct {
 		GetJobLogPage(context.Context, string, string, domain.JobLogPageMode, int64) (domain.JobLogPage, error)
 		SearchJobLog(context.Context, string, string, int64) (domain.JobLogSearchResult, error)
 	}
+	TestHistory interface {
+		GetTestHistoryView(context.Context, application.TestHistoryRequest) (presentation.TestHistoryView, error)
+	}
+	TestQuarantine interface {
+		Add(context.Context, application.AddTestQuarantineRequest) (domain.TestQuarantineEntry, error)
+		Remove(context.Context, application.RemoveTestQuarantineRequest) (application.RemoveTestQuarantineResult, error)
+	}
 	ArtifactDownloads application.ArtifactDownloadService
 	JobContexts       interface {
 		GetJobExecutionGraphContext(context.Context, string) (protocol.JobExecutionGraphContext, error)
@@ -97,6 +104,11 @@ type Services struct {
 	CommandReceipts interface {
 		Get(context.Context, string) (application.CommandReceiptStatus, error)
 	}
+	// Auth is optional; without it every session is trusted.
+	Auth interface {
+		Enabled(context.Context) (bool, error)
+		AuthenticateToken(context.Context, string) (domain.Principal, error)
+	}
 	Changes *application.ChangeHub
 	Version string
 }
@@ -124,6 +136,11 @@ func (s *Handler) ServeSession(ctx context.Context, session cnp.Session) {
 		_ = session.CloseWithError(fmt.Errorf("valid hello required"))
 		return
 	}
+	apiToken := message.GetHello().GetApiToken()
+	if _, err := s.authenticate(ctx, apiToken); err != nil {
+		_ = session.CloseWithError(fmt.Errorf("authentication failed: %w", err))
+		return
+	}
 	snapshot := s.services.Changes.Snapshot()
 	serverInfo, _ := s.services.Server.GetServerInfo(ctx)
 	welcome := &cnpv1.ServerMessage{Body: &cnpv1.ServerMessage_Welcome{Welcome: &cnpv1.Welcome{
@@ -132,7 +149,7 @@ func (s *Handler) ServeSession(ctx context.Context, session cnp.Session) {
 		ServerInstanceId:     snapshot.InstanceID,
 		ServerInstallationId: serverInfo.InstallationID,
 		Capabilities: []string{
-			"server_info", "server_updates", "projects", "project_actions", "project_import", "managed_yaml", "vault", "front_page", "project_icons_batch", "project_details", "job_details", "artifact_downloads", "artifact_download_resume_v1", "job_output_stream", "job_log_v1", "run_pipeline", "run_pipeline_chain", "run_options", "agents", "agent_details", "agent_actions", "agent_scripts", "execution_housekeeping", "execution_controls", "command_receipts", "watch_changes",
+			"server_info", "server_updates", "projects", "project_actions", "project_import", "managed_yaml", "vault", "front_page", "project_icons_batch", "project_details", "job_details", "artifact_downloads", "artifact_download_resume_v1", "job_output_stream", "job_log_v1", "test_history", "test_quarantine", "run_pipeline", "run_pipeline_chain", "run_options", "agents", "agent_details", "agent_actions", "agent_scripts", "execution_housekeeping", "execution_controls", "command_receipts", "watch_changes",
 		},
 	}}}
 	if err := writeFrame(stream, welcome); err != nil {
@@ -146,11 +163,11 @@ func (s *Handler) ServeSession(ctx context.Context, session cnp.Session) {
 		if err != nil {
 			return
 		}
-		go s.handleRequestStream(ctx, stream)
+		go s.handleRequestStream(ctx, stream, apiToken)
 	}
 }
 
-func (s *Handler) handleRequestStream(parent context.Context, stream cnp.Stream) {
+func (s *Handler) handleRequestStream(parent context.Context, stream cnp.Stream, apiToken string) {
 	defer stream.Close()
 	reader := newFrameReader(stream)
 	var message cnpv1.ClientMessage
@@ -170,6 +187,16 @@ func (s *Handler) handleRequestStream(parent context.Context, stream cnp.Stream)
 	}
 	defer cancel()
 
+	ctx, err := s.authenticate(ctx, apiToken)
+	if err == nil {
+		err = application.NewAuthorizer(s.services.Auth).Authorize(ctx, application.OperationAction(requestOperation(request)))
+	}
+	if err != nil {
+		response := &cnpv1.Response{RequestId: request.Metadata.RequestId, Result: &cnpv1.Response_Error{Error: errorToProto(err)}}
+		logNativeUnaryRequest(ctx, request, response, 0)
+		_ = writeFrame(stream, &cnpv1.ServerMessage{Body: &cnpv1.ServerMessage_Response{Response: response}})
+		return
+	}
 	if _, watch := request.Operation.(*cnpv1.Request_WatchChanges); watch {
 		s.writeChanges(ctx, stream, request.Metadata.RequestId, monitorPeerClose(stream))
 		return
@@ -509,6 +536,54 @@ func (s *Handler) execute(ctx context.Context, request *cnpv1.Request) *cnpv1.Re
 		if err == nil {
 			response.Result = &cnpv1.Response_JobLogSearch{JobLogSearch: jobLogSearchToProto(result)}
 		}
+	case *cnpv1.Request_GetTestHistory:
+		var view presentation.TestHistoryView
+		if s.services.TestHistory == nil {
+			err = application.NewError(application.ErrorUnavailable, "test history service unavailable", nil)
+		} else {
+			request := operation.GetTestHistory
+			view, err = s.services.TestHistory.GetTestHistoryView(ctx, application.TestHistoryRequest{
+				Scope: domain.TestHistoryScope{
+					ProjectID: request.GetProjectId(), PipelineID: request.GetPipelineId(),
+					PipelineJobID: request.GetPipelineJobId(), MatrixName: request.GetMatrixName(),
+				},
+				Query: request.GetQuery(),
+			})
+		}
+		if err == nil {
+			response.Result = &cnpv1.Response_TestHistory{TestHistory: testHistoryToProto(view)}
+		}
+	case *cnpv1.Request_AddTestQuarantine:
+		var entry domain.TestQuarantineEntry
+		if s.services.TestQuarantine == nil {
+			err = application.NewError(application.ErrorUnavailable, "test quarantine unavailable", nil)
+		} else {
+			add := operation.AddTestQuarantine
+			entry, err = s.services.TestQuarantine.Add(ctx, application.AddTestQuarantineRequest{
+				ProjectID: add.GetProjectId(), Package: add.GetPackage(), Name: add.GetName(), Owner: add.GetOwner(),
+				Reason: add.GetReason(), Expires: add.GetExpires(), IdempotencyKey: request.Metadata.IdempotencyKey,
+			})
+		}
+		if err == nil {
+			response.Result = &cnpv1.Response_TestQuarantineEntry{
+				TestQuarantineEntry: testQuarantineEntryToProto(presentation.PresentTestQuarantineEntry(entry, time.Now().UTC())),
+			}
+		}
+	case *cnpv1.Request_RemoveTestQuarantine:
+		var result application.RemoveTestQuarantineResult
+		if s.services.TestQuarantine == nil {
+			err = application.NewError(application.ErrorUnavailable, "test quarantine unavailable", nil)
+		} else {
+			result, err = s.services.TestQuarantine.Remove(ctx, application.RemoveTestQuarantineRequest{
+				ProjectID: operation.RemoveTestQuarantine.GetProjectId(), ID: operation.RemoveTestQuarantine.GetId(),
+				IdempotencyKey: request.Metadata.IdempotencyKey,
+			})
+		}
+		if err == nil {
+			response.Result = &cnpv1.Response_RemoveTestQuarantine{
+				RemoveTestQuarantine: &cnpv1.RemoveTestQuarantineResult{ProjectId: result.ProjectID, Id: result.ID},
+			}
+		}
 	case *cnpv1.Request_DownloadArtifact:
 		var chunk application.ArtifactDownloadChunk
 		chunk, err = s.services.ArtifactDownloads.DownloadArtifact(ctx, application.ArtifactDownloadRequest{
@@ -566,6 +641,7 @@ func (s *Handler) execute(ctx context.Context, request *cnpv1.Request) *cnpv1.Re
 		var result application.AgentActionResult
 		result, err = s.services.AgentCommands.Execute(ctx, application.AgentActionRequest{
 			AgentID: operation.AgentAction.GetAgentId(), Action: operation.AgentAction.GetAction(),
+			Slots: int(operation.AgentAction.GetSlots()), Labels: agentLabelsFromProto(operation.AgentAction.GetLabels()),
 			IdempotencyKey: request.Metadata.IdempotencyKey,
 		})
 		if err == nil {
@@ -628,8 +704,8 @@ func (s *Handler) execute(ctx context.Context, request *cnpv1.Request) *cnpv1.Re
 			response.Result = &cnpv1.Response_ManagedYaml{ManagedYaml: managedYAMLToProto(definition)}
 		}
 	case *cnpv1.Request_ListVaultConnections:
-		var connections []protocol.VaultConnection
-		connections, err = s.services.Vault.ListVaultConnections(ctx)
+		var connections []application.VaultConnection
+		connections, err = s.services.Vault.List(ctx)
 		if err == nil {
 			items := make([]*cnpv1.VaultConnection, 0, len(connections))
 			for _, connection := range connections {
@@ -639,24 +715,26 @@ func (s *Handler) execute(ctx context.Context, request *cnpv1.Request) *cnpv1.Re
 		}
 	case *cnpv1.Request_UpsertVaultConnection:
 		request := operation.UpsertVaultConnection
-		var connection protocol.VaultConnection
-		connection, err = s.services.Vault.UpsertVaultConnection(ctx, protocol.UpsertVaultConnectionRequest{
+		var connection application.VaultConnection
+		connection, err = s.services.Vault.Upsert(ctx, application.UpsertVaultConnectionRequest{
 			Name: request.GetName(), URL: request.GetUrl(), AuthMethod: request.GetAuthMethod(), AppRoleMount: request.GetApproleMount(),
 			RoleID: request.GetRoleId(), SecretIDEnv: request.GetSecretIdEnv(), Namespace: request.GetNamespace(),
-			KVDefaultMount: request.GetKvDefaultMount(), KVDefaultVer: int(request.GetKvDefaultVersion()),
+			KVDefaultMount: request.GetKvDefaultMount(), KVDefaultVersion: int(request.GetKvDefaultVersion()),
 		})
 		if err == nil {
 			response.Result = &cnpv1.Response_VaultConnection{VaultConnection: vaultConnectionToProto(connection)}
 		}
 	case *cnpv1.Request_TestVaultConnection:
-		var result protocol.TestVaultConnectionResponse
-		result, err = s.services.Vault.TestVaultConnection(ctx, operation.TestVaultConnection.GetId(), operation.TestVaultConnection.GetSecretIdOverride())
+		var result application.TestVaultConnectionResult
+		result, err = s.services.Vault.Test(ctx, application.TestVaultConnectionRequest{
+			ID: operation.TestVaultConnection.GetId(), SecretIDOverride: operation.TestVaultConnection.GetSecretIdOverride(),
+		})
 		if err == nil {
 			response.Result = &cnpv1.Response_TestVaultConnection{TestVaultConnection: &cnpv1.TestVaultConnectionResult{Ok: result.OK, Message: result.Message}}
 		}
 	case *cnpv1.Request_DeleteVaultConnection:
 		id := operation.DeleteVaultConnection.GetId()
-		err = s.services.Vault.DeleteVaultConnection(ctx, id)
+		err = s.services.Vault.Delete(ctx, id)
 		if err == nil {
 			response.Result = &cnpv1.Response_DeleteVaultConnection{DeleteVaultConnection: &cnpv1.DeleteVaultConnectionResult{Deleted: true, Id: id}}
 		}
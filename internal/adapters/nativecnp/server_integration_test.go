@@ -563,16 +563,16 @@ type commandReceiptService struct{}
 
 type vaultServiceStub struct{}
 
-func (vaultServiceStub) ListVaultConnections(context.Context) ([]protocol.VaultConnection, error) {
+func (vaultServiceStub) List(context.Context) ([]application.VaultConnection, error) {
 	return nil, nil
 }
-func (vaultServiceStub) UpsertVaultConnection(_ context.Context, request protocol.UpsertVaultConnectionRequest) (protocol.VaultConnection, error) {
-	return protocol.VaultConnection{Name: request.Name}, nil
+func (vaultServiceStub) Upsert(_ context.Context, request application.UpsertVaultConnectionRequest) (application.VaultConnection, error) {
+	return application.VaultConnection{Name: request.Name}, nil
 }
-func (vaultServiceStub) TestVaultConnection(context.Context, int64, string) (protocol.TestVaultConnectionResponse, error) {
-	return protocol.TestVaultConnectionResponse{OK: true}, nil
+func (vaultServiceStub) Test(context.Context, application.TestVaultConnectionRequest) (application.TestVaultConnectionResult, error) {
+	return application.TestVaultConnectionResult{OK: true}, nil
 }
-func (vaultServiceStub) DeleteVaultConnection(context.Context, int64) error { return nil }
+func (vaultServiceStub) Delete(context.Context, int64) error { return nil }
 
 func (commandReceiptService) Get(_ context.Context, key string) (application.CommandReceiptStatus, error) {
 	return application.CommandReceiptStatus{Found: true, Key: key, Status: "completed", Operation: "test"}, nil
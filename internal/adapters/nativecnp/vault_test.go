@@ -4,22 +4,22 @@ import (
 	"context"
 	"testing"
 
-	"github.com/izzyreal/ciwi/internal/protocol"
+	"github.com/izzyreal/ciwi/internal/application"
 	cnpv1 "github.com/izzyreal/ciwi/pkg/cnp/v1"
 )
 
 type vaultServiceStub struct{}
 
-func (vaultServiceStub) ListVaultConnections(context.Context) ([]protocol.VaultConnection, error) {
-	return []protocol.VaultConnection{{ID: 7, Name: "home-vault", URL: "https://vault.example", AppRoleMount: "approle"}}, nil
+func (vaultServiceStub) List(context.Context) ([]application.VaultConnection, error) {
+	return []application.VaultConnection{{ID: 7, Name: "home-vault", URL: "https://vault.example", AppRoleMount: "approle"}}, nil
 }
-func (vaultServiceStub) UpsertVaultConnection(_ context.Context, request protocol.UpsertVaultConnectionRequest) (protocol.VaultConnection, error) {
-	return protocol.VaultConnection{ID: 8, Name: request.Name, URL: request.URL}, nil
+func (vaultServiceStub) Upsert(_ context.Context, request application.UpsertVaultConnectionRequest) (application.VaultConnection, error) {
+	return application.VaultConnection{ID: 8, Name: request.Name, URL: request.URL}, nil
 }
-func (vaultServiceStub) TestVaultConnection(context.Context, int64, string) (protocol.TestVaultConnectionResponse, error) {
-	return protocol.TestVaultConnectionResponse{OK: true, Message: "vault auth ok"}, nil
+func (vaultServiceStub) Test(context.Context, application.TestVaultConnectionRequest) (application.TestVaultConnectionResult, error) {
+	return application.TestVaultConnectionResult{OK: true, Message: "vault auth ok"}, nil
 }
-func (vaultServiceStub) DeleteVaultConnection(context.Context, int64) error { return nil }
+func (vaultServiceStub) Delete(context.Context, int64) error { return nil }
 
 func TestVaultOperationsCrossNativeProtocol(t *testing.T) {
 	handler := &Handler{services: Services{Vault: vaultServiceStub{}}}
@@ -0,0 +1,132 @@
+package sqlite
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/izzyreal/ciwi/internal/application"
+	"github.com/izzyreal/ciwi/internal/domain"
+	"github.com/izzyreal/ciwi/internal/store"
+)
+
+type AuthRepository struct {
+	store *store.Store
+}
+
+func NewAuthRepository(db *store.Store) *AuthRepository {
+	return &AuthRepository{store: db}
+}
+
+var _ application.AuthRepository = (*AuthRepository)(nil)
+
+func (r *AuthRepository) CountUsers(ctx context.Context) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+	return r.store.CountUsers()
+}
+
+func (r *AuthRepository) ListUsers(ctx context.Context) ([]domain.User, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return r.store.ListUsers()
+}
+
+func (r *AuthRepository) GetUser(ctx context.Context, id int64) (domain.User, bool, error) {
+	if err := ctx.Err(); err != nil {
+		return domain.User{}, false, err
+	}
+	return r.store.GetUser(id)
+}
+
+func (r *AuthRepository) GetUserCredentials(ctx context.Context, username string) (domain.User, string, bool, error) {
+	if err := ctx.Err(); err != nil {
+		return domain.User{}, "", false, err
+	}
+	return r.store.GetUserCredentials(username)
+}
+
+func (r *AuthRepository) CreateUser(ctx context.Context, user domain.User, passwordHash string, first bool) (domain.User, error) {
+	if err := ctx.Err(); err != nil {
+		return domain.User{}, err
+	}
+	created, err := r.store.CreateUser(user, passwordHash, first)
+	switch {
+	case errors.Is(err, store.ErrUsernameTaken):
+		return domain.User{}, application.NewError(application.ErrorConflict, err.Error(), err)
+	case errors.Is(err, store.ErrUsersExist):
+		return domain.User{}, application.NewError(application.ErrorFailedPrecondition, "users already exist; sign in as an admin to add more", err)
+	case err != nil:
+		return domain.User{}, application.WrapInternal("create user", err)
+	}
+	return created, nil
+}
+
+func (r *AuthRepository) UpdateUser(ctx context.Context, id int64, role domain.Role, passwordHash string) (domain.User, error) {
+	if err := ctx.Err(); err != nil {
+		return domain.User{}, err
+	}
+	user, err := r.store.UpdateUser(id, role, passwordHash)
+	if err != nil {
+		return domain.User{}, application.WrapInternal("update user", err)
+	}
+	return user, nil
+}
+
+func (r *AuthRepository) DeleteUser(ctx context.Context, id int64) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return r.store.DeleteUser(id)
+}
+
+func (r *AuthRepository) RecordLogin(ctx context.Context, userID int64, sessionHash string, at, expires time.Time) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return r.store.RecordLogin(userID, sessionHash, at, expires)
+}
+
+func (r *AuthRepository) FindSessionUser(ctx context.Context, sessionHash string, now time.Time) (domain.User, bool, error) {
+	if err := ctx.Err(); err != nil {
+		return domain.User{}, false, err
+	}
+	return r.store.FindSessionUser(sessionHash, now)
+}
+
+func (r *AuthRepository) DeleteSession(ctx context.Context, sessionHash string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return r.store.DeleteSession(sessionHash)
+}
+
+func (r *AuthRepository) CreateAPIToken(ctx context.Context, token domain.APIToken, tokenHash string) (domain.APIToken, error) {
+	if err := ctx.Err(); err != nil {
+		return domain.APIToken{}, err
+	}
+	return r.store.CreateAPIToken(token, tokenHash)
+}
+
+func (r *AuthRepository) FindAPITokenUser(ctx context.Context, tokenHash string, now time.Time) (domain.User, domain.APIToken, bool, error) {
+	if err := ctx.Err(); err != nil {
+		return domain.User{}, domain.APIToken{}, false, err
+	}
+	return r.store.FindAPITokenUser(tokenHash, now)
+}
+
+func (r *AuthRepository) ListAPITokens(ctx context.Context, userID int64) ([]domain.APIToken, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return r.store.ListAPITokens(userID)
+}
+
+func (r *AuthRepository) DeleteAPIToken(ctx context.Context, userID, tokenID int64) (bool, error) {
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+	return r.store.DeleteAPIToken(userID, tokenID)
+}
@@ -0,0 +1,69 @@
+package sqlite
+
+import (
+	"context"
+	"time"
+
+	"github.com/izzyreal/ciwi/internal/application"
+	"github.com/izzyreal/ciwi/internal/domain"
+	"github.com/izzyreal/ciwi/internal/store"
+)
+
+type AgentCredentialRepository struct {
+	store *store.Store
+}
+
+func NewAgentCredentialRepository(db *store.Store) *AgentCredentialRepository {
+	return &AgentCredentialRepository{store: db}
+}
+
+var _ application.AgentCredentialRepository = (*AgentCredentialRepository)(nil)
+
+func (r *AgentCredentialRepository) CreateEnrollmentToken(ctx context.Context, token domain.AgentEnrollmentToken, tokenHash string) (domain.AgentEnrollmentToken, error) {
+	if err := ctx.Err(); err != nil {
+		return domain.AgentEnrollmentToken{}, err
+	}
+	return r.store.CreateEnrollmentToken(token, tokenHash)
+}
+
+func (r *AgentCredentialRepository) ListEnrollmentTokens(ctx context.Context) ([]domain.AgentEnrollmentToken, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return r.store.ListEnrollmentTokens()
+}
+
+func (r *AgentCredentialRepository) DeleteEnrollmentToken(ctx context.Context, id int64) (bool, error) {
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+	return r.store.DeleteEnrollmentToken(id)
+}
+
+func (r *AgentCredentialRepository) EnrollAgent(ctx context.Context, tokenHash, agentID, credentialHash string, now time.Time) (bool, error) {
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+	return r.store.EnrollAgent(tokenHash, agentID, credentialHash, now)
+}
+
+func (r *AgentCredentialRepository) GetAgentCredential(ctx context.Context, agentID string) (domain.AgentCredential, string, bool, error) {
+	if err := ctx.Err(); err != nil {
+		return domain.AgentCredential{}, "", false, err
+	}
+	return r.store.GetAgentCredential(agentID)
+}
+
+func (r *AgentCredentialRepository) EnrollmentInUse(ctx context.Context) (bool, error) {
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+	return r.store.AgentEnrollmentInUse()
+}
+
+func (r *AgentCredentialRepository) RevokeAgentCredential(ctx context.Context, agentID string, at time.Time) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return r.store.RevokeAgentCredential(agentID, at)
+}
@@ -103,10 +103,18 @@ func projectDetailsFromProtocol(detail protocol.ProjectDetail) domain.ProjectDet
 			DependsOn: append([]string{}, pipeline.DependsOn...), SourceRepo: pipeline.SourceRepo,
 			SourceRef: pipeline.SourceRef, SupportsDryRun: supportsDryRun,
 		})
+		var vcsTrigger *domain.PipelineVCSTrigger
+		if state := pipeline.VCSTrigger; state != nil {
+			vcsTrigger = &domain.PipelineVCSTrigger{
+				PollIntervalSeconds: state.PollIntervalSeconds, LastSeenCommit: state.LastSeenCommit,
+				LastPolledUTC: state.LastPolledUTC, LastPollError: state.LastPollError,
+				LastTriggeredCommit: state.LastTriggeredCommit, LastTriggeredUTC: state.LastTriggeredUTC,
+			}
+		}
 		pipelines = append(pipelines, domain.PipelineDetails{
 			ID: pipeline.ID, PipelineID: pipeline.PipelineID, Trigger: pipeline.Trigger,
 			DependsOn: append([]string{}, pipeline.DependsOn...), SourceRepo: pipeline.SourceRepo,
-			SourceRef: pipeline.SourceRef, Jobs: jobs,
+			SourceRef: pipeline.SourceRef, VCSTrigger: vcsTrigger, Schedule: pipelineScheduleToDomain(pipeline.Schedule), Jobs: jobs,
 		})
 	}
 	chains := make([]domain.PipelineChain, 0, len(detail.PipelineChains))
@@ -114,6 +122,7 @@ func projectDetailsFromProtocol(detail protocol.ProjectDetail) domain.ProjectDet
 		chains = append(chains, domain.PipelineChain{
 			ID: chain.ID, Name: chain.Name, Pipelines: append([]string{}, chain.Pipelines...),
 			SupportsDryRun: chain.SupportsDryRun, VersionPipelineID: chain.VersionPipelineID,
+			Schedule: pipelineScheduleToDomain(chain.Schedule),
 		})
 	}
 	return domain.ProjectDetails{
@@ -137,3 +146,13 @@ func cloneStringMap(input map[string]string) map[string]string {
 	}
 	return out
 }
+
+func pipelineScheduleToDomain(sched *protocol.PipelineSchedule) *domain.PipelineSchedule {
+	if sched == nil {
+		return nil
+	}
+	return &domain.PipelineSchedule{
+		Cron: sched.Cron, Timezone: sched.Timezone, SourceRef: sched.SourceRef, DryRun: sched.DryRun, CatchUp: sched.CatchUp,
+		NextFireUTC: sched.NextFireUTC, LastFireUTC: sched.LastFireUTC, LastError: sched.LastError,
+	}
+}
@@ -0,0 +1,40 @@
+package sqlite
+
+import (
+	"context"
+
+	"github.com/izzyreal/ciwi/internal/application"
+	"github.com/izzyreal/ciwi/internal/domain"
+	"github.com/izzyreal/ciwi/internal/store"
+)
+
+type TestQuarantineRepository struct {
+	store *store.Store
+}
+
+func NewTestQuarantineRepository(db *store.Store) *TestQuarantineRepository {
+	return &TestQuarantineRepository{store: db}
+}
+
+var _ application.TestQuarantineRepository = (*TestQuarantineRepository)(nil)
+
+func (r *TestQuarantineRepository) ListTestQuarantine(ctx context.Context, projectID int64) ([]domain.TestQuarantineEntry, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return r.store.ListTestQuarantineEntries(projectID)
+}
+
+func (r *TestQuarantineRepository) CreateTestQuarantine(ctx context.Context, entry domain.TestQuarantineEntry) (domain.TestQuarantineEntry, error) {
+	if err := ctx.Err(); err != nil {
+		return domain.TestQuarantineEntry{}, err
+	}
+	return r.store.CreateTestQuarantineEntry(entry)
+}
+
+func (r *TestQuarantineRepository) DeleteTestQuarantine(ctx context.Context, projectID, id int64) (bool, error) {
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+	return r.store.DeleteTestQuarantineEntry(projectID, id)
+}
@@ -20,18 +20,21 @@ const (
 	terminalStatusAttemptTTL  = 30 * time.Second
 )
 
-func sendHeartbeat(ctx context.Context, client *http.Client, serverURL, agentID, hostname string, capabilities map[string]string, updateFailure string, updateInProgress bool, restartStatus string) (protocol.HeartbeatResponse, error) {
+func sendHeartbeat(ctx context.Context, client *http.Client, serverURL, agentID, hostname string, capabilities map[string]string, slots int, labels map[string]string, runningJobIDs []string, updateFailure string, updateInProgress bool, restartStatus string) (protocol.HeartbeatResponse, error) {
 	payload := protocol.HeartbeatRequest{
-		AgentID:          agentID,
-		Hostname:         hostname,
-		OS:               runtime.GOOS,
-		Arch:             runtime.GOARCH,
-		Version:          currentVersion(),
-		Capabilities:     cloneMap(capabilities),
-		UpdateFailure:    strings.TrimSpace(updateFailure),
-		UpdateInProgress: updateInProgress,
-		RestartStatus:    strings.TrimSpace(restartStatus),
-		TimestampUTC:     time.Now().UTC(),
+		AgentID:                agentID,
+		Hostname:               hostname,
+		OS:                     runtime.GOOS,
+		Arch:                   runtime.GOARCH,
+		Version:                currentVersion(),
+		Capabilities:           cloneMap(capabilities),
+		UpdateFailure:          strings.TrimSpace(updateFailure),
+		UpdateInProgress:       updateInProgress,
+		RestartStatus:          strings.TrimSpace(restartStatus),
+		Slots:                  slots,
+		Labels:                 cloneMap(labels),
+		TimestampUTC:           time.Now().UTC(),
+		RunningJobExecutionIDs: runningJobIDs,
 	}
 
 	body, err := json.Marshal(payload)
@@ -235,3 +238,32 @@ func getJobExecutionState(ctx context.Context, client *http.Client, serverURL, j
 		Error:  strings.TrimSpace(payload.JobExecution.Error),
 	}, nil
 }
+
+// fetchSourceCredential asks the server for the credential of the job's
+// source repository right before checkout. A nil credential means the
+// repository is checked out without one.
+func fetchSourceCredential(ctx context.Context, client *http.Client, serverURL, agentID, jobID string) (*protocol.SourceCredential, error) {
+	body, err := json.Marshal(protocol.JobSourceCredentialRequest{AgentID: agentID})
+	if err != nil {
+		return nil, fmt.Errorf("marshal source credential request: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, serverURL+"/api/v1/jobs/"+jobID+"/source-credential", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("create source credential request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("send source credential request: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 4*1024))
+		return nil, fmt.Errorf("source credential rejected: status=%d body=%s", resp.StatusCode, bytes.TrimSpace(respBody))
+	}
+	var payload protocol.JobSourceCredentialResponse
+	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
+		return nil, fmt.Errorf("decode source credential response: %w", err)
+	}
+	return payload.Credential, nil
+}
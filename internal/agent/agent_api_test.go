@@ -49,12 +49,18 @@ func TestSendHeartbeatSuccessAndPayload(t *testing.T) {
 			if !req.UpdateInProgress {
 				t.Fatalf("expected update_in_progress=true")
 			}
+			if req.Slots != 2 || req.Labels["dongle"] != "yes" {
+				t.Fatalf("unexpected slots or labels: %d %v", req.Slots, req.Labels)
+			}
 			if req.RestartStatus != "restart requested" {
 				t.Fatalf("expected trimmed restart status, got %q", req.RestartStatus)
 			}
 			if req.OS != runtime.GOOS || req.Arch != runtime.GOARCH {
 				t.Fatalf("unexpected platform in request: %s/%s", req.OS, req.Arch)
 			}
+			if len(req.RunningJobExecutionIDs) != 1 || req.RunningJobExecutionIDs[0] != "job-1" {
+				t.Fatalf("unexpected running jobs: %v", req.RunningJobExecutionIDs)
+			}
 			if req.TimestampUTC.IsZero() {
 				t.Fatalf("expected timestamp to be populated")
 			}
@@ -62,7 +68,7 @@ func TestSendHeartbeatSuccessAndPayload(t *testing.T) {
 		}),
 	}
 
-	resp, err := sendHeartbeat(context.Background(), client, "http://ciwi.local", "agent-1", "host-1", map[string]string{"executor": "script"}, " failed once ", true, " restart requested ")
+	resp, err := sendHeartbeat(context.Background(), client, "http://ciwi.local", "agent-1", "host-1", map[string]string{"executor": "script"}, 2, map[string]string{"dongle": "yes"}, []string{"job-1"}, " failed once ", true, " restart requested ")
 	if err != nil {
 		t.Fatalf("sendHeartbeat returned error: %v", err)
 	}
@@ -76,7 +82,7 @@ func TestSendHeartbeatErrors(t *testing.T) {
 
 	t.Run("request creation error", func(t *testing.T) {
 		t.Parallel()
-		_, err := sendHeartbeat(context.Background(), &http.Client{}, "://bad-url", "a", "h", nil, "", false, "")
+		_, err := sendHeartbeat(context.Background(), &http.Client{}, "://bad-url", "a", "h", nil, 1, nil, nil, "", false, "")
 		if err == nil || !strings.Contains(err.Error(), "create heartbeat request") {
 			t.Fatalf("expected create request error, got %v", err)
 		}
@@ -87,7 +93,7 @@ func TestSendHeartbeatErrors(t *testing.T) {
 		client := &http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
 			return nil, errors.New("boom")
 		})}
-		_, err := sendHeartbeat(context.Background(), client, "http://ciwi.local", "a", "h", nil, "", false, "")
+		_, err := sendHeartbeat(context.Background(), client, "http://ciwi.local", "a", "h", nil, 1, nil, nil, "", false, "")
 		if err == nil || !strings.Contains(err.Error(), "send heartbeat") {
 			t.Fatalf("expected transport error, got %v", err)
 		}
@@ -98,7 +104,7 @@ func TestSendHeartbeatErrors(t *testing.T) {
 		client := &http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
 			return jsonHTTPResponse(http.StatusForbidden, `forbidden`), nil
 		})}
-		_, err := sendHeartbeat(context.Background(), client, "http://ciwi.local", "a", "h", nil, "", false, "")
+		_, err := sendHeartbeat(context.Background(), client, "http://ciwi.local", "a", "h", nil, 1, nil, nil, "", false, "")
 		if err == nil || !strings.Contains(err.Error(), "heartbeat rejected") {
 			t.Fatalf("expected heartbeat rejected error, got %v", err)
 		}
@@ -109,7 +115,7 @@ func TestSendHeartbeatErrors(t *testing.T) {
 		client := &http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
 			return jsonHTTPResponse(http.StatusOK, `{not-json`), nil
 		})}
-		_, err := sendHeartbeat(context.Background(), client, "http://ciwi.local", "a", "h", nil, "", false, "")
+		_, err := sendHeartbeat(context.Background(), client, "http://ciwi.local", "a", "h", nil, 1, nil, nil, "", false, "")
 		if err == nil || !strings.Contains(err.Error(), "decode heartbeat response") {
 			t.Fatalf("expected decode error, got %v", err)
 		}
@@ -371,3 +377,37 @@ func TestCloneMapAndGetJobExecutionState(t *testing.T) {
 		}
 	})
 }
+
+func TestFetchSourceCredential(t *testing.T) {
+	t.Parallel()
+
+	client := &http.Client{
+		Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+			if r.Method != http.MethodPost || r.URL.Path != "/api/v1/jobs/job-1/source-credential" {
+				t.Fatalf("unexpected request: %s %s", r.Method, r.URL.Path)
+			}
+			var req protocol.JobSourceCredentialRequest
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				t.Fatalf("decode source credential request: %v", err)
+			}
+			switch req.AgentID {
+			case "agent-1":
+				return jsonHTTPResponse(http.StatusOK, `{"credential":{"kind":"https_token","secret":"s3cret"}}`), nil
+			case "agent-public":
+				return jsonHTTPResponse(http.StatusOK, `{}`), nil
+			default:
+				return jsonHTTPResponse(http.StatusConflict, "job is not running on this agent"), nil
+			}
+		}),
+	}
+	cred, err := fetchSourceCredential(context.Background(), client, "http://server", "agent-1", "job-1")
+	if err != nil || cred == nil || cred.Kind != protocol.RepoCredentialHTTPSToken || cred.Secret != "s3cret" {
+		t.Fatalf("unexpected credential %+v err=%v", cred, err)
+	}
+	if cred, err := fetchSourceCredential(context.Background(), client, "http://server", "agent-public", "job-1"); err != nil || cred != nil {
+		t.Fatalf("expected no credential for a public repository, got %+v err=%v", cred, err)
+	}
+	if _, err := fetchSourceCredential(context.Background(), client, "http://server", "agent-2", "job-1"); err == nil || !strings.Contains(err.Error(), "status=409") {
+		t.Fatalf("expected a refused request to fail, got %v", err)
+	}
+}
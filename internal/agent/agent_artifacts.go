@@ -15,6 +15,7 @@ import (
 	"time"
 
 	"github.com/bmatcuk/doublestar/v4"
+	"github.com/izzyreal/ciwi/internal/protocol"
 )
 
 const (
@@ -88,7 +89,7 @@ func uploadArtifactsZIP(ctx context.Context, client *http.Client, serverURL, age
 		return fmt.Errorf("create zip artifact upload request: %w", err)
 	}
 	req.Header.Set("Content-Type", "application/zip")
-	req.Header.Set("X-CIWI-Agent-ID", agentID)
+	req.Header.Set(protocol.AgentIDHeader, agentID)
 	resp, err := client.Do(req)
 	if err != nil {
 		return fmt.Errorf("send zip artifact upload after %s: %w", time.Since(uploadStarted).Round(time.Millisecond), err)
@@ -93,12 +93,12 @@ func readCCacheMetrics(cacheDir string, container *executionContainerContext) ma
 	defer cancel()
 	var out string
 	var err error
-	if container != nil && strings.TrimSpace(container.name) != "" {
+	if container != nil && container.runtime != nil && strings.TrimSpace(container.name) != "" {
 		containerCacheDir := cacheEnvPath(cacheDir)
 		out, err = runCommandCapture(
 			ctx,
 			"",
-			"docker",
+			container.runtime.Command(),
 			"exec",
 			strings.TrimSpace(container.name),
 			"ccache",
@@ -91,7 +91,7 @@ exit 1
 		t.Setenv("CIWI_DOCKER_LOG", logPath)
 
 		cacheDir := t.TempDir()
-		got := readCCacheMetrics(cacheDir, &executionContainerContext{name: "ciwi-cache-test"})
+		got := readCCacheMetrics(cacheDir, &executionContainerContext{runtime: dockerRuntime{}, name: "ciwi-cache-test"})
 		if got["Cache directory"] != "/ciwi/cache" || got["Hits"] != "17" {
 			t.Fatalf("unexpected managed-container metrics: %v", got)
 		}
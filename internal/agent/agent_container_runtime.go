@@ -0,0 +1,182 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+const containerRuntimeEnv = "CIWI_AGENT_CONTAINER_RUNTIME"
+
+const (
+	containerRuntimeDocker  = "docker"
+	containerRuntimePodman  = "podman"
+	containerRuntimeNerdctl = "nerdctl"
+)
+
+// containerRuntimeNames is the order in which the agent looks for a runtime
+// when CIWI_AGENT_CONTAINER_RUNTIME does not pick one.
+var containerRuntimeNames = []string{containerRuntimeDocker, containerRuntimePodman, containerRuntimeNerdctl}
+
+// containerRuntime is the container CLI that runs managed execution
+// containers. The runtimes share the docker command surface for run, exec,
+// inspect, kill and rm; they differ in how the agent's user, groups and
+// devices reach the container.
+type containerRuntime interface {
+	// Name is advertised as the agent's container_runtime capability.
+	Name() string
+	Command() string
+	Rootless() bool
+	// IdentityArgs returns the run flags mapping user, groups and devices
+	// into the container. An empty user runs steps as the agent's own user,
+	// so files written to the bind-mounted workspace stay owned by it.
+	IdentityArgs(user string, groups, devices []string) []string
+}
+
+type dockerRuntime struct {
+	rootless bool
+}
+
+func (dockerRuntime) Name() string     { return containerRuntimeDocker }
+func (dockerRuntime) Command() string  { return containerRuntimeDocker }
+func (r dockerRuntime) Rootless() bool { return r.rootless }
+
+func (r dockerRuntime) IdentityArgs(user string, groups, devices []string) []string {
+	args := []string{}
+	// A rootless daemon maps container root onto the agent's user, so
+	// staying root is what keeps workspace files owned by the agent.
+	if user = strings.TrimSpace(user); user == "" && !r.rootless {
+		user = defaultContainerUserSpec()
+	}
+	if user != "" {
+		args = append(args, "--user", user)
+	}
+	args = appendRepeatedFlag(args, "--group-add", groups)
+	return appendRepeatedFlag(args, "--device", devices)
+}
+
+// nerdctlRuntime drives containerd through nerdctl, which follows docker's
+// flags and its rootless user mapping.
+type nerdctlRuntime struct {
+	dockerRuntime
+}
+
+func (nerdctlRuntime) Name() string    { return containerRuntimeNerdctl }
+func (nerdctlRuntime) Command() string { return containerRuntimeNerdctl }
+
+type podmanRuntime struct {
+	rootless bool
+}
+
+func (podmanRuntime) Name() string     { return containerRuntimePodman }
+func (podmanRuntime) Command() string  { return containerRuntimePodman }
+func (r podmanRuntime) Rootless() bool { return r.rootless }
+
+func (r podmanRuntime) IdentityArgs(user string, groups, devices []string) []string {
+	args := []string{}
+	user = strings.TrimSpace(user)
+	switch {
+	case r.rootless && user == "":
+		// keep-id maps the agent's uid and gid onto the same ids inside the
+		// container and runs as them.
+		args = append(args, "--userns=keep-id")
+	case user == "":
+		if spec := defaultContainerUserSpec(); spec != "" {
+			args = append(args, "--user", spec)
+		}
+	default:
+		args = append(args, "--user", user)
+	}
+	if r.rootless && len(nonEmptyValues(groups)) > 0 {
+		// Host groups do not exist in a rootless user namespace. keep-groups
+		// hands the agent's own supplementary groups to the container, which
+		// is what grants access to devices such as /dev/snd or /dev/kvm.
+		args = append(args, "--group-add", "keep-groups")
+	} else {
+		args = appendRepeatedFlag(args, "--group-add", groups)
+	}
+	return appendRepeatedFlag(args, "--device", devices)
+}
+
+func appendRepeatedFlag(args []string, flag string, values []string) []string {
+	for _, value := range nonEmptyValues(values) {
+		args = append(args, flag, value)
+	}
+	return args
+}
+
+func nonEmptyValues(values []string) []string {
+	out := make([]string, 0, len(values))
+	for _, value := range values {
+		if value = strings.TrimSpace(value); value != "" {
+			out = append(out, value)
+		}
+	}
+	return out
+}
+
+// detectContainerRuntime returns the runtime named by
+// CIWI_AGENT_CONTAINER_RUNTIME, or the first of docker, podman and nerdctl
+// found on PATH.
+func detectContainerRuntime() (containerRuntime, error) {
+	if name := strings.ToLower(strings.TrimSpace(os.Getenv(containerRuntimeEnv))); name != "" {
+		if !isContainerRuntimeName(name) {
+			return nil, fmt.Errorf("%s must be one of %s, got %q", containerRuntimeEnv, strings.Join(containerRuntimeNames, ", "), name)
+		}
+		if _, err := exec.LookPath(name); err != nil {
+			return nil, fmt.Errorf("%s not found on agent", name)
+		}
+		return newContainerRuntime(name), nil
+	}
+	for _, name := range containerRuntimeNames {
+		if _, err := exec.LookPath(name); err == nil {
+			return newContainerRuntime(name), nil
+		}
+	}
+	return nil, fmt.Errorf("no container runtime found on agent (looked for %s)", strings.Join(containerRuntimeNames, ", "))
+}
+
+func isContainerRuntimeName(name string) bool {
+	for _, known := range containerRuntimeNames {
+		if name == known {
+			return true
+		}
+	}
+	return false
+}
+
+func newContainerRuntime(name string) containerRuntime {
+	switch name {
+	case containerRuntimePodman:
+		out := probeContainerRuntimeInfo(name, "{{.Host.Security.Rootless}}")
+		return podmanRuntime{rootless: strings.EqualFold(out, "true")}
+	case containerRuntimeNerdctl:
+		return nerdctlRuntime{dockerRuntime{rootless: securityOptionsRootless(name)}}
+	default:
+		return dockerRuntime{rootless: securityOptionsRootless(name)}
+	}
+}
+
+func securityOptionsRootless(command string) bool {
+	return strings.Contains(probeContainerRuntimeInfo(command, "{{json .SecurityOptions}}"), "rootless")
+}
+
+func probeContainerRuntimeInfo(command, format string) string {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	out, err := runCommandCapture(ctx, "", command, "info", "--format", format)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(out)
+}
+
+func containerRuntimeSummary(rt containerRuntime) string {
+	if rt.Rootless() {
+		return rt.Name() + " (rootless)"
+	}
+	return rt.Name()
+}
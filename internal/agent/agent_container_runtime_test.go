@@ -0,0 +1,141 @@
+package agent
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestContainerRuntimeIdentityArgs(t *testing.T) {
+	groups := []string{"audio", " ", "video"}
+	devices := []string{"/dev/snd"}
+	cases := []struct {
+		name string
+		rt   containerRuntime
+		user string
+		want string
+	}{
+		{"docker explicit user", dockerRuntime{}, "1000:1000", "--user 1000:1000 --group-add audio --group-add video --device /dev/snd"},
+		{"rootless docker stays root", dockerRuntime{rootless: true}, "", "--group-add audio --group-add video --device /dev/snd"},
+		{"rootless nerdctl stays root", nerdctlRuntime{dockerRuntime{rootless: true}}, "", "--group-add audio --group-add video --device /dev/snd"},
+		{"rootless podman keeps the agent ids", podmanRuntime{rootless: true}, "", "--userns=keep-id --group-add keep-groups --device /dev/snd"},
+		{"rootless podman explicit user", podmanRuntime{rootless: true}, "builder", "--user builder --group-add keep-groups --device /dev/snd"},
+		{"rootful podman explicit user", podmanRuntime{}, "1000:1000", "--user 1000:1000 --group-add audio --group-add video --device /dev/snd"},
+	}
+	for _, tc := range cases {
+		if got := strings.Join(tc.rt.IdentityArgs(tc.user, groups, devices), " "); got != tc.want {
+			t.Fatalf("%s: expected %q, got %q", tc.name, tc.want, got)
+		}
+	}
+	if got := (podmanRuntime{rootless: true}).IdentityArgs("", nil, nil); strings.Contains(strings.Join(got, " "), "keep-groups") {
+		t.Fatalf("expected no keep-groups without requested groups, got %v", got)
+	}
+	if runtime.GOOS != "windows" {
+		if got := strings.Join((dockerRuntime{}).IdentityArgs("", nil, nil), " "); !strings.HasPrefix(got, "--user ") {
+			t.Fatalf("expected rootful docker to run as the agent user, got %q", got)
+		}
+	}
+}
+
+func writeFakeContainerRuntime(t *testing.T, dir, name, scriptBody string) string {
+	t.Helper()
+	logPath := filepath.Join(dir, name+".log")
+	content := "#!/bin/sh\n" +
+		"echo \"$@\" >> \"" + logPath + "\"\n" +
+		scriptBody + "\n"
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o755); err != nil {
+		t.Fatalf("write fake %s: %v", name, err)
+	}
+	return logPath
+}
+
+func TestDetectContainerRuntime(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("fake runtime shell script tests are posix-only")
+	}
+	binDir := t.TempDir()
+	t.Setenv("PATH", binDir)
+	t.Setenv(containerRuntimeEnv, "")
+	if _, err := detectContainerRuntime(); err == nil {
+		t.Fatalf("expected an error without any runtime on PATH")
+	}
+
+	writeFakeContainerRuntime(t, binDir, "podman", `
+if [ "$1" = "info" ]; then
+  echo true
+fi
+exit 0
+`)
+	rt, err := detectContainerRuntime()
+	if err != nil || rt.Name() != containerRuntimePodman || !rt.Rootless() {
+		t.Fatalf("expected rootless podman, got %v err=%v", rt, err)
+	}
+	if caps := detectAgentCapabilities(); caps["container_runtime"] != containerRuntimePodman {
+		t.Fatalf("expected the agent to advertise podman, got %v", caps)
+	}
+
+	writeFakeContainerRuntime(t, binDir, "docker", "exit 0")
+	if rt, err := detectContainerRuntime(); err != nil || rt.Name() != containerRuntimeDocker || rt.Rootless() {
+		t.Fatalf("expected docker to be preferred, got %v err=%v", rt, err)
+	}
+	t.Setenv(containerRuntimeEnv, "Podman")
+	if rt, err := detectContainerRuntime(); err != nil || rt.Name() != containerRuntimePodman {
+		t.Fatalf("expected %s to pick podman, got %v err=%v", containerRuntimeEnv, rt, err)
+	}
+	t.Setenv(containerRuntimeEnv, "nerdctl")
+	if _, err := detectContainerRuntime(); err == nil || !strings.Contains(err.Error(), "nerdctl not found") {
+		t.Fatalf("expected a missing nerdctl to be reported, got %v", err)
+	}
+	t.Setenv(containerRuntimeEnv, "lxc")
+	if _, err := detectContainerRuntime(); err == nil {
+		t.Fatalf("expected an unknown runtime to be rejected")
+	}
+}
+
+func TestRuntimeContainerLifecycleWithRootlessPodman(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("fake runtime shell script tests are posix-only")
+	}
+	binDir := t.TempDir()
+	logPath := writeFakeContainerRuntime(t, binDir, "podman", `
+if [ "$1" = "inspect" ]; then
+  echo true
+fi
+exit 0
+`)
+	t.Setenv("PATH", binDir)
+
+	rt := podmanRuntime{rootless: true}
+	cfg := runtimeContainerConfig{
+		runtime: rt,
+		name:    "ciwi-probe-podman",
+		image:   "ubuntu-vmpc",
+		workdir: "/workspace",
+		network: "ciwi-job-podman",
+		mounts:  []runtimeContainerMount{{hostPath: filepath.Join(t.TempDir(), "workspace"), containerPath: "/workspace"}},
+		devices: []string{"/dev/snd"},
+		groups:  []string{"audio"},
+	}
+	if err := startRuntimeContainer(context.Background(), cfg); err != nil {
+		t.Fatalf("startRuntimeContainer: %v", err)
+	}
+	if err := validateProbeContainerReady(context.Background(), rt, cfg.name, cfg.image); err != nil {
+		t.Fatalf("validateProbeContainerReady: %v", err)
+	}
+	cleanupRuntimeProbeContainer(context.Background(), rt, cfg.name)
+
+	logRaw, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatalf("read fake podman log: %v", err)
+	}
+	log := string(logRaw)
+	if !strings.Contains(log, "run -d --name ciwi-probe-podman -w /workspace --network ciwi-job-podman --userns=keep-id --group-add keep-groups --device /dev/snd -v ") {
+		t.Fatalf("expected podman run with the rootless identity mapping, got:\n%s", log)
+	}
+	if !strings.Contains(log, "inspect -f {{.State.Running}} ciwi-probe-podman") || !strings.Contains(log, "rm -f ciwi-probe-podman") {
+		t.Fatalf("expected inspect and cleanup through podman, got:\n%s", log)
+	}
+}
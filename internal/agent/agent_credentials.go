@@ -0,0 +1,173 @@
+package agent
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/izzyreal/ciwi/internal/protocol"
+)
+
+const (
+	agentEnrollmentTokenEnv = "CIWI_AGENT_ENROLLMENT_TOKEN"
+	agentCredentialFileEnv  = "CIWI_AGENT_CREDENTIAL_FILE"
+	agentCredentialFileName = "agent-credential.json"
+)
+
+// storedAgentCredential is what the agent keeps on disk after enrolling.
+// The enrollment token hash tells a new token apart from the one already
+// exchanged, so setting a fresh token re-enrolls after a revocation.
+type storedAgentCredential struct {
+	AgentID             string `json:"agent_id"`
+	Credential          string `json:"credential"`
+	EnrollmentTokenHash string `json:"enrollment_token_sha256,omitempty"`
+}
+
+// agentCredentialPath keeps the credential next to, not inside, the work
+// directory so cache and history wipes leave it alone.
+func agentCredentialPath(workDir string) string {
+	if path := strings.TrimSpace(os.Getenv(agentCredentialFileEnv)); path != "" {
+		return path
+	}
+	return filepath.Join(filepath.Dir(workDir), agentCredentialFileName)
+}
+
+// loadOrEnrollAgentCredential returns the agent's credential, enrolling with
+// enrollmentToken when no credential for agentID is stored or the token is
+// new. An empty credential means the agent runs unenrolled.
+func loadOrEnrollAgentCredential(ctx context.Context, client *http.Client, serverURL, agentID, path, enrollmentToken string) (string, error) {
+	enrollmentToken = strings.TrimSpace(enrollmentToken)
+	stored, err := readAgentCredential(path)
+	if err != nil {
+		return "", err
+	}
+	tokenHash := ""
+	if enrollmentToken != "" {
+		sum := sha256.Sum256([]byte(enrollmentToken))
+		tokenHash = hex.EncodeToString(sum[:])
+	}
+	usable := stored.Credential != "" && stored.AgentID == agentID
+	if usable && (tokenHash == "" || tokenHash == stored.EnrollmentTokenHash) {
+		return stored.Credential, nil
+	}
+	if enrollmentToken == "" {
+		if stored.Credential != "" {
+			slog.Warn("stored agent credential belongs to another agent id; running unenrolled", "agent_id", agentID, "stored_agent_id", stored.AgentID, "path", path)
+		}
+		return "", nil
+	}
+	credential, err := enrollAgent(ctx, client, serverURL, agentID, enrollmentToken)
+	if err != nil {
+		return "", err
+	}
+	if err := writeAgentCredential(path, storedAgentCredential{AgentID: agentID, Credential: credential, EnrollmentTokenHash: tokenHash}); err != nil {
+		return "", err
+	}
+	slog.Info("agent enrolled", "agent_id", agentID, "credential_file", path)
+	return credential, nil
+}
+
+func readAgentCredential(path string) (storedAgentCredential, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return storedAgentCredential{}, nil
+	}
+	if err != nil {
+		return storedAgentCredential{}, fmt.Errorf("read agent credential: %w", err)
+	}
+	var stored storedAgentCredential
+	if err := json.Unmarshal(data, &stored); err != nil {
+		return storedAgentCredential{}, fmt.Errorf("parse agent credential %s: %w", path, err)
+	}
+	stored.AgentID = strings.TrimSpace(stored.AgentID)
+	stored.Credential = strings.TrimSpace(stored.Credential)
+	return stored, nil
+}
+
+func writeAgentCredential(path string, stored storedAgentCredential) error {
+	data, err := json.MarshalIndent(stored, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal agent credential: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("create agent credential dir: %w", err)
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return fmt.Errorf("write agent credential: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		_ = os.Remove(tmp)
+		return fmt.Errorf("store agent credential: %w", err)
+	}
+	return nil
+}
+
+func enrollAgent(ctx context.Context, client *http.Client, serverURL, agentID, enrollmentToken string) (string, error) {
+	body, err := json.Marshal(protocol.AgentEnrollRequest{AgentID: agentID, EnrollmentToken: enrollmentToken})
+	if err != nil {
+		return "", fmt.Errorf("marshal enrollment request: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, serverURL+"/api/v1/agent/enroll", bytes.NewReader(body))
+	if err != nil {
+		return "", fmt.Errorf("create enrollment request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("send enrollment request: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 4*1024))
+		return "", fmt.Errorf("enrollment rejected: status=%d body=%s", resp.StatusCode, bytes.TrimSpace(respBody))
+	}
+	var enrolled protocol.AgentEnrollResponse
+	if err := json.NewDecoder(resp.Body).Decode(&enrolled); err != nil {
+		return "", fmt.Errorf("decode enrollment response: %w", err)
+	}
+	if strings.TrimSpace(enrolled.Credential) == "" {
+		return "", fmt.Errorf("enrollment response has no credential")
+	}
+	return strings.TrimSpace(enrolled.Credential), nil
+}
+
+// agentCredentialTransport identifies the agent on every request to the
+// server. Requests to other hosts, such as release downloads, are left as is.
+type agentCredentialTransport struct {
+	base       http.RoundTripper
+	serverHost string
+	agentID    string
+	credential string
+}
+
+func newAgentCredentialTransport(serverURL, agentID, credential string) http.RoundTripper {
+	host := ""
+	if parsed, err := url.Parse(serverURL); err == nil {
+		host = parsed.Host
+	}
+	return &agentCredentialTransport{base: http.DefaultTransport, serverHost: host, agentID: agentID, credential: credential}
+}
+
+func (t *agentCredentialTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.serverHost == "" || !strings.EqualFold(req.URL.Host, t.serverHost) {
+		return t.base.RoundTrip(req)
+	}
+	req = req.Clone(req.Context())
+	req.Header.Set(protocol.AgentIDHeader, t.agentID)
+	if t.credential != "" {
+		req.Header.Set("Authorization", "Bearer "+t.credential)
+	}
+	return t.base.RoundTrip(req)
+}
@@ -0,0 +1,83 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/izzyreal/ciwi/internal/protocol"
+)
+
+func TestLoadOrEnrollAgentCredentialEnrollsOncePerToken(t *testing.T) {
+	enrollments := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/agent/enroll" {
+			http.NotFound(w, r)
+			return
+		}
+		var req protocol.AgentEnrollRequest
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		enrollments++
+		_ = json.NewEncoder(w).Encode(protocol.AgentEnrollResponse{AgentID: req.AgentID, Credential: "ciwi_agent_" + req.EnrollmentToken})
+	}))
+	defer srv.Close()
+
+	path := filepath.Join(t.TempDir(), "agent-credential.json")
+	ctx := context.Background()
+	got, err := loadOrEnrollAgentCredential(ctx, srv.Client(), srv.URL, "agent-a", path, "")
+	if err != nil || got != "" {
+		t.Fatalf("expected unenrolled agent without token, got %q err=%v", got, err)
+	}
+	got, err = loadOrEnrollAgentCredential(ctx, srv.Client(), srv.URL, "agent-a", path, "ciwi_enroll_1")
+	if err != nil || got != "ciwi_agent_ciwi_enroll_1" {
+		t.Fatalf("enroll: got %q err=%v", got, err)
+	}
+	if info, err := os.Stat(path); err != nil || info.Mode().Perm() != 0o600 {
+		t.Fatalf("expected private credential file, info=%v err=%v", info, err)
+	}
+	for _, token := range []string{"", "ciwi_enroll_1"} {
+		if got, err := loadOrEnrollAgentCredential(ctx, srv.Client(), srv.URL, "agent-a", path, token); err != nil || got != "ciwi_agent_ciwi_enroll_1" {
+			t.Fatalf("reload with token %q: got %q err=%v", token, got, err)
+		}
+	}
+	if got, err := loadOrEnrollAgentCredential(ctx, srv.Client(), srv.URL, "agent-a", path, "ciwi_enroll_2"); err != nil || got != "ciwi_agent_ciwi_enroll_2" {
+		t.Fatalf("re-enroll with new token: got %q err=%v", got, err)
+	}
+	if enrollments != 2 {
+		t.Fatalf("expected 2 enrollments, got %d", enrollments)
+	}
+	if got, _ := loadOrEnrollAgentCredential(ctx, srv.Client(), srv.URL, "agent-b", path, ""); got != "" {
+		t.Fatalf("expected credential of another agent id to be ignored, got %q", got)
+	}
+}
+
+func TestAgentCredentialTransportOnlyTalksToServer(t *testing.T) {
+	var serverAuth, serverAgent, otherAuth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		serverAuth, serverAgent = r.Header.Get("Authorization"), r.Header.Get(protocol.AgentIDHeader)
+	}))
+	defer server.Close()
+	other := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		otherAuth = r.Header.Get("Authorization")
+	}))
+	defer other.Close()
+
+	client := &http.Client{Transport: newAgentCredentialTransport(server.URL, "agent-a", "ciwi_agent_secret")}
+	for _, target := range []string{server.URL, other.URL} {
+		resp, err := client.Get(target + "/x")
+		if err != nil {
+			t.Fatalf("get %s: %v", target, err)
+		}
+		_ = resp.Body.Close()
+	}
+	if serverAuth != "Bearer ciwi_agent_secret" || serverAgent != "agent-a" {
+		t.Fatalf("server headers: auth=%q agent=%q", serverAuth, serverAgent)
+	}
+	if otherAuth != "" {
+		t.Fatalf("credential leaked to another host: %q", otherAuth)
+	}
+}
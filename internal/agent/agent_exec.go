@@ -23,10 +23,16 @@ const (
 )
 
 func executeLeasedJob(ctx context.Context, client *http.Client, serverURL, agentID, workDir string, agentCapabilities map[string]string, job protocol.JobExecution) error {
-	return executeLeasedJobWithDependencies(ctx, client, serverURL, agentID, workDir, agentCapabilities, job, defaultExecutionDependencies())
+	return executeLeasedJobInSlot(ctx, client, serverURL, agentID, workDir, 0, agentCapabilities, job)
 }
 
-func executeLeasedJobWithDependencies(ctx context.Context, client *http.Client, serverURL, agentID, workDir string, agentCapabilities map[string]string, job protocol.JobExecution, dependencies executionDependencies) error {
+// executeLeasedJobInSlot runs a job in the workspace of one job slot, so jobs
+// running side by side on the same agent never share a workspace.
+func executeLeasedJobInSlot(ctx context.Context, client *http.Client, serverURL, agentID, workDir string, slot int, agentCapabilities map[string]string, job protocol.JobExecution) error {
+	return executeLeasedJobWithDependencies(ctx, client, serverURL, agentID, workDir, slot, agentCapabilities, job, defaultExecutionDependencies())
+}
+
+func executeLeasedJobWithDependencies(ctx context.Context, client *http.Client, serverURL, agentID, workDir string, slot int, agentCapabilities map[string]string, job protocol.JobExecution, dependencies executionDependencies) error {
 	dependencies = dependencies.withDefaults()
 	slog.Info("job execution started",
 		"job_execution_id", job.ID,
@@ -51,8 +57,8 @@ func executeLeasedJobWithDependencies(ctx context.Context, client *http.Client,
 		return fmt.Errorf("report running status: %w", err)
 	}
 
-	workspaceDir := workspaceDirForJob(workDir, job)
-	fmt.Fprintf(&output, "[meta] workspace=%s\n", workspaceDir)
+	workspaceDir := workspaceDirForJobSlot(workDir, job, slot)
+	fmt.Fprintf(&output, "[meta] workspace=%s slot=%d\n", workspaceDir, slot)
 	workspaceErr := removeAllWithRetry(workspaceDir)
 	if workspaceErr == nil {
 		workspaceErr = os.MkdirAll(workspaceDir, 0o755)
@@ -120,6 +126,14 @@ func executeLeasedJobWithDependencies(ctx context.Context, client *http.Client,
 		progress.markSent(totalLen, currentStep)
 		return nil
 	}
+	// Service phases end with the job, so their last logs and finished
+	// events go out with the terminal update. The network goes last, once
+	// the execution container has left it.
+	var services *jobServices
+	defer func() {
+		services.stop()
+		services.removeNetwork()
+	}()
 	reportTerminalUpdate := func(status string, exitCode *int, failMsg string, cacheStats []protocol.JobCacheStats, runtimeCaps map[string]string) error {
 		deltaRaw, totalLen, _ := progress.unsentFrom(&output)
 		delta := redactSensitive(deltaRaw, job.SensitiveValues)
@@ -128,7 +142,7 @@ func executeLeasedJobWithDependencies(ctx context.Context, client *http.Client,
 			Status:              status,
 			ExitCode:            exitCode,
 			Error:               failMsg,
-			Events:              outputDeltaEvent(delta, nil),
+			Events:              append(outputDeltaEvent(delta, nil), services.stop()...),
 			CacheStats:          cacheStats,
 			RuntimeCapabilities: runtimeCaps,
 			CurrentStep:         "",
@@ -145,6 +159,7 @@ func executeLeasedJobWithDependencies(ctx context.Context, client *http.Client,
 		return fmt.Errorf("report workspace phase: %w", err)
 	}
 	execDir := workspaceDir
+	var checkoutCacheStats []protocol.JobCacheStats
 	if job.Source != nil && strings.TrimSpace(job.Source.Repo) != "" {
 		checkoutPhase := executionPhase(timeline, protocol.JobExecutionPhaseCheckout)
 		checkoutStarted := time.Now().UTC()
@@ -153,8 +168,17 @@ func executeLeasedJobWithDependencies(ctx context.Context, client *http.Client,
 			return fmt.Errorf("report checkout status: %w", err)
 		}
 		fmt.Fprintf(&output, "[checkout] repo=%s ref=%s\n", job.Source.Repo, job.Source.Ref)
-		checkoutOutput, checkoutErr := dependencies.sources.Checkout(runCtx, sourceDir, *job.Source)
-		output.WriteString(checkoutOutput)
+		var checkout sourceCheckoutResult
+		credential, checkoutErr := fetchSourceCredential(runCtx, client, serverURL, agentID, job.ID)
+		if checkoutErr == nil {
+			checkout, checkoutErr = dependencies.sources.Checkout(runCtx, sourceCheckoutRequest{
+				Destination: sourceDir, Source: *job.Source, MirrorRoot: gitMirrorRoot(workDir), Credential: credential,
+			})
+		}
+		output.WriteString(checkout.Output)
+		if checkout.Mirror != nil {
+			checkoutCacheStats = append(checkoutCacheStats, *checkout.Mirror)
+		}
 		fmt.Fprintf(&output, "[checkout] duration=%s\n", time.Since(checkoutStarted).Round(time.Millisecond))
 		if checkoutErr != nil {
 			_ = reportPhaseUpdate(checkoutPhase, []protocol.JobExecutionEvent{phaseFinishedEvent(checkoutPhase, checkoutStarted, checkoutErr)}, nil)
@@ -219,9 +243,9 @@ func executeLeasedJobWithDependencies(ctx context.Context, client *http.Client,
 		fmt.Fprintf(&output, "[cache] %s\n", line)
 	}
 	var execContainer *executionContainerContext
-	cacheStats := collectJobCacheStats(resolvedCaches, execContainer)
+	cacheStats := append(collectJobCacheStats(resolvedCaches, execContainer), checkoutCacheStats...)
 	refreshCacheStats := func() []protocol.JobCacheStats {
-		return collectJobCacheStats(resolvedCaches, execContainer)
+		return append(collectJobCacheStats(resolvedCaches, execContainer), checkoutCacheStats...)
 	}
 	probeContainer := runtimeProbeContainerName(job.ID, job.Metadata)
 	probeContainerImage := runtimeProbeContainerImageFromMetadata(job.Metadata)
@@ -230,9 +254,6 @@ func executeLeasedJobWithDependencies(ctx context.Context, client *http.Client,
 		probeContainerWorkdir = "/workspace"
 	}
 	probeContainerUser := runtimeExecContainerUserFromMetadata(job.Metadata)
-	if strings.TrimSpace(probeContainerUser) == "" {
-		probeContainerUser = defaultContainerUserSpec()
-	}
 	probeContainerDevices := runtimeExecContainerDevicesFromMetadata(job.Metadata)
 	probeContainerGroups := runtimeExecContainerGroupsFromMetadata(job.Metadata)
 	requireContainerTools := len(containerToolRequirements(job.RequiredCapabilities)) > 0
@@ -247,6 +268,38 @@ func executeLeasedJobWithDependencies(ctx context.Context, client *http.Client,
 		return nil
 	}
 	containerExec := strings.TrimSpace(probeContainerImage) != ""
+	var containerRT containerRuntime
+	if containerExec || len(job.Services) > 0 {
+		rt, rtErr := detectContainerRuntime()
+		if rtErr != nil {
+			fmt.Fprintf(&output, "[runtime] %v\n", rtErr)
+			_ = reportPhaseUpdate(environmentPhase, []protocol.JobExecutionEvent{phaseFinishedEvent(environmentPhase, environmentStarted, rtErr)}, nil)
+			if reportErr := reportTerminalUpdate(protocol.JobExecutionStatusFailed, nil, rtErr.Error(), cacheStats, nil); reportErr != nil {
+				return reportErr
+			}
+			slog.Error("job failed", "job_execution_id", job.ID, "error", rtErr.Error())
+			return nil
+		}
+		containerRT = rt
+	}
+	if len(job.Services) > 0 {
+		reportServiceEvents := func(events []protocol.JobExecutionEvent) error {
+			return reportJobStatus(ctx, client, serverURL, job.ID, protocol.JobExecutionStatusUpdateRequest{
+				AgentID: agentID, Status: protocol.JobExecutionStatusRunning, Events: events, TimestampUTC: time.Now().UTC(),
+			})
+		}
+		var servicesErr error
+		services, servicesErr = startJobServices(runCtx, containerRT, job, timeline, !containerExec, &output, reportServiceEvents)
+		if servicesErr != nil {
+			fmt.Fprintf(&output, "[services] %v\n", servicesErr)
+			_ = reportPhaseUpdate(environmentPhase, []protocol.JobExecutionEvent{phaseFinishedEvent(environmentPhase, environmentStarted, servicesErr)}, nil)
+			if reportErr := reportTerminalUpdate(protocol.JobExecutionStatusFailed, nil, servicesErr.Error(), cacheStats, nil); reportErr != nil {
+				return reportErr
+			}
+			slog.Error("job failed", "job_execution_id", job.ID, "error", servicesErr.Error())
+			return nil
+		}
+	}
 	if containerExec {
 		mounts := []runtimeContainerMount{
 			{hostPath: execDir, containerPath: probeContainerWorkdir},
@@ -267,9 +320,11 @@ func executeLeasedJobWithDependencies(ctx context.Context, client *http.Client,
 			})
 		}
 		startErr := startRuntimeContainer(runCtx, runtimeContainerConfig{
+			runtime: containerRT,
 			name:    probeContainer,
 			image:   probeContainerImage,
 			workdir: probeContainerWorkdir,
+			network: services.networkName(),
 			user:    probeContainerUser,
 			mounts:  mounts,
 			devices: probeContainerDevices,
@@ -299,7 +354,7 @@ func executeLeasedJobWithDependencies(ctx context.Context, client *http.Client,
 		}
 		userSummary := strings.TrimSpace(probeContainerUser)
 		if userSummary == "" {
-			userSummary = "default"
+			userSummary = "agent"
 		}
 		deviceSummary := "none"
 		if len(probeContainerDevices) > 0 {
@@ -309,14 +364,15 @@ func executeLeasedJobWithDependencies(ctx context.Context, client *http.Client,
 		if len(probeContainerGroups) > 0 {
 			groupSummary = strings.Join(probeContainerGroups, ", ")
 		}
-		fmt.Fprintf(&output, "[runtime] started execution container %s from %s (workdir=%s user=%s mounts=%s devices=%s groups=%s)\n", probeContainer, probeContainerImage, probeContainerWorkdir, userSummary, mountSummary, deviceSummary, groupSummary)
-		defer cleanupRuntimeProbeContainer(context.Background(), probeContainer)
+		fmt.Fprintf(&output, "[runtime] started execution container %s from %s (runtime=%s workdir=%s user=%s mounts=%s devices=%s groups=%s)\n", probeContainer, probeContainerImage, containerRuntimeSummary(containerRT), probeContainerWorkdir, userSummary, mountSummary, deviceSummary, groupSummary)
+		defer cleanupRuntimeProbeContainer(context.Background(), containerRT, probeContainer)
 		execContainer = &executionContainerContext{
+			runtime: containerRT,
 			name:    probeContainer,
 			workdir: probeContainerWorkdir,
 		}
 	}
-	if ensureErr := validateProbeContainerReady(runCtx, probeContainer, probeContainerImage); ensureErr != nil {
+	if ensureErr := validateProbeContainerReady(runCtx, containerRT, probeContainer, probeContainerImage); ensureErr != nil {
 		fmt.Fprintf(&output, "[runtime] %v\n", ensureErr)
 		_ = reportPhaseUpdate(environmentPhase, []protocol.JobExecutionEvent{phaseFinishedEvent(environmentPhase, environmentStarted, ensureErr)}, nil)
 		if reportErr := reportTerminalUpdate(protocol.JobExecutionStatusFailed, nil, ensureErr.Error(), cacheStats, nil); reportErr != nil {
@@ -331,7 +387,7 @@ func executeLeasedJobWithDependencies(ctx context.Context, client *http.Client,
 		return reportFailure(ctx, client, serverURL, agentID, job, nil, nil, nil, fmt.Sprintf("resolve job shell: %v", err))
 	}
 
-	runtimeCaps := collectRuntimeCapabilities(agentCapabilities, probeContainer)
+	runtimeCaps := collectRuntimeCapabilities(agentCapabilities, containerRT, probeContainer)
 	enrichRuntimeHostToolCapabilities(runtimeCaps, job.RequiredCapabilities, shell)
 	if summary := runtimeProbeSummary(runtimeCaps); summary != "" {
 		fmt.Fprintf(&output, "%s\n", summary)
@@ -370,11 +426,23 @@ func executeLeasedJobWithDependencies(ctx context.Context, client *http.Client,
 	fmt.Fprintf(&output, "[run] shell_trace=%t go_build_verbose=%t\n", traceShell, verboseGo)
 	fmt.Fprintf(&output, "[run] shell=%s\n", shell)
 
+	containerWorkdir := ""
+	if execContainer != nil {
+		containerWorkdir = execContainer.workdir
+	}
+	shardEnv, shardTestCount, err := writeShardTestsFile(execDir, containerWorkdir, job)
+	if err != nil {
+		return reportFailure(ctx, client, serverURL, agentID, job, progress, &output, nil, err.Error())
+	}
+	if index, total, ok := job.Metadata.Shard(); ok {
+		fmt.Fprintf(&output, "[run] %s assigned_tests=%d\n", domain.ShardLabel(index, total), shardTestCount)
+	}
+
 	runEnv := []string(nil)
 	if execContainer != nil {
-		runEnv = withGoVerbose(mergeEnv(mergeEnv([]string{}, job.Env), cacheEnv), verboseGo)
+		runEnv = withGoVerbose(mergeEnv(mergeEnv(mergeEnv(mergeEnv([]string{}, job.Env), cacheEnv), shardEnv), services.env()), verboseGo)
 	} else {
-		runEnv = withGoVerbose(mergeEnv(mergeEnv(os.Environ(), job.Env), cacheEnv), verboseGo)
+		runEnv = withGoVerbose(mergeEnv(mergeEnv(mergeEnv(mergeEnv(os.Environ(), job.Env), cacheEnv), shardEnv), services.env()), verboseGo)
 	}
 	scriptSteps := stepPlanToScriptSteps(job.StepPlan)
 	collectedSuites := make([]protocol.TestSuiteReport, 0, len(scriptSteps))
@@ -392,13 +460,27 @@ func executeLeasedJobWithDependencies(ctx context.Context, client *http.Client,
 		})
 	} else {
 		for _, step := range scriptSteps {
+			if runCtx.Err() != nil {
+				break
+			}
+			// After a failure only steps whose condition asks for it still run;
+			// the rest stay unreached.
+			skipPrefix, skipMessage := "", ""
+			switch {
+			case err != nil && step.runWhen == "":
+				continue
+			case step.meta.kind == "dryrun_skip":
+				skipPrefix, skipMessage = "[dry-run]", "skipped during dry run"
+			case step.meta.kind == "condition_skip" || (err == nil && step.runWhen == protocol.JobStepRunWhenFailure):
+				skipPrefix, skipMessage = "[if]", step.skipReason
+			}
 			currentStep := formatCurrentStep(step.meta)
 			slog.Info("job step started", "job_execution_id", job.ID, "current_step", currentStep)
 			stepStart := time.Now().UTC()
 			eventYAMLLiteral := redactSensitive(step.meta.yamlLiteral, job.SensitiveValues)
 			eventScript := redactSensitive(step.script, job.SensitiveValues)
-			if step.meta.kind == "dryrun_skip" {
-				fmt.Fprintf(&output, "[dry-run] skipped step: %s\n", strings.TrimSpace(step.meta.name))
+			if skipPrefix != "" {
+				fmt.Fprintf(&output, "%s skipped step: %s\n", skipPrefix, strings.TrimSpace(step.meta.name))
 			}
 			events := []protocol.JobExecutionEvent{
 				{
@@ -410,19 +492,21 @@ func executeLeasedJobWithDependencies(ctx context.Context, client *http.Client,
 			if err := reportRunningUpdate(currentStep, events, nil); err != nil {
 				return fmt.Errorf("report step status: %w", err)
 			}
-			if step.meta.kind == "dryrun_skip" {
+			if skipPrefix != "" {
 				skippedMessage := fmt.Sprintf("skipped step: %s", strings.TrimSpace(step.meta.name))
+				skippedStep := jobExecutionEventStep(step.meta, eventYAMLLiteral, eventScript)
+				skippedStep.SkipReason = skipMessage
 				_ = reportRunningUpdate(currentStep, []protocol.JobExecutionEvent{
 					{
 						Type:         protocol.JobExecutionEventTypeStepOutput,
 						Step:         jobExecutionEventStep(step.meta, eventYAMLLiteral, eventScript),
-						Output:       redactSensitive("[dry-run] "+skippedMessage+"\n", job.SensitiveValues),
+						Output:       redactSensitive(skipPrefix+" "+skippedMessage+"\n", job.SensitiveValues),
 						TimestampUTC: time.Now().UTC(),
 					},
 					{
 						Type:         protocol.JobExecutionEventTypeStepFinished,
-						Step:         jobExecutionEventStep(step.meta, eventYAMLLiteral, eventScript),
-						Message:      "skipped during dry run",
+						Step:         skippedStep,
+						Message:      skipMessage,
 						DurationMS:   time.Since(stepStart).Milliseconds(),
 						TimestampUTC: time.Now().UTC(),
 					},
@@ -434,12 +518,43 @@ func executeLeasedJobWithDependencies(ctx context.Context, client *http.Client,
 				stepRunEnv = mergeEnv(runEnv, step.env)
 			}
 			stepEvent := jobExecutionEventStep(step.meta, eventYAMLLiteral, eventScript)
-			stepErr := dependencies.scripts.Run(runCtx, scriptRunRequest{
-				Client: client, ServerURL: serverURL, AgentID: agentID, JobID: job.ID,
-				Shell: shell, ExecDir: execDir, Script: step.script, Container: execContainer,
-				Environment: stepRunEnv, Output: &output, StepEvent: stepEvent, Progress: progress,
-				DefaultCurrentStep: currentStep, SensitiveValues: job.SensitiveValues, TraceShell: traceShell,
-			})
+			if step.retryMaxAttempts > 1 {
+				stepEvent.Attempt = 1
+			}
+			runStep := func() error {
+				return dependencies.scripts.Run(runCtx, scriptRunRequest{
+					Client: client, ServerURL: serverURL, AgentID: agentID, JobID: job.ID,
+					Shell: shell, ExecDir: execDir, Script: step.script, Container: execContainer,
+					Environment: stepRunEnv, Output: &output, StepEvent: stepEvent, Progress: progress,
+					DefaultCurrentStep: currentStep, SensitiveValues: job.SensitiveValues, TraceShell: traceShell,
+				})
+			}
+			stepErr := runStep()
+			// Only a non-zero exit is retried in place; a timeout or cancellation
+			// ends the job anyway.
+			for stepEvent.Attempt > 0 && stepEvent.Attempt < step.retryMaxAttempts && stepErr != nil && runCtx.Err() == nil {
+				code := exitCodeFromErr(stepErr)
+				if code == nil {
+					break
+				}
+				attempt := stepEvent.Attempt + 1
+				delay := stepRetryBackoff(step.retryBackoffSeconds, attempt)
+				fmt.Fprintf(&output, "[retry] step failed: %s (exit=%d); attempt %d of %d in %s\n", currentStep, *code, attempt, step.retryMaxAttempts, delay)
+				_ = reportRunningUpdate(currentStep, []protocol.JobExecutionEvent{{
+					Type: protocol.JobExecutionEventTypeStepFinished, Step: stepEvent, ExitCode: code,
+					Error: fmt.Sprintf("exit=%d", *code), DurationMS: time.Since(stepStart).Milliseconds(), TimestampUTC: time.Now().UTC(),
+				}}, nil)
+				if !sleepWithContext(runCtx, delay) {
+					break
+				}
+				stepEvent = jobExecutionEventStep(step.meta, eventYAMLLiteral, eventScript)
+				stepEvent.Attempt = attempt
+				stepStart = time.Now().UTC()
+				_ = reportRunningUpdate(currentStep, []protocol.JobExecutionEvent{{
+					Type: protocol.JobExecutionEventTypeStepStarted, Step: stepEvent, TimestampUTC: stepStart,
+				}}, nil)
+				stepErr = runStep()
+			}
 			stepEvents := []protocol.JobExecutionEvent(nil)
 			if step.meta.kind == "test" && strings.TrimSpace(step.meta.testReport) != "" {
 				suite, parseErr := parseStepTestSuiteFromFile(execDir, step.meta)
@@ -450,6 +565,12 @@ func executeLeasedJobWithDependencies(ctx context.Context, client *http.Client,
 					}
 				} else {
 					collectedSuites = append(collectedSuites, suite)
+					if stepErr != nil && exitCodeFromErr(stepErr) != nil && runCtx.Err() == nil {
+						if quarantined, ok := testFailuresQuarantined(runCtx, client, serverURL, agentID, job.ID, collectedSuites, collectedCoverage); ok {
+							fmt.Fprintf(&output, "[tests] suite=%s failures quarantined=%d\n", step.meta.testName, quarantined)
+							stepErr = nil
+						}
+					}
 				}
 			}
 			if step.meta.kind == "test" && strings.TrimSpace(step.meta.coverageReport) != "" {
@@ -468,7 +589,7 @@ func executeLeasedJobWithDependencies(ctx context.Context, client *http.Client,
 				timedOut := runCtx.Err() == context.DeadlineExceeded
 				finishedEvent := protocol.JobExecutionEvent{
 					Type:         protocol.JobExecutionEventTypeStepFinished,
-					Step:         jobExecutionEventStep(step.meta, eventYAMLLiteral, eventScript),
+					Step:         stepEvent,
 					DurationMS:   time.Since(stepStart).Milliseconds(),
 					TimestampUTC: time.Now().UTC(),
 				}
@@ -494,12 +615,14 @@ func executeLeasedJobWithDependencies(ctx context.Context, client *http.Client,
 				}
 				stepEvents = append(stepEvents, finishedEvent)
 				_ = reportRunningUpdate(currentStep, stepEvents, nil)
-				err = fmt.Errorf("%s: %w", currentStep, stepErr)
-				break
+				if err == nil {
+					err = fmt.Errorf("%s: %w", currentStep, stepErr)
+				}
+				continue
 			}
 			stepEvents = append(stepEvents, protocol.JobExecutionEvent{
 				Type:         protocol.JobExecutionEventTypeStepFinished,
-				Step:         jobExecutionEventStep(step.meta, eventYAMLLiteral, eventScript),
+				Step:         stepEvent,
 				DurationMS:   time.Since(stepStart).Milliseconds(),
 				TimestampUTC: time.Now().UTC(),
 			})
@@ -549,22 +672,20 @@ func executeLeasedJobWithDependencies(ctx context.Context, client *http.Client,
 			return fmt.Errorf("report test publication status: %w", phaseErr)
 		}
 	}
-	testReport := protocol.JobExecutionTestReport{Suites: collectedSuites, Coverage: collectedCoverage}
-	for _, s := range collectedSuites {
-		testReport.Total += s.Total
-		testReport.Passed += s.Passed
-		testReport.Failed += s.Failed
-		testReport.Skipped += s.Skipped
-	}
+	testReport := testReportFromSuites(collectedSuites, collectedCoverage)
 	if testReport.Total > 0 || testReport.Coverage != nil {
-		if err := uploadTestReport(ctx, client, serverURL, agentID, job.ID, testReport); err != nil {
-			fmt.Fprintf(&output, "[tests] upload_failed=%v\n", err)
+		if stored, uploadErr := uploadTestReport(ctx, client, serverURL, agentID, job.ID, testReport); uploadErr != nil {
+			fmt.Fprintf(&output, "[tests] upload_failed=%v\n", uploadErr)
 		} else {
+			testReport = stored
 			fmt.Fprintf(&output, "%s\n", testReportSummary(testReport))
 		}
 		if err == nil && testReport.Failed > 0 {
 			err = fmt.Errorf("test report contains failures: failed=%d", testReport.Failed)
 		}
+		if err == nil && testReport.Coverage != nil && testReport.Coverage.Gate != nil && !testReport.Coverage.Gate.Passed {
+			err = fmt.Errorf("coverage gate failed: %s", testReport.Coverage.Gate.Message)
+		}
 	}
 	if publishTests {
 		if phaseErr := reportPhaseUpdate(testPhase, []protocol.JobExecutionEvent{phaseFinishedEvent(testPhase, testPhaseStarted, nil)}, runtimeCaps); phaseErr != nil {
@@ -661,8 +782,11 @@ func parseStepTestSuiteFromFile(execDir string, meta stepMarkerMeta) (protocol.T
 	if format == "" {
 		format = "go-test-json"
 	}
-	if format == "junit" {
+	switch format {
+	case "junit":
 		format = "junit-xml"
+	case "nextest-json":
+		format = "libtest-json"
 	}
 	suiteName := strings.TrimSpace(meta.testName)
 	if suiteName == "" {
@@ -681,6 +805,14 @@ func parseStepTestSuiteFromFile(execDir string, meta stepMarkerMeta) (protocol.T
 		suite = parseGoTestJSONSuite(suiteName, lines)
 	case "junit-xml":
 		suite = parseJUnitXMLSuite(suiteName, lines)
+	case "tap":
+		suite = parseTAPSuite(suiteName, lines)
+	case "trx":
+		suite = parseTRXSuite(suiteName, lines)
+	case "libtest-json":
+		suite = parseLibtestJSONSuite(suiteName, lines)
+	case "pytest-reportlog":
+		suite = parsePytestReportLogSuite(suiteName, lines)
 	default:
 		return protocol.TestSuiteReport{}, fmt.Errorf("unsupported test format %q", format)
 	}
@@ -694,9 +826,25 @@ func parseStepTestSuiteFromFile(execDir string, meta stepMarkerMeta) (protocol.T
 }
 
 type jobScriptStep struct {
-	meta   stepMarkerMeta
-	script string
-	env    map[string]string
+	meta       stepMarkerMeta
+	script     string
+	env        map[string]string
+	runWhen    string
+	skipReason string
+
+	retryMaxAttempts    int
+	retryBackoffSeconds int
+}
+
+// stepRetryBackoff doubles the configured backoff for every retry after the
+// first one, capped at five minutes so a retrying step cannot outlast a
+// reasonable job timeout on waiting alone.
+func stepRetryBackoff(backoffSeconds, attempt int) time.Duration {
+	delay := time.Duration(backoffSeconds) * time.Second
+	for i := 2; i < attempt && delay < 5*time.Minute; i++ {
+		delay *= 2
+	}
+	return min(delay, 5*time.Minute)
 }
 
 func stepPlanToScriptSteps(plan []protocol.JobStepPlanItem) []jobScriptStep {
@@ -708,7 +856,7 @@ func stepPlanToScriptSteps(plan []protocol.JobStepPlanItem) []jobScriptStep {
 	for i, step := range plan {
 		script := step.Script
 		kind := strings.TrimSpace(step.Kind)
-		if strings.TrimSpace(script) == "" && kind != "dryrun_skip" {
+		if strings.TrimSpace(script) == "" && kind != "dryrun_skip" && kind != "condition_skip" {
 			continue
 		}
 		index := step.Index
@@ -736,8 +884,13 @@ func stepPlanToScriptSteps(plan []protocol.JobStepPlanItem) []jobScriptStep {
 				coverageFormat: strings.TrimSpace(step.CoverageFormat),
 				coverageReport: strings.TrimSpace(step.CoverageReport),
 			},
-			script: script,
-			env:    cloneMap(step.Env),
+			script:     script,
+			env:        cloneMap(step.Env),
+			runWhen:    strings.TrimSpace(step.RunWhen),
+			skipReason: strings.TrimSpace(step.SkipReason),
+
+			retryMaxAttempts:    step.RetryMaxAttempts,
+			retryBackoffSeconds: step.RetryBackoffSeconds,
 		})
 	}
 	if len(steps) == 0 {
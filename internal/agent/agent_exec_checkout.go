@@ -7,9 +7,11 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/izzyreal/ciwi/internal/gitauth"
 	"github.com/izzyreal/ciwi/internal/protocol"
 )
 
@@ -19,15 +21,20 @@ func checkoutSource(ctx context.Context, sourceDir string, source protocol.Sourc
 	if _, err := exec.LookPath("git"); err != nil {
 		return "", fmt.Errorf("git is required on the agent: %w", err)
 	}
+	options := source.SourceCheckoutOptions
+	if err := requireGitLFS(ctx, options); err != nil {
+		return "", err
+	}
 
 	if err := os.MkdirAll(filepath.Dir(sourceDir), 0o755); err != nil {
 		return "", fmt.Errorf("prepare source parent directory: %w", err)
 	}
 
+	clone := append(append([]string{"clone", "--no-checkout"}, gitDepthArgs(options)...), source.Repo, sourceDir)
 	cloneAttempts := [][]string{
-		{"clone", "--depth", "1", source.Repo, sourceDir},
-		{"-c", "http.version=HTTP/1.1", "clone", "--depth", "1", source.Repo, sourceDir},
-		{"-c", "http.version=HTTP/1.1", "clone", "--depth", "1", source.Repo, sourceDir},
+		clone,
+		append([]string{"-c", "http.version=HTTP/1.1"}, clone...),
+		append([]string{"-c", "http.version=HTTP/1.1"}, clone...),
 	}
 	if err := runGitWithRetry(ctx, &output, "clone", cloneAttempts, func() {
 		_ = os.RemoveAll(sourceDir)
@@ -35,26 +42,112 @@ func checkoutSource(ctx context.Context, sourceDir string, source protocol.Sourc
 		return output.String(), err
 	}
 
-	if strings.TrimSpace(source.Ref) == "" {
-		return output.String(), nil
+	revision := "HEAD"
+	if strings.TrimSpace(source.Ref) != "" {
+		fetch := append(append([]string{"fetch"}, gitDepthArgs(options)...), "origin", source.Ref)
+		if err := runGitWithRetry(ctx, &output, fmt.Sprintf("fetch ref %q", source.Ref), gitFetchAttempts(sourceDir, fetch), nil); err != nil {
+			return output.String(), err
+		}
+		revision = "FETCH_HEAD"
 	}
-
-	fetchAttempts := [][]string{
-		{"-C", sourceDir, "fetch", "--depth", "1", "origin", source.Ref},
-		{"-C", sourceDir, "-c", "http.version=HTTP/1.1", "fetch", "--depth", "1", "origin", source.Ref},
-		{"-C", sourceDir, "-c", "http.version=HTTP/1.1", "fetch", "--depth", "1", "origin", source.Ref},
+	if options.FetchTags {
+		fetch := append(append([]string{"fetch"}, gitDepthArgs(options)...), "--tags", "origin")
+		if err := runGitWithRetry(ctx, &output, "fetch tags", gitFetchAttempts(sourceDir, fetch), nil); err != nil {
+			return output.String(), err
+		}
 	}
-	if err := runGitWithRetry(ctx, &output, fmt.Sprintf("fetch ref %q", source.Ref), fetchAttempts, nil); err != nil {
+
+	fmt.Fprintf(&output, "[checkout] options: %s\n", options.Summary())
+	if err := checkoutWorkingTree(ctx, &output, sourceDir, revision, options); err != nil {
 		return output.String(), err
 	}
+	return output.String(), nil
+}
 
-	checkoutOut, err := runCommandCapture(ctx, "", "git", "-C", sourceDir, "checkout", "--force", "FETCH_HEAD")
-	output.WriteString(checkoutOut)
-	if err != nil {
-		return output.String(), fmt.Errorf("git checkout FETCH_HEAD: %w", err)
+// checkoutWorkingTree fills the working tree of a clone made with
+// --no-checkout: it narrows it to the sparse paths, checks revision out and
+// then brings in submodules and LFS objects as the options ask.
+func checkoutWorkingTree(ctx context.Context, output *strings.Builder, sourceDir, revision string, options protocol.SourceCheckoutOptions) error {
+	var steps []gitStep
+	if len(options.Sparse) > 0 {
+		steps = append(steps, gitStep{"sparse-checkout", append([]string{"-C", sourceDir, "sparse-checkout", "set", "--cone"}, options.Sparse...)})
+	}
+	steps = append(steps, gitStep{"checkout " + revision, []string{"-C", sourceDir, "checkout", "--force", revision}})
+	if err := runGitSteps(ctx, output, steps); err != nil {
+		return err
 	}
 
-	return output.String(), nil
+	submodules := options.EffectiveSubmodules()
+	if submodules != protocol.SourceSubmodulesNone {
+		update := append([]string{"submodule", "update", "--init", "--force"}, gitDepthArgs(options)...)
+		if submodules == protocol.SourceSubmodulesRecursive {
+			update = append(update, "--recursive")
+		}
+		if err := runGitWithRetry(ctx, output, "submodule update", gitFetchAttempts(sourceDir, update), nil); err != nil {
+			return err
+		}
+	}
+	if options.LFS {
+		lfsPull := []string{"lfs", "pull"}
+		if err := runGitWithRetry(ctx, output, "lfs pull", gitFetchAttempts(sourceDir, lfsPull), nil); err != nil {
+			return err
+		}
+		if submodules != protocol.SourceSubmodulesNone {
+			foreach := []string{"-C", sourceDir, "submodule", "foreach"}
+			if submodules == protocol.SourceSubmodulesRecursive {
+				foreach = append(foreach, "--recursive")
+			}
+			if err := runGitSteps(ctx, output, []gitStep{{"lfs pull in submodules", append(foreach, "git lfs pull")}}); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// gitStep is one git invocation, named after what it does for error messages.
+type gitStep struct {
+	phase string
+	args  []string
+}
+
+func runGitSteps(ctx context.Context, output *strings.Builder, steps []gitStep) error {
+	for _, step := range steps {
+		runOut, err := runCommandCapture(ctx, "", "git", step.args...)
+		output.WriteString(runOut)
+		if err != nil {
+			return fmt.Errorf("git %s: %w", step.phase, err)
+		}
+	}
+	return nil
+}
+
+// gitDepthArgs limits a clone or fetch to the configured history depth.
+func gitDepthArgs(options protocol.SourceCheckoutOptions) []string {
+	if depth := options.EffectiveDepth(); depth > 0 {
+		return []string{"--depth", strconv.Itoa(depth)}
+	}
+	return nil
+}
+
+// gitFetchAttempts runs args inside sourceDir, retrying over HTTP/1.1 like
+// the initial clone does.
+func gitFetchAttempts(sourceDir string, args []string) [][]string {
+	return [][]string{
+		append([]string{"-C", sourceDir}, args...),
+		append([]string{"-C", sourceDir, "-c", "http.version=HTTP/1.1"}, args...),
+		append([]string{"-C", sourceDir, "-c", "http.version=HTTP/1.1"}, args...),
+	}
+}
+
+func requireGitLFS(ctx context.Context, options protocol.SourceCheckoutOptions) error {
+	if !options.LFS {
+		return nil
+	}
+	if out, err := runCommandCapture(ctx, "", "git", "lfs", "version"); err != nil {
+		return fmt.Errorf("git lfs is required on the agent for lfs checkouts: %s", strings.TrimSpace(out))
+	}
+	return nil
 }
 
 func runGitWithRetry(ctx context.Context, output *strings.Builder, phase string, attempts [][]string, onRetry func()) error {
@@ -123,12 +216,22 @@ func isRetryableGitTransportError(runOutput string, err error) bool {
 	return false
 }
 
+// runCommandCapture authenticates with the repository credential bound to
+// ctx, which only the checkout phase does; see gitSourceCheckout.
 func runCommandCapture(ctx context.Context, dir, name string, args ...string) (string, error) {
+	authEnv, release, err := gitauth.CommandEnv(ctx)
+	if err != nil {
+		return "", err
+	}
+	defer release()
 	cmd := exec.CommandContext(ctx, name, args...)
 	cmd.Dir = dir
+	if len(authEnv) > 0 {
+		cmd.Env = append(os.Environ(), authEnv...)
+	}
 	var out bytes.Buffer
 	cmd.Stdout = &out
 	cmd.Stderr = &out
-	err := cmd.Run()
+	err = cmd.Run()
 	return out.String(), err
 }
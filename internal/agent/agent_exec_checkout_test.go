@@ -0,0 +1,197 @@
+package agent
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/izzyreal/ciwi/internal/protocol"
+)
+
+func TestCheckoutSourceAppliesCheckoutOptions(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skipf("git not available: %v", err)
+	}
+	// Local submodule URLs use the file transport, which git refuses for
+	// submodules unless allowed.
+	t.Setenv("GIT_CONFIG_COUNT", "1")
+	t.Setenv("GIT_CONFIG_KEY_0", "protocol.file.allow")
+	t.Setenv("GIT_CONFIG_VALUE_0", "always")
+
+	root := t.TempDir()
+	runGit := func(dir string, args ...string) string {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %v failed: %v\n%s", args, err, string(out))
+		}
+		return strings.TrimSpace(string(out))
+	}
+	writeFile := func(path, content string) {
+		t.Helper()
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("mkdir %s: %v", path, err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatalf("write %s: %v", path, err)
+		}
+	}
+	newRepo := func(name string) (string, string) {
+		remote := filepath.Join(root, name+".git")
+		work := filepath.Join(root, name)
+		runGit(root, "init", "--bare", "--initial-branch=main", remote)
+		runGit(root, "clone", remote, work)
+		runGit(work, "config", "user.email", "ciwi@example.local")
+		runGit(work, "config", "user.name", "ciwi")
+		return "file://" + filepath.ToSlash(remote), work
+	}
+
+	libRemote, libWork := newRepo("lib")
+	writeFile(filepath.Join(libWork, "lib.txt"), "lib\n")
+	runGit(libWork, "add", ".")
+	runGit(libWork, "commit", "-m", "lib")
+	runGit(libWork, "push", "origin", "HEAD:main")
+
+	remote, work := newRepo("app")
+	writeFile(filepath.Join(work, "src", "main.txt"), "main\n")
+	writeFile(filepath.Join(work, "docs", "guide.txt"), "guide\n")
+	runGit(work, "add", ".")
+	runGit(work, "commit", "-m", "first")
+	runGit(work, "tag", "v1.0.0")
+	runGit(work, "submodule", "add", libRemote, "src/lib")
+	runGit(work, "commit", "-m", "add lib")
+	runGit(work, "push", "--tags", "origin", "HEAD:main")
+
+	depth := 0
+	options := protocol.SourceCheckoutOptions{Depth: &depth, Submodules: protocol.SourceSubmodulesTop, Sparse: []string{"src"}, FetchTags: true}
+	checkouts := map[string]func(dir string) (string, error){
+		"remote": func(dir string) (string, error) {
+			return checkoutSource(context.Background(), dir, protocol.SourceSpec{Repo: remote, Ref: "main", SourceCheckoutOptions: options})
+		},
+		"mirror": func(dir string) (string, error) {
+			result, err := gitSourceCheckout{}.Checkout(context.Background(), sourceCheckoutRequest{
+				Destination: dir, Source: protocol.SourceSpec{Repo: remote, Ref: "main", SourceCheckoutOptions: options},
+				MirrorRoot: filepath.Join(root, "mirrors"),
+			})
+			if err == nil && result.Mirror == nil {
+				t.Fatalf("expected the checkout to come from the mirror, output:\n%s", result.Output)
+			}
+			return result.Output, err
+		},
+	}
+	for name, checkout := range checkouts {
+		dir := filepath.Join(root, "checkout-"+name)
+		out, err := checkout(dir)
+		if err != nil {
+			t.Fatalf("%s checkout failed: %v\noutput:\n%s", name, err, out)
+		}
+		if !strings.Contains(out, "[checkout] options: depth=full submodules=top lfs=off sparse=src fetch_tags=on") {
+			t.Fatalf("%s checkout should report its options, got:\n%s", name, out)
+		}
+		if _, err := os.Stat(filepath.Join(dir, "src", "main.txt")); err != nil {
+			t.Fatalf("%s checkout should include the sparse path: %v", name, err)
+		}
+		if _, err := os.Stat(filepath.Join(dir, "docs")); !os.IsNotExist(err) {
+			t.Fatalf("%s checkout should leave paths outside the sparse cone out, stat err=%v", name, err)
+		}
+		if data, err := os.ReadFile(filepath.Join(dir, "src", "lib", "lib.txt")); err != nil || string(data) != "lib\n" {
+			t.Fatalf("%s checkout should initialize the submodule, got %q err=%v", name, data, err)
+		}
+		if got := runGit(dir, "rev-parse", "--is-shallow-repository"); got != "false" {
+			t.Fatalf("%s checkout with depth 0 should have the full history, shallow=%s", name, got)
+		}
+		if got := runGit(dir, "tag", "--list"); got != "v1.0.0" {
+			t.Fatalf("%s checkout should fetch tags, got %q", name, got)
+		}
+	}
+
+	shallow := filepath.Join(root, "checkout-default")
+	out, err := checkoutSource(context.Background(), shallow, protocol.SourceSpec{Repo: remote})
+	if err != nil {
+		t.Fatalf("default checkout failed: %v\noutput:\n%s", err, out)
+	}
+	if got := runGit(shallow, "rev-parse", "--is-shallow-repository"); got != "true" {
+		t.Fatalf("default checkout should stay shallow, shallow=%s", got)
+	}
+	if _, err := os.Stat(filepath.Join(shallow, "docs", "guide.txt")); err != nil {
+		t.Fatalf("default checkout should check out every path: %v", err)
+	}
+	if entries, _ := os.ReadDir(filepath.Join(shallow, "src", "lib")); len(entries) != 0 {
+		t.Fatalf("default checkout should not initialize submodules, found %d entries", len(entries))
+	}
+}
+
+func TestCheckoutSourceRequiresGitLFS(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skipf("git not available: %v", err)
+	}
+	if out, err := exec.Command("git", "lfs", "version").CombinedOutput(); err == nil {
+		t.Skipf("git lfs is installed: %s", out)
+	}
+	_, err := checkoutSource(context.Background(), filepath.Join(t.TempDir(), "src"), protocol.SourceSpec{
+		Repo: filepath.Join(t.TempDir(), "missing.git"), SourceCheckoutOptions: protocol.SourceCheckoutOptions{LFS: true},
+	})
+	if err == nil || !strings.Contains(err.Error(), "git lfs is required") {
+		t.Fatalf("expected a missing git lfs to fail the checkout up front, got %v", err)
+	}
+}
+
+func TestCheckoutKeepsSourceCredentialOutOfTheWorkspace(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skipf("git not available: %v", err)
+	}
+	root := t.TempDir()
+	// Key material goes to the temp dir; point it somewhere we can inspect.
+	keyRoot := filepath.Join(root, "tmp")
+	if err := os.MkdirAll(keyRoot, 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	t.Setenv("TMPDIR", keyRoot)
+	runGit := func(dir string, args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v\n%s", args, err, out)
+		}
+	}
+	remote := filepath.Join(root, "app.git")
+	work := filepath.Join(root, "app")
+	runGit(root, "init", "--bare", "--initial-branch=main", remote)
+	runGit(root, "clone", remote, work)
+	runGit(work, "-c", "user.email=ciwi@example.local", "-c", "user.name=ciwi", "commit", "--allow-empty", "-m", "first")
+	runGit(work, "push", "origin", "HEAD:main")
+
+	const secret = "PRIVATE-KEY-MATERIAL"
+	dir := filepath.Join(root, "checkout")
+	result, err := gitSourceCheckout{}.Checkout(context.Background(), sourceCheckoutRequest{
+		Destination: dir, Source: protocol.SourceSpec{Repo: "file://" + filepath.ToSlash(remote), Ref: "main"},
+		MirrorRoot: filepath.Join(root, "mirrors"),
+		Credential: &protocol.SourceCredential{Kind: protocol.RepoCredentialSSHKey, Secret: secret},
+	})
+	if err != nil {
+		t.Fatalf("checkout failed: %v\noutput:\n%s", err, result.Output)
+	}
+	if !strings.Contains(result.Output, "[checkout] using ssh_key repository credential") || strings.Contains(result.Output, secret) {
+		t.Fatalf("expected the output to name the credential kind only, got:\n%s", result.Output)
+	}
+	for _, tree := range []string{dir, filepath.Join(root, "mirrors")} {
+		_ = filepath.WalkDir(tree, func(path string, entry os.DirEntry, err error) error {
+			if err != nil || entry.IsDir() {
+				return err
+			}
+			if data, _ := os.ReadFile(path); strings.Contains(string(data), secret) {
+				t.Fatalf("credential leaked into %s", path)
+			}
+			return nil
+		})
+	}
+	if entries, _ := os.ReadDir(keyRoot); len(entries) != 0 {
+		t.Fatalf("expected the key material to be removed after checkout, found %d entries", len(entries))
+	}
+}
@@ -17,6 +17,12 @@ func prepareCommandForCancellation(cmd *exec.Cmd) {
 	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
 }
 
+// trackCommandTree has nothing to do on unix: the process group set up by
+// prepareCommandForCancellation already spans every descendant.
+func trackCommandTree(_ *exec.Cmd) func() {
+	return func() {}
+}
+
 func interruptCommandTree(cmd *exec.Cmd) error {
 	pid := commandPID(cmd)
 	if pid <= 0 {
@@ -3,21 +3,109 @@
 package agent
 
 import (
+	"errors"
 	"fmt"
+	"log/slog"
 	"os/exec"
 	"strconv"
 	"strings"
+	"sync"
 	"syscall"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
 )
 
+// commandJobObjects maps a command's pid to the job object holding its tree.
+var commandJobObjects sync.Map
+
 func prepareCommandForCancellation(cmd *exec.Cmd) {
 	if cmd == nil {
 		return
 	}
 	// CREATE_NEW_PROCESS_GROUP allows softer console-control attempts later.
+	// CREATE_SUSPENDED keeps the command from spawning children before
+	// trackCommandTree has put it in a job object; trackCommandTree resumes it.
 	cmd.SysProcAttr = &syscall.SysProcAttr{
-		CreationFlags: syscall.CREATE_NEW_PROCESS_GROUP,
+		CreationFlags: syscall.CREATE_NEW_PROCESS_GROUP | windows.CREATE_SUSPENDED,
+	}
+}
+
+// trackCommandTree places the started command in a job object and then lets
+// it run. Unlike taskkill /T, terminating the job also reaches descendants
+// whose parent has already exited, such as processes detached by build tools.
+func trackCommandTree(cmd *exec.Cmd) func() {
+	pid := commandPID(cmd)
+	if pid <= 0 {
+		return func() {}
+	}
+	release := assignCommandJobObject(pid)
+	if cmd.SysProcAttr != nil && cmd.SysProcAttr.CreationFlags&windows.CREATE_SUSPENDED != 0 {
+		if err := resumeProcessThreads(pid); err != nil {
+			// A command that cannot be resumed would never finish.
+			slog.Warn("resume suspended command failed", "pid", pid, "error", err)
+			_ = cmd.Process.Kill()
+		}
+	}
+	return release
+}
+
+func assignCommandJobObject(pid int) func() {
+	job, err := windows.CreateJobObject(nil, nil)
+	if err != nil {
+		slog.Warn("create job object failed", "pid", pid, "error", err)
+		return func() {}
+	}
+	process, err := windows.OpenProcess(windows.PROCESS_SET_QUOTA|windows.PROCESS_TERMINATE, false, uint32(pid))
+	if err == nil {
+		err = windows.AssignProcessToJobObject(job, process)
+		_ = windows.CloseHandle(process)
+	}
+	if err != nil {
+		slog.Warn("assign command to job object failed", "pid", pid, "error", err)
+		_ = windows.CloseHandle(job)
+		return func() {}
 	}
+	commandJobObjects.Store(pid, job)
+	return func() {
+		commandJobObjects.Delete(pid)
+		_ = windows.CloseHandle(job)
+	}
+}
+
+// resumeProcessThreads resumes the threads of a process started with
+// CREATE_SUSPENDED. exec.Cmd does not keep the main thread handle, so the
+// threads are found through a snapshot.
+func resumeProcessThreads(pid int) error {
+	snapshot, err := windows.CreateToolhelp32Snapshot(windows.TH32CS_SNAPTHREAD, 0)
+	if err != nil {
+		return fmt.Errorf("snapshot threads: %w", err)
+	}
+	defer func() { _ = windows.CloseHandle(snapshot) }()
+	resumed := 0
+	entry := windows.ThreadEntry32{Size: uint32(unsafe.Sizeof(windows.ThreadEntry32{}))}
+	for err = windows.Thread32First(snapshot, &entry); err == nil; err = windows.Thread32Next(snapshot, &entry) {
+		if entry.OwnerProcessID != uint32(pid) {
+			continue
+		}
+		thread, openErr := windows.OpenThread(windows.THREAD_SUSPEND_RESUME, false, entry.ThreadID)
+		if openErr != nil {
+			return fmt.Errorf("open thread %d: %w", entry.ThreadID, openErr)
+		}
+		_, resumeErr := windows.ResumeThread(thread)
+		_ = windows.CloseHandle(thread)
+		if resumeErr != nil {
+			return fmt.Errorf("resume thread %d: %w", entry.ThreadID, resumeErr)
+		}
+		resumed++
+	}
+	if !errors.Is(err, windows.ERROR_NO_MORE_FILES) {
+		return fmt.Errorf("list threads: %w", err)
+	}
+	if resumed == 0 {
+		return fmt.Errorf("no threads found")
+	}
+	return nil
 }
 
 func interruptCommandTree(cmd *exec.Cmd) error {
@@ -54,6 +142,12 @@ func killCommandTree(cmd *exec.Cmd) error {
 	}
 	taskkill := exec.Command("taskkill", "/PID", strconv.Itoa(pid), "/T", "/F")
 	_, err := taskkill.CombinedOutput()
+	if job, ok := commandJobObjects.Load(pid); ok {
+		if terminateErr := windows.TerminateJobObject(job.(windows.Handle), 1); terminateErr != nil {
+			return terminateErr
+		}
+		return nil
+	}
 	return err
 }
 
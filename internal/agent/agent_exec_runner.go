@@ -11,6 +11,7 @@ import (
 	"os/exec"
 	"path/filepath"
 	"runtime"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -18,6 +19,8 @@ import (
 	"github.com/izzyreal/ciwi/internal/protocol"
 )
 
+const defaultCommandCancelGracePeriod = 2 * time.Second
+
 func runJobScript(
 	runCtx context.Context,
 	client *http.Client,
@@ -53,7 +56,7 @@ func runJobScript(
 			args = append(args, "--env", e)
 		}
 		args = append(args, containerName, "sh", "-lc", tracedScript)
-		cmd = exec.Command("docker", args...)
+		cmd = exec.Command(container.runtime.Command(), args...)
 	} else {
 		bin, args, err := commandForScript(shell, tracedScript)
 		if err == nil && runtime.GOOS == "windows" && shell == shellCmd {
@@ -76,13 +79,15 @@ func runJobScript(
 
 	stopStreaming := streamRunningUpdates(runCtx, client, serverURL, agentID, jobID, output, progress, sensitive, defaultCurrentStep, stepEvent)
 	defer stopStreaming()
-	return runCancelableCommand(runCtx, cmd)
+	return runCancelableCommand(runCtx, cmd, container)
 }
 
-func runCancelableCommand(ctx context.Context, cmd *exec.Cmd) error {
+func runCancelableCommand(ctx context.Context, cmd *exec.Cmd, container *executionContainerContext) error {
 	if err := cmd.Start(); err != nil {
 		return err
 	}
+	release := trackCommandTree(cmd)
+	defer release()
 
 	waitCh := make(chan error, 1)
 	go func() {
@@ -93,9 +98,12 @@ func runCancelableCommand(ctx context.Context, cmd *exec.Cmd) error {
 	case err := <-waitCh:
 		return err
 	case <-ctx.Done():
+		if container != nil {
+			return stopContainerCommand(ctx, cmd, container, waitCh)
+		}
 		descendantPIDs, _ := commandDescendantPIDs(cmd)
 		_ = interruptCommandTree(cmd)
-		timer := time.NewTimer(2 * time.Second)
+		timer := time.NewTimer(commandCancelGracePeriod())
 		defer timer.Stop()
 		select {
 		case err := <-waitCh:
@@ -130,6 +138,59 @@ func runCancelableCommand(ctx context.Context, cmd *exec.Cmd) error {
 	}
 }
 
+// stopContainerCommand cancels a step running through the runtime's exec. The
+// runtime client does not forward signals to the exec'd process, so the
+// interrupt is delivered inside the container and the container is killed once
+// the grace period runs out.
+func stopContainerCommand(ctx context.Context, cmd *exec.Cmd, container *executionContainerContext, waitCh <-chan error) error {
+	name := strings.TrimSpace(container.name)
+	command := container.runtime.Command()
+	signalCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	// kill -1 reaches every process in the container except the caller and
+	// the container's init process.
+	if _, err := runCommandCapture(signalCtx, "", command, "exec", name, "sh", "-c", "kill -INT -1"); err != nil {
+		slog.Warn("interrupt container processes failed", "container", name, "error", err)
+	}
+	timer := time.NewTimer(commandCancelGracePeriod())
+	defer timer.Stop()
+	select {
+	case err := <-waitCh:
+		if err != nil {
+			return err
+		}
+		return ctx.Err()
+	case <-timer.C:
+	}
+	if _, err := runCommandCapture(signalCtx, "", command, "kill", name); err != nil {
+		slog.Warn("kill job container failed", "container", name, "error", err)
+	}
+	_ = killCommandTree(cmd)
+	select {
+	case err := <-waitCh:
+		if err != nil {
+			return err
+		}
+	case <-time.After(2 * time.Second):
+	}
+	return ctx.Err()
+}
+
+// commandCancelGracePeriod is how long a cancelled step may run its cleanup
+// after the interrupt before it is killed.
+func commandCancelGracePeriod() time.Duration {
+	raw := strings.TrimSpace(os.Getenv("CIWI_AGENT_CANCEL_GRACE_SECONDS"))
+	if raw == "" {
+		return defaultCommandCancelGracePeriod
+	}
+	seconds, err := strconv.Atoi(raw)
+	if err != nil || seconds < 0 {
+		slog.Warn("ignoring invalid CIWI_AGENT_CANCEL_GRACE_SECONDS", "value", raw)
+		return defaultCommandCancelGracePeriod
+	}
+	return time.Duration(seconds) * time.Second
+}
+
 func commandForScript(shell, script string) (string, []string, error) {
 	switch normalizeShell(shell) {
 	case shellPosix:
@@ -239,6 +300,7 @@ type outputReportState struct {
 }
 
 type executionContainerContext struct {
+	runtime containerRuntime
 	name    string
 	workdir string
 }
@@ -2,22 +2,70 @@ package agent
 
 import (
 	"context"
+	"fmt"
 	"net/http"
+	"strings"
 
+	"github.com/izzyreal/ciwi/internal/gitauth"
 	"github.com/izzyreal/ciwi/internal/protocol"
 )
 
 // sourceCheckout is the agent-owned seam for materializing a job source. Git
-// is the only implementation today; authenticated or non-Git sources can be
-// added without coupling the execution workflow to their transport details.
+// is the only implementation today; non-Git sources can be added without
+// coupling the execution workflow to their transport details.
 type sourceCheckout interface {
-	Checkout(context.Context, string, protocol.SourceSpec) (string, error)
+	Checkout(context.Context, sourceCheckoutRequest) (sourceCheckoutResult, error)
+}
+
+type sourceCheckoutRequest struct {
+	Destination string
+	Source      protocol.SourceSpec
+	// MirrorRoot holds the repository mirrors shared between jobs; without
+	// it every checkout clones from the remote.
+	MirrorRoot string
+	// Credential authenticates git for this checkout only. It reaches git
+	// through the environment of the checkout commands and never the
+	// workspace, so later steps cannot see it.
+	Credential *protocol.SourceCredential
+}
+
+type sourceCheckoutResult struct {
+	Output string
+	// Mirror reports the shared mirror the checkout came from, if any.
+	Mirror *protocol.JobCacheStats
 }
 
 type gitSourceCheckout struct{}
 
-func (gitSourceCheckout) Checkout(ctx context.Context, destination string, source protocol.SourceSpec) (string, error) {
-	return checkoutSource(ctx, destination, source)
+// Checkout prefers the shared mirror and falls back to cloning from the remote
+// when the mirror cannot be used.
+func (gitSourceCheckout) Checkout(ctx context.Context, request sourceCheckoutRequest) (sourceCheckoutResult, error) {
+	var result sourceCheckoutResult
+	if request.Credential != nil {
+		credential := request.Credential
+		ctx = gitauth.WithProvider(ctx, request.Source.Repo, func() (*protocol.SourceCredential, error) {
+			return credential, nil
+		})
+		result.Output = fmt.Sprintf("[checkout] using %s repository credential\n", credential.Kind)
+	}
+	if strings.TrimSpace(request.MirrorRoot) != "" && boolEnv(gitMirrorsEnv, true) {
+		output, mirror, err := checkoutSourceFromMirror(ctx, request.MirrorRoot, request.Destination, request.Source)
+		result.Output += output
+		if err == nil {
+			result.Mirror = mirror
+			return result, nil
+		}
+		if ctx.Err() != nil {
+			return result, err
+		}
+		result.Output += fmt.Sprintf("[checkout] git mirror unavailable, cloning from the remote: %v\n", err)
+		if err := removeAllWithRetry(request.Destination); err != nil {
+			return result, fmt.Errorf("clear partial checkout: %w", err)
+		}
+	}
+	output, err := checkoutSource(ctx, request.Destination, request.Source)
+	result.Output += output
+	return result, err
 }
 
 // scriptRunner is deliberately shaped around ciwi's current execution unit.
@@ -3,14 +3,12 @@ package agent
 import (
 	"context"
 	"testing"
-
-	"github.com/izzyreal/ciwi/internal/protocol"
 )
 
 type stubSourceCheckout struct{}
 
-func (stubSourceCheckout) Checkout(context.Context, string, protocol.SourceSpec) (string, error) {
-	return "stub", nil
+func (stubSourceCheckout) Checkout(context.Context, sourceCheckoutRequest) (sourceCheckoutResult, error) {
+	return sourceCheckoutResult{Output: "stub"}, nil
 }
 
 type stubScriptRunner struct{}
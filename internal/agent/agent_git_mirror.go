@@ -0,0 +1,191 @@
+package agent
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/izzyreal/ciwi/internal/protocol"
+)
+
+const (
+	gitMirrorCacheID   = "git-mirror"
+	gitMirrorsEnv      = "CIWI_AGENT_GIT_MIRRORS"
+	gitMirrorsSubdir   = "git-mirrors"
+	gitMirrorCacheType = "git-mirror"
+)
+
+// gitMirrorLocks serializes the jobs of this agent that use the same mirror.
+// A job holds the lock from fetching until its clone is done, so a concurrent
+// fetch never moves the ref or objects another job is cloning.
+var gitMirrorLocks = struct {
+	mu     sync.Mutex
+	byPath map[string]*sync.Mutex
+}{byPath: map[string]*sync.Mutex{}}
+
+func lockGitMirror(mirrorDir string) func() {
+	gitMirrorLocks.mu.Lock()
+	lock, ok := gitMirrorLocks.byPath[mirrorDir]
+	if !ok {
+		lock = &sync.Mutex{}
+		gitMirrorLocks.byPath[mirrorDir] = lock
+	}
+	gitMirrorLocks.mu.Unlock()
+	lock.Lock()
+	return lock.Unlock
+}
+
+func gitMirrorRoot(workDir string) string {
+	return filepath.Join(workDir, "cache", gitMirrorsSubdir)
+}
+
+// gitMirrorDir names the mirror of a repository URL after its last path
+// segment, with a hash of the full URL to keep different hosts apart.
+func gitMirrorDir(mirrorRoot, repo string) string {
+	repo = strings.TrimSpace(repo)
+	sum := sha256.Sum256([]byte(repo))
+	name := sanitizeCacheSegment(strings.TrimSuffix(path.Base(filepath.ToSlash(repo)), ".git"))
+	return filepath.Join(mirrorRoot, name+"-"+hex.EncodeToString(sum[:])[:12]+".git")
+}
+
+// checkoutSourceFromMirror checks source out of a bare mirror of its
+// repository kept under mirrorRoot, so later jobs only fetch what changed.
+// The local clone hardlinks the mirror's objects rather than pointing at them
+// through alternates, which keeps the checkout usable inside job containers
+// that only mount the workspace.
+func checkoutSourceFromMirror(ctx context.Context, mirrorRoot, sourceDir string, source protocol.SourceSpec) (string, *protocol.JobCacheStats, error) {
+	var output strings.Builder
+
+	if _, err := exec.LookPath("git"); err != nil {
+		return "", nil, fmt.Errorf("git is required on the agent: %w", err)
+	}
+	if err := requireGitLFS(ctx, source.SourceCheckoutOptions); err != nil {
+		return "", nil, err
+	}
+	mirrorDir := gitMirrorDir(mirrorRoot, source.Repo)
+	commit, stats, err := cloneFromGitMirror(ctx, &output, mirrorDir, sourceDir, source)
+	if err != nil {
+		return output.String(), nil, err
+	}
+	fmt.Fprintf(&output, "[checkout] options: %s\n", gitMirrorCheckoutOptions(source.SourceCheckoutOptions).Summary())
+	if err := checkoutWorkingTree(ctx, &output, sourceDir, commit, source.SourceCheckoutOptions); err != nil {
+		return output.String(), nil, err
+	}
+	return output.String(), &stats, nil
+}
+
+// gitMirrorCheckoutOptions returns options as a mirror checkout applies them
+// to the repository itself: a clone of the mirror has the full history and
+// every tag, whatever depth and fetch_tags ask for. Submodules still use the
+// configured depth.
+func gitMirrorCheckoutOptions(options protocol.SourceCheckoutOptions) protocol.SourceCheckoutOptions {
+	effective := options.Clone()
+	full := 0
+	effective.Depth = &full
+	effective.FetchTags = true
+	return effective
+}
+
+// cloneFromGitMirror updates the mirror and clones it into sourceDir without
+// checking anything out. Only this part holds the mirror lock; submodules and
+// LFS objects are fetched from their remotes afterwards.
+func cloneFromGitMirror(ctx context.Context, output *strings.Builder, mirrorDir, sourceDir string, source protocol.SourceSpec) (string, protocol.JobCacheStats, error) {
+	unlock := lockGitMirror(mirrorDir)
+	defer unlock()
+
+	hit := isDir(mirrorDir)
+	fmt.Fprintf(output, "[checkout] git mirror=%s source=%s\n", filepath.ToSlash(mirrorDir), gitMirrorSource(hit))
+	commit, err := updateGitMirror(ctx, output, mirrorDir, source, hit)
+	if err != nil {
+		return "", protocol.JobCacheStats{}, err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(sourceDir), 0o755); err != nil {
+		return "", protocol.JobCacheStats{}, fmt.Errorf("prepare source parent directory: %w", err)
+	}
+	if err := runGitSteps(ctx, output, []gitStep{
+		{"clone from mirror", []string{"clone", "--local", "--no-checkout", mirrorDir, sourceDir}},
+		{"set origin", []string{"-C", sourceDir, "remote", "set-url", "origin", source.Repo}},
+	}); err != nil {
+		return "", protocol.JobCacheStats{}, err
+	}
+	return commit, gitMirrorCacheStats(mirrorDir, hit), nil
+}
+
+// updateGitMirror creates or fetches the mirror and resolves the commit to
+// check out: the fetched ref, or the default branch without one.
+func updateGitMirror(ctx context.Context, output *strings.Builder, mirrorDir string, source protocol.SourceSpec, hit bool) (string, error) {
+	if hit {
+		fetchAttempts := [][]string{
+			{"-C", mirrorDir, "fetch", "--prune", "origin"},
+			{"-C", mirrorDir, "-c", "http.version=HTTP/1.1", "fetch", "--prune", "origin"},
+			{"-C", mirrorDir, "-c", "http.version=HTTP/1.1", "fetch", "--prune", "origin"},
+		}
+		if err := runGitWithRetry(ctx, output, "mirror fetch", fetchAttempts, nil); err != nil {
+			return "", err
+		}
+	} else {
+		if err := os.MkdirAll(filepath.Dir(mirrorDir), 0o755); err != nil {
+			return "", fmt.Errorf("prepare git mirror directory: %w", err)
+		}
+		cloneAttempts := [][]string{
+			{"clone", "--mirror", source.Repo, mirrorDir},
+			{"-c", "http.version=HTTP/1.1", "clone", "--mirror", source.Repo, mirrorDir},
+			{"-c", "http.version=HTTP/1.1", "clone", "--mirror", source.Repo, mirrorDir},
+		}
+		removeMirror := func() { _ = os.RemoveAll(mirrorDir) }
+		if err := runGitWithRetry(ctx, output, "mirror clone", cloneAttempts, removeMirror); err != nil {
+			removeMirror()
+			return "", err
+		}
+	}
+
+	revision := "HEAD"
+	if ref := strings.TrimSpace(source.Ref); ref != "" {
+		fetchAttempts := [][]string{
+			{"-C", mirrorDir, "fetch", "origin", ref},
+			{"-C", mirrorDir, "-c", "http.version=HTTP/1.1", "fetch", "origin", ref},
+			{"-C", mirrorDir, "-c", "http.version=HTTP/1.1", "fetch", "origin", ref},
+		}
+		if err := runGitWithRetry(ctx, output, fmt.Sprintf("fetch ref %q", ref), fetchAttempts, nil); err != nil {
+			return "", err
+		}
+		revision = "FETCH_HEAD"
+	}
+	commit, err := runCommandCapture(ctx, "", "git", "-C", mirrorDir, "rev-parse", "--verify", revision+"^{commit}")
+	if err != nil {
+		output.WriteString(commit)
+		return "", fmt.Errorf("resolve %s in git mirror: %w", revision, err)
+	}
+	return strings.TrimSpace(commit), nil
+}
+
+func gitMirrorCacheStats(mirrorDir string, hit bool) protocol.JobCacheStats {
+	stats := protocol.JobCacheStats{
+		ID:     gitMirrorCacheID,
+		Type:   gitMirrorCacheType,
+		Path:   filepath.ToSlash(mirrorDir),
+		Source: gitMirrorSource(hit),
+	}
+	files, dirs, size, err := summarizeDir(mirrorDir)
+	if err != nil {
+		stats.Error = err.Error()
+		return stats
+	}
+	stats.Files, stats.Directories, stats.SizeBytes = files, dirs, size
+	return stats
+}
+
+func gitMirrorSource(hit bool) string {
+	if hit {
+		return "hit"
+	}
+	return "miss"
+}
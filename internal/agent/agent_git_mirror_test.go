@@ -0,0 +1,114 @@
+package agent
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/izzyreal/ciwi/internal/protocol"
+)
+
+func TestGitSourceCheckoutSharesMirrorBetweenJobs(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skipf("git not available: %v", err)
+	}
+	root := t.TempDir()
+	remote := filepath.Join(root, "remote.git")
+	work := filepath.Join(root, "work")
+	agentWorkDir := filepath.Join(root, "agent")
+	runGit := func(dir string, args ...string) string {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %v failed: %v\n%s", args, err, string(out))
+		}
+		return strings.TrimSpace(string(out))
+	}
+	commit := func(name string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(work, name), []byte(name+"\n"), 0o644); err != nil {
+			t.Fatalf("write %s: %v", name, err)
+		}
+		runGit(work, "add", name)
+		runGit(work, "commit", "-m", name)
+		runGit(work, "push", "origin", "HEAD:main")
+	}
+	runGit(root, "init", "--bare", "--initial-branch=main", remote)
+	runGit(root, "clone", remote, work)
+	runGit(work, "config", "user.email", "ciwi@example.local")
+	runGit(work, "config", "user.name", "ciwi")
+	commit("base.txt")
+
+	checkout := func(dir, ref string) (sourceCheckoutResult, error) {
+		return gitSourceCheckout{}.Checkout(context.Background(), sourceCheckoutRequest{
+			Destination: filepath.Join(root, dir, "src"),
+			Source:      protocol.SourceSpec{Repo: remote, Ref: ref},
+			MirrorRoot:  gitMirrorRoot(agentWorkDir),
+		})
+	}
+
+	first, err := checkout("job-1", "")
+	if err != nil {
+		t.Fatalf("first checkout failed: %v\noutput:\n%s", err, first.Output)
+	}
+	if first.Mirror == nil || first.Mirror.Source != "miss" || first.Mirror.Type != gitMirrorCacheType || first.Mirror.SizeBytes == 0 {
+		t.Fatalf("expected the first checkout to create the mirror, got %+v", first.Mirror)
+	}
+	if !strings.Contains(first.Output, "[checkout] options: depth=full ") || !strings.Contains(first.Output, "fetch_tags=on") {
+		t.Fatalf("expected the mirror checkout to report full depth and tags, got:\n%s", first.Output)
+	}
+	if got := runGit(filepath.Join(root, "job-1", "src"), "remote", "get-url", "origin"); got != remote {
+		t.Fatalf("expected origin to point at the remote, got %q", got)
+	}
+
+	commit("feature.txt")
+	var wg sync.WaitGroup
+	dirs := []string{"job-2", "job-3"}
+	results := make([]sourceCheckoutResult, len(dirs))
+	errs := make([]error, len(dirs))
+	for i, dir := range dirs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i], errs[i] = checkout(dir, "main")
+		}()
+	}
+	wg.Wait()
+	for i, result := range results {
+		if errs[i] != nil {
+			t.Fatalf("checkout %d failed: %v\noutput:\n%s", i, errs[i], result.Output)
+		}
+		if result.Mirror == nil || result.Mirror.Source != "hit" || result.Mirror.Path != first.Mirror.Path {
+			t.Fatalf("expected checkout %d to reuse the mirror, got %+v", i, result.Mirror)
+		}
+		data, err := os.ReadFile(filepath.Join(root, dirs[i], "src", "feature.txt"))
+		if err != nil || strings.TrimSpace(string(data)) != "feature.txt" {
+			t.Fatalf("expected checkout %d to include the fetched commit, got %q err=%v", i, data, err)
+		}
+	}
+
+	if _, err := wipeAgentCache(agentWorkDir); err != nil {
+		t.Fatalf("wipe cache: %v", err)
+	}
+	if isDir(filepath.FromSlash(first.Mirror.Path)) {
+		t.Fatalf("expected wipe-cache to remove the mirror")
+	}
+}
+
+func TestGitSourceCheckoutFallsBackToRemoteClone(t *testing.T) {
+	t.Setenv(gitMirrorsEnv, "false")
+	result, err := gitSourceCheckout{}.Checkout(context.Background(), sourceCheckoutRequest{
+		Destination: filepath.Join(t.TempDir(), "src"),
+		Source:      protocol.SourceSpec{Repo: filepath.Join(t.TempDir(), "missing.git")},
+		MirrorRoot:  t.TempDir(),
+	})
+	if err == nil || result.Mirror != nil || strings.Contains(result.Output, "git mirror") {
+		t.Fatalf("expected a disabled mirror to clone straight from the remote, got %+v err=%v", result, err)
+	}
+}
@@ -0,0 +1,345 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os/exec"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/izzyreal/ciwi/internal/protocol"
+)
+
+const (
+	defaultServiceHealthTimeout = 60 * time.Second
+	serviceHealthInterval       = time.Second
+	serviceTeardownTimeout      = 30 * time.Second
+)
+
+// serviceLogReportInterval is how often service logs are sent to the server
+// while the steps run.
+var serviceLogReportInterval = 2 * time.Second
+
+type jobService struct {
+	spec      protocol.JobServiceSpec
+	container string
+	phase     protocol.JobExecutionPhase
+	started   time.Time
+	hostPorts map[int]string
+	logs      syncBuffer
+	logsSent  int
+	follower  *exec.Cmd
+	followed  chan struct{}
+	err       error
+}
+
+// jobServices are the service containers of one job execution. They share a
+// network of their own, which the execution container joins; host jobs reach
+// them through ports published on 127.0.0.1 instead. Each service's log is
+// the output of its own timeline phase.
+type jobServices struct {
+	runtime      containerRuntime
+	network      string
+	publishPorts bool
+	services     []*jobService
+	sensitive    []string
+	report       func([]protocol.JobExecutionEvent) error
+
+	mu         sync.Mutex
+	stopStream chan struct{}
+	streamDone chan struct{}
+	stopOnce   sync.Once
+}
+
+// startJobServices starts the services of job and waits for their health
+// checks. On error the returned services still need stop, which removes
+// whatever was started.
+func startJobServices(ctx context.Context, rt containerRuntime, job protocol.JobExecution, timeline []protocol.JobExecutionTimelineItem, publishPorts bool, output io.Writer, report func([]protocol.JobExecutionEvent) error) (*jobServices, error) {
+	id := shortStableID(job.ID)
+	s := &jobServices{
+		runtime:      rt,
+		network:      "ciwi-job-" + id,
+		publishPorts: publishPorts,
+		sensitive:    job.SensitiveValues,
+		report:       report,
+	}
+	command := rt.Command()
+	if _, err := exec.LookPath(command); err != nil {
+		return s, fmt.Errorf("%s not found on agent", command)
+	}
+	// An agent that died mid-job may have left the network behind.
+	cleanupCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	for _, spec := range job.Services {
+		_, _ = runCommandCapture(cleanupCtx, "", command, "rm", "-f", serviceContainerName(id, spec.Name))
+	}
+	_, _ = runCommandCapture(cleanupCtx, "", command, "network", "rm", s.network)
+	cancel()
+	if out, err := runCommandCapture(ctx, "", command, "network", "create", s.network); err != nil {
+		return s, fmt.Errorf("create service network %q: %w; output: %s", s.network, err, strings.TrimSpace(out))
+	}
+	fmt.Fprintf(output, "[services] network=%s runtime=%s\n", s.network, containerRuntimeSummary(rt))
+
+	for _, spec := range job.Services {
+		svc := &jobService{
+			spec:      spec,
+			container: serviceContainerName(id, spec.Name),
+			phase:     executionPhase(timeline, protocol.JobExecutionServicePhaseID(strings.TrimSpace(spec.Name))),
+			started:   time.Now().UTC(),
+		}
+		s.services = append(s.services, svc)
+		_ = report([]protocol.JobExecutionEvent{phaseStartedEvent(svc.phase, svc.started)})
+		if err := s.start(ctx, svc); err != nil {
+			svc.err = err
+			return s, err
+		}
+		fmt.Fprintf(output, "[services] started %s from %s (container=%s)\n", spec.Name, spec.Image, svc.container)
+	}
+	for _, svc := range s.services {
+		started := time.Now()
+		if err := s.waitHealthy(ctx, svc); err != nil {
+			svc.err = err
+			return s, err
+		}
+		fmt.Fprintf(output, "[services] %s ready after %s\n", svc.spec.Name, time.Since(started).Round(time.Millisecond))
+	}
+	s.streamLogs()
+	return s, nil
+}
+
+func serviceContainerName(jobID, name string) string {
+	return "ciwi-svc-" + jobID + "-" + strings.TrimSpace(name)
+}
+
+func (s *jobServices) start(ctx context.Context, svc *jobService) error {
+	command := s.runtime.Command()
+	args := []string{"run", "-d", "--name", svc.container, "--network", s.network}
+	keys := make([]string, 0, len(svc.spec.Env))
+	for key := range svc.spec.Env {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	for _, key := range keys {
+		args = append(args, "-e", key+"="+svc.spec.Env[key])
+	}
+	if s.publishPorts {
+		for _, port := range svc.spec.Ports {
+			args = append(args, "-p", "127.0.0.1::"+strconv.Itoa(port))
+		}
+	}
+	args = append(args, strings.TrimSpace(svc.spec.Image))
+
+	startCtx, cancel := context.WithTimeout(ctx, runtimeContainerStartTimeout)
+	defer cancel()
+	// The command line carries the service env, so it stays out of the error.
+	if out, err := runCommandCapture(startCtx, "", command, args...); err != nil {
+		return fmt.Errorf("start service %q from %q: %w; output: %s", svc.spec.Name, svc.spec.Image, err, redactSensitive(strings.TrimSpace(out), s.sensitive))
+	}
+
+	follower := exec.Command(command, "logs", "-f", svc.container)
+	follower.Stdout = &svc.logs
+	follower.Stderr = &svc.logs
+	if err := follower.Start(); err == nil {
+		svc.follower = follower
+		svc.followed = make(chan struct{})
+		go func() {
+			_ = follower.Wait()
+			close(svc.followed)
+		}()
+	}
+
+	if !s.publishPorts {
+		return nil
+	}
+	svc.hostPorts = map[int]string{}
+	for _, port := range svc.spec.Ports {
+		out, err := runCommandCapture(ctx, "", command, "port", svc.container, strconv.Itoa(port)+"/tcp")
+		if err != nil {
+			return fmt.Errorf("resolve host port of service %q port %d: %w", svc.spec.Name, port, err)
+		}
+		hostPort, ok := parsePublishedPort(out)
+		if !ok {
+			return fmt.Errorf("resolve host port of service %q port %d: unexpected output %q", svc.spec.Name, port, strings.TrimSpace(out))
+		}
+		svc.hostPorts[port] = hostPort
+	}
+	return nil
+}
+
+// parsePublishedPort reads the host port from the first line of `port`
+// output such as "127.0.0.1:49153".
+func parsePublishedPort(out string) (string, bool) {
+	line, _, _ := strings.Cut(strings.TrimSpace(out), "\n")
+	idx := strings.LastIndex(line, ":")
+	if idx < 0 {
+		return "", false
+	}
+	port := strings.TrimSpace(line[idx+1:])
+	if _, err := strconv.Atoi(port); err != nil {
+		return "", false
+	}
+	return port, true
+}
+
+// waitHealthy runs the health check inside the service container until it
+// passes. Without a health check the service only has to be running.
+func (s *jobServices) waitHealthy(ctx context.Context, svc *jobService) error {
+	timeout := defaultServiceHealthTimeout
+	if svc.spec.HealthTimeoutSeconds > 0 {
+		timeout = time.Duration(svc.spec.HealthTimeoutSeconds) * time.Second
+	}
+	healthCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	command := s.runtime.Command()
+	check := strings.TrimSpace(svc.spec.HealthCheck)
+	lastOutput := ""
+	for {
+		out, err := runCommandCapture(healthCtx, "", command, "inspect", "-f", "{{.State.Running}}", svc.container)
+		if err == nil && !strings.EqualFold(strings.TrimSpace(out), "true") {
+			return fmt.Errorf("service %q exited before it became healthy", svc.spec.Name)
+		}
+		if err == nil && check == "" {
+			return nil
+		}
+		if err == nil {
+			out, err = runCommandCapture(healthCtx, "", command, "exec", svc.container, "sh", "-c", check)
+			if err == nil {
+				return nil
+			}
+			lastOutput = strings.TrimSpace(out)
+		}
+		if healthCtx.Err() != nil || !sleepWithContext(healthCtx, serviceHealthInterval) {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			if lastOutput != "" {
+				return fmt.Errorf("service %q was not healthy within %s: %s", svc.spec.Name, timeout, redactSensitive(lastOutput, s.sensitive))
+			}
+			return fmt.Errorf("service %q was not healthy within %s", svc.spec.Name, timeout)
+		}
+	}
+}
+
+func (s *jobServices) streamLogs() {
+	s.stopStream = make(chan struct{})
+	s.streamDone = make(chan struct{})
+	go func() {
+		defer close(s.streamDone)
+		ticker := time.NewTicker(serviceLogReportInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-s.stopStream:
+				return
+			case <-ticker.C:
+				if events := s.pendingLogEvents(); len(events) > 0 {
+					_ = s.report(events)
+				}
+			}
+		}
+	}()
+}
+
+func (s *jobServices) pendingLogEvents() []protocol.JobExecutionEvent {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var events []protocol.JobExecutionEvent
+	for _, svc := range s.services {
+		delta, total := svc.logs.SliceFrom(svc.logsSent)
+		svc.logsSent = total
+		events = append(events, phaseOutputEvent(svc.phase, redactSensitive(delta, s.sensitive))...)
+	}
+	return events
+}
+
+// env returns CIWI_SERVICE_<NAME>_HOST and _PORT for the first port, plus
+// _PORT_<port> for every port. Container jobs get the service's address on
+// the job network, host jobs the port published on 127.0.0.1.
+func (s *jobServices) env() map[string]string {
+	if s == nil || len(s.services) == 0 {
+		return nil
+	}
+	out := map[string]string{}
+	for _, svc := range s.services {
+		prefix := "CIWI_SERVICE_" + strings.ToUpper(strings.ReplaceAll(strings.TrimSpace(svc.spec.Name), "-", "_"))
+		host := svc.container
+		if s.publishPorts {
+			host = "127.0.0.1"
+		}
+		out[prefix+"_HOST"] = host
+		for i, port := range svc.spec.Ports {
+			value := strconv.Itoa(port)
+			if s.publishPorts {
+				value = svc.hostPorts[port]
+			}
+			out[prefix+"_PORT_"+strconv.Itoa(port)] = value
+			if i == 0 {
+				out[prefix+"_PORT"] = value
+			}
+		}
+	}
+	return out
+}
+
+func (s *jobServices) networkName() string {
+	if s == nil {
+		return ""
+	}
+	return s.network
+}
+
+// stop removes the service containers. The first call returns the rest of
+// the service logs and the finished events of the service phases; later
+// calls do nothing.
+func (s *jobServices) stop() []protocol.JobExecutionEvent {
+	if s == nil {
+		return nil
+	}
+	var events []protocol.JobExecutionEvent
+	s.stopOnce.Do(func() {
+		if s.stopStream != nil {
+			close(s.stopStream)
+			<-s.streamDone
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), serviceTeardownTimeout)
+		defer cancel()
+		for _, svc := range s.services {
+			_, _ = runCommandCapture(ctx, "", s.runtime.Command(), "rm", "-f", svc.container)
+		}
+		for _, svc := range s.services {
+			svc.waitForLogs()
+		}
+		events = s.pendingLogEvents()
+		for _, svc := range s.services {
+			events = append(events, phaseFinishedEvent(svc.phase, svc.started, svc.err))
+		}
+	})
+	return events
+}
+
+// removeNetwork removes the job network, which fails while a container is
+// still attached to it.
+func (s *jobServices) removeNetwork() {
+	if s == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), serviceTeardownTimeout)
+	defer cancel()
+	_, _ = runCommandCapture(ctx, "", s.runtime.Command(), "network", "rm", s.network)
+}
+
+// waitForLogs lets the log follower drain once the container is gone.
+func (svc *jobService) waitForLogs() {
+	if svc.follower == nil {
+		return
+	}
+	select {
+	case <-svc.followed:
+	case <-time.After(5 * time.Second):
+		_ = svc.follower.Process.Kill()
+		<-svc.followed
+	}
+}
@@ -0,0 +1,252 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/izzyreal/ciwi/internal/protocol"
+)
+
+func TestParsePublishedPort(t *testing.T) {
+	for out, want := range map[string]string{
+		"127.0.0.1:49153\n":              "49153",
+		"0.0.0.0:5432\n[::]:5432\n":      "5432",
+		"[::1]:40001":                    "40001",
+		"":                               "",
+		"Error: no public port for 80\n": "",
+	} {
+		got, ok := parsePublishedPort(out)
+		if got != want || ok != (want != "") {
+			t.Fatalf("parsePublishedPort(%q) = %q, %t; want %q", out, got, ok, want)
+		}
+	}
+}
+
+func TestJobServicesOnTheJobNetwork(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("fake runtime shell script tests are posix-only")
+	}
+	binDir := t.TempDir()
+	logPath := writeFakeContainerRuntime(t, binDir, "podman", `
+case "$1" in
+  inspect) echo true ;;
+  logs) echo "ready to accept connections password=s3cret" ;;
+esac
+exit 0
+`)
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	job := protocol.JobExecution{
+		ID: "job-services",
+		Services: []protocol.JobServiceSpec{
+			{Name: "postgres", Image: "postgres:16", Env: map[string]string{"POSTGRES_PASSWORD": "s3cret", "POSTGRES_DB": "ci"}, Ports: []int{5432}, HealthCheck: "pg_isready"},
+			{Name: "object-store", Image: "minio/minio", Ports: []int{9000, 9001}},
+		},
+		SensitiveValues: []string{"s3cret"},
+	}
+	timeline := protocol.BuildJobExecutionTimeline(job)
+	var (
+		mu       sync.Mutex
+		reported []protocol.JobExecutionEvent
+	)
+	report := func(events []protocol.JobExecutionEvent) error {
+		mu.Lock()
+		defer mu.Unlock()
+		reported = append(reported, events...)
+		return nil
+	}
+	var output strings.Builder
+	services, err := startJobServices(context.Background(), podmanRuntime{}, job, timeline, false, &output, report)
+	if err != nil {
+		t.Fatalf("startJobServices: %v", err)
+	}
+	id := shortStableID(job.ID)
+	env := services.env()
+	if env["CIWI_SERVICE_POSTGRES_HOST"] != "ciwi-svc-"+id+"-postgres" || env["CIWI_SERVICE_POSTGRES_PORT"] != "5432" ||
+		env["CIWI_SERVICE_OBJECT_STORE_PORT"] != "9000" || env["CIWI_SERVICE_OBJECT_STORE_PORT_9001"] != "9001" {
+		t.Fatalf("unexpected service env: %v", env)
+	}
+	if services.networkName() != "ciwi-job-"+id {
+		t.Fatalf("unexpected network %q", services.networkName())
+	}
+	if !strings.Contains(output.String(), "[services] postgres ready after") {
+		t.Fatalf("expected readiness in the environment output, got:\n%s", output.String())
+	}
+
+	events := services.stop()
+	if again := services.stop(); len(again) != 0 {
+		t.Fatalf("expected a second stop to do nothing, got %+v", again)
+	}
+	services.removeNetwork()
+	mu.Lock()
+	events = append(reported, events...)
+	mu.Unlock()
+	serviceOutput := map[string]string{}
+	finished := map[string]bool{}
+	for _, event := range events {
+		switch event.Type {
+		case protocol.JobExecutionEventTypePhaseOutput:
+			serviceOutput[event.Phase.ID] += event.Output
+		case protocol.JobExecutionEventTypePhaseFinished:
+			finished[event.Phase.ID] = event.Error == ""
+		}
+	}
+	if got := serviceOutput["service:postgres"]; !strings.Contains(got, "ready to accept connections password=***") {
+		t.Fatalf("expected the redacted postgres log in its own phase, got %q", got)
+	}
+	if !finished["service:postgres"] || !finished["service:object-store"] {
+		t.Fatalf("expected both service phases to finish cleanly, got %v", finished)
+	}
+
+	logRaw, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatalf("read fake podman log: %v", err)
+	}
+	log := string(logRaw)
+	for _, want := range []string{
+		"network create ciwi-job-" + id,
+		"run -d --name ciwi-svc-" + id + "-postgres --network ciwi-job-" + id + " -e POSTGRES_DB=ci -e POSTGRES_PASSWORD=s3cret postgres:16",
+		"exec ciwi-svc-" + id + "-postgres sh -c pg_isready",
+		"rm -f ciwi-svc-" + id + "-object-store",
+		"network rm ciwi-job-" + id,
+	} {
+		if !strings.Contains(log, want) {
+			t.Fatalf("expected %q in the runtime calls, got:\n%s", want, log)
+		}
+	}
+	if strings.Contains(log, "-p 127.0.0.1::") {
+		t.Fatalf("expected no published ports for a container job, got:\n%s", log)
+	}
+}
+
+func TestJobServicesFailWhenAServiceExits(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("fake runtime shell script tests are posix-only")
+	}
+	binDir := t.TempDir()
+	logPath := writeFakeContainerRuntime(t, binDir, "docker", `
+case "$1" in
+  inspect) echo false ;;
+  port) echo "127.0.0.1:49153" ;;
+esac
+exit 0
+`)
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	job := protocol.JobExecution{ID: "job-broken", Services: []protocol.JobServiceSpec{{Name: "db", Image: "postgres:16", Ports: []int{5432}, HealthCheck: "pg_isready"}}}
+	services, err := startJobServices(context.Background(), dockerRuntime{}, job, protocol.BuildJobExecutionTimeline(job), true, io.Discard, func([]protocol.JobExecutionEvent) error { return nil })
+	if err == nil || !strings.Contains(err.Error(), `service "db" exited before it became healthy`) {
+		t.Fatalf("expected the exited service to fail the start, got %v", err)
+	}
+	events := services.stop()
+	if len(events) == 0 || events[len(events)-1].Type != protocol.JobExecutionEventTypePhaseFinished || events[len(events)-1].Error == "" {
+		t.Fatalf("expected the service phase to finish with the error, got %+v", events)
+	}
+	logRaw, _ := os.ReadFile(logPath)
+	if log := string(logRaw); !strings.Contains(log, "-p 127.0.0.1::5432 postgres:16") || !strings.Contains(log, "rm -f ciwi-svc-") {
+		t.Fatalf("expected a published port and cleanup, got:\n%s", log)
+	}
+}
+
+func TestExecuteLeasedJobTearsDownServicesOnCancel(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("fake runtime shell script tests are posix-only")
+	}
+	binDir := t.TempDir()
+	logPath := writeFakeContainerRuntime(t, binDir, "docker", `
+case "$1" in
+  inspect) echo true ;;
+  port) echo "127.0.0.1:49153" ;;
+  logs) echo "database system is ready" ;;
+esac
+exit 0
+`)
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	t.Setenv(containerRuntimeEnv, "docker")
+	prevInterval := serviceLogReportInterval
+	serviceLogReportInterval = 50 * time.Millisecond
+	defer func() { serviceLogReportInterval = prevInterval }()
+
+	var (
+		stateChecks int32
+		mu          sync.Mutex
+		statuses    []protocol.JobExecutionStatusUpdateRequest
+	)
+	client := &http.Client{
+		Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+			body := `{"ok":true}`
+			switch {
+			case r.Method == http.MethodGet && r.URL.Path == "/api/v1/jobs/job-services":
+				status := "running"
+				if atomic.AddInt32(&stateChecks, 1) >= 4 {
+					status = "failed"
+				}
+				body = `{"job_execution":{"id":"job-services","status":"` + status + `","error":"cancelled by user"}}`
+			case r.Method == http.MethodPost && r.URL.Path == "/api/v1/jobs/job-services/status":
+				var req protocol.JobExecutionStatusUpdateRequest
+				if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+					t.Errorf("decode status: %v", err)
+				}
+				mu.Lock()
+				statuses = append(statuses, req)
+				mu.Unlock()
+			default:
+				return &http.Response{StatusCode: http.StatusNotFound, Body: io.NopCloser(strings.NewReader("not found")), Header: make(http.Header)}, nil
+			}
+			return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(body)), Header: make(http.Header)}, nil
+		}),
+	}
+
+	job := protocol.JobExecution{
+		ID:             "job-services",
+		TimeoutSeconds: 60,
+		Script:         "echo db=$CIWI_SERVICE_POSTGRES_HOST:$CIWI_SERVICE_POSTGRES_PORT\nsleep 10",
+		StepPlan: []protocol.JobStepPlanItem{
+			{Index: 1, Total: 2, Name: "connect", Script: "echo db=$CIWI_SERVICE_POSTGRES_HOST:$CIWI_SERVICE_POSTGRES_PORT"},
+			{Index: 2, Total: 2, Name: "wait", Script: "sleep 10"},
+		},
+		RequiredCapabilities: map[string]string{"shell": shellPosix},
+		Services:             []protocol.JobServiceSpec{{Name: "postgres", Image: "postgres:16", Ports: []int{5432}, HealthCheck: "pg_isready"}},
+	}
+	execCtx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+	if err := executeLeasedJob(execCtx, client, "http://example.local", "agent-1", t.TempDir(), nil, job); err != nil {
+		t.Fatalf("executeLeasedJob: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	last := statuses[len(statuses)-1]
+	if last.Status != protocol.JobExecutionStatusFailed {
+		t.Fatalf("expected the cancelled job to end failed, got %q", last.Status)
+	}
+	if output := reconstructedStatusOutput(t, statuses); !strings.Contains(output, "db=127.0.0.1:49153") || !strings.Contains(output, "database system is ready") {
+		t.Fatalf("expected the step to see the service address and the service log to stream, got:\n%s", output)
+	}
+	serviceFinished := false
+	for _, event := range last.Events {
+		if event.Type == protocol.JobExecutionEventTypePhaseFinished && event.Phase != nil && event.Phase.ID == "service:postgres" {
+			serviceFinished = true
+		}
+	}
+	if !serviceFinished {
+		t.Fatalf("expected the terminal update to finish the service phase, got %+v", last.Events)
+	}
+	logRaw, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatalf("read fake docker log: %v", err)
+	}
+	id := shortStableID(job.ID)
+	if log := string(logRaw); !strings.Contains(log, "rm -f ciwi-svc-"+id+"-postgres") || !strings.HasSuffix(strings.TrimSpace(log), "network rm ciwi-job-"+id) {
+		t.Fatalf("expected the service and its network to be removed after the job, got:\n%s", log)
+	}
+}
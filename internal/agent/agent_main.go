@@ -7,10 +7,13 @@ import (
 	"log/slog"
 	"net/http"
 	"os"
+	"sort"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
 
+	"github.com/izzyreal/ciwi/internal/domain"
 	"github.com/izzyreal/ciwi/internal/protocol"
 )
 
@@ -34,7 +37,7 @@ type pendingUpdateRequest struct {
 }
 
 type deferredControl struct {
-	jobInProgress         bool
+	activeJobs            int
 	pendingUpdate         *pendingUpdateRequest
 	pendingRestart        bool
 	pendingCacheWipe      bool
@@ -50,6 +53,7 @@ type heartbeatResult struct {
 
 type jobResult struct {
 	jobID string
+	slot  int
 	err   error
 }
 
@@ -60,6 +64,49 @@ type agentHeartbeatState struct {
 	pendingRestartStatus string
 }
 
+// runningJobs tracks the jobs this agent is executing so a heartbeat can
+// report them and stop the ones the server no longer wants run.
+type runningJobs struct {
+	mu      sync.Mutex
+	cancels map[string]context.CancelFunc
+}
+
+func (r *runningJobs) add(jobID string, cancel context.CancelFunc) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.cancels == nil {
+		r.cancels = make(map[string]context.CancelFunc)
+	}
+	r.cancels[jobID] = cancel
+}
+
+func (r *runningJobs) remove(jobID string) {
+	r.mu.Lock()
+	delete(r.cancels, jobID)
+	r.mu.Unlock()
+}
+
+func (r *runningJobs) ids() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	ids := make([]string, 0, len(r.cancels))
+	for id := range r.cancels {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+func (r *runningJobs) cancel(jobID string) bool {
+	r.mu.Lock()
+	cancel, found := r.cancels[jobID]
+	r.mu.Unlock()
+	if found {
+		cancel()
+	}
+	return found
+}
+
 func (s *agentHeartbeatState) snapshot() (string, bool, string) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
@@ -105,6 +152,10 @@ type agentLoopDeps struct {
 	leaseClient      *http.Client
 	control          *deferredControl
 	heartbeatState   *agentHeartbeatState
+	configuredSlots  int
+	slots            int
+	slotJobs         map[int]string
+	running          runningJobs
 	jobDoneCh        chan jobResult
 	triggerHeartbeat func()
 	detectCapsFn     func() map[string]string
@@ -115,12 +166,24 @@ type agentLoopDeps struct {
 	wipeCacheFn      func(string) (string, error)
 	wipeHistoryFn    func(string) (string, error)
 	leaseJobFn       func(context.Context, *http.Client, string, string, map[string]string) (*protocol.JobExecution, error)
-	executeJobFn     func(context.Context, *http.Client, string, string, string, map[string]string, protocol.JobExecution) error
+	executeJobFn     func(context.Context, *http.Client, string, string, string, int, map[string]string, protocol.JobExecution) error
 	heartbeatNowFn   func() heartbeatResult
 }
 
-func (d *deferredControl) setJobInProgress(v bool) {
-	d.jobInProgress = v
+func (d *deferredControl) jobStarted() {
+	d.activeJobs++
+}
+
+func (d *deferredControl) jobFinished() {
+	if d.activeJobs > 0 {
+		d.activeJobs--
+	}
+}
+
+// busy reports whether any job slot is occupied. Deferred controls only run
+// once every slot has drained.
+func (d *deferredControl) busy() bool {
+	return d.activeJobs > 0
 }
 
 func (d *deferredControl) requestUpdate(target, repository, apiBase string, onDefer func(), runNow func(string, string, string)) {
@@ -128,7 +191,7 @@ func (d *deferredControl) requestUpdate(target, repository, apiBase string, onDe
 	if target == "" {
 		return
 	}
-	if d.jobInProgress {
+	if d.busy() {
 		d.pendingUpdate = &pendingUpdateRequest{target: target, repository: repository, apiBase: apiBase}
 		if onDefer != nil {
 			onDefer()
@@ -139,7 +202,7 @@ func (d *deferredControl) requestUpdate(target, repository, apiBase string, onDe
 }
 
 func (d *deferredControl) requestRestart(onDefer func(), runNow func()) {
-	if d.jobInProgress {
+	if d.busy() {
 		d.pendingRestart = true
 		if onDefer != nil {
 			onDefer()
@@ -150,7 +213,7 @@ func (d *deferredControl) requestRestart(onDefer func(), runNow func()) {
 }
 
 func (d *deferredControl) requestCacheWipe(onDefer func(), runNow func()) {
-	if d.jobInProgress {
+	if d.busy() {
 		d.pendingCacheWipe = true
 		if onDefer != nil {
 			onDefer()
@@ -161,7 +224,7 @@ func (d *deferredControl) requestCacheWipe(onDefer func(), runNow func()) {
 }
 
 func (d *deferredControl) requestJobHistoryWipe(onDefer func(), runNow func()) {
-	if d.jobInProgress {
+	if d.busy() {
 		d.pendingJobHistoryWipe = true
 		if onDefer != nil {
 			onDefer()
@@ -183,7 +246,6 @@ func (d *deferredControl) hasDeferred() bool {
 }
 
 func (d *deferredControl) flushDeferred(runUpdate func(string, string, string), runRestart, runCacheWipe, runJobHistoryWipe func()) {
-	d.jobInProgress = false
 	if d.pendingUpdate != nil {
 		req := *d.pendingUpdate
 		d.pendingUpdate = nil
@@ -205,7 +267,7 @@ func (d *deferredControl) flushDeferred(runUpdate func(string, string, string),
 
 func (d *agentLoopDeps) runOrDeferUpdate(target, repository, apiBase string) {
 	d.control.requestUpdate(target, repository, apiBase, func() {
-		slog.Info("server requested agent update; deferring until current job completes", "target_version", target)
+		slog.Info("server requested agent update; deferring until running jobs complete", "target_version", target)
 	}, func(target, repository, apiBase string) {
 		slog.Info("server requested agent update", "target_version", target)
 		d.heartbeatState.setUpdateInProgress(true)
@@ -221,9 +283,9 @@ func (d *agentLoopDeps) runOrDeferUpdate(target, repository, apiBase string) {
 
 func (d *agentLoopDeps) runOrDeferRestart() {
 	d.control.requestRestart(func() {
-		d.heartbeatState.setPendingRestartStatus("restart deferred: agent busy with active job")
+		d.heartbeatState.setPendingRestartStatus("restart deferred: agent busy with active jobs")
 		d.triggerHeartbeat()
-		slog.Info("server requested agent restart; deferring until current job completes")
+		slog.Info("server requested agent restart; deferring until running jobs complete")
 	}, func() {
 		slog.Info("server requested agent restart")
 		d.heartbeatState.setPendingRestartStatus(d.requestRestartFn())
@@ -233,7 +295,7 @@ func (d *agentLoopDeps) runOrDeferRestart() {
 
 func (d *agentLoopDeps) runOrDeferCacheWipe() {
 	d.control.requestCacheWipe(func() {
-		slog.Info("server requested cache wipe; deferring until current job completes")
+		slog.Info("server requested cache wipe; deferring until running jobs complete")
 	}, func() {
 		slog.Info("server requested cache wipe")
 		msg, err := d.wipeCacheFn(d.workDir)
@@ -247,7 +309,7 @@ func (d *agentLoopDeps) runOrDeferCacheWipe() {
 
 func (d *agentLoopDeps) runOrDeferJobHistoryWipe() {
 	d.control.requestJobHistoryWipe(func() {
-		slog.Info("server requested local job history wipe; deferring until current job completes")
+		slog.Info("server requested local job history wipe; deferring until running jobs complete")
 	}, func() {
 		slog.Info("server requested local job history wipe")
 		msg, err := d.wipeHistoryFn(d.workDir)
@@ -265,6 +327,12 @@ func (d *agentLoopDeps) flushDeferred() {
 }
 
 func (d *agentLoopDeps) processHeartbeat(hb protocol.HeartbeatResponse) {
+	d.applySlotCount(hb.Slots)
+	for _, jobID := range hb.CancelJobExecutionIDs {
+		if d.running.cancel(jobID) {
+			slog.Warn("server requested job cancellation; terminating local processes", "job_execution_id", jobID)
+		}
+	}
 	if hb.RefreshToolsRequested {
 		d.setCapsFn(d.detectCapsFn())
 		slog.Info("server requested tools refresh")
@@ -291,17 +359,69 @@ func (d *agentLoopDeps) handleHeartbeatResult(hbRes heartbeatResult) {
 	}
 	d.heartbeatState.ack(hbRes.sentUpdateFailure, hbRes.sentRestartStatus)
 	d.processHeartbeat(hbRes.resp)
-	if !d.control.jobInProgress && d.control.hasDeferred() {
+	if !d.control.busy() && d.control.hasDeferred() {
 		d.flushDeferred()
 	}
 }
 
-func (d *agentLoopDeps) handleLeaseTick() {
-	if !d.control.jobInProgress && d.control.hasDeferred() {
+// applySlotCount adopts the slot count the server assigned. Servers that do
+// not assign one leave the locally configured count in place.
+func (d *agentLoopDeps) applySlotCount(assigned int) {
+	next := d.configuredSlots
+	if assigned > 0 {
+		next = assigned
+	}
+	next = min(max(next, 1), protocol.MaxAgentJobSlots)
+	if next != d.jobSlots() {
+		slog.Info("agent job slots changed", "slots", next, "previous", d.jobSlots())
+	}
+	d.slots = next
+}
+
+func (d *agentLoopDeps) jobSlots() int {
+	if d.slots <= 0 {
+		return 1
+	}
+	return d.slots
+}
+
+// freeSlot returns the lowest unoccupied slot index, or -1 when all slots are
+// taken. Slots above a lowered count drain without being refilled.
+func (d *agentLoopDeps) freeSlot() int {
+	for slot := 0; slot < d.jobSlots(); slot++ {
+		if _, taken := d.slotJobs[slot]; !taken {
+			return slot
+		}
+	}
+	return -1
+}
+
+// drainingForDeferred stops leasing while a deferred control waits for the
+// running jobs, and runs the control once they are done.
+func (d *agentLoopDeps) drainingForDeferred() bool {
+	if !d.control.hasDeferred() {
+		return false
+	}
+	if !d.control.busy() {
+		d.flushDeferred()
+	}
+	return true
+}
+
+func (d *agentLoopDeps) handleJobDone(done jobResult) {
+	if done.err != nil {
+		slog.Error("execute job failed", "job_execution_id", done.jobID, "slot", done.slot, "error", done.err)
+	}
+	delete(d.slotJobs, done.slot)
+	d.running.remove(done.jobID)
+	d.control.jobFinished()
+	if !d.control.busy() && d.control.hasDeferred() {
 		d.flushDeferred()
-		return
 	}
-	if d.control.jobInProgress {
+}
+
+func (d *agentLoopDeps) handleLeaseTick() {
+	if d.drainingForDeferred() || d.freeSlot() < 0 {
 		return
 	}
 	if d.heartbeatNowFn != nil {
@@ -313,30 +433,40 @@ func (d *agentLoopDeps) handleLeaseTick() {
 		if hbRes.resp.UpdateRequested {
 			return
 		}
-		if !d.control.jobInProgress && d.control.hasDeferred() {
-			d.flushDeferred()
+		if d.drainingForDeferred() {
 			return
 		}
-		if d.control.jobInProgress {
+	}
+	for slot := d.freeSlot(); slot >= 0; slot = d.freeSlot() {
+		job, err := d.leaseJobFn(d.ctx, d.leaseClient, d.serverURL, d.agentID, d.getCapsFn())
+		if err != nil {
+			slog.Error("lease failed", "error", err)
 			return
 		}
+		if job == nil {
+			return
+		}
+		d.startJob(slot, *job)
 	}
-	job, err := d.leaseJobFn(d.ctx, d.leaseClient, d.serverURL, d.agentID, d.getCapsFn())
-	if err != nil {
-		slog.Error("lease failed", "error", err)
-		return
-	}
-	if job == nil {
-		return
+}
+
+func (d *agentLoopDeps) startJob(slot int, job protocol.JobExecution) {
+	if d.slotJobs == nil {
+		d.slotJobs = make(map[int]string)
 	}
-	d.control.setJobInProgress(true)
+	d.slotJobs[slot] = job.ID
+	jobCtx, cancelJob := context.WithCancel(d.ctx)
+	d.running.add(job.ID, cancelJob)
+	d.control.jobStarted()
 	jobCaps := d.getCapsFn()
 	go func(leased protocol.JobExecution, caps map[string]string) {
+		defer cancelJob()
 		d.jobDoneCh <- jobResult{
 			jobID: leased.ID,
-			err:   d.executeJobFn(d.ctx, d.jobClient, d.serverURL, d.agentID, d.workDir, caps, leased),
+			slot:  slot,
+			err:   d.executeJobFn(jobCtx, d.jobClient, d.serverURL, d.agentID, d.workDir, slot, caps, leased),
 		}
-	}(*job, jobCaps)
+	}(job, jobCaps)
 }
 
 func Run(ctx context.Context) error {
@@ -363,9 +493,16 @@ func runLoop(ctx context.Context) error {
 	slog.Info("ciwi agent started", "agent_id", agentID, "version", currentVersion(), "server_url", serverURL)
 	defer slog.Info("ciwi agent stopped", "agent_id", agentID)
 
-	jobClient := &http.Client{Timeout: agentJobHTTPTimeout}
-	heartbeatClient := &http.Client{Timeout: agentHeartbeatHTTPTimeout}
-	leaseClient := &http.Client{Timeout: agentLeaseHTTPTimeout}
+	credential, err := loadOrEnrollAgentCredential(ctx, &http.Client{Timeout: agentHeartbeatHTTPTimeout}, serverURL, agentID, agentCredentialPath(workDir), os.Getenv(agentEnrollmentTokenEnv))
+	if err != nil {
+		return fmt.Errorf("agent enrollment: %w", err)
+	}
+	// Jobs inherit the agent environment; the token has served its purpose.
+	_ = os.Unsetenv(agentEnrollmentTokenEnv)
+	transport := newAgentCredentialTransport(serverURL, agentID, credential)
+	jobClient := &http.Client{Timeout: agentJobHTTPTimeout, Transport: transport}
+	heartbeatClient := &http.Client{Timeout: agentHeartbeatHTTPTimeout, Transport: transport}
+	leaseClient := &http.Client{Timeout: agentLeaseHTTPTimeout, Transport: transport}
 	leaseTicker := time.NewTicker(3 * time.Second)
 	defer leaseTicker.Stop()
 	capabilities := detectAgentCapabilities()
@@ -381,9 +518,11 @@ func runLoop(ctx context.Context) error {
 		capabilitiesMu.Unlock()
 	}
 
+	configuredSlots := agentJobSlotsFromEnv()
+	configuredLabels := agentLabelsFromEnv()
 	heartbeatState := &agentHeartbeatState{pendingRestartStatus: startupHeartbeatGreeting()}
 	control := &deferredControl{}
-	jobDoneCh := make(chan jobResult, 1)
+	jobDoneCh := make(chan jobResult, configuredSlots)
 	heartbeatRespCh := make(chan heartbeatResult, 4)
 	heartbeatKickCh := make(chan struct{}, 1)
 	triggerHeartbeat := func() {
@@ -402,6 +541,9 @@ func runLoop(ctx context.Context) error {
 		leaseClient:      leaseClient,
 		control:          control,
 		heartbeatState:   heartbeatState,
+		configuredSlots:  configuredSlots,
+		slots:            configuredSlots,
+		slotJobs:         make(map[int]string),
 		jobDoneCh:        jobDoneCh,
 		triggerHeartbeat: triggerHeartbeat,
 		detectCapsFn:     detectAgentCapabilities,
@@ -412,7 +554,7 @@ func runLoop(ctx context.Context) error {
 		wipeCacheFn:      wipeAgentCache,
 		wipeHistoryFn:    wipeAgentJobHistory,
 		leaseJobFn:       leaseJob,
-		executeJobFn:     executeLeasedJob,
+		executeJobFn:     executeLeasedJobInSlot,
 	}
 
 	go func() {
@@ -421,7 +563,7 @@ func runLoop(ctx context.Context) error {
 
 		send := func() heartbeatResult {
 			updateFailure, updateInProgress, restartStatus := heartbeatState.snapshot()
-			hb, err := sendHeartbeat(ctx, heartbeatClient, serverURL, agentID, hostname, getCapabilities(), updateFailure, updateInProgress, restartStatus)
+			hb, err := sendHeartbeat(ctx, heartbeatClient, serverURL, agentID, hostname, getCapabilities(), configuredSlots, configuredLabels, loopDeps.running.ids(), updateFailure, updateInProgress, restartStatus)
 			return heartbeatResult{
 				resp:              hb,
 				err:               err,
@@ -451,7 +593,7 @@ func runLoop(ctx context.Context) error {
 	}()
 	loopDeps.heartbeatNowFn = func() heartbeatResult {
 		updateFailure, updateInProgress, restartStatus := heartbeatState.snapshot()
-		hb, err := sendHeartbeat(ctx, heartbeatClient, serverURL, agentID, hostname, getCapabilities(), updateFailure, updateInProgress, restartStatus)
+		hb, err := sendHeartbeat(ctx, heartbeatClient, serverURL, agentID, hostname, getCapabilities(), configuredSlots, configuredLabels, loopDeps.running.ids(), updateFailure, updateInProgress, restartStatus)
 		return heartbeatResult{
 			resp:              hb,
 			err:               err,
@@ -465,10 +607,7 @@ func runLoop(ctx context.Context) error {
 		case <-ctx.Done():
 			return nil
 		case done := <-jobDoneCh:
-			if done.err != nil {
-				slog.Error("execute job failed", "job_execution_id", done.jobID, "error", done.err)
-			}
-			loopDeps.flushDeferred()
+			loopDeps.handleJobDone(done)
 		case hbRes := <-heartbeatRespCh:
 			loopDeps.handleHeartbeatResult(hbRes)
 		case <-leaseTicker.C:
@@ -499,3 +638,33 @@ func selfUpdateWritabilityWarning() string {
 func startupHeartbeatGreeting() string {
 	return fmt.Sprintf("ciwi agent has (re)started (pid=%d)", os.Getpid())
 }
+
+// agentJobSlotsFromEnv reads how many jobs this agent runs at once. Operators
+// can override it per agent from the server.
+func agentJobSlotsFromEnv() int {
+	raw := strings.TrimSpace(os.Getenv("CIWI_AGENT_SLOTS"))
+	if raw == "" {
+		return 1
+	}
+	slots, err := strconv.Atoi(raw)
+	if err != nil || slots < 1 {
+		slog.Warn("ignoring invalid CIWI_AGENT_SLOTS", "value", raw)
+		return 1
+	}
+	return min(slots, protocol.MaxAgentJobSlots)
+}
+
+// agentLabelsFromEnv reads the labels this agent declares, such as
+// "dongle=yes, site=lab". Labels assigned from the server win over these.
+func agentLabelsFromEnv() map[string]string {
+	raw := strings.TrimSpace(os.Getenv("CIWI_AGENT_LABELS"))
+	if raw == "" {
+		return nil
+	}
+	labels, err := domain.ParseAgentLabels(raw)
+	if err != nil {
+		slog.Warn("ignoring invalid CIWI_AGENT_LABELS", "value", raw, "error", err)
+		return nil
+	}
+	return labels
+}
@@ -27,7 +27,8 @@ func TestDeferredControlRequestsAndFlushOrdering(t *testing.T) {
 
 	// While busy, actions should defer (and only latest update request should survive).
 	events = events[:0]
-	c.setJobInProgress(true)
+	c.jobStarted()
+	c.jobStarted()
 	deferred := make([]string, 0)
 	c.requestUpdate("v2.0.0", "repoA", "apiA", func() { deferred = append(deferred, "defer-update-a") }, runUpdate)
 	c.requestUpdate("v2.0.1", "repoB", "apiB", func() { deferred = append(deferred, "defer-update-b") }, runUpdate)
@@ -42,19 +43,25 @@ func TestDeferredControlRequestsAndFlushOrdering(t *testing.T) {
 		t.Fatalf("unexpected deferred markers: got=%v want=%v", deferred, wantDeferred)
 	}
 
+	// Deferred actions wait for every slot, not just the first to finish.
+	c.jobFinished()
+	if !c.busy() {
+		t.Fatalf("expected control to stay busy while another slot runs")
+	}
+	c.jobFinished()
 	c.flushDeferred(runUpdate, runRestart, runCache, runHistory)
 	want = []string{"update:v2.0.1|repoB|apiB", "restart", "cache", "history"}
 	if !reflect.DeepEqual(events, want) {
 		t.Fatalf("unexpected flush order/events: got=%v want=%v", events, want)
 	}
-	if c.jobInProgress || c.pendingUpdate != nil || c.pendingRestart || c.pendingCacheWipe || c.pendingJobHistoryWipe {
+	if c.busy() || c.pendingUpdate != nil || c.pendingRestart || c.pendingCacheWipe || c.pendingJobHistoryWipe {
 		t.Fatalf("expected deferred state to be fully cleared after flush: %+v", c)
 	}
 }
 
 func TestDeferredControlIgnoresEmptyUpdateTarget(t *testing.T) {
 	c := &deferredControl{}
-	c.setJobInProgress(true)
+	c.jobStarted()
 	c.requestUpdate("   ", "repo", "api", nil, func(string, string, string) {
 		t.Fatalf("runNow should not be called for empty update target")
 	})
@@ -163,17 +163,18 @@ func TestAgentLoopDepsHandleHeartbeatResultFlushesDeferredWhenIdle(t *testing.T)
 	if restartStatus != "restart requested" {
 		t.Fatalf("expected restart status to be updated by deferred restart, got %q", restartStatus)
 	}
-	if control.hasDeferred() || control.jobInProgress {
+	if control.hasDeferred() || control.busy() {
 		t.Fatalf("expected deferred control to be cleared after flush: %+v", control)
 	}
 }
 
 func TestAgentLoopDepsHandleLeaseTickBusySkipsLease(t *testing.T) {
-	control := &deferredControl{jobInProgress: true}
+	control := &deferredControl{activeJobs: 1}
 	calledLease := false
 	deps := &agentLoopDeps{
 		ctx:            context.Background(),
 		control:        control,
+		slotJobs:       map[int]string{0: "job-1"},
 		heartbeatState: &agentHeartbeatState{},
 		getCapsFn:      func() map[string]string { return nil },
 		leaseJobFn: func(context.Context, *http.Client, string, string, map[string]string) (*protocol.JobExecution, error) {
@@ -216,7 +217,7 @@ func TestAgentLoopDepsHandleLeaseTickFlushesDeferredInsteadOfLeasing(t *testing.
 			events = append(events, "lease")
 			return nil, nil
 		},
-		executeJobFn: func(context.Context, *http.Client, string, string, string, map[string]string, protocol.JobExecution) error {
+		executeJobFn: func(context.Context, *http.Client, string, string, string, int, map[string]string, protocol.JobExecution) error {
 			return nil
 		},
 		jobDoneCh: make(chan jobResult, 1),
@@ -265,7 +266,7 @@ func TestAgentLoopDepsHandleLeaseTickProcessesFreshHeartbeatBeforeLeasing(t *tes
 			events = append(events, "lease")
 			return nil, nil
 		},
-		executeJobFn: func(context.Context, *http.Client, string, string, string, map[string]string, protocol.JobExecution) error {
+		executeJobFn: func(context.Context, *http.Client, string, string, string, int, map[string]string, protocol.JobExecution) error {
 			return nil
 		},
 		jobDoneCh: make(chan jobResult, 1),
@@ -293,7 +294,7 @@ func TestAgentLoopDepsHandleLeaseTickSchedulesJobExecution(t *testing.T) {
 		leaseJobFn: func(context.Context, *http.Client, string, string, map[string]string) (*protocol.JobExecution, error) {
 			return job, nil
 		},
-		executeJobFn: func(context.Context, *http.Client, string, string, string, map[string]string, protocol.JobExecution) error {
+		executeJobFn: func(context.Context, *http.Client, string, string, string, int, map[string]string, protocol.JobExecution) error {
 			gotCapsCh <- map[string]string{"executor": "script"}
 			return errors.New("job failed")
 		},
@@ -301,7 +302,7 @@ func TestAgentLoopDepsHandleLeaseTickSchedulesJobExecution(t *testing.T) {
 	}
 
 	deps.handleLeaseTick()
-	if !control.jobInProgress {
+	if !control.busy() {
 		t.Fatalf("expected control to mark job in progress once leased")
 	}
 	select {
@@ -324,3 +325,116 @@ func TestAgentLoopDepsHandleLeaseTickSchedulesJobExecution(t *testing.T) {
 		t.Fatalf("timed out waiting for job result")
 	}
 }
+
+func TestAgentLoopDepsFillsSlotsAndDrainsBeforeDeferredRestart(t *testing.T) {
+	control := &deferredControl{}
+	release := make(chan struct{})
+	jobDoneCh := make(chan jobResult, 2)
+	leased := 0
+	gotSlots := make(chan int, 2)
+	restarts := 0
+	deps := &agentLoopDeps{
+		ctx:              context.Background(),
+		workDir:          t.TempDir(),
+		control:          control,
+		heartbeatState:   &agentHeartbeatState{},
+		configuredSlots:  2,
+		slots:            2,
+		triggerHeartbeat: func() {},
+		getCapsFn:        func() map[string]string { return nil },
+		requestRestartFn: func() string {
+			restarts++
+			return "restart requested"
+		},
+		leaseJobFn: func(context.Context, *http.Client, string, string, map[string]string) (*protocol.JobExecution, error) {
+			leased++
+			return &protocol.JobExecution{ID: "job-" + string(rune('0'+leased))}, nil
+		},
+		executeJobFn: func(_ context.Context, _ *http.Client, _, _, _ string, slot int, _ map[string]string, _ protocol.JobExecution) error {
+			gotSlots <- slot
+			<-release
+			return nil
+		},
+		jobDoneCh: jobDoneCh,
+	}
+
+	deps.handleLeaseTick()
+	if leased != 2 || control.activeJobs != 2 {
+		t.Fatalf("expected both slots to be filled, leased=%d active=%d", leased, control.activeJobs)
+	}
+	slots := map[int]bool{<-gotSlots: true, <-gotSlots: true}
+	if !slots[0] || !slots[1] {
+		t.Fatalf("expected jobs in slots 0 and 1, got %v", slots)
+	}
+
+	deps.processHeartbeat(protocol.HeartbeatResponse{RestartRequested: true, Slots: 2})
+	close(release)
+	deps.handleJobDone(<-jobDoneCh)
+	if restarts != 0 {
+		t.Fatalf("expected restart to wait for the second slot")
+	}
+	deps.handleLeaseTick()
+	if leased != 2 {
+		t.Fatalf("expected no new leases while draining for restart, leased=%d", leased)
+	}
+	deps.handleJobDone(<-jobDoneCh)
+	if restarts != 1 {
+		t.Fatalf("expected restart once all slots drained, got %d", restarts)
+	}
+}
+
+func TestAgentLoopDepsAdoptsServerAssignedSlots(t *testing.T) {
+	deps := &agentLoopDeps{control: &deferredControl{}, configuredSlots: 2, slots: 2}
+	deps.applySlotCount(4)
+	if deps.jobSlots() != 4 {
+		t.Fatalf("expected server override to apply, got %d", deps.jobSlots())
+	}
+	deps.applySlotCount(0)
+	if deps.jobSlots() != 2 {
+		t.Fatalf("expected configured slots without a server assignment, got %d", deps.jobSlots())
+	}
+	deps.applySlotCount(protocol.MaxAgentJobSlots + 10)
+	if deps.jobSlots() != protocol.MaxAgentJobSlots {
+		t.Fatalf("expected slot count to be capped, got %d", deps.jobSlots())
+	}
+}
+
+func TestAgentLoopDepsCancelsJobsTheServerStopped(t *testing.T) {
+	jobDoneCh := make(chan jobResult, 2)
+	deps := &agentLoopDeps{
+		ctx:              context.Background(),
+		workDir:          t.TempDir(),
+		control:          &deferredControl{},
+		heartbeatState:   &agentHeartbeatState{},
+		configuredSlots:  2,
+		slots:            2,
+		triggerHeartbeat: func() {},
+		getCapsFn:        func() map[string]string { return nil },
+		executeJobFn: func(ctx context.Context, _ *http.Client, _, _, _ string, _ int, _ map[string]string, _ protocol.JobExecution) error {
+			<-ctx.Done()
+			return ctx.Err()
+		},
+		jobDoneCh: jobDoneCh,
+	}
+	deps.startJob(0, protocol.JobExecution{ID: "job-keep"})
+	deps.startJob(1, protocol.JobExecution{ID: "job-stop"})
+	if got := deps.running.ids(); !reflect.DeepEqual(got, []string{"job-keep", "job-stop"}) {
+		t.Fatalf("unexpected running jobs: %v", got)
+	}
+
+	deps.processHeartbeat(protocol.HeartbeatResponse{CancelJobExecutionIDs: []string{"job-stop", "job-gone"}, Slots: 2})
+	select {
+	case done := <-jobDoneCh:
+		if done.jobID != "job-stop" || !errors.Is(done.err, context.Canceled) {
+			t.Fatalf("unexpected job result: %+v", done)
+		}
+		deps.handleJobDone(done)
+	case <-time.After(2 * time.Second):
+		t.Fatalf("timed out waiting for the cancelled job")
+	}
+	if got := deps.running.ids(); !reflect.DeepEqual(got, []string{"job-keep"}) {
+		t.Fatalf("expected only the untouched job to keep running, got %v", got)
+	}
+	deps.running.cancel("job-keep")
+	deps.handleJobDone(<-jobDoneCh)
+}
@@ -21,14 +21,14 @@ const hostToolRequirementPrefix = "requires.tool."
 
 var runtimeContainerStartTimeout = 60 * time.Second
 
-func collectRuntimeCapabilities(agentCapabilities map[string]string, probeContainer string) map[string]string {
+func collectRuntimeCapabilities(agentCapabilities map[string]string, rt containerRuntime, probeContainer string) map[string]string {
 	out := map[string]string{}
 	for k, v := range agentCapabilities {
 		k = strings.TrimSpace(k)
 		if k == "" || strings.TrimSpace(v) == "" {
 			continue
 		}
-		if strings.HasPrefix(k, "tool.") || k == "os" || k == "arch" || k == "shells" || k == "executor" || k == "run_mode" {
+		if strings.HasPrefix(k, "tool.") || k == "os" || k == "arch" || k == "shells" || k == "executor" || k == "run_mode" || k == "container_runtime" {
 			out["host."+k] = strings.TrimSpace(v)
 		}
 	}
@@ -37,8 +37,13 @@ func collectRuntimeCapabilities(agentCapabilities map[string]string, probeContai
 		return out
 	}
 	out["container.name"] = container
-	if _, err := exec.LookPath("docker"); err != nil {
-		out["container.probe_error"] = "docker not found on agent"
+	if rt == nil {
+		out["container.probe_error"] = "no container runtime on agent"
+		return out
+	}
+	out["container.runtime"] = rt.Name()
+	if _, err := exec.LookPath(rt.Command()); err != nil {
+		out["container.probe_error"] = rt.Command() + " not found on agent"
 		return out
 	}
 	tools := []struct {
@@ -64,7 +69,7 @@ func collectRuntimeCapabilities(agentCapabilities map[string]string, probeContai
 		{name: "signtool", cmd: "signtool", args: []string{"/?"}},
 	}
 	for _, t := range tools {
-		if v := detectToolVersionInContainer(container, t.cmd, t.args...); v != "" {
+		if v := detectToolVersionInContainer(rt, container, t.cmd, t.args...); v != "" {
 			out["container.tool."+t.name] = v
 		}
 	}
@@ -74,7 +79,7 @@ func collectRuntimeCapabilities(agentCapabilities map[string]string, probeContai
 	return out
 }
 
-func detectToolVersionInContainer(container, cmd string, args ...string) string {
+func detectToolVersionInContainer(rt containerRuntime, container, cmd string, args ...string) string {
 	container = strings.TrimSpace(container)
 	cmd = strings.TrimSpace(cmd)
 	if container == "" || cmd == "" {
@@ -87,7 +92,7 @@ func detectToolVersionInContainer(container, cmd string, args ...string) string
 	script := quoted
 	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
 	defer cancel()
-	out, err := runCommandCapture(ctx, "", "docker", "exec", container, "sh", "-lc", script)
+	out, err := runCommandCapture(ctx, "", rt.Command(), "exec", container, "sh", "-lc", script)
 	if err != nil && strings.TrimSpace(out) == "" {
 		return ""
 	}
@@ -219,9 +224,11 @@ type runtimeContainerMount struct {
 }
 
 type runtimeContainerConfig struct {
+	runtime containerRuntime
 	name    string
 	image   string
 	workdir string
+	network string
 	user    string
 	mounts  []runtimeContainerMount
 	devices []string
@@ -263,38 +270,28 @@ func startRuntimeContainer(ctx context.Context, cfg runtimeContainerConfig) erro
 	if name == "" || image == "" {
 		return nil
 	}
-	name = strings.TrimSpace(name)
-	if _, err := exec.LookPath("docker"); err != nil {
-		return fmt.Errorf("docker not found on agent")
+	if cfg.runtime == nil {
+		return fmt.Errorf("no container runtime on agent")
+	}
+	command := cfg.runtime.Command()
+	if _, err := exec.LookPath(command); err != nil {
+		return fmt.Errorf("%s not found on agent", command)
 	}
 	if err := ensureHostMountPaths(cfg.mounts); err != nil {
 		return err
 	}
 	cleanupCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
-	_, _ = runCommandCapture(cleanupCtx, "", "docker", "rm", "-f", name)
+	_, _ = runCommandCapture(cleanupCtx, "", command, "rm", "-f", name)
 
 	args := []string{"run", "-d", "--name", name}
 	if workdir := strings.TrimSpace(cfg.workdir); workdir != "" {
 		args = append(args, "-w", workdir)
 	}
-	if userSpec := strings.TrimSpace(cfg.user); userSpec != "" {
-		args = append(args, "--user", userSpec)
-	}
-	for _, d := range cfg.devices {
-		device := strings.TrimSpace(d)
-		if device == "" {
-			continue
-		}
-		args = append(args, "--device", device)
-	}
-	for _, g := range cfg.groups {
-		group := strings.TrimSpace(g)
-		if group == "" {
-			continue
-		}
-		args = append(args, "--group-add", group)
+	if network := strings.TrimSpace(cfg.network); network != "" {
+		args = append(args, "--network", network)
 	}
+	args = append(args, cfg.runtime.IdentityArgs(cfg.user, cfg.groups, cfg.devices)...)
 	for _, m := range cfg.mounts {
 		hostPath := strings.TrimSpace(m.hostPath)
 		containerPath := strings.TrimSpace(m.containerPath)
@@ -307,8 +304,8 @@ func startRuntimeContainer(ctx context.Context, cfg runtimeContainerConfig) erro
 
 	startCtx, startCancel := context.WithTimeout(ctx, runtimeContainerStartTimeout)
 	defer startCancel()
-	if out, err := runCommandCapture(startCtx, "", "docker", args...); err != nil {
-		cmdLine := "docker " + shellJoin(args)
+	if out, err := runCommandCapture(startCtx, "", command, args...); err != nil {
+		cmdLine := command + " " + shellJoin(args)
 		out = strings.TrimSpace(out)
 		if errors.Is(startCtx.Err(), context.DeadlineExceeded) {
 			if out != "" {
@@ -324,31 +321,34 @@ func startRuntimeContainer(ctx context.Context, cfg runtimeContainerConfig) erro
 	return nil
 }
 
-func cleanupRuntimeProbeContainer(ctx context.Context, name string) {
+func cleanupRuntimeProbeContainer(ctx context.Context, rt containerRuntime, name string) {
 	name = strings.TrimSpace(name)
-	if name == "" {
+	if name == "" || rt == nil {
 		return
 	}
-	if _, err := exec.LookPath("docker"); err != nil {
+	if _, err := exec.LookPath(rt.Command()); err != nil {
 		return
 	}
 	cleanupCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
-	_, _ = runCommandCapture(cleanupCtx, "", "docker", "rm", "-f", name)
+	_, _ = runCommandCapture(cleanupCtx, "", rt.Command(), "rm", "-f", name)
 }
 
-func validateProbeContainerReady(ctx context.Context, name, image string) error {
+func validateProbeContainerReady(ctx context.Context, rt containerRuntime, name, image string) error {
 	name = strings.TrimSpace(name)
 	image = strings.TrimSpace(image)
 	if name == "" || image == "" {
 		return nil
 	}
-	if _, err := exec.LookPath("docker"); err != nil {
-		return fmt.Errorf("docker not found on agent")
+	if rt == nil {
+		return fmt.Errorf("no container runtime on agent")
+	}
+	if _, err := exec.LookPath(rt.Command()); err != nil {
+		return fmt.Errorf("%s not found on agent", rt.Command())
 	}
 	checkCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
-	out, err := runCommandCapture(checkCtx, "", "docker", "inspect", "-f", "{{.State.Running}}", name)
+	out, err := runCommandCapture(checkCtx, "", rt.Command(), "inspect", "-f", "{{.State.Running}}", name)
 	if err != nil {
 		return fmt.Errorf("runtime container %q is not inspectable: %w", name, err)
 	}
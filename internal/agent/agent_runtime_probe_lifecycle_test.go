@@ -57,6 +57,7 @@ exit 0
 
 	hostMount := filepath.Join(t.TempDir(), "workspace")
 	cfg := runtimeContainerConfig{
+		runtime: dockerRuntime{},
 		name:    "ciwi-probe-1",
 		image:   "ubuntu-vmpc",
 		workdir: "/workspace",
@@ -72,16 +73,16 @@ exit 0
 		t.Fatalf("expected host mount path prepared: %v", err)
 	}
 
-	if err := validateProbeContainerReady(context.Background(), cfg.name, cfg.image); err != nil {
+	if err := validateProbeContainerReady(context.Background(), cfg.runtime, cfg.name, cfg.image); err != nil {
 		t.Fatalf("validateProbeContainerReady should pass when inspect=true: %v", err)
 	}
 	t.Setenv("CIWI_DOCKER_INSPECT_RUNNING", "false")
-	if err := validateProbeContainerReady(context.Background(), cfg.name, cfg.image); err == nil {
+	if err := validateProbeContainerReady(context.Background(), cfg.runtime, cfg.name, cfg.image); err == nil {
 		t.Fatalf("validateProbeContainerReady should fail when inspect=false")
 	}
 	t.Setenv("CIWI_DOCKER_INSPECT_RUNNING", "true")
 
-	cleanupRuntimeProbeContainer(context.Background(), cfg.name)
+	cleanupRuntimeProbeContainer(context.Background(), cfg.runtime, cfg.name)
 
 	logRaw, err := os.ReadFile(logPath)
 	if err != nil {
@@ -109,14 +110,16 @@ kill -9 $$
 	t.Setenv("CIWI_DOCKER_LOG", filepath.Join(binDir, "docker.log"))
 
 	err := startRuntimeContainer(context.Background(), runtimeContainerConfig{
-		name:  "ciwi-probe-fail",
-		image: "ubuntu-vmpc",
+		runtime: dockerRuntime{},
+		name:    "ciwi-probe-fail",
+		image:   "ubuntu-vmpc",
+		user:    "1000:1000",
 	})
 	if err == nil {
 		t.Fatal("expected startRuntimeContainer to fail")
 	}
 	msg := err.Error()
-	if !strings.Contains(msg, "docker run -d --name ciwi-probe-fail ubuntu-vmpc sleep infinity") {
+	if !strings.Contains(msg, "docker run -d --name ciwi-probe-fail --user 1000:1000 ubuntu-vmpc sleep infinity") {
 		t.Fatalf("expected docker command in error, got: %v", err)
 	}
 	if !strings.Contains(msg, "docker: cannot allocate memory") {
@@ -142,8 +145,9 @@ func TestStartRuntimeContainerReportsTimeoutClearly(t *testing.T) {
 	})
 
 	err := startRuntimeContainer(context.Background(), runtimeContainerConfig{
-		name:  "ciwi-probe-timeout",
-		image: "ubuntu-vmpc",
+		runtime: dockerRuntime{},
+		name:    "ciwi-probe-timeout",
+		image:   "ubuntu-vmpc",
 	})
 	if err == nil {
 		t.Fatal("expected startRuntimeContainer to time out")
@@ -186,11 +190,14 @@ exit 0
 		"run_mode":   "service",
 		"tool.git":   "2.39.5",
 		"tool.cmake": "3.25.1",
-	}, "ubuntu-vmpc")
+	}, dockerRuntime{}, "ubuntu-vmpc")
 
 	if caps["container.name"] != "ubuntu-vmpc" {
 		t.Fatalf("expected container.name capability, got %v", caps)
 	}
+	if caps["container.runtime"] != "docker" {
+		t.Fatalf("expected container.runtime capability, got %v", caps)
+	}
 	if caps["host.tool.git"] != "2.39.5" {
 		t.Fatalf("expected host tool capability propagation, got %v", caps)
 	}
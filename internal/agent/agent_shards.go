@@ -0,0 +1,43 @@
+package agent
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/izzyreal/ciwi/internal/protocol"
+)
+
+const shardTestsRelPath = ".ciwi/shard-tests.txt"
+
+// writeShardTestsFile writes the tests the server assigned to this shard into
+// the workspace, one "package<TAB>name" line each, and returns the env that
+// points the job script at it. containerWorkdir is where the workspace is
+// mounted when the job runs in a container.
+func writeShardTestsFile(execDir, containerWorkdir string, job protocol.JobExecution) (map[string]string, int, error) {
+	if _, _, ok := job.Metadata.Shard(); !ok {
+		return nil, 0, nil
+	}
+	tests, err := job.Metadata.ShardTests()
+	if err != nil {
+		return nil, 0, err
+	}
+	var content strings.Builder
+	for _, test := range tests {
+		fmt.Fprintf(&content, "%s\t%s\n", test.Package, test.Name)
+	}
+	hostPath := filepath.Join(execDir, filepath.FromSlash(shardTestsRelPath))
+	if err := os.MkdirAll(filepath.Dir(hostPath), 0o755); err != nil {
+		return nil, 0, fmt.Errorf("create shard tests dir: %w", err)
+	}
+	if err := os.WriteFile(hostPath, []byte(content.String()), 0o644); err != nil {
+		return nil, 0, fmt.Errorf("write shard tests: %w", err)
+	}
+	envPath := hostPath
+	if strings.TrimSpace(containerWorkdir) != "" {
+		envPath = path.Join(containerWorkdir, shardTestsRelPath)
+	}
+	return map[string]string{"CIWI_SHARD_TESTS_FILE": envPath}, len(tests), nil
+}
@@ -0,0 +1,40 @@
+package agent
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/izzyreal/ciwi/internal/domain"
+	"github.com/izzyreal/ciwi/internal/protocol"
+)
+
+func TestWriteShardTestsFile(t *testing.T) {
+	execDir := t.TempDir()
+	job := protocol.JobExecution{Metadata: domain.ExecutionMetadata{
+		domain.ExecutionMetadataShardIndex:     "0",
+		domain.ExecutionMetadataShardTotal:     "2",
+		domain.ExecutionMetadataShardTestsJSON: `[{"package":"pkg/a","name":"TestSlow","duration_seconds":3},{"name":"test_b"}]`,
+	}}
+	env, count, err := writeShardTestsFile(execDir, "", job)
+	if err != nil || count != 2 {
+		t.Fatalf("write shard tests: count=%d err=%v", count, err)
+	}
+	hostPath := filepath.Join(execDir, ".ciwi", "shard-tests.txt")
+	if env["CIWI_SHARD_TESTS_FILE"] != hostPath {
+		t.Fatalf("unexpected env %v", env)
+	}
+	content, err := os.ReadFile(hostPath)
+	if err != nil || string(content) != "pkg/a\tTestSlow\n\ttest_b\n" {
+		t.Fatalf("unexpected shard tests file %q err=%v", content, err)
+	}
+
+	env, _, err = writeShardTestsFile(execDir, "/workspace", job)
+	if err != nil || env["CIWI_SHARD_TESTS_FILE"] != "/workspace/.ciwi/shard-tests.txt" {
+		t.Fatalf("expected the container path, got %v err=%v", env, err)
+	}
+
+	if env, _, err := writeShardTestsFile(execDir, "", protocol.JobExecution{}); env != nil || err != nil {
+		t.Fatalf("expected no env for an unsharded job, got %v err=%v", env, err)
+	}
+}
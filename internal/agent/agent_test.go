@@ -768,7 +768,7 @@ func TestRunCancelableCommandSendsInterruptBeforeKill(t *testing.T) {
 		cancel()
 	}()
 
-	err := runCancelableCommand(ctx, cmd)
+	err := runCancelableCommand(ctx, cmd, nil)
 	if err == nil {
 		t.Fatalf("expected cancellation error")
 	}
@@ -805,7 +805,7 @@ func TestRunCancelableCommandLetsShellCleanUpAfterStubbornChild(t *testing.T) {
 	}()
 
 	start := time.Now()
-	err := runCancelableCommand(ctx, cmd)
+	err := runCancelableCommand(ctx, cmd, nil)
 	if err == nil {
 		t.Fatalf("expected cancellation error")
 	}
@@ -817,6 +817,49 @@ func TestRunCancelableCommandLetsShellCleanUpAfterStubbornChild(t *testing.T) {
 	}
 }
 
+func TestRunCancelableCommandHardKillsAfterConfiguredGrace(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("signal semantics test is unix-specific")
+	}
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skipf("sh not available: %v", err)
+	}
+	t.Setenv("CIWI_AGENT_CANCEL_GRACE_SECONDS", "0")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	cmd := exec.Command("sh", "-c", `trap '' INT; while :; do :; done`)
+	prepareCommandForCancellation(cmd)
+
+	go func() {
+		time.Sleep(200 * time.Millisecond)
+		cancel()
+	}()
+
+	start := time.Now()
+	if err := runCancelableCommand(ctx, cmd, nil); err == nil {
+		t.Fatalf("expected cancellation error")
+	}
+	if elapsed := time.Since(start); elapsed > 1500*time.Millisecond {
+		t.Fatalf("expected hard kill without a grace period, took %s", elapsed)
+	}
+}
+
+func TestCommandCancelGracePeriodFromEnv(t *testing.T) {
+	for raw, want := range map[string]time.Duration{
+		"":    defaultCommandCancelGracePeriod,
+		"15":  15 * time.Second,
+		"0":   0,
+		"-1":  defaultCommandCancelGracePeriod,
+		"abc": defaultCommandCancelGracePeriod,
+	} {
+		t.Setenv("CIWI_AGENT_CANCEL_GRACE_SECONDS", raw)
+		if got := commandCancelGracePeriod(); got != want {
+			t.Fatalf("grace for %q = %s, want %s", raw, got, want)
+		}
+	}
+}
+
 func TestExecuteLeasedJobRunsPipelineStepsInSeparateShellProcesses(t *testing.T) {
 	if runtime.GOOS == "windows" {
 		t.Skip("posix shell assertion test skipped on windows")
@@ -1116,6 +1159,233 @@ func TestExecuteLeasedJobIncludesDryRunSkippedStepNoteInOutput(t *testing.T) {
 	}
 }
 
+func TestExecuteLeasedJobRunsFailureStepsAfterAFailedStep(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("posix shell assertion test skipped on windows")
+	}
+
+	var (
+		mu       sync.Mutex
+		statuses []protocol.JobExecutionStatusUpdateRequest
+	)
+	client := &http.Client{
+		Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+			if r.Method == http.MethodPost && r.URL.Path == "/api/v1/jobs/job-if/status" {
+				var req protocol.JobExecutionStatusUpdateRequest
+				if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+					t.Fatalf("decode status: %v", err)
+				}
+				mu.Lock()
+				statuses = append(statuses, req)
+				mu.Unlock()
+				return &http.Response{
+					StatusCode: http.StatusOK,
+					Body:       io.NopCloser(strings.NewReader(`{"ok":true}`)),
+					Header:     make(http.Header),
+				}, nil
+			}
+			return &http.Response{
+				StatusCode: http.StatusNotFound,
+				Body:       io.NopCloser(strings.NewReader("not found")),
+				Header:     make(http.Header),
+			}, nil
+		}),
+	}
+
+	job := protocol.JobExecution{
+		ID:             "job-if",
+		Script:         ":",
+		TimeoutSeconds: 30,
+		StepPlan: []protocol.JobStepPlanItem{
+			{Index: 1, Total: 5, Name: "build", Script: "echo building; exit 3"},
+			{Index: 2, Total: 5, Name: "publish", Script: "echo publishing"},
+			{Index: 3, Total: 5, Name: "notarize", Kind: "condition_skip", SkipReason: "condition not met: !ciwi.dry_run"},
+			{Index: 4, Total: 5, Name: "upload dumps", Script: "echo uploading dumps", RunWhen: protocol.JobStepRunWhenFailure, SkipReason: "condition not met: failure()"},
+			{Index: 5, Total: 5, Name: "cleanup", Script: "echo cleaning up", RunWhen: protocol.JobStepRunWhenAlways},
+		},
+		RequiredCapabilities: map[string]string{
+			"shell": shellPosix,
+		},
+	}
+	if err := executeLeasedJob(context.Background(), client, "http://example.local", "agent-1", t.TempDir(), nil, job); err != nil {
+		t.Fatalf("executeLeasedJob: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(statuses) == 0 {
+		t.Fatalf("expected status updates")
+	}
+	final := statuses[len(statuses)-1]
+	if final.Status != protocol.JobExecutionStatusFailed || !strings.Contains(final.Error, "build") {
+		t.Fatalf("expected job to fail on the build step, got status=%q error=%q", final.Status, final.Error)
+	}
+	output := reconstructedStatusOutput(t, statuses)
+	for _, want := range []string{"building", "uploading dumps", "cleaning up"} {
+		if !strings.Contains(output, want) {
+			t.Fatalf("expected %q in output, got:\n%s", want, output)
+		}
+	}
+	for _, unwanted := range []string{"publishing", "skipped step: notarize"} {
+		if strings.Contains(output, unwanted) {
+			t.Fatalf("did not expect %q in output, got:\n%s", unwanted, output)
+		}
+	}
+}
+
+func TestExecuteLeasedJobSkipsFailureStepsWhenEarlierStepsSucceed(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("posix shell assertion test skipped on windows")
+	}
+
+	var (
+		mu       sync.Mutex
+		statuses []protocol.JobExecutionStatusUpdateRequest
+	)
+	client := &http.Client{
+		Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+			if r.Method == http.MethodPost && r.URL.Path == "/api/v1/jobs/job-if-ok/status" {
+				var req protocol.JobExecutionStatusUpdateRequest
+				if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+					t.Fatalf("decode status: %v", err)
+				}
+				mu.Lock()
+				statuses = append(statuses, req)
+				mu.Unlock()
+				return &http.Response{
+					StatusCode: http.StatusOK,
+					Body:       io.NopCloser(strings.NewReader(`{"ok":true}`)),
+					Header:     make(http.Header),
+				}, nil
+			}
+			return &http.Response{
+				StatusCode: http.StatusNotFound,
+				Body:       io.NopCloser(strings.NewReader("not found")),
+				Header:     make(http.Header),
+			}, nil
+		}),
+	}
+
+	job := protocol.JobExecution{
+		ID:             "job-if-ok",
+		Script:         ":",
+		TimeoutSeconds: 30,
+		StepPlan: []protocol.JobStepPlanItem{
+			{Index: 1, Total: 2, Name: "build", Script: "echo building"},
+			{Index: 2, Total: 2, Name: "upload dumps", Script: "echo uploading dumps", RunWhen: protocol.JobStepRunWhenFailure, SkipReason: "condition not met: failure()"},
+		},
+		RequiredCapabilities: map[string]string{
+			"shell": shellPosix,
+		},
+	}
+	if err := executeLeasedJob(context.Background(), client, "http://example.local", "agent-1", t.TempDir(), nil, job); err != nil {
+		t.Fatalf("executeLeasedJob: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	final := statuses[len(statuses)-1]
+	if final.Status != protocol.JobExecutionStatusSucceeded {
+		t.Fatalf("expected succeeded, got %q (%s)", final.Status, final.Error)
+	}
+	output := reconstructedStatusOutput(t, statuses)
+	if strings.Contains(output, "uploading dumps") || !strings.Contains(output, "[if] skipped step: upload dumps") {
+		t.Fatalf("expected failure-only step to be skipped, got:\n%s", output)
+	}
+	var skipReason string
+	for _, update := range statuses {
+		for _, event := range update.Events {
+			if event.Type == protocol.JobExecutionEventTypeStepFinished && event.Step != nil && event.Step.Index == 2 {
+				skipReason = event.Step.SkipReason
+			}
+		}
+	}
+	if skipReason != "condition not met: failure()" {
+		t.Fatalf("expected skip reason on finished event, got %q", skipReason)
+	}
+}
+
+func TestExecuteLeasedJobRetriesFailedStepInPlace(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("posix shell assertion test skipped on windows")
+	}
+
+	var (
+		mu       sync.Mutex
+		statuses []protocol.JobExecutionStatusUpdateRequest
+	)
+	client := &http.Client{
+		Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+			if r.Method == http.MethodPost && r.URL.Path == "/api/v1/jobs/job-step-retry/status" {
+				var req protocol.JobExecutionStatusUpdateRequest
+				if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+					t.Fatalf("decode status: %v", err)
+				}
+				mu.Lock()
+				statuses = append(statuses, req)
+				mu.Unlock()
+				return &http.Response{
+					StatusCode: http.StatusOK,
+					Body:       io.NopCloser(strings.NewReader(`{"ok":true}`)),
+					Header:     make(http.Header),
+				}, nil
+			}
+			return &http.Response{
+				StatusCode: http.StatusNotFound,
+				Body:       io.NopCloser(strings.NewReader("not found")),
+				Header:     make(http.Header),
+			}, nil
+		}),
+	}
+
+	job := protocol.JobExecution{
+		ID:             "job-step-retry",
+		Script:         ":",
+		TimeoutSeconds: 30,
+		StepPlan: []protocol.JobStepPlanItem{
+			{
+				Index: 1, Total: 1, Name: "sign",
+				Script:           "if [ -f signed-once ]; then echo signed; else touch signed-once; echo network hiccup; exit 7; fi",
+				RetryMaxAttempts: 3,
+			},
+		},
+		RequiredCapabilities: map[string]string{
+			"shell": shellPosix,
+		},
+	}
+	if err := executeLeasedJob(context.Background(), client, "http://example.local", "agent-1", t.TempDir(), nil, job); err != nil {
+		t.Fatalf("executeLeasedJob: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	final := statuses[len(statuses)-1]
+	if final.Status != protocol.JobExecutionStatusSucceeded {
+		t.Fatalf("expected succeeded after retry, got %q (%s)", final.Status, final.Error)
+	}
+	output := reconstructedStatusOutput(t, statuses)
+	if !strings.Contains(output, "[retry] step failed: ") || !strings.Contains(output, "(exit=7); attempt 2 of 3") || !strings.Contains(output, "signed") {
+		t.Fatalf("expected retried step output, got:\n%s", output)
+	}
+	var finished []protocol.JobExecutionEvent
+	for _, update := range statuses {
+		for _, event := range update.Events {
+			if event.Type == protocol.JobExecutionEventTypeStepFinished && event.Step != nil {
+				finished = append(finished, event)
+			}
+		}
+	}
+	if len(finished) != 2 {
+		t.Fatalf("expected one finished event per attempt, got %d", len(finished))
+	}
+	if finished[0].Step.Attempt != 1 || finished[0].Error != "exit=7" {
+		t.Fatalf("unexpected first attempt event: %+v", finished[0])
+	}
+	if finished[1].Step.Attempt != 2 || finished[1].Error != "" {
+		t.Fatalf("unexpected second attempt event: %+v", finished[1])
+	}
+}
+
 func TestExecuteLeasedJobDisablesShellTraceForAdhoc(t *testing.T) {
 	if runtime.GOOS == "windows" {
 		t.Skip("posix shell assertion test skipped on windows")
@@ -12,36 +12,68 @@ import (
 	"github.com/izzyreal/ciwi/internal/protocol"
 )
 
-func uploadTestReport(ctx context.Context, client *http.Client, serverURL, agentID, jobID string, report protocol.JobExecutionTestReport) error {
+// uploadTestReport returns the report as the server stored it, which has the
+// failures of quarantined tests moved out of the failed counts.
+func uploadTestReport(ctx context.Context, client *http.Client, serverURL, agentID, jobID string, report protocol.JobExecutionTestReport) (protocol.JobExecutionTestReport, error) {
 	body, err := json.Marshal(protocol.UploadTestReportRequest{
 		AgentID: agentID,
 		Report:  report,
 	})
 	if err != nil {
-		return fmt.Errorf("marshal test report: %w", err)
+		return report, fmt.Errorf("marshal test report: %w", err)
 	}
 	req, err := http.NewRequestWithContext(ctx, http.MethodPost, serverURL+"/api/v1/jobs/"+jobID+"/tests", bytes.NewReader(body))
 	if err != nil {
-		return fmt.Errorf("create test report request: %w", err)
+		return report, fmt.Errorf("create test report request: %w", err)
 	}
 	req.Header.Set("Content-Type", "application/json")
 
 	resp, err := client.Do(req)
 	if err != nil {
-		return fmt.Errorf("send test report: %w", err)
+		return report, fmt.Errorf("send test report: %w", err)
 	}
 	defer resp.Body.Close()
 	if resp.StatusCode != http.StatusOK {
 		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 4*1024))
-		return fmt.Errorf("test report rejected: status=%d body=%s", resp.StatusCode, bytes.TrimSpace(respBody))
+		return report, fmt.Errorf("test report rejected: status=%d body=%s", resp.StatusCode, bytes.TrimSpace(respBody))
 	}
-	return nil
+	var stored protocol.JobExecutionTestReportResponse
+	if err := json.NewDecoder(resp.Body).Decode(&stored); err != nil || stored.Report.Total != report.Total {
+		return report, nil
+	}
+	return stored.Report, nil
+}
+
+func testReportFromSuites(suites []protocol.TestSuiteReport, coverage *protocol.CoverageReport) protocol.JobExecutionTestReport {
+	report := protocol.JobExecutionTestReport{Suites: suites, Coverage: coverage}
+	for _, s := range suites {
+		report.Total += s.Total
+		report.Passed += s.Passed
+		report.Failed += s.Failed
+		report.Skipped += s.Skipped
+	}
+	return report
+}
+
+// testFailuresQuarantined uploads the suites collected so far and reports
+// whether the server quarantined every failure of the last one. A test step
+// whose only failures are quarantined does not fail the job.
+func testFailuresQuarantined(ctx context.Context, client *http.Client, serverURL, agentID, jobID string, suites []protocol.TestSuiteReport, coverage *protocol.CoverageReport) (int, bool) {
+	if len(suites) == 0 || suites[len(suites)-1].Failed == 0 {
+		return 0, false
+	}
+	stored, err := uploadTestReport(ctx, client, serverURL, agentID, jobID, testReportFromSuites(suites, coverage))
+	if err != nil || len(stored.Suites) != len(suites) {
+		return 0, false
+	}
+	last := stored.Suites[len(stored.Suites)-1]
+	return last.Quarantined, last.Failed == 0 && last.Quarantined > 0
 }
 
 func testReportSummary(report protocol.JobExecutionTestReport) string {
 	if report.Total == 0 {
 		if report.Coverage != nil {
-			return fmt.Sprintf("[coverage] format=%s coverage=%.2f%%", report.Coverage.Format, report.Coverage.Percent)
+			return fmt.Sprintf("[coverage] format=%s coverage=%.2f%%", report.Coverage.Format, report.Coverage.Percent) + coverageGateSummary(report.Coverage)
 		}
 		return "[tests] none"
 	}
@@ -49,8 +81,37 @@ func testReportSummary(report protocol.JobExecutionTestReport) string {
 		" passed=" + strconv.Itoa(report.Passed) +
 		" failed=" + strconv.Itoa(report.Failed) +
 		" skipped=" + strconv.Itoa(report.Skipped)
+	if report.Quarantined > 0 {
+		line += " quarantined=" + strconv.Itoa(report.Quarantined)
+	}
 	if report.Coverage != nil {
-		line += fmt.Sprintf(" | coverage=%s %.2f%%", report.Coverage.Format, report.Coverage.Percent)
+		line += fmt.Sprintf(" | coverage=%s %.2f%%", report.Coverage.Format, report.Coverage.Percent) + coverageGateSummary(report.Coverage)
 	}
 	return line
 }
+
+func coverageGateSummary(coverage *protocol.CoverageReport) string {
+	switch {
+	case coverage.Gate == nil:
+		return ""
+	case coverage.Gate.Passed:
+		return " gate=passed"
+	default:
+		return " gate=failed"
+	}
+}
+
+// tallyTestSuite derives the suite counts from its cases.
+func tallyTestSuite(suite *protocol.TestSuiteReport) {
+	suite.Total, suite.Passed, suite.Failed, suite.Skipped = len(suite.Cases), 0, 0, 0
+	for _, testCase := range suite.Cases {
+		switch testCase.Status {
+		case "pass":
+			suite.Passed++
+		case "fail":
+			suite.Failed++
+		case "skip":
+			suite.Skipped++
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package agent
+
+import (
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/izzyreal/ciwi/internal/protocol"
+)
+
+type coberturaCoverage struct {
+	XMLName xml.Name         `xml:"coverage"`
+	Sources []string         `xml:"sources>source"`
+	Classes []coberturaClass `xml:"packages>package>classes>class"`
+}
+
+type coberturaClass struct {
+	Filename string          `xml:"filename,attr"`
+	Lines    []coberturaLine `xml:"lines>line"`
+}
+
+type coberturaLine struct {
+	Number int   `xml:"number,attr"`
+	Hits   int64 `xml:"hits,attr"`
+}
+
+// parseCoberturaCoverage reads Cobertura XML as written by coverage.py
+// ("coverage xml"), cobertura-maven-plugin and most other tools that
+// export it. Classes that share a source file, such as Java inner classes,
+// are merged into that file.
+func parseCoberturaCoverage(lines []string, execDir string) (*protocol.CoverageReport, error) {
+	var document coberturaCoverage
+	if err := xml.Unmarshal([]byte(strings.TrimSpace(strings.Join(lines, "\n"))), &document); err != nil {
+		return nil, fmt.Errorf("parse cobertura report: %w", err)
+	}
+	hits := map[string]map[int]int{}
+	for _, class := range document.Classes {
+		path := coberturaSourcePath(class.Filename, document.Sources, execDir)
+		if path == "" {
+			continue
+		}
+		if hits[path] == nil {
+			hits[path] = map[int]int{}
+		}
+		for _, line := range class.Lines {
+			if line.Number <= 0 {
+				continue
+			}
+			hits[path][line.Number] = maxInt(hits[path][line.Number], maxInt(0, int(line.Hits)))
+		}
+	}
+	return lineCoverageReport("cobertura", hits), nil
+}
+
+// coberturaSourcePath resolves a class filename, which is relative to one
+// of the report's <source> roots, to a path relative to the step's working
+// directory when the file is found there.
+func coberturaSourcePath(filename string, sources []string, execDir string) string {
+	filename = strings.TrimSpace(filename)
+	if filename == "" {
+		return ""
+	}
+	candidates := make([]string, 0, len(sources)+1)
+	for _, source := range sources {
+		if source = strings.TrimSpace(source); source != "" {
+			candidates = append(candidates, filepath.Join(filepath.FromSlash(source), filepath.FromSlash(filename)))
+		}
+	}
+	candidates = append(candidates, filepath.FromSlash(filename))
+	for _, candidate := range candidates {
+		if !filepath.IsAbs(candidate) {
+			candidate = filepath.Join(execDir, candidate)
+		}
+		relative, err := filepath.Rel(execDir, candidate)
+		if err != nil || relative == ".." || strings.HasPrefix(relative, ".."+string(filepath.Separator)) {
+			continue
+		}
+		if info, err := os.Stat(candidate); err == nil && !info.IsDir() {
+			return filepath.ToSlash(relative)
+		}
+	}
+	return filepath.ToSlash(filename)
+}
@@ -0,0 +1,68 @@
+package agent
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseCoberturaCoverage(t *testing.T) {
+	tmp := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(tmp, "src", "app"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(tmp, "src", "app", "models.py"), []byte("x = 1\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	report, err := parseCoberturaCoverage(strings.Split(`<?xml version="1.0" ?>
+<coverage version="7.4.0" line-rate="0.6" branch-rate="0">
+	<sources>
+		<source>`+filepath.Join(tmp, "src")+`</source>
+	</sources>
+	<packages>
+		<package name="app" line-rate="0.6">
+			<classes>
+				<class name="models.py" filename="app/models.py" line-rate="0.5">
+					<methods/>
+					<lines>
+						<line number="1" hits="1"/>
+						<line number="2" hits="0"/>
+						<line number="3" hits="0"/>
+						<line number="5" hits="2"/>
+					</lines>
+				</class>
+				<class name="Outer$Inner" filename="app/models.py">
+					<lines>
+						<line number="3" hits="4"/>
+					</lines>
+				</class>
+				<class name="views.py" filename="app/views.py">
+					<lines>
+						<line number="1" hits="0"/>
+					</lines>
+				</class>
+			</classes>
+		</package>
+	</packages>
+</coverage>`, "\n"), tmp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Format != "cobertura" || report.TotalLines != 5 || report.CoveredLines != 3 || len(report.Files) != 2 {
+		t.Fatalf("report = %+v", report)
+	}
+	models := report.Files[1]
+	if models.Path != "src/app/models.py" || models.TotalLines != 4 || models.CoveredLines != 3 || models.Lines[2].Hits != 4 {
+		t.Fatalf("models = %+v", models)
+	}
+	// Files that cannot be found under the working directory keep the
+	// reported filename.
+	if report.Files[0].Path != "app/views.py" {
+		t.Fatalf("views path = %q", report.Files[0].Path)
+	}
+
+	if _, err := parseCoberturaCoverage([]string{"<report/>"}, tmp); err == nil {
+		t.Fatal("expected a JaCoCo document to be rejected")
+	}
+}
@@ -1,6 +1,8 @@
 package agent
 
 import (
+	"bytes"
+	"encoding/xml"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -16,21 +18,16 @@ func parseStepCoverageFromFile(execDir string, meta stepMarkerMeta) (*protocol.C
 	if path == "" {
 		return nil, nil
 	}
-	format := strings.TrimSpace(meta.coverageFormat)
-	if format == "" {
-		switch strings.ToLower(filepath.Ext(path)) {
-		case ".info", ".lcov":
-			format = "lcov"
-		default:
-			format = "go-coverprofile"
-		}
-	}
 
 	full := filepath.Join(execDir, filepath.FromSlash(path))
 	raw, err := os.ReadFile(full)
 	if err != nil {
 		return nil, fmt.Errorf("read coverage report %q: %w", path, err)
 	}
+	format := strings.TrimSpace(meta.coverageFormat)
+	if format == "" {
+		format = inferCoverageFormat(path, raw)
+	}
 	lines := strings.Split(strings.ReplaceAll(string(raw), "\r\n", "\n"), "\n")
 
 	switch format {
@@ -38,15 +35,86 @@ func parseStepCoverageFromFile(execDir string, meta stepMarkerMeta) (*protocol.C
 		return parseGoCoverprofileCoverage(lines)
 	case "lcov":
 		return parseLCOVCoverage(lines)
+	case "cobertura":
+		return parseCoberturaCoverage(lines, execDir)
+	case "jacoco":
+		return parseJaCoCoCoverage(lines)
 	default:
 		return nil, fmt.Errorf("unsupported coverage format %q", format)
 	}
 }
 
+// inferCoverageFormat picks the format of a report without coverage_format
+// from its extension and, for XML reports, from the root element.
+func inferCoverageFormat(path string, raw []byte) string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".info", ".lcov":
+		return "lcov"
+	case ".xml":
+		decoder := xml.NewDecoder(bytes.NewReader(raw))
+		for {
+			token, err := decoder.Token()
+			if err != nil {
+				return "cobertura"
+			}
+			if start, ok := token.(xml.StartElement); ok {
+				if start.Name.Local == "report" {
+					return "jacoco"
+				}
+				return "cobertura"
+			}
+		}
+	default:
+		return "go-coverprofile"
+	}
+}
+
+// lineCoverageReport builds a report of per-line hit counts keyed by file
+// path, for formats whose totals are the instrumented lines.
+func lineCoverageReport(format string, hits map[string]map[int]int) *protocol.CoverageReport {
+	report := &protocol.CoverageReport{Format: format}
+	paths := make([]string, 0, len(hits))
+	for path := range hits {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		f := protocol.CoverageFileReport{Path: path, Lines: coverageLines(hits[path])}
+		for _, line := range f.Lines {
+			if line.Hits > 0 {
+				f.CoveredLines++
+			}
+		}
+		f.TotalLines = len(f.Lines)
+		if f.TotalLines > 0 {
+			f.Percent = 100.0 * float64(f.CoveredLines) / float64(f.TotalLines)
+		}
+		report.Files = append(report.Files, f)
+		report.TotalLines += f.TotalLines
+		report.CoveredLines += f.CoveredLines
+	}
+	if report.TotalLines > 0 {
+		report.Percent = 100.0 * float64(report.CoveredLines) / float64(report.TotalLines)
+	}
+	return report
+}
+
+func coverageLines(hits map[int]int) []protocol.CoverageLine {
+	if len(hits) == 0 {
+		return nil
+	}
+	lines := make([]protocol.CoverageLine, 0, len(hits))
+	for line, count := range hits {
+		lines = append(lines, protocol.CoverageLine{Line: line, Hits: count})
+	}
+	sort.Slice(lines, func(i, j int) bool { return lines[i].Line < lines[j].Line })
+	return lines
+}
+
 func parseGoCoverprofileCoverage(lines []string) (*protocol.CoverageReport, error) {
 	type blockStat struct {
 		numStmts int
-		covered  bool
+		hits     int
 	}
 	type fileStat struct {
 		total   int
@@ -92,7 +160,7 @@ func parseGoCoverprofileCoverage(lines []string) (*protocol.CoverageReport, erro
 			return nil, fmt.Errorf("inconsistent statement count line %d", i+1)
 		}
 		block.numStmts = numStmts
-		block.covered = block.covered || count > 0
+		block.hits = maxInt(block.hits, int(count))
 		pathBlocks[blockRange] = block
 	}
 
@@ -107,16 +175,27 @@ func parseGoCoverprofileCoverage(lines []string) (*protocol.CoverageReport, erro
 	sort.Strings(paths)
 	for _, path := range paths {
 		st := fileStat{}
-		for _, block := range blocks[path] {
+		lineHits := map[int]int{}
+		for blockRange, block := range blocks[path] {
 			st.total += block.numStmts
-			if block.covered {
+			if block.hits > 0 {
 				st.covered += block.numStmts
 			}
+			if block.numStmts == 0 {
+				continue
+			}
+			// A line shared by several blocks counts as covered when any
+			// of them ran.
+			start, end := coverprofileBlockLines(blockRange)
+			for line := start; line > 0 && line <= end; line++ {
+				lineHits[line] = maxInt(lineHits[line], block.hits)
+			}
 		}
 		f := protocol.CoverageFileReport{
 			Path:              path,
 			TotalStatements:   st.total,
 			CoveredStatements: st.covered,
+			Lines:             coverageLines(lineHits),
 		}
 		if st.total > 0 {
 			f.Percent = 100.0 * float64(st.covered) / float64(st.total)
@@ -131,6 +210,18 @@ func parseGoCoverprofileCoverage(lines []string) (*protocol.CoverageReport, erro
 	return report, nil
 }
 
+// coverprofileBlockLines returns the first and last line of a
+// "startLine.startCol,endLine.endCol" block.
+func coverprofileBlockLines(blockRange string) (int, int) {
+	start, end, ok := strings.Cut(blockRange, ",")
+	if !ok {
+		return 0, 0
+	}
+	startLine, _, _ := strings.Cut(start, ".")
+	endLine, _, _ := strings.Cut(end, ".")
+	return parseIntDefault(startLine, 0), parseIntDefault(endLine, 0)
+}
+
 func excludedGoCoveragePath(path string) bool {
 	// Generated protobuf implementations are exercised as part of the CNP
 	// vertical slices, but their generated branches are not an actionable
@@ -147,6 +238,7 @@ func parseLCOVCoverage(lines []string) (*protocol.CoverageReport, error) {
 		daTotal, daHits int
 	}
 	stats := map[string]fileStat{}
+	lineHits := map[string]map[int]int{}
 	current := ""
 
 	for i, raw := range lines {
@@ -201,6 +293,12 @@ func parseLCOVCoverage(lines []string) (*protocol.CoverageReport, error) {
 			if err != nil {
 				return nil, fmt.Errorf("invalid lcov DA hits line %d: %w", i+1, err)
 			}
+			if number, err := strconv.Atoi(strings.TrimSpace(parts[0])); err == nil && number > 0 {
+				if lineHits[current] == nil {
+					lineHits[current] = map[int]int{}
+				}
+				lineHits[current][number] += hits
+			}
 			st := stats[current]
 			st.daTotal++
 			if hits > 0 {
@@ -233,6 +331,7 @@ func parseLCOVCoverage(lines []string) (*protocol.CoverageReport, error) {
 			Path:         path,
 			TotalLines:   total,
 			CoveredLines: covered,
+			Lines:        coverageLines(lineHits[path]),
 		}
 		if total > 0 {
 			f.Percent = 100.0 * float64(covered) / float64(total)
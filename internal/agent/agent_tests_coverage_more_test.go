@@ -39,6 +39,20 @@ func TestParseStepCoverageFromFileAndErrors(t *testing.T) {
 		t.Fatalf("unexpected lcov coverage result: %+v", got)
 	}
 
+	jacocoRel := "reports/jacoco.xml"
+	jacocoXML := `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<!DOCTYPE report PUBLIC "-//JACOCO//DTD Report 1.1//EN" "report.dtd"><report name="app"><package name="com/example"><sourcefile name="A.java"><line nr="3" mi="0" ci="2" mb="0" cb="0"/></sourcefile></package></report>`
+	if err := os.WriteFile(filepath.Join(tmp, filepath.FromSlash(jacocoRel)), []byte(jacocoXML), 0o644); err != nil {
+		t.Fatalf("write jacoco file: %v", err)
+	}
+	got, err = parseStepCoverageFromFile(tmp, stepMarkerMeta{coverageReport: jacocoRel})
+	if err != nil {
+		t.Fatalf("parseStepCoverageFromFile jacoco: %v", err)
+	}
+	if got == nil || got.Format != "jacoco" || got.TotalLines != 1 || got.CoveredLines != 1 {
+		t.Fatalf("unexpected inferred jacoco coverage result: %+v", got)
+	}
+
 	got, err = parseStepCoverageFromFile(tmp, stepMarkerMeta{})
 	if err != nil {
 		t.Fatalf("empty coverage marker should not error: %v", err)
@@ -21,6 +21,25 @@ func TestParseGoCoverprofileCoverage(t *testing.T) {
 	if len(report.Files) != 2 {
 		t.Fatalf("expected 2 files, got %d", len(report.Files))
 	}
+	if lines := report.Files[0].Lines; len(lines) != 4 || lines[1].Hits != 1 || lines[2].Line != 3 || lines[2].Hits != 0 {
+		t.Fatalf("unexpected line hits: %+v", lines)
+	}
+}
+
+func TestParseGoCoverprofileCoverageCountsSharedLinesAsCovered(t *testing.T) {
+	report, err := parseGoCoverprofileCoverage([]string{
+		"mode: count",
+		"pkg/a.go:1.10,3.2 2 0",
+		"pkg/a.go:3.2,5.2 1 4",
+		"pkg/a.go:7.1,7.9 0 0",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := report.Files[0].Lines
+	if len(lines) != 5 || lines[0].Hits != 0 || lines[2].Hits != 4 || lines[4].Line != 5 {
+		t.Fatalf("line hits = %+v", lines)
+	}
 }
 
 func TestParseGoCoverprofileCoverageExcludesGeneratedProtobuf(t *testing.T) {
@@ -82,4 +101,7 @@ func TestParseLCOVCoverage(t *testing.T) {
 	if len(report.Files) != 2 {
 		t.Fatalf("expected 2 files, got %d", len(report.Files))
 	}
+	if lines := report.Files[1].Lines; len(lines) != 2 || lines[0].Hits != 3 || lines[1].Line != 2 {
+		t.Fatalf("unexpected line hits: %+v", lines)
+	}
 }
@@ -0,0 +1,75 @@
+package agent
+
+import (
+	"encoding/xml"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/izzyreal/ciwi/internal/protocol"
+)
+
+type jacocoReport struct {
+	XMLName xml.Name `xml:"report"`
+	jacocoGroup
+}
+
+type jacocoGroup struct {
+	Groups   []jacocoGroup   `xml:"group"`
+	Packages []jacocoPackage `xml:"package"`
+}
+
+type jacocoPackage struct {
+	Name        string             `xml:"name,attr"`
+	SourceFiles []jacocoSourceFile `xml:"sourcefile"`
+}
+
+type jacocoSourceFile struct {
+	Name  string       `xml:"name,attr"`
+	Lines []jacocoLine `xml:"line"`
+}
+
+type jacocoLine struct {
+	Number  int `xml:"nr,attr"`
+	Missed  int `xml:"mi,attr"`
+	Covered int `xml:"ci,attr"`
+}
+
+// parseJaCoCoCoverage reads the JaCoCo XML report, including the groups of
+// multi-module reports. JaCoCo records covered instructions rather than
+// execution counts, so a line's hits are its covered instruction count and
+// partly covered lines count as covered, as in JaCoCo's own line counter.
+// Paths are the package directory plus the source file name.
+func parseJaCoCoCoverage(lines []string) (*protocol.CoverageReport, error) {
+	var report jacocoReport
+	if err := xml.Unmarshal([]byte(strings.TrimSpace(strings.Join(lines, "\n"))), &report); err != nil {
+		return nil, fmt.Errorf("parse jacoco report: %w", err)
+	}
+	hits := map[string]map[int]int{}
+	var collect func(group jacocoGroup)
+	collect = func(group jacocoGroup) {
+		for _, child := range group.Groups {
+			collect(child)
+		}
+		for _, pkg := range group.Packages {
+			for _, source := range pkg.SourceFiles {
+				name := strings.TrimSpace(source.Name)
+				if name == "" {
+					continue
+				}
+				filePath := path.Join(strings.TrimSpace(pkg.Name), name)
+				if hits[filePath] == nil {
+					hits[filePath] = map[int]int{}
+				}
+				for _, line := range source.Lines {
+					if line.Number <= 0 || line.Missed+line.Covered <= 0 {
+						continue
+					}
+					hits[filePath][line.Number] += maxInt(0, line.Covered)
+				}
+			}
+		}
+	}
+	collect(report.jacocoGroup)
+	return lineCoverageReport("jacoco", hits), nil
+}
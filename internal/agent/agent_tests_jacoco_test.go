@@ -0,0 +1,45 @@
+package agent
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseJaCoCoCoverage(t *testing.T) {
+	report, err := parseJaCoCoCoverage(strings.Split(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<!DOCTYPE report PUBLIC "-//JACOCO//DTD Report 1.1//EN" "report.dtd">
+<report name="shop">
+	<sessioninfo id="host-1" start="1" dump="2"/>
+	<group name="core">
+		<package name="com/example/core">
+			<class name="com/example/core/Cart" sourcefilename="Cart.java"/>
+			<sourcefile name="Cart.java">
+				<line nr="3" mi="0" ci="3" mb="0" cb="0"/>
+				<line nr="4" mi="2" ci="1" mb="1" cb="1"/>
+				<line nr="6" mi="4" ci="0" mb="0" cb="0"/>
+				<counter type="LINE" missed="1" covered="2"/>
+			</sourcefile>
+		</package>
+	</group>
+	<package name="com/example">
+		<sourcefile name="App.java">
+			<line nr="10" mi="5" ci="0" mb="0" cb="0"/>
+		</sourcefile>
+	</package>
+	<counter type="LINE" missed="2" covered="2"/>
+</report>`, "\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Format != "jacoco" || report.TotalLines != 4 || report.CoveredLines != 2 || len(report.Files) != 2 {
+		t.Fatalf("report = %+v", report)
+	}
+	cart := report.Files[1]
+	if cart.Path != "com/example/core/Cart.java" || len(cart.Lines) != 3 || cart.Lines[1].Hits != 1 || cart.Lines[2].Hits != 0 {
+		t.Fatalf("cart = %+v", cart)
+	}
+
+	if _, err := parseJaCoCoCoverage([]string{"<coverage/>"}); err == nil {
+		t.Fatal("expected a Cobertura document to be rejected")
+	}
+}
@@ -0,0 +1,97 @@
+package agent
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/izzyreal/ciwi/internal/protocol"
+)
+
+type libtestEvent struct {
+	Type     string  `json:"type"`
+	Event    string  `json:"event"`
+	Name     string  `json:"name"`
+	ExecTime float64 `json:"exec_time"`
+	Stdout   string  `json:"stdout"`
+	Message  string  `json:"message"`
+	Reason   string  `json:"reason"`
+}
+
+// parseLibtestJSONSuite reads the JSON lines of the Rust test harness
+// ("cargo test -- --format json") and of cargo-nextest's libtest-json output.
+// nextest prefixes test names with their binary id and a '$', which becomes
+// the package of the case; plain libtest cases are grouped by module path.
+func parseLibtestJSONSuite(name string, lines []string) protocol.TestSuiteReport {
+	suite := protocol.TestSuiteReport{Name: name, Format: "libtest-json"}
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		if !strings.HasPrefix(line, "{") {
+			continue
+		}
+		var event libtestEvent
+		if err := json.Unmarshal([]byte(line), &event); err != nil || event.Type != "test" {
+			continue
+		}
+		status := ""
+		switch event.Event {
+		case "ok":
+			status = "pass"
+		case "failed", "timeout":
+			status = "fail"
+		case "ignored":
+			status = "skip"
+		default:
+			continue
+		}
+		pkg, testName := libtestCaseName(event.Name)
+		output := strings.TrimSpace(event.Stdout)
+		for _, detail := range []string{event.Message, event.Reason} {
+			if detail = strings.TrimSpace(detail); detail != "" {
+				output = strings.TrimSpace(output + "\n" + detail)
+			}
+		}
+		if event.Event == "timeout" {
+			output = strings.TrimSpace(output + "\ntimed out")
+		}
+		testCase := protocol.TestCase{
+			Package: pkg, Name: testName, Status: status, DurationSeconds: event.ExecTime, Output: output,
+		}
+		if file, line, ok := parseRustPanicLocation(output); ok {
+			testCase.File, testCase.Line = file, line
+		}
+		suite.Cases = append(suite.Cases, testCase)
+	}
+	tallyTestSuite(&suite)
+	return suite
+}
+
+func libtestCaseName(raw string) (string, string) {
+	raw = strings.TrimSpace(raw)
+	if binary, test, ok := strings.Cut(raw, "$"); ok {
+		return strings.TrimSpace(binary), strings.TrimSpace(test)
+	}
+	if index := strings.LastIndex(raw, "::"); index > 0 {
+		return raw[:index], raw[index+2:]
+	}
+	return "", raw
+}
+
+// parseRustPanicLocation finds the source location of a panic message such as
+// "thread 'tests::a' panicked at src/lib.rs:12:9:".
+func parseRustPanicLocation(output string) (string, int, bool) {
+	_, rest, ok := strings.Cut(output, "panicked at ")
+	if !ok {
+		return "", 0, false
+	}
+	rest, _, _ = strings.Cut(rest, "\n")
+	parts := strings.Split(strings.TrimSuffix(strings.TrimSpace(rest), ":"), ":")
+	if len(parts) < 3 {
+		return "", 0, false
+	}
+	file := normalizeTestSourcePath(strings.Join(parts[:len(parts)-2], ":"))
+	line := parseIntDefault(parts[len(parts)-2], 0)
+	if file == "" || line <= 0 {
+		return "", 0, false
+	}
+	return file, line, true
+}
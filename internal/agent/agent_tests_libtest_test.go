@@ -0,0 +1,32 @@
+package agent
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseLibtestJSONSuiteHandlesCargoAndNextestNames(t *testing.T) {
+	lines := strings.Split(`{ "type": "suite", "event": "started", "test_count": 4 }
+{ "type": "test", "event": "started", "name": "parser::tests::parses" }
+{ "type": "test", "name": "parser::tests::parses", "event": "ok", "exec_time": 0.25 }
+{ "type": "test", "name": "parser::tests::rejects", "event": "failed", "stdout": "thread 'parser::tests::rejects' panicked at src/parser.rs:41:9:\nassertion failed" }
+{ "type": "test", "name": "my-crate::integration$slow_path", "event": "ignored", "message": "needs network" }
+{ "type": "test", "name": "top_level", "event": "timeout" }
+{ "type": "suite", "event": "failed", "passed": 1, "failed": 1, "ignored": 1 }`, "\n")
+	suite := parseLibtestJSONSuite("rust", lines)
+	if suite.Format != "libtest-json" || suite.Total != 4 || suite.Passed != 1 || suite.Failed != 2 || suite.Skipped != 1 {
+		t.Fatalf("unexpected counts: %+v", suite)
+	}
+	if got := suite.Cases[0]; got.Package != "parser::tests" || got.Name != "parses" || got.DurationSeconds != 0.25 {
+		t.Fatalf("unexpected cargo case: %+v", got)
+	}
+	if got := suite.Cases[1]; got.File != "src/parser.rs" || got.Line != 41 || !strings.Contains(got.Output, "assertion failed") {
+		t.Fatalf("unexpected panic location: %+v", got)
+	}
+	if got := suite.Cases[2]; got.Package != "my-crate::integration" || got.Name != "slow_path" || got.Output != "needs network" {
+		t.Fatalf("unexpected nextest case: %+v", got)
+	}
+	if got := suite.Cases[3]; got.Package != "" || got.Status != "fail" || got.Output != "timed out" {
+		t.Fatalf("unexpected timeout case: %+v", got)
+	}
+}
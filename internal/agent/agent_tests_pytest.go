@@ -0,0 +1,134 @@
+package agent
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/izzyreal/ciwi/internal/protocol"
+)
+
+type pytestReportLogEntry struct {
+	ReportType string          `json:"$report_type"`
+	NodeID     string          `json:"nodeid"`
+	Location   []any           `json:"location"`
+	Outcome    string          `json:"outcome"`
+	When       string          `json:"when"`
+	Duration   float64         `json:"duration"`
+	LongRepr   json.RawMessage `json:"longrepr"`
+	Sections   [][2]string     `json:"sections"`
+}
+
+// parsePytestReportLogSuite reads the JSON lines written by pytest-reportlog
+// ("pytest --report-log"). The setup, call and teardown reports of a test are
+// folded into one case, which fails when any phase failed and is skipped when
+// setup skipped it. Collection errors become failing cases of their module.
+func parsePytestReportLogSuite(name string, lines []string) protocol.TestSuiteReport {
+	suite := protocol.TestSuiteReport{Name: name, Format: "pytest-reportlog"}
+	index := map[string]int{}
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		if !strings.HasPrefix(line, "{") {
+			continue
+		}
+		var entry pytestReportLogEntry
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			continue
+		}
+		switch entry.ReportType {
+		case "TestReport":
+		case "CollectReport":
+			if entry.Outcome != "failed" {
+				continue
+			}
+			entry.When = "collect"
+		default:
+			continue
+		}
+		position, seen := index[entry.NodeID]
+		if !seen {
+			pkg, testName := pytestCaseName(entry.NodeID)
+			testCase := protocol.TestCase{Package: pkg, Name: testName, Status: "pass"}
+			if len(entry.Location) >= 2 {
+				file, _ := entry.Location[0].(string)
+				line, _ := entry.Location[1].(float64)
+				testCase.File = normalizeTestSourcePath(file)
+				// pytest reports zero-based line numbers.
+				testCase.Line = maxInt(0, int(line)+1)
+			}
+			suite.Cases = append(suite.Cases, testCase)
+			position = len(suite.Cases) - 1
+			index[entry.NodeID] = position
+		}
+		testCase := &suite.Cases[position]
+		testCase.DurationSeconds += entry.Duration
+		switch entry.Outcome {
+		case "failed":
+			testCase.Status = "fail"
+		case "skipped":
+			if testCase.Status != "fail" {
+				testCase.Status = "skip"
+			}
+		}
+		if entry.Outcome != "passed" {
+			if detail := pytestLongRepr(entry.LongRepr); detail != "" {
+				testCase.Output = strings.TrimSpace(testCase.Output + "\n" + entry.When + ": " + detail)
+			}
+		}
+		if entry.When == "teardown" || entry.Outcome == "failed" {
+			for _, section := range entry.Sections {
+				if body := strings.TrimSpace(section[1]); body != "" && !strings.Contains(testCase.Output, body) {
+					testCase.Output = strings.TrimSpace(testCase.Output + "\n" + section[0] + "\n" + body)
+				}
+			}
+		}
+	}
+	tallyTestSuite(&suite)
+	return suite
+}
+
+func pytestCaseName(nodeID string) (string, string) {
+	if module, test, ok := strings.Cut(strings.TrimSpace(nodeID), "::"); ok {
+		return module, test
+	}
+	return strings.TrimSpace(nodeID), "Collection"
+}
+
+// pytestLongRepr returns the failure representation, which pytest writes as
+// a string for skips and simple failures and as a structured object otherwise.
+func pytestLongRepr(raw json.RawMessage) string {
+	if len(raw) == 0 || string(raw) == "null" {
+		return ""
+	}
+	var text string
+	if err := json.Unmarshal(raw, &text); err == nil {
+		return strings.TrimSpace(text)
+	}
+	var skip []any
+	if err := json.Unmarshal(raw, &skip); err == nil && len(skip) == 3 {
+		reason, _ := skip[2].(string)
+		return strings.TrimSpace(reason)
+	}
+	var structured struct {
+		ReprCrash struct {
+			Message string `json:"message"`
+		} `json:"reprcrash"`
+		ReprTraceback struct {
+			ReprEntries []struct {
+				Data struct {
+					Lines []string `json:"lines"`
+				} `json:"data"`
+			} `json:"reprentries"`
+		} `json:"reprtraceback"`
+	}
+	if err := json.Unmarshal(raw, &structured); err != nil {
+		return ""
+	}
+	lines := make([]string, 0)
+	for _, entry := range structured.ReprTraceback.ReprEntries {
+		lines = append(lines, entry.Data.Lines...)
+	}
+	if len(lines) == 0 {
+		return strings.TrimSpace(structured.ReprCrash.Message)
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
@@ -0,0 +1,33 @@
+package agent
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParsePytestReportLogSuiteFoldsPhases(t *testing.T) {
+	lines := strings.Split(`{"pytest_version": "8.3.0", "$report_type": "SessionStart"}
+{"nodeid": "tests/test_math.py::test_add", "location": ["tests/test_math.py", 3, "test_add"], "outcome": "passed", "when": "setup", "duration": 0.5, "longrepr": null, "sections": [], "$report_type": "TestReport"}
+{"nodeid": "tests/test_math.py::test_add", "location": ["tests/test_math.py", 3, "test_add"], "outcome": "passed", "when": "call", "duration": 0.25, "longrepr": null, "sections": [], "$report_type": "TestReport"}
+{"nodeid": "tests/test_math.py::TestDiv::test_zero[1]", "location": ["tests/test_math.py", 11, "TestDiv.test_zero[1]"], "outcome": "failed", "when": "call", "duration": 0.1, "longrepr": {"reprcrash": {"path": "tests/test_math.py", "lineno": 13, "message": "ZeroDivisionError"}, "reprtraceback": {"reprentries": [{"data": {"lines": ["    def test_zero(n):", ">       1 / 0", "E       ZeroDivisionError"]}}]}}, "sections": [["Captured stdout call", "dividing"]], "$report_type": "TestReport"}
+{"nodeid": "tests/test_math.py::test_slow", "location": ["tests/test_math.py", 20, "test_slow"], "outcome": "skipped", "when": "setup", "duration": 0, "longrepr": ["tests/test_math.py", 21, "Skipped: slow"], "sections": [], "$report_type": "TestReport"}
+{"nodeid": "tests/test_broken.py", "outcome": "failed", "longrepr": "ImportError while importing test module", "result": null, "sections": [], "$report_type": "CollectReport"}
+{"nodeid": "tests/test_math.py", "outcome": "passed", "longrepr": null, "result": [], "sections": [], "$report_type": "CollectReport"}
+{"exitstatus": 1, "$report_type": "SessionFinish"}`, "\n")
+	suite := parsePytestReportLogSuite("python", lines)
+	if suite.Format != "pytest-reportlog" || suite.Total != 4 || suite.Passed != 1 || suite.Failed != 2 || suite.Skipped != 1 {
+		t.Fatalf("unexpected counts: %+v", suite)
+	}
+	if got := suite.Cases[0]; got.Package != "tests/test_math.py" || got.Name != "test_add" || got.Line != 4 || got.DurationSeconds != 0.75 {
+		t.Fatalf("unexpected passed case: %+v", got)
+	}
+	if got := suite.Cases[1]; got.Name != "TestDiv::test_zero[1]" || !strings.Contains(got.Output, "E       ZeroDivisionError") || !strings.Contains(got.Output, "dividing") {
+		t.Fatalf("unexpected failed case: %+v", got)
+	}
+	if got := suite.Cases[2]; got.Status != "skip" || got.Output != "setup: Skipped: slow" {
+		t.Fatalf("unexpected skipped case: %+v", got)
+	}
+	if got := suite.Cases[3]; got.Package != "tests/test_broken.py" || got.Name != "Collection" || got.Status != "fail" {
+		t.Fatalf("unexpected collection error: %+v", got)
+	}
+}
@@ -0,0 +1,155 @@
+package agent
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/izzyreal/ciwi/internal/protocol"
+)
+
+var (
+	tapTestPointRE = regexp.MustCompile(`^(not ok|ok)\b\s*(\d+)?\s*(?:-\s*)?(.*)$`)
+	tapDirectiveRE = regexp.MustCompile(`(?i)(?:^|\s)#\s*(SKIP|TODO)\S*(?:\s+(.*))?$`)
+	tapDurationRE  = regexp.MustCompile(`(?m)^\s*duration_ms:\s*([0-9.]+)`)
+	tapLocationRE  = regexp.MustCompile(`(?m)^\s*(?:location|at):\s*['"]?(?:file://)?(.+?):(\d+)(?::\d+)?['"]?\s*$`)
+)
+
+type tapSubtest struct {
+	name           string
+	parent         *tapSubtest
+	children       bool
+	failedChildren bool
+}
+
+func (s *tapSubtest) path() string {
+	if s == nil {
+		return ""
+	}
+	parent := s.parent.path()
+	if parent == "" {
+		return s.name
+	}
+	if s.name == "" {
+		return parent
+	}
+	return parent + " / " + s.name
+}
+
+// parseTAPSuite reads TAP 13 and 14 output. Test points of subtests become
+// cases whose package is the path of the enclosing subtests, named by their
+// "# Subtest:" comment or else by the test point that closes them. That
+// closing test point only becomes a case itself when it failed without a
+// failing child, so plan errors are not lost.
+func parseTAPSuite(name string, lines []string) protocol.TestSuiteReport {
+	suite := protocol.TestSuiteReport{Name: name, Format: "tap"}
+	var cases []protocol.TestCase
+	var scopes []*tapSubtest
+	open := map[int]*tapSubtest{}
+	last := -1
+	var yaml strings.Builder
+	inYAML, yamlIndent := false, 0
+
+	subtestAt := func(depth int) *tapSubtest {
+		if depth == 0 {
+			return nil
+		}
+		if open[depth] == nil {
+			open[depth] = &tapSubtest{parent: open[depth-1]}
+		}
+		return open[depth]
+	}
+	flushYAML := func() {
+		if last >= 0 && yaml.Len() > 0 {
+			testCase := &cases[last]
+			diagnostics := yaml.String()
+			testCase.Output = strings.TrimSpace(testCase.Output + "\n" + diagnostics)
+			if match := tapDurationRE.FindStringSubmatch(diagnostics); match != nil {
+				testCase.DurationSeconds = parseFloatDefault(match[1], 0) / 1000
+			}
+			if match := tapLocationRE.FindStringSubmatch(diagnostics); match != nil {
+				testCase.File = normalizeTestSourcePath(match[1])
+				testCase.Line = maxInt(0, parseIntDefault(match[2], 0))
+			}
+		}
+		yaml.Reset()
+		inYAML = false
+	}
+
+	for _, raw := range lines {
+		raw = strings.TrimRight(raw, " \t\r")
+		line := strings.TrimSpace(raw)
+		indent := len(raw) - len(strings.TrimLeft(raw, " \t"))
+		if inYAML {
+			if line == "..." {
+				flushYAML()
+			} else {
+				yaml.WriteString(strings.TrimPrefix(raw, strings.Repeat(" ", yamlIndent)))
+				yaml.WriteString("\n")
+			}
+			continue
+		}
+		depth := indent / 4
+		switch {
+		case line == "":
+			continue
+		case line == "---":
+			inYAML, yamlIndent = true, indent
+			continue
+		case strings.HasPrefix(line, "# Subtest:"):
+			open[depth+1] = &tapSubtest{name: strings.TrimSpace(strings.TrimPrefix(line, "# Subtest:")), parent: subtestAt(depth)}
+			continue
+		case strings.HasPrefix(strings.ToLower(line), "bail out!"):
+			cases = append(cases, protocol.TestCase{Name: "Bail out", Status: "fail", Output: strings.TrimSpace(line[len("bail out!"):])})
+			scopes = append(scopes, subtestAt(depth))
+			last = -1
+			continue
+		}
+		match := tapTestPointRE.FindStringSubmatch(line)
+		if match == nil {
+			if last >= 0 && cases[last].Status == "fail" && strings.HasPrefix(line, "#") {
+				cases[last].Output = strings.TrimSpace(cases[last].Output + "\n" + strings.TrimSpace(strings.TrimPrefix(line, "#")))
+			}
+			continue
+		}
+		description := strings.TrimSpace(match[3])
+		status := "pass"
+		if match[1] == "not ok" {
+			status = "fail"
+		}
+		if directive := tapDirectiveRE.FindStringSubmatch(description); directive != nil {
+			description = strings.TrimSpace(description[:len(description)-len(directive[0])])
+			// A failing TODO test is expected to fail and does not count.
+			if strings.EqualFold(directive[1], "skip") || status == "fail" {
+				status = "skip"
+			}
+		}
+		if description == "" {
+			description = "test " + match[2]
+		}
+		scope := subtestAt(depth)
+		if scope != nil {
+			scope.children = true
+			scope.failedChildren = scope.failedChildren || status == "fail"
+		}
+		if child := open[depth+1]; child != nil {
+			delete(open, depth+1)
+			if child.name == "" {
+				child.name = description
+			}
+			if child.children && (status != "fail" || child.failedChildren) {
+				last = -1
+				continue
+			}
+		}
+		cases = append(cases, protocol.TestCase{Name: description, Status: status})
+		scopes = append(scopes, scope)
+		last = len(cases) - 1
+	}
+	flushYAML()
+	for i := range cases {
+		cases[i].Package = scopes[i].path()
+	}
+	suite.Cases = cases
+	tallyTestSuite(&suite)
+	return suite
+}
@@ -0,0 +1,72 @@
+package agent
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseTAPSuiteWithDirectivesAndDiagnostics(t *testing.T) {
+	lines := strings.Split(`TAP version 13
+1..5
+ok 1 - adds numbers
+not ok 2 - divides by zero
+  ---
+  message: expected error
+  duration_ms: 12.5
+  at: ./src/math.test.js:40:7
+  ...
+ok 3 - network # SKIP offline
+not ok 4 - parser rewrite # TODO not done
+ok 5`, "\n")
+	suite := parseTAPSuite("unit", lines)
+	if suite.Format != "tap" || suite.Total != 5 || suite.Passed != 2 || suite.Failed != 1 || suite.Skipped != 2 {
+		t.Fatalf("unexpected counts: %+v", suite)
+	}
+	failed := suite.Cases[1]
+	if failed.Name != "divides by zero" || failed.Status != "fail" || failed.DurationSeconds != 0.0125 {
+		t.Fatalf("unexpected failed case: %+v", failed)
+	}
+	if failed.File != "src/math.test.js" || failed.Line != 40 || !strings.Contains(failed.Output, "expected error") {
+		t.Fatalf("unexpected failed case diagnostics: %+v", failed)
+	}
+	if suite.Cases[2].Name != "network" || suite.Cases[3].Status != "skip" || suite.Cases[4].Name != "test 5" {
+		t.Fatalf("unexpected directive handling: %+v", suite.Cases)
+	}
+}
+
+func TestParseTAPSuiteFlattensSubtests(t *testing.T) {
+	lines := strings.Split(`TAP version 14
+# Subtest: math
+    # Subtest: division
+        ok 1 - by one
+        not ok 2 - by zero
+        1..2
+    not ok 1 - division
+    ok 2 - addition
+    1..2
+not ok 1 - math
+    ok 1 - unnamed child
+    1..1
+ok 2 - strings
+    1..3
+not ok 3 - plan mismatch
+1..3`, "\n")
+	suite := parseTAPSuite("unit", lines)
+	got := make([]string, 0, len(suite.Cases))
+	for _, testCase := range suite.Cases {
+		got = append(got, testCase.Package+"|"+testCase.Name+"|"+testCase.Status)
+	}
+	want := []string{
+		"math / division|by one|pass",
+		"math / division|by zero|fail",
+		"math|addition|pass",
+		"strings|unnamed child|pass",
+		"|plan mismatch|fail",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("unexpected cases:\n%s", strings.Join(got, "\n"))
+	}
+	if suite.Total != 5 || suite.Failed != 2 {
+		t.Fatalf("unexpected counts: %+v", suite)
+	}
+}
@@ -0,0 +1,141 @@
+package agent
+
+import (
+	"encoding/xml"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/izzyreal/ciwi/internal/protocol"
+)
+
+var trxStackTraceLocationRE = regexp.MustCompile(`\bin (.+?):line (\d+)`)
+
+type trxTestRun struct {
+	Name        string          `xml:"name,attr"`
+	Definitions []trxUnitTest   `xml:"TestDefinitions>UnitTest"`
+	Results     []trxTestResult `xml:"Results>UnitTestResult"`
+	Counters    trxCounters     `xml:"ResultSummary>Counters"`
+}
+
+type trxUnitTest struct {
+	ID     string `xml:"id,attr"`
+	Name   string `xml:"name,attr"`
+	Method struct {
+		ClassName string `xml:"className,attr"`
+		Name      string `xml:"name,attr"`
+	} `xml:"TestMethod"`
+}
+
+type trxTestResult struct {
+	TestID       string          `xml:"testId,attr"`
+	TestName     string          `xml:"testName,attr"`
+	Outcome      string          `xml:"outcome,attr"`
+	Duration     string          `xml:"duration,attr"`
+	StdOut       string          `xml:"Output>StdOut"`
+	StdErr       string          `xml:"Output>StdErr"`
+	Message      string          `xml:"Output>ErrorInfo>Message"`
+	StackTrace   string          `xml:"Output>ErrorInfo>StackTrace"`
+	InnerResults []trxTestResult `xml:"InnerResults>UnitTestResult"`
+}
+
+type trxCounters struct {
+	Total    int `xml:"total,attr"`
+	Executed int `xml:"executed,attr"`
+	Passed   int `xml:"passed,attr"`
+	Failed   int `xml:"failed,attr"`
+	Error    int `xml:"error,attr"`
+	Timeout  int `xml:"timeout,attr"`
+	Aborted  int `xml:"aborted,attr"`
+}
+
+// parseTRXSuite reads a Visual Studio test results (TRX) file as written by
+// "dotnet test --logger trx". Data-driven tests report one case per row.
+func parseTRXSuite(name string, lines []string) protocol.TestSuiteReport {
+	suite := protocol.TestSuiteReport{Name: name, Format: "trx"}
+	var run trxTestRun
+	if err := xml.Unmarshal([]byte(strings.TrimSpace(strings.Join(lines, "\n"))), &run); err != nil {
+		return suite
+	}
+	if suite.Name == "" {
+		suite.Name = strings.TrimSpace(run.Name)
+	}
+	classes := make(map[string]string, len(run.Definitions))
+	for _, definition := range run.Definitions {
+		classes[definition.ID] = strings.TrimSpace(definition.Method.ClassName)
+	}
+	var appendResult func(result trxTestResult, className string)
+	appendResult = func(result trxTestResult, className string) {
+		if len(result.InnerResults) > 0 {
+			for _, inner := range result.InnerResults {
+				appendResult(inner, className)
+			}
+			return
+		}
+		testName := strings.TrimSpace(result.TestName)
+		if className != "" {
+			testName = strings.TrimPrefix(testName, className+".")
+		}
+		testCase := protocol.TestCase{
+			Package:         className,
+			Name:            testName,
+			Status:          trxStatus(result.Outcome),
+			DurationSeconds: parseTRXDuration(result.Duration),
+			Output:          collectTRXOutput(result),
+		}
+		if match := trxStackTraceLocationRE.FindStringSubmatch(result.StackTrace); match != nil {
+			testCase.File = normalizeTestSourcePath(match[1])
+			testCase.Line = maxInt(0, parseIntDefault(match[2], 0))
+		}
+		suite.Cases = append(suite.Cases, testCase)
+	}
+	for _, result := range run.Results {
+		appendResult(result, classes[result.TestID])
+	}
+	tallyTestSuite(&suite)
+
+	// Some producers only write the result summary.
+	if suite.Total == 0 && run.Counters.Total > 0 {
+		suite.Total = run.Counters.Total
+		suite.Failed = run.Counters.Failed + run.Counters.Error + run.Counters.Timeout + run.Counters.Aborted
+		suite.Passed = run.Counters.Passed
+		suite.Skipped = maxInt(0, suite.Total-suite.Passed-suite.Failed)
+	}
+	return suite
+}
+
+func trxStatus(outcome string) string {
+	switch strings.ToLower(strings.TrimSpace(outcome)) {
+	case "passed", "passedbutrunaborted", "warning":
+		return "pass"
+	case "failed", "error", "timeout", "aborted":
+		return "fail"
+	default:
+		return "skip"
+	}
+}
+
+// parseTRXDuration reads the hh:mm:ss.fffffff durations of TRX results.
+func parseTRXDuration(raw string) float64 {
+	parts := strings.Split(strings.TrimSpace(raw), ":")
+	if len(parts) != 3 {
+		return 0
+	}
+	hours := parseIntDefault(parts[0], 0)
+	minutes := parseIntDefault(parts[1], 0)
+	seconds := parseFloatDefault(parts[2], 0)
+	return (time.Duration(hours)*time.Hour + time.Duration(minutes)*time.Minute).Seconds() + seconds
+}
+
+func collectTRXOutput(result trxTestResult) string {
+	sections := make([]string, 0, 4)
+	for _, section := range [][2]string{
+		{"message", result.Message}, {"stack trace", result.StackTrace},
+		{"stdout", result.StdOut}, {"stderr", result.StdErr},
+	} {
+		if body := strings.TrimSpace(section[1]); body != "" {
+			sections = append(sections, section[0]+"\n"+body)
+		}
+	}
+	return strings.Join(sections, "\n")
+}
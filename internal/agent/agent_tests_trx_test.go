@@ -0,0 +1,59 @@
+package agent
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseTRXSuiteMapsOutcomesAndClasses(t *testing.T) {
+	lines := strings.Split(`<?xml version="1.0" encoding="utf-8"?>
+<TestRun id="1" name="ci@host 2026-03-01" xmlns="http://microsoft.com/schemas/VisualStudio/TeamTest/2010">
+  <Results>
+    <UnitTestResult testId="a" testName="Calc.Tests.MathTests.Adds" outcome="Passed" duration="00:00:00.1250000" />
+    <UnitTestResult testId="b" testName="Calc.Tests.MathTests.Divides" outcome="Failed" duration="00:01:02.5000000">
+      <Output>
+        <StdOut>dividing</StdOut>
+        <ErrorInfo>
+          <Message>Assert.Equal() Failure</Message>
+          <StackTrace>at Calc.Tests.MathTests.Divides() in /src/Calc.Tests/MathTests.cs:line 27</StackTrace>
+        </ErrorInfo>
+      </Output>
+    </UnitTestResult>
+    <UnitTestResult testId="c" testName="Rows" outcome="Passed">
+      <InnerResults>
+        <UnitTestResult testId="c" testName="Rows (1)" outcome="Passed" />
+        <UnitTestResult testId="c" testName="Rows (2)" outcome="NotExecuted" />
+      </InnerResults>
+    </UnitTestResult>
+  </Results>
+  <TestDefinitions>
+    <UnitTest id="a" name="Adds"><TestMethod className="Calc.Tests.MathTests" name="Adds" /></UnitTest>
+    <UnitTest id="b" name="Divides"><TestMethod className="Calc.Tests.MathTests" name="Divides" /></UnitTest>
+    <UnitTest id="c" name="Rows"><TestMethod className="Calc.Tests.DataTests" name="Rows" /></UnitTest>
+  </TestDefinitions>
+</TestRun>`, "\n")
+	suite := parseTRXSuite("", lines)
+	if suite.Name != "ci@host 2026-03-01" || suite.Format != "trx" {
+		t.Fatalf("unexpected suite identity: %+v", suite)
+	}
+	if suite.Total != 4 || suite.Passed != 2 || suite.Failed != 1 || suite.Skipped != 1 {
+		t.Fatalf("unexpected counts: %+v", suite)
+	}
+	failed := suite.Cases[1]
+	if failed.Package != "Calc.Tests.MathTests" || failed.Name != "Divides" || failed.DurationSeconds != 62.5 {
+		t.Fatalf("unexpected failed case: %+v", failed)
+	}
+	if failed.File != "/src/Calc.Tests/MathTests.cs" || failed.Line != 27 || !strings.Contains(failed.Output, "Assert.Equal() Failure") || !strings.Contains(failed.Output, "dividing") {
+		t.Fatalf("unexpected failed case details: %+v", failed)
+	}
+	if suite.Cases[3].Package != "Calc.Tests.DataTests" || suite.Cases[3].Name != "Rows (2)" {
+		t.Fatalf("unexpected data row case: %+v", suite.Cases[3])
+	}
+}
+
+func TestParseTRXSuiteFallsBackToCounters(t *testing.T) {
+	suite := parseTRXSuite("dotnet", []string{`<TestRun><ResultSummary outcome="Failed"><Counters total="5" passed="3" failed="1" error="0" /></ResultSummary></TestRun>`})
+	if suite.Total != 5 || suite.Passed != 3 || suite.Failed != 1 || suite.Skipped != 1 {
+		t.Fatalf("unexpected counts: %+v", suite)
+	}
+}
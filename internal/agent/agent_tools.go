@@ -35,6 +35,9 @@ func detectAgentCapabilities() map[string]string {
 		}
 		caps["tool."+tool] = version
 	}
+	if rt, err := detectContainerRuntime(); err == nil {
+		caps["container_runtime"] = rt.Name()
+	}
 	return caps
 }
 
@@ -5,6 +5,7 @@ import (
 	"encoding/hex"
 	"path/filepath"
 	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/izzyreal/ciwi/internal/domain"
@@ -47,6 +48,16 @@ func workspaceDirForJob(workDir string, job protocol.JobExecution) string {
 	return filepath.Join(workDir, "workspaces", name)
 }
 
+// workspaceDirForJobSlot keeps slot 0 on the historical layout and gives
+// every other slot its own sibling directory.
+func workspaceDirForJobSlot(workDir string, job protocol.JobExecution, slot int) string {
+	dir := workspaceDirForJob(workDir, job)
+	if slot <= 0 {
+		return dir
+	}
+	return dir + "_slot" + strconv.Itoa(slot)
+}
+
 func workspaceMatrixIdentity(meta domain.ExecutionMetadata) string {
 	if name := meta.Value(domain.ExecutionMetadataMatrixName); name != "" {
 		return name
@@ -101,3 +101,17 @@ func TestWorkspaceEnvFingerprintFallbackAndSanitize(t *testing.T) {
 		t.Fatalf("expected matrix identity from index, got %q", matrix)
 	}
 }
+
+func TestWorkspaceDirForJobSlotIsolatesConcurrentSlots(t *testing.T) {
+	workDir := t.TempDir()
+	job := protocol.JobExecution{ID: "job-a", Metadata: map[string]string{"project_id": "42", "pipeline_job_id": "unit"}}
+	base := workspaceDirForJob(workDir, job)
+	if got := workspaceDirForJobSlot(workDir, job, 0); got != base {
+		t.Fatalf("expected slot 0 to keep the historical workspace, got %q want %q", got, base)
+	}
+	one := workspaceDirForJobSlot(workDir, job, 1)
+	two := workspaceDirForJobSlot(workDir, job, 2)
+	if one == base || two == base || one == two {
+		t.Fatalf("expected distinct slot workspaces, got base=%q one=%q two=%q", base, one, two)
+	}
+}
@@ -0,0 +1,435 @@
+package application
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"time"
+
+	"github.com/izzyreal/ciwi/internal/domain"
+)
+
+const (
+	// APITokenPrefix marks personal API tokens so transports can tell them
+	// apart from session tokens.
+	APITokenPrefix     = "ciwi_"
+	minPasswordLength  = 8
+	maxUsernameLength  = 64
+	maxTokenNameLength = 100
+)
+
+// SessionTTL is how long a browser session stays valid after login.
+var SessionTTL = 7 * 24 * time.Hour
+
+// AuthRepository persists users, sessions and API tokens. Session and API
+// tokens are only ever stored as hashes.
+type AuthRepository interface {
+	CountUsers(context.Context) (int, error)
+	ListUsers(context.Context) ([]domain.User, error)
+	GetUser(context.Context, int64) (domain.User, bool, error)
+	GetUserCredentials(ctx context.Context, username string) (domain.User, string, bool, error)
+	// CreateUser inserts a user. When first is set it must fail with a
+	// failed precondition error unless no user exists yet.
+	CreateUser(ctx context.Context, user domain.User, passwordHash string, first bool) (domain.User, error)
+	// UpdateUser changes the role and, unless passwordHash is empty, the
+	// password of a user. A password change ends the user's sessions.
+	UpdateUser(ctx context.Context, id int64, role domain.Role, passwordHash string) (domain.User, error)
+	DeleteUser(context.Context, int64) error
+	RecordLogin(ctx context.Context, userID int64, sessionHash string, at, expires time.Time) error
+	FindSessionUser(ctx context.Context, sessionHash string, now time.Time) (domain.User, bool, error)
+	DeleteSession(ctx context.Context, sessionHash string) error
+	CreateAPIToken(ctx context.Context, token domain.APIToken, tokenHash string) (domain.APIToken, error)
+	FindAPITokenUser(ctx context.Context, tokenHash string, now time.Time) (domain.User, domain.APIToken, bool, error)
+	ListAPITokens(ctx context.Context, userID int64) ([]domain.APIToken, error)
+	DeleteAPIToken(ctx context.Context, userID, tokenID int64) (bool, error)
+}
+
+type LoginRequest struct {
+	Username string
+	Password string
+}
+
+type LoginResult struct {
+	User         domain.User
+	SessionToken string
+	ExpiresUTC   time.Time
+}
+
+type CreateUserRequest struct {
+	Username string
+	Password string
+	Role     string
+}
+
+type UpdateUserRequest struct {
+	UserID   int64
+	Role     string
+	Password string
+	// CurrentPassword must be the user's password when they change their
+	// own, so a stolen session or token cannot take over the account.
+	CurrentPassword string
+}
+
+type CreateAPITokenRequest struct {
+	Name       string
+	ExpiresUTC time.Time
+}
+
+type CreateAPITokenResult struct {
+	Token  domain.APIToken
+	Secret string
+}
+
+// AccountService manages local users and authenticates sessions and personal
+// API tokens. Authentication is enabled as soon as the first user exists.
+type AccountService struct {
+	repository AuthRepository
+	authorizer *Authorizer
+	now        func() time.Time
+}
+
+func NewAccountService(repository AuthRepository) *AccountService {
+	s := &AccountService{repository: repository, now: func() time.Time { return time.Now().UTC() }}
+	s.authorizer = NewAuthorizer(s)
+	return s
+}
+
+func (s *AccountService) Enabled(ctx context.Context) (bool, error) {
+	if s == nil || s.repository == nil {
+		return false, nil
+	}
+	count, err := s.repository.CountUsers(ctx)
+	if err != nil {
+		return false, WrapInternal("count users", err)
+	}
+	return count > 0, nil
+}
+
+func (s *AccountService) Login(ctx context.Context, request LoginRequest) (LoginResult, error) {
+	if err := s.available(); err != nil {
+		return LoginResult{}, err
+	}
+	user, encoded, found, err := s.repository.GetUserCredentials(ctx, strings.TrimSpace(request.Username))
+	if err != nil {
+		return LoginResult{}, WrapInternal("get user", err)
+	}
+	if !found {
+		// Spend the same time as a real check so timing does not reveal
+		// which usernames exist.
+		encoded = dummyPasswordHash()
+	}
+	if !verifyPassword(encoded, request.Password) || !found {
+		return LoginResult{}, NewError(ErrorUnauthenticated, "invalid username or password", nil)
+	}
+	secret, err := newAuthSecret("")
+	if err != nil {
+		return LoginResult{}, err
+	}
+	now := s.now()
+	expires := now.Add(SessionTTL)
+	if err := s.repository.RecordLogin(ctx, user.ID, hashAuthSecret(secret), now, expires); err != nil {
+		return LoginResult{}, WrapInternal("create session", err)
+	}
+	user.LastLoginUTC = now
+	return LoginResult{User: user, SessionToken: secret, ExpiresUTC: expires}, nil
+}
+
+func (s *AccountService) Logout(ctx context.Context, sessionToken string) error {
+	if err := s.available(); err != nil {
+		return err
+	}
+	if strings.TrimSpace(sessionToken) == "" {
+		return nil
+	}
+	if err := s.repository.DeleteSession(ctx, hashAuthSecret(sessionToken)); err != nil {
+		return WrapInternal("delete session", err)
+	}
+	return nil
+}
+
+func (s *AccountService) AuthenticateSession(ctx context.Context, sessionToken string) (domain.Principal, error) {
+	if err := s.available(); err != nil {
+		return domain.Principal{}, err
+	}
+	sessionToken = strings.TrimSpace(sessionToken)
+	if sessionToken == "" {
+		return domain.Principal{}, NewError(ErrorUnauthenticated, "authentication required", nil)
+	}
+	user, found, err := s.repository.FindSessionUser(ctx, hashAuthSecret(sessionToken), s.now())
+	if err != nil {
+		return domain.Principal{}, WrapInternal("find session", err)
+	}
+	if !found {
+		return domain.Principal{}, NewError(ErrorUnauthenticated, "session expired or invalid", nil)
+	}
+	return domain.Principal{UserID: user.ID, Username: user.Username, Role: user.Role}, nil
+}
+
+func (s *AccountService) AuthenticateToken(ctx context.Context, token string) (domain.Principal, error) {
+	if err := s.available(); err != nil {
+		return domain.Principal{}, err
+	}
+	token = strings.TrimSpace(token)
+	if !strings.HasPrefix(token, APITokenPrefix) {
+		return domain.Principal{}, NewError(ErrorUnauthenticated, "a ciwi API token is required", nil)
+	}
+	user, apiToken, found, err := s.repository.FindAPITokenUser(ctx, hashAuthSecret(token), s.now())
+	if err != nil {
+		return domain.Principal{}, WrapInternal("find api token", err)
+	}
+	if !found {
+		return domain.Principal{}, NewError(ErrorUnauthenticated, "API token expired, revoked or invalid", nil)
+	}
+	return domain.Principal{UserID: user.ID, Username: user.Username, Role: user.Role, TokenID: apiToken.ID}, nil
+}
+
+// CreateUser adds a user. The first user may be created without
+// authentication and is always an admin; it turns authentication on.
+func (s *AccountService) CreateUser(ctx context.Context, request CreateUserRequest) (domain.User, error) {
+	if err := s.available(); err != nil {
+		return domain.User{}, err
+	}
+	username, err := validateUsername(request.Username)
+	if err != nil {
+		return domain.User{}, err
+	}
+	if err := validatePassword(request.Password); err != nil {
+		return domain.User{}, err
+	}
+	enabled, err := s.Enabled(ctx)
+	if err != nil {
+		return domain.User{}, err
+	}
+	role := domain.RoleAdmin
+	if enabled {
+		if err := s.authorizer.Authorize(ctx, ActionManageUsers); err != nil {
+			return domain.User{}, err
+		}
+		var ok bool
+		if role, ok = domain.ParseRole(request.Role); !ok {
+			return domain.User{}, NewError(ErrorInvalidArgument, "role must be viewer, operator or admin", nil)
+		}
+	}
+	encoded, err := hashPassword(request.Password)
+	if err != nil {
+		return domain.User{}, WrapInternal("hash password", err)
+	}
+	return s.repository.CreateUser(ctx, domain.User{Username: username, Role: role, CreatedUTC: s.now()}, encoded, !enabled)
+}
+
+func (s *AccountService) ListUsers(ctx context.Context) ([]domain.User, error) {
+	if err := s.available(); err != nil {
+		return nil, err
+	}
+	if err := s.authorizer.Authorize(ctx, ActionManageUsers); err != nil {
+		return nil, err
+	}
+	users, err := s.repository.ListUsers(ctx)
+	if err != nil {
+		return nil, WrapInternal("list users", err)
+	}
+	return users, nil
+}
+
+// UpdateUser changes a user's role or password. Users may change their own
+// password if they confirm the current one; everything else requires the
+// manage users permission.
+func (s *AccountService) UpdateUser(ctx context.Context, request UpdateUserRequest) (domain.User, error) {
+	if err := s.available(); err != nil {
+		return domain.User{}, err
+	}
+	user, found, err := s.repository.GetUser(ctx, request.UserID)
+	if err != nil {
+		return domain.User{}, WrapInternal("get user", err)
+	}
+	if !found {
+		return domain.User{}, NewError(ErrorNotFound, "user not found", nil)
+	}
+	role := user.Role
+	if strings.TrimSpace(request.Role) != "" {
+		var ok bool
+		if role, ok = domain.ParseRole(request.Role); !ok {
+			return domain.User{}, NewError(ErrorInvalidArgument, "role must be viewer, operator or admin", nil)
+		}
+	}
+	principal, authenticated := PrincipalFromContext(ctx)
+	ownPasswordChange := authenticated && principal.UserID == user.ID && role == user.Role
+	if !ownPasswordChange {
+		if err := s.authorizer.Authorize(ctx, ActionManageUsers); err != nil {
+			return domain.User{}, err
+		}
+	} else if request.Password != "" {
+		if request.CurrentPassword == "" {
+			return domain.User{}, NewError(ErrorInvalidArgument, "current_password is required to change your own password", nil)
+		}
+		_, encoded, found, err := s.repository.GetUserCredentials(ctx, user.Username)
+		if err != nil {
+			return domain.User{}, WrapInternal("get user credentials", err)
+		}
+		if !found || !verifyPassword(encoded, request.CurrentPassword) {
+			return domain.User{}, NewError(ErrorPermissionDenied, "current password is incorrect", nil)
+		}
+	}
+	if user.Role == domain.RoleAdmin && role != domain.RoleAdmin {
+		if err := s.requireAnotherAdmin(ctx, user.ID); err != nil {
+			return domain.User{}, err
+		}
+	}
+	encoded := ""
+	if request.Password != "" {
+		if err := validatePassword(request.Password); err != nil {
+			return domain.User{}, err
+		}
+		if encoded, err = hashPassword(request.Password); err != nil {
+			return domain.User{}, WrapInternal("hash password", err)
+		}
+	}
+	return s.repository.UpdateUser(ctx, user.ID, role, encoded)
+}
+
+func (s *AccountService) DeleteUser(ctx context.Context, userID int64) error {
+	if err := s.available(); err != nil {
+		return err
+	}
+	if err := s.authorizer.Authorize(ctx, ActionManageUsers); err != nil {
+		return err
+	}
+	user, found, err := s.repository.GetUser(ctx, userID)
+	if err != nil {
+		return WrapInternal("get user", err)
+	}
+	if !found {
+		return NewError(ErrorNotFound, "user not found", nil)
+	}
+	if user.Role == domain.RoleAdmin {
+		if err := s.requireAnotherAdmin(ctx, user.ID); err != nil {
+			return err
+		}
+	}
+	if err := s.repository.DeleteUser(ctx, user.ID); err != nil {
+		return WrapInternal("delete user", err)
+	}
+	return nil
+}
+
+func (s *AccountService) requireAnotherAdmin(ctx context.Context, exceptUserID int64) error {
+	users, err := s.repository.ListUsers(ctx)
+	if err != nil {
+		return WrapInternal("list users", err)
+	}
+	for _, user := range users {
+		if user.ID != exceptUserID && user.Role == domain.RoleAdmin {
+			return nil
+		}
+	}
+	return NewError(ErrorFailedPrecondition, "at least one admin must remain", nil)
+}
+
+// CreateAPIToken issues a personal API token for the calling user. The
+// secret is only returned here.
+func (s *AccountService) CreateAPIToken(ctx context.Context, request CreateAPITokenRequest) (CreateAPITokenResult, error) {
+	principal, err := s.requireUser(ctx)
+	if err != nil {
+		return CreateAPITokenResult{}, err
+	}
+	name := strings.TrimSpace(request.Name)
+	if name == "" || len(name) > maxTokenNameLength {
+		return CreateAPITokenResult{}, NewError(ErrorInvalidArgument, "token name is required and must be at most 100 characters", nil)
+	}
+	now := s.now()
+	if !request.ExpiresUTC.IsZero() && !request.ExpiresUTC.After(now) {
+		return CreateAPITokenResult{}, NewError(ErrorInvalidArgument, "token expiry must be in the future", nil)
+	}
+	secret, err := newAuthSecret(APITokenPrefix)
+	if err != nil {
+		return CreateAPITokenResult{}, err
+	}
+	token, err := s.repository.CreateAPIToken(ctx, domain.APIToken{
+		UserID: principal.UserID, Name: name, Prefix: secret[:len(APITokenPrefix)+8],
+		CreatedUTC: now, ExpiresUTC: request.ExpiresUTC.UTC(),
+	}, hashAuthSecret(secret))
+	if err != nil {
+		return CreateAPITokenResult{}, WrapInternal("create api token", err)
+	}
+	return CreateAPITokenResult{Token: token, Secret: secret}, nil
+}
+
+func (s *AccountService) ListAPITokens(ctx context.Context) ([]domain.APIToken, error) {
+	principal, err := s.requireUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tokens, err := s.repository.ListAPITokens(ctx, principal.UserID)
+	if err != nil {
+		return nil, WrapInternal("list api tokens", err)
+	}
+	return tokens, nil
+}
+
+func (s *AccountService) RevokeAPIToken(ctx context.Context, tokenID int64) error {
+	principal, err := s.requireUser(ctx)
+	if err != nil {
+		return err
+	}
+	deleted, err := s.repository.DeleteAPIToken(ctx, principal.UserID, tokenID)
+	if err != nil {
+		return WrapInternal("revoke api token", err)
+	}
+	if !deleted {
+		return NewError(ErrorNotFound, "API token not found", nil)
+	}
+	return nil
+}
+
+func (s *AccountService) requireUser(ctx context.Context) (domain.Principal, error) {
+	if err := s.available(); err != nil {
+		return domain.Principal{}, err
+	}
+	principal, ok := PrincipalFromContext(ctx)
+	if !ok || principal.UserID == 0 {
+		return domain.Principal{}, NewError(ErrorUnauthenticated, "sign in to manage API tokens", nil)
+	}
+	return principal, nil
+}
+
+func (s *AccountService) available() error {
+	if s == nil || s.repository == nil {
+		return NewError(ErrorUnavailable, "account service unavailable", nil)
+	}
+	return nil
+}
+
+func validateUsername(raw string) (string, error) {
+	username := strings.TrimSpace(raw)
+	if username == "" || len(username) > maxUsernameLength {
+		return "", NewError(ErrorInvalidArgument, "username is required and must be at most 64 characters", nil)
+	}
+	for _, r := range username {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '.' || r == '_' || r == '-' || r == '@') {
+			return "", NewError(ErrorInvalidArgument, "username may only contain letters, digits, '.', '_', '-' and '@'", nil)
+		}
+	}
+	return username, nil
+}
+
+func validatePassword(password string) error {
+	if len(password) < minPasswordLength {
+		return NewError(ErrorInvalidArgument, "password must be at least 8 characters", nil)
+	}
+	return nil
+}
+
+func newAuthSecret(prefix string) (string, error) {
+	var raw [32]byte
+	if _, err := rand.Read(raw[:]); err != nil {
+		return "", WrapInternal("generate secret", err)
+	}
+	return prefix + hex.EncodeToString(raw[:]), nil
+}
+
+func hashAuthSecret(secret string) string {
+	sum := sha256.Sum256([]byte(strings.TrimSpace(secret)))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,251 @@
+package application
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/izzyreal/ciwi/internal/domain"
+)
+
+type authRepositoryStub struct {
+	users     map[int64]domain.User
+	passwords map[int64]string
+	sessions  map[string]int64
+	tokens    map[string]domain.APIToken
+	nextID    int64
+}
+
+func newAuthRepositoryStub() *authRepositoryStub {
+	return &authRepositoryStub{
+		users: map[int64]domain.User{}, passwords: map[int64]string{},
+		sessions: map[string]int64{}, tokens: map[string]domain.APIToken{},
+	}
+}
+
+func (s *authRepositoryStub) CountUsers(context.Context) (int, error) { return len(s.users), nil }
+
+func (s *authRepositoryStub) ListUsers(context.Context) ([]domain.User, error) {
+	out := make([]domain.User, 0, len(s.users))
+	for _, user := range s.users {
+		out = append(out, user)
+	}
+	return out, nil
+}
+
+func (s *authRepositoryStub) GetUser(_ context.Context, id int64) (domain.User, bool, error) {
+	user, ok := s.users[id]
+	return user, ok, nil
+}
+
+func (s *authRepositoryStub) GetUserCredentials(_ context.Context, username string) (domain.User, string, bool, error) {
+	for id, user := range s.users {
+		if strings.EqualFold(user.Username, username) {
+			return user, s.passwords[id], true, nil
+		}
+	}
+	return domain.User{}, "", false, nil
+}
+
+func (s *authRepositoryStub) CreateUser(_ context.Context, user domain.User, passwordHash string, first bool) (domain.User, error) {
+	if first && len(s.users) > 0 {
+		return domain.User{}, NewError(ErrorFailedPrecondition, "users already exist", nil)
+	}
+	s.nextID++
+	user.ID = s.nextID
+	s.users[user.ID] = user
+	s.passwords[user.ID] = passwordHash
+	return user, nil
+}
+
+func (s *authRepositoryStub) UpdateUser(_ context.Context, id int64, role domain.Role, passwordHash string) (domain.User, error) {
+	user := s.users[id]
+	user.Role = role
+	s.users[id] = user
+	if passwordHash != "" {
+		s.passwords[id] = passwordHash
+	}
+	return user, nil
+}
+
+func (s *authRepositoryStub) DeleteUser(_ context.Context, id int64) error {
+	delete(s.users, id)
+	return nil
+}
+
+func (s *authRepositoryStub) RecordLogin(_ context.Context, userID int64, sessionHash string, _, _ time.Time) error {
+	s.sessions[sessionHash] = userID
+	return nil
+}
+
+func (s *authRepositoryStub) FindSessionUser(_ context.Context, sessionHash string, _ time.Time) (domain.User, bool, error) {
+	id, ok := s.sessions[sessionHash]
+	if !ok {
+		return domain.User{}, false, nil
+	}
+	user, ok := s.users[id]
+	return user, ok, nil
+}
+
+func (s *authRepositoryStub) DeleteSession(_ context.Context, sessionHash string) error {
+	delete(s.sessions, sessionHash)
+	return nil
+}
+
+func (s *authRepositoryStub) CreateAPIToken(_ context.Context, token domain.APIToken, tokenHash string) (domain.APIToken, error) {
+	s.nextID++
+	token.ID = s.nextID
+	s.tokens[tokenHash] = token
+	return token, nil
+}
+
+func (s *authRepositoryStub) FindAPITokenUser(_ context.Context, tokenHash string, _ time.Time) (domain.User, domain.APIToken, bool, error) {
+	token, ok := s.tokens[tokenHash]
+	if !ok {
+		return domain.User{}, domain.APIToken{}, false, nil
+	}
+	user, ok := s.users[token.UserID]
+	return user, token, ok, nil
+}
+
+func (s *authRepositoryStub) ListAPITokens(_ context.Context, userID int64) ([]domain.APIToken, error) {
+	var out []domain.APIToken
+	for _, token := range s.tokens {
+		if token.UserID == userID {
+			out = append(out, token)
+		}
+	}
+	return out, nil
+}
+
+func (s *authRepositoryStub) DeleteAPIToken(_ context.Context, userID, tokenID int64) (bool, error) {
+	for hash, token := range s.tokens {
+		if token.ID == tokenID && token.UserID == userID {
+			delete(s.tokens, hash)
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func lowerPasswordHashCost(t *testing.T) {
+	t.Helper()
+	previous := passwordHashIterations
+	passwordHashIterations = 1000
+	t.Cleanup(func() { passwordHashIterations = previous })
+}
+
+func TestAccountServiceBootstrapsFirstAdminAndLogsIn(t *testing.T) {
+	lowerPasswordHashCost(t)
+	service := NewAccountService(newAuthRepositoryStub())
+	ctx := context.Background()
+	if enabled, _ := service.Enabled(ctx); enabled {
+		t.Fatal("auth must be disabled without users")
+	}
+	admin, err := service.CreateUser(ctx, CreateUserRequest{Username: "root", Password: "correct horse", Role: "viewer"})
+	if err != nil {
+		t.Fatalf("bootstrap admin: %v", err)
+	}
+	if admin.Role != domain.RoleAdmin {
+		t.Fatalf("first user must be admin, got %q", admin.Role)
+	}
+	anonymous := WithPrincipal(ctx, domain.Principal{})
+	if _, err := service.CreateUser(anonymous, CreateUserRequest{Username: "late", Password: "password1", Role: "admin"}); ErrorKindOf(err) != ErrorUnauthenticated {
+		t.Fatalf("expected unauthenticated user creation to fail once auth is on, got %v", err)
+	}
+	if _, err := service.Login(ctx, LoginRequest{Username: "root", Password: "wrong password"}); ErrorKindOf(err) != ErrorUnauthenticated {
+		t.Fatalf("expected bad password to fail, got %v", err)
+	}
+	if _, err := service.Login(ctx, LoginRequest{Username: "nobody", Password: "correct horse"}); ErrorKindOf(err) != ErrorUnauthenticated {
+		t.Fatalf("expected unknown user to fail, got %v", err)
+	}
+	login, err := service.Login(ctx, LoginRequest{Username: "root", Password: "correct horse"})
+	if err != nil || login.SessionToken == "" {
+		t.Fatalf("login: %+v %v", login, err)
+	}
+	principal, err := service.AuthenticateSession(ctx, login.SessionToken)
+	if err != nil || principal.UserID != admin.ID || principal.Role != domain.RoleAdmin {
+		t.Fatalf("authenticate session: %+v %v", principal, err)
+	}
+	if err := service.Logout(ctx, login.SessionToken); err != nil {
+		t.Fatalf("logout: %v", err)
+	}
+	if _, err := service.AuthenticateSession(ctx, login.SessionToken); ErrorKindOf(err) != ErrorUnauthenticated {
+		t.Fatalf("expected logged out session to fail, got %v", err)
+	}
+}
+
+func TestAccountServiceRolesAndTokens(t *testing.T) {
+	lowerPasswordHashCost(t)
+	service := NewAccountService(newAuthRepositoryStub())
+	admin, err := service.CreateUser(context.Background(), CreateUserRequest{Username: "root", Password: "password1"})
+	if err != nil {
+		t.Fatalf("bootstrap admin: %v", err)
+	}
+	adminCtx := WithPrincipal(context.Background(), domain.Principal{UserID: admin.ID, Username: admin.Username, Role: admin.Role})
+	operator, err := service.CreateUser(adminCtx, CreateUserRequest{Username: "ops", Password: "password2", Role: "operator"})
+	if err != nil || operator.Role != domain.RoleOperator {
+		t.Fatalf("create operator: %+v %v", operator, err)
+	}
+	operatorCtx := WithPrincipal(context.Background(), domain.Principal{UserID: operator.ID, Username: operator.Username, Role: operator.Role})
+	if _, err := service.CreateUser(operatorCtx, CreateUserRequest{Username: "x", Password: "password3", Role: "admin"}); ErrorKindOf(err) != ErrorPermissionDenied {
+		t.Fatalf("expected operator user creation to be denied, got %v", err)
+	}
+	if _, err := service.UpdateUser(operatorCtx, UpdateUserRequest{UserID: operator.ID, Role: "admin"}); ErrorKindOf(err) != ErrorPermissionDenied {
+		t.Fatalf("expected self promotion to be denied, got %v", err)
+	}
+	if _, err := service.UpdateUser(operatorCtx, UpdateUserRequest{UserID: operator.ID, Password: "new password"}); ErrorKindOf(err) != ErrorInvalidArgument {
+		t.Fatalf("expected own password change without the current password to fail, got %v", err)
+	}
+	if _, err := service.UpdateUser(operatorCtx, UpdateUserRequest{UserID: operator.ID, Password: "new password", CurrentPassword: "wrong password"}); ErrorKindOf(err) != ErrorPermissionDenied {
+		t.Fatalf("expected own password change with a wrong current password to fail, got %v", err)
+	}
+	if _, err := service.UpdateUser(operatorCtx, UpdateUserRequest{UserID: operator.ID, Password: "new password", CurrentPassword: "password2"}); err != nil {
+		t.Fatalf("expected own password change to pass, got %v", err)
+	}
+	if _, err := service.Login(context.Background(), LoginRequest{Username: "ops", Password: "new password"}); err != nil {
+		t.Fatalf("expected login with the new password, got %v", err)
+	}
+	if _, err := service.UpdateUser(adminCtx, UpdateUserRequest{UserID: operator.ID, Password: "reset password"}); err != nil {
+		t.Fatalf("expected an admin to reset another password without it, got %v", err)
+	}
+	if _, err := service.UpdateUser(adminCtx, UpdateUserRequest{UserID: admin.ID, Role: "viewer"}); ErrorKindOf(err) != ErrorFailedPrecondition {
+		t.Fatalf("expected demoting the last admin to fail, got %v", err)
+	}
+	if err := service.DeleteUser(adminCtx, admin.ID); ErrorKindOf(err) != ErrorFailedPrecondition {
+		t.Fatalf("expected deleting the last admin to fail, got %v", err)
+	}
+
+	created, err := service.CreateAPIToken(operatorCtx, CreateAPITokenRequest{Name: "ci"})
+	if err != nil || !strings.HasPrefix(created.Secret, APITokenPrefix) || !strings.HasPrefix(created.Secret, created.Token.Prefix) {
+		t.Fatalf("create api token: %+v %v", created, err)
+	}
+	principal, err := service.AuthenticateToken(context.Background(), created.Secret)
+	if err != nil || principal.UserID != operator.ID || principal.TokenID != created.Token.ID {
+		t.Fatalf("authenticate token: %+v %v", principal, err)
+	}
+	if err := service.RevokeAPIToken(adminCtx, created.Token.ID); ErrorKindOf(err) != ErrorNotFound {
+		t.Fatalf("expected tokens to be revocable only by their owner, got %v", err)
+	}
+	if err := service.RevokeAPIToken(operatorCtx, created.Token.ID); err != nil {
+		t.Fatalf("revoke token: %v", err)
+	}
+	if _, err := service.AuthenticateToken(context.Background(), created.Secret); ErrorKindOf(err) != ErrorUnauthenticated {
+		t.Fatalf("expected revoked token to fail, got %v", err)
+	}
+}
+
+func TestPasswordHashRoundTrip(t *testing.T) {
+	lowerPasswordHashCost(t)
+	encoded, err := hashPassword("s3cret-pass")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(encoded, "pbkdf2-sha256$1000$") {
+		t.Fatalf("unexpected encoding %q", encoded)
+	}
+	if !verifyPassword(encoded, "s3cret-pass") || verifyPassword(encoded, "s3cret-pasS") || verifyPassword("garbage", "s3cret-pass") {
+		t.Fatal("password verification mismatch")
+	}
+}
@@ -0,0 +1,231 @@
+package application
+
+import (
+	"context"
+	"crypto/subtle"
+	"strings"
+	"time"
+
+	"github.com/izzyreal/ciwi/internal/domain"
+)
+
+const (
+	// AgentEnrollmentTokenPrefix and AgentCredentialPrefix mark agent
+	// secrets. Both share APITokenPrefix, so transports must check for them
+	// before treating a bearer token as a personal API token.
+	AgentEnrollmentTokenPrefix = "ciwi_enroll_"
+	AgentCredentialPrefix      = "ciwi_agent_"
+	maxAgentIDLength           = 200
+	maxEnrollmentDescription   = 200
+)
+
+// AgentEnrollmentTokenTTL is the validity of enrollment tokens created
+// without an explicit expiry.
+var AgentEnrollmentTokenTTL = 24 * time.Hour
+
+// AgentCredentialRepository persists enrollment tokens and per-agent
+// credentials. Secrets are only ever stored as hashes.
+type AgentCredentialRepository interface {
+	CreateEnrollmentToken(ctx context.Context, token domain.AgentEnrollmentToken, tokenHash string) (domain.AgentEnrollmentToken, error)
+	ListEnrollmentTokens(context.Context) ([]domain.AgentEnrollmentToken, error)
+	DeleteEnrollmentToken(ctx context.Context, id int64) (bool, error)
+	// EnrollAgent consumes the unused, unexpired token matching tokenHash
+	// and binds credentialHash to agentID, replacing an earlier credential.
+	// It reports false when no usable token matches.
+	EnrollAgent(ctx context.Context, tokenHash, agentID, credentialHash string, now time.Time) (bool, error)
+	GetAgentCredential(ctx context.Context, agentID string) (domain.AgentCredential, string, bool, error)
+	// EnrollmentInUse reports whether any enrollment token or agent
+	// credential exists.
+	EnrollmentInUse(ctx context.Context) (bool, error)
+	RevokeAgentCredential(ctx context.Context, agentID string, at time.Time) error
+}
+
+type CreateEnrollmentTokenRequest struct {
+	Description string
+	ExpiresUTC  time.Time
+}
+
+type CreateEnrollmentTokenResult struct {
+	Token  domain.AgentEnrollmentToken
+	Secret string
+}
+
+type EnrollAgentRequest struct {
+	AgentID string
+	Token   string
+}
+
+type EnrollAgentResult struct {
+	AgentID    string
+	Credential string
+}
+
+// AgentCredentialService issues enrollment tokens and checks that agent
+// requests carry the credential bound to the agent ID they claim. Agents
+// that never enrolled are only accepted while no agent uses enrollment and
+// enrollment is not required.
+type AgentCredentialService struct {
+	repository AgentCredentialRepository
+	required   bool
+	authorizer *Authorizer
+	now        func() time.Time
+}
+
+func NewAgentCredentialService(repository AgentCredentialRepository, enrollmentRequired bool, authorizer *Authorizer) *AgentCredentialService {
+	return &AgentCredentialService{repository: repository, required: enrollmentRequired, authorizer: authorizer, now: func() time.Time { return time.Now().UTC() }}
+}
+
+func (s *AgentCredentialService) CreateEnrollmentToken(ctx context.Context, request CreateEnrollmentTokenRequest) (CreateEnrollmentTokenResult, error) {
+	if err := s.available(); err != nil {
+		return CreateEnrollmentTokenResult{}, err
+	}
+	if err := s.authorizer.Authorize(ctx, ActionManageAgents); err != nil {
+		return CreateEnrollmentTokenResult{}, err
+	}
+	description := strings.TrimSpace(request.Description)
+	if len(description) > maxEnrollmentDescription {
+		return CreateEnrollmentTokenResult{}, NewError(ErrorInvalidArgument, "description must be at most 200 characters", nil)
+	}
+	now := s.now()
+	expires := request.ExpiresUTC.UTC()
+	if expires.IsZero() {
+		expires = now.Add(AgentEnrollmentTokenTTL)
+	}
+	if !expires.After(now) {
+		return CreateEnrollmentTokenResult{}, NewError(ErrorInvalidArgument, "token expiry must be in the future", nil)
+	}
+	secret, err := newAuthSecret(AgentEnrollmentTokenPrefix)
+	if err != nil {
+		return CreateEnrollmentTokenResult{}, err
+	}
+	principal, _ := PrincipalFromContext(ctx)
+	token, err := s.repository.CreateEnrollmentToken(ctx, domain.AgentEnrollmentToken{
+		Description: description, Prefix: secret[:len(AgentEnrollmentTokenPrefix)+8], CreatedBy: principal.Username,
+		CreatedUTC: now, ExpiresUTC: expires,
+	}, hashAuthSecret(secret))
+	if err != nil {
+		return CreateEnrollmentTokenResult{}, WrapInternal("create enrollment token", err)
+	}
+	return CreateEnrollmentTokenResult{Token: token, Secret: secret}, nil
+}
+
+func (s *AgentCredentialService) ListEnrollmentTokens(ctx context.Context) ([]domain.AgentEnrollmentToken, error) {
+	if err := s.available(); err != nil {
+		return nil, err
+	}
+	if err := s.authorizer.Authorize(ctx, ActionManageAgents); err != nil {
+		return nil, err
+	}
+	tokens, err := s.repository.ListEnrollmentTokens(ctx)
+	if err != nil {
+		return nil, WrapInternal("list enrollment tokens", err)
+	}
+	return tokens, nil
+}
+
+func (s *AgentCredentialService) DeleteEnrollmentToken(ctx context.Context, id int64) error {
+	if err := s.available(); err != nil {
+		return err
+	}
+	if err := s.authorizer.Authorize(ctx, ActionManageAgents); err != nil {
+		return err
+	}
+	deleted, err := s.repository.DeleteEnrollmentToken(ctx, id)
+	if err != nil {
+		return WrapInternal("delete enrollment token", err)
+	}
+	if !deleted {
+		return NewError(ErrorNotFound, "enrollment token not found", nil)
+	}
+	return nil
+}
+
+// Enroll exchanges a one-time enrollment token for a credential bound to
+// the agent ID. The credential is only returned here.
+func (s *AgentCredentialService) Enroll(ctx context.Context, request EnrollAgentRequest) (EnrollAgentResult, error) {
+	if err := s.available(); err != nil {
+		return EnrollAgentResult{}, err
+	}
+	agentID := strings.TrimSpace(request.AgentID)
+	if agentID == "" || len(agentID) > maxAgentIDLength {
+		return EnrollAgentResult{}, NewError(ErrorInvalidArgument, "agent id is required and must be at most 200 characters", nil)
+	}
+	token := strings.TrimSpace(request.Token)
+	if !strings.HasPrefix(token, AgentEnrollmentTokenPrefix) {
+		return EnrollAgentResult{}, NewError(ErrorUnauthenticated, "an agent enrollment token is required", nil)
+	}
+	credential, err := newAuthSecret(AgentCredentialPrefix)
+	if err != nil {
+		return EnrollAgentResult{}, err
+	}
+	enrolled, err := s.repository.EnrollAgent(ctx, hashAuthSecret(token), agentID, hashAuthSecret(credential), s.now())
+	if err != nil {
+		return EnrollAgentResult{}, WrapInternal("enroll agent", err)
+	}
+	if !enrolled {
+		return EnrollAgentResult{}, NewError(ErrorUnauthenticated, "enrollment token expired, used or invalid", nil)
+	}
+	return EnrollAgentResult{AgentID: agentID, Credential: credential}, nil
+}
+
+// AuthenticateAgent checks a request that claims to come from agentID.
+// credential may be empty for agents that never enrolled, but only until the
+// first enrollment token is created: from then on an agent ID alone could be
+// claimed by anyone.
+func (s *AgentCredentialService) AuthenticateAgent(ctx context.Context, agentID, credential string) error {
+	if err := s.available(); err != nil {
+		return err
+	}
+	agentID = strings.TrimSpace(agentID)
+	credential = strings.TrimSpace(credential)
+	bound, storedHash, found, err := s.repository.GetAgentCredential(ctx, agentID)
+	if err != nil {
+		return WrapInternal("get agent credential", err)
+	}
+	if credential == "" {
+		switch {
+		case found:
+			return NewError(ErrorUnauthenticated, "agent "+agentID+" is enrolled and must present its credential", nil)
+		case s.required:
+			return NewError(ErrorUnauthenticated, "agent enrollment is required; start the agent with an enrollment token", nil)
+		}
+		inUse, err := s.repository.EnrollmentInUse(ctx)
+		if err != nil {
+			return WrapInternal("check agent enrollment", err)
+		}
+		if inUse {
+			return NewError(ErrorUnauthenticated, "this server enrolls agents; start agent "+agentID+" with an enrollment token", nil)
+		}
+		return nil
+	}
+	if !found || bound.Revoked() || subtle.ConstantTimeCompare([]byte(storedHash), []byte(hashAuthSecret(credential))) != 1 {
+		return NewError(ErrorUnauthenticated, "agent credential is revoked or does not belong to agent "+agentID, nil)
+	}
+	return nil
+}
+
+// RevokeCredential invalidates the agent's credential. The agent ID stays
+// bound, so requests claiming it are rejected until it enrolls again.
+func (s *AgentCredentialService) RevokeCredential(ctx context.Context, agentID string) error {
+	if err := s.available(); err != nil {
+		return err
+	}
+	if err := s.authorizer.Authorize(ctx, ActionManageAgents); err != nil {
+		return err
+	}
+	agentID = strings.TrimSpace(agentID)
+	if agentID == "" {
+		return NewError(ErrorInvalidArgument, "agent id is required", nil)
+	}
+	if err := s.repository.RevokeAgentCredential(ctx, agentID, s.now()); err != nil {
+		return WrapInternal("revoke agent credential", err)
+	}
+	return nil
+}
+
+func (s *AgentCredentialService) available() error {
+	if s == nil || s.repository == nil {
+		return NewError(ErrorUnavailable, "agent credential service unavailable", nil)
+	}
+	return nil
+}
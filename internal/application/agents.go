@@ -5,6 +5,7 @@ import (
 	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
+	"fmt"
 	"strings"
 
 	"github.com/izzyreal/ciwi/internal/domain"
@@ -14,16 +15,19 @@ const agentActionOperation = "agent_action"
 const runAgentScriptOperation = "run_agent_script"
 
 const (
-	AgentActionAuthorize       = "authorize"
-	AgentActionUnauthorize     = "unauthorize"
-	AgentActionActivate        = "activate"
-	AgentActionDeactivate      = "deactivate"
-	AgentActionRefreshTools    = "refresh-tools"
-	AgentActionRestart         = "restart"
-	AgentActionUpdate          = "update"
-	AgentActionDelete          = "delete"
-	AgentActionWipeCache       = "wipe-cache"
-	AgentActionFlushJobHistory = "flush-job-history"
+	AgentActionAuthorize         = "authorize"
+	AgentActionUnauthorize       = "unauthorize"
+	AgentActionActivate          = "activate"
+	AgentActionDeactivate        = "deactivate"
+	AgentActionRefreshTools      = "refresh-tools"
+	AgentActionRestart           = "restart"
+	AgentActionUpdate            = "update"
+	AgentActionDelete            = "delete"
+	AgentActionWipeCache         = "wipe-cache"
+	AgentActionFlushJobHistory   = "flush-job-history"
+	AgentActionRevokeCredentials = "revoke-credentials"
+	AgentActionSetSlots          = "set-slots"
+	AgentActionSetLabels         = "set-labels"
 )
 
 type AgentRepository interface {
@@ -49,9 +53,14 @@ func (q *AgentQueries) ListAgents(ctx context.Context) ([]domain.Agent, error) {
 	return agents, nil
 }
 
+// AgentActionRequest carries Slots only for set-slots, where zero hands the
+// slot count back to the agent's own configuration, and Labels only for
+// set-labels, where they replace every label an admin assigned before.
 type AgentActionRequest struct {
 	AgentID        string
 	Action         string
+	Slots          int
+	Labels         map[string]string
 	IdempotencyKey string
 }
 
@@ -83,13 +92,14 @@ type AgentScriptMutator interface {
 }
 
 type AgentScriptCommands struct {
-	mutator  AgentScriptMutator
-	receipts CommandReceiptRepository
-	changes  *ChangeHub
+	mutator    AgentScriptMutator
+	receipts   CommandReceiptRepository
+	changes    *ChangeHub
+	authorizer *Authorizer
 }
 
-func NewAgentScriptCommands(mutator AgentScriptMutator, receipts CommandReceiptRepository, changes *ChangeHub) *AgentScriptCommands {
-	return &AgentScriptCommands{mutator: mutator, receipts: receipts, changes: changes}
+func NewAgentScriptCommands(mutator AgentScriptMutator, receipts CommandReceiptRepository, changes *ChangeHub, authorizer *Authorizer) *AgentScriptCommands {
+	return &AgentScriptCommands{mutator: mutator, receipts: receipts, changes: changes, authorizer: authorizer}
 }
 
 func (c *AgentScriptCommands) Run(ctx context.Context, request RunAgentScriptRequest) (RunAgentScriptResult, error) {
@@ -105,6 +115,9 @@ func (c *AgentScriptCommands) Run(ctx context.Context, request RunAgentScriptReq
 	if c == nil || c.mutator == nil {
 		return RunAgentScriptResult{}, NewError(ErrorUnavailable, "agent script runner unavailable", nil)
 	}
+	if err := c.authorizer.Authorize(ctx, ActionManageAgents); err != nil {
+		return RunAgentScriptResult{}, err
+	}
 	key, err := validateCommandKey(request.IdempotencyKey)
 	if err != nil {
 		return RunAgentScriptResult{}, err
@@ -134,13 +147,14 @@ type AgentMutator interface {
 }
 
 type AgentCommands struct {
-	mutator  AgentMutator
-	receipts CommandReceiptRepository
-	changes  *ChangeHub
+	mutator    AgentMutator
+	receipts   CommandReceiptRepository
+	changes    *ChangeHub
+	authorizer *Authorizer
 }
 
-func NewAgentCommands(mutator AgentMutator, receipts CommandReceiptRepository, changes *ChangeHub) *AgentCommands {
-	return &AgentCommands{mutator: mutator, receipts: receipts, changes: changes}
+func NewAgentCommands(mutator AgentMutator, receipts CommandReceiptRepository, changes *ChangeHub, authorizer *Authorizer) *AgentCommands {
+	return &AgentCommands{mutator: mutator, receipts: receipts, changes: changes, authorizer: authorizer}
 }
 
 func (c *AgentCommands) Execute(ctx context.Context, request AgentActionRequest) (AgentActionResult, error) {
@@ -152,9 +166,24 @@ func (c *AgentCommands) Execute(ctx context.Context, request AgentActionRequest)
 	if !supportedAgentAction(request.Action) {
 		return AgentActionResult{}, NewError(ErrorInvalidArgument, "unsupported agent action", nil)
 	}
+	if request.Action != AgentActionSetSlots {
+		request.Slots = 0
+	} else if request.Slots < 0 || request.Slots > domain.MaxAgentJobSlots {
+		return AgentActionResult{}, NewError(ErrorInvalidArgument, fmt.Sprintf("slots must be between 0 and %d", domain.MaxAgentJobSlots), nil)
+	}
+	if request.Action != AgentActionSetLabels {
+		request.Labels = nil
+	} else if labels, err := domain.NormalizeAgentLabels(request.Labels); err != nil {
+		return AgentActionResult{}, NewError(ErrorInvalidArgument, err.Error(), err)
+	} else {
+		request.Labels = labels
+	}
 	if c == nil || c.mutator == nil {
 		return AgentActionResult{}, NewError(ErrorUnavailable, "agent operator unavailable", nil)
 	}
+	if err := c.authorizer.Authorize(ctx, ActionManageAgents); err != nil {
+		return AgentActionResult{}, err
+	}
 	key, err := validateCommandKey(request.IdempotencyKey)
 	if err != nil {
 		return AgentActionResult{}, err
@@ -183,7 +212,7 @@ func supportedAgentAction(action string) bool {
 	switch action {
 	case AgentActionAuthorize, AgentActionUnauthorize, AgentActionActivate, AgentActionDeactivate,
 		AgentActionRefreshTools, AgentActionRestart, AgentActionUpdate, AgentActionDelete,
-		AgentActionWipeCache, AgentActionFlushJobHistory:
+		AgentActionWipeCache, AgentActionFlushJobHistory, AgentActionRevokeCredentials, AgentActionSetSlots, AgentActionSetLabels:
 		return true
 	default:
 		return false
@@ -44,7 +44,7 @@ func TestAgentQueriesDelegate(t *testing.T) {
 func TestAgentCommandsValidateExecuteAndPublish(t *testing.T) {
 	mutator := &agentMutatorStub{}
 	changes := NewChangeHub()
-	commands := NewAgentCommands(mutator, nil, changes)
+	commands := NewAgentCommands(mutator, nil, changes, nil)
 	watchCtx, cancel := context.WithCancel(t.Context())
 	defer cancel()
 	events := changes.Watch(watchCtx)
@@ -65,7 +65,7 @@ func TestAgentCommandsValidateExecuteAndPublish(t *testing.T) {
 func TestAgentScriptCommandsValidateNormalizeAndPublish(t *testing.T) {
 	mutator := &agentScriptMutatorStub{}
 	changes := NewChangeHub()
-	commands := NewAgentScriptCommands(mutator, nil, changes)
+	commands := NewAgentScriptCommands(mutator, nil, changes, nil)
 	watchCtx, cancel := context.WithCancel(t.Context())
 	defer cancel()
 	events := changes.Watch(watchCtx)
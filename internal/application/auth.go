@@ -0,0 +1,145 @@
+package application
+
+import (
+	"context"
+	"strings"
+
+	"github.com/izzyreal/ciwi/internal/domain"
+)
+
+// Action names a class of operations that share one access requirement.
+// Operations map onto actions through OperationAction, and the application
+// services check them with an Authorizer, so HTTP and CNP enforce the same
+// policy.
+type Action string
+
+const (
+	ActionView              Action = "view"
+	ActionRunPipelines      Action = "run_pipelines"
+	ActionControlExecutions Action = "control_executions"
+	ActionManageProjects    Action = "manage_projects"
+	ActionManageAgents      Action = "manage_agents"
+	ActionManageVault       Action = "manage_vault"
+	ActionManageUpdates     Action = "manage_updates"
+	ActionManageUsers       Action = "manage_users"
+	ActionAdminister        Action = "administer"
+)
+
+// RequiredRole returns the least role that may perform action. Unknown
+// actions require admin.
+func RequiredRole(action Action) domain.Role {
+	switch action {
+	case ActionView:
+		return domain.RoleViewer
+	case ActionRunPipelines, ActionControlExecutions:
+		return domain.RoleOperator
+	default:
+		return domain.RoleAdmin
+	}
+}
+
+type principalContextKey struct{}
+
+type systemCallerContextKey struct{}
+
+func WithPrincipal(ctx context.Context, principal domain.Principal) context.Context {
+	return context.WithValue(ctx, principalContextKey{}, principal)
+}
+
+func PrincipalFromContext(ctx context.Context) (domain.Principal, bool) {
+	principal, ok := ctx.Value(principalContextKey{}).(domain.Principal)
+	return principal, ok
+}
+
+// WithSystemCaller marks ctx as a trusted in-process caller such as the
+// scheduler. Such callers act without a principal even once the server has
+// user accounts; name identifies them in audit records.
+func WithSystemCaller(ctx context.Context, name string) context.Context {
+	return context.WithValue(ctx, systemCallerContextKey{}, strings.TrimSpace(name))
+}
+
+func systemCallerFromContext(ctx context.Context) (string, bool) {
+	name, ok := ctx.Value(systemCallerContextKey{}).(string)
+	return name, ok
+}
+
+// ActorName names the caller for audit records such as cancellations. Callers
+// without a principal or a system caller name are reported as "user".
+func ActorName(ctx context.Context) string {
+	if principal, ok := PrincipalFromContext(ctx); ok {
+		if name := strings.TrimSpace(principal.Username); name != "" {
+			return name
+		}
+	}
+	if name, ok := systemCallerFromContext(ctx); ok && name != "" {
+		return name
+	}
+	return "user"
+}
+
+// AuthenticationState reports whether the server has user accounts.
+type AuthenticationState interface {
+	Enabled(context.Context) (bool, error)
+}
+
+// Authorizer checks the role policy inside the application services, so a
+// call that bypasses transport authentication is still denied.
+type Authorizer struct {
+	state AuthenticationState
+}
+
+func NewAuthorizer(state AuthenticationState) *Authorizer {
+	return &Authorizer{state: state}
+}
+
+// Authorize checks that the caller in ctx may perform action. Once the server
+// has user accounts a caller needs a principal, unless it is a system caller.
+// A nil Authorizer, as in tests, belongs to a server without accounts.
+func (a *Authorizer) Authorize(ctx context.Context, action Action) error {
+	principal, ok := PrincipalFromContext(ctx)
+	if !ok {
+		if _, system := systemCallerFromContext(ctx); system || a == nil || a.state == nil {
+			return nil
+		}
+		enabled, err := a.state.Enabled(ctx)
+		if err != nil {
+			return err
+		}
+		if enabled {
+			return NewError(ErrorUnauthenticated, "authentication required", nil)
+		}
+		return nil
+	}
+	if principal.UserID == 0 || principal.Role == "" {
+		return NewError(ErrorUnauthenticated, "authentication required", nil)
+	}
+	if !principal.Role.Allows(RequiredRole(action)) {
+		return NewError(ErrorPermissionDenied, "role "+string(principal.Role)+" may not "+actionDescription(action), nil)
+	}
+	return nil
+}
+
+func actionDescription(action Action) string {
+	switch action {
+	case ActionView:
+		return "view this resource"
+	case ActionRunPipelines:
+		return "run pipelines"
+	case ActionControlExecutions:
+		return "cancel, rerun or remove job executions"
+	case ActionManageProjects:
+		return "manage projects"
+	case ActionManageAgents:
+		return "manage agents"
+	case ActionManageVault:
+		return "manage Vault connections"
+	case ActionManageUpdates:
+		return "manage server updates"
+	case ActionManageUsers:
+		return "manage users"
+	case ActionAdminister:
+		return "administer the server"
+	default:
+		return string(action)
+	}
+}
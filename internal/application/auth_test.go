@@ -0,0 +1,78 @@
+package application
+
+import (
+	"context"
+	"testing"
+
+	"github.com/izzyreal/ciwi/internal/domain"
+)
+
+func TestAuthorizeAppliesRoleHierarchy(t *testing.T) {
+	viewer := WithPrincipal(context.Background(), domain.Principal{UserID: 1, Username: "v", Role: domain.RoleViewer})
+	operator := WithPrincipal(context.Background(), domain.Principal{UserID: 2, Username: "o", Role: domain.RoleOperator})
+	admin := WithPrincipal(context.Background(), domain.Principal{UserID: 3, Username: "a", Role: domain.RoleAdmin})
+	tests := []struct {
+		name   string
+		ctx    context.Context
+		action Action
+		want   ErrorKind
+	}{
+		{"viewer views", viewer, ActionView, ""},
+		{"viewer cannot run", viewer, ActionRunPipelines, ErrorPermissionDenied},
+		{"operator runs", operator, ActionRunPipelines, ""},
+		{"operator cancels", operator, ActionControlExecutions, ""},
+		{"operator cannot manage agents", operator, ActionManageAgents, ErrorPermissionDenied},
+		{"operator cannot manage vault", operator, ActionManageVault, ErrorPermissionDenied},
+		{"admin manages updates", admin, ActionManageUpdates, ""},
+		{"admin manages projects", admin, ActionManageProjects, ""},
+		{"system caller", WithSystemCaller(context.Background(), "scheduler"), ActionManageUsers, ""},
+		{"missing principal", context.Background(), ActionView, ErrorUnauthenticated},
+		{"anonymous principal", WithPrincipal(context.Background(), domain.Principal{}), ActionView, ErrorUnauthenticated},
+	}
+	authorizer := NewAuthorizer(authenticationStateStub(true))
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := authorizer.Authorize(tt.ctx, tt.action)
+			if tt.want == "" {
+				if err != nil {
+					t.Fatalf("expected access, got %v", err)
+				}
+				return
+			}
+			if got := ErrorKindOf(err); err == nil || got != tt.want {
+				t.Fatalf("expected %s, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+type authenticationStateStub bool
+
+func (s authenticationStateStub) Enabled(context.Context) (bool, error) { return bool(s), nil }
+
+func TestAuthorizeAllowsMissingPrincipalWithoutAccounts(t *testing.T) {
+	for name, authorizer := range map[string]*Authorizer{"no accounts": NewAuthorizer(authenticationStateStub(false)), "nil": nil} {
+		if err := authorizer.Authorize(context.Background(), ActionAdminister); err != nil {
+			t.Fatalf("%s: expected an open server to allow callers without a principal, got %v", name, err)
+		}
+	}
+}
+
+func TestCommandsEnforceAuthorization(t *testing.T) {
+	viewer := WithPrincipal(context.Background(), domain.Principal{UserID: 1, Username: "v", Role: domain.RoleViewer})
+	runner := &pipelineRunnerStub{}
+	if _, err := NewPipelineCommands(runner, nil, nil, nil).RunPipeline(viewer, RunPipelineRequest{PipelineDBID: 1}); ErrorKindOf(err) != ErrorPermissionDenied {
+		t.Fatalf("expected viewer run to be denied, got %v", err)
+	}
+	if runner.calls != 0 {
+		t.Fatal("denied command reached the runner")
+	}
+	authorizer := NewAuthorizer(authenticationStateStub(true))
+	if _, err := NewPipelineCommands(runner, nil, nil, authorizer).RunPipeline(context.Background(), RunPipelineRequest{PipelineDBID: 1}); ErrorKindOf(err) != ErrorUnauthenticated || runner.calls != 0 {
+		t.Fatalf("expected a call without a principal to be denied once accounts exist, calls=%d err=%v", runner.calls, err)
+	}
+	operator := WithPrincipal(context.Background(), domain.Principal{UserID: 2, Username: "o", Role: domain.RoleOperator})
+	if _, err := NewPipelineCommands(runner, nil, nil, nil).RunPipeline(operator, RunPipelineRequest{PipelineDBID: 1}); err != nil || runner.calls != 1 {
+		t.Fatalf("expected operator run to pass, calls=%d err=%v", runner.calls, err)
+	}
+}
@@ -0,0 +1,48 @@
+package application
+
+import (
+	"context"
+
+	"github.com/izzyreal/ciwi/internal/domain"
+)
+
+const coverageTrendRuns = 20
+
+// CoverageTrendRepository lists indexed coverage totals of a project, grouped
+// by pipeline job and matrix entry and newest first within each.
+type CoverageTrendRepository interface {
+	ListCoverageRuns(ctx context.Context, projectID int64, perScopeLimit int) ([]domain.CoverageRun, error)
+}
+
+type CoverageTrendQueries struct {
+	repository CoverageTrendRepository
+	authorizer *Authorizer
+}
+
+func NewCoverageTrendQueries(repository CoverageTrendRepository, authorizer *Authorizer) *CoverageTrendQueries {
+	return &CoverageTrendQueries{repository: repository, authorizer: authorizer}
+}
+
+func (q *CoverageTrendQueries) ListCoverageTrends(ctx context.Context, projectID int64) ([]domain.CoverageTrend, error) {
+	if q == nil || q.repository == nil {
+		return nil, NewError(ErrorUnavailable, "coverage trend repository unavailable", nil)
+	}
+	if projectID <= 0 {
+		return nil, NewError(ErrorInvalidArgument, "project id is required", nil)
+	}
+	if err := q.authorizer.Authorize(ctx, ActionView); err != nil {
+		return nil, err
+	}
+	runs, err := q.repository.ListCoverageRuns(ctx, projectID, coverageTrendRuns)
+	if err != nil {
+		return nil, WrapInternal("list coverage runs", err)
+	}
+	trends := []domain.CoverageTrend{}
+	for _, run := range runs {
+		if len(trends) == 0 || trends[len(trends)-1].Scope != run.Scope {
+			trends = append(trends, domain.CoverageTrend{Scope: run.Scope})
+		}
+		trends[len(trends)-1].Runs = append(trends[len(trends)-1].Runs, run)
+	}
+	return trends, nil
+}
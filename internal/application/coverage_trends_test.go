@@ -0,0 +1,35 @@
+package application
+
+import (
+	"context"
+	"testing"
+
+	"github.com/izzyreal/ciwi/internal/domain"
+)
+
+type coverageTrendRepositoryStub struct {
+	runs []domain.CoverageRun
+}
+
+func (s coverageTrendRepositoryStub) ListCoverageRuns(context.Context, int64, int) ([]domain.CoverageRun, error) {
+	return s.runs, nil
+}
+
+func TestListCoverageTrendsGroupsRunsByScope(t *testing.T) {
+	unit := domain.TestHistoryScope{ProjectID: 1, PipelineID: "build", PipelineJobID: "unit"}
+	linux := domain.TestHistoryScope{ProjectID: 1, PipelineID: "build", PipelineJobID: "e2e", MatrixName: "linux"}
+	trends, err := NewCoverageTrendQueries(coverageTrendRepositoryStub{runs: []domain.CoverageRun{
+		{JobExecutionID: "j3", Scope: unit, Percent: 81},
+		{JobExecutionID: "j1", Scope: unit, Percent: 80},
+		{JobExecutionID: "j2", Scope: linux, Percent: 60},
+	}}, nil).ListCoverageTrends(context.Background(), 1)
+	if err != nil {
+		t.Fatalf("list coverage trends: %v", err)
+	}
+	if len(trends) != 2 || trends[0].Scope != unit || len(trends[0].Runs) != 2 || trends[1].Scope != linux || trends[1].Runs[0].JobExecutionID != "j2" {
+		t.Fatalf("unexpected trends %+v", trends)
+	}
+	if _, err := NewCoverageTrendQueries(coverageTrendRepositoryStub{}, nil).ListCoverageTrends(context.Background(), 0); err == nil {
+		t.Fatal("expected a project id to be required")
+	}
+}
@@ -14,6 +14,8 @@ const (
 	ErrorFailedPrecondition ErrorKind = "failed_precondition"
 	ErrorUnavailable        ErrorKind = "unavailable"
 	ErrorUnsupported        ErrorKind = "unsupported"
+	ErrorUnauthenticated    ErrorKind = "unauthenticated"
+	ErrorPermissionDenied   ErrorKind = "permission_denied"
 	ErrorInternal           ErrorKind = "internal"
 )
 
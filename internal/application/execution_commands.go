@@ -52,9 +52,10 @@ type ExecutionMutator interface {
 }
 
 type ExecutionCommands struct {
-	mutator  ExecutionMutator
-	receipts CommandReceiptRepository
-	changes  *ChangeHub
+	mutator    ExecutionMutator
+	receipts   CommandReceiptRepository
+	changes    *ChangeHub
+	authorizer *Authorizer
 }
 
 func (c *ExecutionCommands) RemoveQueued(ctx context.Context, request RemoveQueuedExecutionRequest) (RemoveQueuedExecutionResult, error) {
@@ -65,6 +66,9 @@ func (c *ExecutionCommands) RemoveQueued(ctx context.Context, request RemoveQueu
 	if jobID == "" {
 		return RemoveQueuedExecutionResult{}, NewError(ErrorInvalidArgument, "job execution id is required", nil)
 	}
+	if err := c.authorizer.Authorize(ctx, ActionControlExecutions); err != nil {
+		return RemoveQueuedExecutionResult{}, err
+	}
 	key, err := validateCommandKey(request.IdempotencyKey)
 	if err != nil {
 		return RemoveQueuedExecutionResult{}, err
@@ -88,14 +92,17 @@ func (c *ExecutionCommands) RemoveQueued(ctx context.Context, request RemoveQueu
 	return executeIdempotentCommand(ctx, c.receipts, key, removeQueuedExecutionOperation, executionControlFingerprint(jobID), execute)
 }
 
-func NewExecutionCommands(mutator ExecutionMutator, receipts CommandReceiptRepository, changes *ChangeHub) *ExecutionCommands {
-	return &ExecutionCommands{mutator: mutator, receipts: receipts, changes: changes}
+func NewExecutionCommands(mutator ExecutionMutator, receipts CommandReceiptRepository, changes *ChangeHub, authorizer *Authorizer) *ExecutionCommands {
+	return &ExecutionCommands{mutator: mutator, receipts: receipts, changes: changes, authorizer: authorizer}
 }
 
 func (c *ExecutionCommands) ClearQueue(ctx context.Context, request ClearExecutionQueueRequest) (ClearExecutionQueueResult, error) {
 	if c == nil || c.mutator == nil {
 		return ClearExecutionQueueResult{}, NewError(ErrorUnavailable, "execution mutator unavailable", nil)
 	}
+	if err := c.authorizer.Authorize(ctx, ActionControlExecutions); err != nil {
+		return ClearExecutionQueueResult{}, err
+	}
 	key, err := validateCommandKey(request.IdempotencyKey)
 	if err != nil {
 		return ClearExecutionQueueResult{}, err
@@ -117,6 +124,9 @@ func (c *ExecutionCommands) FlushHistory(ctx context.Context, request FlushExecu
 	if c == nil || c.mutator == nil {
 		return FlushExecutionHistoryResult{}, NewError(ErrorUnavailable, "execution mutator unavailable", nil)
 	}
+	if err := c.authorizer.Authorize(ctx, ActionControlExecutions); err != nil {
+		return FlushExecutionHistoryResult{}, err
+	}
 	key, err := validateCommandKey(request.IdempotencyKey)
 	if err != nil {
 		return FlushExecutionHistoryResult{}, err
@@ -39,7 +39,7 @@ func TestExecutionCommandsAreIdempotentAndPublishChanges(t *testing.T) {
 	mutator := &executionMutatorStub{cleared: 3, deleted: []string{"job-1", "job-2"}}
 	receipts := newReceiptRepositoryStub()
 	changes := NewChangeHub()
-	commands := NewExecutionCommands(mutator, receipts, changes)
+	commands := NewExecutionCommands(mutator, receipts, changes, nil)
 
 	first, err := commands.ClearQueue(t.Context(), ClearExecutionQueueRequest{IdempotencyKey: "clear-1"})
 	if err != nil {
@@ -85,7 +85,7 @@ func TestExecutionCommandsAreIdempotentAndPublishChanges(t *testing.T) {
 }
 
 func TestExecutionCommandsValidateFlushScope(t *testing.T) {
-	commands := NewExecutionCommands(&executionMutatorStub{}, nil, nil)
+	commands := NewExecutionCommands(&executionMutatorStub{}, nil, nil, nil)
 	tests := []FlushExecutionHistoryRequest{
 		{All: true, JobExecutionIDs: []string{"job-1"}},
 	}
@@ -98,7 +98,7 @@ func TestExecutionCommandsValidateFlushScope(t *testing.T) {
 
 func TestExecutionCommandsFlushAll(t *testing.T) {
 	mutator := &executionMutatorStub{deleted: []string{"job-1"}}
-	commands := NewExecutionCommands(mutator, nil, nil)
+	commands := NewExecutionCommands(mutator, nil, nil, nil)
 	result, err := commands.FlushHistory(t.Context(), FlushExecutionHistoryRequest{All: true})
 	if err != nil {
 		t.Fatal(err)
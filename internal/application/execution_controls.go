@@ -37,14 +37,15 @@ type ExecutionControlCommands struct {
 	controller ExecutionController
 	receipts   CommandReceiptRepository
 	changes    *ChangeHub
+	authorizer *Authorizer
 }
 
-func NewExecutionControlCommands(controller ExecutionController, receipts CommandReceiptRepository, changes *ChangeHub) *ExecutionControlCommands {
-	return &ExecutionControlCommands{controller: controller, receipts: receipts, changes: changes}
+func NewExecutionControlCommands(controller ExecutionController, receipts CommandReceiptRepository, changes *ChangeHub, authorizer *Authorizer) *ExecutionControlCommands {
+	return &ExecutionControlCommands{controller: controller, receipts: receipts, changes: changes, authorizer: authorizer}
 }
 
 func (c *ExecutionControlCommands) Cancel(ctx context.Context, request ExecutionControlRequest) (CancelExecutionResult, error) {
-	jobID, key, err := validateExecutionControlRequest(c, request)
+	jobID, key, err := validateExecutionControlRequest(ctx, c, request)
 	if err != nil {
 		return CancelExecutionResult{}, err
 	}
@@ -62,7 +63,7 @@ func (c *ExecutionControlCommands) Cancel(ctx context.Context, request Execution
 }
 
 func (c *ExecutionControlCommands) Rerun(ctx context.Context, request ExecutionControlRequest) (RerunExecutionResult, error) {
-	jobID, key, err := validateExecutionControlRequest(c, request)
+	jobID, key, err := validateExecutionControlRequest(ctx, c, request)
 	if err != nil {
 		return RerunExecutionResult{}, err
 	}
@@ -79,7 +80,7 @@ func (c *ExecutionControlCommands) Rerun(ctx context.Context, request ExecutionC
 	return executeIdempotentCommand(ctx, c.receipts, key, rerunExecutionOperation, executionControlFingerprint(jobID), execute)
 }
 
-func validateExecutionControlRequest(c *ExecutionControlCommands, request ExecutionControlRequest) (string, string, error) {
+func validateExecutionControlRequest(ctx context.Context, c *ExecutionControlCommands, request ExecutionControlRequest) (string, string, error) {
 	if c == nil || c.controller == nil {
 		return "", "", NewError(ErrorUnavailable, "execution controller unavailable", nil)
 	}
@@ -87,6 +88,9 @@ func validateExecutionControlRequest(c *ExecutionControlCommands, request Execut
 	if jobID == "" {
 		return "", "", NewError(ErrorInvalidArgument, "job execution id is required", nil)
 	}
+	if err := c.authorizer.Authorize(ctx, ActionControlExecutions); err != nil {
+		return "", "", err
+	}
 	key, err := validateCommandKey(request.IdempotencyKey)
 	return jobID, key, err
 }
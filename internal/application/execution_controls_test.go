@@ -23,7 +23,7 @@ func (s *executionControllerStub) RerunExecution(_ context.Context, jobID string
 func TestExecutionControlCommandsAreIdempotent(t *testing.T) {
 	controller := &executionControllerStub{}
 	hub := NewChangeHub()
-	commands := NewExecutionControlCommands(controller, newReceiptRepositoryStub(), hub)
+	commands := NewExecutionControlCommands(controller, newReceiptRepositoryStub(), hub, nil)
 	request := ExecutionControlRequest{JobExecutionID: "job-1", IdempotencyKey: "cancel-1"}
 	first, err := commands.Cancel(t.Context(), request)
 	if err != nil {
@@ -48,7 +48,7 @@ func TestExecutionControlCommandsAreIdempotent(t *testing.T) {
 }
 
 func TestExecutionControlCommandsValidateJobID(t *testing.T) {
-	commands := NewExecutionControlCommands(&executionControllerStub{}, nil, nil)
+	commands := NewExecutionControlCommands(&executionControllerStub{}, nil, nil, nil)
 	if _, err := commands.Cancel(t.Context(), ExecutionControlRequest{}); ErrorKindOf(err) != ErrorInvalidArgument {
 		t.Fatalf("Cancel() error = %v", err)
 	}
@@ -126,6 +126,9 @@ func (q *ExecutionQueries) GetJobExecutionDetails(ctx context.Context, jobID str
 	if err != nil {
 		return domain.JobExecutionDetails{}, WrapInternal("get job execution details", err)
 	}
+	if repository, ok := q.repository.(TestHistoryRepository); ok {
+		attachTestHistoryBadges(ctx, repository, &details)
+	}
 	return details, nil
 }
 
@@ -0,0 +1,134 @@
+package application
+
+// Operation names one thing a client can ask the server to do. The names of
+// native operations are the field names of the CNP request oneof; the rest
+// only exist on the HTTP API. Both transports authorize requests through
+// OperationAction, so one table holds the role policy.
+type Operation string
+
+const (
+	OperationGetServerInfo            Operation = "get_server_info"
+	OperationListProjects             Operation = "list_projects"
+	OperationGetFrontPageView         Operation = "get_front_page_view"
+	OperationRunPipeline              Operation = "run_pipeline"
+	OperationWatchChanges             Operation = "watch_changes"
+	OperationGetProjectDetails        Operation = "get_project_details"
+	OperationGetJobDetails            Operation = "get_job_details"
+	OperationWatchJobOutput           Operation = "watch_job_output"
+	OperationClearExecutionQueue      Operation = "clear_execution_queue"
+	OperationFlushExecutionHistory    Operation = "flush_execution_history"
+	OperationCancelExecution          Operation = "cancel_execution"
+	OperationRerunExecution           Operation = "rerun_execution"
+	OperationRunPipelineChain         Operation = "run_pipeline_chain"
+	OperationGetRunOptions            Operation = "get_run_options"
+	OperationGetAgentsView            Operation = "get_agents_view"
+	OperationAgentAction              Operation = "agent_action"
+	OperationProjectAction            Operation = "project_action"
+	OperationImportProject            Operation = "import_project"
+	OperationGetServerUpdateStatus    Operation = "get_server_update_status"
+	OperationCheckServerUpdates       Operation = "check_server_updates"
+	OperationListServerUpdateVersions Operation = "list_server_update_versions"
+	OperationServerUpdateAction       Operation = "server_update_action"
+	OperationRemoveQueuedExecution    Operation = "remove_queued_execution"
+	OperationGetAgentDetails          Operation = "get_agent_details"
+	OperationGetCommandReceiptStatus  Operation = "get_command_receipt_status"
+	OperationRunAgentScript           Operation = "run_agent_script"
+	OperationGetManagedYAML           Operation = "get_managed_yaml"
+	OperationValidateManagedYAML      Operation = "validate_managed_yaml"
+	OperationSaveManagedYAML          Operation = "save_managed_yaml"
+	OperationListVaultConnections     Operation = "list_vault_connections"
+	OperationUpsertVaultConnection    Operation = "upsert_vault_connection"
+	OperationTestVaultConnection      Operation = "test_vault_connection"
+	OperationDeleteVaultConnection    Operation = "delete_vault_connection"
+	OperationDownloadArtifact         Operation = "download_artifact"
+	OperationGetProjectIcons          Operation = "get_project_icons"
+	OperationGetJobLogDescriptor      Operation = "get_job_log_descriptor"
+	OperationGetJobLogPage            Operation = "get_job_log_page"
+	OperationSearchJobLog             Operation = "search_job_log"
+	OperationWatchJobLog              Operation = "watch_job_log"
+	OperationGetTestHistory           Operation = "get_test_history"
+	OperationAddTestQuarantine        Operation = "add_test_quarantine"
+	OperationRemoveTestQuarantine     Operation = "remove_test_quarantine"
+
+	// HTTP only.
+	OperationRead                        Operation = "read"
+	OperationPreviewPipeline             Operation = "preview_pipeline"
+	OperationCreateJobExecution          Operation = "create_job_execution"
+	OperationManageProjectWebhook        Operation = "manage_project_webhook"
+	OperationManageAgentEnrollmentTokens Operation = "manage_agent_enrollment_tokens"
+	OperationManageRepoCredentials       Operation = "manage_repo_credentials"
+	OperationRestartServer               Operation = "restart_server"
+)
+
+var operationActions = map[Operation]Action{
+	OperationGetServerInfo:            ActionView,
+	OperationListProjects:             ActionView,
+	OperationGetFrontPageView:         ActionView,
+	OperationWatchChanges:             ActionView,
+	OperationGetProjectDetails:        ActionView,
+	OperationGetJobDetails:            ActionView,
+	OperationWatchJobOutput:           ActionView,
+	OperationGetRunOptions:            ActionView,
+	OperationGetAgentsView:            ActionView,
+	OperationGetServerUpdateStatus:    ActionView,
+	OperationListServerUpdateVersions: ActionView,
+	OperationGetAgentDetails:          ActionView,
+	OperationGetCommandReceiptStatus:  ActionView,
+	OperationGetManagedYAML:           ActionView,
+	OperationListVaultConnections:     ActionView,
+	OperationDownloadArtifact:         ActionView,
+	OperationGetProjectIcons:          ActionView,
+	OperationGetJobLogDescriptor:      ActionView,
+	OperationGetJobLogPage:            ActionView,
+	OperationSearchJobLog:             ActionView,
+	OperationWatchJobLog:              ActionView,
+	OperationGetTestHistory:           ActionView,
+	OperationRead:                     ActionView,
+	OperationPreviewPipeline:          ActionView,
+
+	OperationRunPipeline:        ActionRunPipelines,
+	OperationRunPipelineChain:   ActionRunPipelines,
+	OperationCreateJobExecution: ActionRunPipelines,
+
+	OperationCancelExecution:       ActionControlExecutions,
+	OperationRerunExecution:        ActionControlExecutions,
+	OperationRemoveQueuedExecution: ActionControlExecutions,
+	OperationClearExecutionQueue:   ActionControlExecutions,
+	OperationFlushExecutionHistory: ActionControlExecutions,
+
+	OperationProjectAction:        ActionManageProjects,
+	OperationImportProject:        ActionManageProjects,
+	OperationValidateManagedYAML:  ActionManageProjects,
+	OperationSaveManagedYAML:      ActionManageProjects,
+	OperationAddTestQuarantine:    ActionManageProjects,
+	OperationRemoveTestQuarantine: ActionManageProjects,
+	OperationManageProjectWebhook: ActionManageProjects,
+
+	OperationAgentAction:                 ActionManageAgents,
+	OperationRunAgentScript:              ActionManageAgents,
+	OperationManageAgentEnrollmentTokens: ActionManageAgents,
+
+	OperationUpsertVaultConnection: ActionManageVault,
+	OperationTestVaultConnection:   ActionManageVault,
+	OperationDeleteVaultConnection: ActionManageVault,
+	OperationManageRepoCredentials: ActionManageVault,
+
+	OperationCheckServerUpdates: ActionManageUpdates,
+	OperationServerUpdateAction: ActionManageUpdates,
+	OperationRestartServer:      ActionManageUpdates,
+}
+
+// OperationAction returns the action that guards op. Unknown operations
+// require admin.
+func OperationAction(op Operation) Action {
+	if action, ok := operationActions[op]; ok {
+		return action
+	}
+	return ActionAdminister
+}
+
+// KnownOperation reports whether op is in the policy table.
+func KnownOperation(op Operation) bool {
+	_, ok := operationActions[op]
+	return ok
+}
@@ -0,0 +1,66 @@
+package application
+
+import (
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const passwordHashScheme = "pbkdf2-sha256"
+
+// passwordHashIterations follows the OWASP recommendation for
+// PBKDF2-HMAC-SHA256. Tests lower it.
+var passwordHashIterations = 600_000
+
+// hashPassword encodes a password as pbkdf2-sha256$<iterations>$<salt>$<key>.
+func hashPassword(password string) (string, error) {
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+	key, err := pbkdf2.Key(sha256.New, password, salt, passwordHashIterations, 32)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s$%d$%s$%s", passwordHashScheme, passwordHashIterations,
+		base64.RawStdEncoding.EncodeToString(salt), base64.RawStdEncoding.EncodeToString(key)), nil
+}
+
+func verifyPassword(encoded, password string) bool {
+	parts := strings.Split(encoded, "$")
+	if len(parts) != 4 || parts[0] != passwordHashScheme {
+		return false
+	}
+	iterations, err := strconv.Atoi(parts[1])
+	if err != nil || iterations <= 0 {
+		return false
+	}
+	salt, err := base64.RawStdEncoding.DecodeString(parts[2])
+	if err != nil {
+		return false
+	}
+	want, err := base64.RawStdEncoding.DecodeString(parts[3])
+	if err != nil || len(want) == 0 {
+		return false
+	}
+	got, err := pbkdf2.Key(sha256.New, password, salt, iterations, len(want))
+	return err == nil && subtle.ConstantTimeCompare(got, want) == 1
+}
+
+var (
+	dummyPasswordHashOnce  sync.Once
+	dummyPasswordHashValue string
+)
+
+func dummyPasswordHash() string {
+	dummyPasswordHashOnce.Do(func() {
+		dummyPasswordHashValue, _ = hashPassword("ciwi-unknown-user")
+	})
+	return dummyPasswordHashValue
+}
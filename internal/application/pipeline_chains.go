@@ -20,7 +20,11 @@ type RunPipelineChainRequest struct {
 	SourceRef      string
 	AgentID        string
 	ExecutionMode  string
+	Priority       *int
 	IdempotencyKey string
+	// Trigger names what started the run when it was not a person, e.g.
+	// "schedule".
+	Trigger string
 }
 
 type RunPipelineChainResult struct {
@@ -36,13 +40,14 @@ type PipelineChainRunner interface {
 }
 
 type PipelineChainCommands struct {
-	runner   PipelineChainRunner
-	receipts CommandReceiptRepository
-	changes  *ChangeHub
+	runner     PipelineChainRunner
+	receipts   CommandReceiptRepository
+	changes    *ChangeHub
+	authorizer *Authorizer
 }
 
-func NewPipelineChainCommands(runner PipelineChainRunner, receipts CommandReceiptRepository, changes *ChangeHub) *PipelineChainCommands {
-	return &PipelineChainCommands{runner: runner, receipts: receipts, changes: changes}
+func NewPipelineChainCommands(runner PipelineChainRunner, receipts CommandReceiptRepository, changes *ChangeHub, authorizer *Authorizer) *PipelineChainCommands {
+	return &PipelineChainCommands{runner: runner, receipts: receipts, changes: changes, authorizer: authorizer}
 }
 
 func (c *PipelineChainCommands) RunPipelineChain(ctx context.Context, request RunPipelineChainRequest) (RunPipelineChainResult, error) {
@@ -56,6 +61,9 @@ func (c *PipelineChainCommands) RunPipelineChain(ctx context.Context, request Ru
 	if c == nil || c.runner == nil {
 		return RunPipelineChainResult{}, NewError(ErrorUnavailable, "pipeline chain runner unavailable", nil)
 	}
+	if err := c.authorizer.Authorize(ctx, ActionRunPipelines); err != nil {
+		return RunPipelineChainResult{}, err
+	}
 	key, err := validateCommandKey(request.IdempotencyKey)
 	if err != nil {
 		return RunPipelineChainResult{}, err
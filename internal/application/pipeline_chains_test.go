@@ -19,7 +19,7 @@ func (s *pipelineChainRunnerStub) RunPipelineChain(_ context.Context, request Ru
 func TestPipelineChainCommandsDeduplicatesRun(t *testing.T) {
 	runner := &pipelineChainRunnerStub{}
 	hub := NewChangeHub()
-	commands := NewPipelineChainCommands(runner, newReceiptRepositoryStub(), hub)
+	commands := NewPipelineChainCommands(runner, newReceiptRepositoryStub(), hub, nil)
 	request := RunPipelineChainRequest{ProjectID: 7, ChainID: " build+release ", DryRun: true, IdempotencyKey: "chain-1"}
 	first, err := commands.RunPipelineChain(t.Context(), request)
 	if err != nil {
@@ -35,7 +35,7 @@ func TestPipelineChainCommandsDeduplicatesRun(t *testing.T) {
 }
 
 func TestPipelineChainCommandsValidateIdentity(t *testing.T) {
-	commands := NewPipelineChainCommands(&pipelineChainRunnerStub{}, nil, nil)
+	commands := NewPipelineChainCommands(&pipelineChainRunnerStub{}, nil, nil, nil)
 	for _, request := range []RunPipelineChainRequest{{ChainID: "build"}, {ProjectID: 1}} {
 		if _, err := commands.RunPipelineChain(t.Context(), request); ErrorKindOf(err) != ErrorInvalidArgument {
 			t.Fatalf("RunPipelineChain(%#v) error = %v", request, err)
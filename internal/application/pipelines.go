@@ -18,7 +18,12 @@ type RunPipelineRequest struct {
 	SourceRef      string
 	AgentID        string
 	ExecutionMode  string
+	Priority       *int
 	IdempotencyKey string
+	// Trigger names what started the run when it was not a person, e.g. "vcs".
+	Trigger string
+	// SourceCommit pins the run to an exact commit of the pipeline source.
+	SourceCommit string
 }
 
 type RunPipelineResult struct {
@@ -33,13 +38,14 @@ type PipelineRunner interface {
 }
 
 type PipelineCommands struct {
-	runner   PipelineRunner
-	receipts CommandReceiptRepository
-	changes  *ChangeHub
+	runner     PipelineRunner
+	receipts   CommandReceiptRepository
+	changes    *ChangeHub
+	authorizer *Authorizer
 }
 
-func NewPipelineCommands(runner PipelineRunner, receipts CommandReceiptRepository, changes *ChangeHub) *PipelineCommands {
-	return &PipelineCommands{runner: runner, receipts: receipts, changes: changes}
+func NewPipelineCommands(runner PipelineRunner, receipts CommandReceiptRepository, changes *ChangeHub, authorizer *Authorizer) *PipelineCommands {
+	return &PipelineCommands{runner: runner, receipts: receipts, changes: changes, authorizer: authorizer}
 }
 
 func (c *PipelineCommands) RunPipeline(ctx context.Context, request RunPipelineRequest) (RunPipelineResult, error) {
@@ -49,6 +55,9 @@ func (c *PipelineCommands) RunPipeline(ctx context.Context, request RunPipelineR
 	if c == nil || c.runner == nil {
 		return RunPipelineResult{}, NewError(ErrorUnavailable, "pipeline runner unavailable", nil)
 	}
+	if err := c.authorizer.Authorize(ctx, ActionRunPipelines); err != nil {
+		return RunPipelineResult{}, err
+	}
 	key, err := validateCommandKey(request.IdempotencyKey)
 	if err != nil {
 		return RunPipelineResult{}, err
@@ -56,7 +56,7 @@ func TestPipelineCommandsDeduplicatesCompletedCommand(t *testing.T) {
 	}}
 	receipts := newReceiptRepositoryStub()
 	hub := NewChangeHub()
-	commands := NewPipelineCommands(runner, receipts, hub)
+	commands := NewPipelineCommands(runner, receipts, hub, nil)
 	request := RunPipelineRequest{PipelineDBID: 7, DryRun: true, IdempotencyKey: "command-1"}
 
 	first, err := commands.RunPipeline(context.Background(), request)
@@ -80,7 +80,7 @@ func TestPipelineCommandsDeduplicatesCompletedCommand(t *testing.T) {
 
 func TestPipelineCommandsRejectsReusedKeyForDifferentRequest(t *testing.T) {
 	runner := &pipelineRunnerStub{result: RunPipelineResult{PipelineID: "build"}}
-	commands := NewPipelineCommands(runner, newReceiptRepositoryStub(), nil)
+	commands := NewPipelineCommands(runner, newReceiptRepositoryStub(), nil, nil)
 	if _, err := commands.RunPipeline(context.Background(), RunPipelineRequest{PipelineDBID: 1, IdempotencyKey: "same"}); err != nil {
 		t.Fatal(err)
 	}
@@ -92,7 +92,7 @@ func TestPipelineCommandsRejectsReusedKeyForDifferentRequest(t *testing.T) {
 
 func TestPipelineCommandsRejectsOversizedIdempotencyKey(t *testing.T) {
 	runner := &pipelineRunnerStub{}
-	commands := NewPipelineCommands(runner, newReceiptRepositoryStub(), nil)
+	commands := NewPipelineCommands(runner, newReceiptRepositoryStub(), nil, nil)
 	_, err := commands.RunPipeline(context.Background(), RunPipelineRequest{PipelineDBID: 1, IdempotencyKey: string(make([]byte, 201))})
 	if ErrorKindOf(err) != ErrorInvalidArgument || runner.calls != 0 {
 		t.Fatalf("error=%v runner calls=%d", err, runner.calls)
@@ -102,7 +102,7 @@ func TestPipelineCommandsRejectsOversizedIdempotencyKey(t *testing.T) {
 func TestPipelineCommandsReplaysFailureWithoutRepeatingCommand(t *testing.T) {
 	runner := &pipelineRunnerStub{err: errors.New("failed")}
 	receipts := newReceiptRepositoryStub()
-	commands := NewPipelineCommands(runner, receipts, nil)
+	commands := NewPipelineCommands(runner, receipts, nil, nil)
 	request := RunPipelineRequest{PipelineDBID: 1, IdempotencyKey: "retry"}
 	_, err := commands.RunPipeline(context.Background(), request)
 	if err == nil {
@@ -39,9 +39,24 @@ type ImportProjectResult struct {
 	Pipelines   int    `json:"pipelines"`
 }
 
+// ProjectWebhookSecretRequest rotates the secret that authenticates a
+// project's push webhooks, or clears it to disable the webhook.
+type ProjectWebhookSecretRequest struct {
+	ProjectID int64
+	Clear     bool
+}
+
+type ProjectWebhookSecretResult struct {
+	ProjectID int64
+	Secret    string
+}
+
 type ProjectMutator interface {
 	ExecuteProjectAction(context.Context, ProjectActionRequest) (ProjectActionResult, error)
 	ImportProject(context.Context, ImportProjectRequest) (ImportProjectResult, error)
+	// SetProjectWebhookSecret stores secret; an empty secret disables the
+	// webhook.
+	SetProjectWebhookSecret(ctx context.Context, projectID int64, secret string) error
 }
 
 func (c *ProjectCommands) Import(ctx context.Context, request ImportProjectRequest) (ImportProjectResult, error) {
@@ -57,6 +72,9 @@ func (c *ProjectCommands) Import(ctx context.Context, request ImportProjectReque
 	if c == nil || c.mutator == nil {
 		return ImportProjectResult{}, NewError(ErrorUnavailable, "project operator unavailable", nil)
 	}
+	if err := c.authorizer.Authorize(ctx, ActionManageProjects); err != nil {
+		return ImportProjectResult{}, err
+	}
 	key, err := validateCommandKey(request.IdempotencyKey)
 	if err != nil {
 		return ImportProjectResult{}, err
@@ -82,13 +100,14 @@ func (c *ProjectCommands) Import(ctx context.Context, request ImportProjectReque
 }
 
 type ProjectCommands struct {
-	mutator  ProjectMutator
-	receipts CommandReceiptRepository
-	changes  *ChangeHub
+	mutator    ProjectMutator
+	receipts   CommandReceiptRepository
+	changes    *ChangeHub
+	authorizer *Authorizer
 }
 
-func NewProjectCommands(mutator ProjectMutator, receipts CommandReceiptRepository, changes *ChangeHub) *ProjectCommands {
-	return &ProjectCommands{mutator: mutator, receipts: receipts, changes: changes}
+func NewProjectCommands(mutator ProjectMutator, receipts CommandReceiptRepository, changes *ChangeHub, authorizer *Authorizer) *ProjectCommands {
+	return &ProjectCommands{mutator: mutator, receipts: receipts, changes: changes, authorizer: authorizer}
 }
 
 func (c *ProjectCommands) Execute(ctx context.Context, request ProjectActionRequest) (ProjectActionResult, error) {
@@ -99,6 +118,9 @@ func (c *ProjectCommands) Execute(ctx context.Context, request ProjectActionRequ
 	if c == nil || c.mutator == nil {
 		return ProjectActionResult{}, NewError(ErrorUnavailable, "project operator unavailable", nil)
 	}
+	if err := c.authorizer.Authorize(ctx, ActionManageProjects); err != nil {
+		return ProjectActionResult{}, err
+	}
 	key, err := validateCommandKey(request.IdempotencyKey)
 	if err != nil {
 		return ProjectActionResult{}, err
@@ -122,3 +144,32 @@ func (c *ProjectCommands) Execute(ctx context.Context, request ProjectActionRequ
 	sum := sha256.Sum256(payload)
 	return executeIdempotentCommand(ctx, c.receipts, key, "project_action", hex.EncodeToString(sum[:]), execute)
 }
+
+// SetWebhookSecret generates a new webhook secret for a project, or clears
+// it. The secret is only returned here; callers cannot read it back later.
+func (c *ProjectCommands) SetWebhookSecret(ctx context.Context, request ProjectWebhookSecretRequest) (ProjectWebhookSecretResult, error) {
+	if request.ProjectID <= 0 {
+		return ProjectWebhookSecretResult{}, NewError(ErrorInvalidArgument, "a valid project is required", nil)
+	}
+	if c == nil || c.mutator == nil {
+		return ProjectWebhookSecretResult{}, NewError(ErrorUnavailable, "project operator unavailable", nil)
+	}
+	if err := c.authorizer.Authorize(ctx, ActionManageProjects); err != nil {
+		return ProjectWebhookSecretResult{}, err
+	}
+	result := ProjectWebhookSecretResult{ProjectID: request.ProjectID}
+	if !request.Clear {
+		secret, err := newAuthSecret("")
+		if err != nil {
+			return ProjectWebhookSecretResult{}, err
+		}
+		result.Secret = secret
+	}
+	if err := c.mutator.SetProjectWebhookSecret(ctx, request.ProjectID, result.Secret); err != nil {
+		return ProjectWebhookSecretResult{}, err
+	}
+	if c.changes != nil {
+		c.changes.Publish(ChangeProjects)
+	}
+	return result, nil
+}
@@ -3,9 +3,14 @@ package application
 import (
 	"context"
 	"testing"
+
+	"github.com/izzyreal/ciwi/internal/domain"
 )
 
-type projectMutatorStub struct{ request ProjectActionRequest }
+type projectMutatorStub struct {
+	request        ProjectActionRequest
+	webhookSecrets []string
+}
 
 func (s *projectMutatorStub) ExecuteProjectAction(_ context.Context, request ProjectActionRequest) (ProjectActionResult, error) {
 	s.request = request
@@ -16,10 +21,15 @@ func (s *projectMutatorStub) ImportProject(_ context.Context, request ImportProj
 	return ImportProjectResult{ProjectName: "ciwi", RepoURL: request.RepoURL, ConfigFile: request.ConfigFile}, nil
 }
 
+func (s *projectMutatorStub) SetProjectWebhookSecret(_ context.Context, _ int64, secret string) error {
+	s.webhookSecrets = append(s.webhookSecrets, secret)
+	return nil
+}
+
 func TestProjectCommandsValidateExecuteAndPublish(t *testing.T) {
 	mutator := &projectMutatorStub{}
 	changes := NewChangeHub()
-	commands := NewProjectCommands(mutator, nil, changes)
+	commands := NewProjectCommands(mutator, nil, changes, nil)
 	watchCtx, cancel := context.WithCancel(t.Context())
 	defer cancel()
 	events := changes.Watch(watchCtx)
@@ -38,7 +48,7 @@ func TestProjectCommandsValidateExecuteAndPublish(t *testing.T) {
 
 func TestProjectImportDefaultsConfigAndPublishes(t *testing.T) {
 	changes := NewChangeHub()
-	commands := NewProjectCommands(&projectMutatorStub{}, nil, changes)
+	commands := NewProjectCommands(&projectMutatorStub{}, nil, changes, nil)
 	watchCtx, cancel := context.WithCancel(t.Context())
 	defer cancel()
 	events := changes.Watch(watchCtx)
@@ -51,3 +61,30 @@ func TestProjectImportDefaultsConfigAndPublishes(t *testing.T) {
 		t.Fatalf("change=%+v", change)
 	}
 }
+
+func TestProjectWebhookSecretNeedsProjectManagers(t *testing.T) {
+	mutator := &projectMutatorStub{}
+	commands := NewProjectCommands(mutator, nil, nil, NewAuthorizer(authenticationStateStub(true)))
+	operator := WithPrincipal(context.Background(), domain.Principal{UserID: 1, Username: "o", Role: domain.RoleOperator})
+	admin := WithPrincipal(context.Background(), domain.Principal{UserID: 2, Username: "a", Role: domain.RoleAdmin})
+
+	if _, err := commands.SetWebhookSecret(context.Background(), ProjectWebhookSecretRequest{ProjectID: 7}); ErrorKindOf(err) != ErrorUnauthenticated {
+		t.Fatalf("rotate without a principal error = %v", err)
+	}
+	if _, err := commands.SetWebhookSecret(operator, ProjectWebhookSecretRequest{ProjectID: 7, Clear: true}); ErrorKindOf(err) != ErrorPermissionDenied {
+		t.Fatalf("operator clear error = %v", err)
+	}
+	if len(mutator.webhookSecrets) != 0 {
+		t.Fatalf("denied calls reached the mutator: %v", mutator.webhookSecrets)
+	}
+	result, err := commands.SetWebhookSecret(admin, ProjectWebhookSecretRequest{ProjectID: 7})
+	if err != nil || len(result.Secret) != 64 {
+		t.Fatalf("rotate = %+v, %v", result, err)
+	}
+	if _, err := commands.SetWebhookSecret(admin, ProjectWebhookSecretRequest{ProjectID: 7, Clear: true}); err != nil {
+		t.Fatal(err)
+	}
+	if len(mutator.webhookSecrets) != 2 || mutator.webhookSecrets[0] != result.Secret || mutator.webhookSecrets[1] != "" {
+		t.Fatalf("stored secrets = %v", mutator.webhookSecrets)
+	}
+}
@@ -0,0 +1,131 @@
+package application
+
+import (
+	"context"
+	"strings"
+)
+
+const (
+	RepoCredentialHTTPSToken = "https_token"
+	RepoCredentialSSHKey     = "ssh_key"
+
+	// RepoCredentialSecretEnvPrefix is the prefix every secret_env must
+	// carry, so a repository credential cannot hand other server
+	// environment variables, such as a Vault secret ID, to a git remote.
+	RepoCredentialSecretEnvPrefix = "CIWI_REPO_CRED_"
+)
+
+// RepoCredential authenticates git access to the repositories under
+// URLPrefix. It names where the secret lives, never the secret itself.
+type RepoCredential struct {
+	ID              int64
+	Name            string
+	URLPrefix       string
+	Kind            string
+	Username        string
+	SecretEnv       string
+	VaultConnection string
+	VaultSecret     *VaultSecretRef
+	KnownHosts      string
+}
+
+type UpsertRepoCredentialRequest struct {
+	Name            string
+	URLPrefix       string
+	Kind            string
+	Username        string
+	SecretEnv       string
+	VaultConnection string
+	VaultSecret     *VaultSecretRef
+	KnownHosts      string
+}
+
+type RepoCredentialRepository interface {
+	ListRepoCredentials(context.Context) ([]RepoCredential, error)
+	UpsertRepoCredential(context.Context, UpsertRepoCredentialRequest) (RepoCredential, error)
+	DeleteRepoCredential(context.Context, int64) error
+}
+
+// RepoCredentials manages the credentials the server and agents use for
+// private repositories. Changing them needs the Vault role because they
+// decide where secrets are sent.
+type RepoCredentials struct {
+	repository RepoCredentialRepository
+	authorizer *Authorizer
+}
+
+func NewRepoCredentials(repository RepoCredentialRepository, authorizer *Authorizer) *RepoCredentials {
+	return &RepoCredentials{repository: repository, authorizer: authorizer}
+}
+
+func (c *RepoCredentials) List(ctx context.Context) ([]RepoCredential, error) {
+	if c == nil || c.repository == nil {
+		return nil, NewError(ErrorUnavailable, "repo credential service unavailable", nil)
+	}
+	if err := c.authorizer.Authorize(ctx, ActionView); err != nil {
+		return nil, err
+	}
+	return c.repository.ListRepoCredentials(ctx)
+}
+
+func (c *RepoCredentials) Upsert(ctx context.Context, request UpsertRepoCredentialRequest) (RepoCredential, error) {
+	if c == nil || c.repository == nil {
+		return RepoCredential{}, NewError(ErrorUnavailable, "repo credential service unavailable", nil)
+	}
+	if err := c.authorizer.Authorize(ctx, ActionManageVault); err != nil {
+		return RepoCredential{}, err
+	}
+	if err := validateRepoCredentialRequest(request); err != nil {
+		return RepoCredential{}, err
+	}
+	return c.repository.UpsertRepoCredential(ctx, request)
+}
+
+func (c *RepoCredentials) Delete(ctx context.Context, id int64) error {
+	if c == nil || c.repository == nil {
+		return NewError(ErrorUnavailable, "repo credential service unavailable", nil)
+	}
+	if err := c.authorizer.Authorize(ctx, ActionManageVault); err != nil {
+		return err
+	}
+	if id <= 0 {
+		return NewError(ErrorInvalidArgument, "invalid repo credential id", nil)
+	}
+	return c.repository.DeleteRepoCredential(ctx, id)
+}
+
+// ValidRepoCredentialSecretEnv reports whether name may hold a repository
+// credential secret.
+func ValidRepoCredentialSecretEnv(name string) bool {
+	name = strings.TrimSpace(name)
+	return strings.HasPrefix(name, RepoCredentialSecretEnvPrefix) && len(name) > len(RepoCredentialSecretEnvPrefix)
+}
+
+func validateRepoCredentialRequest(req UpsertRepoCredentialRequest) error {
+	invalid := func(message string) error { return NewError(ErrorInvalidArgument, message, nil) }
+	if strings.TrimSpace(req.Name) == "" || strings.TrimSpace(req.URLPrefix) == "" {
+		return invalid("name and url_prefix are required")
+	}
+	switch strings.TrimSpace(req.Kind) {
+	case RepoCredentialHTTPSToken:
+		if strings.TrimSpace(req.KnownHosts) != "" {
+			return invalid("known_hosts only applies to ssh_key credentials")
+		}
+	case RepoCredentialSSHKey:
+	default:
+		return invalid("kind must be " + RepoCredentialHTTPSToken + " or " + RepoCredentialSSHKey)
+	}
+	fromEnv := strings.TrimSpace(req.SecretEnv) != ""
+	fromVault := strings.TrimSpace(req.VaultConnection) != "" || req.VaultSecret != nil
+	if fromEnv == fromVault {
+		return invalid("exactly one of secret_env or vault_connection with vault_secret is required")
+	}
+	if fromEnv && !ValidRepoCredentialSecretEnv(req.SecretEnv) {
+		return invalid("secret_env must start with " + RepoCredentialSecretEnvPrefix)
+	}
+	if fromVault && (strings.TrimSpace(req.VaultConnection) == "" || req.VaultSecret == nil ||
+		strings.TrimSpace(req.VaultSecret.Path) == "" || strings.TrimSpace(req.VaultSecret.Key) == "") {
+		return invalid("vault credentials need vault_connection and a vault_secret with path and key")
+	}
+	return nil
+}
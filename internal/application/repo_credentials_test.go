@@ -0,0 +1,69 @@
+package application
+
+import (
+	"context"
+	"testing"
+
+	"github.com/izzyreal/ciwi/internal/domain"
+)
+
+type repoCredentialRepositoryStub struct {
+	upserted []UpsertRepoCredentialRequest
+	deleted  []int64
+}
+
+func (s *repoCredentialRepositoryStub) ListRepoCredentials(context.Context) ([]RepoCredential, error) {
+	return []RepoCredential{{ID: 3, Name: "org", SecretEnv: "CIWI_REPO_CRED_ORG"}}, nil
+}
+func (s *repoCredentialRepositoryStub) UpsertRepoCredential(_ context.Context, request UpsertRepoCredentialRequest) (RepoCredential, error) {
+	s.upserted = append(s.upserted, request)
+	return RepoCredential{ID: 4, Name: request.Name}, nil
+}
+func (s *repoCredentialRepositoryStub) DeleteRepoCredential(_ context.Context, id int64) error {
+	s.deleted = append(s.deleted, id)
+	return nil
+}
+
+func TestRepoCredentialsAuthorizeAndValidate(t *testing.T) {
+	repository := &repoCredentialRepositoryStub{}
+	credentials := NewRepoCredentials(repository, NewAuthorizer(authenticationStateStub(true)))
+	operator := WithPrincipal(context.Background(), domain.Principal{UserID: 1, Username: "o", Role: domain.RoleOperator})
+	admin := WithPrincipal(context.Background(), domain.Principal{UserID: 2, Username: "a", Role: domain.RoleAdmin})
+	valid := UpsertRepoCredentialRequest{Name: "org", URLPrefix: "https://git.example.com/org/", Kind: RepoCredentialHTTPSToken, SecretEnv: "CIWI_REPO_CRED_ORG"}
+
+	if _, err := credentials.List(context.Background()); ErrorKindOf(err) != ErrorUnauthenticated {
+		t.Fatalf("list without a principal error = %v", err)
+	}
+	if items, err := credentials.List(operator); err != nil || len(items) != 1 {
+		t.Fatalf("operator list = %+v, %v", items, err)
+	}
+	if _, err := credentials.Upsert(operator, valid); ErrorKindOf(err) != ErrorPermissionDenied {
+		t.Fatalf("operator upsert error = %v", err)
+	}
+	if err := credentials.Delete(operator, 3); ErrorKindOf(err) != ErrorPermissionDenied {
+		t.Fatalf("operator delete error = %v", err)
+	}
+	if len(repository.upserted)+len(repository.deleted) != 0 {
+		t.Fatalf("denied calls reached the repository: %+v", repository)
+	}
+
+	for _, request := range []UpsertRepoCredentialRequest{
+		{Name: "org", URLPrefix: "https://git.example.com/org/", Kind: "password", SecretEnv: "CIWI_REPO_CRED_ORG"},
+		{Name: "org", URLPrefix: "https://git.example.com/org/", Kind: RepoCredentialHTTPSToken, SecretEnv: "CIWI_VAULT_SECRET_ID"},
+		{Name: "org", URLPrefix: "https://git.example.com/org/", Kind: RepoCredentialHTTPSToken, SecretEnv: RepoCredentialSecretEnvPrefix},
+		{Name: "org", URLPrefix: "https://git.example.com/org/", Kind: RepoCredentialSSHKey, VaultConnection: "vault", VaultSecret: &VaultSecretRef{Path: "ci/deploy"}},
+	} {
+		if _, err := credentials.Upsert(admin, request); ErrorKindOf(err) != ErrorInvalidArgument {
+			t.Fatalf("upsert %+v error = %v", request, err)
+		}
+	}
+	if _, err := credentials.Upsert(admin, valid); err != nil {
+		t.Fatal(err)
+	}
+	if err := credentials.Delete(admin, 3); err != nil {
+		t.Fatal(err)
+	}
+	if len(repository.upserted) != 1 || len(repository.deleted) != 1 {
+		t.Fatalf("admin calls = %+v", repository)
+	}
+}
@@ -2,6 +2,8 @@ package application
 
 import (
 	"context"
+	"slices"
+	"strconv"
 	"strings"
 )
 
@@ -21,6 +23,7 @@ type RunOptionsRequest struct {
 	SourceRef              string
 	AgentID                string
 	ExecutionMode          string
+	Priority               *int
 	IncludeSourceRefs      bool
 	IncludeEligibleAgents  bool
 	AllowMissingSourceRepo bool
@@ -42,11 +45,23 @@ type RunOptions struct {
 	DefaultSourceRef  string      `json:"default_source_ref,omitempty"`
 	SelectedSourceRef string      `json:"selected_source_ref"`
 	SelectedAgentID   string      `json:"selected_agent_id"`
+	SelectedPriority  string      `json:"selected_priority"`
 	SourceRefs        []RunOption `json:"source_refs"`
 	EligibleAgents    []RunOption `json:"eligible_agents"`
+	PriorityOptions   []RunOption `json:"priority_options"`
 	PendingJobs       int         `json:"pending_jobs"`
 }
 
+// priorityRunOptions are the presets offered for overriding the configured job
+// priorities of a run. The empty value keeps the configured priorities.
+var priorityRunOptions = []RunOption{
+	{Value: "", Label: "Configured priority"},
+	{Value: "100", Label: "Urgent (100)"},
+	{Value: "50", Label: "High (50)"},
+	{Value: "0", Label: "Normal (0)"},
+	{Value: "-50", Label: "Low (-50)"},
+}
+
 type RunOptionsProvider interface {
 	GetRunOptions(context.Context, RunOptionsRequest) (RunOptions, error)
 }
@@ -85,5 +100,12 @@ func (q *RunOptionsQueries) GetRunOptions(ctx context.Context, request RunOption
 		result.SelectedSourceRef = result.DefaultSourceRef
 	}
 	result.SelectedAgentID = strings.TrimSpace(request.AgentID)
+	result.PriorityOptions = append([]RunOption(nil), priorityRunOptions...)
+	if request.Priority != nil {
+		result.SelectedPriority = strconv.Itoa(*request.Priority)
+		if !slices.ContainsFunc(result.PriorityOptions, func(option RunOption) bool { return option.Value == result.SelectedPriority }) {
+			result.PriorityOptions = append(result.PriorityOptions, RunOption{Value: result.SelectedPriority, Label: "Custom (" + result.SelectedPriority + ")"})
+		}
+	}
 	return result, nil
 }
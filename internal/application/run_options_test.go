@@ -54,3 +54,23 @@ func TestRunOptionsQueriesRejectAmbiguousTargets(t *testing.T) {
 		}
 	}
 }
+
+func TestRunOptionsQueriesOfferPriorityOverrides(t *testing.T) {
+	queries := NewRunOptionsQueries(&runOptionsProviderStub{result: RunOptions{TargetKind: RunTargetPipeline}})
+	result, err := queries.GetRunOptions(t.Context(), RunOptionsRequest{PipelineDBID: 7})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.SelectedPriority != "" || len(result.PriorityOptions) == 0 || result.PriorityOptions[0].Value != "" {
+		t.Fatalf("expected the configured priority to be selected, got %+v", result)
+	}
+	priority := 20
+	result, err = queries.GetRunOptions(t.Context(), RunOptionsRequest{PipelineDBID: 7, Priority: &priority})
+	if err != nil {
+		t.Fatal(err)
+	}
+	last := result.PriorityOptions[len(result.PriorityOptions)-1]
+	if result.SelectedPriority != "20" || last.Value != "20" || last.Label != "Custom (20)" {
+		t.Fatalf("expected a custom priority option, got %+v", result)
+	}
+}
@@ -66,17 +66,18 @@ type ServerUpdateBackend interface {
 }
 
 type ServerUpdateOperations struct {
-	backend  ServerUpdateBackend
-	changes  *ChangeHub
-	receipts CommandReceiptRepository
+	backend    ServerUpdateBackend
+	changes    *ChangeHub
+	receipts   CommandReceiptRepository
+	authorizer *Authorizer
 }
 
-func NewServerUpdateOperations(backend ServerUpdateBackend, changes *ChangeHub, receipts ...CommandReceiptRepository) *ServerUpdateOperations {
+func NewServerUpdateOperations(backend ServerUpdateBackend, changes *ChangeHub, authorizer *Authorizer, receipts ...CommandReceiptRepository) *ServerUpdateOperations {
 	var receiptRepository CommandReceiptRepository
 	if len(receipts) > 0 {
 		receiptRepository = receipts[0]
 	}
-	return &ServerUpdateOperations{backend: backend, changes: changes, receipts: receiptRepository}
+	return &ServerUpdateOperations{backend: backend, changes: changes, receipts: receiptRepository, authorizer: authorizer}
 }
 
 func (o *ServerUpdateOperations) Status(ctx context.Context) (ServerUpdateStatus, error) {
@@ -90,6 +91,9 @@ func (o *ServerUpdateOperations) Check(ctx context.Context) (ServerUpdateCheckRe
 	if o == nil || o.backend == nil {
 		return ServerUpdateCheckResult{}, NewError(ErrorUnavailable, "server update service unavailable", nil)
 	}
+	if err := o.authorizer.Authorize(ctx, ActionManageUpdates); err != nil {
+		return ServerUpdateCheckResult{}, err
+	}
 	result, err := o.backend.CheckForServerUpdates(ctx)
 	if err == nil && o.changes != nil {
 		o.changes.Publish(ChangeUpdates)
@@ -116,6 +120,9 @@ func (o *ServerUpdateOperations) Execute(ctx context.Context, request ServerUpda
 	if o == nil || o.backend == nil {
 		return ServerUpdateActionResult{}, NewError(ErrorUnavailable, "server update service unavailable", nil)
 	}
+	if err := o.authorizer.Authorize(ctx, ActionManageUpdates); err != nil {
+		return ServerUpdateActionResult{}, err
+	}
 	key, err := validateCommandKey(request.IdempotencyKey)
 	if err != nil {
 		return ServerUpdateActionResult{}, err
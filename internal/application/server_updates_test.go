@@ -29,7 +29,7 @@ func (s *updateBackendStub) ExecuteServerUpdateAction(_ context.Context, request
 func TestServerUpdateOperationsValidateAndPublish(t *testing.T) {
 	backend := &updateBackendStub{}
 	hub := NewChangeHub()
-	operations := NewServerUpdateOperations(backend, hub)
+	operations := NewServerUpdateOperations(backend, hub, nil)
 	before := hub.Snapshot().Revision
 
 	if _, err := operations.Execute(context.Background(), ServerUpdateActionRequest{Action: "rollback"}); ErrorKindOf(err) != ErrorInvalidArgument {
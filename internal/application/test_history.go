@@ -0,0 +1,215 @@
+package application
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/izzyreal/ciwi/internal/domain"
+)
+
+const (
+	testHistoryExecutionLimit = 50
+	testHistoryRecentRuns     = 5
+)
+
+// TestHistoryRepository lists indexed test case results, newest first.
+type TestHistoryRepository interface {
+	ListTestCaseRuns(ctx context.Context, scope domain.TestHistoryScope, executionLimit int) ([]domain.TestCaseRun, error)
+}
+
+type TestHistoryRequest struct {
+	Scope domain.TestHistoryScope
+	// Query keeps the cases whose package or name contains it.
+	Query string
+}
+
+type TestHistoryQueries struct {
+	repository TestHistoryRepository
+}
+
+func NewTestHistoryQueries(repository TestHistoryRepository) *TestHistoryQueries {
+	return &TestHistoryQueries{repository: repository}
+}
+
+func (q *TestHistoryQueries) GetTestHistory(ctx context.Context, request TestHistoryRequest) (domain.TestHistory, error) {
+	if q == nil || q.repository == nil {
+		return domain.TestHistory{}, NewError(ErrorUnavailable, "test history repository unavailable", nil)
+	}
+	scope := request.Scope
+	scope.PipelineID = strings.TrimSpace(scope.PipelineID)
+	scope.PipelineJobID = strings.TrimSpace(scope.PipelineJobID)
+	scope.MatrixName = strings.TrimSpace(scope.MatrixName)
+	if scope.ProjectID <= 0 || scope.PipelineID == "" || scope.PipelineJobID == "" {
+		return domain.TestHistory{}, NewError(ErrorInvalidArgument, "project id, pipeline id and pipeline job id are required", nil)
+	}
+	runs, err := q.repository.ListTestCaseRuns(ctx, scope, testHistoryExecutionLimit)
+	if err != nil {
+		return domain.TestHistory{}, WrapInternal("list test case runs", err)
+	}
+	history := AnalyzeTestHistory(runs)
+	history.Scope = scope
+	if query := strings.ToLower(strings.TrimSpace(request.Query)); query != "" {
+		filtered := history.Cases[:0]
+		for _, testCase := range history.Cases {
+			if strings.Contains(strings.ToLower(testCase.Package+" "+testCase.Name), query) {
+				filtered = append(filtered, testCase)
+			}
+		}
+		history.Cases = filtered
+	}
+	return history, nil
+}
+
+// AnalyzeTestHistory correlates runs, newest first, by package and test name.
+func AnalyzeTestHistory(runs []domain.TestCaseRun) domain.TestHistory {
+	type caseKey struct{ pkg, name string }
+	executions := map[string]struct{}{}
+	byCase := map[caseKey][]domain.TestCaseRun{}
+	for _, run := range runs {
+		executions[run.JobExecutionID] = struct{}{}
+		key := caseKey{pkg: run.Package, name: run.Name}
+		byCase[key] = append(byCase[key], run)
+	}
+	history := domain.TestHistory{Executions: len(executions), Cases: make([]domain.TestCaseHistory, 0, len(byCase))}
+	for key, caseRuns := range byCase {
+		history.Cases = append(history.Cases, analyzeTestCase(key.pkg, key.name, caseRuns))
+	}
+	sort.Slice(history.Cases, func(i, j int) bool {
+		if history.Cases[i].Package != history.Cases[j].Package {
+			return history.Cases[i].Package < history.Cases[j].Package
+		}
+		return history.Cases[i].Name < history.Cases[j].Name
+	})
+	return history
+}
+
+func analyzeTestCase(pkg, name string, runs []domain.TestCaseRun) domain.TestCaseHistory {
+	history := domain.TestCaseHistory{Package: pkg, Name: name, Runs: runs}
+	type commitOutcome struct{ failed, flaky bool }
+	outcomes := map[string]*commitOutcome{}
+	// Walk oldest first so a pass is only flaky when it follows a failure.
+	for i := len(runs) - 1; i >= 0; i-- {
+		run := runs[i]
+		key := "commit:" + run.SourceCommit
+		if run.SourceCommit == "" {
+			key = "attempt:" + run.AttemptRootID
+		}
+		outcome := outcomes[key]
+		if outcome == nil {
+			outcome = &commitOutcome{}
+			outcomes[key] = outcome
+		}
+		switch run.Status {
+		case "pass":
+			history.Passed++
+			outcome.flaky = outcome.flaky || outcome.failed
+		case "fail":
+			history.Failed++
+			outcome.failed = true
+		case "skip":
+			history.Skipped++
+		}
+	}
+	for _, outcome := range outcomes {
+		if outcome.failed {
+			history.FailingCommits++
+			if outcome.flaky {
+				history.FlakyCommits++
+			}
+		}
+	}
+	if history.FailingCommits > 0 {
+		history.FlakinessScore = float64(history.FlakyCommits) / float64(history.FailingCommits)
+	}
+	var durations []float64
+	for _, run := range runs {
+		if run.Status != "pass" && run.Status != "fail" {
+			continue
+		}
+		durations = append(durations, run.DurationSeconds)
+		switch {
+		case history.StreakStatus == "":
+			history.StreakStatus, history.StreakLength = run.Status, 1
+		case history.StreakStatus == run.Status && history.StreakLength == len(durations)-1:
+			history.StreakLength++
+		}
+	}
+	if len(durations) > 0 {
+		history.AverageDurationSeconds = averageOf(durations)
+		recent := durations[:min(testHistoryRecentRuns, len(durations))]
+		history.RecentDurationSeconds = averageOf(recent)
+		if earlier := durations[len(recent):]; len(earlier) > 0 {
+			if baseline := averageOf(earlier); baseline > 0 {
+				history.DurationTrend = (history.RecentDurationSeconds - baseline) / baseline
+			}
+		}
+	}
+	return history
+}
+
+// TestHistoryBadge classifies the result a case had in jobExecutionID. Known
+// flaky tests are reported as such whatever their result; a failure is new
+// when the latest earlier result passed or there is none.
+func TestHistoryBadge(history domain.TestCaseHistory, jobExecutionID string) string {
+	if history.FlakyCommits > 0 {
+		return domain.TestHistoryBadgeKnownFlaky
+	}
+	for i, run := range history.Runs {
+		if run.JobExecutionID != jobExecutionID {
+			continue
+		}
+		if run.Status != "fail" {
+			return ""
+		}
+		for _, earlier := range history.Runs[i+1:] {
+			if earlier.JobExecutionID == jobExecutionID || (earlier.Status != "pass" && earlier.Status != "fail") {
+				continue
+			}
+			if earlier.Status == "pass" {
+				return domain.TestHistoryBadgeNewFailure
+			}
+			return ""
+		}
+		return domain.TestHistoryBadgeNewFailure
+	}
+	return ""
+}
+
+// attachTestHistoryBadges marks the cases of a job's test report that are
+// new failures or known flakes. History is advisory, so a failed lookup
+// leaves the report unbadged instead of failing the details query.
+func attachTestHistoryBadges(ctx context.Context, repository TestHistoryRepository, details *domain.JobExecutionDetails) {
+	if details.TestReport == nil || details.ProjectID <= 0 || details.PipelineJobID == "" {
+		return
+	}
+	runs, err := repository.ListTestCaseRuns(ctx, domain.TestHistoryScope{
+		ProjectID: details.ProjectID, PipelineID: details.PipelineID,
+		PipelineJobID: details.PipelineJobID, MatrixName: details.MatrixName,
+	}, testHistoryExecutionLimit)
+	if err != nil || len(runs) == 0 {
+		return
+	}
+	type caseKey struct{ pkg, name string }
+	histories := map[caseKey]domain.TestCaseHistory{}
+	for _, history := range AnalyzeTestHistory(runs).Cases {
+		histories[caseKey{pkg: history.Package, name: history.Name}] = history
+	}
+	for suiteIndex := range details.TestReport.Suites {
+		cases := details.TestReport.Suites[suiteIndex].Cases
+		for caseIndex := range cases {
+			key := caseKey{pkg: strings.TrimSpace(cases[caseIndex].Package), name: strings.TrimSpace(cases[caseIndex].Name)}
+			if history, ok := histories[key]; ok {
+				cases[caseIndex].HistoryBadge = TestHistoryBadge(history, details.ID)
+			}
+		}
+	}
+}
+
+func averageOf(values []float64) float64 {
+	total := 0.0
+	for _, value := range values {
+		total += value
+	}
+	return total / float64(len(values))
+}
@@ -0,0 +1,120 @@
+package application
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/izzyreal/ciwi/internal/domain"
+)
+
+type testHistoryRepositoryStub struct {
+	executionRepositoryStub
+	runs []domain.TestCaseRun
+}
+
+func (s testHistoryRepositoryStub) ListTestCaseRuns(context.Context, domain.TestHistoryScope, int) ([]domain.TestCaseRun, error) {
+	return s.runs, nil
+}
+
+// testRun builds a result of p.TestA recorded age hours before the newest run.
+func testRun(job, root, commit, status string, seconds float64, age int) domain.TestCaseRun {
+	return domain.TestCaseRun{
+		JobExecutionID: job, AttemptRootID: root, SourceCommit: commit, Package: "p", Name: "TestA",
+		Status: status, DurationSeconds: seconds, CreatedUTC: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC).Add(-time.Duration(age) * time.Hour),
+	}
+}
+
+func TestAnalyzeTestHistoryDetectsFlakesStreaksAndDurationTrend(t *testing.T) {
+	history := AnalyzeTestHistory([]domain.TestCaseRun{
+		testRun("j7", "j7", "c4", "pass", 2, 0),
+		testRun("j6", "j6", "c4", "pass", 2, 1),
+		testRun("j5", "j4", "c3", "pass", 2, 2), // rerun of j4 passed: flaky on c3
+		testRun("j4", "j4", "c3", "fail", 2, 3),
+		testRun("j3", "j3", "c2", "fail", 2, 4), // c2 never passed: a real failure
+		testRun("j2", "j2", "", "skip", 0, 5),
+		testRun("j1", "j1", "c1", "pass", 1, 6),
+	})
+	if history.Executions != 7 || len(history.Cases) != 1 {
+		t.Fatalf("unexpected history shape %+v", history)
+	}
+	testCase := history.Cases[0]
+	if testCase.Passed != 4 || testCase.Failed != 2 || testCase.Skipped != 1 {
+		t.Fatalf("unexpected counts %+v", testCase)
+	}
+	if testCase.StreakStatus != "pass" || testCase.StreakLength != 3 {
+		t.Fatalf("expected a three-run pass streak, got %s x%d", testCase.StreakStatus, testCase.StreakLength)
+	}
+	if testCase.FailingCommits != 2 || testCase.FlakyCommits != 1 || testCase.FlakinessScore != 0.5 {
+		t.Fatalf("expected one of two failing commits to be flaky, got %+v", testCase)
+	}
+	if testCase.RecentDurationSeconds != 2 || math.Abs(testCase.DurationTrend-1) > 1e-9 {
+		t.Fatalf("expected recent runs to take twice as long, got %v (%v)", testCase.RecentDurationSeconds, testCase.DurationTrend)
+	}
+	if badge := TestHistoryBadge(testCase, "j7"); badge != domain.TestHistoryBadgeKnownFlaky {
+		t.Fatalf("expected known flaky badge, got %q", badge)
+	}
+}
+
+func TestTestHistoryBadgeSeparatesNewAndOngoingFailures(t *testing.T) {
+	history := AnalyzeTestHistory([]domain.TestCaseRun{
+		testRun("j3", "j3", "c3", "fail", 1, 0),
+		testRun("j2", "j2", "c2", "fail", 1, 1),
+		testRun("j1", "j1", "c1", "pass", 1, 2),
+	}).Cases[0]
+	for job, want := range map[string]string{"j3": "", "j2": domain.TestHistoryBadgeNewFailure, "j1": "", "unknown": ""} {
+		if got := TestHistoryBadge(history, job); got != want {
+			t.Fatalf("TestHistoryBadge(%s) = %q, want %q", job, got, want)
+		}
+	}
+	first := AnalyzeTestHistory([]domain.TestCaseRun{testRun("j1", "j1", "c1", "fail", 1, 0)}).Cases[0]
+	if got := TestHistoryBadge(first, "j1"); got != domain.TestHistoryBadgeNewFailure {
+		t.Fatalf("expected a first failure to be new, got %q", got)
+	}
+}
+
+func TestGetTestHistoryValidatesScopeAndFilters(t *testing.T) {
+	queries := NewTestHistoryQueries(testHistoryRepositoryStub{runs: []domain.TestCaseRun{
+		testRun("j1", "j1", "c1", "pass", 1, 0),
+		{JobExecutionID: "j1", Package: "p", Name: "TestOther", Status: "fail"},
+	}})
+	if _, err := queries.GetTestHistory(t.Context(), TestHistoryRequest{Scope: domain.TestHistoryScope{ProjectID: 1, PipelineID: "build"}}); ErrorKindOf(err) != ErrorInvalidArgument {
+		t.Fatalf("expected invalid argument, got %v", err)
+	}
+	history, err := queries.GetTestHistory(t.Context(), TestHistoryRequest{
+		Scope: domain.TestHistoryScope{ProjectID: 1, PipelineID: "build", PipelineJobID: " unit "}, Query: "other",
+	})
+	if err != nil {
+		t.Fatalf("get test history: %v", err)
+	}
+	if history.Scope.PipelineJobID != "unit" || len(history.Cases) != 1 || history.Cases[0].Name != "TestOther" {
+		t.Fatalf("unexpected filtered history %+v", history)
+	}
+}
+
+func TestGetJobExecutionDetailsBadgesTestCasesFromHistory(t *testing.T) {
+	details := domain.JobExecutionDetails{
+		ID: "j2", ProjectID: 1, PipelineID: "build", PipelineJobID: "unit",
+		TestReport: &domain.JobTestReport{Suites: []domain.JobTestSuite{{Cases: []domain.JobTestCase{
+			{Package: "p", Name: "TestA", Status: "fail"},
+			{Package: "p", Name: "TestB", Status: "pass"},
+		}}}},
+	}
+	queries := NewExecutionQueries(testHistoryRepositoryStub{
+		executionRepositoryStub: executionRepositoryStub{details: details},
+		runs: []domain.TestCaseRun{
+			testRun("j2", "j2", "c2", "fail", 1, 0),
+			{JobExecutionID: "j2", AttemptRootID: "j2", Package: "p", Name: "TestB", Status: "pass"},
+			testRun("j1", "j1", "c1", "pass", 1, 1),
+		},
+	})
+	got, err := queries.GetJobExecutionDetails(t.Context(), "j2")
+	if err != nil {
+		t.Fatalf("get details: %v", err)
+	}
+	cases := got.TestReport.Suites[0].Cases
+	if cases[0].HistoryBadge != domain.TestHistoryBadgeNewFailure || cases[1].HistoryBadge != "" {
+		t.Fatalf("unexpected badges %+v", cases)
+	}
+}
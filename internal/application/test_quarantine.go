@@ -0,0 +1,199 @@
+package application
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/izzyreal/ciwi/internal/domain"
+)
+
+const (
+	maxTestQuarantineName   = 500
+	maxTestQuarantineOwner  = 200
+	maxTestQuarantineReason = 1000
+	// maxTestQuarantineDuration bounds how long a test may stay quarantined
+	// without someone looking at it again.
+	maxTestQuarantineDuration = 366 * 24 * time.Hour
+)
+
+type TestQuarantineRepository interface {
+	ListTestQuarantine(ctx context.Context, projectID int64) ([]domain.TestQuarantineEntry, error)
+	CreateTestQuarantine(ctx context.Context, entry domain.TestQuarantineEntry) (domain.TestQuarantineEntry, error)
+	DeleteTestQuarantine(ctx context.Context, projectID, id int64) (bool, error)
+}
+
+// AddTestQuarantineRequest quarantines a test of a project. Expires is an
+// RFC 3339 time or a YYYY-MM-DD date, which lasts until the end of that day
+// in UTC.
+type AddTestQuarantineRequest struct {
+	ProjectID      int64
+	Package        string
+	Name           string
+	Owner          string
+	Reason         string
+	Expires        string
+	IdempotencyKey string
+}
+
+type RemoveTestQuarantineRequest struct {
+	ProjectID      int64
+	ID             int64
+	IdempotencyKey string
+}
+
+type RemoveTestQuarantineResult struct {
+	ProjectID int64 `json:"project_id"`
+	ID        int64 `json:"id"`
+}
+
+// TestQuarantineService manages the per-project list of quarantined tests.
+// Report ingestion applies the list itself; see domain.ActiveTestQuarantine.
+type TestQuarantineService struct {
+	repository TestQuarantineRepository
+	receipts   CommandReceiptRepository
+	changes    *ChangeHub
+	authorizer *Authorizer
+	now        func() time.Time
+}
+
+func NewTestQuarantineService(repository TestQuarantineRepository, receipts CommandReceiptRepository, changes *ChangeHub, authorizer *Authorizer) *TestQuarantineService {
+	return &TestQuarantineService{repository: repository, receipts: receipts, changes: changes, authorizer: authorizer, now: func() time.Time { return time.Now().UTC() }}
+}
+
+func (s *TestQuarantineService) List(ctx context.Context, projectID int64) ([]domain.TestQuarantineEntry, error) {
+	if err := s.available(); err != nil {
+		return nil, err
+	}
+	if projectID <= 0 {
+		return nil, NewError(ErrorInvalidArgument, "project id is required", nil)
+	}
+	if err := s.authorizer.Authorize(ctx, ActionView); err != nil {
+		return nil, err
+	}
+	entries, err := s.repository.ListTestQuarantine(ctx, projectID)
+	if err != nil {
+		return nil, WrapInternal("list test quarantine", err)
+	}
+	return entries, nil
+}
+
+func (s *TestQuarantineService) Add(ctx context.Context, request AddTestQuarantineRequest) (domain.TestQuarantineEntry, error) {
+	if err := s.available(); err != nil {
+		return domain.TestQuarantineEntry{}, err
+	}
+	request.Package = strings.TrimSpace(request.Package)
+	request.Name = strings.TrimSpace(request.Name)
+	request.Owner = strings.TrimSpace(request.Owner)
+	request.Reason = strings.TrimSpace(request.Reason)
+	request.Expires = strings.TrimSpace(request.Expires)
+	if request.ProjectID <= 0 || request.Name == "" || request.Owner == "" {
+		return domain.TestQuarantineEntry{}, NewError(ErrorInvalidArgument, "project id, test name and owner are required", nil)
+	}
+	if len(request.Package)+len(request.Name) > maxTestQuarantineName || len(request.Owner) > maxTestQuarantineOwner || len(request.Reason) > maxTestQuarantineReason {
+		return domain.TestQuarantineEntry{}, NewError(ErrorInvalidArgument, "test name, owner or reason is too long", nil)
+	}
+	now := s.now()
+	expires, err := ParseTestQuarantineExpiry(request.Expires)
+	if err != nil {
+		return domain.TestQuarantineEntry{}, err
+	}
+	if !expires.After(now) || expires.Sub(now) > maxTestQuarantineDuration {
+		return domain.TestQuarantineEntry{}, NewError(ErrorInvalidArgument, "quarantine expiry must be in the future and within a year", nil)
+	}
+	if err := s.authorizer.Authorize(ctx, ActionManageProjects); err != nil {
+		return domain.TestQuarantineEntry{}, err
+	}
+	key, err := validateCommandKey(request.IdempotencyKey)
+	if err != nil {
+		return domain.TestQuarantineEntry{}, err
+	}
+	principal, _ := PrincipalFromContext(ctx)
+	execute := func() (domain.TestQuarantineEntry, error) {
+		entry, createErr := s.repository.CreateTestQuarantine(ctx, domain.TestQuarantineEntry{
+			ProjectID: request.ProjectID, Package: request.Package, Name: request.Name,
+			Owner: request.Owner, Reason: request.Reason, CreatedBy: principal.Username,
+			CreatedUTC: now, ExpiresUTC: expires,
+		})
+		if createErr != nil {
+			return domain.TestQuarantineEntry{}, WrapInternal("create test quarantine entry", createErr)
+		}
+		s.publish()
+		return entry, nil
+	}
+	fingerprint := request
+	fingerprint.IdempotencyKey = ""
+	payload, err := json.Marshal(fingerprint)
+	if err != nil {
+		return domain.TestQuarantineEntry{}, WrapInternal("fingerprint test quarantine", err)
+	}
+	sum := sha256.Sum256(payload)
+	return executeIdempotentCommand(ctx, s.receipts, key, "test_quarantine_add", hex.EncodeToString(sum[:]), execute)
+}
+
+func (s *TestQuarantineService) Remove(ctx context.Context, request RemoveTestQuarantineRequest) (RemoveTestQuarantineResult, error) {
+	if err := s.available(); err != nil {
+		return RemoveTestQuarantineResult{}, err
+	}
+	if request.ProjectID <= 0 || request.ID <= 0 {
+		return RemoveTestQuarantineResult{}, NewError(ErrorInvalidArgument, "project id and quarantine entry id are required", nil)
+	}
+	if err := s.authorizer.Authorize(ctx, ActionManageProjects); err != nil {
+		return RemoveTestQuarantineResult{}, err
+	}
+	key, err := validateCommandKey(request.IdempotencyKey)
+	if err != nil {
+		return RemoveTestQuarantineResult{}, err
+	}
+	execute := func() (RemoveTestQuarantineResult, error) {
+		deleted, deleteErr := s.repository.DeleteTestQuarantine(ctx, request.ProjectID, request.ID)
+		if deleteErr != nil {
+			return RemoveTestQuarantineResult{}, WrapInternal("delete test quarantine entry", deleteErr)
+		}
+		if !deleted {
+			return RemoveTestQuarantineResult{}, NewError(ErrorNotFound, "test quarantine entry not found", nil)
+		}
+		s.publish()
+		return RemoveTestQuarantineResult{ProjectID: request.ProjectID, ID: request.ID}, nil
+	}
+	fingerprint := request
+	fingerprint.IdempotencyKey = ""
+	payload, err := json.Marshal(fingerprint)
+	if err != nil {
+		return RemoveTestQuarantineResult{}, WrapInternal("fingerprint test quarantine removal", err)
+	}
+	sum := sha256.Sum256(payload)
+	return executeIdempotentCommand(ctx, s.receipts, key, "test_quarantine_remove", hex.EncodeToString(sum[:]), execute)
+}
+
+// ParseTestQuarantineExpiry accepts an RFC 3339 time or a YYYY-MM-DD date.
+func ParseTestQuarantineExpiry(raw string) (time.Time, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return time.Time{}, NewError(ErrorInvalidArgument, "quarantine expiry is required", nil)
+	}
+	if date, err := time.Parse(time.DateOnly, raw); err == nil {
+		return date.Add(24*time.Hour - time.Second), nil
+	}
+	expires, err := time.Parse(time.RFC3339, raw)
+	if err != nil {
+		return time.Time{}, NewError(ErrorInvalidArgument, "quarantine expiry must be a YYYY-MM-DD date or an RFC 3339 time", nil)
+	}
+	return expires.UTC(), nil
+}
+
+func (s *TestQuarantineService) publish() {
+	if s.changes != nil {
+		s.changes.Publish(ChangeProjects)
+	}
+}
+
+func (s *TestQuarantineService) available() error {
+	if s == nil || s.repository == nil {
+		return NewError(ErrorUnavailable, "test quarantine unavailable", nil)
+	}
+	return nil
+}
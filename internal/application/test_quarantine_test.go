@@ -0,0 +1,83 @@
+package application
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/izzyreal/ciwi/internal/domain"
+)
+
+type testQuarantineRepositoryStub struct {
+	entries []domain.TestQuarantineEntry
+}
+
+func (s *testQuarantineRepositoryStub) ListTestQuarantine(_ context.Context, projectID int64) ([]domain.TestQuarantineEntry, error) {
+	var entries []domain.TestQuarantineEntry
+	for _, entry := range s.entries {
+		if entry.ProjectID == projectID {
+			entries = append(entries, entry)
+		}
+	}
+	return entries, nil
+}
+
+func (s *testQuarantineRepositoryStub) CreateTestQuarantine(_ context.Context, entry domain.TestQuarantineEntry) (domain.TestQuarantineEntry, error) {
+	entry.ID = int64(len(s.entries) + 1)
+	s.entries = append(s.entries, entry)
+	return entry, nil
+}
+
+func (s *testQuarantineRepositoryStub) DeleteTestQuarantine(_ context.Context, projectID, id int64) (bool, error) {
+	for i, entry := range s.entries {
+		if entry.ProjectID == projectID && entry.ID == id {
+			s.entries = append(s.entries[:i], s.entries[i+1:]...)
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func TestTestQuarantineServiceValidatesAndIsIdempotent(t *testing.T) {
+	repository := &testQuarantineRepositoryStub{}
+	service := NewTestQuarantineService(repository, newReceiptRepositoryStub(), NewChangeHub(), nil)
+	service.now = func() time.Time { return time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC) }
+	admin := WithPrincipal(t.Context(), domain.Principal{UserID: 1, Username: "admin", Role: domain.RoleAdmin})
+
+	request := AddTestQuarantineRequest{ProjectID: 7, Package: " p ", Name: "TestFlaky", Owner: "alice", Expires: "2026-03-15", IdempotencyKey: "quarantine-1"}
+	entry, err := service.Add(admin, request)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if entry.Package != "p" || entry.CreatedBy != "admin" || !entry.ExpiresUTC.Equal(time.Date(2026, 3, 15, 23, 59, 59, 0, time.UTC)) {
+		t.Fatalf("unexpected entry %+v", entry)
+	}
+	replayed, err := service.Add(admin, request)
+	if err != nil || replayed.ID != entry.ID || len(repository.entries) != 1 {
+		t.Fatalf("expected replay of the first entry, got %+v (%v), stored %d", replayed, err, len(repository.entries))
+	}
+
+	for name, invalid := range map[string]AddTestQuarantineRequest{
+		"missing owner": {ProjectID: 7, Name: "TestFlaky", Expires: "2026-03-15"},
+		"past expiry":   {ProjectID: 7, Name: "TestFlaky", Owner: "alice", Expires: "2026-02-28"},
+		"too long":      {ProjectID: 7, Name: "TestFlaky", Owner: "alice", Expires: "2027-06-01"},
+		"bad expiry":    {ProjectID: 7, Name: "TestFlaky", Owner: "alice", Expires: "next week"},
+	} {
+		invalid.IdempotencyKey = "invalid-" + name
+		if _, err := service.Add(admin, invalid); ErrorKindOf(err) != ErrorInvalidArgument {
+			t.Fatalf("%s: expected invalid argument, got %v", name, err)
+		}
+	}
+
+	viewer := WithPrincipal(t.Context(), domain.Principal{UserID: 2, Username: "viewer", Role: domain.RoleViewer})
+	if _, err := service.Add(viewer, AddTestQuarantineRequest{ProjectID: 7, Name: "TestOther", Owner: "bob", Expires: "2026-03-15", IdempotencyKey: "viewer-1"}); ErrorKindOf(err) != ErrorPermissionDenied {
+		t.Fatalf("expected viewers to be denied, got %v", err)
+	}
+
+	if _, err := service.Remove(admin, RemoveTestQuarantineRequest{ProjectID: 8, ID: entry.ID, IdempotencyKey: "remove-1"}); ErrorKindOf(err) != ErrorNotFound {
+		t.Fatalf("expected removal through another project to be not found, got %v", err)
+	}
+	if _, err := service.Remove(admin, RemoveTestQuarantineRequest{ProjectID: 7, ID: entry.ID, IdempotencyKey: "remove-2"}); err != nil || len(repository.entries) != 0 {
+		t.Fatalf("expected removal, got %v with %d entries left", err, len(repository.entries))
+	}
+}
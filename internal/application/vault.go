@@ -0,0 +1,140 @@
+package application
+
+import (
+	"context"
+	"strings"
+)
+
+type VaultConnection struct {
+	ID               int64
+	Name             string
+	URL              string
+	AuthMethod       string
+	AppRoleMount     string
+	RoleID           string
+	SecretIDEnv      string
+	Namespace        string
+	KVDefaultMount   string
+	KVDefaultVersion int
+}
+
+type UpsertVaultConnectionRequest struct {
+	Name             string
+	URL              string
+	AuthMethod       string
+	AppRoleMount     string
+	RoleID           string
+	SecretIDEnv      string
+	Namespace        string
+	KVDefaultMount   string
+	KVDefaultVersion int
+}
+
+// VaultSecretRef names one secret value that a connection test reads after
+// logging in.
+type VaultSecretRef struct {
+	Name      string
+	Mount     string
+	Path      string
+	Key       string
+	KVVersion int
+}
+
+type TestVaultConnectionRequest struct {
+	ID               int64
+	SecretIDOverride string
+	TestSecret       *VaultSecretRef
+}
+
+type TestVaultConnectionResult struct {
+	OK      bool
+	Message string
+}
+
+// VaultBackend stores Vault connections and talks to Vault. A failed login
+// or secret read is a TestVaultConnectionResult, not an error.
+type VaultBackend interface {
+	ListVaultConnections(context.Context) ([]VaultConnection, error)
+	UpsertVaultConnection(context.Context, UpsertVaultConnectionRequest) (VaultConnection, error)
+	DeleteVaultConnection(context.Context, int64) error
+	TestVaultConnection(context.Context, TestVaultConnectionRequest) (TestVaultConnectionResult, error)
+}
+
+// VaultConnections manages the global Vault connections that steps and
+// repository credentials resolve secrets through.
+type VaultConnections struct {
+	backend    VaultBackend
+	changes    *ChangeHub
+	authorizer *Authorizer
+}
+
+func NewVaultConnections(backend VaultBackend, changes *ChangeHub, authorizer *Authorizer) *VaultConnections {
+	return &VaultConnections{backend: backend, changes: changes, authorizer: authorizer}
+}
+
+func (v *VaultConnections) List(ctx context.Context) ([]VaultConnection, error) {
+	if v == nil || v.backend == nil {
+		return nil, NewError(ErrorUnavailable, "vault service unavailable", nil)
+	}
+	if err := v.authorizer.Authorize(ctx, ActionView); err != nil {
+		return nil, err
+	}
+	return v.backend.ListVaultConnections(ctx)
+}
+
+func (v *VaultConnections) Upsert(ctx context.Context, request UpsertVaultConnectionRequest) (VaultConnection, error) {
+	if v == nil || v.backend == nil {
+		return VaultConnection{}, NewError(ErrorUnavailable, "vault service unavailable", nil)
+	}
+	if err := v.authorizer.Authorize(ctx, ActionManageVault); err != nil {
+		return VaultConnection{}, err
+	}
+	if strings.TrimSpace(request.Name) == "" || strings.TrimSpace(request.URL) == "" || strings.TrimSpace(request.RoleID) == "" || strings.TrimSpace(request.SecretIDEnv) == "" {
+		return VaultConnection{}, NewError(ErrorInvalidArgument, "name, url, role_id and secret_id_env are required", nil)
+	}
+	connection, err := v.backend.UpsertVaultConnection(ctx, request)
+	if err != nil {
+		return VaultConnection{}, err
+	}
+	v.publish()
+	return connection, nil
+}
+
+func (v *VaultConnections) Delete(ctx context.Context, id int64) error {
+	if v == nil || v.backend == nil {
+		return NewError(ErrorUnavailable, "vault service unavailable", nil)
+	}
+	if err := v.authorizer.Authorize(ctx, ActionManageVault); err != nil {
+		return err
+	}
+	if id <= 0 {
+		return NewError(ErrorInvalidArgument, "invalid vault connection id", nil)
+	}
+	if err := v.backend.DeleteVaultConnection(ctx, id); err != nil {
+		return err
+	}
+	v.publish()
+	return nil
+}
+
+// Test logs in with the connection and, when asked, reads a secret. It
+// needs the same role as changing connections because it proves access to
+// Vault.
+func (v *VaultConnections) Test(ctx context.Context, request TestVaultConnectionRequest) (TestVaultConnectionResult, error) {
+	if v == nil || v.backend == nil {
+		return TestVaultConnectionResult{}, NewError(ErrorUnavailable, "vault service unavailable", nil)
+	}
+	if err := v.authorizer.Authorize(ctx, ActionManageVault); err != nil {
+		return TestVaultConnectionResult{}, err
+	}
+	if request.ID <= 0 {
+		return TestVaultConnectionResult{}, NewError(ErrorInvalidArgument, "invalid vault connection id", nil)
+	}
+	return v.backend.TestVaultConnection(ctx, request)
+}
+
+func (v *VaultConnections) publish() {
+	if v.changes != nil {
+		v.changes.Publish(ChangeVault)
+	}
+}
@@ -0,0 +1,75 @@
+package application
+
+import (
+	"context"
+	"testing"
+
+	"github.com/izzyreal/ciwi/internal/domain"
+)
+
+type vaultBackendStub struct {
+	upserted []UpsertVaultConnectionRequest
+	deleted  []int64
+	tested   []TestVaultConnectionRequest
+}
+
+func (s *vaultBackendStub) ListVaultConnections(context.Context) ([]VaultConnection, error) {
+	return []VaultConnection{{ID: 7, Name: "home-vault"}}, nil
+}
+func (s *vaultBackendStub) UpsertVaultConnection(_ context.Context, request UpsertVaultConnectionRequest) (VaultConnection, error) {
+	s.upserted = append(s.upserted, request)
+	return VaultConnection{ID: 8, Name: request.Name}, nil
+}
+func (s *vaultBackendStub) DeleteVaultConnection(_ context.Context, id int64) error {
+	s.deleted = append(s.deleted, id)
+	return nil
+}
+func (s *vaultBackendStub) TestVaultConnection(_ context.Context, request TestVaultConnectionRequest) (TestVaultConnectionResult, error) {
+	s.tested = append(s.tested, request)
+	return TestVaultConnectionResult{OK: true}, nil
+}
+
+func TestVaultConnectionsAuthorizeValidateAndPublish(t *testing.T) {
+	backend := &vaultBackendStub{}
+	hub := NewChangeHub()
+	vault := NewVaultConnections(backend, hub, NewAuthorizer(authenticationStateStub(true)))
+	viewer := WithPrincipal(context.Background(), domain.Principal{UserID: 1, Username: "v", Role: domain.RoleViewer})
+	admin := WithPrincipal(context.Background(), domain.Principal{UserID: 2, Username: "a", Role: domain.RoleAdmin})
+	valid := UpsertVaultConnectionRequest{Name: "home-vault", URL: "https://vault.example", RoleID: "role", SecretIDEnv: "VAULT_SECRET_ID"}
+
+	if _, err := vault.List(context.Background()); ErrorKindOf(err) != ErrorUnauthenticated {
+		t.Fatalf("list without a principal error = %v", err)
+	}
+	if connections, err := vault.List(viewer); err != nil || len(connections) != 1 {
+		t.Fatalf("viewer list = %+v, %v", connections, err)
+	}
+	if _, err := vault.Upsert(viewer, valid); ErrorKindOf(err) != ErrorPermissionDenied {
+		t.Fatalf("viewer upsert error = %v", err)
+	}
+	if err := vault.Delete(viewer, 7); ErrorKindOf(err) != ErrorPermissionDenied {
+		t.Fatalf("viewer delete error = %v", err)
+	}
+	if _, err := vault.Test(viewer, TestVaultConnectionRequest{ID: 7}); ErrorKindOf(err) != ErrorPermissionDenied {
+		t.Fatalf("viewer test error = %v", err)
+	}
+	if len(backend.upserted)+len(backend.deleted)+len(backend.tested) != 0 {
+		t.Fatalf("denied calls reached the backend: %+v", backend)
+	}
+
+	if _, err := vault.Upsert(admin, UpsertVaultConnectionRequest{Name: "home-vault", URL: "https://vault.example"}); ErrorKindOf(err) != ErrorInvalidArgument {
+		t.Fatalf("incomplete upsert error = %v", err)
+	}
+	before := hub.Snapshot().Revision
+	if _, err := vault.Upsert(admin, valid); err != nil {
+		t.Fatal(err)
+	}
+	if err := vault.Delete(admin, 7); err != nil {
+		t.Fatal(err)
+	}
+	if got := hub.Snapshot().Revision; got != before+2 {
+		t.Fatalf("revision = %d, want %d", got, before+2)
+	}
+	if result, err := vault.Test(admin, TestVaultConnectionRequest{ID: 7}); err != nil || !result.OK {
+		t.Fatalf("admin test = %+v, %v", result, err)
+	}
+}
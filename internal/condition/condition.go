@@ -0,0 +1,369 @@
+package condition
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Expression is a parsed `if:` condition. Conditions compare matrix variables
+// and ciwi.* run values with quoted string literals and combine the results
+// with !, && and ||. The status functions success(), failure() and always()
+// test the outcome of the steps that ran before.
+type Expression struct {
+	source     string
+	root       node
+	usesStatus bool
+}
+
+// Context supplies the values an expression is evaluated against.
+type Context struct {
+	// Vars holds matrix.<name> and ciwi.<name> values. Missing matrix
+	// variables evaluate to the empty string.
+	Vars map[string]string
+	// Failed reports whether an earlier step of the job failed.
+	Failed bool
+}
+
+// RunValues lists the ciwi.* names an expression may reference.
+var RunValues = []string{
+	"ciwi.dry_run",
+	"ciwi.pipeline",
+	"ciwi.project",
+	"ciwi.source_commit",
+	"ciwi.source_ref",
+	"ciwi.source_repo",
+	"ciwi.tag_prefix",
+	"ciwi.version",
+	"ciwi.version_raw",
+}
+
+var functionArity = map[string]int{
+	"success":    0,
+	"failure":    0,
+	"always":     0,
+	"contains":   2,
+	"startsWith": 2,
+	"endsWith":   2,
+}
+
+// Parse parses an `if:` expression.
+func Parse(source string) (*Expression, error) {
+	source = strings.TrimSpace(source)
+	if source == "" {
+		return nil, fmt.Errorf("condition is empty")
+	}
+	tokens, err := tokenize(source)
+	if err != nil {
+		return nil, fmt.Errorf("condition %q: %w", source, err)
+	}
+	p := &parser{tokens: tokens}
+	root, err := p.parseOr()
+	if err == nil && p.peek().kind != tokenEnd {
+		err = fmt.Errorf("unexpected %s", p.peek())
+	}
+	if err != nil {
+		return nil, fmt.Errorf("condition %q: %w", source, err)
+	}
+	return &Expression{source: source, root: root, usesStatus: p.usesStatus}, nil
+}
+
+// String returns the expression as written.
+func (e *Expression) String() string {
+	return e.source
+}
+
+// UsesStatus reports whether the expression calls success(), failure() or
+// always(). Step conditions without one only run while the job succeeds.
+func (e *Expression) UsesStatus() bool {
+	return e.usesStatus
+}
+
+// Evaluate reports whether the condition holds in ctx.
+func (e *Expression) Evaluate(ctx Context) bool {
+	return truthy(e.root.eval(ctx))
+}
+
+// SkipReason describes a step or job that did not run because its condition
+// was false.
+func SkipReason(source string) string {
+	return "condition not met: " + strings.TrimSpace(source)
+}
+
+func truthy(value string) bool {
+	return value != "" && value != "false" && value != "0"
+}
+
+func boolValue(value bool) string {
+	if value {
+		return "true"
+	}
+	return "false"
+}
+
+type node interface {
+	eval(Context) string
+}
+
+type literalNode struct{ value string }
+
+func (n literalNode) eval(Context) string { return n.value }
+
+type variableNode struct{ name string }
+
+func (n variableNode) eval(ctx Context) string { return ctx.Vars[n.name] }
+
+type notNode struct{ operand node }
+
+func (n notNode) eval(ctx Context) string { return boolValue(!truthy(n.operand.eval(ctx))) }
+
+type binaryNode struct {
+	op          string
+	left, right node
+}
+
+func (n binaryNode) eval(ctx Context) string {
+	switch n.op {
+	case "&&":
+		return boolValue(truthy(n.left.eval(ctx)) && truthy(n.right.eval(ctx)))
+	case "||":
+		return boolValue(truthy(n.left.eval(ctx)) || truthy(n.right.eval(ctx)))
+	case "==":
+		return boolValue(n.left.eval(ctx) == n.right.eval(ctx))
+	default:
+		return boolValue(n.left.eval(ctx) != n.right.eval(ctx))
+	}
+}
+
+type callNode struct {
+	name string
+	args []node
+}
+
+func (n callNode) eval(ctx Context) string {
+	switch n.name {
+	case "success":
+		return boolValue(!ctx.Failed)
+	case "failure":
+		return boolValue(ctx.Failed)
+	case "always":
+		return "true"
+	}
+	a, b := n.args[0].eval(ctx), n.args[1].eval(ctx)
+	switch n.name {
+	case "contains":
+		return boolValue(strings.Contains(a, b))
+	case "startsWith":
+		return boolValue(strings.HasPrefix(a, b))
+	default:
+		return boolValue(strings.HasSuffix(a, b))
+	}
+}
+
+type tokenKind int
+
+const (
+	tokenEnd tokenKind = iota
+	tokenIdent
+	tokenString
+	tokenOperator
+)
+
+type token struct {
+	kind  tokenKind
+	text  string
+	value string
+}
+
+func (t token) String() string {
+	if t.kind == tokenEnd {
+		return "end of expression"
+	}
+	return fmt.Sprintf("%q", t.text)
+}
+
+func tokenize(source string) ([]token, error) {
+	var tokens []token
+	for i := 0; i < len(source); {
+		c := source[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '\'' || c == '"':
+			end := strings.IndexByte(source[i+1:], c)
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated string at offset %d", i)
+			}
+			tokens = append(tokens, token{kind: tokenString, text: source[i : i+end+2], value: source[i+1 : i+1+end]})
+			i += end + 2
+		case isIdentByte(c):
+			start := i
+			for i < len(source) && (isIdentByte(source[i]) || source[i] == '.' || source[i] == '-') {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokenIdent, text: source[start:i]})
+		default:
+			op := ""
+			for _, candidate := range []string{"==", "!=", "&&", "||", "!", "(", ")", ","} {
+				if strings.HasPrefix(source[i:], candidate) {
+					op = candidate
+					break
+				}
+			}
+			if op == "" {
+				return nil, fmt.Errorf("unexpected character %q at offset %d", c, i)
+			}
+			tokens = append(tokens, token{kind: tokenOperator, text: op})
+			i += len(op)
+		}
+	}
+	return append(tokens, token{kind: tokenEnd}), nil
+}
+
+func isIdentByte(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+}
+
+type parser struct {
+	tokens     []token
+	pos        int
+	usesStatus bool
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokenEnd {
+		p.pos++
+	}
+	return t
+}
+
+func (p *parser) accept(op string) bool {
+	if t := p.peek(); t.kind == tokenOperator && t.text == op {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *parser) expect(op string) error {
+	if !p.accept(op) {
+		return fmt.Errorf("expected %q, found %s", op, p.peek())
+	}
+	return nil
+}
+
+func (p *parser) parseOr() (node, error) {
+	left, err := p.parseAnd()
+	for err == nil && p.accept("||") {
+		var right node
+		if right, err = p.parseAnd(); err == nil {
+			left = binaryNode{op: "||", left: left, right: right}
+		}
+	}
+	return left, err
+}
+
+func (p *parser) parseAnd() (node, error) {
+	left, err := p.parseUnary()
+	for err == nil && p.accept("&&") {
+		var right node
+		if right, err = p.parseUnary(); err == nil {
+			left = binaryNode{op: "&&", left: left, right: right}
+		}
+	}
+	return left, err
+}
+
+func (p *parser) parseUnary() (node, error) {
+	if p.accept("!") {
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return notNode{operand: operand}, nil
+	}
+	left, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	for _, op := range []string{"==", "!="} {
+		if p.accept(op) {
+			right, err := p.parsePrimary()
+			if err != nil {
+				return nil, err
+			}
+			return binaryNode{op: op, left: left, right: right}, nil
+		}
+	}
+	return left, nil
+}
+
+func (p *parser) parsePrimary() (node, error) {
+	if p.accept("(") {
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		return inner, p.expect(")")
+	}
+	t := p.next()
+	switch t.kind {
+	case tokenString:
+		return literalNode{value: t.value}, nil
+	case tokenIdent:
+		if p.accept("(") {
+			return p.parseCall(t.text)
+		}
+		return p.parseName(t.text)
+	}
+	return nil, fmt.Errorf("unexpected %s", t)
+}
+
+func (p *parser) parseName(name string) (node, error) {
+	switch {
+	case name == "true" || name == "false":
+		return literalNode{value: name}, nil
+	case strings.HasPrefix(name, "matrix.") && len(name) > len("matrix."):
+		return variableNode{name: name}, nil
+	case slices.Contains(RunValues, name):
+		return variableNode{name: name}, nil
+	case strings.HasPrefix(name, "ciwi."):
+		return nil, fmt.Errorf("unknown value %q (known: %s)", name, strings.Join(RunValues, ", "))
+	}
+	return nil, fmt.Errorf("unknown name %q; use matrix.<name>, ciwi.<name> or a quoted string", name)
+}
+
+func (p *parser) parseCall(name string) (node, error) {
+	arity, ok := functionArity[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown function %s()", name)
+	}
+	var args []node
+	if !p.accept(")") {
+		for {
+			arg, err := p.parseOr()
+			if err != nil {
+				return nil, err
+			}
+			args = append(args, arg)
+			if p.accept(")") {
+				break
+			}
+			if err := p.expect(","); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if len(args) != arity {
+		return nil, fmt.Errorf("%s() takes %d argument(s), got %d", name, arity, len(args))
+	}
+	if arity == 0 {
+		p.usesStatus = true
+	}
+	return callNode{name: name, args: args}, nil
+}
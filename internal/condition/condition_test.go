@@ -0,0 +1,86 @@
+package condition
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestEvaluate(t *testing.T) {
+	vars := map[string]string{
+		"matrix.os":       "linux",
+		"ciwi.source_ref": "refs/heads/main",
+		"ciwi.dry_run":    "false",
+		"ciwi.version":    "1.4.0",
+	}
+	cases := []struct {
+		expr   string
+		failed bool
+		want   bool
+	}{
+		{expr: "matrix.os == 'linux'", want: true},
+		{expr: `matrix.os != "linux"`, want: false},
+		{expr: "matrix.arch == ''", want: true},
+		{expr: "!ciwi.dry_run", want: true},
+		{expr: "ciwi.dry_run", want: false},
+		{expr: "endsWith(ciwi.source_ref, '/main') && !ciwi.dry_run", want: true},
+		{expr: "startsWith(ciwi.version, '2.') || contains(ciwi.version, '.4.')", want: true},
+		{expr: "!(matrix.os == 'linux' || matrix.os == 'macos')", want: false},
+		{expr: "success()", want: true},
+		{expr: "success()", failed: true, want: false},
+		{expr: "failure() && matrix.os == 'linux'", failed: true, want: true},
+		{expr: "failure()", want: false},
+		{expr: "always()", failed: true, want: true},
+		{expr: "true", want: true},
+	}
+	for _, tc := range cases {
+		expr, err := Parse(tc.expr)
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", tc.expr, err)
+		}
+		if got := expr.Evaluate(Context{Vars: vars, Failed: tc.failed}); got != tc.want {
+			t.Fatalf("Evaluate(%q, failed=%v) = %v, want %v", tc.expr, tc.failed, got, tc.want)
+		}
+	}
+}
+
+func TestUsesStatus(t *testing.T) {
+	for expr, want := range map[string]bool{
+		"matrix.os == 'linux'":             false,
+		"contains(matrix.os, 'li')":        false,
+		"failure()":                        true,
+		"always() && matrix.os == 'linux'": true,
+	} {
+		parsed, err := Parse(expr)
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", expr, err)
+		}
+		if parsed.UsesStatus() != want {
+			t.Fatalf("UsesStatus(%q) = %v, want %v", expr, parsed.UsesStatus(), want)
+		}
+	}
+}
+
+func TestParseRejectsInvalidExpressions(t *testing.T) {
+	cases := map[string]string{
+		"":                          "empty",
+		"matrix.os == 'linux":       "unterminated string",
+		"ciwi.branch == 'main'":     `unknown value "ciwi.branch"`,
+		"env.HOME == '/root'":       `unknown name "env.HOME"`,
+		"matrix.os = 'linux'":       "unexpected character",
+		"failure('x')":              "takes 0 argument(s)",
+		"exec('rm -rf /')":          "unknown function exec()",
+		"(matrix.os == 'linux'":     `expected ")"`,
+		"matrix.os == 'linux' 'x'":  "unexpected",
+		"matrix.os == 'a' == 'b'":   "unexpected",
+		"startsWith(ciwi.version)":  "takes 2 argument(s)",
+		"matrix.os == 'linux' &&":   "end of expression",
+		"matrix. == 'linux'":        `unknown name "matrix."`,
+		"contains(matrix.os, 'x',)": "unexpected",
+	}
+	for expr, want := range cases {
+		_, err := Parse(expr)
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Fatalf("Parse(%q) error = %v, want %q", expr, err, want)
+		}
+	}
+}
@@ -8,7 +8,9 @@ import (
 	"slices"
 	"strings"
 
+	"github.com/izzyreal/ciwi/internal/condition"
 	"github.com/izzyreal/ciwi/internal/pipelinechain"
+	"github.com/izzyreal/ciwi/internal/schedule"
 	"gopkg.in/yaml.v3"
 )
 
@@ -37,17 +39,48 @@ type StepVaultSecretRef struct {
 }
 
 type Pipeline struct {
-	ID         string              `yaml:"id" json:"id"`
-	Trigger    string              `yaml:"trigger" json:"trigger"`
-	DependsOn  []string            `yaml:"depends_on,omitempty" json:"depends_on,omitempty"`
-	VCSSource  *Source             `yaml:"vcs_source,omitempty" json:"vcs_source,omitempty"`
-	Versioning *PipelineVersioning `yaml:"versioning,omitempty" json:"versioning,omitempty"`
-	Jobs       []PipelineJobSpec   `yaml:"jobs" json:"jobs"`
+	ID          string              `yaml:"id" json:"id"`
+	Trigger     string              `yaml:"trigger" json:"trigger"`
+	DependsOn   []string            `yaml:"depends_on,omitempty" json:"depends_on,omitempty"`
+	VCSSource   *Source             `yaml:"vcs_source,omitempty" json:"vcs_source,omitempty"`
+	Versioning  *PipelineVersioning `yaml:"versioning,omitempty" json:"versioning,omitempty"`
+	Schedule    *Schedule           `yaml:"schedule,omitempty" json:"schedule,omitempty"`
+	Priority    int                 `yaml:"priority,omitempty" json:"priority,omitempty"`
+	Concurrency *Concurrency        `yaml:"concurrency,omitempty" json:"concurrency,omitempty"`
+	Jobs        []PipelineJobSpec   `yaml:"jobs" json:"jobs"`
 }
 
 type PipelineChain struct {
-	Name      string   `yaml:"name,omitempty" json:"name,omitempty"`
-	Pipelines []string `yaml:"pipelines" json:"pipelines"`
+	Name      string    `yaml:"name,omitempty" json:"name,omitempty"`
+	Pipelines []string  `yaml:"pipelines" json:"pipelines"`
+	Schedule  *Schedule `yaml:"schedule,omitempty" json:"schedule,omitempty"`
+}
+
+// Schedule starts runs of a pipeline with trigger: schedule, or of a pipeline
+// chain, at the times matched by a five-field cron expression.
+type Schedule struct {
+	Cron string `yaml:"cron" json:"cron"`
+	// Timezone is an IANA zone name the expression is evaluated in; UTC when
+	// empty.
+	Timezone  string `yaml:"timezone,omitempty" json:"timezone,omitempty"`
+	SourceRef string `yaml:"source_ref,omitempty" json:"source_ref,omitempty"`
+	DryRun    bool   `yaml:"dry_run,omitempty" json:"dry_run,omitempty"`
+	// CatchUp decides what happens to fires missed while the server was
+	// down: skip drops them, once starts a single run for all of them.
+	CatchUp string `yaml:"catch_up,omitempty" json:"catch_up,omitempty"`
+}
+
+const (
+	ScheduleCatchUpSkip = "skip"
+	ScheduleCatchUpOnce = "once"
+)
+
+// EffectiveCatchUp returns the configured catch-up policy, defaulting to skip.
+func (s Schedule) EffectiveCatchUp() string {
+	if policy := strings.TrimSpace(s.CatchUp); policy != "" {
+		return policy
+	}
+	return ScheduleCatchUpSkip
 }
 
 type PipelineVersioning struct {
@@ -61,19 +94,52 @@ type PipelineVersioning struct {
 type Source struct {
 	Repo string `yaml:"repo" json:"repo"`
 	Ref  string `yaml:"ref" json:"ref"`
+	// PollIntervalSeconds overrides the server-wide poll interval for
+	// pipelines with trigger: vcs. Zero means use the server default.
+	PollIntervalSeconds int `yaml:"poll_interval_seconds,omitempty" json:"poll_interval_seconds,omitempty"`
+	CheckoutOptions     `yaml:",inline"`
 }
 
+// CheckoutOptions shape how agents check a vcs_source out. The zero value is
+// a shallow clone of the one commit, without tags, submodules or LFS objects.
+type CheckoutOptions struct {
+	// Depth is the number of commits to fetch; nil keeps the default of one
+	// and 0 fetches the full history.
+	Depth      *int     `yaml:"depth,omitempty" json:"depth,omitempty"`
+	Submodules string   `yaml:"submodules,omitempty" json:"submodules,omitempty"`
+	LFS        bool     `yaml:"lfs,omitempty" json:"lfs,omitempty"`
+	Sparse     []string `yaml:"sparse,omitempty" json:"sparse,omitempty"`
+	FetchTags  bool     `yaml:"fetch_tags,omitempty" json:"fetch_tags,omitempty"`
+}
+
+// Submodule checkout modes for vcs_source.submodules.
+const (
+	SubmodulesNone      = "none"
+	SubmodulesTop       = "top"
+	SubmodulesRecursive = "recursive"
+)
+
+// MinVCSPollIntervalSeconds bounds how often the server may poll a single
+// vcs_source for new commits.
+const MinVCSPollIntervalSeconds = 10
+
 type PipelineJobSpec struct {
 	ID              string                      `yaml:"id" json:"id"`
+	If              string                      `yaml:"if,omitempty" json:"if,omitempty"`
 	Needs           []string                    `yaml:"needs,omitempty" json:"needs,omitempty"`
 	ArtifactSources []PipelineJobArtifactSource `yaml:"artifact_sources,omitempty" json:"artifact_sources,omitempty"`
-	RunsOn          map[string]string           `yaml:"runs_on" json:"runs_on"`
+	RunsOn          RunsOn                      `yaml:"runs_on" json:"runs_on"`
 	Requires        PipelineJobRequirements     `yaml:"requires,omitempty" json:"requires,omitempty"`
 	TimeoutSeconds  int                         `yaml:"timeout_seconds" json:"timeout_seconds"`
 	Artifacts       []string                    `yaml:"artifacts" json:"artifacts"`
 	Caches          []PipelineJobCacheSpec      `yaml:"caches,omitempty" json:"caches,omitempty"`
+	Services        []PipelineJobServiceSpec    `yaml:"services,omitempty" json:"services,omitempty"`
 	GoCache         *PipelineJobGoCacheSpec     `yaml:"go_cache,omitempty" json:"go_cache,omitempty"`
+	Retry           *RetryPolicy                `yaml:"retry,omitempty" json:"retry,omitempty"`
+	Priority        *int                        `yaml:"priority,omitempty" json:"priority,omitempty"`
+	Concurrency     *Concurrency                `yaml:"concurrency,omitempty" json:"concurrency,omitempty"`
 	Matrix          PipelineJobMatrix           `yaml:"matrix" json:"matrix"`
+	Shards          int                         `yaml:"shards,omitempty" json:"shards,omitempty"`
 	Steps           []PipelineJobStep           `yaml:"steps" json:"steps"`
 }
 
@@ -88,10 +154,86 @@ type PipelineJobCacheSpec struct {
 	Env string `yaml:"env,omitempty" json:"env,omitempty"`
 }
 
+// PipelineJobServiceSpec is a container the agent runs next to the job's
+// steps on a per-job network. Steps reach it through the
+// CIWI_SERVICE_<NAME>_HOST and CIWI_SERVICE_<NAME>_PORT variables; the
+// health check runs inside the service container and must pass before the
+// first step starts.
+type PipelineJobServiceSpec struct {
+	Name                 string            `yaml:"name" json:"name"`
+	Image                string            `yaml:"image" json:"image"`
+	Env                  map[string]string `yaml:"env,omitempty" json:"env,omitempty"`
+	Ports                []int             `yaml:"ports,omitempty" json:"ports,omitempty"`
+	HealthCheck          string            `yaml:"health_check,omitempty" json:"health_check,omitempty"`
+	HealthTimeoutSeconds int               `yaml:"health_timeout_seconds,omitempty" json:"health_timeout_seconds,omitempty"`
+}
+
 type PipelineJobGoCacheSpec struct {
 	Enabled *bool `yaml:"enabled,omitempty" json:"enabled,omitempty"`
 }
 
+// RunsOn selects the agents a job may run on. Next to flat keys such as os
+// and arch it accepts a labels mapping, which is kept flattened under
+// RunsOnLabelPrefix so a job's requirements remain one map of capabilities.
+type RunsOn map[string]string
+
+const RunsOnLabelPrefix = "label."
+
+func (r *RunsOn) UnmarshalYAML(value *yaml.Node) error {
+	if value.Tag == "!!null" {
+		*r = nil
+		return nil
+	}
+	if value.Kind != yaml.MappingNode {
+		return fmt.Errorf("line %d: runs_on must be a mapping", value.Line)
+	}
+	runsOn := RunsOn{}
+	for i := 0; i+1 < len(value.Content); i += 2 {
+		key, node := value.Content[i].Value, value.Content[i+1]
+		if key == "labels" {
+			var labels map[string]string
+			if err := node.Decode(&labels); err != nil {
+				return err
+			}
+			for label, labelValue := range labels {
+				runsOn[RunsOnLabelPrefix+label] = labelValue
+			}
+			continue
+		}
+		var capability string
+		if err := node.Decode(&capability); err != nil {
+			return err
+		}
+		runsOn[key] = capability
+	}
+	*r = runsOn
+	return nil
+}
+
+func (r RunsOn) MarshalYAML() (any, error) {
+	out := map[string]any{}
+	for key, value := range r {
+		if !strings.HasPrefix(key, RunsOnLabelPrefix) {
+			out[key] = value
+		}
+	}
+	if labels := r.Labels(); len(labels) > 0 {
+		out["labels"] = labels
+	}
+	return out, nil
+}
+
+// Labels returns the agent labels the job requires.
+func (r RunsOn) Labels() map[string]string {
+	labels := map[string]string{}
+	for key, value := range r {
+		if label, ok := strings.CutPrefix(key, RunsOnLabelPrefix); ok {
+			labels[label] = value
+		}
+	}
+	return labels
+}
+
 type PipelineJobRequirements struct {
 	Tools     map[string]string                `yaml:"tools,omitempty" json:"tools,omitempty"`
 	Container PipelineJobContainerRequirements `yaml:"container,omitempty" json:"container,omitempty"`
@@ -109,18 +251,82 @@ type PipelineJobStep struct {
 	Name       string               `yaml:"name,omitempty" json:"name,omitempty"`
 	Run        string               `yaml:"run,omitempty" json:"run,omitempty"`
 	Test       *PipelineJobTestStep `yaml:"test,omitempty" json:"test,omitempty"`
+	If         string               `yaml:"if,omitempty" json:"if,omitempty"`
+	Retry      *RetryPolicy         `yaml:"retry,omitempty" json:"retry,omitempty"`
 	SkipDryRun bool                 `yaml:"skip_dry_run,omitempty" json:"skip_dry_run,omitempty"`
 	Env        map[string]string    `yaml:"env,omitempty" json:"env,omitempty"`
 	Vault      *StepVault           `yaml:"vault,omitempty" json:"vault,omitempty"`
 }
 
+// RetryPolicy re-runs a failed job or step. A failed job is retried as a new
+// linked attempt; a failed step is re-run in place within the same attempt.
+type RetryPolicy struct {
+	// MaxAttempts counts the first run, so 3 allows two retries.
+	MaxAttempts int `yaml:"max_attempts" json:"max_attempts"`
+	// BackoffSeconds is the wait before the first retry; it doubles for each
+	// further retry.
+	BackoffSeconds int `yaml:"backoff_seconds,omitempty" json:"backoff_seconds,omitempty"`
+	// On lists the failure kinds that qualify. Jobs default to all kinds;
+	// steps only support exit_code.
+	On []string `yaml:"on,omitempty" json:"on,omitempty"`
+}
+
+// Concurrency limits how many runs of a group run at once. On a pipeline a
+// whole run holds the group; on a job each execution does.
+type Concurrency struct {
+	// Group may interpolate {{ciwi.*}} and {{matrix.*}} values.
+	Group string `yaml:"group" json:"group"`
+	// CancelInProgress cancels running holders of the group when a newer run
+	// is queued; queued ones are always superseded.
+	CancelInProgress bool `yaml:"cancel_in_progress,omitempty" json:"cancel_in_progress,omitempty"`
+	// MaxRunning is how many holders may run at once; zero means one.
+	MaxRunning int `yaml:"max_running,omitempty" json:"max_running,omitempty"`
+}
+
+// EffectiveMaxRunning returns MaxRunning, defaulting to one.
+func (c Concurrency) EffectiveMaxRunning() int {
+	if c.MaxRunning <= 0 {
+		return 1
+	}
+	return c.MaxRunning
+}
+
+const (
+	RetryOnExitCode  = "exit_code"
+	RetryOnTimeout   = "timeout"
+	RetryOnAgentLost = "agent_lost"
+)
+
+// EffectiveOn returns the configured failure kinds, defaulting to every kind.
+func (r RetryPolicy) EffectiveOn() []string {
+	if len(r.On) == 0 {
+		return []string{RetryOnExitCode, RetryOnTimeout, RetryOnAgentLost}
+	}
+	out := make([]string, 0, len(r.On))
+	for _, kind := range r.On {
+		out = append(out, strings.TrimSpace(kind))
+	}
+	return out
+}
+
 type PipelineJobTestStep struct {
-	Name           string `yaml:"name,omitempty" json:"name,omitempty"`
-	Command        string `yaml:"command" json:"command"`
-	Format         string `yaml:"format,omitempty" json:"format,omitempty"`
-	Report         string `yaml:"report,omitempty" json:"report,omitempty"`
-	CoverageFormat string `yaml:"coverage_format,omitempty" json:"coverage_format,omitempty"`
-	CoverageReport string `yaml:"coverage_report,omitempty" json:"coverage_report,omitempty"`
+	Name           string        `yaml:"name,omitempty" json:"name,omitempty"`
+	Command        string        `yaml:"command" json:"command"`
+	Format         string        `yaml:"format,omitempty" json:"format,omitempty"`
+	Report         string        `yaml:"report,omitempty" json:"report,omitempty"`
+	CoverageFormat string        `yaml:"coverage_format,omitempty" json:"coverage_format,omitempty"`
+	CoverageReport string        `yaml:"coverage_report,omitempty" json:"coverage_report,omitempty"`
+	CoverageGate   *CoverageGate `yaml:"coverage_gate,omitempty" json:"coverage_gate,omitempty"`
+	Shards         int           `yaml:"shards,omitempty" json:"shards,omitempty"`
+}
+
+// CoverageGate fails a job whose coverage is below MinPercent, or that
+// dropped by more than MaxDrop percentage points since the last successful
+// run of the same job on the same branch. The server evaluates it when the
+// coverage report is uploaded.
+type CoverageGate struct {
+	MinPercent *float64 `yaml:"min_percent,omitempty" json:"min_percent,omitempty"`
+	MaxDrop    *float64 `yaml:"max_drop,omitempty" json:"max_drop,omitempty"`
 }
 
 func Load(path string) (File, error) {
@@ -172,9 +378,18 @@ func (cfg File) Validate() []string {
 			pipelineIDs[p.ID] = struct{}{}
 		}
 
-		if strings.TrimSpace(p.Trigger) != "" && !slices.Contains([]string{"manual", "vcs"}, p.Trigger) {
-			errs = append(errs, fmt.Sprintf("pipelines[%d].trigger must be one of manual,vcs", i))
+		if strings.TrimSpace(p.Trigger) != "" && !slices.Contains([]string{"manual", "vcs", "schedule"}, p.Trigger) {
+			errs = append(errs, fmt.Sprintf("pipelines[%d].trigger must be one of manual,vcs,schedule", i))
+		}
+		errs = append(errs, validatePriority(fmt.Sprintf("pipelines[%d].priority", i), p.Priority)...)
+		errs = append(errs, validateConcurrency(fmt.Sprintf("pipelines[%d].concurrency", i), p.Concurrency)...)
+		if p.Trigger == "schedule" && p.Schedule == nil {
+			errs = append(errs, fmt.Sprintf("pipelines[%d].schedule is required when trigger is schedule", i))
+		}
+		if p.Schedule != nil && p.Trigger != "schedule" {
+			errs = append(errs, fmt.Sprintf("pipelines[%d].schedule requires trigger schedule", i))
 		}
+		errs = append(errs, validateSchedule(fmt.Sprintf("pipelines[%d].schedule", i), p.Schedule)...)
 
 		for j, dep := range p.DependsOn {
 			if strings.TrimSpace(dep) == "" {
@@ -188,6 +403,15 @@ func (cfg File) Validate() []string {
 		if p.VCSSource != nil && strings.TrimSpace(p.VCSSource.Repo) == "" {
 			errs = append(errs, fmt.Sprintf("pipelines[%d].vcs_source.repo is required when vcs_source is set", i))
 		}
+		if p.Trigger == "vcs" && p.VCSSource == nil {
+			errs = append(errs, fmt.Sprintf("pipelines[%d].vcs_source is required when trigger is vcs", i))
+		}
+		if p.VCSSource != nil {
+			if interval := p.VCSSource.PollIntervalSeconds; interval < 0 || (interval > 0 && interval < MinVCSPollIntervalSeconds) {
+				errs = append(errs, fmt.Sprintf("pipelines[%d].vcs_source.poll_interval_seconds must be 0 or at least %d", i, MinVCSPollIntervalSeconds))
+			}
+			errs = append(errs, validateCheckoutOptions(fmt.Sprintf("pipelines[%d].vcs_source", i), p.VCSSource.CheckoutOptions)...)
+		}
 		if p.Versioning != nil && (p.VCSSource == nil || strings.TrimSpace(p.VCSSource.Repo) == "") {
 			errs = append(errs, fmt.Sprintf("pipelines[%d].vcs_source.repo is required when versioning is set", i))
 		}
@@ -257,6 +481,7 @@ func (cfg File) Validate() []string {
 			containerImage := strings.TrimSpace(job.RunsOn["container_image"])
 			containerDevices := strings.TrimSpace(job.RunsOn["container_devices"])
 			containerGroups := strings.TrimSpace(job.RunsOn["container_groups"])
+			containerRuntime := strings.TrimSpace(job.RunsOn["container_runtime"])
 			if executor != "" && executor != "script" {
 				errs = append(errs, fmt.Sprintf("pipelines[%d].jobs[%d].runs_on.executor must be \"script\"", i, j))
 			}
@@ -269,9 +494,25 @@ func (cfg File) Validate() []string {
 			if shell != "" && executor != "script" {
 				errs = append(errs, fmt.Sprintf("pipelines[%d].jobs[%d].runs_on.executor must be \"script\" when runs_on.shell is set", i, j))
 			}
+			errs = append(errs, validateRunsOnLabels(fmt.Sprintf("pipelines[%d].jobs[%d].runs_on.labels", i, j), job.RunsOn.Labels())...)
 			if len(job.Steps) == 0 {
 				errs = append(errs, fmt.Sprintf("pipelines[%d].jobs[%d].steps must contain at least one step", i, j))
 			}
+			if strings.TrimSpace(job.If) != "" {
+				if expr, err := condition.Parse(job.If); err != nil {
+					errs = append(errs, fmt.Sprintf("pipelines[%d].jobs[%d].if: %v", i, j, err))
+				} else if expr.UsesStatus() {
+					errs = append(errs, fmt.Sprintf("pipelines[%d].jobs[%d].if must not use success(), failure() or always(); they only apply to steps", i, j))
+				}
+			}
+			if job.Retry != nil {
+				errs = append(errs, validateRetryPolicy(fmt.Sprintf("pipelines[%d].jobs[%d].retry", i, j), job.Retry, false)...)
+			}
+			errs = append(errs, validatePipelineJobShards(fmt.Sprintf("pipelines[%d].jobs[%d]", i, j), job)...)
+			if job.Priority != nil {
+				errs = append(errs, validatePriority(fmt.Sprintf("pipelines[%d].jobs[%d].priority", i, j), *job.Priority)...)
+			}
+			errs = append(errs, validateConcurrency(fmt.Sprintf("pipelines[%d].jobs[%d].concurrency", i, j), job.Concurrency)...)
 			for tool, constraint := range job.Requires.Tools {
 				if strings.TrimSpace(tool) == "" {
 					errs = append(errs, fmt.Sprintf("pipelines[%d].jobs[%d].requires.tools contains empty tool name", i, j))
@@ -303,6 +544,14 @@ func (cfg File) Validate() []string {
 			if containerGroups != "" && containerImage == "" {
 				errs = append(errs, fmt.Sprintf("pipelines[%d].jobs[%d].runs_on.container_image is required when runs_on.container_groups is set", i, j))
 			}
+			if containerRuntime != "" {
+				if containerRuntime != "docker" && containerRuntime != "podman" && containerRuntime != "nerdctl" {
+					errs = append(errs, fmt.Sprintf("pipelines[%d].jobs[%d].runs_on.container_runtime must be one of docker,podman,nerdctl", i, j))
+				}
+				if containerImage == "" {
+					errs = append(errs, fmt.Sprintf("pipelines[%d].jobs[%d].runs_on.container_image is required when runs_on.container_runtime is set", i, j))
+				}
+			}
 			cacheIDs := map[string]struct{}{}
 			for cIdx, c := range job.Caches {
 				cacheID := strings.TrimSpace(c.ID)
@@ -319,6 +568,32 @@ func (cfg File) Validate() []string {
 					errs = append(errs, fmt.Sprintf("pipelines[%d].jobs[%d].caches[%d].env is required", i, j, cIdx))
 				}
 			}
+			serviceNames := map[string]struct{}{}
+			for sIdx, svc := range job.Services {
+				name := strings.TrimSpace(svc.Name)
+				if name == "" {
+					errs = append(errs, fmt.Sprintf("pipelines[%d].jobs[%d].services[%d].name is required", i, j, sIdx))
+				} else {
+					if !serviceNamePattern.MatchString(name) {
+						errs = append(errs, fmt.Sprintf("pipelines[%d].jobs[%d].services[%d].name %q must be lowercase letters, digits, '-' or '_'", i, j, sIdx, name))
+					}
+					if _, exists := serviceNames[name]; exists {
+						errs = append(errs, fmt.Sprintf("pipelines[%d].jobs[%d].services[%d].name duplicate %q", i, j, sIdx, name))
+					}
+					serviceNames[name] = struct{}{}
+				}
+				if strings.TrimSpace(svc.Image) == "" {
+					errs = append(errs, fmt.Sprintf("pipelines[%d].jobs[%d].services[%d].image is required", i, j, sIdx))
+				}
+				for pIdx, port := range svc.Ports {
+					if port < 1 || port > 65535 {
+						errs = append(errs, fmt.Sprintf("pipelines[%d].jobs[%d].services[%d].ports[%d] must be between 1 and 65535", i, j, sIdx, pIdx))
+					}
+				}
+				if svc.HealthTimeoutSeconds < 0 {
+					errs = append(errs, fmt.Sprintf("pipelines[%d].jobs[%d].services[%d].health_timeout_seconds must be >= 0", i, j, sIdx))
+				}
+			}
 			for k, st := range job.Steps {
 				runSet := strings.TrimSpace(st.Run) != ""
 				testSet := st.Test != nil
@@ -338,7 +613,7 @@ func (cfg File) Validate() []string {
 					}
 					if format := strings.TrimSpace(st.Test.Format); format != "" {
 						switch format {
-						case "go-test-json", "junit", "junit-xml":
+						case "go-test-json", "junit", "junit-xml", "tap", "trx", "libtest-json", "nextest-json", "pytest-reportlog":
 						default:
 							errs = append(errs, fmt.Sprintf("pipelines[%d].jobs[%d].steps[%d].test.format unsupported %q", i, j, k, st.Test.Format))
 						}
@@ -358,7 +633,7 @@ func (cfg File) Validate() []string {
 					}
 					if coverageFormat != "" {
 						switch coverageFormat {
-						case "go-coverprofile", "lcov":
+						case "go-coverprofile", "lcov", "cobertura", "jacoco":
 						default:
 							errs = append(errs, fmt.Sprintf("pipelines[%d].jobs[%d].steps[%d].test.coverage_format unsupported %q", i, j, k, st.Test.CoverageFormat))
 						}
@@ -366,6 +641,13 @@ func (cfg File) Validate() []string {
 					if coverageReport == "" && coverageFormat != "" {
 						errs = append(errs, fmt.Sprintf("pipelines[%d].jobs[%d].steps[%d].test.coverage_report is required when coverage_format is set", i, j, k))
 					}
+					if st.Test.CoverageGate != nil {
+						prefix := fmt.Sprintf("pipelines[%d].jobs[%d].steps[%d].test.coverage_gate", i, j, k)
+						if coverageReport == "" {
+							errs = append(errs, prefix+" requires coverage_report")
+						}
+						errs = append(errs, validateCoverageGate(prefix, st.Test.CoverageGate)...)
+					}
 				}
 				for envK := range st.Env {
 					if strings.TrimSpace(envK) == "" {
@@ -375,6 +657,14 @@ func (cfg File) Validate() []string {
 				if st.Vault != nil {
 					errs = append(errs, validateVaultRef(fmt.Sprintf("pipelines[%d].jobs[%d].steps[%d].vault", i, j, k), st.Vault)...)
 				}
+				if strings.TrimSpace(st.If) != "" {
+					if _, err := condition.Parse(st.If); err != nil {
+						errs = append(errs, fmt.Sprintf("pipelines[%d].jobs[%d].steps[%d].if: %v", i, j, k, err))
+					}
+				}
+				if st.Retry != nil {
+					errs = append(errs, validateRetryPolicy(fmt.Sprintf("pipelines[%d].jobs[%d].steps[%d].retry", i, j, k), st.Retry, true)...)
+				}
 			}
 		}
 
@@ -500,6 +790,7 @@ func (cfg File) Validate() []string {
 
 	chainSequences := map[string]int{}
 	for i, ch := range cfg.PipelineChains {
+		errs = append(errs, validateSchedule(fmt.Sprintf("pipeline_chains[%d].schedule", i), ch.Schedule)...)
 		if len(ch.Pipelines) == 0 {
 			errs = append(errs, fmt.Sprintf("pipeline_chains[%d].pipelines must contain at least one pipeline id", i))
 			continue
@@ -530,6 +821,201 @@ func (cfg File) Validate() []string {
 	return errs
 }
 
+func validateSchedule(prefix string, sched *Schedule) []string {
+	if sched == nil {
+		return nil
+	}
+	var errs []string
+	if strings.TrimSpace(sched.Cron) == "" {
+		errs = append(errs, prefix+".cron is required")
+	} else if _, err := schedule.Parse(sched.Cron); err != nil {
+		errs = append(errs, fmt.Sprintf("%s.cron is invalid: %v", prefix, err))
+	}
+	if _, err := schedule.Location(sched.Timezone); err != nil {
+		errs = append(errs, fmt.Sprintf("%s.timezone is invalid: %v", prefix, err))
+	}
+	if !slices.Contains([]string{ScheduleCatchUpSkip, ScheduleCatchUpOnce}, sched.EffectiveCatchUp()) {
+		errs = append(errs, fmt.Sprintf("%s.catch_up must be one of %s,%s", prefix, ScheduleCatchUpSkip, ScheduleCatchUpOnce))
+	}
+	return errs
+}
+
+// validateCheckoutOptions checks the checkout options of a vcs_source. Sparse
+// paths are directories in cone mode, so they must stay inside the repository.
+func validateCheckoutOptions(prefix string, options CheckoutOptions) []string {
+	var errs []string
+	if options.Depth != nil && *options.Depth < 0 {
+		errs = append(errs, prefix+".depth must be >= 0")
+	}
+	if !slices.Contains([]string{"", SubmodulesNone, SubmodulesTop, SubmodulesRecursive}, strings.TrimSpace(options.Submodules)) {
+		errs = append(errs, fmt.Sprintf("%s.submodules must be one of %s,%s,%s", prefix, SubmodulesNone, SubmodulesTop, SubmodulesRecursive))
+	}
+	for i, sparsePath := range options.Sparse {
+		sparsePath = strings.TrimSpace(sparsePath)
+		if sparsePath == "" || strings.HasPrefix(sparsePath, "/") || strings.HasPrefix(sparsePath, "-") || slices.Contains(strings.Split(sparsePath, "/"), "..") {
+			errs = append(errs, fmt.Sprintf("%s.sparse[%d] must be a relative in-repo directory", prefix, i))
+		}
+	}
+	return errs
+}
+
+// validateRetryPolicy checks a job or step retry block. Step retries happen
+// inside a running job, so a timeout or lost agent cannot be retried there.
+func validateRetryPolicy(prefix string, retry *RetryPolicy, step bool) []string {
+	var errs []string
+	if retry.MaxAttempts < 1 {
+		errs = append(errs, prefix+".max_attempts must be >= 1")
+	}
+	if retry.BackoffSeconds < 0 {
+		errs = append(errs, prefix+".backoff_seconds must be >= 0")
+	}
+	allowed := []string{RetryOnExitCode, RetryOnTimeout, RetryOnAgentLost}
+	if step {
+		allowed = []string{RetryOnExitCode}
+	}
+	for k, kind := range retry.On {
+		if !slices.Contains(allowed, strings.TrimSpace(kind)) {
+			errs = append(errs, fmt.Sprintf("%s.on[%d] must be one of %s", prefix, k, strings.Join(allowed, ",")))
+		}
+	}
+	return errs
+}
+
+// Priorities order queued executions; higher runs first and zero is the
+// default.
+const (
+	MinPriority = -100
+	MaxPriority = 100
+)
+
+// EffectivePipelineJobPriority returns the job's priority, or the pipeline's
+// when the job does not set one. An explicit zero on the job wins.
+func EffectivePipelineJobPriority(pipelinePriority int, job PipelineJobSpec) int {
+	if job.Priority != nil {
+		return *job.Priority
+	}
+	return pipelinePriority
+}
+
+func validatePriority(field string, priority int) []string {
+	if priority < MinPriority || priority > MaxPriority {
+		return []string{fmt.Sprintf("%s must be between %d and %d", field, MinPriority, MaxPriority)}
+	}
+	return nil
+}
+
+var concurrencyGroupPlaceholder = regexp.MustCompile(`{{([^}]*)}}`)
+
+func validateConcurrency(field string, concurrency *Concurrency) []string {
+	if concurrency == nil {
+		return nil
+	}
+	var errs []string
+	if strings.TrimSpace(concurrency.Group) == "" {
+		errs = append(errs, field+".group is required")
+	}
+	for _, match := range concurrencyGroupPlaceholder.FindAllStringSubmatch(concurrency.Group, -1) {
+		if !strings.HasPrefix(match[1], "ciwi.") && !strings.HasPrefix(match[1], "matrix.") {
+			errs = append(errs, fmt.Sprintf("%s.group may only interpolate ciwi.* and matrix.* values, got %q", field, match[0]))
+		}
+	}
+	if concurrency.MaxRunning < 0 {
+		errs = append(errs, field+".max_running must be >= 0")
+	}
+	return errs
+}
+
+var runsOnLabelKeyPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_.-]*$`)
+
+// serviceNamePattern keeps service names usable as network aliases and, once
+// uppercased, as environment variable names.
+var serviceNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+func validateRunsOnLabels(field string, labels map[string]string) []string {
+	keys := make([]string, 0, len(labels))
+	for key := range labels {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	var errs []string
+	for _, key := range keys {
+		if !runsOnLabelKeyPattern.MatchString(key) {
+			errs = append(errs, fmt.Sprintf("%s key %q may only contain letters, digits, '.', '_' and '-'", field, key))
+		} else if strings.TrimSpace(labels[key]) == "" {
+			errs = append(errs, fmt.Sprintf("%s.%s must not be empty", field, key))
+		}
+	}
+	return errs
+}
+
+// MaxPipelineJobShards bounds how many executions one matrix entry may be
+// split into.
+const MaxPipelineJobShards = 64
+
+// EffectivePipelineJobShards returns how many shards each matrix entry of a
+// job runs as: the job's shards, or else those of its test steps. Zero means
+// the job is not sharded.
+func EffectivePipelineJobShards(job PipelineJobSpec) int {
+	if job.Shards > 0 {
+		return job.Shards
+	}
+	for _, step := range job.Steps {
+		if step.Test != nil && step.Test.Shards > 0 {
+			return step.Test.Shards
+		}
+	}
+	return 0
+}
+
+func validatePipelineJobShards(prefix string, job PipelineJobSpec) []string {
+	var errs []string
+	if job.Shards < 0 || job.Shards > MaxPipelineJobShards {
+		errs = append(errs, fmt.Sprintf("%s.shards must be between 1 and %d", prefix, MaxPipelineJobShards))
+	}
+	effective := job.Shards
+	for k, step := range job.Steps {
+		if step.Test == nil || step.Test.Shards == 0 {
+			continue
+		}
+		stepPrefix := fmt.Sprintf("%s.steps[%d].test.shards", prefix, k)
+		if step.Test.Shards < 0 || step.Test.Shards > MaxPipelineJobShards {
+			errs = append(errs, fmt.Sprintf("%s must be between 1 and %d", stepPrefix, MaxPipelineJobShards))
+			continue
+		}
+		// Shards are executions of the whole job, so every step that sets
+		// them has to agree.
+		if effective > 0 && step.Test.Shards != effective {
+			errs = append(errs, fmt.Sprintf("%s must match the job's shard count %d", stepPrefix, effective))
+			continue
+		}
+		effective = step.Test.Shards
+	}
+	if effective > 0 {
+		// A shard only runs part of the tests, so its coverage says nothing
+		// about the job's.
+		for k, step := range job.Steps {
+			if step.Test != nil && step.Test.CoverageGate != nil {
+				errs = append(errs, fmt.Sprintf("%s.steps[%d].test.coverage_gate cannot be combined with shards", prefix, k))
+			}
+		}
+	}
+	return errs
+}
+
+func validateCoverageGate(prefix string, gate *CoverageGate) []string {
+	var errs []string
+	if gate.MinPercent == nil && gate.MaxDrop == nil {
+		errs = append(errs, prefix+" must set min_percent or max_drop")
+	}
+	if gate.MinPercent != nil && (*gate.MinPercent < 0 || *gate.MinPercent > 100) {
+		errs = append(errs, prefix+".min_percent must be between 0 and 100")
+	}
+	if gate.MaxDrop != nil && (*gate.MaxDrop < 0 || *gate.MaxDrop > 100) {
+		errs = append(errs, prefix+".max_drop must be between 0 and 100")
+	}
+	return errs
+}
+
 var secretPlaceholderPattern = regexp.MustCompile(`\{\{\s*secret\.([a-zA-Z0-9_\-]+)\s*\}\}`)
 
 func secretPlaceholderNames(value string) []string {
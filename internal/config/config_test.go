@@ -1,10 +1,13 @@
 package config
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
+
+	"gopkg.in/yaml.v3"
 )
 
 func TestLoadReadsConfigAndIncludesPathInErrors(t *testing.T) {
@@ -83,6 +86,195 @@ pipelines:
 	}
 }
 
+func TestParseAcceptsVCSTriggerPollInterval(t *testing.T) {
+	cfg, err := Parse([]byte(`
+version: 1
+project:
+  name: ciwi
+pipelines:
+  - id: build
+    trigger: vcs
+    vcs_source:
+      repo: https://github.com/izzyreal/ciwi.git
+      ref: main
+      poll_interval_seconds: 30
+    jobs:
+      - id: compile
+        timeout_seconds: 60
+        steps:
+          - run: go build ./...
+`), "test-vcs-trigger")
+	if err != nil {
+		t.Fatalf("parse config: %v", err)
+	}
+	if got := cfg.Pipelines[0].VCSSource.PollIntervalSeconds; got != 30 {
+		t.Fatalf("expected poll interval 30, got %d", got)
+	}
+}
+
+func TestParseVCSSourceCheckoutOptions(t *testing.T) {
+	cfg, err := Parse([]byte(`
+version: 1
+project:
+  name: ciwi
+pipelines:
+  - id: build
+    vcs_source:
+      repo: https://github.com/izzyreal/ciwi.git
+      depth: 0
+      submodules: recursive
+      lfs: true
+      sparse: [internal, docs]
+      fetch_tags: true
+    jobs:
+      - id: compile
+        timeout_seconds: 60
+        steps:
+          - run: go build ./...
+`), "test-checkout-options")
+	if err != nil {
+		t.Fatalf("parse config: %v", err)
+	}
+	options := cfg.Pipelines[0].VCSSource.CheckoutOptions
+	if options.Depth == nil || *options.Depth != 0 || options.Submodules != SubmodulesRecursive || !options.LFS || !options.FetchTags || strings.Join(options.Sparse, ",") != "internal,docs" {
+		t.Fatalf("unexpected checkout options: %+v", options)
+	}
+
+	for want, source := range map[string]string{
+		"vcs_source.depth must be >= 0":                       "depth: -1",
+		"vcs_source.submodules must be one of":                "submodules: all",
+		"vcs_source.sparse[0] must be a relative in-repo dir": "sparse: [../outside]",
+	} {
+		_, err := Parse([]byte(`
+version: 1
+project:
+  name: ciwi
+pipelines:
+  - id: build
+    vcs_source:
+      repo: https://github.com/izzyreal/ciwi.git
+      `+source+`
+    jobs:
+      - id: compile
+        timeout_seconds: 60
+        steps:
+          - run: go build ./...
+`), "test-checkout-options")
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Fatalf("expected %q validation error, got: %v", want, err)
+		}
+	}
+}
+
+func TestParseRejectsInvalidVCSTrigger(t *testing.T) {
+	cases := map[string]string{
+		"vcs_source is required when trigger is vcs": `
+version: 1
+project:
+  name: ciwi
+pipelines:
+  - id: build
+    trigger: vcs
+    jobs:
+      - id: compile
+        timeout_seconds: 60
+        steps:
+          - run: go build ./...
+`,
+		"poll_interval_seconds must be 0 or at least": `
+version: 1
+project:
+  name: ciwi
+pipelines:
+  - id: build
+    trigger: vcs
+    vcs_source:
+      repo: https://github.com/izzyreal/ciwi.git
+      poll_interval_seconds: 2
+    jobs:
+      - id: compile
+        timeout_seconds: 60
+        steps:
+          - run: go build ./...
+`,
+	}
+	for want, raw := range cases {
+		_, err := Parse([]byte(raw), "test-vcs-trigger")
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Fatalf("expected %q validation error, got: %v", want, err)
+		}
+	}
+}
+
+func TestParseScheduleTrigger(t *testing.T) {
+	cfg, err := Parse([]byte(`
+version: 1
+project:
+  name: ciwi
+pipelines:
+  - id: nightly
+    trigger: schedule
+    schedule:
+      cron: "0 3 * * mon-fri"
+      timezone: Europe/Amsterdam
+      source_ref: main
+      dry_run: true
+      catch_up: once
+    jobs:
+      - id: compile
+        timeout_seconds: 60
+        steps:
+          - run: go build ./...
+pipeline_chains:
+  - pipelines: [nightly]
+    schedule:
+      cron: "@weekly"
+`), "test-schedule-trigger")
+	if err != nil {
+		t.Fatalf("parse config: %v", err)
+	}
+	sched := cfg.Pipelines[0].Schedule
+	if sched == nil || sched.Cron != "0 3 * * mon-fri" || sched.Timezone != "Europe/Amsterdam" || sched.SourceRef != "main" || !sched.DryRun || sched.EffectiveCatchUp() != ScheduleCatchUpOnce {
+		t.Fatalf("unexpected pipeline schedule: %+v", sched)
+	}
+	if chain := cfg.PipelineChains[0].Schedule; chain == nil || chain.EffectiveCatchUp() != ScheduleCatchUpSkip {
+		t.Fatalf("unexpected chain schedule: %+v", chain)
+	}
+}
+
+func TestParseRejectsInvalidSchedule(t *testing.T) {
+	pipeline := func(trigger, schedule string) string {
+		return `
+version: 1
+project:
+  name: ciwi
+pipelines:
+  - id: build
+    trigger: ` + trigger + `
+` + schedule + `
+    jobs:
+      - id: compile
+        timeout_seconds: 60
+        steps:
+          - run: go build ./...
+`
+	}
+	cases := map[string]string{
+		"pipelines[0].schedule is required when trigger is schedule": pipeline("schedule", ""),
+		"pipelines[0].schedule requires trigger schedule":            pipeline("manual", "    schedule:\n      cron: \"@daily\""),
+		"pipelines[0].schedule.cron is required":                     pipeline("schedule", "    schedule:\n      timezone: UTC"),
+		"pipelines[0].schedule.cron is invalid":                      pipeline("schedule", "    schedule:\n      cron: \"0 25 * * *\""),
+		"pipelines[0].schedule.timezone is invalid":                  pipeline("schedule", "    schedule:\n      cron: \"@daily\"\n      timezone: Mars/Olympus"),
+		"pipelines[0].schedule.catch_up must be one of skip,once":    pipeline("schedule", "    schedule:\n      cron: \"@daily\"\n      catch_up: all"),
+	}
+	for want, raw := range cases {
+		_, err := Parse([]byte(raw), "test-schedule-trigger")
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Fatalf("expected %q validation error, got: %v", want, err)
+		}
+	}
+}
+
 func TestParseAcceptsPipelineWithoutVCSSource(t *testing.T) {
 	_, err := Parse([]byte(`
 version: 1
@@ -615,6 +807,126 @@ pipelines:
 	}
 }
 
+func TestParseStepAndJobConditions(t *testing.T) {
+	cfg, err := Parse([]byte(`
+version: 1
+project:
+  name: ciwi
+pipelines:
+  - id: release
+    jobs:
+      - id: publish
+        if: endsWith(ciwi.source_ref, 'main') && !ciwi.dry_run
+        timeout_seconds: 60
+        steps:
+          - run: ./build.sh
+          - run: ./upload-crash-dumps.sh
+            if: failure()
+`), "test-conditions")
+	if err != nil {
+		t.Fatalf("parse conditions: %v", err)
+	}
+	job := cfg.Pipelines[0].Jobs[0]
+	if job.If != "endsWith(ciwi.source_ref, 'main') && !ciwi.dry_run" || job.Steps[1].If != "failure()" {
+		t.Fatalf("unexpected conditions: job=%q step=%q", job.If, job.Steps[1].If)
+	}
+}
+
+func TestParseRejectsInvalidConditions(t *testing.T) {
+	_, err := Parse([]byte(`
+version: 1
+project:
+  name: ciwi
+pipelines:
+  - id: release
+    jobs:
+      - id: publish
+        if: failure()
+        timeout_seconds: 60
+        steps:
+          - run: ./build.sh
+            if: ciwi.branch == 'main'
+`), "test-invalid-conditions")
+	if err == nil {
+		t.Fatal("expected invalid conditions to be rejected")
+	}
+	for _, want := range []string{"jobs[0].if must not use success(), failure() or always()", `steps[0].if: condition "ciwi.branch == 'main'": unknown value`} {
+		if !strings.Contains(err.Error(), want) {
+			t.Fatalf("expected %q in error, got: %v", want, err)
+		}
+	}
+}
+
+func TestParseRetryPolicies(t *testing.T) {
+	cfg, err := Parse([]byte(`
+version: 1
+project:
+  name: ciwi
+pipelines:
+  - id: release
+    jobs:
+      - id: sign
+        timeout_seconds: 60
+        retry:
+          max_attempts: 3
+          backoff_seconds: 30
+          on: [timeout, agent_lost]
+        steps:
+          - run: ./sign.ps1
+            retry:
+              max_attempts: 2
+`), "test-retry")
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	job := cfg.Pipelines[0].Jobs[0]
+	if job.Retry == nil || job.Retry.MaxAttempts != 3 || job.Retry.BackoffSeconds != 30 {
+		t.Fatalf("unexpected job retry: %+v", job.Retry)
+	}
+	if got := strings.Join(job.Retry.EffectiveOn(), ","); got != "timeout,agent_lost" {
+		t.Fatalf("unexpected job retry kinds: %q", got)
+	}
+	step := job.Steps[0]
+	if step.Retry == nil || step.Retry.MaxAttempts != 2 {
+		t.Fatalf("unexpected step retry: %+v", step.Retry)
+	}
+}
+
+func TestParseRejectsInvalidRetryPolicies(t *testing.T) {
+	_, err := Parse([]byte(`
+version: 1
+project:
+  name: ciwi
+pipelines:
+  - id: release
+    jobs:
+      - id: sign
+        timeout_seconds: 60
+        retry:
+          max_attempts: 0
+          backoff_seconds: -1
+          on: [crash]
+        steps:
+          - run: ./sign.ps1
+            retry:
+              max_attempts: 2
+              on: [timeout]
+`), "test-invalid-retry")
+	if err == nil {
+		t.Fatal("expected invalid retry policies to be rejected")
+	}
+	for _, want := range []string{
+		"jobs[0].retry.max_attempts must be >= 1",
+		"jobs[0].retry.backoff_seconds must be >= 0",
+		"jobs[0].retry.on[0] must be one of exit_code,timeout,agent_lost",
+		"steps[0].retry.on[0] must be one of exit_code",
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Fatalf("expected %q in error, got: %v", want, err)
+		}
+	}
+}
+
 func TestParseRejectsUnsupportedTestFormat(t *testing.T) {
 	_, err := Parse([]byte(`
 version: 1
@@ -628,14 +940,165 @@ pipelines:
         steps:
           - test:
               command: ./tests
-              format: tap
-              report: out/tests.tap
+              format: subunit
+              report: out/tests.subunit
 `), "test-step-format")
 	if err == nil || !strings.Contains(err.Error(), "test.format unsupported") {
 		t.Fatalf("expected unsupported test.format error, got: %v", err)
 	}
 }
 
+func TestParseAcceptsAdditionalTestFormats(t *testing.T) {
+	for _, format := range []string{"tap", "trx", "libtest-json", "nextest-json", "pytest-reportlog"} {
+		_, err := Parse([]byte(`
+version: 1
+project:
+  name: ciwi
+pipelines:
+  - id: test
+    jobs:
+      - id: unit
+        timeout_seconds: 60
+        steps:
+          - test:
+              command: ./tests
+              format: `+format+`
+              report: out/tests.report
+`), "test-step-format")
+		if err != nil {
+			t.Fatalf("format %s: %v", format, err)
+		}
+	}
+}
+
+func TestParseAcceptsXMLCoverageFormats(t *testing.T) {
+	for _, format := range []string{"cobertura", "jacoco"} {
+		_, err := Parse([]byte(`
+version: 1
+project:
+  name: ciwi
+pipelines:
+  - id: test
+    jobs:
+      - id: unit
+        timeout_seconds: 60
+        steps:
+          - test:
+              command: ./tests
+              report: out/tests.json
+              coverage_format: `+format+`
+              coverage_report: out/coverage.xml
+`), "test-coverage-format")
+		if err != nil {
+			t.Fatalf("coverage format %s: %v", format, err)
+		}
+	}
+}
+
+func TestParseValidatesCoverageGate(t *testing.T) {
+	parse := func(gate string) (File, error) {
+		return Parse([]byte(`
+version: 1
+project:
+  name: ciwi
+pipelines:
+  - id: test
+    jobs:
+      - id: unit
+        timeout_seconds: 60
+        steps:
+          - test:
+              command: ./tests
+              report: out/tests.json
+              coverage_report: out/coverage.xml
+              coverage_gate: `+gate+`
+`), "test-coverage-gate")
+	}
+	cfg, err := parse("{min_percent: 80, max_drop: 0}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	gate := cfg.Pipelines[0].Jobs[0].Steps[0].Test.CoverageGate
+	if gate == nil || gate.MinPercent == nil || *gate.MinPercent != 80 || gate.MaxDrop == nil || *gate.MaxDrop != 0 {
+		t.Fatalf("coverage gate = %+v", gate)
+	}
+	for gate, message := range map[string]string{
+		"{}":                 "must set min_percent or max_drop",
+		"{min_percent: 101}": "min_percent must be between 0 and 100",
+		"{max_drop: -1}":     "max_drop must be between 0 and 100",
+	} {
+		if _, err := parse(gate); err == nil || !strings.Contains(err.Error(), message) {
+			t.Fatalf("gate %s: expected %q, got %v", gate, message, err)
+		}
+	}
+}
+
+func TestParseValidatesShards(t *testing.T) {
+	parse := func(jobShards, stepShards int) (File, error) {
+		return Parse([]byte(fmt.Sprintf(`
+version: 1
+project:
+  name: ciwi
+pipelines:
+  - id: test
+    jobs:
+      - id: integration
+        timeout_seconds: 60
+        shards: %d
+        steps:
+          - run: ./prepare
+          - test:
+              command: ./tests
+              report: out/tests.json
+              shards: %d
+`, jobShards, stepShards)), "test-shards")
+	}
+	cfg, err := parse(0, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if shards := EffectivePipelineJobShards(cfg.Pipelines[0].Jobs[0]); shards != 4 {
+		t.Fatalf("effective shards = %d, want 4", shards)
+	}
+	if _, err := parse(4, 4); err != nil {
+		t.Fatalf("matching job and step shards: %v", err)
+	}
+	for _, tc := range []struct {
+		job, step int
+		message   string
+	}{
+		{job: 3, step: 4, message: "steps[1].test.shards must match the job's shard count 3"},
+		{job: 65, message: "jobs[0].shards must be between 1 and 64"},
+		{step: -1, message: "steps[1].test.shards must be between 1 and 64"},
+	} {
+		if _, err := parse(tc.job, tc.step); err == nil || !strings.Contains(err.Error(), tc.message) {
+			t.Fatalf("shards %d/%d: expected %q, got %v", tc.job, tc.step, tc.message, err)
+		}
+	}
+
+	_, err = Parse([]byte(`
+version: 1
+project:
+  name: ciwi
+pipelines:
+  - id: test
+    jobs:
+      - id: unit
+        timeout_seconds: 60
+        shards: 2
+        steps:
+          - test:
+              command: go test -json -coverprofile=cover.out ./... > test.json
+              report: test.json
+              coverage_report: cover.out
+              coverage_gate:
+                min_percent: 80
+`), "test-shards-coverage-gate")
+	if err == nil || !strings.Contains(err.Error(), "steps[0].test.coverage_gate cannot be combined with shards") {
+		t.Fatalf("expected the coverage gate to be rejected on a sharded job, got %v", err)
+	}
+}
+
 func TestParseRejectsMissingTestReport(t *testing.T) {
 	_, err := Parse([]byte(`
 version: 1
@@ -953,6 +1416,37 @@ pipelines:
 	}
 }
 
+func TestParseValidatesContainerRuntime(t *testing.T) {
+	parse := func(runsOn string) error {
+		_, err := Parse([]byte(`
+version: 1
+project:
+  name: ciwi
+pipelines:
+  - id: build
+    jobs:
+      - id: linux
+        runs_on:
+          executor: script
+          shell: posix
+`+runsOn+`
+        timeout_seconds: 60
+        steps:
+          - run: echo build
+`), "test-container-runtime")
+		return err
+	}
+	if err := parse("          container_image: ubuntu:24.04\n          container_runtime: podman"); err != nil {
+		t.Fatalf("expected podman to be accepted, got: %v", err)
+	}
+	if err := parse("          container_image: ubuntu:24.04\n          container_runtime: lxc"); err == nil || !strings.Contains(err.Error(), "runs_on.container_runtime must be one of docker,podman,nerdctl") {
+		t.Fatalf("expected an unknown runtime to be rejected, got: %v", err)
+	}
+	if err := parse("          container_runtime: podman"); err == nil || !strings.Contains(err.Error(), "runs_on.container_image is required when runs_on.container_runtime is set") {
+		t.Fatalf("expected runs_on.container_image validation error for container_runtime, got: %v", err)
+	}
+}
+
 func TestParseAcceptsPowerShellShell(t *testing.T) {
 	_, err := Parse([]byte(`
 version: 1
@@ -1022,6 +1516,62 @@ pipelines:
 	}
 }
 
+func TestParseJobServices(t *testing.T) {
+	parse := func(services string) (File, error) {
+		return Parse([]byte(`
+version: 1
+project:
+  name: ciwi
+pipelines:
+  - id: build
+    jobs:
+      - id: integration
+        runs_on:
+          executor: script
+          shell: posix
+        timeout_seconds: 60
+        services:
+`+services+`
+        steps:
+          - run: make integration
+`), "test-services")
+	}
+	cfg, err := parse(`          - name: postgres
+            image: postgres:16
+            env:
+              POSTGRES_PASSWORD: ci
+            ports: [5432]
+            health_check: pg_isready -U postgres
+            health_timeout_seconds: 90
+          - name: minio
+            image: minio/minio
+            ports: [9000, 9001]`)
+	if err != nil {
+		t.Fatalf("expected services to validate, got: %v", err)
+	}
+	services := cfg.Pipelines[0].Jobs[0].Services
+	if len(services) != 2 || services[0].Env["POSTGRES_PASSWORD"] != "ci" || services[0].HealthCheck != "pg_isready -U postgres" ||
+		services[0].HealthTimeoutSeconds != 90 || len(services[1].Ports) != 2 {
+		t.Fatalf("unexpected services: %+v", services)
+	}
+
+	for _, tc := range []struct {
+		services string
+		want     string
+	}{
+		{"          - image: postgres:16", "services[0].name is required"},
+		{"          - name: Postgres\n            image: postgres:16", `services[0].name "Postgres" must be lowercase`},
+		{"          - name: db\n            image: postgres:16\n          - name: db\n            image: mysql:8", `services[1].name duplicate "db"`},
+		{"          - name: db", "services[0].image is required"},
+		{"          - name: db\n            image: postgres:16\n            ports: [0]", "services[0].ports[0] must be between 1 and 65535"},
+		{"          - name: db\n            image: postgres:16\n            health_timeout_seconds: -1", "services[0].health_timeout_seconds must be >= 0"},
+	} {
+		if _, err := parse(tc.services); err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Fatalf("expected %q for %q, got: %v", tc.want, tc.services, err)
+		}
+	}
+}
+
 func TestParsePipelineChainsValidation(t *testing.T) {
 	_, err := Parse([]byte(`
 version: 1
@@ -1099,3 +1649,123 @@ pipeline_chains:
 		t.Fatalf("expected duplicate chain sequence rejection, got: %v", err)
 	}
 }
+
+func TestParseValidatesPriorities(t *testing.T) {
+	parse := func(pipelinePriority, jobPriority int) (File, error) {
+		return Parse([]byte(fmt.Sprintf(`
+version: 1
+project:
+  name: ciwi
+pipelines:
+  - id: release
+    priority: %d
+    jobs:
+      - id: package
+        timeout_seconds: 60
+        priority: %d
+        steps:
+          - run: ./package
+      - id: publish
+        timeout_seconds: 60
+        steps:
+          - run: ./publish
+`, pipelinePriority, jobPriority)), "test-priorities")
+	}
+	cfg, err := parse(20, 90)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pipeline := cfg.Pipelines[0]
+	if got := EffectivePipelineJobPriority(pipeline.Priority, pipeline.Jobs[0]); got != 90 {
+		t.Fatalf("job priority = %d, want 90", got)
+	}
+	if got := EffectivePipelineJobPriority(pipeline.Priority, pipeline.Jobs[1]); got != 20 {
+		t.Fatalf("inherited priority = %d, want 20", got)
+	}
+	if cfg, err = parse(20, 0); err != nil {
+		t.Fatal(err)
+	}
+	if got := EffectivePipelineJobPriority(cfg.Pipelines[0].Priority, cfg.Pipelines[0].Jobs[0]); got != 0 {
+		t.Fatalf("explicit zero job priority = %d, want 0", got)
+	}
+	if _, err := parse(101, 0); err == nil || !strings.Contains(err.Error(), "pipelines[0].priority must be between -100 and 100") {
+		t.Fatalf("expected pipeline priority rejection, got %v", err)
+	}
+	if _, err := parse(0, -101); err == nil || !strings.Contains(err.Error(), "pipelines[0].jobs[0].priority must be between -100 and 100") {
+		t.Fatalf("expected job priority rejection, got %v", err)
+	}
+}
+
+func TestParseValidatesConcurrency(t *testing.T) {
+	parse := func(concurrency string) error {
+		_, err := Parse([]byte(`
+version: 1
+project:
+  name: ciwi
+pipelines:
+  - id: deploy
+    concurrency: `+concurrency+`
+    jobs:
+      - id: ship
+        timeout_seconds: 60
+        steps:
+          - run: ./deploy
+`), "test-concurrency")
+		return err
+	}
+	if err := parse(`{group: "deploy-{{ciwi.project}}-{{matrix.host}}", cancel_in_progress: true, max_running: 2}`); err != nil {
+		t.Fatalf("valid concurrency: %v", err)
+	}
+	for concurrency, message := range map[string]string{
+		`{max_running: 1}`:                 "pipelines[0].concurrency.group is required",
+		`{group: deploy, max_running: -1}`: "pipelines[0].concurrency.max_running must be >= 0",
+		`{group: "deploy-{{version}}"}`:    `may only interpolate ciwi.* and matrix.* values, got "{{version}}"`,
+	} {
+		if err := parse(concurrency); err == nil || !strings.Contains(err.Error(), message) {
+			t.Fatalf("concurrency %s: expected %q, got %v", concurrency, message, err)
+		}
+	}
+}
+
+func TestParseRunsOnLabels(t *testing.T) {
+	parse := func(runsOn string) (File, error) {
+		return Parse([]byte(`
+version: 1
+project:
+  name: ciwi
+pipelines:
+  - id: flash
+    jobs:
+      - id: firmware
+        runs_on: `+runsOn+`
+        timeout_seconds: 60
+        steps:
+          - run: ./flash
+`), "test-labels")
+	}
+	cfg, err := parse(`{os: linux, labels: {dongle: "yes", site: lab}}`)
+	if err != nil {
+		t.Fatalf("parse labels: %v", err)
+	}
+	runsOn := cfg.Pipelines[0].Jobs[0].RunsOn
+	if runsOn["os"] != "linux" || runsOn["label.dongle"] != "yes" || runsOn["label.site"] != "lab" || len(runsOn) != 3 {
+		t.Fatalf("expected labels flattened next to os, got %+v", runsOn)
+	}
+	encoded, err := yaml.Marshal(runsOn)
+	if err != nil {
+		t.Fatalf("marshal runs_on: %v", err)
+	}
+	if want := "labels:\n    dongle: \"yes\"\n    site: lab\nos: linux\n"; string(encoded) != want {
+		t.Fatalf("expected labels nested again, got:\n%s", encoded)
+	}
+
+	for runsOn, message := range map[string]string{
+		`{labels: {"has space": x}}`: `runs_on.labels key "has space" may only contain`,
+		`{labels: {dongle: ""}}`:     "pipelines[0].jobs[0].runs_on.labels.dongle must not be empty",
+		`{labels: [dongle]}`:         "cannot unmarshal",
+	} {
+		if _, err := parse(runsOn); err == nil || !strings.Contains(err.Error(), message) {
+			t.Fatalf("runs_on %s: expected %q, got %v", runsOn, message, err)
+		}
+	}
+}
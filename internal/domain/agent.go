@@ -2,6 +2,9 @@ package domain
 
 import "time"
 
+// MaxAgentJobSlots bounds how many jobs one agent may run at once.
+const MaxAgentJobSlots = 64
+
 type Agent struct {
 	ID               string
 	Hostname         string
@@ -11,7 +14,12 @@ type Agent struct {
 	Authorized       bool
 	Deactivated      bool
 	JobInProgress    bool
+	Slots            int
+	SlotsOverridden  bool
+	ActiveJobs       int
 	Capabilities     map[string]string
+	Labels           map[string]string
+	AssignedLabels   map[string]string
 	LastSeenUTC      time.Time
 	RecentLog        []string
 	NeedsUpdate      bool
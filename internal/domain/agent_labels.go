@@ -0,0 +1,79 @@
+package domain
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// AgentLabelCapabilityPrefix namespaces agent labels among the capabilities
+// that runs_on requirements are matched against.
+const AgentLabelCapabilityPrefix = "label."
+
+var agentLabelKeyPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_.-]*$`)
+
+// ParseAgentLabels reads labels written as comma- or newline-separated
+// key=value pairs, such as "dongle=yes, site=lab".
+func ParseAgentLabels(raw string) (map[string]string, error) {
+	labels := map[string]string{}
+	for _, field := range strings.FieldsFunc(raw, func(r rune) bool { return r == ',' || r == '\n' || r == '\r' }) {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(field, "=")
+		if !ok {
+			return nil, fmt.Errorf("label %q must be written as key=value", field)
+		}
+		labels[strings.TrimSpace(key)] = value
+	}
+	return NormalizeAgentLabels(labels)
+}
+
+// NormalizeAgentLabels trims labels and rejects malformed keys and values.
+// It returns nil when there are no labels.
+func NormalizeAgentLabels(labels map[string]string) (map[string]string, error) {
+	if len(labels) == 0 {
+		return nil, nil
+	}
+	normalized := make(map[string]string, len(labels))
+	for key, value := range labels {
+		key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+		if !agentLabelKeyPattern.MatchString(key) {
+			return nil, fmt.Errorf("label key %q may only contain letters, digits, '.', '_' and '-'", key)
+		}
+		if value == "" || strings.ContainsAny(value, ",\r\n") {
+			return nil, fmt.Errorf("label %q needs a value without commas or line breaks", key)
+		}
+		normalized[key] = value
+	}
+	return normalized, nil
+}
+
+// FormatAgentLabels renders labels as sorted key=value pairs in the form
+// ParseAgentLabels reads.
+func FormatAgentLabels(labels map[string]string) string {
+	pairs := make([]string, 0, len(labels))
+	for key, value := range labels {
+		pairs = append(pairs, key+"="+value)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ", ")
+}
+
+// MergeAgentLabels returns the labels an agent declares with the labels an
+// admin assigned to it on top.
+func MergeAgentLabels(declared, assigned map[string]string) map[string]string {
+	if len(declared) == 0 && len(assigned) == 0 {
+		return nil
+	}
+	merged := make(map[string]string, len(declared)+len(assigned))
+	for key, value := range declared {
+		merged[key] = value
+	}
+	for key, value := range assigned {
+		merged[key] = value
+	}
+	return merged
+}
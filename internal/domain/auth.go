@@ -0,0 +1,91 @@
+package domain
+
+import (
+	"strings"
+	"time"
+)
+
+// Role is the access level of a user. Roles are ordered: every role may do
+// what the roles below it may do.
+type Role string
+
+const (
+	RoleViewer   Role = "viewer"
+	RoleOperator Role = "operator"
+	RoleAdmin    Role = "admin"
+)
+
+func ParseRole(raw string) (Role, bool) {
+	role := Role(strings.ToLower(strings.TrimSpace(raw)))
+	return role, role.rank() > 0
+}
+
+// Allows reports whether r grants at least the access of required.
+func (r Role) Allows(required Role) bool {
+	return r.rank() > 0 && r.rank() >= required.rank()
+}
+
+func (r Role) rank() int {
+	switch r {
+	case RoleViewer:
+		return 1
+	case RoleOperator:
+		return 2
+	case RoleAdmin:
+		return 3
+	default:
+		return 0
+	}
+}
+
+type User struct {
+	ID           int64
+	Username     string
+	Role         Role
+	CreatedUTC   time.Time
+	LastLoginUTC time.Time
+}
+
+type APIToken struct {
+	ID          int64
+	UserID      int64
+	Name        string
+	Prefix      string
+	CreatedUTC  time.Time
+	LastUsedUTC time.Time
+	ExpiresUTC  time.Time
+}
+
+// Principal is the authenticated caller of a request. TokenID is set when
+// the caller authenticated with a personal API token rather than a session.
+type Principal struct {
+	UserID   int64
+	Username string
+	Role     Role
+	TokenID  int64
+}
+
+// AgentEnrollmentToken is a one-time secret an admin hands to a new agent.
+// The agent exchanges it for its own credential on first start.
+type AgentEnrollmentToken struct {
+	ID            int64
+	Description   string
+	Prefix        string
+	CreatedBy     string
+	CreatedUTC    time.Time
+	ExpiresUTC    time.Time
+	UsedUTC       time.Time
+	UsedByAgentID string
+}
+
+// AgentCredential records that an agent ID is bound to a secret. A revoked
+// credential keeps the agent ID bound until the agent enrolls again.
+type AgentCredential struct {
+	AgentID    string
+	CreatedUTC time.Time
+	RevokedUTC time.Time
+}
+
+func (c AgentCredential) Revoked() bool {
+	return !c.RevokedUTC.IsZero()
+}
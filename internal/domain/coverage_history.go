@@ -0,0 +1,25 @@
+package domain
+
+import "time"
+
+// CoverageRun is the coverage total of one execution of a pipeline job.
+// Percent, Covered and Total count statements for formats that report them
+// and lines otherwise.
+type CoverageRun struct {
+	JobExecutionID string
+	Scope          TestHistoryScope
+	SourceRef      string
+	Status         string
+	Percent        float64
+	Covered        int
+	Total          int
+	GateFailed     bool
+	CreatedUTC     time.Time
+}
+
+// CoverageTrend is the recent coverage of one pipeline job and matrix entry,
+// newest run first.
+type CoverageTrend struct {
+	Scope TestHistoryScope
+	Runs  []CoverageRun
+}
@@ -51,16 +51,19 @@ type ExecutionCardJob struct {
 }
 
 type JobTestSummary struct {
-	Total   int
-	Passed  int
-	Failed  int
-	Skipped int
+	Total       int
+	Passed      int
+	Failed      int
+	Skipped     int
+	Quarantined int
 }
 
 type ExecutionSummary struct {
 	TotalJobs  int
 	Succeeded  int
 	Failed     int
+	Cancelled  int
+	Skipped    int
 	InProgress int
 	Waiting    int
 }
@@ -102,23 +105,30 @@ type JobArtifact struct {
 	SizeBytes int64
 }
 
+// JobTestReport counts the failures of quarantined tests in Quarantined
+// rather than Failed.
 type JobTestReport struct {
-	Total    int
-	Passed   int
-	Failed   int
-	Skipped  int
-	Suites   []JobTestSuite
-	Coverage *JobCoverageReport
+	Total       int
+	Passed      int
+	Failed      int
+	Skipped     int
+	Quarantined int
+	Suites      []JobTestSuite
+	Coverage    *JobCoverageReport
+	// Shards is the number of shard reports combined into this one; zero
+	// when the job is not sharded.
+	Shards int
 }
 
 type JobTestSuite struct {
-	Name    string
-	Format  string
-	Total   int
-	Passed  int
-	Failed  int
-	Skipped int
-	Cases   []JobTestCase
+	Name        string
+	Format      string
+	Total       int
+	Passed      int
+	Failed      int
+	Skipped     int
+	Quarantined int
+	Cases       []JobTestCase
 }
 
 type JobTestCase struct {
@@ -129,6 +139,10 @@ type JobTestCase struct {
 	Status          string
 	DurationSeconds float64
 	Output          string
+	Quarantined     bool
+	// HistoryBadge is one of the TestHistoryBadge values when the case's
+	// earlier runs make this result noteworthy.
+	HistoryBadge string
 }
 
 type JobCoverageReport struct {
@@ -139,6 +153,13 @@ type JobCoverageReport struct {
 	CoveredStatements int
 	Percent           float64
 	Files             []JobCoverageFile
+	Gate              *JobCoverageGate
+}
+
+// JobCoverageGate is the verdict of the coverage gate of a job.
+type JobCoverageGate struct {
+	Passed  bool
+	Message string
 }
 
 type JobCoverageFile struct {
@@ -148,6 +169,14 @@ type JobCoverageFile struct {
 	TotalStatements   int
 	CoveredStatements int
 	Percent           float64
+	// Lines holds the hit count of every instrumented line, ordered by line
+	// number, when the coverage format reports them.
+	Lines []JobCoverageLine
+}
+
+type JobCoverageLine struct {
+	Line int
+	Hits int
 }
 
 type JobCacheStatistics struct {
@@ -183,6 +212,7 @@ type SchedulingAgentAssessment struct {
 	Available          bool                   `json:"available"`
 	CapabilityIssues   []SchedulingMatchIssue `json:"capability_issues,omitempty"`
 	AvailabilityIssues []string               `json:"availability_issues,omitempty"`
+	FreeSlots          int                    `json:"free_slots,omitempty"`
 }
 
 type SchedulingDiagnosis struct {
@@ -210,6 +240,11 @@ type JobTimelineItem struct {
 	Error              string
 	YAMLLiteral        string
 	Command            string
+	// Attempt numbers the runs of a step with a retry policy. Retried marks
+	// an earlier attempt that failed and was run again; its output is part of
+	// the final attempt's item.
+	Attempt int
+	Retried bool
 }
 
 // Progress is a renderer-neutral snapshot. Fraction is the completed share at
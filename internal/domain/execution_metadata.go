@@ -3,6 +3,7 @@ package domain
 import (
 	"strconv"
 	"strings"
+	"time"
 )
 
 // ExecutionMetadata retains the map representation used by SQLite and wire
@@ -28,10 +29,23 @@ const (
 	ExecutionMetadataDependencyBlocked         = "dependency_blocked"
 	ExecutionMetadataNeedsBlocked              = "needs_blocked"
 	ExecutionMetadataNeedsJobIDs               = "needs_job_ids"
+	ExecutionMetadataConditionSkipped          = "condition_skipped"
 	ExecutionMetadataMissingNeedsJobIDs        = "missing_needs_job_ids"
 	ExecutionMetadataMatrixName                = "matrix_name"
 	ExecutionMetadataMatrixIndex               = "matrix_index"
 	ExecutionMetadataMatrixVariablePrefix      = "matrix_var."
+	ExecutionMetadataShardIndex                = "shard_index"
+	ExecutionMetadataShardTotal                = "shard_total"
+	ExecutionMetadataShardTestsJSON            = "shard_tests_json"
+	ExecutionMetadataPriority                  = "priority"
+	ExecutionMetadataConcurrencyGroup          = "concurrency_group"
+	ExecutionMetadataConcurrencyMaxRunning     = "concurrency_max_running"
+	ExecutionMetadataConcurrencyHolder         = "concurrency_holder"
+	ExecutionMetadataConcurrencyCancelRunning  = "concurrency_cancel_in_progress"
+	ExecutionMetadataAffinityKey               = "affinity_key"
+	ExecutionMetadataAffinityAgentID           = "affinity_agent_id"
+	ExecutionMetadataAffinityWaitSeconds       = "affinity_wait_seconds"
+	ExecutionMetadataAffinityWaitingSinceUTC   = "affinity_waiting_since_utc"
 	ExecutionMetadataDryRun                    = "dry_run"
 	ExecutionMetadataBuildTarget               = "build_target"
 	ExecutionMetadataBuildVersion              = "build_version"
@@ -58,11 +72,53 @@ const (
 	ExecutionMetadataRuntimeContainerGroups    = "runtime_exec.container_groups"
 	ExecutionMetadataAttemptRootJobID          = "attempt_root_job_id"
 	ExecutionMetadataRerunOfJobID              = "rerun_of_job_id"
+	ExecutionMetadataRetryMaxAttempts          = "retry_max_attempts"
+	ExecutionMetadataRetryBackoffSeconds       = "retry_backoff_seconds"
+	ExecutionMetadataRetryOn                   = "retry_on"
+	ExecutionMetadataRetryAttempt              = "retry_attempt"
+	ExecutionMetadataRetryReason               = "retry_reason"
 	ExecutionMetadataSchedulingBlocked         = "scheduling_blocked"
 	ExecutionMetadataSchedulingBlockedReason   = "scheduling_blocked_reason"
 	ExecutionMetadataSchedulingRetryUTC        = "scheduling_retry_utc"
+	ExecutionMetadataTrigger                   = "trigger"
+	ExecutionMetadataTriggerCommit             = "trigger_commit"
+	ExecutionMetadataCancelledBy               = "cancelled_by"
+	ExecutionMetadataCancelReason              = "cancel_reason"
 )
 
+// ExecutionCancellation records who or what stopped an execution early. By
+// names the actor (a username, or "user" when accounts are disabled); Reason
+// explains cancellations that were a side effect of another action.
+type ExecutionCancellation struct {
+	By     string
+	Reason string
+}
+
+func (c ExecutionCancellation) Summary() string {
+	by, reason := strings.TrimSpace(c.By), strings.TrimSpace(c.Reason)
+	switch {
+	case by != "" && reason != "":
+		return "cancelled by " + by + ": " + reason
+	case by != "":
+		return "cancelled by " + by
+	case reason != "":
+		return "cancelled: " + reason
+	default:
+		return "cancelled"
+	}
+}
+
+// Cancellation reports the recorded cancellation, if any.
+func (m ExecutionMetadata) Cancellation() (ExecutionCancellation, bool) {
+	cancellation := ExecutionCancellation{By: m.Value(ExecutionMetadataCancelledBy), Reason: m.Value(ExecutionMetadataCancelReason)}
+	return cancellation, cancellation.By != "" || cancellation.Reason != ""
+}
+
+func (m ExecutionMetadata) SetCancellation(cancellation ExecutionCancellation) {
+	m.Set(ExecutionMetadataCancelledBy, strings.TrimSpace(cancellation.By))
+	m.Set(ExecutionMetadataCancelReason, strings.TrimSpace(cancellation.Reason))
+}
+
 func (m ExecutionMetadata) Value(key string) string {
 	return strings.TrimSpace(m[key])
 }
@@ -80,6 +136,54 @@ func (m ExecutionMetadata) Int64(key string) (int64, bool) {
 	return parsed, err == nil
 }
 
+// Priority orders the execution in the queue; higher leases first. Executions
+// without one have priority zero.
+func (m ExecutionMetadata) Priority() int {
+	priority, _ := m.Int64(ExecutionMetadataPriority)
+	return int(priority)
+}
+
+// ConcurrencyGroup returns the concurrency group of the execution and how
+// many of its holders may run at once.
+func (m ExecutionMetadata) ConcurrencyGroup() (group string, maxRunning int, ok bool) {
+	group = m.Value(ExecutionMetadataConcurrencyGroup)
+	if group == "" {
+		return "", 0, false
+	}
+	limit, _ := m.Int64(ExecutionMetadataConcurrencyMaxRunning)
+	return group, max(int(limit), 1), true
+}
+
+// AffinityKey identifies the pipeline job, matrix entry and shard the
+// execution runs. Executions of the same key share a workspace on an agent.
+// Executions outside pipelines have no key.
+func (m ExecutionMetadata) AffinityKey() string {
+	jobID := m.Value(ExecutionMetadataPipelineJobID)
+	if jobID == "" {
+		return ""
+	}
+	matrix := m.Value(ExecutionMetadataMatrixName)
+	if matrix == "" {
+		if index := m.Value(ExecutionMetadataMatrixIndex); index != "" {
+			matrix = "idx-" + index
+		}
+	}
+	return strings.Join([]string{
+		m.Value(ExecutionMetadataProjectID), m.Value(ExecutionMetadataPipelineID), jobID, matrix, m.Value(ExecutionMetadataShardIndex),
+	}, "/")
+}
+
+// AgentAffinity returns the agent the execution prefers and how long other
+// agents leave it to that agent.
+func (m ExecutionMetadata) AgentAffinity() (agentID string, wait time.Duration, ok bool) {
+	agentID = m.Value(ExecutionMetadataAffinityAgentID)
+	seconds, _ := m.Int64(ExecutionMetadataAffinityWaitSeconds)
+	if agentID == "" || seconds <= 0 {
+		return "", 0, false
+	}
+	return agentID, time.Duration(seconds) * time.Second, true
+}
+
 func (m ExecutionMetadata) CSV(key string) []string {
 	raw := m.Value(key)
 	if raw == "" {
@@ -39,6 +39,7 @@ type PipelineChain struct {
 	Pipelines         []string
 	SupportsDryRun    bool
 	VersionPipelineID int64
+	Schedule          *PipelineSchedule
 }
 
 type ProjectDetails struct {
@@ -53,9 +54,35 @@ type PipelineDetails struct {
 	DependsOn  []string
 	SourceRepo string
 	SourceRef  string
+	VCSTrigger *PipelineVCSTrigger
+	Schedule   *PipelineSchedule
 	Jobs       []PipelineJobDetails
 }
 
+// PipelineSchedule is the cron schedule of a pipeline or pipeline chain and
+// the scheduler's view of its next and last fire.
+type PipelineSchedule struct {
+	Cron        string
+	Timezone    string
+	SourceRef   string
+	DryRun      bool
+	CatchUp     string
+	NextFireUTC time.Time
+	LastFireUTC time.Time
+	LastError   string
+}
+
+// PipelineVCSTrigger is the poll state of a pipeline started by new commits
+// on its vcs_source.
+type PipelineVCSTrigger struct {
+	PollIntervalSeconds int
+	LastSeenCommit      string
+	LastPolledUTC       time.Time
+	LastPollError       string
+	LastTriggeredCommit string
+	LastTriggeredUTC    time.Time
+}
+
 type PipelineJobDetails struct {
 	ID             string
 	Needs          []string
@@ -0,0 +1,77 @@
+package domain
+
+import "time"
+
+// TestHistoryScope selects the executions whose test results are compared:
+// one pipeline job of a project and, for matrix jobs, one matrix entry.
+type TestHistoryScope struct {
+	ProjectID     int64
+	PipelineID    string
+	PipelineJobID string
+	MatrixName    string
+}
+
+// TestHistoryScopeOf returns the history scope of an execution; only
+// executions of a pipeline job have one.
+func TestHistoryScopeOf(metadata ExecutionMetadata) (TestHistoryScope, bool) {
+	projectID, _ := metadata.Int64(ExecutionMetadataProjectID)
+	pipelineJobID := metadata.Value(ExecutionMetadataPipelineJobID)
+	if projectID <= 0 || pipelineJobID == "" {
+		return TestHistoryScope{}, false
+	}
+	return TestHistoryScope{
+		ProjectID: projectID, PipelineID: metadata.Value(ExecutionMetadataPipelineID),
+		PipelineJobID: pipelineJobID, MatrixName: metadata.Value(ExecutionMetadataMatrixName),
+	}, true
+}
+
+// TestCaseRun is the result of one test case in one execution. Runs that share
+// a source commit or an attempt root ran the same code.
+type TestCaseRun struct {
+	JobExecutionID  string
+	AttemptRootID   string
+	SourceCommit    string
+	Package         string
+	Name            string
+	Status          string
+	DurationSeconds float64
+	CreatedUTC      time.Time
+}
+
+// TestCaseHistory correlates the runs of one test case, newest first.
+// A test is flaky on a commit when it failed and later passed without a code
+// change, either on a rerun attempt or on another run of the same commit.
+// FlakinessScore is the share of failing commits that turned out flaky.
+type TestCaseHistory struct {
+	Package                string
+	Name                   string
+	Runs                   []TestCaseRun
+	Passed                 int
+	Failed                 int
+	Skipped                int
+	StreakStatus           string
+	StreakLength           int
+	FailingCommits         int
+	FlakyCommits           int
+	FlakinessScore         float64
+	AverageDurationSeconds float64
+	RecentDurationSeconds  float64
+	// DurationTrend is the relative change of the recent average duration
+	// against the runs before them, or 0 without enough history.
+	DurationTrend float64
+}
+
+// TestHistory is the correlated history of every test case in a scope.
+type TestHistory struct {
+	Scope      TestHistoryScope
+	Executions int
+	Cases      []TestCaseHistory
+}
+
+const (
+	// TestHistoryBadgeNewFailure marks a failing test whose previous run
+	// passed or that has no earlier runs.
+	TestHistoryBadgeNewFailure = "new_failure"
+	// TestHistoryBadgeKnownFlaky marks a test that has been flaky before.
+	TestHistoryBadgeKnownFlaky = "known_flaky"
+)
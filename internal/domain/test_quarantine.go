@@ -0,0 +1,42 @@
+package domain
+
+import (
+	"strings"
+	"time"
+)
+
+// TestQuarantineEntry keeps a known-flaky test of a project from failing its
+// jobs. The test still runs and is reported; only its failures stop counting
+// until the entry expires. An empty Package matches the name in any package.
+type TestQuarantineEntry struct {
+	ID         int64
+	ProjectID  int64
+	Package    string
+	Name       string
+	Owner      string
+	Reason     string
+	CreatedBy  string
+	CreatedUTC time.Time
+	ExpiresUTC time.Time
+}
+
+func (e TestQuarantineEntry) Expired(now time.Time) bool {
+	return !e.ExpiresUTC.After(now)
+}
+
+func (e TestQuarantineEntry) Matches(pkg, name string) bool {
+	if strings.TrimSpace(name) != e.Name {
+		return false
+	}
+	return e.Package == "" || strings.TrimSpace(pkg) == e.Package
+}
+
+// ActiveTestQuarantine returns the unexpired entry that quarantines a test.
+func ActiveTestQuarantine(entries []TestQuarantineEntry, pkg, name string, now time.Time) (TestQuarantineEntry, bool) {
+	for _, entry := range entries {
+		if !entry.Expired(now) && entry.Matches(pkg, name) {
+			return entry, true
+		}
+	}
+	return TestQuarantineEntry{}, false
+}
@@ -0,0 +1,124 @@
+package domain
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ShardTest is a test assigned to a shard. DurationSeconds is its average
+// duration in earlier runs.
+type ShardTest struct {
+	Package         string  `json:"package,omitempty"`
+	Name            string  `json:"name"`
+	DurationSeconds float64 `json:"duration_seconds,omitempty"`
+}
+
+// Shard returns the zero-based shard index and shard count an execution runs
+// as, if it is one shard of a sharded job.
+func (m ExecutionMetadata) Shard() (index, total int, ok bool) {
+	total, err := strconv.Atoi(m.Value(ExecutionMetadataShardTotal))
+	if err != nil || total <= 0 {
+		return 0, 0, false
+	}
+	index, err = strconv.Atoi(m.Value(ExecutionMetadataShardIndex))
+	if err != nil || index < 0 || index >= total {
+		return 0, 0, false
+	}
+	return index, total, true
+}
+
+// ShardTests returns the tests assigned to the execution's shard. It is empty
+// when there was no test history to balance.
+func (m ExecutionMetadata) ShardTests() ([]ShardTest, error) {
+	raw := m.Value(ExecutionMetadataShardTestsJSON)
+	if raw == "" {
+		return nil, nil
+	}
+	var tests []ShardTest
+	if err := json.Unmarshal([]byte(raw), &tests); err != nil {
+		return nil, fmt.Errorf("decode shard tests: %w", err)
+	}
+	return tests, nil
+}
+
+// ShardLabel names a shard for display, counting from one.
+func ShardLabel(index, total int) string {
+	return fmt.Sprintf("shard %d/%d", index+1, total)
+}
+
+// BalanceTestShards splits the tests seen in runs over total shards so their
+// expected durations come out as even as possible: the longest tests are
+// placed first, each on the shard with the least work so far. Shards list
+// their tests by package and name.
+func BalanceTestShards(runs []TestCaseRun, total int) [][]ShardTest {
+	if total <= 0 {
+		return nil
+	}
+	type caseKey struct{ pkg, name string }
+	type durationSum struct {
+		seconds float64
+		count   int
+	}
+	sums := map[caseKey]*durationSum{}
+	for _, run := range runs {
+		name := strings.TrimSpace(run.Name)
+		if name == "" {
+			continue
+		}
+		key := caseKey{pkg: strings.TrimSpace(run.Package), name: name}
+		sum := sums[key]
+		if sum == nil {
+			sum = &durationSum{}
+			sums[key] = sum
+		}
+		// Skipped runs say nothing about how long a test takes.
+		if run.Status != "skip" {
+			sum.seconds += run.DurationSeconds
+			sum.count++
+		}
+	}
+	tests := make([]ShardTest, 0, len(sums))
+	for key, sum := range sums {
+		test := ShardTest{Package: key.pkg, Name: key.name}
+		if sum.count > 0 {
+			test.DurationSeconds = sum.seconds / float64(sum.count)
+		}
+		tests = append(tests, test)
+	}
+	sort.Slice(tests, func(i, j int) bool {
+		if tests[i].DurationSeconds != tests[j].DurationSeconds {
+			return tests[i].DurationSeconds > tests[j].DurationSeconds
+		}
+		return shardTestLess(tests[i], tests[j])
+	})
+	shards := make([][]ShardTest, total)
+	loads := make([]float64, total)
+	for i, test := range tests {
+		target := 0
+		for shard := 1; shard < total; shard++ {
+			if loads[shard] < loads[target] || (loads[shard] == loads[target] && len(shards[shard]) < len(shards[target])) {
+				target = shard
+			}
+		}
+		// Tests without any recorded duration are spread round-robin.
+		if test.DurationSeconds == 0 {
+			target = i % total
+		}
+		shards[target] = append(shards[target], test)
+		loads[target] += test.DurationSeconds
+	}
+	for _, shard := range shards {
+		sort.Slice(shard, func(i, j int) bool { return shardTestLess(shard[i], shard[j]) })
+	}
+	return shards
+}
+
+func shardTestLess(a, b ShardTest) bool {
+	if a.Package != b.Package {
+		return a.Package < b.Package
+	}
+	return a.Name < b.Name
+}
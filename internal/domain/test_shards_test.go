@@ -0,0 +1,51 @@
+package domain
+
+import "testing"
+
+func TestBalanceTestShardsEvensOutDurations(t *testing.T) {
+	run := func(pkg, name, status string, seconds float64) TestCaseRun {
+		return TestCaseRun{Package: pkg, Name: name, Status: status, DurationSeconds: seconds}
+	}
+	shards := BalanceTestShards([]TestCaseRun{
+		run("p", "TestSlow", "pass", 8), run("p", "TestSlow", "fail", 12),
+		run("p", "TestMedium", "pass", 6),
+		run("q", "TestA", "pass", 3), run("q", "TestB", "pass", 3),
+		run("q", "TestSkipped", "skip", 0),
+		run("r", "TestNew", "pass", 0),
+	}, 2)
+	if len(shards) != 2 {
+		t.Fatalf("expected two shards, got %d", len(shards))
+	}
+	load := func(shard []ShardTest) (total float64) {
+		for _, test := range shard {
+			total += test.DurationSeconds
+		}
+		return total
+	}
+	if load(shards[0]) != 10 || load(shards[1]) != 12 {
+		t.Fatalf("unbalanced shards %+v", shards)
+	}
+	if len(shards[0])+len(shards[1]) != 6 {
+		t.Fatalf("expected every test to be assigned once, got %+v", shards)
+	}
+	if first := shards[0][0]; first.Package != "p" || first.Name != "TestSlow" || first.DurationSeconds != 10 {
+		t.Fatalf("expected shards sorted by package and name with averaged durations, got %+v", shards[0])
+	}
+	if BalanceTestShards(nil, 3) == nil || len(BalanceTestShards(nil, 3)) != 3 {
+		t.Fatal("expected empty shards without history")
+	}
+}
+
+func TestExecutionMetadataShard(t *testing.T) {
+	metadata := ExecutionMetadata{ExecutionMetadataShardIndex: "1", ExecutionMetadataShardTotal: "4"}
+	if index, total, ok := metadata.Shard(); !ok || index != 1 || total != 4 || ShardLabel(index, total) != "shard 2/4" {
+		t.Fatalf("unexpected shard %d/%d ok=%v", index, total, ok)
+	}
+	metadata.Set(ExecutionMetadataShardIndex, "4")
+	if _, _, ok := metadata.Shard(); ok {
+		t.Fatal("expected an out of range index to be rejected")
+	}
+	if _, _, ok := (ExecutionMetadata{}).Shard(); ok {
+		t.Fatal("expected unsharded metadata to have no shard")
+	}
+}
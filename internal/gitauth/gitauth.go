@@ -0,0 +1,168 @@
+// Package gitauth authenticates git child processes with a repository
+// credential through their environment only. An HTTPS token is answered by
+// an inline credential helper scoped to the repository host and an SSH key is
+// passed through GIT_SSH_COMMAND; nothing is written to a repository's
+// config, so a checkout keeps no trace of the credential.
+package gitauth
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/izzyreal/ciwi/internal/protocol"
+)
+
+// DefaultTokenUsername is sent with an HTTPS token that has no username;
+// GitHub and GitLab accept any non-empty name next to a token.
+const DefaultTokenUsername = "x-access-token"
+
+const credentialHelper = `!f() { test "$1" = get || exit 0; printf 'username=%s\npassword=%s\n' "$CIWI_GIT_USERNAME" "$CIWI_GIT_PASSWORD"; }; f`
+
+// Session is the environment that authenticates git against one repository.
+// Close removes the key material an SSH session keeps on disk.
+type Session struct {
+	env []string
+	dir string
+}
+
+// Start prepares the environment authenticating git against repoURL. SSH keys
+// are written to a private temporary directory outside any workspace.
+func Start(cred protocol.SourceCredential, repoURL string) (*Session, error) {
+	if strings.TrimSpace(cred.Secret) == "" {
+		return nil, fmt.Errorf("repository credential has no secret")
+	}
+	switch cred.Kind {
+	case protocol.RepoCredentialHTTPSToken:
+		return startHTTPS(cred, repoURL)
+	case protocol.RepoCredentialSSHKey:
+		return startSSH(cred)
+	default:
+		return nil, fmt.Errorf("unsupported repository credential kind %q", cred.Kind)
+	}
+}
+
+// Env returns the variables to add to a git command's environment.
+func (s *Session) Env() []string {
+	if s == nil {
+		return nil
+	}
+	return append([]string(nil), s.env...)
+}
+
+func (s *Session) Close() error {
+	if s == nil || s.dir == "" {
+		return nil
+	}
+	return os.RemoveAll(s.dir)
+}
+
+func startHTTPS(cred protocol.SourceCredential, repoURL string) (*Session, error) {
+	parsed, err := url.Parse(strings.TrimSpace(repoURL))
+	if err != nil || (parsed.Scheme != "https" && parsed.Scheme != "http") || parsed.Host == "" {
+		return nil, fmt.Errorf("an https_token credential needs an http(s) repository url, got %q", repoURL)
+	}
+	username := strings.TrimSpace(cred.Username)
+	if username == "" {
+		username = DefaultTokenUsername
+	}
+	// The empty helper first drops any helper configured on the host for
+	// this URL, so the token is neither answered by nor stored in a keychain.
+	scope := "credential." + parsed.Scheme + "://" + parsed.Host + ".helper"
+	env := configEnv([][2]string{{scope, ""}, {scope, credentialHelper}})
+	env = append(env, "CIWI_GIT_USERNAME="+username, "CIWI_GIT_PASSWORD="+cred.Secret, "GIT_TERMINAL_PROMPT=0")
+	return &Session{env: env}, nil
+}
+
+func startSSH(cred protocol.SourceCredential) (*Session, error) {
+	dir, err := os.MkdirTemp("", "ciwi-git-ssh-*")
+	if err != nil {
+		return nil, fmt.Errorf("create ssh key directory: %w", err)
+	}
+	session := &Session{dir: dir}
+	key := cred.Secret
+	if !strings.HasSuffix(key, "\n") {
+		key += "\n"
+	}
+	keyPath := filepath.Join(dir, "id")
+	if err := os.WriteFile(keyPath, []byte(key), 0o600); err != nil {
+		_ = session.Close()
+		return nil, fmt.Errorf("write ssh key: %w", err)
+	}
+	command := []string{"ssh", "-i", shellQuote(keyPath), "-o", "IdentitiesOnly=yes", "-o", "BatchMode=yes"}
+	if knownHosts := strings.TrimSpace(cred.KnownHosts); knownHosts != "" {
+		knownHostsPath := filepath.Join(dir, "known_hosts")
+		if err := os.WriteFile(knownHostsPath, []byte(knownHosts+"\n"), 0o600); err != nil {
+			_ = session.Close()
+			return nil, fmt.Errorf("write ssh known hosts: %w", err)
+		}
+		command = append(command, "-o", "UserKnownHostsFile="+shellQuote(knownHostsPath), "-o", "StrictHostKeyChecking=yes")
+	}
+	session.env = []string{"GIT_SSH_COMMAND=" + strings.Join(command, " "), "GIT_TERMINAL_PROMPT=0"}
+	return session, nil
+}
+
+// configEnv adds git config entries after any the process already passes
+// through GIT_CONFIG_COUNT.
+func configEnv(entries [][2]string) []string {
+	base, _ := strconv.Atoi(strings.TrimSpace(os.Getenv("GIT_CONFIG_COUNT")))
+	if base < 0 {
+		base = 0
+	}
+	env := make([]string, 0, 2*len(entries)+1)
+	for i, entry := range entries {
+		env = append(env, fmt.Sprintf("GIT_CONFIG_KEY_%d=%s", base+i, entry[0]), fmt.Sprintf("GIT_CONFIG_VALUE_%d=%s", base+i, entry[1]))
+	}
+	return append(env, fmt.Sprintf("GIT_CONFIG_COUNT=%d", base+len(entries)))
+}
+
+// shellQuote quotes a path for GIT_SSH_COMMAND, which git runs through sh.
+func shellQuote(path string) string {
+	return "'" + strings.ReplaceAll(filepath.ToSlash(path), "'", `'\''`) + "'"
+}
+
+// Provider resolves the credential for the repository a context is bound to.
+// A nil credential leaves git unauthenticated.
+type Provider func() (*protocol.SourceCredential, error)
+
+type contextKey struct{}
+
+type binding struct {
+	repoURL string
+	resolve func() (*protocol.SourceCredential, error)
+}
+
+// WithProvider binds git commands run under ctx to repoURL's credential. The
+// provider is only asked once, when the first git command needs it.
+func WithProvider(ctx context.Context, repoURL string, provider Provider) context.Context {
+	if provider == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, contextKey{}, binding{repoURL: repoURL, resolve: sync.OnceValues(provider)})
+}
+
+// CommandEnv returns the environment authenticating a git command run under
+// ctx and a release function to call once the command has finished.
+func CommandEnv(ctx context.Context) ([]string, func(), error) {
+	b, ok := ctx.Value(contextKey{}).(binding)
+	if !ok {
+		return nil, func() {}, nil
+	}
+	cred, err := b.resolve()
+	if err != nil {
+		return nil, func() {}, fmt.Errorf("resolve credential for %s: %w", b.repoURL, err)
+	}
+	if cred == nil {
+		return nil, func() {}, nil
+	}
+	session, err := Start(*cred, b.repoURL)
+	if err != nil {
+		return nil, func() {}, err
+	}
+	return session.Env(), func() { _ = session.Close() }, nil
+}
@@ -0,0 +1,123 @@
+package gitauth
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/izzyreal/ciwi/internal/protocol"
+)
+
+func TestHTTPSTokenIsOnlyAnsweredForTheRepositoryHost(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skipf("git not available: %v", err)
+	}
+	t.Setenv("GIT_CONFIG_COUNT", "1")
+	t.Setenv("GIT_CONFIG_KEY_0", "protocol.file.allow")
+	t.Setenv("GIT_CONFIG_VALUE_0", "always")
+	session, err := Start(protocol.SourceCredential{Kind: protocol.RepoCredentialHTTPSToken, Secret: "s3cret"}, "https://git.example.com/team/app.git")
+	if err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	defer session.Close()
+	env := strings.Join(session.Env(), "\n")
+	if !strings.Contains(env, "GIT_CONFIG_KEY_1=credential.https://git.example.com.helper") || !strings.Contains(env, "GIT_CONFIG_COUNT=3") {
+		t.Fatalf("expected the helper to follow existing config entries, got:\n%s", env)
+	}
+
+	fill := func(host string) (string, error) {
+		cmd := exec.Command("git", "credential", "fill")
+		cmd.Env = append(os.Environ(), session.Env()...)
+		cmd.Stdin = strings.NewReader("protocol=https\nhost=" + host + "\n\n")
+		out, err := cmd.CombinedOutput()
+		return string(out), err
+	}
+	out, err := fill("git.example.com")
+	if err != nil || !strings.Contains(out, "username="+DefaultTokenUsername+"\n") || !strings.Contains(out, "password=s3cret\n") {
+		t.Fatalf("expected the helper to answer with the token, got %q err=%v", out, err)
+	}
+	if out, _ := fill("other.example.com"); strings.Contains(out, "s3cret") {
+		t.Fatalf("expected other hosts not to get the token, got %q", out)
+	}
+}
+
+func TestSSHKeyLivesOutsideTheRepositoryUntilClosed(t *testing.T) {
+	session, err := Start(protocol.SourceCredential{Kind: protocol.RepoCredentialSSHKey, Secret: "KEY", KnownHosts: "git.example.com ssh-ed25519 AAAA"}, "git@git.example.com:team/app.git")
+	if err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	command := ""
+	for _, entry := range session.Env() {
+		if value, ok := strings.CutPrefix(entry, "GIT_SSH_COMMAND="); ok {
+			command = value
+		}
+	}
+	if !strings.Contains(command, "-o IdentitiesOnly=yes") || !strings.Contains(command, "StrictHostKeyChecking=yes") {
+		t.Fatalf("unexpected GIT_SSH_COMMAND %q", command)
+	}
+	data, err := os.ReadFile(filepath.Join(session.dir, "id"))
+	if err != nil || string(data) != "KEY\n" {
+		t.Fatalf("expected the key file to hold the key, got %q err=%v", data, err)
+	}
+	if info, err := os.Stat(filepath.Join(session.dir, "id")); runtime.GOOS != "windows" && (err != nil || info.Mode().Perm()&0o077 != 0) {
+		t.Fatalf("expected the key file to be private, mode=%v", info.Mode())
+	}
+	if err := session.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if _, err := os.Stat(session.dir); !os.IsNotExist(err) {
+		t.Fatalf("expected Close to remove the key, stat err=%v", err)
+	}
+}
+
+func TestStartRejectsUnusableCredentials(t *testing.T) {
+	cases := map[string]struct {
+		cred    protocol.SourceCredential
+		repoURL string
+	}{
+		"no secret":      {protocol.SourceCredential{Kind: protocol.RepoCredentialHTTPSToken}, "https://git.example.com/app.git"},
+		"token over ssh": {protocol.SourceCredential{Kind: protocol.RepoCredentialHTTPSToken, Secret: "t"}, "git@git.example.com:app.git"},
+		"unknown kind":   {protocol.SourceCredential{Kind: "password", Secret: "t"}, "https://git.example.com/app.git"},
+	}
+	for name, tc := range cases {
+		if _, err := Start(tc.cred, tc.repoURL); err == nil {
+			t.Fatalf("%s: expected Start to fail", name)
+		}
+	}
+}
+
+func TestCommandEnvResolvesTheProviderOnce(t *testing.T) {
+	if env, release, err := CommandEnv(context.Background()); err != nil || env != nil {
+		t.Fatalf("expected no environment without a provider, got %v err=%v", env, err)
+	} else {
+		release()
+	}
+
+	calls := 0
+	ctx := WithProvider(context.Background(), "https://git.example.com/app.git", func() (*protocol.SourceCredential, error) {
+		calls++
+		return &protocol.SourceCredential{Kind: protocol.RepoCredentialHTTPSToken, Secret: "t"}, nil
+	})
+	for i := 0; i < 2; i++ {
+		env, release, err := CommandEnv(ctx)
+		if err != nil || !strings.Contains(strings.Join(env, "\n"), "CIWI_GIT_PASSWORD=t") {
+			t.Fatalf("expected the token environment, got %v err=%v", env, err)
+		}
+		release()
+	}
+	if calls != 1 {
+		t.Fatalf("expected the provider to be asked once, got %d", calls)
+	}
+
+	failing := WithProvider(context.Background(), "https://git.example.com/app.git", func() (*protocol.SourceCredential, error) {
+		return nil, errors.New("vault is sealed")
+	})
+	if _, _, err := CommandEnv(failing); err == nil || !strings.Contains(err.Error(), "vault is sealed") {
+		t.Fatalf("expected the provider error, got %v", err)
+	}
+}
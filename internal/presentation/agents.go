@@ -23,7 +23,14 @@ type AgentView struct {
 	Authorized        bool               `json:"authorized"`
 	Deactivated       bool               `json:"deactivated"`
 	JobInProgress     bool               `json:"job_in_progress"`
+	Slots             int                `json:"slots"`
+	ActiveJobs        int                `json:"active_jobs"`
+	SlotsLabel        string             `json:"slots_label"`
+	SelectedSlots     string             `json:"selected_slots"`
+	SlotOptions       []AgentSlotOption  `json:"slot_options"`
 	CapabilitiesLabel string             `json:"capabilities_label"`
+	LabelsLabel       string             `json:"labels_label"`
+	AssignedLabels    string             `json:"assigned_labels"`
 	RunMode           string             `json:"run_mode"`
 	LastSeen          string             `json:"last_seen"`
 	LastSeenUnixMS    int64              `json:"last_seen_unix_ms"`
@@ -41,6 +48,11 @@ type AgentScriptShell struct {
 	ExampleScript string `json:"example_script"`
 }
 
+type AgentSlotOption struct {
+	Value string `json:"value"`
+	Label string `json:"label"`
+}
+
 type AgentsView struct {
 	Summary string      `json:"summary"`
 	Agents  []AgentView `json:"agents"`
@@ -124,12 +136,20 @@ func agentToView(agent domain.Agent, now time.Time) AgentView {
 		runMode = "Service"
 	}
 	scriptShells := AgentScriptShells(agent.Capabilities)
+	slots := max(agent.Slots, 1)
+	selectedSlots := "0"
+	if agent.SlotsOverridden {
+		selectedSlots = strconv.Itoa(slots)
+	}
 	return AgentView{
 		ID: agent.ID, Hostname: agent.Hostname, Platform: strings.Trim(strings.TrimSpace(agent.OS)+"/"+strings.TrimSpace(agent.Arch), "/"),
 		Version: agent.Version, Status: status, StatusLabel: strings.ToUpper(status[:1]) + status[1:],
 		Authorization: boolLabel(agent.Authorized, "Authorized", "Unauthorized"), Activation: boolLabel(!agent.Deactivated, "Active", "Deactivated"),
 		Authorized: agent.Authorized, Deactivated: agent.Deactivated, JobInProgress: agent.JobInProgress,
-		CapabilitiesLabel: strings.Join(capabilities, ", "), RunMode: runMode, LastSeen: formatAgentTime(agent.LastSeenUTC),
+		Slots: slots, ActiveJobs: agent.ActiveJobs, SlotsLabel: agentSlotsLabel(agent.ActiveJobs, slots),
+		SelectedSlots: selectedSlots, SlotOptions: agentSlotOptions(slots, agent.SlotsOverridden),
+		CapabilitiesLabel: strings.Join(capabilities, ", "), LabelsLabel: domain.FormatAgentLabels(agent.Labels),
+		AssignedLabels: domain.FormatAgentLabels(agent.AssignedLabels), RunMode: runMode, LastSeen: formatAgentTime(agent.LastSeenUTC),
 		LastSeenUnixMS: unixMilliOrZero(agent.LastSeenUTC),
 		RecentLog:      strings.Join(agent.RecentLog, "\n"), UpdateLabel: updateLabel,
 		CanUpdate:    !agent.UpdateInProgress && (agent.UpdateRequested || agent.NeedsUpdate) && status != "offline",
@@ -138,6 +158,41 @@ func agentToView(agent domain.Agent, now time.Time) AgentView {
 	}
 }
 
+func agentSlotsLabel(active, slots int) string {
+	if slots == 1 {
+		return boolLabel(active > 0, "Job slot: busy", "Job slot: free")
+	}
+	return "Job slots: " + strconv.Itoa(active) + "/" + strconv.Itoa(slots) + " busy"
+}
+
+// agentSlotOptions offers the agent's own setting plus common slot counts.
+// An override outside those counts stays selectable.
+func agentSlotOptions(slots int, overridden bool) []AgentSlotOption {
+	agentSetting := "Agent setting"
+	if !overridden {
+		agentSetting += " (" + strconv.Itoa(slots) + ")"
+	}
+	options := []AgentSlotOption{{Value: "0", Label: agentSetting}}
+	listed := false
+	for _, count := range []int{1, 2, 4, 8, 16, 32, domain.MaxAgentJobSlots} {
+		if overridden && !listed && slots <= count {
+			listed = true
+			if slots < count {
+				options = append(options, AgentSlotOption{Value: strconv.Itoa(slots), Label: slotCountLabel(slots)})
+			}
+		}
+		options = append(options, AgentSlotOption{Value: strconv.Itoa(count), Label: slotCountLabel(count)})
+	}
+	return options
+}
+
+func slotCountLabel(count int) string {
+	if count == 1 {
+		return "1 slot"
+	}
+	return strconv.Itoa(count) + " slots"
+}
+
 func unixMilliOrZero(value time.Time) int64 {
 	if value.IsZero() {
 		return 0
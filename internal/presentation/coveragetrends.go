@@ -0,0 +1,91 @@
+package presentation
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/izzyreal/ciwi/internal/domain"
+)
+
+type CoverageTrendView struct {
+	Key     string
+	Label   string
+	Summary string
+	Tone    string
+	Points  []CoverageTrendPointView
+}
+
+// CoverageTrendPointView is one run of a trend, oldest first, so the points
+// read left to right. Delta compares it with the run before it.
+type CoverageTrendPointView struct {
+	JobExecutionID string
+	PercentLabel   string
+	DeltaLabel     string
+	DateLabel      string
+	Tone           string
+	Progress       domain.Progress
+}
+
+type coverageTrendSource interface {
+	ListCoverageTrends(context.Context, int64) ([]domain.CoverageTrend, error)
+}
+
+// WithCoverageTrends adds the recent coverage of each pipeline job to project
+// details.
+func (q *ProjectDetailsQueries) WithCoverageTrends(trends coverageTrendSource) *ProjectDetailsQueries {
+	q.coverage = trends
+	return q
+}
+
+func presentCoverageTrends(trends []domain.CoverageTrend) []CoverageTrendView {
+	views := make([]CoverageTrendView, 0, len(trends))
+	for _, trend := range trends {
+		if len(trend.Runs) == 0 {
+			continue
+		}
+		scope := trend.Scope
+		view := CoverageTrendView{
+			Key:   scope.PipelineID + "/" + scope.PipelineJobID + "/" + scope.MatrixName,
+			Label: scope.PipelineID + " / " + scope.PipelineJobID, Tone: "muted",
+		}
+		if scope.MatrixName != "" {
+			view.Label += " (" + scope.MatrixName + ")"
+		}
+		for i := len(trend.Runs) - 1; i >= 0; i-- {
+			run := trend.Runs[i]
+			point := CoverageTrendPointView{
+				JobExecutionID: run.JobExecutionID,
+				PercentLabel:   fmt.Sprintf("%.1f%%", run.Percent),
+				DateLabel:      run.CreatedUTC.UTC().Format(time.DateOnly),
+				Tone:           "muted",
+				Progress:       domain.Progress{State: domain.ProgressDeterminate, Fraction: min(1, max(0, run.Percent/100))},
+			}
+			if i+1 < len(trend.Runs) {
+				point.DeltaLabel = coverageDeltaLabel(run.Percent - trend.Runs[i+1].Percent)
+			}
+			if run.GateFailed {
+				point.Tone = "danger"
+			}
+			view.Points = append(view.Points, point)
+		}
+		latest := trend.Runs[0]
+		view.Summary = fmt.Sprintf("Latest %.1f%%", latest.Percent)
+		if len(trend.Runs) > 1 {
+			view.Summary += " · " + coverageDeltaLabel(latest.Percent-trend.Runs[1].Percent) + " since the previous run"
+		}
+		if latest.GateFailed {
+			view.Summary += " · coverage gate failed"
+			view.Tone = "danger"
+		}
+		views = append(views, view)
+	}
+	return views
+}
+
+func coverageDeltaLabel(delta float64) string {
+	if delta > -0.05 && delta < 0.05 {
+		return "±0.0"
+	}
+	return fmt.Sprintf("%+.1f", delta)
+}
@@ -0,0 +1,33 @@
+package presentation
+
+import (
+	"testing"
+	"time"
+
+	"github.com/izzyreal/ciwi/internal/domain"
+)
+
+func TestPresentCoverageTrendsOrdersPointsOldestFirst(t *testing.T) {
+	day := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	scope := domain.TestHistoryScope{ProjectID: 1, PipelineID: "build", PipelineJobID: "unit", MatrixName: "linux"}
+	views := presentCoverageTrends([]domain.CoverageTrend{{Scope: scope, Runs: []domain.CoverageRun{
+		{JobExecutionID: "j3", Percent: 71.25, GateFailed: true, CreatedUTC: day},
+		{JobExecutionID: "j2", Percent: 80, CreatedUTC: day.Add(-24 * time.Hour)},
+		{JobExecutionID: "j1", Percent: 80, CreatedUTC: day.Add(-48 * time.Hour)},
+	}}})
+	if len(views) != 1 {
+		t.Fatalf("expected one trend, got %+v", views)
+	}
+	view := views[0]
+	if view.Label != "build / unit (linux)" || view.Tone != "danger" || view.Summary != "Latest 71.2% · -8.8 since the previous run · coverage gate failed" {
+		t.Fatalf("unexpected trend %+v", view)
+	}
+	if len(view.Points) != 3 || view.Points[0].JobExecutionID != "j1" || view.Points[0].DeltaLabel != "" || view.Points[1].DeltaLabel != "±0.0" {
+		t.Fatalf("unexpected points %+v", view.Points)
+	}
+	last := view.Points[2]
+	if last.Tone != "danger" || last.PercentLabel != "71.2%" || last.DateLabel != "2026-03-01" ||
+		last.Progress.State != domain.ProgressDeterminate || last.Progress.Fraction != 0.7125 {
+		t.Fatalf("unexpected latest point %+v", last)
+	}
+}
@@ -28,6 +28,8 @@ func PresentExecutionCard(card domain.ExecutionCard, queued bool) ExecutionCardD
 	status, tone := "succeeded", "success"
 	if card.Summary.Failed > 0 {
 		status, tone = "failed", "danger"
+	} else if card.Summary.Cancelled > 0 && !queued {
+		status, tone = "cancelled", "warning"
 	} else if queued && card.Summary.InProgress > 0 {
 		status, tone = "running", "warning"
 	} else if queued {
@@ -45,6 +47,12 @@ func ExecutionSummaryLabel(summary domain.ExecutionSummary) string {
 	if summary.Failed > 0 {
 		parts = append(parts, fmt.Sprintf("%d failed", summary.Failed))
 	}
+	if summary.Cancelled > 0 {
+		parts = append(parts, fmt.Sprintf("%d cancelled", summary.Cancelled))
+	}
+	if summary.Skipped > 0 {
+		parts = append(parts, fmt.Sprintf("%d skipped", summary.Skipped))
+	}
 	if summary.InProgress > 0 {
 		parts = append(parts, fmt.Sprintf("%d in progress", summary.InProgress))
 	}
@@ -74,6 +82,10 @@ func statusWithTestCounts(status string, summary *domain.JobTestSummary) string
 	if summary == nil || summary.Total <= 0 {
 		return status
 	}
+	// Only quarantined tests failed; the job passed but deserves a look.
+	if summary.Quarantined > 0 && strings.EqualFold(strings.TrimSpace(status), "succeeded") {
+		status += " with warnings"
+	}
 	return fmt.Sprintf("%s (%d/%d passed)", status, summary.Passed, summary.Total)
 }
 
@@ -119,6 +131,81 @@ func PipelineGraphSummaryLabel(jobsCount, dependencyCount int) string {
 	return fmt.Sprintf("%s · %s", countLabel(jobsCount, "job", "jobs"), countLabel(dependencyCount, "dependency", "dependencies"))
 }
 
+// PipelineVCSTriggerLabel summarizes the poll state of a trigger: vcs
+// pipeline. Pipelines without a vcs trigger get an empty label.
+func PipelineVCSTriggerLabel(state *domain.PipelineVCSTrigger) string {
+	if state == nil {
+		return ""
+	}
+	interval := "at the server default interval"
+	if state.PollIntervalSeconds > 0 {
+		interval = fmt.Sprintf("every %ds", state.PollIntervalSeconds)
+	}
+	parts := []string{"Polls for new commits " + interval}
+	if state.LastPolledUTC.IsZero() {
+		parts = append(parts, "not polled yet")
+	} else {
+		parts = append(parts, "last polled "+DeclarativeTimestamp(state.LastPolledUTC))
+	}
+	if commit := shortCommitLabel(state.LastSeenCommit); commit != "" {
+		parts = append(parts, "last seen "+commit)
+	}
+	if commit := shortCommitLabel(state.LastTriggeredCommit); commit != "" && !state.LastTriggeredUTC.IsZero() {
+		parts = append(parts, fmt.Sprintf("last triggered %s at %s", commit, DeclarativeTimestamp(state.LastTriggeredUTC)))
+	}
+	return strings.Join(parts, " · ")
+}
+
+func PipelineVCSTriggerError(state *domain.PipelineVCSTrigger) string {
+	if state == nil || strings.TrimSpace(state.LastPollError) == "" {
+		return ""
+	}
+	return "Last poll error: " + strings.TrimSpace(state.LastPollError)
+}
+
+// PipelineScheduleLabel summarizes the cron schedule of a pipeline or chain
+// and when it fires next.
+func PipelineScheduleLabel(sched *domain.PipelineSchedule) string {
+	if sched == nil {
+		return ""
+	}
+	zone := DeclarativeDefaultLabel(sched.Timezone, "UTC")
+	parts := []string{fmt.Sprintf("Runs on schedule %s (%s)", strings.TrimSpace(sched.Cron), zone)}
+	if sched.DryRun {
+		parts = append(parts, "dry run")
+	}
+	if ref := strings.TrimSpace(sched.SourceRef); ref != "" {
+		parts = append(parts, "ref "+ref)
+	}
+	if sched.NextFireUTC.IsZero() {
+		parts = append(parts, "no upcoming fire")
+	} else {
+		parts = append(parts, "next "+DeclarativeTimestamp(sched.NextFireUTC))
+	}
+	if !sched.LastFireUTC.IsZero() {
+		parts = append(parts, "last fired "+DeclarativeTimestamp(sched.LastFireUTC))
+	}
+	if catchUp := strings.TrimSpace(sched.CatchUp); catchUp != "" {
+		parts = append(parts, "missed fires: "+catchUp)
+	}
+	return strings.Join(parts, " · ")
+}
+
+func PipelineScheduleError(sched *domain.PipelineSchedule) string {
+	if sched == nil || strings.TrimSpace(sched.LastError) == "" {
+		return ""
+	}
+	return "Last scheduled run error: " + strings.TrimSpace(sched.LastError)
+}
+
+func shortCommitLabel(commit string) string {
+	commit = strings.TrimSpace(commit)
+	if len(commit) > 12 {
+		return commit[:12]
+	}
+	return commit
+}
+
 func ProjectJobSummaryLabel(stepsCount int, runsOn string) string {
 	return fmt.Sprintf("%s · runs on: %s", countLabel(stepsCount, "step", "steps"), DeclarativeDefaultLabel(runsOn, "unspecified"))
 }
@@ -16,6 +16,12 @@ func TestDeclarativeExecutionPresentationIsTransportIndependent(t *testing.T) {
 	if display.Status != "failed" || display.SummaryTone != "danger" || display.SummaryLabel != "1/4 successful, 1 failed, 1 in progress, 1 waiting" || display.JobExecutionIDsCSV != "job-1,job-2" {
 		t.Fatalf("display = %+v", display)
 	}
+	cancelled := PresentExecutionCard(domain.ExecutionCard{
+		Summary: domain.ExecutionSummary{TotalJobs: 3, Succeeded: 1, Cancelled: 1, Skipped: 1},
+	}, false)
+	if cancelled.Status != "cancelled" || cancelled.SummaryTone != "warning" || cancelled.SummaryLabel != "1/3 successful, 1 cancelled, 1 skipped" {
+		t.Fatalf("cancelled display = %+v", cancelled)
+	}
 	started := time.Date(2026, 8, 7, 12, 0, 0, 0, time.Local)
 	job := PresentExecutionCardJob(domain.ExecutionCardJob{
 		Status: "failed", CreatedUTC: started.Add(-time.Minute), StartedUTC: started,
@@ -52,3 +58,53 @@ func TestDeclarativeProjectLabelsAreStable(t *testing.T) {
 		t.Fatalf("environment label = %q", got)
 	}
 }
+
+func TestPipelineVCSTriggerLabels(t *testing.T) {
+	if got := PipelineVCSTriggerLabel(nil); got != "" {
+		t.Fatalf("manual pipeline trigger label = %q", got)
+	}
+	if got := PipelineVCSTriggerLabel(&domain.PipelineVCSTrigger{}); got != "Polls for new commits at the server default interval · not polled yet" {
+		t.Fatalf("unpolled trigger label = %q", got)
+	}
+	polled := time.Date(2026, 5, 1, 10, 0, 0, 0, time.UTC)
+	got := PipelineVCSTriggerLabel(&domain.PipelineVCSTrigger{
+		PollIntervalSeconds: 30, LastPolledUTC: polled,
+		LastSeenCommit: "0123456789abcdef0123456789abcdef01234567",
+	})
+	want := "Polls for new commits every 30s · last polled " + DeclarativeTimestamp(polled) + " · last seen 0123456789ab"
+	if got != want {
+		t.Fatalf("trigger label = %q, want %q", got, want)
+	}
+	if got := PipelineVCSTriggerError(&domain.PipelineVCSTrigger{LastPollError: " timeout "}); got != "Last poll error: timeout" {
+		t.Fatalf("trigger error = %q", got)
+	}
+}
+
+func TestPipelineScheduleLabels(t *testing.T) {
+	if got := PipelineScheduleLabel(nil); got != "" {
+		t.Fatalf("unscheduled label = %q", got)
+	}
+	next := time.Date(2026, 5, 2, 3, 0, 0, 0, time.UTC)
+	got := PipelineScheduleLabel(&domain.PipelineSchedule{Cron: "0 3 * * *", DryRun: true, CatchUp: "skip", NextFireUTC: next})
+	want := "Runs on schedule 0 3 * * * (UTC) · dry run · next " + DeclarativeTimestamp(next) + " · missed fires: skip"
+	if got != want {
+		t.Fatalf("schedule label = %q, want %q", got, want)
+	}
+	got = PipelineScheduleLabel(&domain.PipelineSchedule{Cron: "0 0 30 2 *", Timezone: "Europe/Amsterdam", SourceRef: "main"})
+	if want := "Runs on schedule 0 0 30 2 * (Europe/Amsterdam) · ref main · no upcoming fire"; got != want {
+		t.Fatalf("schedule label = %q, want %q", got, want)
+	}
+	if got := PipelineScheduleError(&domain.PipelineSchedule{LastError: "queue full"}); got != "Last scheduled run error: queue full" {
+		t.Fatalf("schedule error = %q", got)
+	}
+}
+
+func TestQuarantinedFailuresMarkSucceededJobsWithWarnings(t *testing.T) {
+	summary := &domain.JobTestSummary{Total: 3, Passed: 2, Quarantined: 1}
+	if got := statusWithTestCounts("succeeded", summary); got != "succeeded with warnings (2/3 passed)" {
+		t.Fatalf("succeeded label = %q", got)
+	}
+	if got := statusWithTestCounts("failed", &domain.JobTestSummary{Total: 3, Passed: 1, Failed: 1, Quarantined: 1}); got != "failed (1/3 passed)" {
+		t.Fatalf("failed label = %q", got)
+	}
+}
@@ -97,6 +97,7 @@ type TreeNodeView struct {
 	ActionPath      string
 	DefaultExpanded bool
 	FilterValues    []string
+	Badge           string
 	Children        []TreeNodeView
 }
 
@@ -247,7 +248,7 @@ func presentJobDetails(details domain.JobExecutionDetails) JobDetailsView {
 	statusLabel := humanStatus(details.Status)
 	if details.TestReport != nil {
 		statusLabel = statusWithTestCounts(statusLabel, &domain.JobTestSummary{
-			Total: details.TestReport.Total, Passed: details.TestReport.Passed,
+			Total: details.TestReport.Total, Passed: details.TestReport.Passed, Quarantined: details.TestReport.Quarantined,
 		})
 	}
 	view := JobDetailsView{
@@ -278,12 +279,12 @@ func presentJobDetails(details domain.JobExecutionDetails) JobDetailsView {
 	view.ReleaseSummary, view.HasReleaseSummary = presentReleaseSummary(details)
 	view.Artifacts = presentArtifacts(details.Artifacts)
 	view.TestReport = presentTestReport(details.TestReport, details.Metadata)
-	view.CoverageReport = presentCoverageReport(details.TestReport)
+	view.CoverageReport = presentCoverageReport(details.TestReport, details.Metadata)
 	phaseTotal, stepTotal := 0, 0
 	for _, item := range details.Timeline {
 		if item.Kind == "phase" {
 			phaseTotal++
-		} else {
+		} else if !item.Retried {
 			stepTotal++
 		}
 	}
@@ -297,6 +298,8 @@ func presentJobDetails(details domain.JobExecutionDetails) JobDetailsView {
 			prefix = "Ciwi phase"
 			phaseIndex++
 			categoryIndex, categoryTotal = phaseIndex, phaseTotal
+		} else if item.Retried {
+			categoryIndex = stepIndex + 1
 		} else {
 			stepIndex++
 			categoryIndex = stepIndex
@@ -305,6 +308,9 @@ func presentJobDetails(details domain.JobExecutionDetails) JobDetailsView {
 		if name := strings.TrimSpace(item.Name); name != "" {
 			title += ": " + name
 		}
+		if item.Attempt > 0 {
+			title += fmt.Sprintf(" (attempt %d)", item.Attempt)
+		}
 		reached := item.Reached || (item.Status != "" && item.Status != "pending" && item.Status != "not reached")
 		itemProgress := progressForInput(progressInput{
 			status: item.Status, started: item.StartedUTC, finished: item.FinishedUTC,
@@ -315,6 +321,9 @@ func presentJobDetails(details domain.JobExecutionDetails) JobDetailsView {
 			Status: item.Status, StatusLabel: humanStatus(item.Status), Duration: formatDurationMS(item.DurationMS),
 			ExitCode: formatExitCode(item.ExitCode), Error: item.Error, Progress: itemProgress,
 		})
+		if item.Retried {
+			continue
+		}
 		view.OutputGroups = append(view.OutputGroups, JobOutputGroupView{
 			ID: item.ID, StateKey: "job-output:" + details.ID + ":" + item.ID, Kind: item.Kind, Title: title,
 			CommandSummary: strings.Join(strings.Fields(item.Command), " "), Status: item.Status,
@@ -344,14 +353,43 @@ func presentTestReport(report *domain.JobTestReport, metadata domain.ExecutionMe
 	if report == nil {
 		return ReportDetailsView{EmptyLabel: "No parsed test report"}
 	}
-	return ReportDetailsView{
-		Summary: formatTestCounts(report.Total, report.Passed, report.Failed, report.Skipped), Tone: reportTone(report.Total, report.Failed),
+	view := ReportDetailsView{
+		Summary: withQuarantinedCount(formatTestCounts(report.Total, report.Passed, report.Failed, report.Skipped), report.Quarantined), Tone: reportTone(report.Total, report.Failed),
 		Filter: "all", Filters: []ReportFilterView{{Value: "all", Label: "All"}, {Value: "fail", Label: "Failed"}, {Value: "skip", Label: "Skipped"}, {Value: "pass", Label: "Passed"}},
 		Nodes: presentTestTree(report, metadata),
 	}
+	if report.Shards > 1 {
+		view.Summary += fmt.Sprintf(" · combined from %d shards", report.Shards)
+	}
+	if report.Quarantined > 0 {
+		view.Filters = append(view.Filters, ReportFilterView{Value: "quarantined", Label: "Quarantined"})
+		if report.Failed == 0 {
+			view.Tone = "warning"
+		}
+	}
+	newFailures, knownFlaky := 0, 0
+	for _, suite := range report.Suites {
+		for _, testCase := range suite.Cases {
+			switch testCase.HistoryBadge {
+			case domain.TestHistoryBadgeNewFailure:
+				newFailures++
+			case domain.TestHistoryBadgeKnownFlaky:
+				knownFlaky++
+			}
+		}
+	}
+	if newFailures > 0 {
+		view.Filters = append(view.Filters, ReportFilterView{Value: domain.TestHistoryBadgeNewFailure, Label: "New failures"})
+		view.Summary += fmt.Sprintf(" · %d new failure(s)", newFailures)
+	}
+	if knownFlaky > 0 {
+		view.Filters = append(view.Filters, ReportFilterView{Value: domain.TestHistoryBadgeKnownFlaky, Label: "Known flaky"})
+		view.Summary += fmt.Sprintf(" · %d known flaky", knownFlaky)
+	}
+	return view
 }
 
-func presentCoverageReport(report *domain.JobTestReport) ReportDetailsView {
+func presentCoverageReport(report *domain.JobTestReport, metadata domain.ExecutionMetadata) ReportDetailsView {
 	if report == nil || report.Coverage == nil {
 		return ReportDetailsView{EmptyLabel: "No parsed coverage report"}
 	}
@@ -369,7 +407,18 @@ func presentCoverageReport(report *domain.JobTestReport) ReportDetailsView {
 		Summary: fmt.Sprintf("%.2f%% overall · %d/%d %s · %d file(s) · %s", percent, covered, total, unit, len(coverage.Files), format),
 		Tone:    "accent",
 	}
-	view.Nodes = presentCoverageTree(coverage.Files)
+	if report.Shards > 1 {
+		view.Summary += fmt.Sprintf(" · combined from %d shards", report.Shards)
+	}
+	if gate := coverage.Gate; gate != nil {
+		if gate.Passed {
+			view.Summary += " · coverage gate passed"
+		} else {
+			view.Summary += " · coverage gate failed: " + gate.Message
+			view.Tone = "danger"
+		}
+	}
+	view.Nodes = presentCoverageTree(coverage.Files, metadata)
 	return view
 }
 
@@ -377,6 +426,13 @@ func formatTestCounts(total, passed, failed, skipped int) string {
 	return fmt.Sprintf("%d total · %d passed · %d failed · %d skipped", total, passed, failed, skipped)
 }
 
+func withQuarantinedCount(label string, quarantined int) string {
+	if quarantined <= 0 {
+		return label
+	}
+	return fmt.Sprintf("%s · %d quarantined", label, quarantined)
+}
+
 func reportTone(total, failed int) string {
 	if failed > 0 {
 		return "danger"
@@ -447,6 +503,9 @@ func presentJobProperties(details domain.JobExecutionDetails, view JobDetailsVie
 		{Label: "Duration", Value: view.Duration},
 		{Label: "Exit Code", Value: view.ExitCode},
 	}
+	if cancellation, ok := details.Metadata.Cancellation(); ok {
+		rows = append(rows, JobDetailRowView{Label: "Cancelled", Value: cancellation.Summary(), Tone: "warning"})
+	}
 	return rows
 }
 
@@ -642,7 +701,8 @@ func canRerunJob(details domain.JobExecutionDetails) bool {
 	if !details.StartedUTC.IsZero() {
 		return true
 	}
-	if strings.ToLower(strings.TrimSpace(details.Status)) != "failed" {
+	// Jobs skipped because a dependency failed can be retried once it passes.
+	if strings.ToLower(strings.TrimSpace(details.Status)) != "skipped" {
 		return false
 	}
 	reason := strings.ToLower(strings.TrimSpace(details.Error))
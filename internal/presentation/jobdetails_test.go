@@ -64,7 +64,7 @@ func TestJobDetailsViewExposesEligibleControls(t *testing.T) {
 		t.Fatalf("queued controls = %+v", queued)
 	}
 	blocked := presentJobDetails(domain.JobExecutionDetails{
-		ID: "blocked", Status: "failed", Error: "cancelled: upstream pipeline build failed",
+		ID: "blocked", Status: "skipped", Error: "cancelled: upstream pipeline build failed",
 	})
 	if blocked.CanCancel || !blocked.CanRerun {
 		t.Fatalf("blocked controls = %+v", blocked)
@@ -207,6 +207,55 @@ func TestJobReportTreesPreserveHierarchyFiltersDownloadsAndSourceLinks(t *testin
 	}
 }
 
+func TestJobDetailsCoverageTreeListsUncoveredLineRanges(t *testing.T) {
+	view := presentJobDetails(domain.JobExecutionDetails{
+		ID: "job-1",
+		Metadata: map[string]string{
+			"pipeline_source_repo":         "https://github.com/izzyreal/ciwi.git",
+			"pipeline_source_ref_resolved": "abc123",
+		},
+		TestReport: &domain.JobTestReport{
+			Coverage: &domain.JobCoverageReport{
+				Format: "cobertura", TotalLines: 7, CoveredLines: 3,
+				Files: []domain.JobCoverageFile{{
+					Path: "app/models.py", TotalLines: 7, CoveredLines: 3,
+					Lines: []domain.JobCoverageLine{
+						{Line: 1, Hits: 2}, {Line: 3, Hits: 0}, {Line: 4, Hits: 0}, {Line: 8, Hits: 0},
+						{Line: 9, Hits: 1}, {Line: 12, Hits: 0}, {Line: 2, Hits: 1},
+					},
+				}},
+			},
+		},
+	})
+	file := view.CoverageReport.Nodes[0].Children[0]
+	if file.Label != "models.py" || file.Detail != "42.86% · 3/7 · 2 uncovered range(s)" || len(file.Children) != 2 {
+		t.Fatalf("coverage file = %+v", file)
+	}
+	first, second := file.Children[0], file.Children[1]
+	if first.Label != "Lines 3–8" || first.Detail != "3 line(s) not covered" || first.Tone != "danger" {
+		t.Fatalf("first range = %+v", first)
+	}
+	if !strings.HasSuffix(first.Link, "/blob/abc123/app/models.py#L3") {
+		t.Fatalf("first range link = %q", first.Link)
+	}
+	if second.Label != "Line 12" || second.Key != "coverage:app/models.py:12" {
+		t.Fatalf("second range = %+v", second)
+	}
+}
+
+func TestJobDetailsCoverageSummaryReportsFailedGate(t *testing.T) {
+	view := presentJobDetails(domain.JobExecutionDetails{
+		ID: "job-1",
+		TestReport: &domain.JobTestReport{Coverage: &domain.JobCoverageReport{
+			Format: "lcov", TotalLines: 10, CoveredLines: 5,
+			Gate: &domain.JobCoverageGate{Message: "coverage 50.00% is below the minimum of 80.00%"},
+		}},
+	})
+	if view.CoverageReport.Tone != "danger" || !strings.HasSuffix(view.CoverageReport.Summary, "coverage gate failed: coverage 50.00% is below the minimum of 80.00%") {
+		t.Fatalf("coverage report = %+v", view.CoverageReport)
+	}
+}
+
 func TestJobDetailsViewLimitsClosestSchedulingAgents(t *testing.T) {
 	agents := []requirements.AgentAssessment{{AgentID: "matching", CapabilityMatch: true, AvailabilityIssues: []string{"busy"}}}
 	for _, id := range []string{"a", "b", "c", "d"} {
@@ -6,16 +6,20 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/izzyreal/ciwi/internal/domain"
 )
 
 type ProjectDetailsView struct {
-	Project           domain.Project
-	ProjectLabels     ProjectLabels
-	Pipelines         []ProjectPipelineView
-	StructureFilters  []ProjectStructureFilterView
-	HistoryExecutions []domain.ExecutionCard
+	Project               domain.Project
+	ProjectLabels         ProjectLabels
+	Pipelines             []ProjectPipelineView
+	StructureFilters      []ProjectStructureFilterView
+	HistoryExecutions     []domain.ExecutionCard
+	TestQuarantine        []TestQuarantineEntryView
+	TestQuarantineNotices []string
+	CoverageTrends        []CoverageTrendView
 }
 
 type ProjectLabels struct {
@@ -53,6 +57,8 @@ type ProjectPipelineView struct {
 	Jobs           []ProjectJobView
 	SummaryLabel   string
 	GraphSummary   string
+	TriggerLabel   string
+	TriggerError   string
 }
 
 type ProjectJobView struct {
@@ -90,6 +96,8 @@ type ProjectDetailsQueries struct {
 	executions interface {
 		ListFrontPageExecutionCards(context.Context) ([]domain.ExecutionCard, []domain.ExecutionCard, error)
 	}
+	quarantine testQuarantineSource
+	coverage   coverageTrendSource
 }
 
 func NewProjectDetailsQueries(projects interface {
@@ -157,7 +165,12 @@ func (q *ProjectDetailsQueries) GetProjectDetailsView(ctx context.Context, proje
 			DependsOn: append([]string{}, pipeline.DependsOn...), Dependencies: dependencies,
 			JobsCount: len(jobs), SupportsDryRun: pipelineSupport[pipeline.PipelineID], Jobs: jobs,
 			SummaryLabel: PipelineSummaryLabel(len(jobs), dependencies), GraphSummary: PipelineGraphSummaryLabel(len(jobs), len(pipeline.DependsOn)),
+			TriggerLabel: PipelineVCSTriggerLabel(pipeline.VCSTrigger), TriggerError: PipelineVCSTriggerError(pipeline.VCSTrigger),
 		})
+		if pipeline.Schedule != nil {
+			pipelines[len(pipelines)-1].TriggerLabel = PipelineScheduleLabel(pipeline.Schedule)
+			pipelines[len(pipelines)-1].TriggerError = PipelineScheduleError(pipeline.Schedule)
+		}
 	}
 	history := []domain.ExecutionCard{}
 	if q.executions != nil {
@@ -167,10 +180,25 @@ func (q *ProjectDetailsQueries) GetProjectDetailsView(ctx context.Context, proje
 		}
 		history = projectExecutionCards(allHistory, projectID)
 	}
-	return ProjectDetailsView{
+	view := ProjectDetailsView{
 		Project: details.Project, ProjectLabels: PresentProjectLabels(details.Project), Pipelines: pipelines,
 		StructureFilters: PresentProjectStructureFilters(details.Project, pipelines), HistoryExecutions: history,
-	}, nil
+	}
+	if q.quarantine != nil {
+		entries, err := q.quarantine.List(ctx, projectID)
+		if err != nil {
+			return ProjectDetailsView{}, err
+		}
+		view.TestQuarantine, view.TestQuarantineNotices = presentTestQuarantine(entries, time.Now().UTC())
+	}
+	if q.coverage != nil {
+		trends, err := q.coverage.ListCoverageTrends(ctx, projectID)
+		if err != nil {
+			return ProjectDetailsView{}, err
+		}
+		view.CoverageTrends = presentCoverageTrends(trends)
+	}
+	return view, nil
 }
 
 func PresentProjectLabels(project domain.Project) ProjectLabels {
@@ -202,10 +230,14 @@ func PresentProjectStructureFilters(project domain.Project, pipelines []ProjectP
 		if name == "" {
 			name = strings.TrimSpace(chain.ID)
 		}
+		meta := PipelineChainSequenceLabel(chain.Pipelines)
+		if schedule := PipelineScheduleLabel(chain.Schedule); schedule != "" {
+			meta += " · " + schedule
+		}
 		filters = append(filters, ProjectStructureFilterView{
 			Value: "chain:" + chain.ID, Label: name + " (chain)", PipelineIDs: append([]string(nil), chain.Pipelines...), ShowPipelineStructure: true,
 			Root: ProjectStructureRootView{
-				ID: "chain:" + chain.ID, Label: "Chain: " + name, Meta: PipelineChainSequenceLabel(chain.Pipelines),
+				ID: "chain:" + chain.ID, Label: "Chain: " + name, Meta: meta,
 				Runnable: true, ProjectID: project.ID, ChainID: chain.ID,
 			},
 		})
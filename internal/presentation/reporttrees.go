@@ -18,6 +18,7 @@ type mutableTreeNode struct {
 	total    int
 	covered  int
 	percent  float64
+	lines    []domain.JobCoverageLine
 }
 
 func presentArtifactTree(artifacts []domain.JobArtifact) []TreeNodeView {
@@ -110,45 +111,58 @@ func presentTestTree(report *domain.JobTestReport, metadata domain.ExecutionMeta
 				return cases[i].Name < cases[j].Name
 			})
 			caseNodes := make([]TreeNodeView, 0, len(cases))
-			passed, failed, skipped := 0, 0, 0
+			passed, failed, skipped, quarantined := 0, 0, 0, 0
 			for caseIndex, testCase := range cases {
 				status := strings.ToLower(strings.TrimSpace(testCase.Status))
-				switch status {
-				case "pass":
+				switch {
+				case status == "pass":
 					passed++
-				case "fail":
+				case status == "fail" && testCase.Quarantined:
+					quarantined++
+				case status == "fail":
 					failed++
-				case "skip":
+				case status == "skip":
 					skipped++
 				}
 				detail := status
 				if testCase.DurationSeconds >= 0 {
 					detail = strings.TrimSpace(detail + " · " + fmt.Sprintf("%.3fs", testCase.DurationSeconds))
 				}
+				filterValues := []string{"all", status}
+				tone := testStatusTone(status)
+				if testCase.Quarantined {
+					detail += " · quarantined"
+					filterValues = append(filterValues, "quarantined")
+					tone = "warning"
+				}
+				if badge := testHistoryBadgeLabel(testCase.HistoryBadge); badge != "" {
+					detail += " · " + strings.ToLower(badge)
+					filterValues = append(filterValues, testCase.HistoryBadge)
+				}
 				caseNodes = append(caseNodes, TreeNodeView{
 					Key: fmt.Sprintf("case:%d:%s:%d", suiteIndex, packageName, caseIndex), Label: DeclarativeDefaultLabel(testCase.Name, "(unnamed test)"),
-					Detail: detail, Tone: testStatusTone(status), Link: testCaseSourceURL(testCase, metadata),
-					FilterValues: []string{"all", status},
+					Detail: detail, Tone: tone, Link: testCaseSourceURL(testCase, metadata),
+					FilterValues: filterValues, Badge: testCase.HistoryBadge,
 				})
 			}
 			packageKey := fmt.Sprintf("suite:%d:package:%s", suiteIndex, packageName)
 			children = append(children, TreeNodeView{
 				Key: packageKey, Label: packageName,
-				Detail: fmt.Sprintf("%d total · %d passed · %d failed · %d skipped", len(cases), passed, failed, skipped),
+				Detail: withQuarantinedCount(fmt.Sprintf("%d total · %d passed · %d failed · %d skipped", len(cases), passed, failed, skipped), quarantined),
 				Tone:   reportTone(len(cases), failed), Children: caseNodes,
 			})
 		}
 		suiteKey := fmt.Sprintf("suite:%d:%s", suiteIndex, label)
 		result = append(result, TreeNodeView{
 			Key: suiteKey, Label: label,
-			Detail: strings.TrimSpace(strings.TrimSpace(suite.Format) + " · " + formatTestCounts(suite.Total, suite.Passed, suite.Failed, suite.Skipped)),
+			Detail: withQuarantinedCount(strings.TrimSpace(strings.TrimSpace(suite.Format)+" · "+formatTestCounts(suite.Total, suite.Passed, suite.Failed, suite.Skipped)), suite.Quarantined),
 			Tone:   reportTone(suite.Total, suite.Failed), DefaultExpanded: true, Children: children,
 		})
 	}
 	return result
 }
 
-func presentCoverageTree(files []domain.JobCoverageFile) []TreeNodeView {
+func presentCoverageTree(files []domain.JobCoverageFile, metadata domain.ExecutionMetadata) []TreeNodeView {
 	root := &mutableTreeNode{children: map[string]*mutableTreeNode{}}
 	for _, file := range files {
 		filePath := normalizeTreePath(file.Path)
@@ -169,29 +183,78 @@ func presentCoverageTree(files []domain.JobCoverageFile) []TreeNodeView {
 			child.covered += covered
 			if index == len(parts)-1 {
 				child.percent = file.Percent
+				child.lines = append(child.lines, file.Lines...)
 			}
 			node = child
 		}
 	}
-	return presentCoverageChildren(root, 0)
+	return presentCoverageChildren(root, 0, metadata)
 }
 
-func presentCoverageChildren(node *mutableTreeNode, depth int) []TreeNodeView {
+func presentCoverageChildren(node *mutableTreeNode, depth int, metadata domain.ExecutionMetadata) []TreeNodeView {
 	names := sortedTreeKeys(node.children)
 	result := make([]TreeNodeView, 0, len(names))
 	for _, name := range names {
 		child := node.children[name]
 		detail := fmt.Sprintf("%.2f%% · %d/%d", coveragePercent(child.covered, child.total, child.percent), child.covered, child.total)
-		children := presentCoverageChildren(child, depth+1)
+		children := presentCoverageChildren(child, depth+1, metadata)
 		entry := TreeNodeView{Key: "coverage:" + child.path, Label: child.name, Detail: detail, Children: children}
 		if len(children) > 0 {
 			entry.DefaultExpanded = depth == 0
 		}
+		if ranges := presentUncoveredRanges(child.path, child.lines, metadata); len(ranges) > 0 {
+			entry.Children = append(entry.Children, ranges...)
+			entry.Detail += fmt.Sprintf(" · %d uncovered range(s)", len(ranges))
+		}
 		result = append(result, entry)
 	}
 	return result
 }
 
+// presentUncoveredRanges lists the runs of uncovered lines of a file. Lines
+// that are not instrumented, such as comments and blank lines, do not end a
+// run, so a missed function shows up as one range.
+func presentUncoveredRanges(filePath string, lines []domain.JobCoverageLine, metadata domain.ExecutionMetadata) []TreeNodeView {
+	if len(lines) == 0 {
+		return nil
+	}
+	lines = append([]domain.JobCoverageLine(nil), lines...)
+	sort.SliceStable(lines, func(i, j int) bool { return lines[i].Line < lines[j].Line })
+	result := make([]TreeNodeView, 0)
+	start, end, missed := 0, 0, 0
+	flush := func() {
+		if missed == 0 {
+			return
+		}
+		label := fmt.Sprintf("Line %d", start)
+		if end > start {
+			label = fmt.Sprintf("Lines %d–%d", start, end)
+		}
+		result = append(result, TreeNodeView{
+			Key: fmt.Sprintf("coverage:%s:%d", filePath, start), Label: label,
+			Detail: fmt.Sprintf("%d line(s) not covered", missed), Tone: "danger",
+			Link: testCaseSourceURL(domain.JobTestCase{File: filePath, Line: start}, metadata),
+		})
+		missed = 0
+	}
+	for _, line := range lines {
+		if line.Hits > 0 {
+			flush()
+			continue
+		}
+		if line.Line == end && missed > 0 {
+			continue
+		}
+		if missed == 0 {
+			start = line.Line
+		}
+		end = line.Line
+		missed++
+	}
+	flush()
+	return result
+}
+
 func sortedTreeKeys(values map[string]*mutableTreeNode) []string {
 	keys := make([]string, 0, len(values))
 	for key := range values {
@@ -0,0 +1,146 @@
+package presentation
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/izzyreal/ciwi/internal/application"
+	"github.com/izzyreal/ciwi/internal/domain"
+)
+
+const testHistoryRecentStatuses = 10
+
+type TestHistoryView struct {
+	ProjectID     int64
+	PipelineID    string
+	PipelineJobID string
+	MatrixName    string
+	Title         string
+	Summary       string
+	EmptyLabel    string
+	Tests         []TestHistoryTestView
+}
+
+// TestHistoryTestView describes one test case across runs. Status is the
+// latest result; RecentStatuses lists results newest first.
+type TestHistoryTestView struct {
+	Key                  string
+	Package              string
+	Name                 string
+	Status               string
+	StatusLabel          string
+	Tone                 string
+	Badge                string
+	BadgeLabel           string
+	RunsLabel            string
+	StreakLabel          string
+	FlakinessScore       float64
+	FlakinessLabel       string
+	DurationLabel        string
+	RecentStatuses       []string
+	LatestJobExecutionID string
+}
+
+type TestHistoryQueries struct {
+	history *application.TestHistoryQueries
+}
+
+func NewTestHistoryQueries(history *application.TestHistoryQueries) *TestHistoryQueries {
+	return &TestHistoryQueries{history: history}
+}
+
+func (q *TestHistoryQueries) GetTestHistoryView(ctx context.Context, request application.TestHistoryRequest) (TestHistoryView, error) {
+	history, err := q.history.GetTestHistory(ctx, request)
+	if err != nil {
+		return TestHistoryView{}, err
+	}
+	return presentTestHistory(history), nil
+}
+
+func presentTestHistory(history domain.TestHistory) TestHistoryView {
+	scope := history.Scope
+	view := TestHistoryView{
+		ProjectID: scope.ProjectID, PipelineID: scope.PipelineID, PipelineJobID: scope.PipelineJobID, MatrixName: scope.MatrixName,
+		Title: scope.PipelineID + " / " + scope.PipelineJobID,
+		Tests: make([]TestHistoryTestView, 0, len(history.Cases)),
+	}
+	if scope.MatrixName != "" {
+		view.Title += " · " + scope.MatrixName
+	}
+	if len(history.Cases) == 0 {
+		view.EmptyLabel = "No test results recorded for this job yet"
+	}
+	flaky, failing := 0, 0
+	for _, testCase := range history.Cases {
+		test := presentTestCaseHistory(testCase)
+		if testCase.FlakyCommits > 0 {
+			flaky++
+		}
+		if test.Status == "fail" {
+			failing++
+		}
+		view.Tests = append(view.Tests, test)
+	}
+	sort.SliceStable(view.Tests, func(i, j int) bool {
+		left, right := view.Tests[i], view.Tests[j]
+		if left.FlakinessScore != right.FlakinessScore {
+			return left.FlakinessScore > right.FlakinessScore
+		}
+		if (left.Status == "fail") != (right.Status == "fail") {
+			return left.Status == "fail"
+		}
+		if left.Package != right.Package {
+			return left.Package < right.Package
+		}
+		return left.Name < right.Name
+	})
+	view.Summary = fmt.Sprintf("%d test(s) across %d run(s) · %d flaky · %d failing", len(history.Cases), history.Executions, flaky, failing)
+	return view
+}
+
+func presentTestCaseHistory(history domain.TestCaseHistory) TestHistoryTestView {
+	test := TestHistoryTestView{
+		Key: "test:" + history.Package + ":" + history.Name, Package: history.Package, Name: history.Name,
+		RunsLabel:      fmt.Sprintf("%d run(s) · %d passed · %d failed · %d skipped", len(history.Runs), history.Passed, history.Failed, history.Skipped),
+		FlakinessScore: history.FlakinessScore, FlakinessLabel: "Not flaky",
+	}
+	if len(history.Runs) > 0 {
+		latest := history.Runs[0]
+		test.Status, test.LatestJobExecutionID = latest.Status, latest.JobExecutionID
+		test.Badge = application.TestHistoryBadge(history, latest.JobExecutionID)
+	}
+	test.StatusLabel, test.Tone = humanStatus(test.Status), testStatusTone(test.Status)
+	test.BadgeLabel = testHistoryBadgeLabel(test.Badge)
+	switch history.StreakStatus {
+	case "pass":
+		test.StreakLabel = fmt.Sprintf("Passing for %d run(s)", history.StreakLength)
+	case "fail":
+		test.StreakLabel = fmt.Sprintf("Failing for %d run(s)", history.StreakLength)
+	}
+	if history.FlakyCommits > 0 {
+		test.FlakinessLabel = fmt.Sprintf("Flaky on %d of %d failing commit(s) (%.0f%%)", history.FlakyCommits, history.FailingCommits, 100*history.FlakinessScore)
+	}
+	if history.AverageDurationSeconds > 0 {
+		test.DurationLabel = fmt.Sprintf("%.3fs average · %.3fs recent", history.AverageDurationSeconds, history.RecentDurationSeconds)
+		if history.DurationTrend != 0 {
+			test.DurationLabel += fmt.Sprintf(" (%+.0f%%)", 100*history.DurationTrend)
+		}
+	}
+	for _, run := range history.Runs[:min(testHistoryRecentStatuses, len(history.Runs))] {
+		test.RecentStatuses = append(test.RecentStatuses, run.Status)
+	}
+	return test
+}
+
+func testHistoryBadgeLabel(badge string) string {
+	switch strings.TrimSpace(badge) {
+	case domain.TestHistoryBadgeNewFailure:
+		return "New failure"
+	case domain.TestHistoryBadgeKnownFlaky:
+		return "Known flaky"
+	default:
+		return ""
+	}
+}
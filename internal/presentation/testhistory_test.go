@@ -0,0 +1,63 @@
+package presentation
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/izzyreal/ciwi/internal/domain"
+)
+
+func TestPresentTestHistoryRanksFlakyTestsFirst(t *testing.T) {
+	view := presentTestHistory(domain.TestHistory{
+		Scope:      domain.TestHistoryScope{ProjectID: 1, PipelineID: "build", PipelineJobID: "unit", MatrixName: "linux"},
+		Executions: 4,
+		Cases: []domain.TestCaseHistory{
+			{Package: "p", Name: "TestStable", Passed: 4, StreakStatus: "pass", StreakLength: 4, AverageDurationSeconds: 0.5, RecentDurationSeconds: 0.5,
+				Runs: []domain.TestCaseRun{{JobExecutionID: "j4", Status: "pass"}, {JobExecutionID: "j3", Status: "pass"}, {JobExecutionID: "j2", Status: "pass"}, {JobExecutionID: "j1", Status: "pass"}}},
+			{Package: "p", Name: "TestFlaky", Passed: 3, Failed: 1, StreakStatus: "pass", StreakLength: 3, FailingCommits: 1, FlakyCommits: 1, FlakinessScore: 1,
+				AverageDurationSeconds: 1, RecentDurationSeconds: 1.5, DurationTrend: 0.5,
+				Runs: []domain.TestCaseRun{{JobExecutionID: "j4", Status: "pass"}, {JobExecutionID: "j3", Status: "pass"}, {JobExecutionID: "j2", Status: "fail"}}},
+		},
+	})
+	if view.Title != "build / unit · linux" || view.Summary != "2 test(s) across 4 run(s) · 1 flaky · 0 failing" {
+		t.Fatalf("unexpected header %q / %q", view.Title, view.Summary)
+	}
+	flaky := view.Tests[0]
+	if flaky.Name != "TestFlaky" || flaky.Badge != domain.TestHistoryBadgeKnownFlaky || flaky.BadgeLabel != "Known flaky" {
+		t.Fatalf("expected flaky test first with a badge, got %+v", flaky)
+	}
+	if flaky.FlakinessLabel != "Flaky on 1 of 1 failing commit(s) (100%)" || flaky.DurationLabel != "1.000s average · 1.500s recent (+50%)" {
+		t.Fatalf("unexpected labels %q / %q", flaky.FlakinessLabel, flaky.DurationLabel)
+	}
+	if strings.Join(flaky.RecentStatuses, ",") != "pass,pass,fail" || flaky.StreakLabel != "Passing for 3 run(s)" {
+		t.Fatalf("unexpected recent results %v / %q", flaky.RecentStatuses, flaky.StreakLabel)
+	}
+	if stable := view.Tests[1]; stable.FlakinessLabel != "Not flaky" || stable.Badge != "" || stable.LatestJobExecutionID != "j4" {
+		t.Fatalf("unexpected stable test %+v", stable)
+	}
+}
+
+func TestPresentTestReportBadgesAndFiltersHistory(t *testing.T) {
+	view := presentTestReport(&domain.JobTestReport{Total: 2, Failed: 2, Suites: []domain.JobTestSuite{{
+		Name: "unit", Format: "go-test-json", Total: 2, Failed: 2,
+		Cases: []domain.JobTestCase{
+			{Package: "p", Name: "TestNew", Status: "fail", HistoryBadge: domain.TestHistoryBadgeNewFailure},
+			{Package: "p", Name: "TestFlaky", Status: "fail", HistoryBadge: domain.TestHistoryBadgeKnownFlaky},
+		},
+	}}}, nil)
+	if !strings.HasSuffix(view.Summary, " · 1 new failure(s) · 1 known flaky") {
+		t.Fatalf("unexpected summary %q", view.Summary)
+	}
+	if last := view.Filters[len(view.Filters)-1]; last.Value != domain.TestHistoryBadgeKnownFlaky {
+		t.Fatalf("expected history filters, got %+v", view.Filters)
+	}
+	cases := view.Nodes[0].Children[0].Children
+	for _, node := range cases {
+		if node.Badge == "" || !strings.Contains(strings.Join(node.FilterValues, ","), node.Badge) {
+			t.Fatalf("expected badge to be filterable, got %+v", node)
+		}
+	}
+	if !strings.HasSuffix(cases[0].Detail, "known flaky") && !strings.HasSuffix(cases[0].Detail, "new failure") {
+		t.Fatalf("expected badge in detail, got %q", cases[0].Detail)
+	}
+}
@@ -0,0 +1,63 @@
+package presentation
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/izzyreal/ciwi/internal/domain"
+)
+
+type TestQuarantineEntryView struct {
+	ID           int64
+	ProjectID    int64
+	Package      string
+	Name         string
+	TestLabel    string
+	Owner        string
+	Reason       string
+	ExpiresLabel string
+	Expired      bool
+	Tone         string
+}
+
+type testQuarantineSource interface {
+	List(context.Context, int64) ([]domain.TestQuarantineEntry, error)
+}
+
+// WithTestQuarantine adds the project's quarantined tests, and notices for
+// the expired ones, to project details.
+func (q *ProjectDetailsQueries) WithTestQuarantine(quarantine testQuarantineSource) *ProjectDetailsQueries {
+	q.quarantine = quarantine
+	return q
+}
+
+func presentTestQuarantine(entries []domain.TestQuarantineEntry, now time.Time) ([]TestQuarantineEntryView, []string) {
+	views := make([]TestQuarantineEntryView, 0, len(entries))
+	var notices []string
+	for _, entry := range entries {
+		view := PresentTestQuarantineEntry(entry, now)
+		if view.Expired {
+			notices = append(notices, fmt.Sprintf("Quarantine of %s (owner %s) expired on %s; its failures fail jobs again. Renew or remove it.",
+				view.TestLabel, entry.Owner, entry.ExpiresUTC.UTC().Format(time.DateOnly)))
+		}
+		views = append(views, view)
+	}
+	return views, notices
+}
+
+func PresentTestQuarantineEntry(entry domain.TestQuarantineEntry, now time.Time) TestQuarantineEntryView {
+	view := TestQuarantineEntryView{
+		ID: entry.ID, ProjectID: entry.ProjectID, Package: entry.Package, Name: entry.Name,
+		TestLabel: entry.Name, Owner: entry.Owner, Reason: entry.Reason,
+		ExpiresLabel: "Expires " + entry.ExpiresUTC.UTC().Format(time.DateOnly), Tone: "muted",
+	}
+	if entry.Package != "" {
+		view.TestLabel = entry.Package + " · " + entry.Name
+	}
+	if entry.Expired(now) {
+		view.Expired, view.Tone = true, "warning"
+		view.ExpiresLabel = "Expired " + entry.ExpiresUTC.UTC().Format(time.DateOnly)
+	}
+	return view
+}
@@ -0,0 +1,26 @@
+package presentation
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/izzyreal/ciwi/internal/domain"
+)
+
+func TestPresentTestQuarantineNoticesExpiredEntries(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	views, notices := presentTestQuarantine([]domain.TestQuarantineEntry{
+		{ID: 1, Package: "p", Name: "TestOld", Owner: "alice", ExpiresUTC: now.Add(-24 * time.Hour)},
+		{ID: 2, Name: "TestFlaky", Owner: "bob", ExpiresUTC: now.Add(24 * time.Hour)},
+	}, now)
+	if len(views) != 2 || !views[0].Expired || views[0].Tone != "warning" || views[0].TestLabel != "p · TestOld" {
+		t.Fatalf("unexpected expired view %+v", views[0])
+	}
+	if views[1].Expired || views[1].ExpiresLabel != "Expires 2026-03-02" || views[1].TestLabel != "TestFlaky" {
+		t.Fatalf("unexpected active view %+v", views[1])
+	}
+	if len(notices) != 1 || !strings.Contains(notices[0], "p · TestOld (owner alice) expired on 2026-02-28") {
+		t.Fatalf("unexpected notices %v", notices)
+	}
+}
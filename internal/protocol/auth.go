@@ -0,0 +1,100 @@
+package protocol
+
+type User struct {
+	ID           int64  `json:"id"`
+	Username     string `json:"username"`
+	Role         string `json:"role"`
+	CreatedUTC   string `json:"created_utc,omitempty"`
+	LastLoginUTC string `json:"last_login_utc,omitempty"`
+}
+
+type APIToken struct {
+	ID          int64  `json:"id"`
+	Name        string `json:"name"`
+	Prefix      string `json:"prefix"`
+	CreatedUTC  string `json:"created_utc,omitempty"`
+	LastUsedUTC string `json:"last_used_utc,omitempty"`
+	ExpiresUTC  string `json:"expires_utc,omitempty"`
+}
+
+type LoginRequest struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+type AuthSessionResponse struct {
+	Enabled       bool   `json:"enabled"`
+	Authenticated bool   `json:"authenticated"`
+	User          *User  `json:"user,omitempty"`
+	ExpiresUTC    string `json:"expires_utc,omitempty"`
+}
+
+type CreateUserRequest struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+	Role     string `json:"role,omitempty"`
+}
+
+type UpdateUserRequest struct {
+	Role            string `json:"role,omitempty"`
+	Password        string `json:"password,omitempty"`
+	CurrentPassword string `json:"current_password,omitempty"`
+}
+
+type UsersResponse struct {
+	Users []User `json:"users"`
+}
+
+type CreateAPITokenRequest struct {
+	Name       string `json:"name"`
+	ExpiresUTC string `json:"expires_utc,omitempty"`
+}
+
+type CreateAPITokenResponse struct {
+	Token  APIToken `json:"token"`
+	Secret string   `json:"secret"`
+}
+
+type APITokensResponse struct {
+	Tokens []APIToken `json:"tokens"`
+}
+
+// AgentIDHeader names the agent an agent request claims to come from. Agents
+// send it with their credential so routes without an agent ID in the body can
+// be authenticated too.
+const AgentIDHeader = "X-CIWI-Agent-ID"
+
+type AgentEnrollRequest struct {
+	AgentID         string `json:"agent_id"`
+	EnrollmentToken string `json:"enrollment_token"`
+}
+
+type AgentEnrollResponse struct {
+	AgentID    string `json:"agent_id"`
+	Credential string `json:"credential"`
+}
+
+type AgentEnrollmentToken struct {
+	ID            int64  `json:"id"`
+	Description   string `json:"description,omitempty"`
+	Prefix        string `json:"prefix"`
+	CreatedBy     string `json:"created_by,omitempty"`
+	CreatedUTC    string `json:"created_utc,omitempty"`
+	ExpiresUTC    string `json:"expires_utc,omitempty"`
+	UsedUTC       string `json:"used_utc,omitempty"`
+	UsedByAgentID string `json:"used_by_agent_id,omitempty"`
+}
+
+type CreateAgentEnrollmentTokenRequest struct {
+	Description string `json:"description,omitempty"`
+	ExpiresUTC  string `json:"expires_utc,omitempty"`
+}
+
+type CreateAgentEnrollmentTokenResponse struct {
+	Token  AgentEnrollmentToken `json:"token"`
+	Secret string               `json:"secret"`
+}
+
+type AgentEnrollmentTokensResponse struct {
+	Tokens []AgentEnrollmentToken `json:"tokens"`
+}
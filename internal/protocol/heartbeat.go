@@ -1,10 +1,15 @@
 package protocol
 
-import "time"
+import (
+	"time"
+
+	"github.com/izzyreal/ciwi/internal/domain"
+)
 
 const (
 	AgentHeartbeatInterval     = 10 * time.Second
 	AgentHeartbeatFadeDuration = AgentHeartbeatInterval * 16 / 25
+	MaxAgentJobSlots           = domain.MaxAgentJobSlots
 )
 
 type HeartbeatRequest struct {
@@ -14,10 +19,15 @@ type HeartbeatRequest struct {
 	Arch             string            `json:"arch"`
 	Version          string            `json:"version,omitempty"`
 	Capabilities     map[string]string `json:"capabilities"`
+	Labels           map[string]string `json:"labels,omitempty"`
 	UpdateFailure    string            `json:"update_failure,omitempty"`
 	UpdateInProgress bool              `json:"update_in_progress,omitempty"`
 	RestartStatus    string            `json:"restart_status,omitempty"`
+	Slots            int               `json:"slots,omitempty"`
 	TimestampUTC     time.Time         `json:"timestamp_utc"`
+	// RunningJobExecutionIDs lists the jobs the agent is executing, so the
+	// server can tell it which ones were cancelled in the meantime.
+	RunningJobExecutionIDs []string `json:"running_job_execution_ids,omitempty"`
 }
 
 type HeartbeatResponse struct {
@@ -31,4 +41,7 @@ type HeartbeatResponse struct {
 	RestartRequested         bool   `json:"restart_requested,omitempty"`
 	WipeCacheRequested       bool   `json:"wipe_cache_requested,omitempty"`
 	FlushJobHistoryRequested bool   `json:"flush_job_history_requested,omitempty"`
+	Slots                    int    `json:"slots,omitempty"`
+	// CancelJobExecutionIDs names reported running jobs the agent must stop.
+	CancelJobExecutionIDs []string `json:"cancel_job_execution_ids,omitempty"`
 }
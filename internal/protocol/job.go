@@ -28,6 +28,19 @@ type AgentInfo struct {
 type SourceSpec struct {
 	Repo string `json:"repo"`
 	Ref  string `json:"ref,omitempty"`
+	SourceCheckoutOptions
+}
+
+// SourceCheckoutOptions carry a pipeline's vcs_source checkout options to the
+// agent. The zero value is a shallow clone of the one commit.
+type SourceCheckoutOptions struct {
+	// Depth is the number of commits to fetch; nil means one and 0 the full
+	// history.
+	Depth      *int     `json:"depth,omitempty"`
+	Submodules string   `json:"submodules,omitempty"`
+	LFS        bool     `json:"lfs,omitempty"`
+	Sparse     []string `json:"sparse,omitempty"`
+	FetchTags  bool     `json:"fetch_tags,omitempty"`
 }
 
 type JobCacheSpec struct {
@@ -35,6 +48,17 @@ type JobCacheSpec struct {
 	Env string `json:"env,omitempty"`
 }
 
+// JobServiceSpec is a service container the agent starts next to a job, such
+// as a database its integration tests talk to.
+type JobServiceSpec struct {
+	Name                 string            `json:"name"`
+	Image                string            `json:"image"`
+	Env                  map[string]string `json:"env,omitempty"`
+	Ports                []int             `json:"ports,omitempty"`
+	HealthCheck          string            `json:"health_check,omitempty"`
+	HealthTimeoutSeconds int               `json:"health_timeout_seconds,omitempty"`
+}
+
 type CreateJobExecutionRequest struct {
 	Script                   string                   `json:"script"`
 	Env                      map[string]string        `json:"env,omitempty"`
@@ -43,6 +67,7 @@ type CreateJobExecutionRequest struct {
 	ArtifactGlobs            []string                 `json:"artifact_globs,omitempty"`
 	DependencyArtifactJobIDs []string                 `json:"dependency_artifact_job_ids,omitempty"`
 	Caches                   []JobCacheSpec           `json:"caches,omitempty"`
+	Services                 []JobServiceSpec         `json:"services,omitempty"`
 	Source                   *SourceSpec              `json:"source,omitempty"`
 	Metadata                 domain.ExecutionMetadata `json:"metadata,omitempty"`
 	StepPlan                 []JobStepPlanItem        `json:"step_plan,omitempty"`
@@ -57,6 +82,7 @@ type JobExecution struct {
 	ArtifactGlobs            []string                    `json:"artifact_globs,omitempty"`
 	DependencyArtifactJobIDs []string                    `json:"dependency_artifact_job_ids,omitempty"`
 	Caches                   []JobCacheSpec              `json:"caches,omitempty"`
+	Services                 []JobServiceSpec            `json:"services,omitempty"`
 	Source                   *SourceSpec                 `json:"source,omitempty"`
 	Metadata                 domain.ExecutionMetadata    `json:"metadata,omitempty"`
 	StepPlan                 []JobStepPlanItem           `json:"step_plan,omitempty"`
@@ -151,11 +177,12 @@ type PipelineSummary struct {
 }
 
 type PipelineChainSummary struct {
-	ID                string   `json:"id"`
-	Name              string   `json:"name"`
-	Pipelines         []string `json:"pipelines"`
-	SupportsDryRun    bool     `json:"supports_dry_run,omitempty"`
-	VersionPipelineID int64    `json:"version_pipeline_id,omitempty"`
+	ID                string            `json:"id"`
+	Name              string            `json:"name"`
+	Pipelines         []string          `json:"pipelines"`
+	SupportsDryRun    bool              `json:"supports_dry_run,omitempty"`
+	VersionPipelineID int64             `json:"version_pipeline_id,omitempty"`
+	Schedule          *PipelineSchedule `json:"schedule,omitempty"`
 }
 
 type MatrixInclude struct {
@@ -175,6 +202,8 @@ type PipelineJobDetail struct {
 	Caches          []JobCacheSpec              `json:"caches,omitempty"`
 	Steps           []PipelineStep              `json:"steps,omitempty"`
 	MatrixIncludes  []MatrixInclude             `json:"matrix_includes,omitempty"`
+	Shards          int                         `json:"shards,omitempty"`
+	Priority        int                         `json:"priority,omitempty"`
 }
 
 type PipelineJobArtifactSource struct {
@@ -193,6 +222,7 @@ type PipelineStep struct {
 	TestReport     string            `json:"test_report,omitempty"`
 	CoverageFormat string            `json:"coverage_format,omitempty"`
 	CoverageReport string            `json:"coverage_report,omitempty"`
+	CoverageGate   *CoverageGate     `json:"coverage_gate,omitempty"`
 	SkipDryRun     bool              `json:"skip_dry_run,omitempty"`
 	Env            map[string]string `json:"env,omitempty"`
 }
@@ -205,9 +235,35 @@ type PipelineDetail struct {
 	SourceRepo string              `json:"source_repo,omitempty"`
 	SourceRef  string              `json:"source_ref,omitempty"`
 	Versioning PipelineVersioning  `json:"versioning,omitempty"`
+	VCSTrigger *PipelineVCSTrigger `json:"vcs_trigger,omitempty"`
+	Schedule   *PipelineSchedule   `json:"schedule,omitempty"`
 	Jobs       []PipelineJobDetail `json:"jobs,omitempty"`
 }
 
+// PipelineSchedule is the cron schedule of a pipeline or pipeline chain
+// together with the scheduler state recorded for it.
+type PipelineSchedule struct {
+	Cron        string    `json:"cron"`
+	Timezone    string    `json:"timezone,omitempty"`
+	SourceRef   string    `json:"source_ref,omitempty"`
+	DryRun      bool      `json:"dry_run,omitempty"`
+	CatchUp     string    `json:"catch_up,omitempty"`
+	NextFireUTC time.Time `json:"next_fire_utc,omitempty"`
+	LastFireUTC time.Time `json:"last_fire_utc,omitempty"`
+	LastError   string    `json:"last_error,omitempty"`
+}
+
+// PipelineVCSTrigger is the persisted poll state of a pipeline with
+// trigger: vcs.
+type PipelineVCSTrigger struct {
+	PollIntervalSeconds int       `json:"poll_interval_seconds,omitempty"`
+	LastSeenCommit      string    `json:"last_seen_commit,omitempty"`
+	LastPolledUTC       time.Time `json:"last_polled_utc,omitempty"`
+	LastPollError       string    `json:"last_poll_error,omitempty"`
+	LastTriggeredCommit string    `json:"last_triggered_commit,omitempty"`
+	LastTriggeredUTC    time.Time `json:"last_triggered_utc,omitempty"`
+}
+
 type PipelineVersioning struct {
 	File      string `json:"file,omitempty"`
 	TagPrefix string `json:"tag_prefix,omitempty"`
@@ -281,6 +337,8 @@ type RunPipelineSelectionRequest struct {
 	SourceRef     string `json:"source_ref,omitempty"`
 	AgentID       string `json:"agent_id,omitempty"`
 	ExecutionMode string `json:"execution_mode,omitempty"`
+	// Priority overrides the configured priority of every job in the run.
+	Priority *int `json:"priority,omitempty"`
 }
 
 type JobExecutionArtifact struct {
@@ -339,6 +397,14 @@ const (
 	JobExecutionPhaseTests        = "system.tests"
 )
 
+// JobExecutionServicePhasePrefix prefixes the phase of each service
+// container; its phase output is the service's log.
+const JobExecutionServicePhasePrefix = "service:"
+
+func JobExecutionServicePhaseID(name string) string {
+	return JobExecutionServicePhasePrefix + name
+}
+
 type JobStepPlanItem struct {
 	Index           int                 `json:"index"`
 	Total           int                 `json:"total,omitempty"`
@@ -354,7 +420,22 @@ type JobStepPlanItem struct {
 	TestReport      string              `json:"test_report,omitempty"`
 	CoverageFormat  string              `json:"coverage_format,omitempty"`
 	CoverageReport  string              `json:"coverage_report,omitempty"`
-}
+	CoverageGate    *CoverageGate       `json:"coverage_gate,omitempty"`
+	RunWhen         string              `json:"run_when,omitempty"`
+	SkipReason      string              `json:"skip_reason,omitempty"`
+	// RetryMaxAttempts and RetryBackoffSeconds re-run a step in place when it
+	// exits non-zero. Attempt is only set on events of a retried step.
+	RetryMaxAttempts    int `json:"retry_max_attempts,omitempty"`
+	RetryBackoffSeconds int `json:"retry_backoff_seconds,omitempty"`
+	Attempt             int `json:"attempt,omitempty"`
+}
+
+// Step run conditions resolved from `if:` when the job was queued. A step
+// with an empty RunWhen runs only while every earlier step succeeded.
+const (
+	JobStepRunWhenFailure = "failure"
+	JobStepRunWhenAlways  = "always"
+)
 
 type JobExecutionEvent struct {
 	ID           int64              `json:"id,omitempty"`
@@ -395,25 +476,37 @@ type TestCase struct {
 	Status          string  `json:"status"`
 	DurationSeconds float64 `json:"duration_seconds,omitempty"`
 	Output          string  `json:"output,omitempty"`
+	// Quarantined is set by the server on failures of quarantined tests.
+	Quarantined bool `json:"quarantined,omitempty"`
 }
 
+// TestSuiteReport counts quarantined failures separately: Failed only
+// counts the failures that fail the job.
 type TestSuiteReport struct {
-	Name    string     `json:"name,omitempty"`
-	Format  string     `json:"format"`
-	Total   int        `json:"total"`
-	Passed  int        `json:"passed"`
-	Failed  int        `json:"failed"`
-	Skipped int        `json:"skipped"`
-	Cases   []TestCase `json:"cases,omitempty"`
+	Name        string     `json:"name,omitempty"`
+	Format      string     `json:"format"`
+	Total       int        `json:"total"`
+	Passed      int        `json:"passed"`
+	Failed      int        `json:"failed"`
+	Skipped     int        `json:"skipped"`
+	Quarantined int        `json:"quarantined,omitempty"`
+	Cases       []TestCase `json:"cases,omitempty"`
+}
+
+// CoverageLine is the hit count of one instrumented source line.
+type CoverageLine struct {
+	Line int `json:"line"`
+	Hits int `json:"hits"`
 }
 
 type CoverageFileReport struct {
-	Path              string  `json:"path,omitempty"`
-	TotalLines        int     `json:"total_lines"`
-	CoveredLines      int     `json:"covered_lines"`
-	TotalStatements   int     `json:"total_statements"`
-	CoveredStatements int     `json:"covered_statements"`
-	Percent           float64 `json:"percent"`
+	Path              string         `json:"path,omitempty"`
+	TotalLines        int            `json:"total_lines"`
+	CoveredLines      int            `json:"covered_lines"`
+	TotalStatements   int            `json:"total_statements"`
+	CoveredStatements int            `json:"covered_statements"`
+	Percent           float64        `json:"percent"`
+	Lines             []CoverageLine `json:"lines,omitempty"`
 }
 
 type CoverageReport struct {
@@ -424,22 +517,44 @@ type CoverageReport struct {
 	CoveredStatements int                  `json:"covered_statements"`
 	Percent           float64              `json:"percent"`
 	Files             []CoverageFileReport `json:"files,omitempty"`
+	// Gate is set by the server when the job's test step has a coverage gate.
+	Gate *CoverageGateResult `json:"gate,omitempty"`
+}
+
+// CoverageGate bounds the coverage of a job: MinPercent is an absolute
+// floor and MaxDrop the largest drop, in percentage points, from the last
+// successful run of the same job on the same branch.
+type CoverageGate struct {
+	MinPercent *float64 `json:"min_percent,omitempty"`
+	MaxDrop    *float64 `json:"max_drop,omitempty"`
+}
+
+type CoverageGateResult struct {
+	Passed     bool     `json:"passed"`
+	MinPercent *float64 `json:"min_percent,omitempty"`
+	MaxDrop    *float64 `json:"max_drop,omitempty"`
+	// BaselineJobID is empty when no earlier successful run had coverage.
+	BaselineJobID   string  `json:"baseline_job_id,omitempty"`
+	BaselinePercent float64 `json:"baseline_percent,omitempty"`
+	Message         string  `json:"message,omitempty"`
 }
 
 type JobExecutionTestReport struct {
-	Total    int               `json:"total"`
-	Passed   int               `json:"passed"`
-	Failed   int               `json:"failed"`
-	Skipped  int               `json:"skipped"`
-	Suites   []TestSuiteReport `json:"suites,omitempty"`
-	Coverage *CoverageReport   `json:"coverage,omitempty"`
+	Total       int               `json:"total"`
+	Passed      int               `json:"passed"`
+	Failed      int               `json:"failed"`
+	Skipped     int               `json:"skipped"`
+	Quarantined int               `json:"quarantined,omitempty"`
+	Suites      []TestSuiteReport `json:"suites,omitempty"`
+	Coverage    *CoverageReport   `json:"coverage,omitempty"`
 }
 
 type JobExecutionTestSummary struct {
-	Total   int `json:"total"`
-	Passed  int `json:"passed"`
-	Failed  int `json:"failed"`
-	Skipped int `json:"skipped"`
+	Total       int `json:"total"`
+	Passed      int `json:"passed"`
+	Failed      int `json:"failed"`
+	Skipped     int `json:"skipped"`
+	Quarantined int `json:"quarantined,omitempty"`
 }
 
 type UploadTestReportRequest struct {
@@ -0,0 +1,23 @@
+package protocol
+
+import (
+	"time"
+
+	"github.com/izzyreal/ciwi/internal/domain"
+)
+
+// AgentAffinityWait returns the agent job prefers and until when other agents
+// leave the job to it. The wait starts when another agent first could have
+// taken the job, so a job that sat behind its needs still gets all of it;
+// started is false until then.
+func AgentAffinityWait(job JobExecution) (preferred string, until time.Time, started bool) {
+	preferred, wait, ok := job.Metadata.AgentAffinity()
+	if !ok {
+		return "", time.Time{}, false
+	}
+	since, err := time.Parse(time.RFC3339Nano, job.Metadata.Value(domain.ExecutionMetadataAffinityWaitingSinceUTC))
+	if err != nil {
+		return preferred, time.Time{}, false
+	}
+	return preferred, since.Add(wait), true
+}
@@ -0,0 +1,76 @@
+package protocol
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/izzyreal/ciwi/internal/domain"
+)
+
+// JobExecutionConcurrencyHolder identifies what holds a concurrency group: the
+// pipeline run for pipeline-level groups, otherwise the execution itself.
+func JobExecutionConcurrencyHolder(job JobExecution) string {
+	if holder := job.Metadata.Value(domain.ExecutionMetadataConcurrencyHolder); holder != "" {
+		return holder
+	}
+	return strings.TrimSpace(job.ID)
+}
+
+// ConcurrencyGroupHolders returns one execution per holder of job's
+// concurrency group, other than job's own holder, oldest first. A holder holds
+// the group from its first lease until all of its executions have finished.
+// group lists the executions of the group's unfinished holders.
+func ConcurrencyGroupHolders(job JobExecution, group []JobExecution) []JobExecution {
+	name, _, ok := job.Metadata.ConcurrencyGroup()
+	if !ok {
+		return nil
+	}
+	own := JobExecutionConcurrencyHolder(job)
+	active := map[string]bool{}
+	started := map[string]JobExecution{}
+	for _, other := range group {
+		if otherName, _, ok := other.Metadata.ConcurrencyGroup(); !ok || otherName != name {
+			continue
+		}
+		holder := JobExecutionConcurrencyHolder(other)
+		if holder == own {
+			continue
+		}
+		if IsActiveJobExecutionStatus(other.Status) {
+			active[holder] = true
+		}
+		if IsQueuedJobExecutionStatus(other.Status) {
+			continue
+		}
+		if current, ok := started[holder]; !ok || (IsActiveJobExecutionStatus(other.Status) && !IsActiveJobExecutionStatus(current.Status)) {
+			started[holder] = other
+		}
+	}
+	holders := make([]JobExecution, 0, len(started))
+	for holder, execution := range started {
+		if active[holder] {
+			holders = append(holders, execution)
+		}
+	}
+	sort.Slice(holders, func(i, j int) bool {
+		if !holders[i].CreatedUTC.Equal(holders[j].CreatedUTC) {
+			return holders[i].CreatedUTC.Before(holders[j].CreatedUTC)
+		}
+		return holders[i].ID < holders[j].ID
+	})
+	return holders
+}
+
+// ConcurrencyGroupBlockers returns the holders that keep job from being
+// leased, or nil when its concurrency group has room for it.
+func ConcurrencyGroupBlockers(job JobExecution, group []JobExecution) []JobExecution {
+	_, maxRunning, ok := job.Metadata.ConcurrencyGroup()
+	if !ok {
+		return nil
+	}
+	holders := ConcurrencyGroupHolders(job, group)
+	if len(holders) < maxRunning {
+		return nil
+	}
+	return holders
+}
@@ -0,0 +1,41 @@
+package protocol
+
+import (
+	"testing"
+	"time"
+
+	"github.com/izzyreal/ciwi/internal/domain"
+)
+
+func TestConcurrencyGroupBlockersCountStartedRuns(t *testing.T) {
+	base := time.Now().UTC()
+	member := func(id, run, status string, offset int) JobExecution {
+		return JobExecution{ID: id, Status: status, CreatedUTC: base.Add(time.Duration(offset) * time.Second), Metadata: domain.ExecutionMetadata{
+			domain.ExecutionMetadataConcurrencyGroup:      "deploy",
+			domain.ExecutionMetadataConcurrencyMaxRunning: "1",
+			domain.ExecutionMetadataConcurrencyHolder:     run,
+		}}
+	}
+	// run-1 finished its first job and still waits on the second, so it keeps
+	// holding the group between jobs.
+	group := []JobExecution{
+		member("job-1a", "run-1", JobExecutionStatusSucceeded, 0),
+		member("job-1b", "run-1", JobExecutionStatusQueued, 1),
+		member("job-2a", "run-2", JobExecutionStatusQueued, 2),
+	}
+	blockers := ConcurrencyGroupBlockers(group[2], group)
+	if len(blockers) != 1 || blockers[0].ID != "job-1a" {
+		t.Fatalf("expected run-1 to hold the group, got %+v", blockers)
+	}
+	if blockers := ConcurrencyGroupBlockers(group[1], group); len(blockers) != 0 {
+		t.Fatalf("expected a run never to block itself, got %+v", blockers)
+	}
+
+	group[2].Metadata[domain.ExecutionMetadataConcurrencyMaxRunning] = "2"
+	if blockers := ConcurrencyGroupBlockers(group[2], group); len(blockers) != 0 {
+		t.Fatalf("expected room for a second run, got %+v", blockers)
+	}
+	if blockers := ConcurrencyGroupBlockers(JobExecution{ID: "free"}, group); blockers != nil {
+		t.Fatalf("expected executions without a group to run freely, got %+v", blockers)
+	}
+}
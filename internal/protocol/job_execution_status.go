@@ -12,6 +12,8 @@ const (
 	JobExecutionStatusRunning   = "running"
 	JobExecutionStatusSucceeded = "succeeded"
 	JobExecutionStatusFailed    = "failed"
+	JobExecutionStatusCancelled = "cancelled"
+	JobExecutionStatusSkipped   = "skipped"
 
 	JobSchedulingBlockedMetadataKey       = domain.ExecutionMetadataSchedulingBlocked
 	JobSchedulingBlockedReasonMetadataKey = domain.ExecutionMetadataSchedulingBlockedReason
@@ -51,9 +53,15 @@ func IsActiveJobExecutionStatus(status string) bool {
 	}
 }
 
+// TerminalJobExecutionStatuses lists every status a job execution cannot
+// leave once reached.
+func TerminalJobExecutionStatuses() []string {
+	return []string{JobExecutionStatusSucceeded, JobExecutionStatusFailed, JobExecutionStatusCancelled, JobExecutionStatusSkipped}
+}
+
 func IsTerminalJobExecutionStatus(status string) bool {
 	switch NormalizeJobExecutionStatus(status) {
-	case JobExecutionStatusSucceeded, JobExecutionStatusFailed:
+	case JobExecutionStatusSucceeded, JobExecutionStatusFailed, JobExecutionStatusCancelled, JobExecutionStatusSkipped:
 		return true
 	default:
 		return false
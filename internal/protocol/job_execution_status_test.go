@@ -19,8 +19,13 @@ func TestJobExecutionStatusPredicates(t *testing.T) {
 	if !IsActiveJobExecutionStatus(JobExecutionStatusRunning) || !IsActiveJobExecutionStatus(JobExecutionStatusQueued) || !IsActiveJobExecutionStatus(JobExecutionStatusLeased) {
 		t.Fatal("active status predicate should include queued, leased and running")
 	}
-	if !IsTerminalJobExecutionStatus(JobExecutionStatusSucceeded) || !IsTerminalJobExecutionStatus(JobExecutionStatusFailed) {
-		t.Fatal("terminal status predicate should include succeeded and failed")
+	for _, status := range TerminalJobExecutionStatuses() {
+		if !IsTerminalJobExecutionStatus(status) || IsActiveJobExecutionStatus(status) {
+			t.Fatalf("%s should be terminal and inactive", status)
+		}
+	}
+	if !IsTerminalJobExecutionStatus(JobExecutionStatusCancelled) || !IsTerminalJobExecutionStatus(JobExecutionStatusSkipped) {
+		t.Fatal("terminal status predicate should include cancelled and skipped")
 	}
 	if IsTerminalJobExecutionStatus(JobExecutionStatusRunning) {
 		t.Fatal("terminal status predicate should exclude running")
@@ -34,4 +39,7 @@ func TestIsValidJobExecutionUpdateStatus(t *testing.T) {
 	if IsValidJobExecutionUpdateStatus(JobExecutionStatusQueued) || IsValidJobExecutionUpdateStatus(JobExecutionStatusLeased) {
 		t.Fatal("valid update status predicate should reject queued/leased")
 	}
+	if IsValidJobExecutionUpdateStatus(JobExecutionStatusCancelled) || IsValidJobExecutionUpdateStatus(JobExecutionStatusSkipped) {
+		t.Fatal("agents must not report server-decided cancelled/skipped statuses")
+	}
 }
@@ -1,6 +1,9 @@
 package protocol
 
-import "time"
+import (
+	"fmt"
+	"time"
+)
 
 type JobExecutionGraphContext struct {
 	Scope                string                      `json:"scope"`
@@ -27,6 +30,17 @@ type JobExecutionGraphJob struct {
 	Needs         []string                     `json:"needs,omitempty"`
 	Status        string                       `json:"status"`
 	Executions    []JobExecutionGraphExecution `json:"executions"`
+	TestSummary   *JobExecutionTestSummary     `json:"test_summary,omitempty"`
+}
+
+// SummaryLabel describes the job's status, execution count and, once its
+// latest executions reported tests, their combined test counts.
+func (j JobExecutionGraphJob) SummaryLabel() string {
+	label := fmt.Sprintf("%s · %d execution(s)", j.Status, len(j.Executions))
+	if j.TestSummary != nil && j.TestSummary.Total > 0 {
+		label += fmt.Sprintf(" · %d/%d tests passed", j.TestSummary.Passed, j.TestSummary.Total)
+	}
+	return label
 }
 
 type JobExecutionGraphExecution struct {
@@ -34,6 +48,7 @@ type JobExecutionGraphExecution struct {
 	Status        string    `json:"status"`
 	MatrixIndex   string    `json:"matrix_index,omitempty"`
 	MatrixName    string    `json:"matrix_name,omitempty"`
+	ShardLabel    string    `json:"shard_label,omitempty"`
 	AttemptRootID string    `json:"attempt_root_id"`
 	RerunOfJobID  string    `json:"rerun_of_job_id,omitempty"`
 	LatestAttempt bool      `json:"latest_attempt"`
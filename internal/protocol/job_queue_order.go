@@ -0,0 +1,34 @@
+package protocol
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/izzyreal/ciwi/internal/domain"
+)
+
+// JobExecutionProjectKey groups executions for fair-share ordering. Ad-hoc
+// executions without a project share the empty key.
+func JobExecutionProjectKey(job JobExecution) string {
+	return job.Metadata.Value(domain.ExecutionMetadataProjectID)
+}
+
+// SortQueuedJobExecutions orders queued executions the way agents lease them:
+// higher priority first, then executions of projects with fewer leased or
+// running executions, then oldest first. activeByProject is keyed by
+// JobExecutionProjectKey.
+func SortQueuedJobExecutions(jobs []JobExecution, activeByProject map[string]int) {
+	sort.SliceStable(jobs, func(i, j int) bool {
+		left, right := jobs[i], jobs[j]
+		if lp, rp := left.Metadata.Priority(), right.Metadata.Priority(); lp != rp {
+			return lp > rp
+		}
+		if la, ra := activeByProject[JobExecutionProjectKey(left)], activeByProject[JobExecutionProjectKey(right)]; la != ra {
+			return la < ra
+		}
+		if !left.CreatedUTC.Equal(right.CreatedUTC) {
+			return left.CreatedUTC.Before(right.CreatedUTC)
+		}
+		return strings.TrimSpace(left.ID) < strings.TrimSpace(right.ID)
+	})
+}
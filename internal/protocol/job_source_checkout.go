@@ -0,0 +1,66 @@
+package protocol
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Submodule modes of SourceCheckoutOptions.Submodules, matching the
+// vcs_source.submodules values.
+const (
+	SourceSubmodulesNone      = "none"
+	SourceSubmodulesTop       = "top"
+	SourceSubmodulesRecursive = "recursive"
+)
+
+// Clone returns a copy that shares no memory with o.
+func (o SourceCheckoutOptions) Clone() SourceCheckoutOptions {
+	if o.Depth != nil {
+		depth := *o.Depth
+		o.Depth = &depth
+	}
+	o.Sparse = append([]string(nil), o.Sparse...)
+	return o
+}
+
+// EffectiveDepth returns the number of commits to fetch, 0 meaning all.
+func (o SourceCheckoutOptions) EffectiveDepth() int {
+	if o.Depth == nil {
+		return 1
+	}
+	return max(*o.Depth, 0)
+}
+
+// EffectiveSubmodules returns the submodule mode, "none" when unset.
+func (o SourceCheckoutOptions) EffectiveSubmodules() string {
+	if submodules := strings.TrimSpace(o.Submodules); submodules != "" {
+		return submodules
+	}
+	return SourceSubmodulesNone
+}
+
+// Summary describes every option as it applies, defaults included, such as
+// "depth=1 submodules=none lfs=off sparse=off fetch_tags=off".
+func (o SourceCheckoutOptions) Summary() string {
+	onOff := func(on bool) string {
+		if on {
+			return "on"
+		}
+		return "off"
+	}
+	depth := "full"
+	if n := o.EffectiveDepth(); n > 0 {
+		depth = strconv.Itoa(n)
+	}
+	sparse := "off"
+	if len(o.Sparse) > 0 {
+		sparse = strings.Join(o.Sparse, ",")
+	}
+	return fmt.Sprintf("depth=%s submodules=%s lfs=%s sparse=%s fetch_tags=%s", depth, o.EffectiveSubmodules(), onOff(o.LFS), sparse, onOff(o.FetchTags))
+}
+
+// IsZero reports whether o asks for the default checkout.
+func (o SourceCheckoutOptions) IsZero() bool {
+	return o.Depth == nil && o.Submodules == "" && !o.LFS && len(o.Sparse) == 0 && !o.FetchTags
+}
@@ -16,6 +16,9 @@ func BuildJobExecutionTimeline(job JobExecution) []JobExecutionTimelineItem {
 		if ref := strings.TrimSpace(job.Source.Ref); ref != "" {
 			description += "\nRef: " + ref
 		}
+		if !job.Source.SourceCheckoutOptions.IsZero() {
+			description += "\nCheckout options: " + job.Source.SourceCheckoutOptions.Summary()
+		}
 		addPhase(JobExecutionPhaseCheckout, "Check out source", description)
 	}
 	if ids := dependencyArtifactIDsForTimeline(job.DependencyArtifactJobIDs); len(ids) > 0 {
@@ -34,6 +37,11 @@ func BuildJobExecutionTimeline(job JobExecution) []JobExecutionTimelineItem {
 		}
 	}
 	addPhase(JobExecutionPhaseEnvironment, "Prepare execution environment", envDescription)
+	for _, service := range job.Services {
+		if name := strings.TrimSpace(service.Name); name != "" {
+			addPhase(JobExecutionServicePhaseID(name), "Service "+name, "Image: "+strings.TrimSpace(service.Image))
+		}
+	}
 	for _, step := range job.StepPlan {
 		name := strings.TrimSpace(step.Name)
 		items = append(items, JobExecutionTimelineItem{
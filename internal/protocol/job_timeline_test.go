@@ -1,6 +1,9 @@
 package protocol
 
-import "testing"
+import (
+	"strings"
+	"testing"
+)
 
 func TestBuildJobExecutionTimelineIncludesApplicablePhasesAndSteps(t *testing.T) {
 	job := JobExecution{
@@ -57,3 +60,40 @@ func TestBuildJobExecutionTimelineMinimalJob(t *testing.T) {
 		t.Fatalf("expected unnamed step to omit a redundant display name, got %q", timeline[2].Name)
 	}
 }
+
+func TestBuildJobExecutionTimelineDescribesCheckoutOptions(t *testing.T) {
+	depth := 0
+	source := &SourceSpec{Repo: "https://example.test/repo.git", SourceCheckoutOptions: SourceCheckoutOptions{
+		Depth: &depth, Submodules: "recursive", Sparse: []string{"src", "docs"}, FetchTags: true,
+	}}
+	checkout, ok := TimelinePhase(BuildJobExecutionTimeline(JobExecution{Source: source}), JobExecutionPhaseCheckout)
+	want := "Repository: https://example.test/repo.git\nCheckout options: depth=full submodules=recursive lfs=off sparse=src,docs fetch_tags=on"
+	if !ok || checkout.Description != want {
+		t.Fatalf("expected checkout options in the description, got %+v", checkout)
+	}
+
+	checkout, _ = TimelinePhase(BuildJobExecutionTimeline(JobExecution{Source: &SourceSpec{Repo: source.Repo}}), JobExecutionPhaseCheckout)
+	if strings.Contains(checkout.Description, "Checkout options") {
+		t.Fatalf("expected the default checkout to keep the short description, got %q", checkout.Description)
+	}
+}
+
+func TestBuildJobExecutionTimelineAddsServicePhasesAfterEnvironment(t *testing.T) {
+	timeline := BuildJobExecutionTimeline(JobExecution{
+		Services: []JobServiceSpec{{Name: "postgres", Image: "postgres:16"}, {Name: "minio", Image: "minio/minio"}},
+		StepPlan: []JobStepPlanItem{{Index: 1}},
+	})
+	wantIDs := []string{JobExecutionPhaseWorkspace, JobExecutionPhaseEnvironment, "service:postgres", "service:minio", "step:1"}
+	if len(timeline) != len(wantIDs) {
+		t.Fatalf("expected %d timeline items, got %+v", len(wantIDs), timeline)
+	}
+	for i, wantID := range wantIDs {
+		if timeline[i].ID != wantID {
+			t.Fatalf("unexpected timeline item %d: %+v", i, timeline[i])
+		}
+	}
+	postgres, ok := TimelinePhase(timeline, JobExecutionServicePhaseID("postgres"))
+	if !ok || postgres.Name != "Service postgres" || postgres.Description != "Image: postgres:16" || postgres.Index != 3 || postgres.Total != 4 {
+		t.Fatalf("unexpected service phase: %+v", postgres)
+	}
+}
@@ -0,0 +1,50 @@
+package protocol
+
+const (
+	RepoCredentialHTTPSToken = "https_token"
+	RepoCredentialSSHKey     = "ssh_key"
+)
+
+// RepoCredential authenticates git access to every repository whose URL
+// starts with URLPrefix; the longest matching prefix wins. The secret itself
+// is never stored: it is read from the server environment variable SecretEnv
+// or from VaultSecret through the named Vault connection.
+type RepoCredential struct {
+	ID              int64              `json:"id"`
+	Name            string             `json:"name"`
+	URLPrefix       string             `json:"url_prefix"`
+	Kind            string             `json:"kind"`
+	Username        string             `json:"username,omitempty"`
+	SecretEnv       string             `json:"secret_env,omitempty"`
+	VaultConnection string             `json:"vault_connection,omitempty"`
+	VaultSecret     *ProjectSecretSpec `json:"vault_secret,omitempty"`
+	KnownHosts      string             `json:"known_hosts,omitempty"`
+}
+
+type UpsertRepoCredentialRequest struct {
+	Name            string             `json:"name"`
+	URLPrefix       string             `json:"url_prefix"`
+	Kind            string             `json:"kind"`
+	Username        string             `json:"username,omitempty"`
+	SecretEnv       string             `json:"secret_env,omitempty"`
+	VaultConnection string             `json:"vault_connection,omitempty"`
+	VaultSecret     *ProjectSecretSpec `json:"vault_secret,omitempty"`
+	KnownHosts      string             `json:"known_hosts,omitempty"`
+}
+
+// SourceCredential is a resolved repository credential, handed to an agent
+// for the checkout phase of a job it holds the lease for.
+type SourceCredential struct {
+	Kind       string `json:"kind"`
+	Username   string `json:"username,omitempty"`
+	Secret     string `json:"secret"`
+	KnownHosts string `json:"known_hosts,omitempty"`
+}
+
+type JobSourceCredentialRequest struct {
+	AgentID string `json:"agent_id"`
+}
+
+type JobSourceCredentialResponse struct {
+	Credential *SourceCredential `json:"credential,omitempty"`
+}
@@ -0,0 +1,28 @@
+package protocol
+
+type TestQuarantineEntry struct {
+	ID         int64  `json:"id"`
+	ProjectID  int64  `json:"project_id"`
+	Package    string `json:"package,omitempty"`
+	Name       string `json:"name"`
+	Owner      string `json:"owner"`
+	Reason     string `json:"reason,omitempty"`
+	CreatedBy  string `json:"created_by,omitempty"`
+	CreatedUTC string `json:"created_utc,omitempty"`
+	ExpiresUTC string `json:"expires_utc"`
+	Expired    bool   `json:"expired"`
+}
+
+// CreateTestQuarantineRequest accepts an RFC 3339 time or a YYYY-MM-DD date
+// as expiry; a date quarantines the test until the end of that day (UTC).
+type CreateTestQuarantineRequest struct {
+	Package    string `json:"package,omitempty"`
+	Name       string `json:"name"`
+	Owner      string `json:"owner"`
+	Reason     string `json:"reason,omitempty"`
+	ExpiresUTC string `json:"expires_utc"`
+}
+
+type TestQuarantineResponse struct {
+	Entries []TestQuarantineEntry `json:"entries"`
+}
@@ -14,11 +14,30 @@ type AgentSnapshot struct {
 	OS           string
 	Arch         string
 	Capabilities map[string]string
+	Labels       map[string]string
 	Freshness    string
 	Authorized   bool
 	Deactivated  bool
 	Updating     bool
-	Busy         bool
+	Slots        int
+	ActiveJobs   int
+}
+
+// JobSlots is how many jobs the agent runs at once; zero slots means one.
+func (a AgentSnapshot) JobSlots() int {
+	if a.Slots <= 0 {
+		return 1
+	}
+	return a.Slots
+}
+
+func (a AgentSnapshot) FreeSlots() int {
+	return max(a.JobSlots()-a.ActiveJobs, 0)
+}
+
+// Busy reports whether every job slot of the agent is taken.
+func (a AgentSnapshot) Busy() bool {
+	return a.FreeSlots() == 0
 }
 
 const (
@@ -66,6 +85,9 @@ func MatchCapabilities(required, observed map[string]string) MatchResult {
 		case key == "shell":
 			matched = ShellCapabilityMatch(observed, expected)
 			code = "shell_mismatch"
+		case strings.HasPrefix(key, domain.AgentLabelCapabilityPrefix):
+			matched = strings.TrimSpace(observed[actualKey]) == expected
+			code = "label_mismatch"
 		default:
 			matched = strings.TrimSpace(observed[actualKey]) == expected
 		}
@@ -93,18 +115,20 @@ func DiagnoseScheduling(required map[string]string, agents []AgentSnapshot) Sche
 	sort.Slice(sortedAgents, func(i, j int) bool { return sortedAgents[i].ID < sortedAgents[j].ID })
 	matching := 0
 	available := 0
+	freeSlots := 0
 	for _, agent := range sortedAgents {
 		match := MatchAgent(required, agent)
 		availabilityIssues := agentAvailabilityIssues(agent)
 		assessment := AgentAssessment{
 			AgentID: strings.TrimSpace(agent.ID), CapabilityMatch: match.Matches,
 			Available:        match.Matches && len(availabilityIssues) == 0,
-			CapabilityIssues: match.Issues, AvailabilityIssues: availabilityIssues,
+			CapabilityIssues: match.Issues, AvailabilityIssues: availabilityIssues, FreeSlots: agent.FreeSlots(),
 		}
 		if match.Matches {
 			matching++
 			if assessment.Available {
 				available++
+				freeSlots += assessment.FreeSlots
 			}
 		}
 		diagnosis.Agents = append(diagnosis.Agents, assessment)
@@ -124,6 +148,9 @@ func DiagnoseScheduling(required map[string]string, agents []AgentSnapshot) Sche
 	case available > 0:
 		diagnosis.State = DiagnosisReady
 		diagnosis.Summary = "Eligible agent available; awaiting lease"
+		if freeSlots > 1 {
+			diagnosis.Summary = fmt.Sprintf("%d free job slots on eligible agents; awaiting lease", freeSlots)
+		}
 	case matching == 1:
 		diagnosis.State = DiagnosisWaiting
 		for _, agent := range diagnosis.Agents {
@@ -161,6 +188,8 @@ func RequirementLabels(required map[string]string) []string {
 			}
 		case key == "shell":
 			labels = append(labels, value+" shell")
+		case strings.HasPrefix(key, domain.AgentLabelCapabilityPrefix):
+			labels = append(labels, "label "+strings.TrimPrefix(key, domain.AgentLabelCapabilityPrefix)+"="+value)
 		case key == "agent_id":
 			labels = append(labels, "agent "+value)
 		case key == "os", key == "arch":
@@ -173,6 +202,19 @@ func RequirementLabels(required map[string]string) []string {
 	return labels
 }
 
+// PreferredAgentAvailable reports whether a job may keep waiting for agent,
+// the agent it prefers: the agent must not be offline, must be authorized
+// and active, and must match required. Busy and updating agents still count
+// because they take jobs again once they are done.
+func PreferredAgentAvailable(required map[string]string, agent AgentSnapshot) bool {
+	switch strings.ToLower(strings.TrimSpace(agent.Freshness)) {
+	case "online", "stale":
+	default:
+		return false
+	}
+	return agent.Authorized && !agent.Deactivated && MatchAgent(required, agent).Matches
+}
+
 func agentAvailabilityIssues(agent AgentSnapshot) []string {
 	issues := make([]string, 0, 5)
 	switch strings.ToLower(strings.TrimSpace(agent.Freshness)) {
@@ -191,8 +233,12 @@ func agentAvailabilityIssues(agent AgentSnapshot) []string {
 	if agent.Updating {
 		issues = append(issues, "updating")
 	}
-	if agent.Busy {
-		issues = append(issues, "busy")
+	if agent.Busy() {
+		if agent.JobSlots() > 1 {
+			issues = append(issues, fmt.Sprintf("busy (%d/%d slots)", agent.ActiveJobs, agent.JobSlots()))
+		} else {
+			issues = append(issues, "busy")
+		}
 	}
 	return issues
 }
@@ -205,6 +251,9 @@ func mismatchMessage(key, expected, actual string) string {
 	if strings.HasPrefix(key, "requires.tool.") {
 		return fmt.Sprintf("tool %s expected %s, got %s", strings.TrimPrefix(key, "requires.tool."), expected, actualLabel)
 	}
+	if label, ok := strings.CutPrefix(key, domain.AgentLabelCapabilityPrefix); ok {
+		return fmt.Sprintf("label %s expected %s, got %s", label, expected, actualLabel)
+	}
 	return fmt.Sprintf("%s expected %s, got %s", key, expected, actualLabel)
 }
 
@@ -278,6 +327,11 @@ func ToolConstraintMatch(agentValue, constraint string) bool {
 func mergeCapabilities(agent AgentSnapshot) map[string]string {
 	merged := map[string]string{}
 	for key, value := range agent.Capabilities {
+		if !strings.HasPrefix(key, domain.AgentLabelCapabilityPrefix) {
+			merged[key] = value
+		}
+	}
+	for key, value := range LabelCapabilities(agent.Labels) {
 		merged[key] = value
 	}
 	merged["agent_id"] = strings.TrimSpace(agent.ID)
@@ -286,6 +340,16 @@ func mergeCapabilities(agent AgentSnapshot) map[string]string {
 	return merged
 }
 
+// LabelCapabilities turns agent labels into the capabilities runs_on labels
+// are matched against.
+func LabelCapabilities(labels map[string]string) map[string]string {
+	capabilities := make(map[string]string, len(labels))
+	for key, value := range labels {
+		capabilities[domain.AgentLabelCapabilityPrefix+key] = value
+	}
+	return capabilities
+}
+
 func normalizeSemver(v string) (string, bool) {
 	v = strings.TrimSpace(v)
 	if v == "" {
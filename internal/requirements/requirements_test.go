@@ -87,7 +87,7 @@ func TestDiagnoseSchedulingAvailabilityAndContainerTools(t *testing.T) {
 				"shells":      "posix",
 				"tool.docker": "28.0.0",
 			},
-			Freshness: "online", Authorized: true, Busy: true,
+			Freshness: "online", Authorized: true, ActiveJobs: 1,
 		},
 	})
 	if diagnosis.State != DiagnosisWaiting || len(diagnosis.Agents) != 1 || !diagnosis.Agents[0].CapabilityMatch {
@@ -110,3 +110,59 @@ func TestDiagnoseSchedulingNoAgentsAndReadyAgent(t *testing.T) {
 		t.Fatalf("unexpected ready diagnosis: %+v", ready)
 	}
 }
+
+func TestDiagnoseSchedulingCountsFreeSlots(t *testing.T) {
+	agents := []AgentSnapshot{
+		{ID: "agent-a", OS: "linux", Freshness: "online", Authorized: true, Slots: 4, ActiveJobs: 1},
+		{ID: "agent-b", OS: "linux", Freshness: "online", Authorized: true, Slots: 2, ActiveJobs: 2},
+	}
+	diagnosis := DiagnoseScheduling(map[string]string{"os": "linux"}, agents)
+	if diagnosis.State != DiagnosisReady || diagnosis.Summary != "3 free job slots on eligible agents; awaiting lease" {
+		t.Fatalf("unexpected diagnosis: %+v", diagnosis)
+	}
+	if got := diagnosis.Agents[1].AvailabilityIssues; len(got) != 1 || got[0] != "busy (2/2 slots)" {
+		t.Fatalf("availability issues = %v", got)
+	}
+
+	full := DiagnoseScheduling(map[string]string{"os": "linux"}, agents[1:])
+	if full.State != DiagnosisWaiting || full.Summary != "Matching agent agent-b is busy (2/2 slots)" {
+		t.Fatalf("unexpected full-agent diagnosis: %+v", full)
+	}
+}
+
+func TestMatchAgentReportsLabelMismatches(t *testing.T) {
+	required := map[string]string{"os": "linux", "label.dongle": "yes"}
+	agent := AgentSnapshot{ID: "agent-a", OS: "linux", Capabilities: map[string]string{"label.dongle": "yes"}}
+	result := MatchAgent(required, agent)
+	if result.Matches || len(result.Issues) != 1 || result.Issues[0].Code != "label_mismatch" ||
+		result.Issues[0].Message != "label dongle expected yes, got missing" {
+		t.Fatalf("expected reported capabilities not to stand in for labels, got %+v", result)
+	}
+
+	agent.Labels = map[string]string{"dongle": "yes"}
+	if result := MatchAgent(required, agent); !result.Matches {
+		t.Fatalf("expected the agent label to match, got %+v", result.Issues)
+	}
+	if labels := RequirementLabels(required); len(labels) != 2 || labels[0] != "label dongle=yes" {
+		t.Fatalf("unexpected requirement labels %v", labels)
+	}
+}
+
+func TestPreferredAgentAvailable(t *testing.T) {
+	required := map[string]string{"os": "linux"}
+	live := AgentSnapshot{ID: "a", OS: "linux", Freshness: "online", Authorized: true, Slots: 1, ActiveJobs: 1, Updating: true}
+	if !PreferredAgentAvailable(required, live) {
+		t.Fatal("expected a busy, updating but live agent to stay preferred")
+	}
+	for name, agent := range map[string]AgentSnapshot{
+		"offline":      {ID: "a", OS: "linux", Freshness: "offline", Authorized: true},
+		"unknown":      {ID: "a", OS: "linux", Authorized: true},
+		"unauthorized": {ID: "a", OS: "linux", Freshness: "online"},
+		"deactivated":  {ID: "a", OS: "linux", Freshness: "online", Authorized: true, Deactivated: true},
+		"mismatch":     {ID: "a", OS: "darwin", Freshness: "stale", Authorized: true},
+	} {
+		if PreferredAgentAvailable(required, agent) {
+			t.Fatalf("expected a %s agent not to be preferred", name)
+		}
+	}
+}
@@ -0,0 +1,201 @@
+package schedule
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Cron is a parsed five-field cron expression: minute, hour, day of month,
+// month and day of week.
+type Cron struct {
+	minutes  uint64
+	hours    uint64
+	days     uint64
+	months   uint64
+	weekdays uint64
+	// When both day fields are restricted a time matches if either matches,
+	// as in Vixie cron.
+	daysRestricted     bool
+	weekdaysRestricted bool
+}
+
+type field struct {
+	name  string
+	min   int
+	max   int
+	names map[string]int
+}
+
+var (
+	minuteField  = field{name: "minute", min: 0, max: 59}
+	hourField    = field{name: "hour", min: 0, max: 23}
+	dayField     = field{name: "day of month", min: 1, max: 31}
+	monthField   = field{name: "month", min: 1, max: 12, names: map[string]int{"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6, "jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12}}
+	weekdayField = field{name: "day of week", min: 0, max: 7, names: map[string]int{"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6}}
+)
+
+var macros = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// Parse parses a standard five-field cron expression. Fields accept *, lists,
+// ranges, steps and three-letter month and weekday names; the @yearly,
+// @monthly, @weekly, @daily and @hourly macros are also understood.
+func Parse(expr string) (Cron, error) {
+	expr = strings.TrimSpace(expr)
+	if expanded, ok := macros[strings.ToLower(expr)]; ok {
+		expr = expanded
+	}
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return Cron{}, fmt.Errorf("cron expression %q must have 5 fields, got %d", expr, len(fields))
+	}
+	var c Cron
+	var err error
+	if c.minutes, err = minuteField.parse(fields[0]); err != nil {
+		return Cron{}, err
+	}
+	if c.hours, err = hourField.parse(fields[1]); err != nil {
+		return Cron{}, err
+	}
+	if c.days, err = dayField.parse(fields[2]); err != nil {
+		return Cron{}, err
+	}
+	if c.months, err = monthField.parse(fields[3]); err != nil {
+		return Cron{}, err
+	}
+	if c.weekdays, err = weekdayField.parse(fields[4]); err != nil {
+		return Cron{}, err
+	}
+	// 7 is an alias for Sunday.
+	if c.weekdays&(1<<7) != 0 {
+		c.weekdays = c.weekdays&^(1<<7) | 1
+	}
+	c.daysRestricted = !strings.HasPrefix(fields[2], "*")
+	c.weekdaysRestricted = !strings.HasPrefix(fields[4], "*")
+	return c, nil
+}
+
+func (f field) parse(raw string) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(raw, ",") {
+		lo, hi, step := f.min, f.max, 1
+		rangePart := part
+		if slash := strings.Index(part, "/"); slash >= 0 {
+			rangePart = part[:slash]
+			n, err := strconv.Atoi(part[slash+1:])
+			if err != nil || n <= 0 {
+				return 0, fmt.Errorf("invalid step in %s field %q", f.name, raw)
+			}
+			step = n
+		}
+		switch {
+		case rangePart == "*":
+		case strings.Contains(rangePart, "-"):
+			bounds := strings.SplitN(rangePart, "-", 2)
+			var err error
+			if lo, err = f.value(bounds[0]); err != nil {
+				return 0, err
+			}
+			if hi, err = f.value(bounds[1]); err != nil {
+				return 0, err
+			}
+			if lo > hi {
+				return 0, fmt.Errorf("invalid range in %s field %q", f.name, raw)
+			}
+		default:
+			value, err := f.value(rangePart)
+			if err != nil {
+				return 0, err
+			}
+			lo = value
+			if step == 1 {
+				hi = value
+			}
+		}
+		for v := lo; v <= hi; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+	return bits, nil
+}
+
+func (f field) value(raw string) (int, error) {
+	if v, ok := f.names[strings.ToLower(raw)]; ok {
+		return v, nil
+	}
+	v, err := strconv.Atoi(raw)
+	if err != nil || v < f.min || v > f.max {
+		return 0, fmt.Errorf("invalid %s %q, expected %d-%d", f.name, raw, f.min, f.max)
+	}
+	return v, nil
+}
+
+// maxSearchYears bounds Next for expressions such as "0 0 30 2 *" that never
+// match.
+const maxSearchYears = 5
+
+// Next returns the first time strictly after t that matches the expression,
+// evaluated in t's location. The zero time is returned when nothing matches
+// within a few years.
+func (c Cron) Next(t time.Time) time.Time {
+	loc := t.Location()
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(maxSearchYears, 0, 0)
+	for t.Before(limit) {
+		if c.months&(1<<uint(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc)
+			continue
+		}
+		if !c.dayMatches(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
+			continue
+		}
+		if c.hours&(1<<uint(t.Hour())) == 0 {
+			next := time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, loc)
+			if !next.After(t) {
+				// Daylight saving transitions can map the next wall-clock hour
+				// back onto the current instant.
+				next = t.Add(time.Hour).Truncate(time.Hour)
+			}
+			t = next
+			continue
+		}
+		if c.minutes&(1<<uint(t.Minute())) == 0 {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+	return time.Time{}
+}
+
+func (c Cron) dayMatches(t time.Time) bool {
+	day := c.days&(1<<uint(t.Day())) != 0
+	weekday := c.weekdays&(1<<uint(t.Weekday())) != 0
+	if c.daysRestricted && c.weekdaysRestricted {
+		return day || weekday
+	}
+	return day && weekday
+}
+
+// Location resolves an IANA time zone name. An empty name means UTC.
+func Location(name string) (*time.Location, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return time.UTC, nil
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("unknown time zone %q", name)
+	}
+	return loc, nil
+}
@@ -0,0 +1,79 @@
+package schedule
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseRejectsInvalidExpressions(t *testing.T) {
+	for expr, want := range map[string]string{
+		"* * * *":       "must have 5 fields",
+		"60 * * * *":    "invalid minute",
+		"* 24 * * *":    "invalid hour",
+		"* * 0 * *":     "invalid day of month",
+		"* * * foo *":   "invalid month",
+		"*/0 * * * *":   "invalid step",
+		"10-5 * * * *":  "invalid range",
+		"* * * * mon-x": "invalid day of week",
+	} {
+		if _, err := Parse(expr); err == nil || !strings.Contains(err.Error(), want) {
+			t.Fatalf("Parse(%q) error = %v, want %q", expr, err, want)
+		}
+	}
+}
+
+func TestNext(t *testing.T) {
+	from := time.Date(2026, 5, 1, 10, 7, 30, 0, time.UTC) // a Friday
+	for expr, want := range map[string]time.Time{
+		"*/15 * * * *":     time.Date(2026, 5, 1, 10, 15, 0, 0, time.UTC),
+		"0 3 * * *":        time.Date(2026, 5, 2, 3, 0, 0, 0, time.UTC),
+		"@hourly":          time.Date(2026, 5, 1, 11, 0, 0, 0, time.UTC),
+		"30 9 * * mon-fri": time.Date(2026, 5, 4, 9, 30, 0, 0, time.UTC),
+		"0 0 1 jan *":      time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC),
+		"0 12 * * 7":       time.Date(2026, 5, 3, 12, 0, 0, 0, time.UTC),
+		"0 12 15 * sat":    time.Date(2026, 5, 2, 12, 0, 0, 0, time.UTC),
+		"0 0 29 2 *":       time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC),
+		"5,10 10 1,2 5 *":  time.Date(2026, 5, 1, 10, 10, 0, 0, time.UTC),
+		"0 0-23/6 * * *":   time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC),
+	} {
+		c, err := Parse(expr)
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", expr, err)
+		}
+		if got := c.Next(from); !got.Equal(want) {
+			t.Fatalf("Next(%q) = %s, want %s", expr, got, want)
+		}
+	}
+	never, _ := Parse("0 0 30 2 *")
+	if got := never.Next(from); !got.IsZero() {
+		t.Fatalf("expected impossible expression to never fire, got %s", got)
+	}
+}
+
+func TestNextUsesLocationAcrossDaylightSaving(t *testing.T) {
+	loc, err := Location("Europe/Amsterdam")
+	if err != nil {
+		t.Skipf("time zone data unavailable: %v", err)
+	}
+	c, _ := Parse("30 2 * * *")
+	// 2026-03-29 02:30 does not exist in Amsterdam, so that day is skipped.
+	got := c.Next(time.Date(2026, 3, 28, 12, 0, 0, 0, loc))
+	if want := time.Date(2026, 3, 30, 2, 30, 0, 0, loc); !got.Equal(want) {
+		t.Fatalf("unexpected fire across spring-forward: %s", got)
+	}
+	daily, _ := Parse("0 9 * * *")
+	got = daily.Next(time.Date(2026, 10, 24, 12, 0, 0, 0, loc))
+	if want := time.Date(2026, 10, 25, 9, 0, 0, 0, loc); !got.Equal(want) || got.UTC().Hour() != 8 {
+		t.Fatalf("expected 09:00 local after fall-back, got %s", got)
+	}
+}
+
+func TestLocation(t *testing.T) {
+	if loc, err := Location(""); err != nil || loc != time.UTC {
+		t.Fatalf("expected empty zone to mean UTC, got %v %v", loc, err)
+	}
+	if _, err := Location("Mars/Olympus"); err == nil {
+		t.Fatalf("expected unknown zone error")
+	}
+}
@@ -0,0 +1,48 @@
+package jobexecution
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/izzyreal/ciwi/internal/domain"
+	"github.com/izzyreal/ciwi/internal/protocol"
+)
+
+// JobCoverageGate returns the coverage gate of the last test step that
+// collects coverage.
+func JobCoverageGate(job protocol.JobExecution) (protocol.CoverageGate, bool) {
+	for i := len(job.StepPlan) - 1; i >= 0; i-- {
+		step := job.StepPlan[i]
+		if strings.TrimSpace(step.CoverageReport) == "" {
+			continue
+		}
+		if step.CoverageGate == nil {
+			return protocol.CoverageGate{}, false
+		}
+		return *step.CoverageGate, true
+	}
+	return protocol.CoverageGate{}, false
+}
+
+// EvaluateCoverageGate records on the coverage report whether it meets gate.
+// The drop bound only applies when there is a baseline to compare with.
+func EvaluateCoverageGate(coverage *protocol.CoverageReport, gate protocol.CoverageGate, baseline *domain.CoverageRun) {
+	result := &protocol.CoverageGateResult{Passed: true, MinPercent: gate.MinPercent, MaxDrop: gate.MaxDrop}
+	reasons := make([]string, 0, 2)
+	if gate.MinPercent != nil && coverage.Percent < *gate.MinPercent {
+		reasons = append(reasons, fmt.Sprintf("coverage %.2f%% is below the minimum of %.2f%%", coverage.Percent, *gate.MinPercent))
+	}
+	if baseline != nil {
+		result.BaselineJobID = baseline.JobExecutionID
+		result.BaselinePercent = baseline.Percent
+		if drop := baseline.Percent - coverage.Percent; gate.MaxDrop != nil && drop > *gate.MaxDrop {
+			reasons = append(reasons, fmt.Sprintf("coverage dropped %.2f points from %.2f%% in %s, more than the allowed %.2f",
+				drop, baseline.Percent, baseline.JobExecutionID, *gate.MaxDrop))
+		}
+	}
+	if len(reasons) > 0 {
+		result.Passed = false
+		result.Message = strings.Join(reasons, "; ")
+	}
+	coverage.Gate = result
+}
@@ -0,0 +1,49 @@
+package jobexecution
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/izzyreal/ciwi/internal/domain"
+	"github.com/izzyreal/ciwi/internal/protocol"
+)
+
+func TestEvaluateCoverageGate(t *testing.T) {
+	floor, drop := 70.0, 1.0
+	gate := protocol.CoverageGate{MinPercent: &floor, MaxDrop: &drop}
+	baseline := &domain.CoverageRun{JobExecutionID: "job-1", Percent: 80}
+
+	coverage := &protocol.CoverageReport{Percent: 79.5}
+	EvaluateCoverageGate(coverage, gate, baseline)
+	if !coverage.Gate.Passed || coverage.Gate.BaselineJobID != "job-1" || coverage.Gate.BaselinePercent != 80 {
+		t.Fatalf("expected a small drop to pass, got %+v", coverage.Gate)
+	}
+
+	coverage = &protocol.CoverageReport{Percent: 65}
+	EvaluateCoverageGate(coverage, gate, baseline)
+	if coverage.Gate.Passed || !strings.Contains(coverage.Gate.Message, "below the minimum") || !strings.Contains(coverage.Gate.Message, "dropped 15.00 points") {
+		t.Fatalf("expected both bounds to fail, got %+v", coverage.Gate)
+	}
+
+	coverage = &protocol.CoverageReport{Percent: 75}
+	EvaluateCoverageGate(coverage, gate, nil)
+	if !coverage.Gate.Passed || coverage.Gate.BaselineJobID != "" {
+		t.Fatalf("expected the drop bound to be skipped without a baseline, got %+v", coverage.Gate)
+	}
+}
+
+func TestJobCoverageGateUsesLastCoverageStep(t *testing.T) {
+	floor := 50.0
+	job := protocol.JobExecution{StepPlan: []protocol.JobStepPlanItem{
+		{Kind: "test", CoverageReport: "a.out", CoverageGate: &protocol.CoverageGate{MinPercent: &floor}},
+		{Kind: "test", CoverageReport: "b.out"},
+		{Kind: "run"},
+	}}
+	if _, ok := JobCoverageGate(job); ok {
+		t.Fatal("expected the ungated last coverage step to win")
+	}
+	job.StepPlan = job.StepPlan[:1]
+	if gate, ok := JobCoverageGate(job); !ok || *gate.MinPercent != 50 {
+		t.Fatalf("expected the gate of the coverage step, got %+v %v", gate, ok)
+	}
+}
@@ -14,6 +14,7 @@ import (
 	"time"
 
 	"github.com/izzyreal/ciwi/internal/application"
+	"github.com/izzyreal/ciwi/internal/domain"
 	"github.com/izzyreal/ciwi/internal/protocol"
 	"github.com/izzyreal/ciwi/internal/server/httpx"
 )
@@ -24,6 +25,7 @@ type Store interface {
 	GetJobExecution(id string) (protocol.JobExecution, error)
 	DeleteQueuedJobExecution(id string) error
 	UpdateJobExecutionStatus(id string, req protocol.JobExecutionStatusUpdateRequest) (protocol.JobExecution, error)
+	MergeJobExecutionMetadata(id string, patch map[string]string) (map[string]string, error)
 	AppendJobExecutionEvents(id string, events []protocol.JobExecutionEvent) error
 	ListJobExecutionEvents(id string) ([]protocol.JobExecutionEvent, error)
 	ListJobExecutionEventsAfter(id string, afterID int64) ([]protocol.JobExecutionEvent, error)
@@ -54,12 +56,17 @@ type HandlerDeps struct {
 	AttachSchedulingDiagnosis func(*protocol.JobExecution)
 	AttachProgress            func(*protocol.JobExecution)
 	MarkAgentSeen             func(agentID string, ts time.Time)
+	AuthorizeAgent            func(r *http.Request, agentID string) error
+	AuthorizeEnrolledAgent    func(r *http.Request, agentID string) error
 	OnJobUpdated              func(job protocol.JobExecution)
 	OnJobStateChanged         func(job protocol.JobExecution)
 	OnQueueChanged            func()
 	OnHistoryChanged          func()
 	OnJobHistoryChanged       func(jobExecutionID string)
 	PrepareRerun              func(original protocol.JobExecution, request *protocol.CreateJobExecutionRequest) error
+	ListTestQuarantine        func(projectID int64) ([]domain.TestQuarantineEntry, error)
+	CoverageBaseline          func(scope domain.TestHistoryScope, sourceRef, excludeJobID string) (domain.CoverageRun, bool, error)
+	SourceCredential          func(ctx context.Context, repoURL string) (*protocol.SourceCredential, error)
 	Now                       func() time.Time
 }
 
@@ -233,6 +240,11 @@ func HandleByID(w http.ResponseWriter, r *http.Request, deps HandlerDeps) {
 		return
 	}
 
+	if parsed.IsResource("source-credential") {
+		handleJobSourceCredential(w, r, deps, jobID)
+		return
+	}
+
 	if parsed.IsResource("blocked-by") {
 		handleJobBlockedBy(w, r, deps, jobID)
 		return
@@ -551,13 +563,27 @@ func cloneJobCaches(in []protocol.JobCacheSpec) []protocol.JobCacheSpec {
 	return out
 }
 
+func cloneJobServices(in []protocol.JobServiceSpec) []protocol.JobServiceSpec {
+	if len(in) == 0 {
+		return nil
+	}
+	out := make([]protocol.JobServiceSpec, 0, len(in))
+	for _, svc := range in {
+		svc.Env = cloneStringMap(svc.Env)
+		svc.Ports = append([]int(nil), svc.Ports...)
+		out = append(out, svc)
+	}
+	return out
+}
+
 func cloneSource(in *protocol.SourceSpec) *protocol.SourceSpec {
 	if in == nil {
 		return nil
 	}
 	return &protocol.SourceSpec{
-		Repo: in.Repo,
-		Ref:  in.Ref,
+		Repo:                  in.Repo,
+		Ref:                   in.Ref,
+		SourceCheckoutOptions: in.SourceCheckoutOptions.Clone(),
 	}
 }
 
@@ -572,25 +598,47 @@ func cloneJobStepPlan(in []protocol.JobStepPlanItem) []protocol.JobStepPlanItem
 			secrets = append([]protocol.ProjectSecretSpec(nil), step.VaultSecrets...)
 		}
 		out = append(out, protocol.JobStepPlanItem{
-			Index:           step.Index,
-			Total:           step.Total,
-			Name:            step.Name,
-			YAMLLiteral:     step.YAMLLiteral,
-			Script:          step.Script,
-			Kind:            step.Kind,
-			Env:             cloneStringMap(step.Env),
-			VaultConnection: step.VaultConnection,
-			VaultSecrets:    secrets,
-			TestName:        step.TestName,
-			TestFormat:      step.TestFormat,
-			TestReport:      step.TestReport,
-			CoverageFormat:  step.CoverageFormat,
-			CoverageReport:  step.CoverageReport,
+			Index:               step.Index,
+			Total:               step.Total,
+			Name:                step.Name,
+			YAMLLiteral:         step.YAMLLiteral,
+			Script:              step.Script,
+			Kind:                step.Kind,
+			Env:                 cloneStringMap(step.Env),
+			VaultConnection:     step.VaultConnection,
+			VaultSecrets:        secrets,
+			TestName:            step.TestName,
+			TestFormat:          step.TestFormat,
+			TestReport:          step.TestReport,
+			CoverageFormat:      step.CoverageFormat,
+			CoverageReport:      step.CoverageReport,
+			CoverageGate:        step.CoverageGate,
+			RunWhen:             step.RunWhen,
+			SkipReason:          step.SkipReason,
+			RetryMaxAttempts:    step.RetryMaxAttempts,
+			RetryBackoffSeconds: step.RetryBackoffSeconds,
 		})
 	}
 	return out
 }
 
+func authorizeAgent(r *http.Request, deps HandlerDeps, agentID string) error {
+	if deps.AuthorizeAgent == nil {
+		return nil
+	}
+	return deps.AuthorizeAgent(r, strings.TrimSpace(agentID))
+}
+
+// authorizeEnrolledAgent is authorizeAgent for requests that hand out
+// secrets: the caller must present its agent credential even while
+// enrollment is optional.
+func authorizeEnrolledAgent(r *http.Request, deps HandlerDeps, agentID string) error {
+	if deps.AuthorizeEnrolledAgent == nil {
+		return authorizeAgent(r, deps, agentID)
+	}
+	return deps.AuthorizeEnrolledAgent(r, strings.TrimSpace(agentID))
+}
+
 func nowUTC(deps HandlerDeps) time.Time {
 	if deps.Now != nil {
 		ts := deps.Now()
@@ -43,10 +43,7 @@ func handleJobBlockedBy(w http.ResponseWriter, r *http.Request, deps HandlerDeps
 }
 
 func isDependencyBlockedJob(job protocol.JobExecution) bool {
-	if protocol.NormalizeJobExecutionStatus(job.Status) != protocol.JobExecutionStatusFailed {
-		return false
-	}
-	if !job.StartedUTC.IsZero() {
+	if protocol.NormalizeJobExecutionStatus(job.Status) != protocol.JobExecutionStatusSkipped {
 		return false
 	}
 	_, _, ok := blockedReasonFromError(job.Error)
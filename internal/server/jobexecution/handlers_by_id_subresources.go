@@ -38,7 +38,7 @@ func handleJobCancel(w http.ResponseWriter, r *http.Request, deps HandlerDeps, j
 		httpx.WriteJSON(w, http.StatusOK, SingleViewResponse{JobExecution: ViewFromProtocol(updated)})
 		return
 	}
-	updated, err := CancelJobExecution(deps.Store, jobID, nowUTC(deps))
+	updated, err := CancelJobExecution(deps.Store, jobID, nowUTC(deps), domain.ExecutionCancellation{By: application.ActorName(r.Context())})
 	if err != nil {
 		status := http.StatusBadRequest
 		if strings.Contains(err.Error(), "not found") {
@@ -108,11 +108,18 @@ func writeApplicationError(w http.ResponseWriter, err error) {
 		status = http.StatusServiceUnavailable
 	case application.ErrorUnsupported:
 		status = http.StatusNotImplemented
+	case application.ErrorUnauthenticated:
+		status = http.StatusUnauthorized
+	case application.ErrorPermissionDenied:
+		status = http.StatusForbidden
 	}
 	http.Error(w, err.Error(), status)
 }
 
-func CancelJobExecution(store Store, jobID string, now time.Time) (protocol.JobExecution, error) {
+// CancelJobExecution terminates an active execution on the server and records
+// who cancelled it. The leasing agent notices the terminal state and stops its
+// process tree.
+func CancelJobExecution(store Store, jobID string, now time.Time, cancellation domain.ExecutionCancellation) (protocol.JobExecution, error) {
 	job, err := store.GetJobExecution(jobID)
 	if err != nil {
 		return protocol.JobExecution{}, fmt.Errorf("job not found: %w", err)
@@ -124,16 +131,28 @@ func CancelJobExecution(store Store, jobID string, now time.Time) (protocol.JobE
 	if agentID == "" {
 		agentID = "server-control"
 	}
+	summary := cancellation.Summary()
 	updated, err := store.UpdateJobExecutionStatus(jobID, protocol.JobExecutionStatusUpdateRequest{
-		AgentID: agentID, Status: protocol.JobExecutionStatusFailed,
-		Error: "cancelled by user", TimestampUTC: now,
+		AgentID: agentID, Status: protocol.JobExecutionStatusCancelled,
+		Error: summary, TimestampUTC: now,
 	})
 	if err != nil {
 		return protocol.JobExecution{}, err
 	}
+	if protocol.NormalizeJobExecutionStatus(updated.Status) != protocol.JobExecutionStatusCancelled {
+		// The job finished on its own before the cancellation landed.
+		return updated, nil
+	}
+	patch := domain.ExecutionMetadata{}
+	patch.SetCancellation(cancellation)
+	metadata, err := store.MergeJobExecutionMetadata(jobID, patch)
+	if err != nil {
+		return protocol.JobExecution{}, err
+	}
+	updated.Metadata = metadata
 	if err := store.AppendJobExecutionEvents(jobID, []protocol.JobExecutionEvent{{
 		Type: protocol.JobExecutionEventTypeSystemMessage, TimestampUTC: now,
-		Message: "[control] job cancelled by user",
+		Message: "[control] job " + summary,
 	}}); err != nil {
 		return protocol.JobExecution{}, err
 	}
@@ -158,11 +177,13 @@ func RerunJobExecution(store Store, jobID string, prepare func(protocol.JobExecu
 	}
 	metadata.Set(protocol.JobMetadataAttemptRootJobID, rootID)
 	metadata.Set(protocol.JobMetadataRerunOfJobID, job.ID)
+	// The new attempt waits for its preferred agent afresh.
+	delete(metadata, domain.ExecutionMetadataAffinityWaitingSinceUTC)
 	request := protocol.CreateJobExecutionRequest{
 		Script: job.Script, Env: cloneStringMap(job.Env), RequiredCapabilities: cloneStringMap(job.RequiredCapabilities),
 		TimeoutSeconds: job.TimeoutSeconds, ArtifactGlobs: append([]string(nil), job.ArtifactGlobs...),
 		DependencyArtifactJobIDs: append([]string(nil), job.DependencyArtifactJobIDs...), Caches: cloneJobCaches(job.Caches),
-		Source: cloneSource(job.Source), Metadata: metadata, StepPlan: cloneJobStepPlan(job.StepPlan),
+		Services: cloneJobServices(job.Services), Source: cloneSource(job.Source), Metadata: metadata, StepPlan: cloneJobStepPlan(job.StepPlan),
 	}
 	if prepare != nil {
 		if err := prepare(job, &request); err != nil {
@@ -186,6 +207,10 @@ func handleJobStatus(w http.ResponseWriter, r *http.Request, deps HandlerDeps, j
 		http.Error(w, "agent_id is required", http.StatusBadRequest)
 		return
 	}
+	if err := authorizeAgent(r, deps, req.AgentID); err != nil {
+		writeApplicationError(w, err)
+		return
+	}
 	if !protocol.IsValidJobExecutionUpdateStatus(req.Status) {
 		http.Error(w, "status must be running, succeeded or failed", http.StatusBadRequest)
 		return
@@ -248,7 +273,7 @@ func handleJobArtifactsUploadZIP(w http.ResponseWriter, r *http.Request, deps Ha
 		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
 		return
 	}
-	agentID := strings.TrimSpace(r.Header.Get("X-CIWI-Agent-ID"))
+	agentID := strings.TrimSpace(r.Header.Get(protocol.AgentIDHeader))
 	if agentID == "" {
 		agentID = strings.TrimSpace(r.URL.Query().Get("agent_id"))
 	}
@@ -256,6 +281,10 @@ func handleJobArtifactsUploadZIP(w http.ResponseWriter, r *http.Request, deps Ha
 		http.Error(w, "agent_id is required", http.StatusBadRequest)
 		return
 	}
+	if err := authorizeAgent(r, deps, agentID); err != nil {
+		writeApplicationError(w, err)
+		return
+	}
 	job, err := deps.Store.GetJobExecution(jobID)
 	if err != nil {
 		http.Error(w, "job not found", http.StatusNotFound)
@@ -362,6 +391,10 @@ func handleJobTests(w http.ResponseWriter, r *http.Request, deps HandlerDeps, jo
 			http.Error(w, "agent_id is required", http.StatusBadRequest)
 			return
 		}
+		if err := authorizeAgent(r, deps, req.AgentID); err != nil {
+			writeApplicationError(w, err)
+			return
+		}
 		job, err := deps.Store.GetJobExecution(jobID)
 		if err != nil {
 			http.Error(w, "job not found", http.StatusNotFound)
@@ -371,6 +404,28 @@ func handleJobTests(w http.ResponseWriter, r *http.Request, deps HandlerDeps, jo
 			http.Error(w, "job is leased by another agent", http.StatusConflict)
 			return
 		}
+		if projectID, ok := job.Metadata.Int64(domain.ExecutionMetadataProjectID); ok && projectID > 0 && deps.ListTestQuarantine != nil {
+			entries, err := deps.ListTestQuarantine(projectID)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			ApplyTestQuarantine(&req.Report, entries, nowUTC(deps))
+		}
+		if gate, ok := JobCoverageGate(job); ok && req.Report.Coverage != nil {
+			var baseline *domain.CoverageRun
+			if scope, ok := domain.TestHistoryScopeOf(job.Metadata); ok && deps.CoverageBaseline != nil {
+				run, found, err := deps.CoverageBaseline(scope, job.Metadata.Value(domain.ExecutionMetadataPipelineSourceRefRaw), jobID)
+				if err != nil {
+					http.Error(w, err.Error(), http.StatusInternalServerError)
+					return
+				}
+				if found {
+					baseline = &run
+				}
+			}
+			EvaluateCoverageGate(req.Report.Coverage, gate, baseline)
+		}
 		if err := deps.Store.SaveJobExecutionTestReport(jobID, req.Report); err != nil {
 			http.Error(w, err.Error(), http.StatusInternalServerError)
 			return
@@ -392,3 +447,46 @@ func handleJobTests(w http.ResponseWriter, r *http.Request, deps HandlerDeps, jo
 		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
 	}
 }
+
+// handleJobSourceCredential hands the agent running a job the credential for
+// its source repository, just in time for the checkout phase. Only the
+// enrolled agent holding the lease gets it, and only while the job is still
+// active.
+func handleJobSourceCredential(w http.ResponseWriter, r *http.Request, deps HandlerDeps, jobID string) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	var req protocol.JobSourceCredentialRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid JSON body", http.StatusBadRequest)
+		return
+	}
+	if strings.TrimSpace(req.AgentID) == "" {
+		http.Error(w, "agent_id is required", http.StatusBadRequest)
+		return
+	}
+	if err := authorizeEnrolledAgent(r, deps, req.AgentID); err != nil {
+		writeApplicationError(w, err)
+		return
+	}
+	job, err := deps.Store.GetJobExecution(jobID)
+	if err != nil {
+		http.Error(w, "job not found", http.StatusNotFound)
+		return
+	}
+	if job.LeasedByAgentID != req.AgentID || !protocol.IsActiveJobExecutionStatus(job.Status) {
+		http.Error(w, "job is not running on this agent", http.StatusConflict)
+		return
+	}
+	if job.Source == nil || deps.SourceCredential == nil {
+		httpx.WriteJSON(w, http.StatusOK, protocol.JobSourceCredentialResponse{})
+		return
+	}
+	cred, err := deps.SourceCredential(r.Context(), job.Source.Repo)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	httpx.WriteJSON(w, http.StatusOK, protocol.JobSourceCredentialResponse{Credential: cred})
+}
@@ -223,7 +223,7 @@ func TestHandleByIDBlockedBy(t *testing.T) {
 			getJobExecutionFn: func(id string) (protocol.JobExecution, error) {
 				return protocol.JobExecution{
 					ID:     id,
-					Status: protocol.JobExecutionStatusFailed,
+					Status: protocol.JobExecutionStatusSkipped,
 					Error:  "cancelled: required job unit-tests failed",
 					Metadata: map[string]string{
 						"project":         "ciwi",
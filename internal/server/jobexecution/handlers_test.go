@@ -15,6 +15,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/izzyreal/ciwi/internal/domain"
 	"github.com/izzyreal/ciwi/internal/protocol"
 )
 
@@ -24,6 +25,7 @@ type stubStore struct {
 	getJobExecutionFn               func(id string) (protocol.JobExecution, error)
 	deleteQueuedJobExecutionFn      func(id string) error
 	updateJobExecutionStatusFn      func(id string, req protocol.JobExecutionStatusUpdateRequest) (protocol.JobExecution, error)
+	mergeJobExecutionMetadataFn     func(id string, patch map[string]string) (map[string]string, error)
 	appendJobExecutionEventsFn      func(id string, events []protocol.JobExecutionEvent) error
 	listJobExecutionEventsFn        func(id string) ([]protocol.JobExecutionEvent, error)
 	listJobExecutionEventsAfterFn   func(id string, afterID int64) ([]protocol.JobExecutionEvent, error)
@@ -71,6 +73,13 @@ func (s *stubStore) UpdateJobExecutionStatus(id string, req protocol.JobExecutio
 	return protocol.JobExecution{}, fmt.Errorf("unexpected UpdateJobExecutionStatus call")
 }
 
+func (s *stubStore) MergeJobExecutionMetadata(id string, patch map[string]string) (map[string]string, error) {
+	if s.mergeJobExecutionMetadataFn != nil {
+		return s.mergeJobExecutionMetadataFn(id, patch)
+	}
+	return nil, fmt.Errorf("unexpected MergeJobExecutionMetadata call")
+}
+
 func (s *stubStore) AppendJobExecutionEvents(id string, events []protocol.JobExecutionEvent) error {
 	if s.appendJobExecutionEventsFn != nil {
 		return s.appendJobExecutionEventsFn(id, events)
@@ -154,15 +163,21 @@ func TestHandleByIDCancelActiveJob(t *testing.T) {
 		}, nil
 	}
 	store.updateJobExecutionStatusFn = func(id string, req protocol.JobExecutionStatusUpdateRequest) (protocol.JobExecution, error) {
-		if req.AgentID != "agent-1" || req.Status != protocol.JobExecutionStatusFailed {
+		if req.AgentID != "agent-1" || req.Status != protocol.JobExecutionStatusCancelled {
 			t.Fatalf("unexpected update request: %+v", req)
 		}
 		return protocol.JobExecution{
 			ID:     id,
-			Status: protocol.JobExecutionStatusFailed,
+			Status: protocol.JobExecutionStatusCancelled,
 			Error:  req.Error,
 		}, nil
 	}
+	store.mergeJobExecutionMetadataFn = func(id string, patch map[string]string) (map[string]string, error) {
+		if patch[domain.ExecutionMetadataCancelledBy] != "user" {
+			t.Fatalf("expected cancellation actor in metadata patch, got %+v", patch)
+		}
+		return patch, nil
+	}
 	store.appendJobExecutionEventsFn = func(id string, events []protocol.JobExecutionEvent) error {
 		if len(events) != 1 || events[0].Type != protocol.JobExecutionEventTypeSystemMessage || !strings.Contains(events[0].Message, "job cancelled by user") {
 			t.Fatalf("unexpected cancellation events: %+v", events)
@@ -232,7 +247,7 @@ func TestHandleByIDRerunAllowsDependencyBlockedJob(t *testing.T) {
 	store := &stubStore{}
 	store.getJobExecutionFn = func(id string) (protocol.JobExecution, error) {
 		return protocol.JobExecution{
-			ID: id, Script: "echo package", Status: protocol.JobExecutionStatusFailed,
+			ID: id, Script: "echo package", Status: protocol.JobExecutionStatusSkipped,
 			Error:    "cancelled: upstream pipeline build failed",
 			Metadata: map[string]string{"pipeline_id": "package"},
 		}, nil
@@ -0,0 +1,37 @@
+package jobexecution
+
+import (
+	"strings"
+	"time"
+
+	"github.com/izzyreal/ciwi/internal/domain"
+	"github.com/izzyreal/ciwi/internal/protocol"
+)
+
+// ApplyTestQuarantine marks the failures of quarantined tests and moves them
+// from the failed to the quarantined counts. It recomputes the marks from
+// scratch, so a report that is posted again is not counted twice.
+func ApplyTestQuarantine(report *protocol.JobExecutionTestReport, entries []domain.TestQuarantineEntry, now time.Time) {
+	report.Failed += report.Quarantined
+	report.Quarantined = 0
+	for suiteIndex := range report.Suites {
+		suite := &report.Suites[suiteIndex]
+		suite.Failed += suite.Quarantined
+		suite.Quarantined = 0
+		for caseIndex := range suite.Cases {
+			testCase := &suite.Cases[caseIndex]
+			_, quarantined := domain.ActiveTestQuarantine(entries, testCase.Package, testCase.Name, now)
+			testCase.Quarantined = quarantined && strings.EqualFold(strings.TrimSpace(testCase.Status), "fail")
+			if testCase.Quarantined {
+				suite.Quarantined++
+			}
+		}
+		// A parser may count failures it could not attribute to a case;
+		// those always keep failing the job.
+		suite.Quarantined = min(suite.Quarantined, suite.Failed)
+		suite.Failed -= suite.Quarantined
+		report.Quarantined += suite.Quarantined
+	}
+	report.Quarantined = min(report.Quarantined, report.Failed)
+	report.Failed -= report.Quarantined
+}
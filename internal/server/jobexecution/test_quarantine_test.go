@@ -0,0 +1,48 @@
+package jobexecution
+
+import (
+	"testing"
+	"time"
+
+	"github.com/izzyreal/ciwi/internal/domain"
+	"github.com/izzyreal/ciwi/internal/protocol"
+)
+
+func TestApplyTestQuarantineMovesOnlyActiveFailures(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	entries := []domain.TestQuarantineEntry{
+		{Package: "p", Name: "TestFlaky", ExpiresUTC: now.Add(time.Hour)},
+		{Name: "TestAnywhere", ExpiresUTC: now.Add(time.Hour)},
+		{Package: "p", Name: "TestExpired", ExpiresUTC: now.Add(-time.Hour)},
+	}
+	report := protocol.JobExecutionTestReport{Total: 5, Passed: 1, Failed: 4, Suites: []protocol.TestSuiteReport{{
+		Total: 5, Passed: 1, Failed: 4,
+		Cases: []protocol.TestCase{
+			{Package: "p", Name: "TestFlaky", Status: "fail"},
+			{Package: "q", Name: "TestAnywhere", Status: "fail"},
+			{Package: "p", Name: "TestExpired", Status: "fail"},
+			{Package: "p", Name: "TestReal", Status: "fail"},
+			{Package: "p", Name: "TestFlaky", Status: "pass"},
+		},
+	}}}
+
+	ApplyTestQuarantine(&report, entries, now)
+	ApplyTestQuarantine(&report, entries, now)
+
+	suite := report.Suites[0]
+	if report.Failed != 2 || report.Quarantined != 2 || suite.Failed != 2 || suite.Quarantined != 2 {
+		t.Fatalf("expected two quarantined and two real failures, got report %d/%d suite %d/%d",
+			report.Failed, report.Quarantined, suite.Failed, suite.Quarantined)
+	}
+	for i, want := range []bool{true, true, false, false, false} {
+		if suite.Cases[i].Quarantined != want {
+			t.Fatalf("case %d quarantined = %v, want %v", i, suite.Cases[i].Quarantined, want)
+		}
+	}
+
+	// Once the quarantine lapses, a repost fails the job again.
+	ApplyTestQuarantine(&report, nil, now)
+	if report.Failed != 4 || report.Quarantined != 0 || report.Suites[0].Cases[0].Quarantined {
+		t.Fatalf("expected quarantine to be lifted, got %+v", report)
+	}
+}
@@ -17,6 +17,7 @@ type View struct {
 	ArtifactGlobs            []string                            `json:"artifact_globs,omitempty"`
 	DependencyArtifactJobIDs []string                            `json:"dependency_artifact_job_ids,omitempty"`
 	Caches                   []protocol.JobCacheSpec             `json:"caches,omitempty"`
+	Services                 []protocol.JobServiceSpec           `json:"services,omitempty"`
 	Source                   *protocol.SourceSpec                `json:"source,omitempty"`
 	Metadata                 domain.ExecutionMetadata            `json:"metadata,omitempty"`
 	StepPlan                 []protocol.JobStepPlanItem          `json:"step_plan,omitempty"`
@@ -112,6 +113,7 @@ func ViewFromProtocol(job protocol.JobExecution) View {
 		ArtifactGlobs:            job.ArtifactGlobs,
 		DependencyArtifactJobIDs: job.DependencyArtifactJobIDs,
 		Caches:                   job.Caches,
+		Services:                 job.Services,
 		Source:                   job.Source,
 		Metadata:                 job.Metadata,
 		StepPlan:                 job.StepPlan,
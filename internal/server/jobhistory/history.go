@@ -61,6 +61,8 @@ type SummaryView struct {
 	TotalJobs  int `json:"total_jobs"`
 	Succeeded  int `json:"succeeded"`
 	Failed     int `json:"failed"`
+	Cancelled  int `json:"cancelled"`
+	Skipped    int `json:"skipped"`
 	InProgress int `json:"in_progress"`
 	Waiting    int `json:"waiting"`
 }
@@ -509,6 +511,10 @@ func summarizeCard(jobs []protocol.JobExecution, card executionCard) SummaryView
 			out.Succeeded++
 		case status == protocol.JobExecutionStatusFailed:
 			out.Failed++
+		case status == protocol.JobExecutionStatusCancelled:
+			out.Cancelled++
+		case status == protocol.JobExecutionStatusSkipped:
+			out.Skipped++
 		case isWaitingJobExecution(job):
 			out.Waiting++
 		case protocol.IsActiveJobExecutionStatus(status):
@@ -661,13 +667,19 @@ func buildSectionItems(jobs []protocol.JobExecution) []ItemView {
 }
 
 func matrixEntryLabel(job protocol.JobExecution) string {
+	label := ""
 	if name := job.Metadata.Value(domain.ExecutionMetadataMatrixName); name != "" {
-		return name
+		label = name
+	} else if idx := job.Metadata.Value(domain.ExecutionMetadataMatrixIndex); idx != "" {
+		label = "idx-" + idx
 	}
-	if idx := job.Metadata.Value(domain.ExecutionMetadataMatrixIndex); idx != "" {
-		return "idx-" + idx
+	if index, total, ok := job.Metadata.Shard(); ok {
+		if label != "" {
+			label += " · "
+		}
+		label += domain.ShardLabel(index, total)
 	}
-	return ""
+	return label
 }
 
 func cardTitle(jobs []protocol.JobExecution, card executionCard) string {
@@ -790,7 +802,7 @@ func pipelineCardKey(job protocol.JobExecution) string {
 }
 
 func matrixGroupKey(job protocol.JobExecution) string {
-	if job.Metadata.Value(domain.ExecutionMetadataMatrixName) == "" && job.Metadata.Value(domain.ExecutionMetadataMatrixIndex) == "" {
+	if _, _, sharded := job.Metadata.Shard(); !sharded && job.Metadata.Value(domain.ExecutionMetadataMatrixName) == "" && job.Metadata.Value(domain.ExecutionMetadataMatrixIndex) == "" {
 		return ""
 	}
 	pipelineID := job.Metadata.Value(domain.ExecutionMetadataPipelineID)
@@ -268,7 +268,7 @@ func (e *Estimator) comparableCompletedExecutionUnits(target protocol.JobExecuti
 	exact := make([]protocol.JobExecution, 0)
 	fallback := make([]protocol.JobExecution, 0)
 	for _, candidate := range jobs {
-		if candidate.ID == target.ID || !protocol.IsTerminalJobExecutionStatus(candidate.Status) || !candidate.CreatedUTC.Before(target.CreatedUTC) {
+		if candidate.ID == target.ID || !ranToCompletion(candidate.Status) || !candidate.CreatedUTC.Before(target.CreatedUTC) {
 			continue
 		}
 		if candidate.StartedUTC.IsZero() || candidate.FinishedUTC.IsZero() || !candidate.FinishedUTC.After(candidate.StartedUTC) {
@@ -289,6 +289,17 @@ func (e *Estimator) comparableCompletedExecutionUnits(target protocol.JobExecuti
 	return exact, fallback
 }
 
+// ranToCompletion reports whether a job ran all the steps it was going to
+// run. Cancelled and skipped jobs only carry partial durations.
+func ranToCompletion(status string) bool {
+	switch protocol.NormalizeJobExecutionStatus(status) {
+	case protocol.JobExecutionStatusSucceeded, protocol.JobExecutionStatusFailed:
+		return true
+	default:
+		return false
+	}
+}
+
 func (e *Estimator) comparableSuccessfulJobsByKey(target protocol.JobExecution, jobs []protocol.JobExecution, key string, keyFor func(protocol.JobExecution) string) []protocol.JobExecution {
 	if key == "" {
 		return nil
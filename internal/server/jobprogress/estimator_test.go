@@ -478,6 +478,27 @@ func TestAttachDetailEstimateUsesSuccessfulUnitsFromFailedJob(t *testing.T) {
 	}
 }
 
+func TestAttachDetailEstimateIgnoresCancelledJobs(t *testing.T) {
+	base := time.Date(2026, 7, 18, 20, 0, 0, 0, time.UTC)
+	step := protocol.JobStepPlanItem{Index: 1, Script: "make", Kind: "run"}
+	target := progressJob("target", base, protocol.JobExecutionStatusRunning, "agent-a", step)
+	cancelled := completedProgressJob("cancelled", base.Add(-time.Minute), "agent-a", step, 2*time.Second)
+	cancelled.Status = protocol.JobExecutionStatusCancelled
+	store := &stubStore{
+		jobs: []protocol.JobExecution{cancelled},
+		events: map[string][]protocol.JobExecutionEvent{
+			cancelled.ID: {{Type: protocol.JobExecutionEventTypeStepFinished, Step: &step, DurationMS: 1400}},
+		},
+	}
+
+	if err := New(store).AttachDetailEstimate(&target); err != nil {
+		t.Fatalf("AttachDetailEstimate: %v", err)
+	}
+	if target.ExpectedDurationMS != 0 || len(target.StepExpectedDuration) != 0 {
+		t.Fatalf("cancelled job must not contribute durations: total=%d steps=%v", target.ExpectedDurationMS, target.StepExpectedDuration)
+	}
+}
+
 func TestAttachDetailEstimateFallsBackPerUnitWhenSameAgentSampleIsUnusable(t *testing.T) {
 	base := time.Date(2026, 7, 18, 20, 0, 0, 0, time.UTC)
 	step := protocol.JobStepPlanItem{Index: 1, Script: "make", Kind: "run"}
@@ -10,6 +10,8 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+
+	"github.com/izzyreal/ciwi/internal/gitauth"
 )
 
 type RepoFetchResult struct {
@@ -115,18 +117,27 @@ func runCmd(ctx context.Context, dir, name string, args ...string) (string, erro
 }
 
 func runCmdWithEnv(ctx context.Context, dir string, env map[string]string, name string, args ...string) (string, error) {
-	cmd := exec.CommandContext(ctx, name, args...)
-	cmd.Dir = dir
-	if len(env) > 0 {
-		cmd.Env = mergeEnvWithOverrides(env)
-	}
-	out, err := cmd.CombinedOutput()
+	out, err := runCmdBytesWithEnv(ctx, dir, env, name, args...)
 	return string(out), err
 }
 
 func runCmdBytes(ctx context.Context, dir, name string, args ...string) ([]byte, error) {
+	return runCmdBytesWithEnv(ctx, dir, nil, name, args...)
+}
+
+// runCmdBytesWithEnv also authenticates with the repository credential bound
+// to ctx, if any.
+func runCmdBytesWithEnv(ctx context.Context, dir string, env map[string]string, name string, args ...string) ([]byte, error) {
+	authEnv, release, err := gitauth.CommandEnv(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
 	cmd := exec.CommandContext(ctx, name, args...)
 	cmd.Dir = dir
+	if len(env) > 0 || len(authEnv) > 0 {
+		cmd.Env = append(mergeEnvWithOverrides(env), authEnv...)
+	}
 	return cmd.CombinedOutput()
 }
 
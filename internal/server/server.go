@@ -33,6 +33,7 @@ type stateStore struct {
 	update          updateState
 	restartServerFn func()
 	installationID  string
+	startedUTC      time.Time
 }
 
 type projectIconState struct {
@@ -73,6 +74,7 @@ func Run(ctx context.Context) error {
 			os.Exit(0)
 		},
 		installationID: installationID,
+		startedUTC:     time.Now().UTC(),
 	}
 	if target, ok, err := db.GetAppState("agent_update_target"); err == nil && ok {
 		s.update.mu.Lock()
@@ -91,6 +93,8 @@ func Run(ctx context.Context) error {
 		slog.Error("initial blocked job reconciliation failed", "error", err)
 	}
 	go s.runJobExecutionMaintenanceLoop(ctx)
+	go s.runVCSPollerLoop(ctx)
+	go s.runSchedulerLoop(ctx)
 	srv := &http.Server{Addr: addr, Handler: buildRouter(s, artifactsDir), ReadHeaderTimeout: 10 * time.Second}
 	stopMDNS := startMDNSAdvertiser(addr)
 	defer stopMDNS()
@@ -102,11 +106,13 @@ func Run(ctx context.Context) error {
 		app := s.app()
 		artifactDownloads := newArtifactDownloadService(s.db, artifactsDir)
 		handler, handlerErr := nativecnp.NewHandler(nativecnp.Services{
-			Server: app.server, Projects: app.projects, ProjectCommands: app.projectCommands, ManagedYAML: s, Vault: s, Updates: app.updates, FrontPage: app.frontPage,
+			Server: app.server, Projects: app.projects, ProjectCommands: app.projectCommands, ManagedYAML: s, Vault: app.vault, Updates: app.updates, FrontPage: app.frontPage,
 			ProjectDetails:    app.projectDetails,
 			ProjectIcons:      s,
 			JobDetails:        app.jobDetails,
 			JobLogs:           app.jobDetails,
+			TestHistory:       app.testHistory,
+			TestQuarantine:    app.testQuarantine,
 			ArtifactDownloads: artifactDownloads,
 			JobContexts:       s,
 			Pipelines:         app.pipelines, PipelineChains: app.pipelineChains,
@@ -117,6 +123,7 @@ func Run(ctx context.Context) error {
 			ExecutionCommands: app.executionCommands, ExecutionControls: app.executionControls,
 			CommandReceipts: app.commandReceipts,
 			Changes:         app.changes, Version: currentVersion(),
+			Auth: app.accounts,
 		})
 		if handlerErr != nil {
 			return handlerErr
@@ -4,10 +4,12 @@ import (
 	"strings"
 	"time"
 
+	"github.com/izzyreal/ciwi/internal/domain"
 	"github.com/izzyreal/ciwi/internal/protocol"
+	"github.com/izzyreal/ciwi/internal/server/jobexecution"
 )
 
-func (s *stateStore) cancelActiveJobsForAgent(agentID string) (int, error) {
+func (s *stateStore) cancelActiveJobsForAgent(agentID string, cancellation domain.ExecutionCancellation) (int, error) {
 	agentID = strings.TrimSpace(agentID)
 	if agentID == "" {
 		return 0, nil
@@ -24,19 +26,7 @@ func (s *stateStore) cancelActiveJobsForAgent(agentID string) (int, error) {
 		if !protocol.IsActiveJobExecutionStatus(job.Status) {
 			continue
 		}
-		if _, err := s.agentJobExecutionStore().UpdateJobExecutionStatus(job.ID, protocol.JobExecutionStatusUpdateRequest{
-			AgentID:      agentID,
-			Status:       protocol.JobExecutionStatusFailed,
-			Error:        "cancelled by user",
-			TimestampUTC: time.Now().UTC(),
-		}); err != nil {
-			return cancelled, err
-		}
-		if err := s.jobExecutionStore().AppendJobExecutionEvents(job.ID, []protocol.JobExecutionEvent{{
-			Type:         protocol.JobExecutionEventTypeSystemMessage,
-			TimestampUTC: time.Now().UTC(),
-			Message:      "[control] job cancelled by user",
-		}}); err != nil {
+		if _, err := jobexecution.CancelJobExecution(s.jobExecutionStore(), job.ID, time.Now().UTC(), cancellation); err != nil {
 			return cancelled, err
 		}
 		cancelled++
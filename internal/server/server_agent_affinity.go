@@ -0,0 +1,74 @@
+package server
+
+import (
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/izzyreal/ciwi/internal/domain"
+	"github.com/izzyreal/ciwi/internal/protocol"
+	"github.com/izzyreal/ciwi/internal/requirements"
+)
+
+const (
+	agentAffinityWaitEnv     = "CIWI_AGENT_AFFINITY_WAIT_SECONDS"
+	agentAffinityDefaultWait = 60 * time.Second
+)
+
+// setAgentAffinityMetadata makes a pipeline job prefer the agent that last ran
+// it successfully, whose workspace and caches for it are still warm.
+func (s *stateStore) setAgentAffinityMetadata(metadata domain.ExecutionMetadata) {
+	key := metadata.AffinityKey()
+	if key == "" || metadata.Flag(domain.ExecutionMetadataDryRun) {
+		return
+	}
+	metadata.Set(domain.ExecutionMetadataAffinityKey, key)
+	wait := agentAffinityWaitFromEnv()
+	if wait <= 0 {
+		return
+	}
+	agentID, err := s.pipelineStore().LastSucceededAgentForAffinityKey(key)
+	if err != nil {
+		slog.Warn("look up preferred agent", "affinity_key", key, "error", err)
+		return
+	}
+	if agentID == "" {
+		return
+	}
+	metadata.Set(domain.ExecutionMetadataAffinityAgentID, agentID)
+	metadata.Set(domain.ExecutionMetadataAffinityWaitSeconds, strconv.Itoa(int(wait/time.Second)))
+}
+
+// preferredAgentEligible reports whether a queued job should keep waiting for
+// its preferred agent. An agent that went offline, lost its authorization,
+// was deactivated or deleted, or no longer matches runs_on would otherwise
+// hold the job for the whole wait.
+func (s *stateStore) preferredAgentEligible(agentID string, job protocol.JobExecution) bool {
+	s.mu.Lock()
+	agent, ok := s.agents[agentID]
+	var snapshot requirements.AgentSnapshot
+	if ok {
+		snapshot = requirements.AgentSnapshot{
+			ID: agentID, OS: agent.OS, Arch: agent.Arch, Capabilities: cloneMap(agent.Capabilities), Labels: agent.effectiveLabels(),
+			Freshness: classifyAgentFreshness(agent.LastSeenUTC, time.Now().UTC()), Authorized: agent.Authorized, Deactivated: agent.Deactivated,
+		}
+	}
+	s.mu.Unlock()
+	return ok && requirements.PreferredAgentAvailable(job.RequiredCapabilities, snapshot)
+}
+
+// agentAffinityWaitFromEnv reads how long other agents leave a job to its
+// preferred agent; 0 turns affinity off.
+func agentAffinityWaitFromEnv() time.Duration {
+	raw := strings.TrimSpace(envOrDefault(agentAffinityWaitEnv, ""))
+	if raw == "" {
+		return agentAffinityDefaultWait
+	}
+	seconds, err := strconv.Atoi(raw)
+	if err != nil || seconds < 0 {
+		slog.Warn("ignoring invalid agent affinity wait", "env", agentAffinityWaitEnv, "value", raw)
+		return agentAffinityDefaultWait
+	}
+	return time.Duration(seconds) * time.Second
+}
@@ -0,0 +1,111 @@
+package server
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/izzyreal/ciwi/internal/domain"
+	"github.com/izzyreal/ciwi/internal/protocol"
+)
+
+func TestEnqueuePrefersAgentWithWarmWorkspace(t *testing.T) {
+	s, pipeline := loadPipelineForEnqueueBuilderTest(t, []byte(`
+version: 1
+project:
+  name: ciwi
+pipelines:
+  - id: ci
+    jobs:
+      - id: build
+        runs_on: {os: linux}
+        timeout_seconds: 30
+        steps:
+          - run: go build ./...
+`), "affinity")
+	linux := map[string]string{"os": "linux"}
+	enqueue := func() protocol.JobExecution {
+		t.Helper()
+		resp, err := s.enqueuePersistedPipeline(pipeline, nil)
+		if err != nil {
+			t.Fatalf("enqueue pipeline: %v", err)
+		}
+		job, err := s.db.GetJobExecution(resp.JobExecutionIDs[0])
+		if err != nil {
+			t.Fatalf("get job: %v", err)
+		}
+		return job
+	}
+
+	first := enqueue()
+	if _, _, ok := first.Metadata.AgentAffinity(); ok || first.Metadata.Value(domain.ExecutionMetadataAffinityKey) == "" {
+		t.Fatalf("expected a first run to have a key but no preferred agent, got %+v", first.Metadata)
+	}
+	if leased, err := s.db.LeaseJobExecution("agent-a", linux); err != nil || leased == nil || leased.ID != first.ID {
+		t.Fatalf("expected agent-a to lease %s, got %+v err=%v", first.ID, leased, err)
+	}
+	if _, err := s.db.UpdateJobExecutionStatus(first.ID, protocol.JobExecutionStatusUpdateRequest{AgentID: "agent-a", Status: protocol.JobExecutionStatusSucceeded}); err != nil {
+		t.Fatalf("finish job: %v", err)
+	}
+
+	second := enqueue()
+	if preferred, wait, ok := second.Metadata.AgentAffinity(); !ok || preferred != "agent-a" || wait != agentAffinityDefaultWait {
+		t.Fatalf("expected the next run to prefer agent-a for %s, got %q %s", agentAffinityDefaultWait, preferred, wait)
+	}
+	if leased, err := s.db.LeaseJobExecution("agent-b", linux); err != nil || leased != nil {
+		t.Fatalf("expected agent-b to leave the job to agent-a, got %+v err=%v", leased, err)
+	}
+	s.agents = map[string]agentState{
+		"agent-a": {OS: "linux", Arch: "amd64", Authorized: true, LastSeenUTC: time.Now().UTC()},
+		"agent-b": {OS: "linux", Arch: "amd64", Authorized: true, LastSeenUTC: time.Now().UTC()},
+	}
+	waiting, err := s.db.GetJobExecution(second.ID)
+	if err != nil {
+		t.Fatalf("get job: %v", err)
+	}
+	s.attachJobExecutionSchedulingDiagnosis(&waiting)
+	if diagnosis := waiting.SchedulingDiagnosis; diagnosis == nil || diagnosis.State != "waiting" ||
+		!strings.HasPrefix(diagnosis.Summary, "Waiting for preferred agent agent-a") {
+		t.Fatalf("expected the diagnosis to name the preferred agent, got %+v", diagnosis)
+	}
+	if leased, err := s.db.LeaseJobExecution("agent-a", linux); err != nil || leased == nil || leased.ID != second.ID {
+		t.Fatalf("expected agent-a to lease %s, got %+v err=%v", second.ID, leased, err)
+	}
+
+	if _, err := s.db.UpdateJobExecutionStatus(second.ID, protocol.JobExecutionStatusUpdateRequest{AgentID: "agent-a", Status: protocol.JobExecutionStatusSucceeded}); err != nil {
+		t.Fatalf("finish job: %v", err)
+	}
+
+	// An offline preferred agent does not hold the job.
+	s.agents["agent-a"] = agentState{OS: "linux", Arch: "amd64", Authorized: true, LastSeenUTC: time.Now().UTC().Add(-time.Hour)}
+	offline := enqueue()
+	if preferred, _, ok := offline.Metadata.AgentAffinity(); !ok || preferred != "agent-a" {
+		t.Fatalf("expected the run to prefer agent-a, got %+v", offline.Metadata)
+	}
+	if leased, err := s.db.LeaseJobExecutionWithAffinity("agent-b", linux, s.preferredAgentEligible); err != nil || leased == nil || leased.ID != offline.ID {
+		t.Fatalf("expected agent-b to lease %s while agent-a is offline, got %+v err=%v", offline.ID, leased, err)
+	}
+	if _, err := s.db.UpdateJobExecutionStatus(offline.ID, protocol.JobExecutionStatusUpdateRequest{AgentID: "agent-b", Status: protocol.JobExecutionStatusFailed}); err != nil {
+		t.Fatalf("finish job: %v", err)
+	}
+
+	// Neither does a deactivated one, while a live one still does.
+	s.agents["agent-a"] = agentState{OS: "linux", Arch: "amd64", Authorized: true, Deactivated: true, LastSeenUTC: time.Now().UTC()}
+	deactivated := enqueue()
+	if leased, err := s.db.LeaseJobExecutionWithAffinity("agent-b", linux, s.preferredAgentEligible); err != nil || leased == nil || leased.ID != deactivated.ID {
+		t.Fatalf("expected agent-b to lease %s while agent-a is deactivated, got %+v err=%v", deactivated.ID, leased, err)
+	}
+	if _, err := s.db.UpdateJobExecutionStatus(deactivated.ID, protocol.JobExecutionStatusUpdateRequest{AgentID: "agent-b", Status: protocol.JobExecutionStatusFailed}); err != nil {
+		t.Fatalf("finish job: %v", err)
+	}
+	s.agents["agent-a"] = agentState{OS: "linux", Arch: "amd64", Authorized: true, LastSeenUTC: time.Now().UTC()}
+	live := enqueue()
+	if leased, err := s.db.LeaseJobExecutionWithAffinity("agent-b", linux, s.preferredAgentEligible); err != nil || leased != nil {
+		t.Fatalf("expected agent-b to leave %s to the live agent-a, got %+v err=%v", live.ID, leased, err)
+	}
+
+	t.Setenv(agentAffinityWaitEnv, "0")
+	if third := enqueue(); third.Metadata.Value(domain.ExecutionMetadataAffinityAgentID) != "" {
+		t.Fatalf("expected a zero wait to turn affinity off, got %+v", third.Metadata)
+	}
+}
@@ -26,14 +26,16 @@ func (a agentRepositoryAdapter) ListAgents(ctx context.Context) ([]domain.Agent,
 	serverVersion := currentVersion()
 	result := make([]domain.Agent, 0, len(snapshots))
 	for _, snapshot := range snapshots {
-		jobInProgress, err := a.state.agentJobExecutionStore().AgentHasActiveJobExecution(snapshot.ID)
+		activeJobs, err := a.state.agentJobExecutionStore().CountAgentActiveJobExecutions(snapshot.ID)
 		if err != nil {
-			jobInProgress = false
+			activeJobs = 0
 		}
-		view := agentViewFromState(snapshot.ID, snapshot.State, snapshot.PendingUpdate, serverVersion, jobInProgress)
+		view := agentViewFromState(snapshot.ID, snapshot.State, snapshot.PendingUpdate, serverVersion, activeJobs)
 		result = append(result, domain.Agent{
 			ID: view.AgentID, Hostname: view.Hostname, OS: view.OS, Arch: view.Arch, Version: view.Version,
 			Authorized: view.Authorized, Deactivated: view.Deactivated, JobInProgress: view.JobInProgress,
+			Slots: view.Slots, SlotsOverridden: view.SlotsOverride > 0, ActiveJobs: view.ActiveJobs,
+			Labels: cloneMap(view.Labels), AssignedLabels: cloneMap(view.AssignedLabels),
 			Capabilities: cloneMap(view.Capabilities), LastSeenUTC: view.LastSeenUTC, RecentLog: append([]string(nil), view.RecentLog...),
 			NeedsUpdate: view.NeedsUpdate, UpdateTarget: view.UpdateTarget, UpdateRequested: view.UpdateRequested,
 			UpdateAttempts: view.UpdateAttempts, UpdateInProgress: view.UpdateInProgress,
@@ -149,7 +151,9 @@ func (a agentMutatorAdapter) ExecuteAgentAction(ctx context.Context, request app
 		cancelled := 0
 		if deactivated {
 			var err error
-			cancelled, err = s.cancelActiveJobsForAgent(agentID)
+			cancelled, err = s.cancelActiveJobsForAgent(agentID, domain.ExecutionCancellation{
+				By: application.ActorName(ctx), Reason: "agent " + agentID + " deactivated",
+			})
 			if err != nil {
 				return application.AgentActionResult{}, application.WrapInternal("cancel active agent jobs", err)
 			}
@@ -243,6 +247,57 @@ func (a agentMutatorAdapter) ExecuteAgentAction(ctx context.Context, request app
 		return requestedAgentAction(agentID, "agent snapshot deleted"), nil
 	case application.AgentActionUpdate:
 		return s.requestAgentUpdate(agentID)
+	case application.AgentActionSetSlots:
+		s.mu.Lock()
+		agent, ok := s.agents[agentID]
+		if ok {
+			agent.SlotsOverride = request.Slots
+			if request.Slots > 0 {
+				agent.RecentLog = appendAgentLog(agent.RecentLog, "job slots set to "+strconv.Itoa(request.Slots))
+			} else {
+				agent.RecentLog = appendAgentLog(agent.RecentLog, "job slots reset to agent configuration")
+			}
+			s.agents[agentID] = agent
+		}
+		s.mu.Unlock()
+		if !ok {
+			return application.AgentActionResult{}, agentNotFoundError(agentID)
+		}
+		if err := s.persistAgentSnapshot(agentID, agent); err != nil {
+			return application.AgentActionResult{}, application.WrapInternal("persist agent job slots", err)
+		}
+		return requestedAgentAction(agentID, "agent job slots set to "+strconv.Itoa(agent.jobSlots())), nil
+	case application.AgentActionSetLabels:
+		s.mu.Lock()
+		agent, ok := s.agents[agentID]
+		if ok {
+			agent.AssignedLabels = request.Labels
+			if len(request.Labels) > 0 {
+				agent.RecentLog = appendAgentLog(agent.RecentLog, "labels set to "+domain.FormatAgentLabels(request.Labels))
+			} else {
+				agent.RecentLog = appendAgentLog(agent.RecentLog, "assigned labels cleared")
+			}
+			s.agents[agentID] = agent
+		}
+		s.mu.Unlock()
+		if !ok {
+			return application.AgentActionResult{}, agentNotFoundError(agentID)
+		}
+		if err := s.persistAgentSnapshot(agentID, agent); err != nil {
+			return application.AgentActionResult{}, application.WrapInternal("persist agent labels", err)
+		}
+		return requestedAgentAction(agentID, "agent labels updated"), nil
+	case application.AgentActionRevokeCredentials:
+		if err := s.app().agentCredentials.RevokeCredential(ctx, agentID); err != nil {
+			return application.AgentActionResult{}, err
+		}
+		s.mu.Lock()
+		if agent, ok := s.agents[agentID]; ok {
+			agent.RecentLog = appendAgentLog(agent.RecentLog, "agent credentials revoked")
+			s.agents[agentID] = agent
+		}
+		s.mu.Unlock()
+		return requestedAgentAction(agentID, "agent credentials revoked; the agent must enroll again"), nil
 	default:
 		return application.AgentActionResult{}, application.NewError(application.ErrorInvalidArgument, "unsupported agent action", nil)
 	}
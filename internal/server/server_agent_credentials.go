@@ -0,0 +1,160 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/izzyreal/ciwi/internal/application"
+	"github.com/izzyreal/ciwi/internal/domain"
+	"github.com/izzyreal/ciwi/internal/protocol"
+)
+
+// agentEnrollmentRequiredEnv makes the server reject agents that have not
+// enrolled. Without it they are only rejected once enrollment is in use.
+const agentEnrollmentRequiredEnv = "CIWI_AGENT_ENROLLMENT_REQUIRED"
+
+type agentCallerContextKey struct{}
+
+func agentCallerFromContext(ctx context.Context) (string, bool) {
+	agentID, ok := ctx.Value(agentCallerContextKey{}).(string)
+	return agentID, ok
+}
+
+// bearerAgentCredential returns the agent credential of a request, if it
+// carries one instead of a personal API token.
+func bearerAgentCredential(r *http.Request) (string, bool) {
+	token, ok := strings.CutPrefix(strings.TrimSpace(r.Header.Get("Authorization")), "Bearer ")
+	if !ok || !strings.HasPrefix(strings.TrimSpace(token), application.AgentCredentialPrefix) {
+		return "", false
+	}
+	return strings.TrimSpace(token), true
+}
+
+// authenticateAgentRequest verifies the credential against the agent ID the
+// request names in its header and returns a context carrying that agent.
+func (s *stateStore) authenticateAgentRequest(r *http.Request, credential string) (context.Context, error) {
+	agentID := strings.TrimSpace(r.Header.Get(protocol.AgentIDHeader))
+	if agentID == "" {
+		return nil, application.NewError(application.ErrorUnauthenticated, "agent credentials require the "+protocol.AgentIDHeader+" header", nil)
+	}
+	if err := s.app().agentCredentials.AuthenticateAgent(r.Context(), agentID, credential); err != nil {
+		return nil, err
+	}
+	return context.WithValue(r.Context(), agentCallerContextKey{}, agentID), nil
+}
+
+// authorizeAgentCaller checks that an agent route request comes from the
+// agent it claims to be. Requests without a credential are only accepted for
+// agents that never enrolled, and only while no agent uses enrollment.
+func (s *stateStore) authorizeAgentCaller(r *http.Request, agentID string) error {
+	agentID = strings.TrimSpace(agentID)
+	if caller, ok := agentCallerFromContext(r.Context()); ok {
+		if caller != agentID {
+			return application.NewError(application.ErrorPermissionDenied, "credential of agent "+caller+" cannot act as agent "+agentID, nil)
+		}
+		return nil
+	}
+	return s.app().agentCredentials.AuthenticateAgent(r.Context(), agentID, "")
+}
+
+// authorizeEnrolledAgentCaller is authorizeAgentCaller without the fallback
+// for unenrolled agents. The middleware only sets the caller after checking
+// the credential, so revoked credentials never get here.
+func (s *stateStore) authorizeEnrolledAgentCaller(r *http.Request, agentID string) error {
+	agentID = strings.TrimSpace(agentID)
+	caller, ok := agentCallerFromContext(r.Context())
+	if !ok {
+		return application.NewError(application.ErrorUnauthenticated, "agent "+agentID+" must enroll and present its credential", nil)
+	}
+	if caller != agentID {
+		return application.NewError(application.ErrorPermissionDenied, "credential of agent "+caller+" cannot act as agent "+agentID, nil)
+	}
+	return nil
+}
+
+func (s *stateStore) agentEnrollHandler(w http.ResponseWriter, r *http.Request) {
+	var req protocol.AgentEnrollRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid JSON body", http.StatusBadRequest)
+		return
+	}
+	result, err := s.app().agentCredentials.Enroll(r.Context(), application.EnrollAgentRequest{AgentID: req.AgentID, Token: req.EnrollmentToken})
+	if err != nil {
+		http.Error(w, err.Error(), applicationErrorHTTPStatus(err))
+		return
+	}
+	s.mu.Lock()
+	if agent, ok := s.agents[result.AgentID]; ok {
+		agent.RecentLog = appendAgentLog(agent.RecentLog, "agent enrolled")
+		s.agents[result.AgentID] = agent
+	}
+	s.mu.Unlock()
+	writeJSON(w, http.StatusOK, protocol.AgentEnrollResponse{AgentID: result.AgentID, Credential: result.Credential})
+}
+
+func (s *stateStore) agentEnrollmentTokensHandler(w http.ResponseWriter, r *http.Request) {
+	credentials := s.app().agentCredentials
+	switch r.Method {
+	case http.MethodGet:
+		tokens, err := credentials.ListEnrollmentTokens(r.Context())
+		if err != nil {
+			http.Error(w, err.Error(), applicationErrorHTTPStatus(err))
+			return
+		}
+		response := protocol.AgentEnrollmentTokensResponse{Tokens: make([]protocol.AgentEnrollmentToken, 0, len(tokens))}
+		for _, token := range tokens {
+			response.Tokens = append(response.Tokens, enrollmentTokenToProtocol(token))
+		}
+		writeJSON(w, http.StatusOK, response)
+	case http.MethodPost:
+		var req protocol.CreateAgentEnrollmentTokenRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "invalid JSON body", http.StatusBadRequest)
+			return
+		}
+		var expires time.Time
+		if strings.TrimSpace(req.ExpiresUTC) != "" {
+			parsed, err := time.Parse(time.RFC3339, strings.TrimSpace(req.ExpiresUTC))
+			if err != nil {
+				http.Error(w, "expires_utc must be an RFC 3339 time", http.StatusBadRequest)
+				return
+			}
+			expires = parsed
+		}
+		result, err := credentials.CreateEnrollmentToken(r.Context(), application.CreateEnrollmentTokenRequest{Description: req.Description, ExpiresUTC: expires})
+		if err != nil {
+			http.Error(w, err.Error(), applicationErrorHTTPStatus(err))
+			return
+		}
+		writeJSON(w, http.StatusCreated, protocol.CreateAgentEnrollmentTokenResponse{Token: enrollmentTokenToProtocol(result.Token), Secret: result.Secret})
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (s *stateStore) agentEnrollmentTokenByIDHandler(w http.ResponseWriter, r *http.Request) {
+	tokenID, ok := authResourceID(w, r, "/api/v1/agent-enrollment-tokens/")
+	if !ok {
+		return
+	}
+	if r.Method != http.MethodDelete {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if err := s.app().agentCredentials.DeleteEnrollmentToken(r.Context(), tokenID); err != nil {
+		http.Error(w, err.Error(), applicationErrorHTTPStatus(err))
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func enrollmentTokenToProtocol(token domain.AgentEnrollmentToken) protocol.AgentEnrollmentToken {
+	return protocol.AgentEnrollmentToken{
+		ID: token.ID, Description: token.Description, Prefix: token.Prefix, CreatedBy: token.CreatedBy,
+		CreatedUTC: formatOptionalUTC(token.CreatedUTC), ExpiresUTC: formatOptionalUTC(token.ExpiresUTC),
+		UsedUTC: formatOptionalUTC(token.UsedUTC), UsedByAgentID: token.UsedByAgentID,
+	}
+}
@@ -0,0 +1,149 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/izzyreal/ciwi/internal/protocol"
+)
+
+func TestAgentEnrollmentBindsCredentialToAgentID(t *testing.T) {
+	_, s := newTestHTTPServerWithState(t)
+	srv := httptest.NewServer(buildRouter(s, s.artifactsDir))
+	defer srv.Close()
+
+	resp := mustJSONRequest(t, srv.Client(), http.MethodPost, srv.URL+"/api/v1/agent-enrollment-tokens", protocol.CreateAgentEnrollmentTokenRequest{Description: "build box"})
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("create enrollment token: %d body=%s", resp.StatusCode, readBody(t, resp))
+	}
+	var token protocol.CreateAgentEnrollmentTokenResponse
+	decodeJSONBody(t, resp, &token)
+
+	resp = mustJSONRequest(t, srv.Client(), http.MethodPost, srv.URL+"/api/v1/agent/enroll", protocol.AgentEnrollRequest{AgentID: "agent-a", EnrollmentToken: token.Secret})
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("enroll: %d body=%s", resp.StatusCode, readBody(t, resp))
+	}
+	var enrolled protocol.AgentEnrollResponse
+	decodeJSONBody(t, resp, &enrolled)
+
+	resp = mustJSONRequest(t, srv.Client(), http.MethodPost, srv.URL+"/api/v1/agent/enroll", protocol.AgentEnrollRequest{AgentID: "agent-b", EnrollmentToken: token.Secret})
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("expected reused token to be rejected, got %d body=%s", resp.StatusCode, readBody(t, resp))
+	}
+	_ = readBody(t, resp)
+
+	heartbeat := func(claimedID, headerID, credential string) int {
+		t.Helper()
+		body, _ := json.Marshal(protocol.HeartbeatRequest{AgentID: claimedID, Hostname: "h", OS: "linux", Arch: "amd64"})
+		req, _ := http.NewRequest(http.MethodPost, srv.URL+"/api/v1/heartbeat", bytes.NewReader(body))
+		if headerID != "" {
+			req.Header.Set(protocol.AgentIDHeader, headerID)
+		}
+		if credential != "" {
+			req.Header.Set("Authorization", "Bearer "+credential)
+		}
+		resp, err := srv.Client().Do(req)
+		if err != nil {
+			t.Fatalf("heartbeat: %v", err)
+		}
+		_ = readBody(t, resp)
+		return resp.StatusCode
+	}
+	if got := heartbeat("agent-a", "agent-a", enrolled.Credential); got != http.StatusOK {
+		t.Fatalf("enrolled heartbeat status=%d", got)
+	}
+	if got := heartbeat("agent-a", "", ""); got != http.StatusUnauthorized {
+		t.Fatalf("expected impersonation without credential to fail, got %d", got)
+	}
+	if got := heartbeat("agent-b", "agent-a", enrolled.Credential); got != http.StatusForbidden {
+		t.Fatalf("expected credential for another agent to be forbidden, got %d", got)
+	}
+	if got := heartbeat("agent-a", "agent-a", enrolled.Credential+"x"); got != http.StatusUnauthorized {
+		t.Fatalf("expected wrong credential to fail, got %d", got)
+	}
+	if got := heartbeat("agent-legacy", "", ""); got != http.StatusUnauthorized {
+		t.Fatalf("expected unenrolled agent to be rejected once enrollment is in use, got %d", got)
+	}
+
+	resp = mustJSONRequest(t, srv.Client(), http.MethodPost, srv.URL+"/api/v1/agents/agent-a/actions", map[string]any{"action": "revoke-credentials"})
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("revoke credentials: %d body=%s", resp.StatusCode, readBody(t, resp))
+	}
+	_ = readBody(t, resp)
+	if got := heartbeat("agent-a", "agent-a", enrolled.Credential); got != http.StatusUnauthorized {
+		t.Fatalf("expected revoked credential to fail, got %d", got)
+	}
+}
+
+func TestUnenrolledAgentIDCannotBeSpoofedByDefault(t *testing.T) {
+	_, s := newTestHTTPServerWithState(t)
+	srv := httptest.NewServer(buildRouter(s, s.artifactsDir))
+	defer srv.Close()
+
+	resp := mustJSONRequest(t, srv.Client(), http.MethodPost, srv.URL+"/api/v1/agent/lease", protocol.LeaseJobExecutionRequest{AgentID: "agent-legacy"})
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected an unenrolled lease to work before any agent enrolls, got %d body=%s", resp.StatusCode, readBody(t, resp))
+	}
+	_ = readBody(t, resp)
+
+	enrollTestAgent(t, s, "agent-a")
+	for _, agentID := range []string{"agent-legacy", "agent-made-up"} {
+		resp = mustJSONRequest(t, srv.Client(), http.MethodPost, srv.URL+"/api/v1/agent/lease", protocol.LeaseJobExecutionRequest{AgentID: agentID})
+		if resp.StatusCode != http.StatusUnauthorized {
+			t.Fatalf("expected lease as %s without a credential to be rejected, got %d body=%s", agentID, resp.StatusCode, readBody(t, resp))
+		}
+		_ = readBody(t, resp)
+		resp = mustJSONRequest(t, srv.Client(), http.MethodPost, srv.URL+"/api/v1/heartbeat", protocol.HeartbeatRequest{AgentID: agentID, Hostname: "h", OS: "linux", Arch: "amd64"})
+		if resp.StatusCode != http.StatusUnauthorized {
+			t.Fatalf("expected heartbeat as %s without a credential to be rejected, got %d body=%s", agentID, resp.StatusCode, readBody(t, resp))
+		}
+		_ = readBody(t, resp)
+	}
+}
+
+func TestAgentEnrollmentRequiredRejectsUnenrolledAgents(t *testing.T) {
+	t.Setenv(agentEnrollmentRequiredEnv, "true")
+	_, s := newTestHTTPServerWithState(t)
+	srv := httptest.NewServer(buildRouter(s, s.artifactsDir))
+	defer srv.Close()
+
+	resp := mustJSONRequest(t, srv.Client(), http.MethodPost, srv.URL+"/api/v1/agent/lease", protocol.LeaseJobExecutionRequest{AgentID: "agent-legacy"})
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("expected unenrolled lease to be rejected, got %d body=%s", resp.StatusCode, readBody(t, resp))
+	}
+	_ = readBody(t, resp)
+
+	resp = mustJSONRequest(t, srv.Client(), http.MethodPost, srv.URL+"/api/v1/jobs/job-1/status", protocol.JobExecutionStatusUpdateRequest{AgentID: "agent-legacy", Status: protocol.JobExecutionStatusRunning})
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("expected unenrolled status update to be rejected, got %d body=%s", resp.StatusCode, readBody(t, resp))
+	}
+	_ = readBody(t, resp)
+}
+
+func TestAgentIDHeaderWithoutCredentialNeedsUserAuthentication(t *testing.T) {
+	_, s := newTestHTTPServerWithState(t)
+	srv := httptest.NewServer(buildRouter(s, s.artifactsDir))
+	defer srv.Close()
+
+	resp := mustJSONRequest(t, srv.Client(), http.MethodPost, srv.URL+"/api/v1/auth/users", protocol.CreateUserRequest{Username: "root", Password: "correct horse"})
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("expected bootstrap admin 201, got %d body=%s", resp.StatusCode, readBody(t, resp))
+	}
+	_ = readBody(t, resp)
+
+	for _, path := range []string{"/api/v1/jobs/job-1", "/api/v1/jobs/job-1/artifacts", "/api/v1/jobs/job-1/artifacts/download-all", "/artifacts/job-1/out.txt"} {
+		req, _ := http.NewRequest(http.MethodGet, srv.URL+path, nil)
+		req.Header.Set(protocol.AgentIDHeader, "agent-made-up")
+		resp, err := srv.Client().Do(req)
+		if err != nil {
+			t.Fatalf("GET %s: %v", path, err)
+		}
+		_ = readBody(t, resp)
+		if resp.StatusCode != http.StatusUnauthorized {
+			t.Fatalf("expected GET %s with only an agent ID header to be rejected, got %d", path, resp.StatusCode)
+		}
+	}
+}
@@ -4,6 +4,9 @@ import (
 	"strings"
 	"sync"
 	"time"
+
+	"github.com/izzyreal/ciwi/internal/domain"
+	"github.com/izzyreal/ciwi/internal/protocol"
 )
 
 // agentRegistry owns all concurrent, process-local agent state. Persistence,
@@ -139,6 +142,26 @@ type agentState struct {
 	UpdateNextRetryUTC   time.Time         `json:"update_next_retry_utc,omitempty"`
 	UpdateLastError      string            `json:"update_last_error,omitempty"`
 	UpdateLastErrorUTC   time.Time         `json:"update_last_error_utc,omitempty"`
+	Slots                int               `json:"slots,omitempty"`
+	SlotsOverride        int               `json:"slots_override,omitempty"`
+	Labels               map[string]string `json:"labels,omitempty"`
+	AssignedLabels       map[string]string `json:"assigned_labels,omitempty"`
+}
+
+// effectiveLabels are the labels the agent declares with those an admin
+// assigned on top.
+func (a agentState) effectiveLabels() map[string]string {
+	return domain.MergeAgentLabels(a.Labels, a.AssignedLabels)
+}
+
+// jobSlots is how many jobs the agent may run at once: the operator override
+// when set, otherwise what the agent advertises.
+func (a agentState) jobSlots() int {
+	slots := a.Slots
+	if a.SlotsOverride > 0 {
+		slots = a.SlotsOverride
+	}
+	return min(max(slots, 1), protocol.MaxAgentJobSlots)
 }
 
 type agentUpdateRolloutState struct {
@@ -15,8 +15,13 @@ type agentView struct {
 	Authorized           bool                            `json:"authorized"`
 	Deactivated          bool                            `json:"deactivated,omitempty"`
 	JobInProgress        bool                            `json:"job_in_progress,omitempty"`
+	Slots                int                             `json:"slots"`
+	SlotsOverride        int                             `json:"slots_override,omitempty"`
+	ActiveJobs           int                             `json:"active_jobs"`
 	Version              string                          `json:"version,omitempty"`
 	Capabilities         map[string]string               `json:"capabilities"`
+	Labels               map[string]string               `json:"labels,omitempty"`
+	AssignedLabels       map[string]string               `json:"assigned_labels,omitempty"`
 	CanRunScript         bool                            `json:"can_run_script"`
 	ScriptShells         []presentation.AgentScriptShell `json:"script_shells"`
 	LastSeenUTC          time.Time                       `json:"last_seen_utc"`
@@ -56,7 +61,7 @@ type agentRunScriptResponse struct {
 	Notice         presentation.TransientNotice `json:"notice"`
 }
 
-func agentViewFromState(agentID string, state agentState, pendingTarget, serverVersion string, jobInProgress bool) agentView {
+func agentViewFromState(agentID string, state agentState, pendingTarget, serverVersion string, activeJobs int) agentView {
 	version := strings.TrimSpace(state.Version)
 	trimmedPendingTarget := strings.TrimSpace(pendingTarget)
 	trimmedStateTarget := strings.TrimSpace(state.UpdateTarget)
@@ -77,9 +82,14 @@ func agentViewFromState(agentID string, state agentState, pendingTarget, serverV
 		Arch:                 state.Arch,
 		Authorized:           state.Authorized,
 		Deactivated:          state.Deactivated,
-		JobInProgress:        jobInProgress,
+		JobInProgress:        activeJobs > 0,
+		Slots:                state.jobSlots(),
+		SlotsOverride:        state.SlotsOverride,
+		ActiveJobs:           activeJobs,
 		Version:              state.Version,
 		Capabilities:         cloneMap(state.Capabilities),
+		Labels:               state.effectiveLabels(),
+		AssignedLabels:       cloneMap(state.AssignedLabels),
 		CanRunScript:         len(scriptShells) > 0,
 		ScriptShells:         scriptShells,
 		LastSeenUTC:          state.LastSeenUTC,
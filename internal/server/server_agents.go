@@ -2,6 +2,7 @@ package server
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
 	"maps"
@@ -10,6 +11,7 @@ import (
 	"time"
 
 	"github.com/izzyreal/ciwi/internal/application"
+	"github.com/izzyreal/ciwi/internal/domain"
 	"github.com/izzyreal/ciwi/internal/protocol"
 	"github.com/izzyreal/ciwi/internal/server/jobexecution"
 )
@@ -60,7 +62,12 @@ func (s *stateStore) heartbeatHandler(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "agent_id is required", http.StatusBadRequest)
 		return
 	}
+	if err := s.authorizeAgentCaller(r, hb.AgentID); err != nil {
+		http.Error(w, err.Error(), applicationErrorHTTPStatus(err))
+		return
+	}
 	now := time.Now().UTC()
+	declaredLabels, labelsErr := domain.NormalizeAgentLabels(hb.Labels)
 	hasActiveJob := false
 	if active, err := s.agentJobExecutionStore().AgentHasActiveJobExecution(hb.AgentID); err == nil {
 		hasActiveJob = active
@@ -234,6 +241,13 @@ func (s *stateStore) heartbeatHandler(w http.ResponseWriter, r *http.Request) {
 		UpdateNextRetryUTC:   prev.UpdateNextRetryUTC,
 		UpdateLastError:      prev.UpdateLastError,
 		UpdateLastErrorUTC:   prev.UpdateLastErrorUTC,
+		Slots:                hb.Slots,
+		SlotsOverride:        prev.SlotsOverride,
+		Labels:               declaredLabels,
+		AssignedLabels:       prev.AssignedLabels,
+	}
+	if labelsErr != nil {
+		state.RecentLog = appendAgentLog(state.RecentLog, "ignored declared labels: "+labelsErr.Error())
 	}
 	state.RecentLog = appendAgentLog(state.RecentLog, fmt.Sprintf("heartbeat version=%s platform=%s/%s", strings.TrimSpace(hb.Version), strings.TrimSpace(hb.OS), strings.TrimSpace(hb.Arch)))
 	if refreshTools {
@@ -284,7 +298,9 @@ func (s *stateStore) heartbeatHandler(w http.ResponseWriter, r *http.Request) {
 
 	resp := protocol.HeartbeatResponse{
 		Accepted: true,
+		Slots:    state.jobSlots(),
 	}
+	resp.CancelJobExecutionIDs = s.jobExecutionsToStop(hb.AgentID, hb.RunningJobExecutionIDs)
 	if refreshTools {
 		resp.RefreshToolsRequested = true
 	}
@@ -307,9 +323,34 @@ func (s *stateStore) heartbeatHandler(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, http.StatusOK, resp)
 }
 
+// jobExecutionsToStop picks the reported running jobs the agent should no
+// longer be executing: cancelled or otherwise finished jobs, and jobs that
+// were removed or handed to another agent.
+func (s *stateStore) jobExecutionsToStop(agentID string, running []string) []string {
+	var stop []string
+	for _, id := range running {
+		id = strings.TrimSpace(id)
+		if id == "" {
+			continue
+		}
+		job, err := s.jobExecutionStore().GetJobExecution(id)
+		if err != nil {
+			if !errors.Is(err, domain.ErrJobExecutionNotFound) {
+				slog.Warn("check running job failed", "agent_id", agentID, "job_execution_id", id, "error", err)
+				continue
+			}
+		} else if !protocol.IsTerminalJobExecutionStatus(job.Status) && strings.TrimSpace(job.LeasedByAgentID) == agentID {
+			continue
+		}
+		stop = append(stop, id)
+	}
+	return stop
+}
+
 func agentEligibilityChanged(previous, current agentState) bool {
 	return previous.OS != current.OS || previous.Arch != current.Arch || previous.Authorized != current.Authorized ||
-		previous.Deactivated != current.Deactivated || !maps.Equal(previous.Capabilities, current.Capabilities)
+		previous.Deactivated != current.Deactivated || previous.jobSlots() != current.jobSlots() ||
+		!maps.Equal(previous.Capabilities, current.Capabilities) || !maps.Equal(previous.effectiveLabels(), current.effectiveLabels())
 }
 
 func summarizeUpdateFailure(raw string) string {
@@ -349,11 +390,11 @@ func (s *stateStore) listAgentsHandler(w http.ResponseWriter, r *http.Request) {
 	serverVersion := currentVersion()
 	agents := make([]agentView, 0, len(snapshots))
 	for _, snap := range snapshots {
-		jobInProgress, err := s.agentJobExecutionStore().AgentHasActiveJobExecution(snap.ID)
+		activeJobs, err := s.agentJobExecutionStore().CountAgentActiveJobExecutions(snap.ID)
 		if err != nil {
-			jobInProgress = false
+			activeJobs = 0
 		}
-		agents = append(agents, agentViewFromState(snap.ID, snap.State, snap.PendingUpdate, serverVersion, jobInProgress))
+		agents = append(agents, agentViewFromState(snap.ID, snap.State, snap.PendingUpdate, serverVersion, activeJobs))
 	}
 	writeJSON(w, http.StatusOK, agentsViewResponse{Agents: agents})
 }
@@ -372,6 +413,10 @@ func (s *stateStore) leaseJobHandler(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "agent_id is required", http.StatusBadRequest)
 		return
 	}
+	if err := s.authorizeAgentCaller(r, req.AgentID); err != nil {
+		http.Error(w, err.Error(), applicationErrorHTTPStatus(err))
+		return
+	}
 
 	agentCaps := req.Capabilities
 	s.mu.Lock()
@@ -417,25 +462,30 @@ func (s *stateStore) leaseJobHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	agentCaps = mergeCapabilities(a, req.Capabilities)
+	slots := a.jobSlots()
 	s.mu.Unlock()
 	if agentCaps == nil {
 		agentCaps = map[string]string{}
 	}
 	agentCaps["agent_id"] = req.AgentID
-	hasActive, err := s.agentJobExecutionStore().AgentHasActiveJobExecution(req.AgentID)
+	active, err := s.agentJobExecutionStore().CountAgentActiveJobExecutions(req.AgentID)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
-	if hasActive {
+	if active >= slots {
+		message := "agent already has an active job"
+		if slots > 1 {
+			message = fmt.Sprintf("all %d agent job slots are busy", slots)
+		}
 		writeJSON(w, http.StatusOK, jobexecution.LeaseViewResponse{
 			Assigned: false,
-			Message:  "agent already has an active job",
+			Message:  message,
 		})
 		return
 	}
 
-	job, err := s.agentJobExecutionStore().LeaseJobExecution(req.AgentID, agentCaps)
+	job, err := s.agentJobExecutionStore().LeaseJobExecutionWithAffinity(req.AgentID, agentCaps, s.preferredAgentEligible)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -445,6 +495,21 @@ func (s *stateStore) leaseJobHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	slog.Info("job leased to agent", "job_execution_id", job.ID, "agent_id", req.AgentID)
+	if jobNeedsSecrets(*job) && s.authorizeEnrolledAgentCaller(r, req.AgentID) != nil {
+		// Resolved Vault secrets only go to agents that present their
+		// credential, like source credentials.
+		reason := "Waiting for an enrolled agent: Vault secrets are only sent to agents that present their credential"
+		if _, err := s.agentJobExecutionStore().RequeueLeasedJobExecution(job.ID, req.AgentID, map[string]string{
+			protocol.JobSchedulingBlockedMetadataKey:       "1",
+			protocol.JobSchedulingBlockedReasonMetadataKey: reason,
+			protocol.JobSchedulingRetryUTCMetadataKey:      time.Now().UTC().Add(5 * time.Second).Format(time.RFC3339Nano),
+		}); err != nil {
+			http.Error(w, fmt.Sprintf("record enrollment blocker: %v", err), http.StatusInternalServerError)
+			return
+		}
+		writeJSON(w, http.StatusOK, jobexecution.LeaseViewResponse{Assigned: false, Message: reason})
+		return
+	}
 	if err := s.resolveJobSecrets(r.Context(), job); err != nil {
 		if reason, retryable := transientVaultSchedulingReason(err); retryable {
 			retryUTC := time.Now().UTC().Add(5 * time.Second).Format(time.RFC3339Nano)
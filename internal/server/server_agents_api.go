@@ -42,11 +42,11 @@ func (s *stateStore) agentDetailsHandler(w http.ResponseWriter, r *http.Request,
 		http.Error(w, "agent not found", http.StatusNotFound)
 		return
 	}
-	jobInProgress, err := s.agentJobExecutionStore().AgentHasActiveJobExecution(agentID)
+	activeJobs, err := s.agentJobExecutionStore().CountAgentActiveJobExecutions(agentID)
 	if err != nil {
-		jobInProgress = false
+		activeJobs = 0
 	}
-	writeJSON(w, http.StatusOK, agentViewResponse{Agent: agentViewFromState(agentID, snapshot.State, snapshot.PendingUpdate, currentVersion(), jobInProgress)})
+	writeJSON(w, http.StatusOK, agentViewResponse{Agent: agentViewFromState(agentID, snapshot.State, snapshot.PendingUpdate, currentVersion(), activeJobs)})
 }
 
 func (s *stateStore) agentActionHandler(w http.ResponseWriter, r *http.Request, agentID string) {
@@ -59,10 +59,12 @@ func (s *stateStore) agentActionHandler(w http.ResponseWriter, r *http.Request,
 		return
 	}
 	var req struct {
-		Action         string `json:"action"`
-		Script         string `json:"script"`
-		Shell          string `json:"shell"`
-		TimeoutSeconds int    `json:"timeout_seconds"`
+		Action         string            `json:"action"`
+		Slots          int               `json:"slots"`
+		Labels         map[string]string `json:"labels"`
+		Script         string            `json:"script"`
+		Shell          string            `json:"shell"`
+		TimeoutSeconds int               `json:"timeout_seconds"`
 	}
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		http.Error(w, "invalid JSON body", http.StatusBadRequest)
@@ -90,7 +92,7 @@ func (s *stateStore) agentActionHandler(w http.ResponseWriter, r *http.Request,
 		return
 	}
 	result, err := s.app().agentCommands.Execute(r.Context(), application.AgentActionRequest{
-		AgentID: agentID, Action: action, IdempotencyKey: strings.TrimSpace(r.Header.Get("Idempotency-Key")),
+		AgentID: agentID, Action: action, Slots: req.Slots, Labels: req.Labels, IdempotencyKey: strings.TrimSpace(r.Header.Get("Idempotency-Key")),
 	})
 	if err != nil {
 		http.Error(w, err.Error(), applicationErrorHTTPStatus(err))
@@ -2,6 +2,7 @@ package server
 
 import (
 	"net/http"
+	"slices"
 	"strings"
 	"testing"
 	"time"
@@ -339,6 +340,184 @@ func TestAgentLeaseHandlerValidationAndBranches(t *testing.T) {
 	}
 }
 
+func TestAgentLeaseFillsAdvertisedSlotsAndHonoursOverride(t *testing.T) {
+	ts := newTestHTTPServer(t)
+	defer ts.Close()
+	client := ts.Client()
+
+	hbResp := mustJSONRequest(t, client, http.MethodPost, ts.URL+"/api/v1/heartbeat", map[string]any{
+		"agent_id":      "agent-slots",
+		"hostname":      "host-slots",
+		"os":            "linux",
+		"arch":          "amd64",
+		"version":       "v1.0.0",
+		"capabilities":  map[string]string{"executor": "script", "shells": "posix"},
+		"slots":         2,
+		"timestamp_utc": "2026-02-12T00:00:00Z",
+	})
+	if hbResp.StatusCode != http.StatusOK {
+		t.Fatalf("heartbeat status=%d body=%s", hbResp.StatusCode, readBody(t, hbResp))
+	}
+	var hbPayload struct {
+		Slots int `json:"slots"`
+	}
+	decodeJSONBody(t, hbResp, &hbPayload)
+	if hbPayload.Slots != 2 {
+		t.Fatalf("expected heartbeat to confirm 2 slots, got %d", hbPayload.Slots)
+	}
+
+	authResp := mustJSONRequest(t, client, http.MethodPost, ts.URL+"/api/v1/agents/agent-slots/actions", map[string]any{"action": "authorize"})
+	if authResp.StatusCode != http.StatusOK {
+		t.Fatalf("authorize status=%d body=%s", authResp.StatusCode, readBody(t, authResp))
+	}
+	_ = readBody(t, authResp)
+
+	for i := 0; i < 3; i++ {
+		runResp := mustJSONRequest(t, client, http.MethodPost, ts.URL+"/api/v1/agents/agent-slots/actions", map[string]any{
+			"action": "run-script",
+			"shell":  "posix",
+			"script": "echo slot",
+		})
+		if runResp.StatusCode != http.StatusCreated {
+			t.Fatalf("run-script %d status=%d body=%s", i, runResp.StatusCode, readBody(t, runResp))
+		}
+		_ = readBody(t, runResp)
+	}
+
+	lease := func() (bool, string) {
+		t.Helper()
+		resp := mustJSONRequest(t, client, http.MethodPost, ts.URL+"/api/v1/agent/lease", map[string]any{"agent_id": "agent-slots"})
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("lease status=%d body=%s", resp.StatusCode, readBody(t, resp))
+		}
+		var payload struct {
+			Assigned bool   `json:"assigned"`
+			Message  string `json:"message"`
+		}
+		decodeJSONBody(t, resp, &payload)
+		return payload.Assigned, payload.Message
+	}
+	for i := 0; i < 2; i++ {
+		if assigned, message := lease(); !assigned {
+			t.Fatalf("expected lease %d to fill a free slot, got message %q", i, message)
+		}
+	}
+	if assigned, message := lease(); assigned || !strings.Contains(message, "all 2 agent job slots are busy") {
+		t.Fatalf("expected third lease to be refused, got assigned=%v message=%q", assigned, message)
+	}
+
+	setResp := mustJSONRequest(t, client, http.MethodPost, ts.URL+"/api/v1/agents/agent-slots/actions", map[string]any{"action": "set-slots", "slots": 3})
+	if setResp.StatusCode != http.StatusOK {
+		t.Fatalf("set-slots status=%d body=%s", setResp.StatusCode, readBody(t, setResp))
+	}
+	_ = readBody(t, setResp)
+	if assigned, message := lease(); !assigned {
+		t.Fatalf("expected override to open a third slot, got message %q", message)
+	}
+
+	detailResp := mustJSONRequest(t, client, http.MethodGet, ts.URL+"/api/v1/agents/agent-slots", nil)
+	if detailResp.StatusCode != http.StatusOK {
+		t.Fatalf("agent detail status=%d body=%s", detailResp.StatusCode, readBody(t, detailResp))
+	}
+	var detail agentViewResponse
+	decodeJSONBody(t, detailResp, &detail)
+	if detail.Agent.Slots != 3 || detail.Agent.SlotsOverride != 3 || detail.Agent.ActiveJobs != 3 {
+		t.Fatalf("unexpected slot view: %+v", detail.Agent)
+	}
+
+	invalidResp := mustJSONRequest(t, client, http.MethodPost, ts.URL+"/api/v1/agents/agent-slots/actions", map[string]any{"action": "set-slots", "slots": 65})
+	if invalidResp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("expected out-of-range slots to be rejected, got %d body=%s", invalidResp.StatusCode, readBody(t, invalidResp))
+	}
+	_ = readBody(t, invalidResp)
+}
+
+func TestAgentLabelsGateLeases(t *testing.T) {
+	ts, s := newTestHTTPServerWithState(t)
+	defer ts.Close()
+	client := ts.Client()
+
+	hbResp := mustJSONRequest(t, client, http.MethodPost, ts.URL+"/api/v1/heartbeat", map[string]any{
+		"agent_id":      "agent-labels",
+		"hostname":      "host-labels",
+		"os":            "linux",
+		"arch":          "amd64",
+		"version":       "v1.0.0",
+		"capabilities":  map[string]string{"executor": "script", "shells": "posix"},
+		"labels":        map[string]string{"site": "lab", "dongle": "no"},
+		"timestamp_utc": "2026-02-12T00:00:00Z",
+	})
+	if hbResp.StatusCode != http.StatusOK {
+		t.Fatalf("heartbeat status=%d body=%s", hbResp.StatusCode, readBody(t, hbResp))
+	}
+	_ = readBody(t, hbResp)
+	authResp := mustJSONRequest(t, client, http.MethodPost, ts.URL+"/api/v1/agents/agent-labels/actions", map[string]any{"action": "authorize"})
+	if authResp.StatusCode != http.StatusOK {
+		t.Fatalf("authorize status=%d body=%s", authResp.StatusCode, readBody(t, authResp))
+	}
+	_ = readBody(t, authResp)
+
+	job, err := s.db.CreateJobExecution(protocol.CreateJobExecutionRequest{
+		Script: "./flash", TimeoutSeconds: 30,
+		RequiredCapabilities: map[string]string{"os": "linux", "label.dongle": "yes", "label.site": "lab"},
+	})
+	if err != nil {
+		t.Fatalf("create job: %v", err)
+	}
+	queued := protocol.JobExecution{ID: job.ID, Status: protocol.JobExecutionStatusQueued, RequiredCapabilities: job.RequiredCapabilities}
+	s.attachJobExecutionSchedulingDiagnosis(&queued)
+	if diagnosis := queued.SchedulingDiagnosis; diagnosis == nil || diagnosis.State != "incompatible" {
+		t.Fatalf("expected the declared dongle=no label to rule the agent out, got %+v", diagnosis)
+	}
+
+	lease := func() bool {
+		t.Helper()
+		resp := mustJSONRequest(t, client, http.MethodPost, ts.URL+"/api/v1/agent/lease", map[string]any{"agent_id": "agent-labels"})
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("lease status=%d body=%s", resp.StatusCode, readBody(t, resp))
+		}
+		var payload struct {
+			Assigned bool `json:"assigned"`
+		}
+		decodeJSONBody(t, resp, &payload)
+		return payload.Assigned
+	}
+	if lease() {
+		t.Fatalf("expected the label requirement to block the lease")
+	}
+
+	setResp := mustJSONRequest(t, client, http.MethodPost, ts.URL+"/api/v1/agents/agent-labels/actions", map[string]any{
+		"action": "set-labels",
+		"labels": map[string]string{"dongle": "yes"},
+	})
+	if setResp.StatusCode != http.StatusOK {
+		t.Fatalf("set-labels status=%d body=%s", setResp.StatusCode, readBody(t, setResp))
+	}
+	_ = readBody(t, setResp)
+	if !lease() {
+		t.Fatalf("expected the assigned label to satisfy the requirement")
+	}
+
+	detailResp := mustJSONRequest(t, client, http.MethodGet, ts.URL+"/api/v1/agents/agent-labels", nil)
+	if detailResp.StatusCode != http.StatusOK {
+		t.Fatalf("agent detail status=%d body=%s", detailResp.StatusCode, readBody(t, detailResp))
+	}
+	var detail agentViewResponse
+	decodeJSONBody(t, detailResp, &detail)
+	if detail.Agent.Labels["dongle"] != "yes" || detail.Agent.Labels["site"] != "lab" || len(detail.Agent.AssignedLabels) != 1 {
+		t.Fatalf("unexpected label view: labels=%v assigned=%v", detail.Agent.Labels, detail.Agent.AssignedLabels)
+	}
+
+	invalidResp := mustJSONRequest(t, client, http.MethodPost, ts.URL+"/api/v1/agents/agent-labels/actions", map[string]any{
+		"action": "set-labels",
+		"labels": map[string]string{"bad key": "x"},
+	})
+	if invalidResp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("expected malformed label key to be rejected, got %d body=%s", invalidResp.StatusCode, readBody(t, invalidResp))
+	}
+	_ = readBody(t, invalidResp)
+}
+
 func TestAgentDeactivationBlocksLeaseUntilActivated(t *testing.T) {
 	ts := newTestHTTPServer(t)
 	defer ts.Close()
@@ -500,11 +679,11 @@ func TestAgentDeactivationCancelsActiveJob(t *testing.T) {
 		} `json:"job_execution"`
 	}
 	decodeJSONBody(t, jobResp, &jobPayload)
-	if jobPayload.Job.Status != "failed" {
-		t.Fatalf("expected failed status, got %q", jobPayload.Job.Status)
+	if jobPayload.Job.Status != "cancelled" {
+		t.Fatalf("expected cancelled status, got %q", jobPayload.Job.Status)
 	}
-	if jobPayload.Job.Error != "cancelled by user" {
-		t.Fatalf("expected cancelled by user error, got %q", jobPayload.Job.Error)
+	if jobPayload.Job.Error != "cancelled by user: agent agent-cancel-on-deactivate deactivated" {
+		t.Fatalf("expected deactivation cancellation error, got %q", jobPayload.Job.Error)
 	}
 	eventsResp := mustJSONRequest(t, client, http.MethodGet, ts.URL+"/api/v1/jobs/"+runPayload.JobExecutionID+"/events", nil)
 	if eventsResp.StatusCode != http.StatusOK {
@@ -514,7 +693,25 @@ func TestAgentDeactivationCancelsActiveJob(t *testing.T) {
 		Events []protocol.JobExecutionEvent `json:"events"`
 	}
 	decodeJSONBody(t, eventsResp, &eventsPayload)
-	if len(eventsPayload.Events) != 1 || !strings.Contains(eventsPayload.Events[0].Message, "[control] job cancelled by user") {
+	if len(eventsPayload.Events) != 1 || !strings.Contains(eventsPayload.Events[0].Message, "[control] job cancelled by user: agent agent-cancel-on-deactivate deactivated") {
 		t.Fatalf("expected cancel event, got %+v", eventsPayload.Events)
 	}
+	stopResp := mustJSONRequest(t, client, http.MethodPost, ts.URL+"/api/v1/heartbeat", map[string]any{
+		"agent_id":                  "agent-cancel-on-deactivate",
+		"hostname":                  "host-cancel-on-deactivate",
+		"os":                        "linux",
+		"arch":                      "amd64",
+		"version":                   "v1.0.0",
+		"capabilities":              map[string]string{"executor": "script", "shells": "posix"},
+		"running_job_execution_ids": []string{runPayload.JobExecutionID, "job-unknown"},
+		"timestamp_utc":             "2026-02-12T00:00:10Z",
+	})
+	if stopResp.StatusCode != http.StatusOK {
+		t.Fatalf("heartbeat status=%d body=%s", stopResp.StatusCode, readBody(t, stopResp))
+	}
+	var stopPayload protocol.HeartbeatResponse
+	decodeJSONBody(t, stopResp, &stopPayload)
+	if !slices.Equal(stopPayload.CancelJobExecutionIDs, []string{runPayload.JobExecutionID, "job-unknown"}) {
+		t.Fatalf("expected heartbeat to stop cancelled and unknown jobs, got %v", stopPayload.CancelJobExecutionIDs)
+	}
 }
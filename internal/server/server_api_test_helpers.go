@@ -2,6 +2,7 @@ package server
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"io"
 	"net/http"
@@ -12,6 +13,8 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/izzyreal/ciwi/internal/application"
+	"github.com/izzyreal/ciwi/internal/protocol"
 	"github.com/izzyreal/ciwi/internal/store"
 )
 
@@ -102,6 +105,8 @@ func newTestHTTPServerWithState(t *testing.T) (*httptest.Server, *stateStore) {
 	mux.HandleFunc("/api/v1/agent/lease", s.leaseJobHandler)
 	mux.HandleFunc("/api/v1/vault/connections", s.vaultConnectionsHandler)
 	mux.HandleFunc("/api/v1/vault/connections/", s.vaultConnectionByIDHandler)
+	mux.HandleFunc("/api/v1/repo-credentials", s.repoCredentialsHandler)
+	mux.HandleFunc("/api/v1/repo-credentials/", s.repoCredentialByIDHandler)
 	mux.HandleFunc("/api/v1/update/check", s.updateCheckHandler)
 	mux.HandleFunc("/api/v1/update/apply", s.updateApplyHandler)
 	mux.HandleFunc("/api/v1/update/rollback", s.updateRollbackHandler)
@@ -135,6 +140,45 @@ func mustJSONRequest(t *testing.T, client *http.Client, method, url string, body
 	return resp
 }
 
+// enrollTestAgent enrolls agentID with a fresh token and returns its
+// credential.
+func enrollTestAgent(t *testing.T, s *stateStore, agentID string) string {
+	t.Helper()
+	token, err := s.app().agentCredentials.CreateEnrollmentToken(context.Background(), application.CreateEnrollmentTokenRequest{Description: agentID})
+	if err != nil {
+		t.Fatalf("create enrollment token: %v", err)
+	}
+	enrolled, err := s.app().agentCredentials.Enroll(context.Background(), application.EnrollAgentRequest{AgentID: agentID, Token: token.Secret})
+	if err != nil {
+		t.Fatalf("enroll %s: %v", agentID, err)
+	}
+	return enrolled.Credential
+}
+
+// mustAgentJSONRequest sends body as agentID, with its credential when one
+// is given.
+func mustAgentJSONRequest(t *testing.T, client *http.Client, method, url, agentID, credential string, body any) *http.Response {
+	t.Helper()
+	data, err := json.Marshal(body)
+	if err != nil {
+		t.Fatalf("marshal request JSON: %v", err)
+	}
+	req, err := http.NewRequest(method, url, bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("new request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(protocol.AgentIDHeader, agentID)
+	if credential != "" {
+		req.Header.Set("Authorization", "Bearer "+credential)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("do request: %v", err)
+	}
+	return resp
+}
+
 func mustRawJSONRequest(t *testing.T, client *http.Client, method, url, body string) *http.Response {
 	t.Helper()
 	req, err := http.NewRequest(method, url, strings.NewReader(body))
@@ -36,7 +36,14 @@ type serverApplication struct {
 	frontPage         *presentation.FrontPageQueries
 	projectDetails    *presentation.ProjectDetailsQueries
 	jobDetails        *presentation.JobDetailsQueries
+	testHistory       *presentation.TestHistoryQueries
 	changes           *application.ChangeHub
+	accounts          *application.AccountService
+	agentCredentials  *application.AgentCredentialService
+	testQuarantine    *application.TestQuarantineService
+	vault             *application.VaultConnections
+	repoCredentials   *application.RepoCredentials
+	authorizer        *application.Authorizer
 }
 
 type localServerInfoSource struct{ installationID string }
@@ -66,31 +73,47 @@ func newServerApplication(s *stateStore) *serverApplication {
 	agentQueries := application.NewAgentQueries(agentRepositoryAdapter{state: s})
 	changes := application.NewChangeHub()
 	receipts := sqliteadapter.NewCommandReceiptRepository(s.db)
+	accounts := application.NewAccountService(sqliteadapter.NewAuthRepository(s.db))
+	authorizer := application.NewAuthorizer(accounts)
+	testQuarantine := application.NewTestQuarantineService(sqliteadapter.NewTestQuarantineRepository(s.db), receipts, changes, authorizer)
+	coverageTrends := application.NewCoverageTrendQueries(executionRepository, authorizer)
 	frontPageQueries := presentation.NewFrontPageQueriesWithObserver(serverQueries, projectQueries, executionQueries, observeFrontPageTiming)
 	return &serverApplication{
 		server:          serverQueries,
 		projects:        projectQueries,
-		projectCommands: application.NewProjectCommands(projectMutatorAdapter{state: s}, receipts, changes),
-		updates:         application.NewServerUpdateOperations(serverUpdateAdapter{state: s}, changes, receipts),
+		projectCommands: application.NewProjectCommands(projectMutatorAdapter{state: s}, receipts, changes, authorizer),
+		updates:         application.NewServerUpdateOperations(serverUpdateAdapter{state: s}, changes, authorizer, receipts),
 		pipelines: application.NewPipelineCommands(
 			pipelineRunnerAdapter{state: s},
 			receipts,
 			changes,
+			authorizer,
 		),
-		pipelineChains:    application.NewPipelineChainCommands(pipelineChainRunnerAdapter{state: s}, receipts, changes),
+		pipelineChains:    application.NewPipelineChainCommands(pipelineChainRunnerAdapter{state: s}, receipts, changes, authorizer),
 		runOptions:        application.NewRunOptionsQueries(runOptionsAdapter{state: s}),
 		agents:            presentation.NewAgentsQueries(agentQueries),
-		agentCommands:     application.NewAgentCommands(agentMutatorAdapter{state: s}, receipts, changes),
-		agentScripts:      application.NewAgentScriptCommands(agentScriptMutatorAdapter{state: s}, receipts, changes),
+		agentCommands:     application.NewAgentCommands(agentMutatorAdapter{state: s}, receipts, changes, authorizer),
+		agentScripts:      application.NewAgentScriptCommands(agentScriptMutatorAdapter{state: s}, receipts, changes, authorizer),
 		executions:        executionQueries,
-		executionCommands: application.NewExecutionCommands(executionMutatorAdapter{state: s}, receipts, changes),
-		executionControls: application.NewExecutionControlCommands(executionControllerAdapter{state: s}, receipts, changes),
+		executionCommands: application.NewExecutionCommands(executionMutatorAdapter{state: s}, receipts, changes, authorizer),
+		executionControls: application.NewExecutionControlCommands(executionControllerAdapter{state: s}, receipts, changes, authorizer),
 		commandReceipts:   application.NewCommandReceiptQueries(receipts),
 		receipts:          receipts,
 		frontPage:         frontPageQueries,
-		projectDetails:    presentation.NewProjectDetailsQueries(projectQueries, executionQueries),
+		projectDetails:    presentation.NewProjectDetailsQueries(projectQueries, executionQueries).WithTestQuarantine(testQuarantine).WithCoverageTrends(coverageTrends),
 		jobDetails:        presentation.NewJobDetailsQueries(executionQueries),
+		testHistory:       presentation.NewTestHistoryQueries(application.NewTestHistoryQueries(executionRepository)),
 		changes:           changes,
+		testQuarantine:    testQuarantine,
+		accounts:          accounts,
+		vault:             application.NewVaultConnections(vaultBackendAdapter{state: s}, changes, authorizer),
+		repoCredentials:   application.NewRepoCredentials(repoCredentialRepositoryAdapter{state: s}, authorizer),
+		authorizer:        authorizer,
+		agentCredentials: application.NewAgentCredentialService(
+			sqliteadapter.NewAgentCredentialRepository(s.db),
+			strings.TrimSpace(envOrDefault(agentEnrollmentRequiredEnv, "false")) == "true",
+			authorizer,
+		),
 	}
 }
 
@@ -155,6 +178,26 @@ func (s executionDetailsStore) SearchJobLog(jobID, query string, selectedIndex i
 	return store.SearchJobLog(jobID, query, selectedIndex)
 }
 
+func (s executionDetailsStore) ListTestCaseRuns(scope domain.TestHistoryScope, executionLimit int) ([]domain.TestCaseRun, error) {
+	store, ok := s.Store.(interface {
+		ListTestCaseRuns(domain.TestHistoryScope, int) ([]domain.TestCaseRun, error)
+	})
+	if !ok {
+		return nil, nil
+	}
+	return store.ListTestCaseRuns(scope, executionLimit)
+}
+
+func (s executionDetailsStore) ListCoverageRuns(projectID int64, perScopeLimit int) ([]domain.CoverageRun, error) {
+	store, ok := s.Store.(interface {
+		ListCoverageRuns(int64, int) ([]domain.CoverageRun, error)
+	})
+	if !ok {
+		return nil, nil
+	}
+	return store.ListCoverageRuns(projectID, perScopeLimit)
+}
+
 func (s executionDetailsStore) ListJobExecutionArtifacts(jobID string) ([]protocol.JobExecutionArtifact, error) {
 	artifacts, err := s.Store.ListJobExecutionArtifacts(jobID)
 	if err != nil {
@@ -180,8 +223,9 @@ func (a pipelineChainRunnerAdapter) RunPipelineChain(ctx context.Context, reques
 	selection := &protocol.RunPipelineSelectionRequest{
 		PipelineJobID: request.PipelineJobID, MatrixName: request.MatrixName, MatrixIndex: request.MatrixIndex,
 		DryRun: request.DryRun, SourceRef: request.SourceRef, AgentID: request.AgentID, ExecutionMode: request.ExecutionMode,
+		Priority: request.Priority,
 	}
-	result, err := a.state.enqueuePersistedPipelineChain(chain, selection)
+	result, err := a.state.enqueueTriggeredPipelineChain(chain, selection, pipelineTrigger{Kind: strings.TrimSpace(request.Trigger)})
 	if err != nil {
 		return application.RunPipelineChainResult{}, application.NewError(application.ErrorInvalidArgument, err.Error(), err)
 	}
@@ -199,7 +243,7 @@ func (a executionControllerAdapter) CancelExecution(ctx context.Context, jobID s
 	if err := ctx.Err(); err != nil {
 		return application.CancelExecutionResult{}, err
 	}
-	job, err := jobexecution.CancelJobExecution(a.state.jobExecutionStore(), jobID, time.Now().UTC())
+	job, err := jobexecution.CancelJobExecution(a.state.jobExecutionStore(), jobID, time.Now().UTC(), domain.ExecutionCancellation{By: application.ActorName(ctx)})
 	if err != nil {
 		return application.CancelExecutionResult{}, executionControlError(err)
 	}
@@ -299,8 +343,10 @@ func (a pipelineRunnerAdapter) RunPipeline(ctx context.Context, request applicat
 		SourceRef:     request.SourceRef,
 		AgentID:       request.AgentID,
 		ExecutionMode: request.ExecutionMode,
+		Priority:      request.Priority,
 	}
-	result, err := a.state.enqueuePersistedPipeline(pipeline, selection)
+	trigger := pipelineTrigger{Kind: strings.TrimSpace(request.Trigger), Commit: strings.TrimSpace(request.SourceCommit)}
+	result, err := a.state.enqueueTriggeredPipeline(pipeline, selection, trigger)
 	if err != nil {
 		return application.RunPipelineResult{}, application.NewError(application.ErrorInvalidArgument, err.Error(), err)
 	}
@@ -20,6 +20,10 @@ func applicationErrorHTTPStatus(err error) int {
 		return http.StatusServiceUnavailable
 	case application.ErrorUnsupported:
 		return http.StatusNotImplemented
+	case application.ErrorUnauthenticated:
+		return http.StatusUnauthorized
+	case application.ErrorPermissionDenied:
+		return http.StatusForbidden
 	default:
 		return http.StatusInternalServerError
 	}
@@ -0,0 +1,477 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/izzyreal/ciwi/internal/application"
+	"github.com/izzyreal/ciwi/internal/domain"
+	"github.com/izzyreal/ciwi/internal/protocol"
+)
+
+const sessionCookieName = "ciwi_session"
+
+// routeAccess describes how a request is authenticated once the server has
+// user accounts. Public routes are either static assets or authenticate on
+// their own (login, signed webhooks, agent handlers). Agent routes also
+// accept agent credentials. Account routes are authorized by the account
+// service. For everything else the operation decides the action a user
+// needs, through the same table as native CNP requests.
+type routeAccess struct {
+	public    bool
+	page      bool
+	agent     bool
+	accounts  bool
+	operation application.Operation
+}
+
+func routeAccessFor(r *http.Request) routeAccess {
+	path := strings.TrimSuffix(r.URL.Path, "/")
+	if path == "" {
+		path = "/"
+	}
+	read := r.Method == http.MethodGet || r.Method == http.MethodHead
+	switch {
+	case path == "/healthz", path == "/login", path == "/favicon.ico", path == "/ciwi-favicon.png", path == "/ciwi-logo.png",
+		strings.HasPrefix(path, "/ui/"):
+		return routeAccess{public: true}
+	case strings.HasPrefix(path, "/api/v1/hooks/"):
+		return routeAccess{public: true}
+	case path == "/api/v1/auth/login", path == "/api/v1/auth/logout", path == "/api/v1/auth/session":
+		return routeAccess{public: true}
+	case strings.HasPrefix(path, "/api/v1/auth/"):
+		// The account service decides per operation, e.g. users may
+		// change their own password.
+		return routeAccess{accounts: true}
+	case path == "/api/v1/agent/enroll":
+		return routeAccess{public: true}
+	case isAgentRoute(r.Method, path):
+		return routeAccess{public: true, agent: true}
+	case read && isAgentReadRoute(path):
+		return routeAccess{agent: true, operation: application.OperationRead}
+	case !strings.HasPrefix(path, "/api/") && !strings.HasPrefix(path, "/artifacts/"):
+		return routeAccess{page: true, operation: application.OperationRead}
+	case read:
+		return routeAccess{operation: application.OperationRead}
+	default:
+		return routeAccess{operation: writeRouteOperation(r.Method, path)}
+	}
+}
+
+// isAgentRoute reports whether the request is one only agents make while
+// leasing and running jobs. Their handlers check the agent's credential.
+func isAgentRoute(method, path string) bool {
+	if method != http.MethodPost {
+		return false
+	}
+	if path == "/api/v1/heartbeat" || path == "/api/v1/agent/lease" {
+		return true
+	}
+	rel, ok := strings.CutPrefix(path, "/api/v1/jobs/")
+	if !ok {
+		return false
+	}
+	parts := strings.Split(rel, "/")
+	return (len(parts) == 2 && (parts[1] == "status" || parts[1] == "tests" || parts[1] == "source-credential")) ||
+		(len(parts) == 3 && parts[1] == "artifacts" && parts[2] == "upload-zip")
+}
+
+// isAgentReadRoute reports whether a read is shared by agents, which fetch
+// job state and dependency artifacts, and the web UI. Once the server has
+// user accounts, only enrolled agents with a valid credential read them as
+// agents; an agent ID header alone proves nothing.
+func isAgentReadRoute(path string) bool {
+	if strings.HasPrefix(path, "/artifacts/") {
+		return true
+	}
+	rel, ok := strings.CutPrefix(path, "/api/v1/jobs/")
+	if !ok || rel == "" {
+		return false
+	}
+	parts := strings.Split(rel, "/")
+	return len(parts) == 1 || (len(parts) == 2 && parts[1] == "artifacts") || (len(parts) == 3 && parts[1] == "artifacts" && parts[2] == "download-all")
+}
+
+// writeRouteOperation names the operation of a state-changing request.
+// Paths it does not know map to no operation, which requires admin.
+func writeRouteOperation(method, path string) application.Operation {
+	switch {
+	case strings.HasSuffix(path, "/dry-run-preview"), strings.HasSuffix(path, "/eligible-agents"):
+		return application.OperationPreviewPipeline
+	case strings.HasPrefix(path, "/api/v1/pipelines/"):
+		return application.OperationRunPipeline
+	case path == "/api/v1/jobs":
+		return application.OperationCreateJobExecution
+	case path == "/api/v1/jobs/clear-queue":
+		return application.OperationClearExecutionQueue
+	case path == "/api/v1/jobs/flush-history":
+		return application.OperationFlushExecutionHistory
+	case strings.HasPrefix(path, "/api/v1/jobs/"):
+		return jobWriteOperation(method, strings.TrimPrefix(path, "/api/v1/jobs/"))
+	case path == "/api/v1/projects/import":
+		return application.OperationImportProject
+	case path == "/api/v1/projects/managed-yaml/validate":
+		return application.OperationValidateManagedYAML
+	case path == "/api/v1/projects/managed-yaml":
+		return application.OperationSaveManagedYAML
+	case strings.HasPrefix(path, "/api/v1/projects/"):
+		return projectWriteOperation(method, strings.Split(strings.TrimPrefix(path, "/api/v1/projects/"), "/"))
+	case strings.HasPrefix(path, "/api/v1/agents/"):
+		return application.OperationAgentAction
+	case path == "/api/v1/agent-enrollment-tokens", strings.HasPrefix(path, "/api/v1/agent-enrollment-tokens/"):
+		return application.OperationManageAgentEnrollmentTokens
+	case path == "/api/v1/vault/connections":
+		return application.OperationUpsertVaultConnection
+	case strings.HasPrefix(path, "/api/v1/vault/connections/"):
+		if strings.HasSuffix(path, "/test") {
+			return application.OperationTestVaultConnection
+		}
+		return application.OperationDeleteVaultConnection
+	case path == "/api/v1/repo-credentials", strings.HasPrefix(path, "/api/v1/repo-credentials/"):
+		return application.OperationManageRepoCredentials
+	case path == "/api/v1/update/check":
+		return application.OperationCheckServerUpdates
+	case path == "/api/v1/update/apply", path == "/api/v1/update/rollback":
+		return application.OperationServerUpdateAction
+	case path == "/api/v1/server/restart":
+		return application.OperationRestartServer
+	default:
+		return ""
+	}
+}
+
+func jobWriteOperation(method, rel string) application.Operation {
+	parts := strings.Split(rel, "/")
+	switch {
+	case len(parts) == 1 && method == http.MethodDelete:
+		return application.OperationRemoveQueuedExecution
+	case len(parts) == 2 && parts[1] == "cancel":
+		return application.OperationCancelExecution
+	case len(parts) == 2 && parts[1] == "rerun":
+		return application.OperationRerunExecution
+	default:
+		return ""
+	}
+}
+
+func projectWriteOperation(method string, parts []string) application.Operation {
+	if len(parts) < 2 {
+		return application.OperationProjectAction
+	}
+	switch parts[1] {
+	case "pipeline-chains":
+		if len(parts) == 4 && parts[3] == "run" {
+			return application.OperationRunPipelineChain
+		}
+		return ""
+	case "test-quarantine":
+		if method == http.MethodDelete {
+			return application.OperationRemoveTestQuarantine
+		}
+		return application.OperationAddTestQuarantine
+	case "managed-yaml":
+		return application.OperationSaveManagedYAML
+	case "webhook-secret":
+		return application.OperationManageProjectWebhook
+	default:
+		return application.OperationProjectAction
+	}
+}
+
+// authMiddleware authenticates requests with a session cookie or a personal
+// API token and checks the route's action against the caller's role. Until
+// the first user is created the server stays open, as before.
+func (s *stateStore) authMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		access := routeAccessFor(r)
+		if credential, ok := bearerAgentCredential(r); ok {
+			if !access.agent {
+				http.Error(w, "agent credentials are only accepted on agent routes", http.StatusForbidden)
+				return
+			}
+			ctx, err := s.authenticateAgentRequest(r, credential)
+			if err != nil {
+				http.Error(w, err.Error(), applicationErrorHTTPStatus(err))
+				return
+			}
+			next.ServeHTTP(w, r.WithContext(ctx))
+			return
+		}
+		if access.public {
+			next.ServeHTTP(w, r)
+			return
+		}
+		enabled, err := s.app().accounts.Enabled(r.Context())
+		if err != nil {
+			http.Error(w, err.Error(), applicationErrorHTTPStatus(err))
+			return
+		}
+		if !enabled {
+			next.ServeHTTP(w, r)
+			return
+		}
+		principal, viaCookie, err := s.authenticateRequest(r)
+		if err != nil {
+			if access.page && r.Method == http.MethodGet {
+				http.Redirect(w, r, "/login?next="+url.QueryEscape(r.URL.RequestURI()), http.StatusSeeOther)
+				return
+			}
+			http.Error(w, err.Error(), applicationErrorHTTPStatus(err))
+			return
+		}
+		if viaCookie && !crossSiteSafe(r) {
+			http.Error(w, "cross-site request rejected", http.StatusForbidden)
+			return
+		}
+		ctx := application.WithPrincipal(r.Context(), principal)
+		if !access.accounts {
+			if err := s.app().authorizer.Authorize(ctx, application.OperationAction(access.operation)); err != nil {
+				http.Error(w, err.Error(), applicationErrorHTTPStatus(err))
+				return
+			}
+		}
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// authenticateRequest resolves a bearer API token, falling back to the
+// session cookie.
+func (s *stateStore) authenticateRequest(r *http.Request) (domain.Principal, bool, error) {
+	accounts := s.app().accounts
+	if header := strings.TrimSpace(r.Header.Get("Authorization")); header != "" {
+		token, ok := strings.CutPrefix(header, "Bearer ")
+		if !ok {
+			return domain.Principal{}, false, application.NewError(application.ErrorUnauthenticated, "unsupported authorization scheme", nil)
+		}
+		principal, err := accounts.AuthenticateToken(r.Context(), token)
+		return principal, false, err
+	}
+	cookie, err := r.Cookie(sessionCookieName)
+	if err != nil {
+		return domain.Principal{}, false, application.NewError(application.ErrorUnauthenticated, "authentication required", nil)
+	}
+	principal, err := accounts.AuthenticateSession(r.Context(), cookie.Value)
+	return principal, true, err
+}
+
+// crossSiteSafe rejects state-changing cookie-authenticated requests that a
+// browser sent on behalf of another site.
+func crossSiteSafe(r *http.Request) bool {
+	switch r.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	}
+	if origin := strings.TrimSpace(r.Header.Get("Origin")); origin != "" {
+		parsed, err := url.Parse(origin)
+		return err == nil && strings.EqualFold(parsed.Host, r.Host)
+	}
+	return r.Header.Get("Sec-Fetch-Site") != "cross-site"
+}
+
+func (s *stateStore) loginHandler(w http.ResponseWriter, r *http.Request) {
+	var req protocol.LoginRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid JSON body", http.StatusBadRequest)
+		return
+	}
+	result, err := s.app().accounts.Login(r.Context(), application.LoginRequest{Username: req.Username, Password: req.Password})
+	if err != nil {
+		http.Error(w, err.Error(), applicationErrorHTTPStatus(err))
+		return
+	}
+	http.SetCookie(w, &http.Cookie{
+		Name: sessionCookieName, Value: result.SessionToken, Path: "/", Expires: result.ExpiresUTC,
+		HttpOnly: true, Secure: requestIsHTTPS(r), SameSite: http.SameSiteLaxMode,
+	})
+	user := userToProtocol(result.User)
+	writeJSON(w, http.StatusOK, protocol.AuthSessionResponse{
+		Enabled: true, Authenticated: true, User: &user, ExpiresUTC: result.ExpiresUTC.Format(time.RFC3339),
+	})
+}
+
+func (s *stateStore) logoutHandler(w http.ResponseWriter, r *http.Request) {
+	if cookie, err := r.Cookie(sessionCookieName); err == nil {
+		if err := s.app().accounts.Logout(r.Context(), cookie.Value); err != nil {
+			http.Error(w, err.Error(), applicationErrorHTTPStatus(err))
+			return
+		}
+	}
+	http.SetCookie(w, &http.Cookie{
+		Name: sessionCookieName, Value: "", Path: "/", MaxAge: -1,
+		HttpOnly: true, Secure: requestIsHTTPS(r), SameSite: http.SameSiteLaxMode,
+	})
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *stateStore) authSessionHandler(w http.ResponseWriter, r *http.Request) {
+	enabled, err := s.app().accounts.Enabled(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), applicationErrorHTTPStatus(err))
+		return
+	}
+	response := protocol.AuthSessionResponse{Enabled: enabled}
+	if enabled {
+		if principal, _, err := s.authenticateRequest(r); err == nil {
+			response.Authenticated = true
+			response.User = &protocol.User{ID: principal.UserID, Username: principal.Username, Role: string(principal.Role)}
+		}
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+func (s *stateStore) usersHandler(w http.ResponseWriter, r *http.Request) {
+	accounts := s.app().accounts
+	switch r.Method {
+	case http.MethodGet:
+		users, err := accounts.ListUsers(r.Context())
+		if err != nil {
+			http.Error(w, err.Error(), applicationErrorHTTPStatus(err))
+			return
+		}
+		response := protocol.UsersResponse{Users: make([]protocol.User, 0, len(users))}
+		for _, user := range users {
+			response.Users = append(response.Users, userToProtocol(user))
+		}
+		writeJSON(w, http.StatusOK, response)
+	case http.MethodPost:
+		var req protocol.CreateUserRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "invalid JSON body", http.StatusBadRequest)
+			return
+		}
+		user, err := accounts.CreateUser(r.Context(), application.CreateUserRequest{Username: req.Username, Password: req.Password, Role: req.Role})
+		if err != nil {
+			http.Error(w, err.Error(), applicationErrorHTTPStatus(err))
+			return
+		}
+		writeJSON(w, http.StatusCreated, userToProtocol(user))
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (s *stateStore) userByIDHandler(w http.ResponseWriter, r *http.Request) {
+	userID, ok := authResourceID(w, r, "/api/v1/auth/users/")
+	if !ok {
+		return
+	}
+	accounts := s.app().accounts
+	switch r.Method {
+	case http.MethodPatch, http.MethodPut:
+		var req protocol.UpdateUserRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "invalid JSON body", http.StatusBadRequest)
+			return
+		}
+		user, err := accounts.UpdateUser(r.Context(), application.UpdateUserRequest{
+			UserID: userID, Role: req.Role, Password: req.Password, CurrentPassword: req.CurrentPassword,
+		})
+		if err != nil {
+			http.Error(w, err.Error(), applicationErrorHTTPStatus(err))
+			return
+		}
+		writeJSON(w, http.StatusOK, userToProtocol(user))
+	case http.MethodDelete:
+		if err := accounts.DeleteUser(r.Context(), userID); err != nil {
+			http.Error(w, err.Error(), applicationErrorHTTPStatus(err))
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (s *stateStore) apiTokensHandler(w http.ResponseWriter, r *http.Request) {
+	accounts := s.app().accounts
+	switch r.Method {
+	case http.MethodGet:
+		tokens, err := accounts.ListAPITokens(r.Context())
+		if err != nil {
+			http.Error(w, err.Error(), applicationErrorHTTPStatus(err))
+			return
+		}
+		response := protocol.APITokensResponse{Tokens: make([]protocol.APIToken, 0, len(tokens))}
+		for _, token := range tokens {
+			response.Tokens = append(response.Tokens, apiTokenToProtocol(token))
+		}
+		writeJSON(w, http.StatusOK, response)
+	case http.MethodPost:
+		var req protocol.CreateAPITokenRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "invalid JSON body", http.StatusBadRequest)
+			return
+		}
+		var expires time.Time
+		if strings.TrimSpace(req.ExpiresUTC) != "" {
+			parsed, err := time.Parse(time.RFC3339, strings.TrimSpace(req.ExpiresUTC))
+			if err != nil {
+				http.Error(w, "expires_utc must be an RFC 3339 time", http.StatusBadRequest)
+				return
+			}
+			expires = parsed
+		}
+		result, err := accounts.CreateAPIToken(r.Context(), application.CreateAPITokenRequest{Name: req.Name, ExpiresUTC: expires})
+		if err != nil {
+			http.Error(w, err.Error(), applicationErrorHTTPStatus(err))
+			return
+		}
+		writeJSON(w, http.StatusCreated, protocol.CreateAPITokenResponse{Token: apiTokenToProtocol(result.Token), Secret: result.Secret})
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (s *stateStore) apiTokenByIDHandler(w http.ResponseWriter, r *http.Request) {
+	tokenID, ok := authResourceID(w, r, "/api/v1/auth/tokens/")
+	if !ok {
+		return
+	}
+	if r.Method != http.MethodDelete {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if err := s.app().accounts.RevokeAPIToken(r.Context(), tokenID); err != nil {
+		http.Error(w, err.Error(), applicationErrorHTTPStatus(err))
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func authResourceID(w http.ResponseWriter, r *http.Request, prefix string) (int64, bool) {
+	id, err := strconv.ParseInt(strings.Trim(strings.TrimPrefix(r.URL.Path, prefix), "/"), 10, 64)
+	if err != nil || id <= 0 {
+		http.Error(w, "invalid id", http.StatusBadRequest)
+		return 0, false
+	}
+	return id, true
+}
+
+func requestIsHTTPS(r *http.Request) bool {
+	return r.TLS != nil || strings.EqualFold(r.Header.Get("X-Forwarded-Proto"), "https")
+}
+
+func userToProtocol(user domain.User) protocol.User {
+	return protocol.User{
+		ID: user.ID, Username: user.Username, Role: string(user.Role),
+		CreatedUTC: formatOptionalUTC(user.CreatedUTC), LastLoginUTC: formatOptionalUTC(user.LastLoginUTC),
+	}
+}
+
+func apiTokenToProtocol(token domain.APIToken) protocol.APIToken {
+	return protocol.APIToken{
+		ID: token.ID, Name: token.Name, Prefix: token.Prefix, CreatedUTC: formatOptionalUTC(token.CreatedUTC),
+		LastUsedUTC: formatOptionalUTC(token.LastUsedUTC), ExpiresUTC: formatOptionalUTC(token.ExpiresUTC),
+	}
+}
+
+func formatOptionalUTC(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
@@ -0,0 +1,179 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/cookiejar"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/izzyreal/ciwi/internal/application"
+	"github.com/izzyreal/ciwi/internal/protocol"
+)
+
+func TestRouteAccessFor(t *testing.T) {
+	tests := []struct {
+		method string
+		path   string
+		want   routeAccess
+	}{
+		{http.MethodGet, "/healthz", routeAccess{public: true}},
+		{http.MethodGet, "/ui/declarative.js", routeAccess{public: true}},
+		{http.MethodPost, "/api/v1/hooks/3", routeAccess{public: true}},
+		{http.MethodPost, "/api/v1/auth/login", routeAccess{public: true}},
+		{http.MethodPost, "/api/v1/agent/enroll", routeAccess{public: true}},
+		{http.MethodPost, "/api/v1/heartbeat", routeAccess{public: true, agent: true}},
+		{http.MethodPost, "/api/v1/agent/lease", routeAccess{public: true, agent: true}},
+		{http.MethodPost, "/api/v1/jobs/job-1/status", routeAccess{public: true, agent: true}},
+		{http.MethodPost, "/api/v1/jobs/job-1/tests", routeAccess{public: true, agent: true}},
+		{http.MethodGet, "/api/v1/jobs/job-1", routeAccess{agent: true, operation: application.OperationRead}},
+		{http.MethodGet, "/", routeAccess{page: true, operation: application.OperationRead}},
+		{http.MethodGet, "/projects/1", routeAccess{page: true, operation: application.OperationRead}},
+		{http.MethodGet, "/api/v1/projects", routeAccess{operation: application.OperationRead}},
+		{http.MethodGet, "/artifacts/job-1/out.txt", routeAccess{agent: true, operation: application.OperationRead}},
+		{http.MethodGet, "/api/v1/jobs/job-1/log", routeAccess{operation: application.OperationRead}},
+		{http.MethodPost, "/api/v1/auth/users", routeAccess{accounts: true}},
+		{http.MethodPost, "/api/v1/pipelines/1/run-selection", routeAccess{operation: application.OperationRunPipeline}},
+		{http.MethodPost, "/api/v1/pipelines/1/dry-run-preview", routeAccess{operation: application.OperationPreviewPipeline}},
+		{http.MethodPost, "/api/v1/projects/1/pipeline-chains/2/run", routeAccess{operation: application.OperationRunPipelineChain}},
+		{http.MethodPost, "/api/v1/projects/import", routeAccess{operation: application.OperationImportProject}},
+		{http.MethodPost, "/api/v1/projects/1/reload", routeAccess{operation: application.OperationProjectAction}},
+		{http.MethodPut, "/api/v1/projects/1/managed-yaml", routeAccess{operation: application.OperationSaveManagedYAML}},
+		{http.MethodDelete, "/api/v1/projects/1/test-quarantine/4", routeAccess{operation: application.OperationRemoveTestQuarantine}},
+		{http.MethodPost, "/api/v1/jobs", routeAccess{operation: application.OperationCreateJobExecution}},
+		{http.MethodPost, "/api/v1/jobs/job-1/cancel", routeAccess{operation: application.OperationCancelExecution}},
+		{http.MethodPost, "/api/v1/jobs/clear-queue", routeAccess{operation: application.OperationClearExecutionQueue}},
+		{http.MethodDelete, "/api/v1/jobs/job-1", routeAccess{operation: application.OperationRemoveQueuedExecution}},
+		{http.MethodPost, "/api/v1/agents/a1/actions", routeAccess{operation: application.OperationAgentAction}},
+		{http.MethodPost, "/api/v1/agent-enrollment-tokens", routeAccess{operation: application.OperationManageAgentEnrollmentTokens}},
+		{http.MethodPost, "/api/v1/vault/connections", routeAccess{operation: application.OperationUpsertVaultConnection}},
+		{http.MethodPost, "/api/v1/vault/connections/2/test", routeAccess{operation: application.OperationTestVaultConnection}},
+		{http.MethodPost, "/api/v1/update/apply", routeAccess{operation: application.OperationServerUpdateAction}},
+		{http.MethodPost, "/api/v1/server/restart", routeAccess{operation: application.OperationRestartServer}},
+		{http.MethodPost, "/api/v1/unknown", routeAccess{}},
+	}
+	for _, tc := range tests {
+		got := routeAccessFor(httptest.NewRequest(tc.method, tc.path, nil))
+		if got != tc.want {
+			t.Errorf("%s %s: got %+v, want %+v", tc.method, tc.path, got, tc.want)
+		}
+	}
+}
+
+func TestAuthMiddlewareSessionsTokensAndRoles(t *testing.T) {
+	_, s := newTestHTTPServerWithState(t)
+	srv := httptest.NewServer(buildRouter(s, s.artifactsDir))
+	defer srv.Close()
+
+	resp := mustJSONRequest(t, srv.Client(), http.MethodGet, srv.URL+"/api/v1/projects", nil)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected open server before first user, got %d body=%s", resp.StatusCode, readBody(t, resp))
+	}
+	_ = readBody(t, resp)
+
+	resp = mustJSONRequest(t, srv.Client(), http.MethodPost, srv.URL+"/api/v1/auth/users", protocol.CreateUserRequest{Username: "root", Password: "correct horse"})
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("expected bootstrap admin 201, got %d body=%s", resp.StatusCode, readBody(t, resp))
+	}
+	_ = readBody(t, resp)
+
+	resp = mustJSONRequest(t, srv.Client(), http.MethodGet, srv.URL+"/api/v1/projects", nil)
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("expected 401 without credentials, got %d body=%s", resp.StatusCode, readBody(t, resp))
+	}
+	_ = readBody(t, resp)
+
+	noRedirect := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }}
+	resp = mustJSONRequest(t, noRedirect, http.MethodGet, srv.URL+"/agents", nil)
+	if resp.StatusCode != http.StatusSeeOther || resp.Header.Get("Location") != "/login?next=%2Fagents" {
+		t.Fatalf("expected redirect to login, got %d location=%q", resp.StatusCode, resp.Header.Get("Location"))
+	}
+	_ = readBody(t, resp)
+
+	admin := newCookieClient(t)
+	login(t, admin, srv.URL, "root", "correct horse")
+	resp = mustJSONRequest(t, admin, http.MethodPost, srv.URL+"/api/v1/auth/users", protocol.CreateUserRequest{Username: "vera", Password: "viewer password", Role: "viewer"})
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("expected admin to create viewer, got %d body=%s", resp.StatusCode, readBody(t, resp))
+	}
+	_ = readBody(t, resp)
+
+	viewer := newCookieClient(t)
+	login(t, viewer, srv.URL, "vera", "viewer password")
+	resp = mustJSONRequest(t, viewer, http.MethodGet, srv.URL+"/api/v1/projects", nil)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected viewer to list projects, got %d body=%s", resp.StatusCode, readBody(t, resp))
+	}
+	_ = readBody(t, resp)
+	resp = mustJSONRequest(t, viewer, http.MethodPost, srv.URL+"/api/v1/pipelines/1/run", map[string]any{})
+	if resp.StatusCode != http.StatusForbidden {
+		t.Fatalf("expected viewer run to be forbidden, got %d body=%s", resp.StatusCode, readBody(t, resp))
+	}
+	_ = readBody(t, resp)
+
+	req, _ := http.NewRequest(http.MethodPost, srv.URL+"/api/v1/jobs/clear-queue", nil)
+	req.Header.Set("Origin", "https://evil.example")
+	resp, err := admin.Do(req)
+	if err != nil {
+		t.Fatalf("cross-site request: %v", err)
+	}
+	if resp.StatusCode != http.StatusForbidden {
+		t.Fatalf("expected cross-site cookie request to be rejected, got %d", resp.StatusCode)
+	}
+	_ = readBody(t, resp)
+
+	resp = mustJSONRequest(t, admin, http.MethodPost, srv.URL+"/api/v1/auth/tokens", protocol.CreateAPITokenRequest{Name: "ci"})
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("expected token 201, got %d body=%s", resp.StatusCode, readBody(t, resp))
+	}
+	var created protocol.CreateAPITokenResponse
+	if err := json.NewDecoder(resp.Body).Decode(&created); err != nil {
+		t.Fatalf("decode token: %v", err)
+	}
+	_ = resp.Body.Close()
+	if !strings.HasPrefix(created.Secret, application.APITokenPrefix) {
+		t.Fatalf("unexpected token secret %q", created.Secret)
+	}
+
+	req, _ = http.NewRequest(http.MethodGet, srv.URL+"/api/v1/auth/session", nil)
+	req.Header.Set("Authorization", "Bearer "+created.Secret)
+	resp, err = srv.Client().Do(req)
+	if err != nil {
+		t.Fatalf("session request: %v", err)
+	}
+	var session protocol.AuthSessionResponse
+	if err := json.NewDecoder(resp.Body).Decode(&session); err != nil {
+		t.Fatalf("decode session: %v", err)
+	}
+	_ = resp.Body.Close()
+	if !session.Enabled || !session.Authenticated || session.User == nil || session.User.Username != "root" || session.User.Role != "admin" {
+		t.Fatalf("unexpected token session: %+v", session)
+	}
+
+	resp = mustJSONRequest(t, admin, http.MethodPost, srv.URL+"/api/v1/auth/logout", nil)
+	_ = readBody(t, resp)
+	resp = mustJSONRequest(t, admin, http.MethodGet, srv.URL+"/api/v1/projects", nil)
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("expected 401 after logout, got %d body=%s", resp.StatusCode, readBody(t, resp))
+	}
+	_ = readBody(t, resp)
+}
+
+func newCookieClient(t *testing.T) *http.Client {
+	t.Helper()
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		t.Fatalf("cookie jar: %v", err)
+	}
+	return &http.Client{Jar: jar}
+}
+
+func login(t *testing.T, client *http.Client, baseURL, username, password string) {
+	t.Helper()
+	resp := mustJSONRequest(t, client, http.MethodPost, baseURL+"/api/v1/auth/login", protocol.LoginRequest{Username: username, Password: password})
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("login %s: got %d body=%s", username, resp.StatusCode, readBody(t, resp))
+	}
+	_ = readBody(t, resp)
+}
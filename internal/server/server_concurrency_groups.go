@@ -0,0 +1,113 @@
+package server
+
+import (
+	"fmt"
+	"log/slog"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/izzyreal/ciwi/internal/domain"
+	"github.com/izzyreal/ciwi/internal/protocol"
+	"github.com/izzyreal/ciwi/internal/server/jobexecution"
+	"github.com/izzyreal/ciwi/internal/store"
+)
+
+// setConcurrencyGroupMetadata records the concurrency group of a pending job.
+// A job's own concurrency replaces the pipeline's; a pipeline group is held by
+// the whole run, a job group by each execution.
+func setConcurrencyGroupMetadata(metadata domain.ExecutionMetadata, p store.PersistedPipeline, pj store.PersistedPipelineJob, vars map[string]string, runID string) {
+	concurrency, holder := pj.Concurrency, ""
+	if concurrency == nil {
+		concurrency, holder = p.Concurrency, runID
+	}
+	if concurrency == nil {
+		return
+	}
+	group := strings.TrimSpace(renderTemplate(concurrency.Group, vars))
+	if group == "" {
+		return
+	}
+	metadata.Set(domain.ExecutionMetadataConcurrencyGroup, group)
+	metadata.Set(domain.ExecutionMetadataConcurrencyMaxRunning, strconv.Itoa(concurrency.EffectiveMaxRunning()))
+	metadata.Set(domain.ExecutionMetadataConcurrencyHolder, holder)
+	metadata.SetFlag(domain.ExecutionMetadataConcurrencyCancelRunning, concurrency.CancelInProgress)
+}
+
+// supersedeConcurrencyGroups cancels the older holders of the groups that jobs
+// just joined. Holders that have not started yet are only superseded when the
+// group has no room left for them next to the running holders and the new
+// ones; running holders are cancelled when the group cancels in-progress runs.
+// Holders created in the same batch, such as the pipelines of one chain run,
+// never supersede each other.
+func (s *stateStore) supersedeConcurrencyGroups(jobs []protocol.JobExecution) error {
+	created := map[string]bool{}
+	supersededBy := map[string]protocol.JobExecution{}
+	joined := map[string]map[string]bool{}
+	for _, job := range jobs {
+		holder := protocol.JobExecutionConcurrencyHolder(job)
+		created[holder] = true
+		if group, _, ok := job.Metadata.ConcurrencyGroup(); ok {
+			supersededBy[group] = job
+			if joined[group] == nil {
+				joined[group] = map[string]bool{}
+			}
+			joined[group][holder] = true
+		}
+	}
+	now := time.Now().UTC()
+	for group, newer := range supersededBy {
+		executions, err := s.jobExecutionStore().ListConcurrencyGroupExecutions(group)
+		if err != nil {
+			return err
+		}
+		_, maxRunning, _ := newer.Metadata.ConcurrencyGroup()
+		cancelRunning := newer.Metadata.Flag(domain.ExecutionMetadataConcurrencyCancelRunning)
+		started := map[string]bool{}
+		waitingSince := map[string]time.Time{}
+		for _, execution := range executions {
+			holder := protocol.JobExecutionConcurrencyHolder(execution)
+			if created[holder] || !protocol.IsActiveJobExecutionStatus(execution.Status) {
+				continue
+			}
+			if !protocol.IsQueuedJobExecutionStatus(execution.Status) {
+				started[holder] = true
+			} else if since, ok := waitingSince[holder]; !ok || execution.CreatedUTC.Before(since) {
+				waitingSince[holder] = execution.CreatedUTC
+			}
+		}
+		superseded := map[string]bool{}
+		free := maxRunning - len(joined[group])
+		if cancelRunning {
+			for holder := range started {
+				superseded[holder] = true
+			}
+		} else {
+			free -= len(started)
+		}
+		waiting := make([]string, 0, len(waitingSince))
+		for holder := range waitingSince {
+			if !started[holder] {
+				waiting = append(waiting, holder)
+			}
+		}
+		// The newest waiting holders keep the free places.
+		sort.Slice(waiting, func(i, j int) bool { return waitingSince[waiting[i]].After(waitingSince[waiting[j]]) })
+		for i, holder := range waiting {
+			if i >= free {
+				superseded[holder] = true
+			}
+		}
+		cancellation := domain.ExecutionCancellation{Reason: fmt.Sprintf("superseded by %s in concurrency group %q", newer.ID, group)}
+		for _, execution := range executions {
+			if !superseded[protocol.JobExecutionConcurrencyHolder(execution)] || !protocol.IsActiveJobExecutionStatus(execution.Status) {
+				continue
+			}
+			if _, err := jobexecution.CancelJobExecution(s.jobExecutionStore(), execution.ID, now, cancellation); err != nil {
+				slog.Warn("supersede concurrency group execution", "job_execution_id", execution.ID, "group", group, "error", err)
+			}
+		}
+	}
+	return nil
+}
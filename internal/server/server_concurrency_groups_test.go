@@ -0,0 +1,185 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/izzyreal/ciwi/internal/domain"
+	"github.com/izzyreal/ciwi/internal/protocol"
+)
+
+func TestPendingJobsCarryConcurrencyGroups(t *testing.T) {
+	s, pipeline := loadPipelineForEnqueueBuilderTest(t, []byte(`
+version: 1
+project:
+  name: ciwi
+pipelines:
+  - id: release
+    concurrency:
+      group: deploy-{{ciwi.project}}
+      cancel_in_progress: true
+    jobs:
+      - id: publish
+        runs_on: {os: linux}
+        timeout_seconds: 30
+        steps:
+          - run: ./publish
+      - id: smoke
+        runs_on: {os: linux}
+        matrix:
+          include:
+            - device: pixel
+        concurrency:
+          group: device-{{matrix.device}}
+          max_running: 2
+        timeout_seconds: 30
+        steps:
+          - run: ./smoke
+`), "concurrency")
+
+	_, pending, err := s.preparePendingPipelineJobs(pipeline, nil, enqueuePipelineOptions{
+		forcedRun: &pipelineRunContext{},
+		forcedDep: &pipelineDependencyContext{},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pending) != 2 {
+		t.Fatalf("expected two pending jobs, got %d", len(pending))
+	}
+	runID := pending[0].metadata.Value(domain.ExecutionMetadataPipelineRunID)
+	if group, maxRunning, ok := pending[0].metadata.ConcurrencyGroup(); !ok || group != "deploy-ciwi" || maxRunning != 1 {
+		t.Fatalf("expected pipeline group deploy-ciwi, got %q max=%d", group, maxRunning)
+	}
+	if holder := pending[0].metadata.Value(domain.ExecutionMetadataConcurrencyHolder); holder == "" || holder != runID {
+		t.Fatalf("expected the run %q to hold the pipeline group, got %q", runID, holder)
+	}
+	if !pending[0].metadata.Flag(domain.ExecutionMetadataConcurrencyCancelRunning) {
+		t.Fatalf("expected cancel_in_progress on the pipeline group, got %+v", pending[0].metadata)
+	}
+	if group, maxRunning, ok := pending[1].metadata.ConcurrencyGroup(); !ok || group != "device-pixel" || maxRunning != 2 {
+		t.Fatalf("expected job group device-pixel to replace the pipeline group, got %q max=%d", group, maxRunning)
+	}
+	if holder := pending[1].metadata.Value(domain.ExecutionMetadataConcurrencyHolder); holder != "" {
+		t.Fatalf("expected each execution to hold a job group, got %q", holder)
+	}
+
+	_, pending, err = s.preparePendingPipelineJobs(pipeline, &protocol.RunPipelineSelectionRequest{DryRun: true}, enqueuePipelineOptions{
+		forcedRun: &pipelineRunContext{},
+		forcedDep: &pipelineDependencyContext{},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, job := range pending {
+		if _, _, ok := job.metadata.ConcurrencyGroup(); ok {
+			t.Fatalf("expected dry runs to stay out of concurrency groups, got %+v", job.metadata)
+		}
+	}
+}
+
+func TestEnqueueSupersedesQueuedConcurrencyGroupRuns(t *testing.T) {
+	s, pipeline := loadPipelineForEnqueueBuilderTest(t, []byte(`
+version: 1
+project:
+  name: ciwi
+pipelines:
+  - id: deploy
+    concurrency:
+      group: deploy-homelab
+    jobs:
+      - id: apply
+        runs_on: {os: linux}
+        timeout_seconds: 30
+        steps:
+          - run: ./apply
+`), "concurrency")
+
+	enqueue := func() string {
+		t.Helper()
+		resp, err := s.enqueuePersistedPipeline(pipeline, nil)
+		if err != nil {
+			t.Fatalf("enqueue pipeline: %v", err)
+		}
+		return resp.JobExecutionIDs[0]
+	}
+	status := func(id string) string {
+		t.Helper()
+		job, err := s.db.GetJobExecution(id)
+		if err != nil {
+			t.Fatalf("get job: %v", err)
+		}
+		return job.Status
+	}
+
+	running := enqueue()
+	if leased, err := s.db.LeaseJobExecution("agent-a", map[string]string{"os": "linux"}); err != nil || leased == nil || leased.ID != running {
+		t.Fatalf("expected %s to be leased, got %+v err=%v", running, leased, err)
+	}
+	waiting := enqueue()
+	if got := status(running); got != protocol.JobExecutionStatusLeased {
+		t.Fatalf("expected the started run to keep running without cancel_in_progress, got %q", got)
+	}
+	newest := enqueue()
+	if got := status(waiting); got != protocol.JobExecutionStatusCancelled {
+		t.Fatalf("expected the queued run to be superseded, got %q", got)
+	}
+	if got := status(newest); got != protocol.JobExecutionStatusQueued {
+		t.Fatalf("expected the newest run to wait for the group, got %q", got)
+	}
+}
+
+func TestEnqueueKeepsQueuedRunsWithinConcurrencyGroupCapacity(t *testing.T) {
+	s, pipeline := loadPipelineForEnqueueBuilderTest(t, []byte(`
+version: 1
+project:
+  name: ciwi
+pipelines:
+  - id: e2e
+    concurrency:
+      group: devices
+      max_running: 3
+    jobs:
+      - id: run
+        runs_on: {os: linux}
+        timeout_seconds: 30
+        steps:
+          - run: ./e2e
+`), "concurrency")
+
+	enqueue := func() string {
+		t.Helper()
+		resp, err := s.enqueuePersistedPipeline(pipeline, nil)
+		if err != nil {
+			t.Fatalf("enqueue pipeline: %v", err)
+		}
+		return resp.JobExecutionIDs[0]
+	}
+	status := func(id string) string {
+		t.Helper()
+		job, err := s.db.GetJobExecution(id)
+		if err != nil {
+			t.Fatalf("get job: %v", err)
+		}
+		return job.Status
+	}
+
+	running := enqueue()
+	if leased, err := s.db.LeaseJobExecution("agent-a", map[string]string{"os": "linux"}); err != nil || leased == nil || leased.ID != running {
+		t.Fatalf("expected %s to be leased, got %+v err=%v", running, leased, err)
+	}
+	first, second := enqueue(), enqueue()
+	for _, id := range []string{first, second} {
+		if got := status(id); got != protocol.JobExecutionStatusQueued {
+			t.Fatalf("expected %s to keep its place within max_running, got %q", id, got)
+		}
+	}
+	third := enqueue()
+	if got := status(first); got != protocol.JobExecutionStatusCancelled {
+		t.Fatalf("expected the oldest queued run beyond the group's capacity to be superseded, got %q", got)
+	}
+	for _, id := range []string{running, second, third} {
+		if got := status(id); got == protocol.JobExecutionStatusCancelled {
+			t.Fatalf("expected %s to stay within the group's capacity, got %q", id, got)
+		}
+	}
+}
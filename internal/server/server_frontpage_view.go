@@ -98,6 +98,8 @@ type executionSummaryResponse struct {
 	TotalJobs  int `json:"total_jobs"`
 	Succeeded  int `json:"succeeded"`
 	Failed     int `json:"failed"`
+	Cancelled  int `json:"cancelled"`
+	Skipped    int `json:"skipped"`
 	InProgress int `json:"in_progress"`
 	Waiting    int `json:"waiting"`
 }
@@ -167,7 +169,8 @@ func executionCardsToResponse(cards []domain.ExecutionCard, queued bool) []execu
 			JobExecutionIDs: append([]string(nil), card.JobExecutionIDs...),
 			Summary: executionSummaryResponse{
 				TotalJobs: card.Summary.TotalJobs, Succeeded: card.Summary.Succeeded,
-				Failed: card.Summary.Failed, InProgress: card.Summary.InProgress, Waiting: card.Summary.Waiting,
+				Failed: card.Summary.Failed, Cancelled: card.Summary.Cancelled, Skipped: card.Summary.Skipped,
+				InProgress: card.Summary.InProgress, Waiting: card.Summary.Waiting,
 			},
 			Sections: executionCardSectionsToResponse(card.Sections), Progress: card.Progress,
 			Status: display.Status, SummaryTone: display.SummaryTone, SummaryLabel: display.SummaryLabel,
@@ -9,7 +9,10 @@ import (
 	"path/filepath"
 	"strings"
 
+	"github.com/izzyreal/ciwi/internal/domain"
+	"github.com/izzyreal/ciwi/internal/gitauth"
 	"github.com/izzyreal/ciwi/internal/protocol"
+	"github.com/izzyreal/ciwi/internal/requirements"
 	"github.com/izzyreal/ciwi/internal/server/httpx"
 )
 
@@ -62,6 +65,14 @@ func mergeCapabilities(agent agentState, override map[string]string) map[string]
 	for k, v := range override {
 		merged[k] = v
 	}
+	for k := range merged {
+		if strings.HasPrefix(k, domain.AgentLabelCapabilityPrefix) {
+			delete(merged, k)
+		}
+	}
+	for k, v := range requirements.LabelCapabilities(agent.effectiveLabels()) {
+		merged[k] = v
+	}
 	return merged
 }
 
@@ -84,9 +95,19 @@ func envOrDefault(key, fallback string) string {
 	return fallback
 }
 
+// runCmd runs a command with the repository credential bound to ctx, if any;
+// see stateStore.withRepoCredential.
 func runCmd(ctx context.Context, dir, name string, args ...string) (string, error) {
+	authEnv, release, err := gitauth.CommandEnv(ctx)
+	if err != nil {
+		return "", err
+	}
+	defer release()
 	cmd := exec.CommandContext(ctx, name, args...)
 	cmd.Dir = dir
+	if len(authEnv) > 0 {
+		cmd.Env = append(os.Environ(), authEnv...)
+	}
 	out, err := cmd.CombinedOutput()
 	return string(out), err
 }
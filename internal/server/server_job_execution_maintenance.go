@@ -3,7 +3,10 @@ package server
 import (
 	"context"
 	"log/slog"
+	"strings"
 	"time"
+
+	"github.com/izzyreal/ciwi/internal/protocol"
 )
 
 const (
@@ -11,6 +14,8 @@ const (
 	jobExecutionLeaseStaleAfter       = 45 * time.Second
 	jobExecutionTimeoutReaperGrace    = 15 * time.Second
 	jobExecutionTimeoutReaperErrorMsg = "job timed out while running (server maintenance)"
+	jobExecutionAgentLostAfter        = 2 * agentStaleMaxAge
+	jobExecutionAgentLostErrorMsg     = "agent lost while the job was running"
 )
 
 func (s *stateStore) runJobExecutionMaintenancePass(now time.Time) error {
@@ -28,10 +33,14 @@ func (s *stateStore) runJobExecutionMaintenancePass(now time.Time) error {
 	if err != nil {
 		return err
 	}
-	if requeued > 0 || failed > 0 {
-		slog.Warn("job execution maintenance applied", "requeued_stale_leased", requeued, "failed_timed_out_running", failed)
+	lost, err := s.failJobExecutionsOfLostAgents(now)
+	if err != nil {
+		return err
+	}
+	if requeued > 0 || failed > 0 || lost > 0 {
+		slog.Warn("job execution maintenance applied", "requeued_stale_leased", requeued, "failed_timed_out_running", failed, "failed_agent_lost", lost)
 	}
-	if failed > 0 {
+	if failed > 0 || lost > 0 {
 		if err := s.reconcileBlockedJobExecutions(); err != nil {
 			return err
 		}
@@ -39,6 +48,52 @@ func (s *stateStore) runJobExecutionMaintenancePass(now time.Time) error {
 	return nil
 }
 
+// failJobExecutionsOfLostAgents fails running jobs whose agent stopped
+// reporting. Silence is measured from server start at the earliest, so a
+// restarted server gives agents time to reconnect.
+func (s *stateStore) failJobExecutionsOfLostAgents(now time.Time) (int, error) {
+	lost := 0
+	for _, snapshot := range s.agentRegistry.snapshots() {
+		agentID := strings.TrimSpace(snapshot.ID)
+		if agentID == "" {
+			continue
+		}
+		lastSeen := snapshot.State.LastSeenUTC
+		if s.startedUTC.After(lastSeen) {
+			lastSeen = s.startedUTC
+		}
+		if now.Sub(lastSeen) < jobExecutionAgentLostAfter {
+			continue
+		}
+		jobs, err := s.jobExecutionStore().ListAgentActiveJobExecutions(agentID)
+		if err != nil {
+			return lost, err
+		}
+		reason := jobExecutionAgentLostErrorMsg + ": no contact from " + agentID + " since " + lastSeen.UTC().Format(time.RFC3339)
+		for _, job := range jobs {
+			if protocol.NormalizeJobExecutionStatus(job.Status) != protocol.JobExecutionStatusRunning {
+				continue
+			}
+			updated, err := s.jobExecutionStore().UpdateJobExecutionStatus(job.ID, protocol.JobExecutionStatusUpdateRequest{
+				AgentID: agentID, Status: protocol.JobExecutionStatusFailed, Error: reason, TimestampUTC: now,
+			})
+			if err != nil {
+				return lost, err
+			}
+			if protocol.NormalizeJobExecutionStatus(updated.Status) != protocol.JobExecutionStatusFailed {
+				continue
+			}
+			if err := s.jobExecutionStore().AppendJobExecutionEvents(job.ID, []protocol.JobExecutionEvent{{
+				Type: protocol.JobExecutionEventTypeSystemMessage, TimestampUTC: now, Message: "[control] " + reason,
+			}}); err != nil {
+				return lost, err
+			}
+			lost++
+		}
+	}
+	return lost, nil
+}
+
 func (s *stateStore) runJobExecutionMaintenanceLoop(ctx context.Context) {
 	ticker := time.NewTicker(jobExecutionMaintenanceInterval)
 	defer ticker.Stop()
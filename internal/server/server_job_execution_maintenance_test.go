@@ -167,7 +167,7 @@ func TestServerMaintenancePropagatesTimeoutToBlockedDependents(t *testing.T) {
 	if err != nil {
 		t.Fatalf("get dependent: %v", err)
 	}
-	if got.Status != protocol.JobExecutionStatusFailed || !strings.Contains(got.Error, "required job unit-tests failed") {
-		t.Fatalf("expected timeout failure to propagate, got status=%q error=%q", got.Status, got.Error)
+	if got.Status != protocol.JobExecutionStatusSkipped || !strings.Contains(got.Error, "required job unit-tests failed") {
+		t.Fatalf("expected timeout to skip dependent, got status=%q error=%q", got.Status, got.Error)
 	}
 }
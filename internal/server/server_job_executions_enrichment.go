@@ -1,6 +1,7 @@
 package server
 
 import (
+	"log/slog"
 	"strings"
 	"time"
 
@@ -34,17 +35,19 @@ func (s *stateStore) attachJobExecutionTestSummary(job *protocol.JobExecution) {
 		return
 	}
 	job.TestSummary = &protocol.JobExecutionTestSummary{
-		Total:   report.Total,
-		Passed:  report.Passed,
-		Failed:  report.Failed,
-		Skipped: report.Skipped,
+		Total:       report.Total,
+		Passed:      report.Passed,
+		Failed:      report.Failed,
+		Skipped:     report.Skipped,
+		Quarantined: report.Quarantined,
 	}
 }
 
 func (s *stateStore) attachJobExecutionSchedulingDiagnoses(jobs []protocol.JobExecution) {
 	agents := s.schedulingAgentSnapshots(time.Now().UTC())
+	queue := s.schedulingQueueForDiagnosis(jobs)
 	for i := range jobs {
-		jobs[i].SchedulingDiagnosis = executiondiagnosis.DiagnoseQueuedJob(jobs[i], agents)
+		jobs[i].SchedulingDiagnosis = executiondiagnosis.DiagnoseQueuedJob(jobs[i], agents, queue)
 	}
 }
 
@@ -52,5 +55,17 @@ func (s *stateStore) attachJobExecutionSchedulingDiagnosis(job *protocol.JobExec
 	if job == nil {
 		return
 	}
-	job.SchedulingDiagnosis = executiondiagnosis.DiagnoseQueuedJob(*job, s.schedulingAgentSnapshots(time.Now().UTC()))
+	queue := s.schedulingQueueForDiagnosis([]protocol.JobExecution{*job})
+	job.SchedulingDiagnosis = executiondiagnosis.DiagnoseQueuedJob(*job, s.schedulingAgentSnapshots(time.Now().UTC()), queue)
+}
+
+// schedulingQueueForDiagnosis degrades to an empty queue so a failed read
+// only drops the priority and concurrency explanations from diagnoses.
+func (s *stateStore) schedulingQueueForDiagnosis(jobs []protocol.JobExecution) executiondiagnosis.Queue {
+	queue, err := executiondiagnosis.LoadQueue(s.jobExecutionStore(), jobs)
+	if err != nil {
+		slog.Warn("load queue for scheduling diagnosis", "error", err)
+		return executiondiagnosis.Queue{}
+	}
+	return queue
 }
@@ -24,6 +24,8 @@ func (s *stateStore) jobExecutionHandlerDeps() jobexecution.HandlerDeps {
 		AttachTestSummary:         s.attachJobExecutionTestSummary,
 		AttachSchedulingDiagnosis: s.attachJobExecutionSchedulingDiagnosis,
 		MarkAgentSeen:             s.markAgentSeen,
+		AuthorizeAgent:            s.authorizeAgentCaller,
+		AuthorizeEnrolledAgent:    s.authorizeEnrolledAgentCaller,
 		OnJobUpdated:              s.onJobExecutionUpdated,
 		OnJobStateChanged: func(job protocol.JobExecution) {
 			s.app().changes.PublishForJobExecution(job.ID, application.ChangeQueue, application.ChangeHistory)
@@ -37,8 +39,11 @@ func (s *stateStore) jobExecutionHandlerDeps() jobexecution.HandlerDeps {
 		OnJobHistoryChanged: func(jobExecutionID string) {
 			s.app().changes.PublishForJobExecution(jobExecutionID, application.ChangeHistory)
 		},
-		PrepareRerun:   s.prepareJobExecutionRerun,
-		AttachProgress: attachProgress,
+		PrepareRerun:       s.prepareJobExecutionRerun,
+		AttachProgress:     attachProgress,
+		ListTestQuarantine: s.db.ListTestQuarantineEntries,
+		CoverageBaseline:   s.db.CoverageBaseline,
+		SourceCredential:   s.resolveRepoCredential,
 	}
 }
 
@@ -1,9 +1,11 @@
 package server
 
 import (
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/izzyreal/ciwi/internal/domain"
 	"github.com/izzyreal/ciwi/internal/protocol"
 )
 
@@ -38,6 +40,37 @@ func TestJobExecutionEnrichmentHelpers(t *testing.T) {
 		t.Fatalf("expected ready scheduling diagnosis, got %+v", jobs[0].SchedulingDiagnosis)
 	}
 
+	if _, err := s.db.CreateJobExecution(protocol.CreateJobExecutionRequest{
+		Script: "echo urgent", TimeoutSeconds: 30, RequiredCapabilities: map[string]string{"os": "linux"},
+		Metadata: map[string]string{domain.ExecutionMetadataPriority: "50"},
+	}); err != nil {
+		t.Fatalf("create urgent job: %v", err)
+	}
+	s.attachJobExecutionSchedulingDiagnoses(jobs)
+	if diagnosis := jobs[0].SchedulingDiagnosis; diagnosis == nil || diagnosis.State != "waiting" ||
+		!strings.HasPrefix(diagnosis.Summary, "Waiting behind 1 higher-priority execution;") {
+		t.Fatalf("expected the job to wait behind the urgent one, got %+v", diagnosis)
+	}
+
+	groupMetadata := func(holder string) map[string]string {
+		return map[string]string{domain.ExecutionMetadataConcurrencyGroup: "deploy", domain.ExecutionMetadataConcurrencyHolder: holder}
+	}
+	holder, err := s.db.CreateJobExecution(protocol.CreateJobExecutionRequest{
+		Script: "./deploy", TimeoutSeconds: 30, RequiredCapabilities: map[string]string{"os": "freebsd"}, Metadata: groupMetadata("run-1"),
+	})
+	if err != nil {
+		t.Fatalf("create holder job: %v", err)
+	}
+	if leased, err := s.db.LeaseJobExecution("agent-bsd", map[string]string{"os": "freebsd"}); err != nil || leased == nil || leased.ID != holder.ID {
+		t.Fatalf("expected holder job to be leased, got %+v err=%v", leased, err)
+	}
+	grouped := protocol.JobExecution{ID: "grouped", Status: protocol.JobExecutionStatusQueued, RequiredCapabilities: map[string]string{"os": "linux"}, Metadata: groupMetadata("run-2")}
+	s.attachJobExecutionSchedulingDiagnosis(&grouped)
+	if diagnosis := grouped.SchedulingDiagnosis; diagnosis == nil || diagnosis.State != "waiting" ||
+		diagnosis.Summary != `Concurrency group "deploy" is held by `+holder.ID {
+		t.Fatalf("expected the job to wait for the group holder, got %+v", diagnosis)
+	}
+
 	queued := protocol.JobExecution{ID: "q", Status: protocol.JobExecutionStatusQueued, RequiredCapabilities: map[string]string{"os": "darwin"}}
 	s.attachJobExecutionSchedulingDiagnosis(&queued)
 	if queued.SchedulingDiagnosis == nil || queued.SchedulingDiagnosis.State != "incompatible" {
@@ -56,7 +56,61 @@ func (s *stateStore) jobExecutionGraphContextForTarget(ctx context.Context, targ
 			}
 		}
 	}
-	return buildJobExecutionGraphContext(target, jobs, pipelineDBIDs), nil
+	graphContext := buildJobExecutionGraphContext(target, jobs, pipelineDBIDs)
+	if s.db != nil {
+		if err := attachJobExecutionGraphTestSummaries(ctx, s.db, &graphContext); err != nil {
+			return protocol.JobExecutionGraphContext{}, err
+		}
+	}
+	return graphContext, nil
+}
+
+type jobExecutionTestSummaryLister interface {
+	ListJobExecutionTestSummaries(context.Context, []string) (map[string]protocol.JobExecutionTestSummary, error)
+}
+
+// attachJobExecutionGraphTestSummaries adds up the test reports of the latest
+// attempts of each job, so sharded and matrix jobs show their combined counts.
+func attachJobExecutionGraphTestSummaries(ctx context.Context, reports jobExecutionTestSummaryLister, graphContext *protocol.JobExecutionGraphContext) error {
+	jobIDs := make([]string, 0)
+	for _, pipeline := range graphContext.Pipelines {
+		for _, job := range pipeline.Jobs {
+			for _, execution := range job.Executions {
+				if execution.LatestAttempt {
+					jobIDs = append(jobIDs, execution.ID)
+				}
+			}
+		}
+	}
+	if len(jobIDs) == 0 {
+		return nil
+	}
+	summaries, err := reports.ListJobExecutionTestSummaries(ctx, jobIDs)
+	if err != nil {
+		return err
+	}
+	for p := range graphContext.Pipelines {
+		jobs := graphContext.Pipelines[p].Jobs
+		for j := range jobs {
+			var combined *protocol.JobExecutionTestSummary
+			for _, execution := range jobs[j].Executions {
+				summary, ok := summaries[execution.ID]
+				if !ok || !execution.LatestAttempt {
+					continue
+				}
+				if combined == nil {
+					combined = &protocol.JobExecutionTestSummary{}
+				}
+				combined.Total += summary.Total
+				combined.Passed += summary.Passed
+				combined.Failed += summary.Failed
+				combined.Skipped += summary.Skipped
+				combined.Quarantined += summary.Quarantined
+			}
+			jobs[j].TestSummary = combined
+		}
+	}
+	return nil
 }
 
 func buildJobExecutionGraphContext(target protocol.JobExecution, jobs []protocol.JobExecution, pipelineDBIDs map[string]int64) protocol.JobExecutionGraphContext {
@@ -205,11 +259,16 @@ func buildJobExecutionGraphJobs(jobs []protocol.JobExecution) []protocol.JobExec
 		})
 		for _, job := range group.executions {
 			_, isLatest := latestIDs[job.ID]
+			shardLabel := ""
+			if index, total, ok := job.Metadata.Shard(); ok {
+				shardLabel = domain.ShardLabel(index, total)
+			}
 			executions = append(executions, protocol.JobExecutionGraphExecution{
 				ID:            job.ID,
 				Status:        graphExecutionStatus(job),
 				MatrixIndex:   job.Metadata.Value(domain.ExecutionMetadataMatrixIndex),
 				MatrixName:    job.Metadata.Value(domain.ExecutionMetadataMatrixName),
+				ShardLabel:    shardLabel,
 				AttemptRootID: protocol.JobExecutionAttemptRootID(job),
 				RerunOfJobID:  job.Metadata.Value(protocol.JobMetadataRerunOfJobID),
 				LatestAttempt: isLatest,
@@ -252,12 +311,18 @@ func aggregateJobGraphStatuses(statuses []string) string {
 	if seen[protocol.JobExecutionStatusFailed] {
 		return protocol.JobExecutionStatusFailed
 	}
+	if seen[protocol.JobExecutionStatusCancelled] {
+		return protocol.JobExecutionStatusCancelled
+	}
 	if seen["waiting"] {
 		return "waiting"
 	}
 	if len(seen) > 0 && seen[protocol.JobExecutionStatusSucceeded] {
 		return protocol.JobExecutionStatusSucceeded
 	}
+	if seen[protocol.JobExecutionStatusSkipped] {
+		return protocol.JobExecutionStatusSkipped
+	}
 	return "unknown"
 }
 
@@ -99,4 +99,16 @@ func TestAggregateJobGraphStatusesPrioritizesActiveThenFailure(t *testing.T) {
 	if got := aggregateJobGraphStatuses([]string{"waiting", "failed"}); got != "failed" {
 		t.Fatalf("failure should win over waiting, got %q", got)
 	}
+	if got := aggregateJobGraphStatuses([]string{"cancelled", "failed"}); got != "failed" {
+		t.Fatalf("failure should win over cancellation, got %q", got)
+	}
+	if got := aggregateJobGraphStatuses([]string{"succeeded", "cancelled"}); got != "cancelled" {
+		t.Fatalf("cancellation should win over success, got %q", got)
+	}
+	if got := aggregateJobGraphStatuses([]string{"skipped", "succeeded"}); got != "succeeded" {
+		t.Fatalf("skipped jobs should not hide success, got %q", got)
+	}
+	if got := aggregateJobGraphStatuses([]string{"skipped"}); got != "skipped" {
+		t.Fatalf("all-skipped group should be skipped, got %q", got)
+	}
 }
@@ -43,7 +43,8 @@ func (s *stateStore) prepareJobExecutionRerun(original protocol.JobExecution, re
 		return fmt.Errorf("rerun dependencies are not satisfied: %w", err)
 	}
 	req.DependencyArtifactJobIDs = nil
-	for _, key := range []string{domain.ExecutionMetadataChainCancelled, domain.ExecutionMetadataDependencyBlocked, domain.ExecutionMetadataNeedsBlocked} {
+	// A manual rerun starts with a fresh retry budget.
+	for _, key := range []string{domain.ExecutionMetadataChainCancelled, domain.ExecutionMetadataDependencyBlocked, domain.ExecutionMetadataNeedsBlocked, domain.ExecutionMetadataRetryAttempt, domain.ExecutionMetadataRetryReason} {
 		delete(req.Metadata, key)
 	}
 	dependencyArtifactJobIDs, err := dependencyArtifactJobIDsForJob(original, depCtx)
@@ -72,6 +73,9 @@ func validateRerunNeeds(job protocol.JobExecution, jobs []protocol.JobExecution)
 				continue
 			}
 			found = true
+			if skippedByCondition(candidate) {
+				continue
+			}
 			if protocol.NormalizeJobExecutionStatus(candidate.Status) != protocol.JobExecutionStatusSucceeded {
 				return fmt.Errorf("required job %q latest attempt has status %s", need, protocol.NormalizeJobExecutionStatus(candidate.Status))
 			}
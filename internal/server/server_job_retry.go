@@ -0,0 +1,112 @@
+package server
+
+import (
+	"fmt"
+	"log/slog"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/izzyreal/ciwi/internal/application"
+	"github.com/izzyreal/ciwi/internal/config"
+	"github.com/izzyreal/ciwi/internal/domain"
+	"github.com/izzyreal/ciwi/internal/protocol"
+	"github.com/izzyreal/ciwi/internal/server/jobexecution"
+)
+
+const maxJobRetryBackoff = time.Hour
+
+// retryFailedJobExecutions queues the next attempt of every failed job whose
+// retry policy covers the failure. The store only returns the latest attempt
+// of a job, so a job that was already retried is never retried twice.
+func (s *stateStore) retryFailedJobExecutions(now time.Time) error {
+	pending, err := s.pipelineStore().ListRetryPendingJobExecutions()
+	if err != nil {
+		return err
+	}
+	for _, job := range pending {
+		attempt, kind, ok := nextJobRetryAttempt(job)
+		if !ok {
+			continue
+		}
+		maxAttempts, _ := job.Metadata.Int64(domain.ExecutionMetadataRetryMaxAttempts)
+		reason := fmt.Sprintf("retry %d of %d after %s", attempt, maxAttempts, strings.ReplaceAll(kind, "_", " "))
+		delay := jobRetryBackoff(job, attempt)
+		retried, err := jobexecution.RerunJobExecution(s.jobExecutionStore(), job.ID, func(_ protocol.JobExecution, req *protocol.CreateJobExecutionRequest) error {
+			metadata := domain.ExecutionMetadata(req.Metadata)
+			metadata.Set(domain.ExecutionMetadataRetryAttempt, strconv.Itoa(attempt))
+			metadata.Set(domain.ExecutionMetadataRetryReason, reason)
+			if delay > 0 {
+				metadata.SetFlag(protocol.JobSchedulingBlockedMetadataKey, true)
+				metadata.Set(protocol.JobSchedulingBlockedReasonMetadataKey, reason+"; waiting "+delay.String()+" before starting")
+				metadata.Set(protocol.JobSchedulingRetryUTCMetadataKey, now.Add(delay).Format(time.RFC3339Nano))
+			}
+			return nil
+		})
+		if err != nil {
+			slog.Error("queue job retry", "job_execution_id", job.ID, "error", err)
+			continue
+		}
+		if err := s.pipelineStore().AppendJobExecutionEvents(job.ID, []protocol.JobExecutionEvent{{
+			Type: protocol.JobExecutionEventTypeSystemMessage, TimestampUTC: now,
+			Message: "[control] queued " + reason + " as job " + retried.ID,
+		}}); err != nil {
+			return err
+		}
+		slog.Info("job retry queued", "job_execution_id", job.ID, "retry_job_execution_id", retried.ID, "attempt", attempt, "failure_kind", kind)
+		s.app().changes.PublishForJobExecution(retried.ID, application.ChangeQueue, application.ChangeHistory)
+	}
+	return nil
+}
+
+// nextJobRetryAttempt reports the attempt number a failed job would be retried
+// as and the failure kind that qualified it.
+func nextJobRetryAttempt(job protocol.JobExecution) (int, string, bool) {
+	if protocol.NormalizeJobExecutionStatus(job.Status) != protocol.JobExecutionStatusFailed {
+		return 0, "", false
+	}
+	maxAttempts, ok := job.Metadata.Int64(domain.ExecutionMetadataRetryMaxAttempts)
+	if !ok || maxAttempts <= 1 {
+		return 0, "", false
+	}
+	attempt := int64(1)
+	if current, ok := job.Metadata.Int64(domain.ExecutionMetadataRetryAttempt); ok && current > 1 {
+		attempt = current
+	}
+	if attempt >= maxAttempts {
+		return 0, "", false
+	}
+	kind := jobExecutionFailureKind(job)
+	if kind == "" || !slices.Contains(job.Metadata.CSV(domain.ExecutionMetadataRetryOn), kind) {
+		return 0, "", false
+	}
+	return int(attempt + 1), kind, true
+}
+
+// jobExecutionFailureKind classifies a failed execution for retry policies.
+// Failures without a process exit code, such as a broken secret reference,
+// are not retried.
+func jobExecutionFailureKind(job protocol.JobExecution) string {
+	errText := strings.TrimSpace(job.Error)
+	switch {
+	case strings.HasPrefix(errText, jobExecutionAgentLostErrorMsg):
+		return config.RetryOnAgentLost
+	case strings.Contains(errText, "timed out"):
+		return config.RetryOnTimeout
+	case job.ExitCode != nil && *job.ExitCode != 0:
+		return config.RetryOnExitCode
+	}
+	return ""
+}
+
+// jobRetryBackoff doubles the configured backoff for every retry after the
+// first one.
+func jobRetryBackoff(job protocol.JobExecution, attempt int) time.Duration {
+	seconds, _ := job.Metadata.Int64(domain.ExecutionMetadataRetryBackoffSeconds)
+	delay := time.Duration(seconds) * time.Second
+	for i := 2; i < attempt && delay < maxJobRetryBackoff; i++ {
+		delay *= 2
+	}
+	return min(delay, maxJobRetryBackoff)
+}
@@ -0,0 +1,197 @@
+package server
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/izzyreal/ciwi/internal/domain"
+	"github.com/izzyreal/ciwi/internal/protocol"
+	"github.com/izzyreal/ciwi/internal/store"
+)
+
+func createRetryableRunningJob(t *testing.T, db *store.Store, agentID string, retryMetadata map[string]string) protocol.JobExecution {
+	t.Helper()
+	metadata := map[string]string{
+		domain.ExecutionMetadataProjectID:     "1",
+		domain.ExecutionMetadataPipelineID:    "release",
+		domain.ExecutionMetadataPipelineRunID: "run-1",
+		domain.ExecutionMetadataPipelineJobID: "sign",
+	}
+	for key, value := range retryMetadata {
+		metadata[key] = value
+	}
+	job, err := db.CreateJobExecution(protocol.CreateJobExecutionRequest{
+		Script:               "./sign.ps1",
+		RequiredCapabilities: map[string]string{"os": "windows"},
+		TimeoutSeconds:       300,
+		Metadata:             metadata,
+	})
+	if err != nil {
+		t.Fatalf("create job: %v", err)
+	}
+	leased, err := db.LeaseJobExecution(agentID, map[string]string{"os": "windows"})
+	if err != nil || leased == nil || leased.ID != job.ID {
+		t.Fatalf("lease job: %v", err)
+	}
+	running, err := db.UpdateJobExecutionStatus(job.ID, protocol.JobExecutionStatusUpdateRequest{
+		AgentID: agentID, Status: protocol.JobExecutionStatusRunning,
+	})
+	if err != nil {
+		t.Fatalf("mark running: %v", err)
+	}
+	return running
+}
+
+func latestAttemptOf(t *testing.T, db *store.Store, rootID string) protocol.JobExecution {
+	t.Helper()
+	all, err := db.ListJobExecutions()
+	if err != nil {
+		t.Fatalf("list jobs: %v", err)
+	}
+	for _, job := range protocol.LatestJobExecutionAttempts(all) {
+		if protocol.JobExecutionAttemptRootID(job) == rootID {
+			return job
+		}
+	}
+	t.Fatalf("no attempt found for %q", rootID)
+	return protocol.JobExecution{}
+}
+
+func TestServerMaintenanceRetriesTimedOutJobAfterBackoff(t *testing.T) {
+	db, err := store.Open(filepath.Join(t.TempDir(), "ciwi.db"))
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+	s := &stateStore{db: db}
+
+	job := createRetryableRunningJob(t, db, "agent-win", map[string]string{
+		domain.ExecutionMetadataRetryMaxAttempts:    "3",
+		domain.ExecutionMetadataRetryBackoffSeconds: "30",
+		domain.ExecutionMetadataRetryOn:             "timeout",
+	})
+	if err := s.runJobExecutionMaintenancePass(job.StartedUTC.Add(6 * time.Minute)); err != nil {
+		t.Fatalf("maintenance pass: %v", err)
+	}
+
+	retry := latestAttemptOf(t, db, job.ID)
+	if retry.ID == job.ID {
+		t.Fatal("expected a retry attempt to be queued")
+	}
+	if retry.Status != protocol.JobExecutionStatusQueued {
+		t.Fatalf("expected queued retry, got %q", retry.Status)
+	}
+	if got := retry.Metadata.Value(protocol.JobMetadataRerunOfJobID); got != job.ID {
+		t.Fatalf("expected retry to link to %q, got %q", job.ID, got)
+	}
+	if got := retry.Metadata.Value(domain.ExecutionMetadataRetryAttempt); got != "2" {
+		t.Fatalf("expected retry attempt 2, got %q", got)
+	}
+	if got := retry.Metadata.Value(domain.ExecutionMetadataRetryReason); got != "retry 2 of 3 after timeout" {
+		t.Fatalf("unexpected retry reason %q", got)
+	}
+	if !retry.Metadata.Flag(protocol.JobSchedulingBlockedMetadataKey) {
+		t.Fatal("expected retry to wait for its backoff")
+	}
+	if leased, err := db.LeaseJobExecution("agent-win", map[string]string{"os": "windows"}); err != nil || leased != nil {
+		t.Fatalf("expected retry not to be leased during backoff, got %+v (%v)", leased, err)
+	}
+	events, err := db.ListJobExecutionEvents(job.ID)
+	if err != nil {
+		t.Fatalf("list events: %v", err)
+	}
+	if last := events[len(events)-1].Message; !strings.Contains(last, "queued retry 2 of 3 after timeout as job "+retry.ID) {
+		t.Fatalf("expected retry note on the failed attempt, got %q", last)
+	}
+
+	// A second reconciliation must not queue another attempt for the same failure.
+	if err := s.reconcileBlockedJobExecutions(); err != nil {
+		t.Fatalf("reconcile: %v", err)
+	}
+	all, _ := db.ListJobExecutions()
+	if len(all) != 2 {
+		t.Fatalf("expected exactly one retry, got %d executions", len(all))
+	}
+}
+
+func TestServerMaintenanceFailsJobsOfLostAgentsAndStopsAtMaxAttempts(t *testing.T) {
+	db, err := store.Open(filepath.Join(t.TempDir(), "ciwi.db"))
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+	s := &stateStore{db: db, agentRegistry: newAgentRegistry()}
+	lastSeen := time.Now().UTC()
+	s.agents["agent-win"] = agentState{LastSeenUTC: lastSeen}
+
+	job := createRetryableRunningJob(t, db, "agent-win", map[string]string{
+		domain.ExecutionMetadataRetryMaxAttempts: "2",
+		domain.ExecutionMetadataRetryOn:          "agent_lost,exit_code",
+	})
+	if err := s.runJobExecutionMaintenancePass(lastSeen.Add(time.Minute)); err != nil {
+		t.Fatalf("maintenance pass: %v", err)
+	}
+	if got, _ := db.GetJobExecution(job.ID); got.Status != protocol.JobExecutionStatusRunning {
+		t.Fatalf("expected job to keep running while the agent is briefly silent, got %q", got.Status)
+	}
+	if err := s.runJobExecutionMaintenancePass(lastSeen.Add(3 * time.Minute)); err != nil {
+		t.Fatalf("maintenance pass: %v", err)
+	}
+	lost, _ := db.GetJobExecution(job.ID)
+	if lost.Status != protocol.JobExecutionStatusFailed || !strings.HasPrefix(lost.Error, jobExecutionAgentLostErrorMsg) {
+		t.Fatalf("expected agent lost failure, got %q (%s)", lost.Status, lost.Error)
+	}
+
+	retry := latestAttemptOf(t, db, job.ID)
+	if retry.ID == job.ID || retry.Metadata.Flag(protocol.JobSchedulingBlockedMetadataKey) {
+		t.Fatalf("expected an immediately leasable retry, got %+v", retry.Metadata)
+	}
+	leased, err := db.LeaseJobExecution("agent-win", map[string]string{"os": "windows"})
+	if err != nil || leased == nil || leased.ID != retry.ID {
+		t.Fatalf("lease retry: %+v (%v)", leased, err)
+	}
+	exitCode := 1
+	if _, err := db.UpdateJobExecutionStatus(retry.ID, protocol.JobExecutionStatusUpdateRequest{
+		AgentID: "agent-win", Status: protocol.JobExecutionStatusFailed, ExitCode: &exitCode, Error: "exit status 1",
+	}); err != nil {
+		t.Fatalf("fail retry: %v", err)
+	}
+	if err := s.reconcileBlockedJobExecutions(); err != nil {
+		t.Fatalf("reconcile: %v", err)
+	}
+	if final := latestAttemptOf(t, db, job.ID); final.ID != retry.ID {
+		t.Fatalf("expected no attempt beyond max_attempts, got %q", final.ID)
+	}
+}
+
+func TestNextJobRetryAttemptOnlyRetriesConfiguredFailureKinds(t *testing.T) {
+	exitCode := 2
+	cases := []struct {
+		name   string
+		job    protocol.JobExecution
+		retry  bool
+		expect int
+	}{
+		{name: "exit code", job: protocol.JobExecution{Status: protocol.JobExecutionStatusFailed, ExitCode: &exitCode}, retry: true, expect: 2},
+		{name: "timeout not configured", job: protocol.JobExecution{Status: protocol.JobExecutionStatusFailed, Error: "job timed out after 60 seconds"}},
+		{name: "no exit code", job: protocol.JobExecution{Status: protocol.JobExecutionStatusFailed, Error: "secret resolution failed before execution"}},
+		{name: "cancelled", job: protocol.JobExecution{Status: protocol.JobExecutionStatusCancelled, ExitCode: &exitCode}},
+		{name: "last attempt", job: protocol.JobExecution{Status: protocol.JobExecutionStatusFailed, ExitCode: &exitCode, Metadata: map[string]string{domain.ExecutionMetadataRetryAttempt: "3"}}},
+	}
+	for _, tc := range cases {
+		metadata := domain.ExecutionMetadata{
+			domain.ExecutionMetadataRetryMaxAttempts: "3",
+			domain.ExecutionMetadataRetryOn:          "exit_code,agent_lost",
+		}
+		for key, value := range tc.job.Metadata {
+			metadata[key] = value
+		}
+		tc.job.Metadata = metadata
+		attempt, _, ok := nextJobRetryAttempt(tc.job)
+		if ok != tc.retry || attempt != tc.expect {
+			t.Fatalf("%s: got attempt=%d retry=%v, want attempt=%d retry=%v", tc.name, attempt, ok, tc.expect, tc.retry)
+		}
+	}
+}
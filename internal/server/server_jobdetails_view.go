@@ -91,6 +91,7 @@ type jobTreeNodeResponse struct {
 	ActionPath      string                `json:"action_path"`
 	DefaultExpanded bool                  `json:"default_expanded"`
 	FilterValues    []string              `json:"filter_values"`
+	Badge           string                `json:"badge"`
 	Children        []jobTreeNodeResponse `json:"children"`
 }
 
@@ -349,7 +350,7 @@ func jobTreeNodesToResponse(nodes []presentation.TreeNodeView) []jobTreeNodeResp
 		result = append(result, jobTreeNodeResponse{
 			Key: node.Key, Label: node.Label, Detail: node.Detail, Tone: node.Tone, Link: node.Link,
 			ActionLabel: node.ActionLabel, ActionKind: node.ActionKind, ActionPath: node.ActionPath,
-			DefaultExpanded: node.DefaultExpanded, FilterValues: append([]string{}, node.FilterValues...), Children: jobTreeNodesToResponse(node.Children),
+			DefaultExpanded: node.DefaultExpanded, FilterValues: append([]string{}, node.FilterValues...), Badge: node.Badge, Children: jobTreeNodesToResponse(node.Children),
 		})
 	}
 	return result
@@ -366,6 +367,11 @@ func jobRunContextToResponse(view protocol.JobExecutionGraphContext) jobRunConte
 				if matrixLabel == "" && strings.TrimSpace(execution.MatrixIndex) != "" {
 					matrixLabel = "index-" + strings.TrimSpace(execution.MatrixIndex)
 				}
+				if shardLabel := strings.TrimSpace(execution.ShardLabel); shardLabel != "" && matrixLabel != "" {
+					matrixLabel += " · " + shardLabel
+				} else if shardLabel != "" {
+					matrixLabel = shardLabel
+				}
 				if matrixLabel == "" {
 					matrixLabel = "job"
 				}
@@ -379,7 +385,7 @@ func jobRunContextToResponse(view protocol.JobExecutionGraphContext) jobRunConte
 			}
 			jobs = append(jobs, jobRunContextJobResponse{
 				ID: job.PipelineJobID, Needs: append([]string{}, job.Needs...), Status: job.Status,
-				SummaryLabel: fmt.Sprintf("%s · %d execution(s)", job.Status, len(job.Executions)), Executions: executions,
+				SummaryLabel: job.SummaryLabel(), Executions: executions,
 			})
 		}
 		pipelines = append(pipelines, jobRunContextPipelineResponse{
@@ -13,10 +13,12 @@ import (
 type agentJobExecutionStore interface {
 	CreateJobExecution(req protocol.CreateJobExecutionRequest) (protocol.JobExecution, error)
 	LeaseJobExecution(agentID string, agentCaps map[string]string) (*protocol.JobExecution, error)
+	LeaseJobExecutionWithAffinity(agentID string, agentCaps map[string]string, preferredEligible store.PreferredAgentEligible) (*protocol.JobExecution, error)
 	RequeueLeasedJobExecution(jobID, agentID string, metadataPatch map[string]string) (protocol.JobExecution, error)
 	AgentHasActiveJobExecution(agentID string) (bool, error)
-	ListActiveJobExecutionAgentIDs() (map[string]bool, error)
-	ListActiveJobExecutionAgentIDsContext(context.Context) (map[string]bool, error)
+	CountAgentActiveJobExecutions(agentID string) (int, error)
+	CountActiveJobExecutionsByAgent() (map[string]int, error)
+	CountActiveJobExecutionsByAgentContext(context.Context) (map[string]int, error)
 	UpdateJobExecutionStatus(jobID string, req protocol.JobExecutionStatusUpdateRequest) (protocol.JobExecution, error)
 	MergeJobExecutionEnv(jobID string, patch map[string]string) (map[string]string, error)
 	MergeJobExecutionMetadata(jobID string, patch map[string]string) (map[string]string, error)
@@ -26,6 +28,9 @@ type jobExecutionStore interface {
 	CreateJobExecution(req protocol.CreateJobExecutionRequest) (protocol.JobExecution, error)
 	ListJobExecutions() ([]protocol.JobExecution, error)
 	GetJobExecution(id string) (protocol.JobExecution, error)
+	ListQueuedJobExecutions() ([]protocol.JobExecution, error)
+	ListAgentActiveJobExecutions(agentID string) ([]protocol.JobExecution, error)
+	ListConcurrencyGroupExecutions(group string) ([]protocol.JobExecution, error)
 	UpdateJobExecutionStatus(jobID string, req protocol.JobExecutionStatusUpdateRequest) (protocol.JobExecution, error)
 	MergeJobExecutionMetadata(jobID string, patch map[string]string) (map[string]string, error)
 	AppendJobExecutionEvents(jobID string, events []protocol.JobExecutionEvent) error
@@ -48,8 +53,10 @@ type pipelineStore interface {
 	GetPipelineByDBID(id int64) (store.PersistedPipeline, error)
 	GetPipelineChain(projectID int64, chainID string) (store.PersistedPipelineChain, error)
 	ListJobExecutions() ([]protocol.JobExecution, error)
+	ListRetryPendingJobExecutions() ([]protocol.JobExecution, error)
 	CreateJobExecution(req protocol.CreateJobExecutionRequest) (protocol.JobExecution, error)
 	CreateJobExecutions(reqs []protocol.CreateJobExecutionRequest) ([]protocol.JobExecution, error)
+	LastSucceededAgentForAffinityKey(key string) (string, error)
 	SetJobExecutionDependencyArtifactJobIDs(jobID string, ids []string) ([]string, error)
 	MergeJobExecutionMetadata(jobID string, patch map[string]string) (map[string]string, error)
 	UpdateJobExecutionStatus(jobID string, req protocol.JobExecutionStatusUpdateRequest) (protocol.JobExecution, error)
@@ -79,6 +86,27 @@ type vaultStore interface {
 	GetVaultConnectionByName(name string) (protocol.VaultConnection, error)
 }
 
+type repoCredentialStore interface {
+	ListRepoCredentials() ([]protocol.RepoCredential, error)
+	UpsertRepoCredential(req protocol.UpsertRepoCredentialRequest) (protocol.RepoCredential, error)
+	DeleteRepoCredential(id int64) error
+}
+
+type vcsTriggerStore interface {
+	ListVCSTriggeredPipelines() ([]store.PersistedVCSTrigger, error)
+	RecordVCSPoll(pipelineDBID int64, rec store.VCSPollRecord) error
+}
+
+type scheduleTriggerStore interface {
+	ListScheduleTriggers() ([]store.PersistedScheduleTrigger, error)
+	RecordScheduleCheck(projectID int64, targetKind, targetID string, rec store.ScheduleCheckRecord) error
+}
+
+type projectWebhookStore interface {
+	GetProjectWebhookSecret(projectID int64) (string, error)
+	SetProjectWebhookSecret(projectID int64, secret string) error
+}
+
 type updateStateStore interface {
 	SetAppState(key, value string) error
 	ListAppState() (map[string]string, error)
@@ -105,6 +133,22 @@ func (s *stateStore) vaultStore() vaultStore {
 	return s.db
 }
 
+func (s *stateStore) repoCredentialStore() repoCredentialStore {
+	return s.db
+}
+
+func (s *stateStore) vcsTriggerStore() vcsTriggerStore {
+	return s.db
+}
+
+func (s *stateStore) scheduleTriggerStore() scheduleTriggerStore {
+	return s.db
+}
+
+func (s *stateStore) projectWebhookStore() projectWebhookStore {
+	return s.db
+}
+
 func (s *stateStore) updateStateStore() updateStateStore {
 	return s.db
 }
@@ -114,4 +158,8 @@ var _ jobExecutionStore = (*store.Store)(nil)
 var _ pipelineStore = (*store.Store)(nil)
 var _ projectStore = (*store.Store)(nil)
 var _ vaultStore = (*store.Store)(nil)
+var _ repoCredentialStore = (*store.Store)(nil)
+var _ vcsTriggerStore = (*store.Store)(nil)
+var _ scheduleTriggerStore = (*store.Store)(nil)
+var _ projectWebhookStore = (*store.Store)(nil)
 var _ updateStateStore = (*store.Store)(nil)
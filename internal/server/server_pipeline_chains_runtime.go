@@ -34,6 +34,11 @@ func (s *stateStore) reconcileBlockedJobExecutions() error {
 	s.dependencyMu.Lock()
 	defer s.dependencyMu.Unlock()
 
+	// A failure that will be retried must not skip or cancel dependents, so
+	// queue retries before looking at blocked jobs.
+	if err := s.retryFailedJobExecutions(time.Now().UTC()); err != nil {
+		return err
+	}
 	initial, err := s.pipelineStore().ListJobExecutions()
 	if err != nil {
 		return err
@@ -61,6 +66,12 @@ func (s *stateStore) reconcileOneBlockedJobExecution(all []protocol.JobExecution
 		if protocol.NormalizeJobExecutionStatus(candidate.Status) != protocol.JobExecutionStatusQueued {
 			continue
 		}
+		if reason := candidate.Metadata.Value(domain.ExecutionMetadataConditionSkipped); reason != "" {
+			return true, s.skipBlockedJob(candidate, "server-condition", "if", reason, map[string]string{
+				domain.ExecutionMetadataChainBlocked: "",
+				domain.ExecutionMetadataNeedsBlocked: "",
+			})
+		}
 		if candidate.Metadata.Flag(domain.ExecutionMetadataChainBlocked) {
 			changed, waiting, err := s.reconcileChainBlockedJob(candidate, all)
 			if err != nil || changed {
@@ -94,7 +105,7 @@ func (s *stateStore) reconcileChainBlockedJob(candidate protocol.JobExecution, a
 		}
 		if !succeeded {
 			reason := "cancelled: upstream pipeline " + depID + " failed"
-			return true, false, s.failBlockedJob(candidate, "server-chain", "chain", reason, map[string]string{
+			return true, false, s.skipBlockedJob(candidate, "server-chain", "chain", reason, map[string]string{
 				domain.ExecutionMetadataChainCancelled: "1",
 				domain.ExecutionMetadataChainBlocked:   "",
 			})
@@ -102,7 +113,7 @@ func (s *stateStore) reconcileChainBlockedJob(candidate protocol.JobExecution, a
 	}
 	if err := s.bindQueuedChainJobDependencyArtifacts(candidate, all); err != nil {
 		reason := "cancelled: " + err.Error()
-		return true, false, s.failBlockedJob(candidate, "server-chain", "chain", reason, map[string]string{
+		return true, false, s.skipBlockedJob(candidate, "server-chain", "chain", reason, map[string]string{
 			domain.ExecutionMetadataChainCancelled: "1",
 			domain.ExecutionMetadataChainBlocked:   "",
 		})
@@ -158,7 +169,7 @@ func pipelineChainStatus(all []protocol.JobExecution, chainRunID, pipelineID str
 			succeeded = false
 			continue
 		}
-		if status != protocol.JobExecutionStatusSucceeded {
+		if status != protocol.JobExecutionStatusSucceeded && !skippedByCondition(j) {
 			succeeded = false
 		}
 	}
@@ -182,6 +193,7 @@ func (s *stateStore) reconcileNeedsBlockedJob(candidate protocol.JobExecution, a
 		found := false
 		allTerminal := true
 		allSucceeded := true
+		anySucceeded := false
 		for _, possible := range all {
 			if possible.Metadata.Value(domain.ExecutionMetadataPipelineRunID) != runID ||
 				possible.Metadata.Value(domain.ExecutionMetadataProjectID) != projectID ||
@@ -196,8 +208,13 @@ func (s *stateStore) reconcileNeedsBlockedJob(candidate protocol.JobExecution, a
 				allSucceeded = false
 				continue
 			}
+			if skippedByCondition(possible) {
+				continue
+			}
 			if status != protocol.JobExecutionStatusSucceeded {
 				allSucceeded = false
+			} else {
+				anySucceeded = true
 			}
 		}
 		if !found || !allTerminal {
@@ -205,17 +222,36 @@ func (s *stateStore) reconcileNeedsBlockedJob(candidate protocol.JobExecution, a
 		}
 		if !allSucceeded {
 			reason := "cancelled: required job " + need + " failed"
-			return true, s.failBlockedJob(candidate, "server-needs", "needs", reason, map[string]string{domain.ExecutionMetadataNeedsBlocked: ""})
+			return true, s.skipBlockedJob(candidate, "server-needs", "needs", reason, map[string]string{domain.ExecutionMetadataNeedsBlocked: ""})
+		}
+		if !anySucceeded {
+			// Every entry of the required job had a false condition; skip
+			// this one the same way so its own dependents follow.
+			reason := "required job " + need + " was skipped"
+			return true, s.skipBlockedJob(candidate, "server-needs", "needs", reason, map[string]string{
+				domain.ExecutionMetadataNeedsBlocked:     "",
+				domain.ExecutionMetadataConditionSkipped: reason,
+			})
 		}
 	}
 	_, err := s.pipelineStore().MergeJobExecutionMetadata(candidate.ID, map[string]string{domain.ExecutionMetadataNeedsBlocked: ""})
 	return err == nil, err
 }
 
-func (s *stateStore) failBlockedJob(job protocol.JobExecution, agentID, marker, reason string, metadataPatch map[string]string) error {
+// skippedByCondition reports whether a job did not run because an `if:`
+// condition, its own or one inherited through needs, was false. Such jobs do
+// not fail the pipeline they belong to.
+func skippedByCondition(job protocol.JobExecution) bool {
+	return protocol.NormalizeJobExecutionStatus(job.Status) == protocol.JobExecutionStatusSkipped &&
+		job.Metadata.Value(domain.ExecutionMetadataConditionSkipped) != ""
+}
+
+// skipBlockedJob finishes a job that can no longer run because something it
+// waited on did not succeed.
+func (s *stateStore) skipBlockedJob(job protocol.JobExecution, agentID, marker, reason string, metadataPatch map[string]string) error {
 	if _, err := s.pipelineStore().UpdateJobExecutionStatus(job.ID, protocol.JobExecutionStatusUpdateRequest{
 		AgentID:      agentID,
-		Status:       protocol.JobExecutionStatusFailed,
+		Status:       protocol.JobExecutionStatusSkipped,
 		Error:        reason,
 		TimestampUTC: time.Now().UTC(),
 	}); err != nil {
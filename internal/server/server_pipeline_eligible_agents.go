@@ -1,6 +1,7 @@
 package server
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -33,7 +34,7 @@ func (s *stateStore) pipelineEligibleAgentsHandler(w http.ResponseWriter, p stor
 	options, err := s.app().runOptions.GetRunOptions(r.Context(), application.RunOptionsRequest{
 		PipelineDBID: p.DBID, PipelineJobID: selection.PipelineJobID, MatrixName: selection.MatrixName,
 		MatrixIndex: selection.MatrixIndex, DryRun: selection.DryRun, SourceRef: selection.SourceRef, AgentID: selection.AgentID,
-		ExecutionMode: selection.ExecutionMode, IncludeEligibleAgents: true,
+		ExecutionMode: selection.ExecutionMode, Priority: selection.Priority, IncludeEligibleAgents: true,
 	})
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
@@ -58,7 +59,8 @@ func (s *stateStore) pipelineChainEligibleAgentsHandler(w http.ResponseWriter, c
 	options, err := s.app().runOptions.GetRunOptions(r.Context(), application.RunOptionsRequest{
 		ProjectID: ch.ProjectID, ChainID: ch.ChainID, PipelineJobID: selection.PipelineJobID,
 		MatrixName: selection.MatrixName, MatrixIndex: selection.MatrixIndex, DryRun: selection.DryRun,
-		SourceRef: selection.SourceRef, AgentID: selection.AgentID, ExecutionMode: selection.ExecutionMode, IncludeEligibleAgents: true,
+		SourceRef: selection.SourceRef, AgentID: selection.AgentID, ExecutionMode: selection.ExecutionMode,
+		Priority: selection.Priority, IncludeEligibleAgents: true,
 	})
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
@@ -101,12 +103,12 @@ func (s *stateStore) preparePendingPipelineChainJobs(ch store.PersistedPipelineC
 	if overrideSourceRef != "" && shouldApplySourceRefOverride(firstVersionPipeline.SourceRepo, overrideRepo) {
 		firstVersionPipeline.SourceRef = overrideSourceRef
 	}
-	firstRun, err := resolvePipelineRunContextWithReporter(firstVersionPipeline, firstDep, nil)
+	firstRun, err := resolvePipelineRunContextWithReporter(s.withRepoCredential(context.Background(), firstVersionPipeline.SourceRepo), firstVersionPipeline, firstDep, nil)
 	if err != nil {
 		return nil, err
 	}
 	if firstRun.SourceRefResolved == "" && strings.TrimSpace(firstVersionPipeline.SourceRepo) != "" {
-		resolved, err := resolveSourceRefFromRepo(strings.TrimSpace(firstVersionPipeline.SourceRepo), strings.TrimSpace(firstVersionPipeline.SourceRef))
+		resolved, err := resolveSourceRefFromRepo(s.withRepoCredential(context.Background(), firstVersionPipeline.SourceRepo), strings.TrimSpace(firstVersionPipeline.SourceRepo), strings.TrimSpace(firstVersionPipeline.SourceRef))
 		if err != nil {
 			return nil, err
 		}
@@ -191,6 +193,6 @@ func (s *stateStore) eligibleAgentsForPendingJobs(pending []pendingJob) []string
 
 func agentMatchesRequiredCapabilities(agentID string, agent agentState, required map[string]string) bool {
 	return requirements.MatchAgent(required, requirements.AgentSnapshot{
-		ID: agentID, OS: agent.OS, Arch: agent.Arch, Capabilities: agent.Capabilities,
+		ID: agentID, OS: agent.OS, Arch: agent.Arch, Capabilities: agent.Capabilities, Labels: agent.effectiveLabels(),
 	}).Matches
 }
@@ -1,6 +1,7 @@
 package server
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -48,6 +49,7 @@ type runPreviewStep struct {
 	TestReport      string                       `json:"test_report,omitempty"`
 	CoverageFormat  string                       `json:"coverage_format,omitempty"`
 	CoverageReport  string                       `json:"coverage_report,omitempty"`
+	CoverageGate    *protocol.CoverageGate       `json:"coverage_gate,omitempty"`
 }
 
 type runPreviewResponse struct {
@@ -152,12 +154,12 @@ func (s *stateStore) previewSinglePipelineDryRun(p store.PersistedPipeline, req
 		if err != nil {
 			return nil, false, "", nil, err
 		}
-		runCtx, err := resolvePipelineRunContextWithReporter(p, depCtx, nil)
+		runCtx, err := resolvePipelineRunContextWithReporter(s.withRepoCredential(context.Background(), p.SourceRepo), p, depCtx, nil)
 		if err != nil {
 			return nil, false, "", nil, err
 		}
 		if runCtx.SourceRefResolved == "" && sourceRef != "" && shouldApplySourceRefOverride(p.SourceRepo, opts.sourceRefOverrideRepo) {
-			resolved, err := resolveSourceRefFromRepo(strings.TrimSpace(p.SourceRepo), strings.TrimSpace(p.SourceRef))
+			resolved, err := resolveSourceRefFromRepo(s.withRepoCredential(context.Background(), p.SourceRepo), strings.TrimSpace(p.SourceRepo), strings.TrimSpace(p.SourceRef))
 			if err != nil {
 				return nil, false, "", nil, err
 			}
@@ -273,7 +275,7 @@ func (s *stateStore) previewPipelineChainDryRun(ch store.PersistedPipelineChain,
 
 func (s *stateStore) resolveCachedPreviewRunContext(p store.PersistedPipeline, depCtx pipelineDependencyContext, sel *protocol.RunPipelineSelectionRequest) (pipelineRunContext, bool, string, []string, error) {
 	if depCtx.Version != "" {
-		ctx, err := resolvePipelineRunContextWithReporter(p, depCtx, nil)
+		ctx, err := resolvePipelineRunContextWithReporter(s.withRepoCredential(context.Background(), p.SourceRepo), p, depCtx, nil)
 		if err != nil {
 			return pipelineRunContext{}, false, "", nil, err
 		}
@@ -402,6 +404,7 @@ func toRunPreviewSteps(in []protocol.JobStepPlanItem) []runPreviewStep {
 			TestReport:      strings.TrimSpace(step.TestReport),
 			CoverageFormat:  strings.TrimSpace(step.CoverageFormat),
 			CoverageReport:  strings.TrimSpace(step.CoverageReport),
+			CoverageGate:    step.CoverageGate,
 		})
 	}
 	return out
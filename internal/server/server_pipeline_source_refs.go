@@ -167,7 +167,7 @@ func listRemoteBranchRefsContext(parent context.Context, repoURL string) ([]sour
 	return options, nil
 }
 
-func resolveSourceRefFromRepo(repoURL, sourceRef string) (string, error) {
+func resolveSourceRefFromRepo(parent context.Context, repoURL, sourceRef string) (string, error) {
 	repoURL = strings.TrimSpace(repoURL)
 	sourceRef = strings.TrimSpace(sourceRef)
 	if repoURL == "" {
@@ -179,7 +179,7 @@ func resolveSourceRefFromRepo(repoURL, sourceRef string) (string, error) {
 	}
 	defer func() { _ = os.RemoveAll(tmpDir) }()
 
-	ctx, cancel := context.WithTimeout(context.Background(), 45*time.Second)
+	ctx, cancel := context.WithTimeout(parent, 45*time.Second)
 	defer cancel()
 	if _, err := runCmd(ctx, "", "git", "clone", "--depth", "1", repoURL, tmpDir); err != nil {
 		return "", fmt.Errorf("clone source for source ref resolution: %w", err)
@@ -201,3 +201,61 @@ func resolveSourceRefFromRepo(repoURL, sourceRef string) (string, error) {
 	}
 	return strings.TrimSpace(sha), nil
 }
+
+// resolveRemoteCommitContext asks the remote which commit sourceRef points to
+// without cloning. An empty ref resolves the remote HEAD; branch names win
+// over tags of the same name, and annotated tags resolve to their commit.
+func resolveRemoteCommitContext(parent context.Context, repoURL, sourceRef string) (string, error) {
+	repoURL = strings.TrimSpace(repoURL)
+	sourceRef = strings.TrimSpace(sourceRef)
+	if repoURL == "" {
+		return "", fmt.Errorf("resolve remote commit: empty repo url")
+	}
+	if isFullCommitSHA(sourceRef) {
+		return strings.ToLower(sourceRef), nil
+	}
+	pattern := sourceRef
+	if pattern == "" {
+		pattern = "HEAD"
+	}
+	ctx, cancel := context.WithTimeout(parent, 20*time.Second)
+	defer cancel()
+	// Peeled annotated tags are listed as "<ref>^{}" and must be asked for
+	// explicitly because ls-remote patterns match whole ref name suffixes.
+	out, err := runCmd(ctx, "", "git", "ls-remote", repoURL, pattern, pattern+"^{}")
+	if err != nil {
+		return "", fmt.Errorf("list remote refs from %s: %w", repoURL, err)
+	}
+	commits := map[string]string{}
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 || !isFullCommitSHA(fields[0]) {
+			continue
+		}
+		commits[fields[1]] = strings.ToLower(fields[0])
+	}
+	candidates := []string{pattern}
+	if pattern != "HEAD" && !strings.HasPrefix(pattern, "refs/") {
+		candidates = []string{"refs/heads/" + pattern, "refs/tags/" + pattern + "^{}", "refs/tags/" + pattern}
+	} else if strings.HasPrefix(pattern, "refs/tags/") {
+		candidates = []string{pattern + "^{}", pattern}
+	}
+	for _, candidate := range candidates {
+		if sha := commits[candidate]; sha != "" {
+			return sha, nil
+		}
+	}
+	return "", fmt.Errorf("ref %q not found in %s", pattern, repoURL)
+}
+
+func isFullCommitSHA(value string) bool {
+	if len(value) != 40 {
+		return false
+	}
+	for _, r := range value {
+		if (r < '0' || r > '9') && (r < 'a' || r > 'f') && (r < 'A' || r > 'F') {
+			return false
+		}
+	}
+	return true
+}
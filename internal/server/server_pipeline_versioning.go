@@ -16,7 +16,9 @@ import (
 
 var semverCorePattern = regexp.MustCompile(`^[0-9]+\.[0-9]+\.[0-9]+$`)
 
-func resolvePipelineRunContextWithReporter(p store.PersistedPipeline, dep pipelineDependencyContext, report resolveStepReporter) (pipelineRunContext, error) {
+// resolvePipelineRunContextWithReporter runs git under parent, which carries
+// the repository credential for p's source.
+func resolvePipelineRunContextWithReporter(parent context.Context, p store.PersistedPipeline, dep pipelineDependencyContext, report resolveStepReporter) (pipelineRunContext, error) {
 	ctx := pipelineRunContext{}
 	ctx.SourceRefRaw = strings.TrimSpace(p.SourceRef)
 	file := strings.TrimSpace(p.Versioning.File)
@@ -71,7 +73,7 @@ func resolvePipelineRunContextWithReporter(p store.PersistedPipeline, dep pipeli
 	if report != nil {
 		report("version", "running", fmt.Sprintf("resolving from %s at ref %s", p.SourceRepo, strings.TrimSpace(p.SourceRef)))
 	}
-	versionRaw, sourceRefResolved, err := readVersionFromRepo(p.SourceRepo, p.SourceRef, ctx.VersionFile, report)
+	versionRaw, sourceRefResolved, err := readVersionFromRepo(parent, p.SourceRepo, p.SourceRef, ctx.VersionFile, report)
 	if err != nil {
 		if ctx.AutoBump != "" {
 			if report != nil {
@@ -95,7 +97,7 @@ func resolvePipelineRunContextWithReporter(p store.PersistedPipeline, dep pipeli
 }
 
 func resolvePipelineRunContext(p store.PersistedPipeline, dep pipelineDependencyContext) (pipelineRunContext, error) {
-	return resolvePipelineRunContextWithReporter(p, dep, nil)
+	return resolvePipelineRunContextWithReporter(context.Background(), p, dep, nil)
 }
 
 func toProtocolStepVaultSecrets(in []config.StepVaultSecretRef) []protocol.ProjectSecretSpec {
@@ -115,14 +117,14 @@ func toProtocolStepVaultSecrets(in []config.StepVaultSecretRef) []protocol.Proje
 	return out
 }
 
-func readVersionFromRepo(repoURL, sourceRef, versionFile string, report resolveStepReporter) (string, string, error) {
+func readVersionFromRepo(parent context.Context, repoURL, sourceRef, versionFile string, report resolveStepReporter) (string, string, error) {
 	tmpDir, err := os.MkdirTemp("", "ciwi-version-*")
 	if err != nil {
 		return "", "", fmt.Errorf("create temp dir for version resolution: %w", err)
 	}
 	defer func() { _ = os.RemoveAll(tmpDir) }()
 
-	ctx, cancel := context.WithTimeout(context.Background(), 45*time.Second)
+	ctx, cancel := context.WithTimeout(parent, 45*time.Second)
 	defer cancel()
 
 	if report != nil {
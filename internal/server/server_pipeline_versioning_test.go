@@ -1,6 +1,7 @@
 package server
 
 import (
+	"context"
 	"os"
 	"path/filepath"
 	"strings"
@@ -92,7 +93,7 @@ func TestResolvePipelineRunContextOptionalAndRequiredFailures(t *testing.T) {
 		reports = append(reports, step+":"+status+":"+message)
 	}
 	plain := store.PersistedPipeline{SourceRef: " main "}
-	got, err := resolvePipelineRunContextWithReporter(plain, pipelineDependencyContext{}, report)
+	got, err := resolvePipelineRunContextWithReporter(context.Background(), plain, pipelineDependencyContext{}, report)
 	if err != nil || got.SourceRefRaw != "main" {
 		t.Fatalf("unversioned pipeline should retain raw ref without failing: got=%+v err=%v", got, err)
 	}
@@ -105,7 +106,7 @@ func TestResolvePipelineRunContextOptionalAndRequiredFailures(t *testing.T) {
 		SourceRepo: filepath.Join(t.TempDir(), "missing.git"),
 		Versioning: config.PipelineVersioning{File: "VERSION"},
 	}
-	got, err = resolvePipelineRunContextWithReporter(optional, pipelineDependencyContext{}, report)
+	got, err = resolvePipelineRunContextWithReporter(context.Background(), optional, pipelineDependencyContext{}, report)
 	if err != nil || got.Version != "" || got.VersionRaw != "" || got.SourceRefRaw != "" || got.VersionFile != "" || len(got.AutoBumpSecrets) != 0 {
 		t.Fatalf("optional version resolution should degrade to an empty context: got=%+v err=%v", got, err)
 	}
@@ -114,7 +115,7 @@ func TestResolvePipelineRunContextOptionalAndRequiredFailures(t *testing.T) {
 	}
 
 	optional.Versioning.AutoBump = "patch"
-	if _, err := resolvePipelineRunContextWithReporter(optional, pipelineDependencyContext{}, report); err == nil {
+	if _, err := resolvePipelineRunContextWithReporter(context.Background(), optional, pipelineDependencyContext{}, report); err == nil {
 		t.Fatalf("auto-bump must fail when the source version cannot be resolved")
 	}
 }
@@ -132,7 +133,7 @@ func TestReadVersionFromRepoValidatesFileAndReportsProgress(t *testing.T) {
 	expectedSHA := strings.TrimSpace(runGit(t, repo, "rev-parse", "HEAD"))
 
 	var reports []string
-	raw, sha, err := readVersionFromRepo(repo, "", "VERSION", func(step, status, message string) {
+	raw, sha, err := readVersionFromRepo(context.Background(), repo, "", "VERSION", func(step, status, message string) {
 		reports = append(reports, step+":"+status+":"+message)
 	})
 	if err != nil {
@@ -145,7 +146,7 @@ func TestReadVersionFromRepoValidatesFileAndReportsProgress(t *testing.T) {
 		t.Fatalf("expected checkout and validation progress, got %v", reports)
 	}
 
-	if _, _, err := readVersionFromRepo(repo, "", "../VERSION", nil); err == nil || !strings.Contains(err.Error(), "invalid versioning.file") {
+	if _, _, err := readVersionFromRepo(context.Background(), repo, "", "../VERSION", nil); err == nil || !strings.Contains(err.Error(), "invalid versioning.file") {
 		t.Fatalf("expected unsafe version path to fail, got %v", err)
 	}
 	if err := os.WriteFile(filepath.Join(repo, "BAD_VERSION"), []byte("2.4"), 0o644); err != nil {
@@ -153,7 +154,7 @@ func TestReadVersionFromRepoValidatesFileAndReportsProgress(t *testing.T) {
 	}
 	runGit(t, repo, "add", "BAD_VERSION")
 	runGit(t, repo, "commit", "-m", "invalid version fixture")
-	if _, _, err := readVersionFromRepo(repo, "", "BAD_VERSION", nil); err == nil || !strings.Contains(err.Error(), "semver core") {
+	if _, _, err := readVersionFromRepo(context.Background(), repo, "", "BAD_VERSION", nil); err == nil || !strings.Contains(err.Error(), "semver core") {
 		t.Fatalf("expected invalid semver to fail, got %v", err)
 	}
 }
@@ -1,6 +1,7 @@
 package server
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -116,6 +117,7 @@ func (s *stateStore) pipelineByIDHandler(w http.ResponseWriter, r *http.Request)
 		SourceRef:      req.SourceRef,
 		AgentID:        req.AgentID,
 		ExecutionMode:  req.ExecutionMode,
+		Priority:       req.Priority,
 		IdempotencyKey: strings.TrimSpace(r.Header.Get("Idempotency-Key")),
 	})
 	if err != nil {
@@ -153,7 +155,7 @@ func (s *stateStore) pipelineChainActionHandler(w http.ResponseWriter, r *http.R
 		resp, err := s.app().pipelineChains.RunPipelineChain(r.Context(), application.RunPipelineChainRequest{
 			ProjectID: projectID, ChainID: chainID, PipelineJobID: req.PipelineJobID, MatrixName: req.MatrixName,
 			MatrixIndex: req.MatrixIndex, DryRun: req.DryRun, SourceRef: req.SourceRef, AgentID: req.AgentID,
-			ExecutionMode: req.ExecutionMode, IdempotencyKey: strings.TrimSpace(r.Header.Get("Idempotency-Key")),
+			ExecutionMode: req.ExecutionMode, Priority: req.Priority, IdempotencyKey: strings.TrimSpace(r.Header.Get("Idempotency-Key")),
 		})
 		if err != nil {
 			http.Error(w, err.Error(), applicationErrorHTTPStatus(err))
@@ -197,7 +199,14 @@ func (s *stateStore) pipelineChainActionHandler(w http.ResponseWriter, r *http.R
 }
 
 func (s *stateStore) enqueuePersistedPipelineChain(ch store.PersistedPipelineChain, selection *protocol.RunPipelineSelectionRequest) (protocol.RunPipelineResponse, error) {
+	return s.enqueueTriggeredPipelineChain(ch, selection, pipelineTrigger{})
+}
+
+func (s *stateStore) enqueueTriggeredPipelineChain(ch store.PersistedPipelineChain, selection *protocol.RunPipelineSelectionRequest, trigger pipelineTrigger) (protocol.RunPipelineResponse, error) {
 	if normalizeExecutionMode(selection) == executionModeOfflineCached {
+		if trigger.Kind != "" {
+			return protocol.RunPipelineResponse{}, fmt.Errorf("triggered runs cannot use offline cached execution mode")
+		}
 		return s.enqueuePersistedPipelineChainOfflineCached(ch, selection)
 	}
 	if len(ch.Pipelines) == 0 {
@@ -221,12 +230,12 @@ func (s *stateStore) enqueuePersistedPipelineChain(ch store.PersistedPipelineCha
 	if overrideSourceRef != "" && shouldApplySourceRefOverride(firstVersionPipeline.SourceRepo, overrideRepo) {
 		firstVersionPipeline.SourceRef = overrideSourceRef
 	}
-	firstRun, err := resolvePipelineRunContextWithReporter(firstVersionPipeline, firstDep, nil)
+	firstRun, err := resolvePipelineRunContextWithReporter(s.withRepoCredential(context.Background(), firstVersionPipeline.SourceRepo), firstVersionPipeline, firstDep, nil)
 	if err != nil {
 		return protocol.RunPipelineResponse{}, err
 	}
 	if firstRun.SourceRefResolved == "" && strings.TrimSpace(firstVersionPipeline.SourceRepo) != "" {
-		resolved, err := resolveSourceRefFromRepo(strings.TrimSpace(firstVersionPipeline.SourceRepo), strings.TrimSpace(firstVersionPipeline.SourceRef))
+		resolved, err := resolveSourceRefFromRepo(s.withRepoCredential(context.Background(), firstVersionPipeline.SourceRepo), strings.TrimSpace(firstVersionPipeline.SourceRepo), strings.TrimSpace(firstVersionPipeline.SourceRef))
 		if err != nil {
 			return protocol.RunPipelineResponse{}, err
 		}
@@ -261,6 +270,9 @@ func (s *stateStore) enqueuePersistedPipelineChain(ch store.PersistedPipelineCha
 		if len(chainDeps) > 0 {
 			meta.Set(domain.ExecutionMetadataChainDependsOnPipelines, strings.Join(chainDeps, ","))
 		}
+		if kind := strings.TrimSpace(trigger.Kind); kind != "" {
+			meta.Set(domain.ExecutionMetadataTrigger, kind)
+		}
 		opts := enqueuePipelineOptions{
 			metaPatch:             meta,
 			blocked:               len(chainDeps) > 0,
@@ -1,7 +1,9 @@
 package server
 
 import (
+	"context"
 	"fmt"
+	"log/slog"
 	"strings"
 	"time"
 
@@ -20,6 +22,14 @@ type enqueuePipelineOptions struct {
 	allowUnsatisfiedDeps   bool
 	sourceRefOverride      string
 	sourceRefOverrideRepo  string
+	sourceCommit           string
+}
+
+// pipelineTrigger describes an automatic run start, such as a new commit
+// observed by the vcs poller. The zero value is a manual run.
+type pipelineTrigger struct {
+	Kind   string
+	Commit string
 }
 
 type pendingJob struct {
@@ -32,17 +42,39 @@ type pendingJob struct {
 	artifactGlobs            []string
 	dependencyArtifactJobIDs []string
 	caches                   []protocol.JobCacheSpec
+	services                 []protocol.JobServiceSpec
 	sourceRepo               string
 	sourceRef                string
+	sourceCheckout           protocol.SourceCheckoutOptions
 	metadata                 domain.ExecutionMetadata
 	stepPlan                 []protocol.JobStepPlanItem
 }
 
 func (s *stateStore) enqueuePersistedPipeline(p store.PersistedPipeline, selection *protocol.RunPipelineSelectionRequest) (protocol.RunPipelineResponse, error) {
+	return s.enqueueTriggeredPipeline(p, selection, pipelineTrigger{})
+}
+
+func (s *stateStore) enqueueTriggeredPipeline(p store.PersistedPipeline, selection *protocol.RunPipelineSelectionRequest, trigger pipelineTrigger) (protocol.RunPipelineResponse, error) {
 	if normalizeExecutionMode(selection) == executionModeOfflineCached {
+		if trigger.Kind != "" || trigger.Commit != "" {
+			return protocol.RunPipelineResponse{}, fmt.Errorf("triggered runs cannot use offline cached execution mode")
+		}
 		return s.enqueuePersistedPipelineOfflineCached(p, selection)
 	}
 	opts := enqueuePipelineOptions{}
+	if commit := strings.TrimSpace(trigger.Commit); commit != "" {
+		if strings.TrimSpace(p.SourceRepo) == "" {
+			return protocol.RunPipelineResponse{}, fmt.Errorf("source commit requires pipeline vcs_source.repo")
+		}
+		opts.sourceCommit = commit
+	}
+	if kind := strings.TrimSpace(trigger.Kind); kind != "" {
+		opts.metaPatch = domain.ExecutionMetadata{}
+		opts.metaPatch.Set(domain.ExecutionMetadataTrigger, kind)
+		if opts.sourceCommit != "" {
+			opts.metaPatch.Set(domain.ExecutionMetadataTriggerCommit, opts.sourceCommit)
+		}
+	}
 	if sourceRef := normalizeSourceRef(selection); sourceRef != "" {
 		if strings.TrimSpace(p.SourceRepo) == "" {
 			return protocol.RunPipelineResponse{}, fmt.Errorf("source_ref override requires pipeline vcs_source.repo")
@@ -83,20 +115,25 @@ func (s *stateStore) preparePendingPipelineJobs(p store.PersistedPipeline, selec
 		runCtx = *opts.forcedRun
 	} else {
 		var err error
-		runCtx, err = resolvePipelineRunContextWithReporter(p, depCtx, nil)
+		runCtx, err = resolvePipelineRunContextWithReporter(s.withRepoCredential(context.Background(), p.SourceRepo), p, depCtx, nil)
 		if err != nil {
 			return pipelineRunContext{}, nil, err
 		}
 	}
+	if commit := strings.TrimSpace(opts.sourceCommit); commit != "" {
+		// Triggered runs must build exactly the commit that triggered them,
+		// even if the branch moved while the run context was resolved.
+		runCtx.SourceRefResolved = commit
+	}
 	if runCtx.SourceRefResolved == "" && overrideSourceRef != "" && shouldApplySourceRefOverride(p.SourceRepo, opts.sourceRefOverrideRepo) {
-		resolved, err := resolveSourceRefFromRepo(strings.TrimSpace(p.SourceRepo), strings.TrimSpace(p.SourceRef))
+		resolved, err := resolveSourceRefFromRepo(s.withRepoCredential(context.Background(), p.SourceRepo), strings.TrimSpace(p.SourceRepo), strings.TrimSpace(p.SourceRef))
 		if err != nil {
 			return pipelineRunContext{}, nil, err
 		}
 		runCtx.SourceRefResolved = resolved
 	}
 	if runCtx.SourceRefResolved == "" && strings.TrimSpace(p.SourceRepo) != "" {
-		resolved, err := resolveSourceRefFromRepo(strings.TrimSpace(p.SourceRepo), strings.TrimSpace(p.SourceRef))
+		resolved, err := resolveSourceRefFromRepo(s.withRepoCredential(context.Background(), p.SourceRepo), strings.TrimSpace(p.SourceRepo), strings.TrimSpace(p.SourceRef))
 		if err != nil {
 			return pipelineRunContext{}, nil, err
 		}
@@ -169,8 +206,9 @@ func (s *stateStore) persistPendingJobs(pending []pendingJob) ([]string, error)
 	for _, spec := range pending {
 		var source *protocol.SourceSpec
 		if strings.TrimSpace(spec.sourceRepo) != "" {
-			source = &protocol.SourceSpec{Repo: spec.sourceRepo, Ref: spec.sourceRef}
+			source = &protocol.SourceSpec{Repo: spec.sourceRepo, Ref: spec.sourceRef, SourceCheckoutOptions: spec.sourceCheckout.Clone()}
 		}
+		s.setAgentAffinityMetadata(spec.metadata)
 		requests = append(requests, protocol.CreateJobExecutionRequest{
 			Script:                   spec.script,
 			Env:                      cloneMap(spec.env),
@@ -179,6 +217,7 @@ func (s *stateStore) persistPendingJobs(pending []pendingJob) ([]string, error)
 			ArtifactGlobs:            append([]string(nil), spec.artifactGlobs...),
 			DependencyArtifactJobIDs: append([]string(nil), spec.dependencyArtifactJobIDs...),
 			Caches:                   cloneProtocolJobCaches(spec.caches),
+			Services:                 spec.services,
 			Source:                   source,
 			Metadata:                 spec.metadata,
 			StepPlan:                 cloneJobStepPlan(spec.stepPlan),
@@ -189,8 +228,20 @@ func (s *stateStore) persistPendingJobs(pending []pendingJob) ([]string, error)
 		return nil, err
 	}
 	jobIDs := make([]string, 0, len(jobs))
+	conditionSkipped := false
 	for _, job := range jobs {
 		jobIDs = append(jobIDs, job.ID)
+		conditionSkipped = conditionSkipped || job.Metadata.Value(domain.ExecutionMetadataConditionSkipped) != ""
+	}
+	if err := s.supersedeConcurrencyGroups(jobs); err != nil {
+		slog.Error("supersede concurrency groups", "error", err)
+	}
+	if conditionSkipped {
+		// Jobs whose condition is false finish as skipped right away; the
+		// reconciler also settles their dependents.
+		if err := s.reconcileBlockedJobExecutions(); err != nil {
+			slog.Error("reconcile condition-skipped job executions", "error", err)
+		}
 	}
 	return jobIDs, nil
 }
@@ -1,10 +1,13 @@
 package server
 
 import (
+	"encoding/json"
 	"fmt"
+	"log/slog"
 	"strconv"
 	"strings"
 
+	"github.com/izzyreal/ciwi/internal/condition"
 	"github.com/izzyreal/ciwi/internal/config"
 	"github.com/izzyreal/ciwi/internal/domain"
 	"github.com/izzyreal/ciwi/internal/protocol"
@@ -19,6 +22,9 @@ func (s *stateStore) buildPendingPipelineJobs(
 	depCtx pipelineDependencyContext,
 	runID string,
 ) ([]pendingJob, error) {
+	if selection != nil && selection.Priority != nil && (*selection.Priority < config.MinPriority || *selection.Priority > config.MaxPriority) {
+		return nil, fmt.Errorf("priority must be between %d and %d", config.MinPriority, config.MaxPriority)
+	}
 	pending := make([]pendingJob, 0)
 	sortedJobs := p.SortedJobs()
 	selectedJobIDs := map[string]bool{}
@@ -63,36 +69,53 @@ func (s *stateStore) buildPendingPipelineJobs(
 					continue
 				}
 			}
-			spec, err := s.buildPendingPipelineJobMatrixEntry(
-				p,
-				pj.ID,
-				pj.Steps,
-				pj.RunsOn,
-				pj.RequiresTools,
-				pj.RequiresContainerTools,
-				pj.TimeoutSeconds,
-				pj.Artifacts,
-				pj.ArtifactSources,
-				pj.Caches,
-				pj.Position,
-				index,
-				vars,
-				originalMatrixEntries,
-				needs,
-				missingNeedsByJobID[pj.ID],
-				selection,
-				opts,
-				runCtx,
-				depCtx,
-				runID,
-			)
-			if err != nil {
-				return nil, err
-			}
-			if spec == nil {
-				continue
+			for _, shard := range s.pipelineJobShards(p, pj, vars) {
+				spec, err := s.buildPendingPipelineJobMatrixEntry(
+					p,
+					pj.ID,
+					pj.If,
+					pj.Retry,
+					pj.Steps,
+					pj.RunsOn,
+					pj.RequiresTools,
+					pj.RequiresContainerTools,
+					pj.TimeoutSeconds,
+					pj.Artifacts,
+					pj.ArtifactSources,
+					pj.Caches,
+					pj.Services,
+					pj.Position,
+					index,
+					vars,
+					shard,
+					originalMatrixEntries,
+					needs,
+					missingNeedsByJobID[pj.ID],
+					selection,
+					opts,
+					runCtx,
+					depCtx,
+					runID,
+				)
+				if err != nil {
+					return nil, err
+				}
+				if spec == nil {
+					continue
+				}
+				priority := pj.Priority
+				if selection != nil && selection.Priority != nil {
+					priority = *selection.Priority
+				}
+				if priority != 0 {
+					spec.metadata.Set(domain.ExecutionMetadataPriority, strconv.Itoa(priority))
+				}
+				if selection == nil || !selection.DryRun {
+					// Dry runs neither wait for nor supersede real runs.
+					setConcurrencyGroupMetadata(spec.metadata, p, pj, pipelineConditionVars(p, vars, runCtx, false), runID)
+				}
+				pending = append(pending, *spec)
 			}
-			pending = append(pending, *spec)
 		}
 	}
 	return pending, nil
@@ -101,6 +124,8 @@ func (s *stateStore) buildPendingPipelineJobs(
 func (s *stateStore) buildPendingPipelineJobMatrixEntry(
 	p store.PersistedPipeline,
 	pipelineJobID string,
+	jobCondition string,
+	retry *config.RetryPolicy,
 	steps []config.PipelineJobStep,
 	runsOn map[string]string,
 	requiresTools map[string]string,
@@ -109,9 +134,11 @@ func (s *stateStore) buildPendingPipelineJobMatrixEntry(
 	artifacts []string,
 	artifactSources []config.PipelineJobArtifactSource,
 	caches []config.PipelineJobCacheSpec,
+	services []config.PipelineJobServiceSpec,
 	pipelineJobIndex int,
 	matrixIndex int,
 	matrixVars map[string]string,
+	shard pipelineJobShard,
 	originalMatrixEntries []map[string]string,
 	needs []string,
 	missingNeeds []string,
@@ -134,6 +161,18 @@ func (s *stateStore) buildPendingPipelineJobMatrixEntry(
 	if runCtx.TagPrefix != "" {
 		renderVars["ciwi.tag_prefix"] = runCtx.TagPrefix
 	}
+	dryRun := selection != nil && selection.DryRun
+	conditionVars := pipelineConditionVars(p, matrixVars, runCtx, dryRun)
+	jobSkipReason := ""
+	if strings.TrimSpace(jobCondition) != "" {
+		expr, err := condition.Parse(jobCondition)
+		if err != nil {
+			return nil, fmt.Errorf("pipeline job %q: %w", pipelineJobID, err)
+		}
+		if !expr.Evaluate(condition.Context{Vars: conditionVars}) {
+			jobSkipReason = condition.SkipReason(jobCondition)
+		}
+	}
 	rendered := make([]string, 0, len(steps))
 	stepPlan := make([]protocol.JobStepPlanItem, 0, len(steps))
 	env := make(map[string]string)
@@ -156,7 +195,7 @@ func (s *stateStore) buildPendingPipelineJobMatrixEntry(
 				})
 			}
 		}
-		if selection != nil && selection.DryRun && step.SkipDryRun {
+		if dryRun && step.SkipDryRun {
 			stepPlan = append(stepPlan, protocol.JobStepPlanItem{
 				Name:            describePipelineStep(step, idx, pipelineJobID),
 				YAMLLiteral:     pipelineStepYAMLLiteral(step),
@@ -167,6 +206,30 @@ func (s *stateStore) buildPendingPipelineJobMatrixEntry(
 			})
 			continue
 		}
+		runWhen, skipReason := "", ""
+		if strings.TrimSpace(step.If) != "" {
+			when, skip, err := planStepCondition(step.If, conditionVars)
+			if err != nil {
+				return nil, fmt.Errorf("pipeline job %q step %d: %w", pipelineJobID, idx+1, err)
+			}
+			if skip {
+				stepPlan = append(stepPlan, protocol.JobStepPlanItem{
+					Name:            describePipelineStep(step, idx, pipelineJobID),
+					YAMLLiteral:     pipelineStepYAMLLiteral(step),
+					Kind:            "condition_skip",
+					SkipReason:      condition.SkipReason(step.If),
+					Env:             stepEnv,
+					VaultConnection: stepVaultConnection,
+					VaultSecrets:    stepVaultSecrets,
+				})
+				continue
+			}
+			runWhen = when
+			if runWhen == protocol.JobStepRunWhenFailure {
+				// The agent skips the step when no earlier step failed.
+				skipReason = condition.SkipReason(step.If)
+			}
+		}
 		if step.Test != nil {
 			command := renderTemplate(step.Test.Command, renderVars)
 			if strings.TrimSpace(command) == "" {
@@ -198,7 +261,11 @@ func (s *stateStore) buildPendingPipelineJobMatrixEntry(
 				TestReport:      strings.TrimSpace(step.Test.Report),
 				CoverageFormat:  strings.TrimSpace(step.Test.CoverageFormat),
 				CoverageReport:  strings.TrimSpace(step.Test.CoverageReport),
+				CoverageGate:    coverageGateFromConfig(step.Test.CoverageGate),
+				RunWhen:         runWhen,
+				SkipReason:      skipReason,
 			})
+			applyStepRetryPolicy(&stepPlan[len(stepPlan)-1], step.Retry)
 			continue
 		}
 		line := renderTemplate(step.Run, renderVars)
@@ -213,15 +280,22 @@ func (s *stateStore) buildPendingPipelineJobMatrixEntry(
 			Env:             stepEnv,
 			VaultConnection: stepVaultConnection,
 			VaultSecrets:    stepVaultSecrets,
+			RunWhen:         runWhen,
+			SkipReason:      skipReason,
 		})
+		applyStepRetryPolicy(&stepPlan[len(stepPlan)-1], step.Retry)
 	}
 	if len(stepPlan) == 0 {
 		return nil, fmt.Errorf("pipeline job %q has no executable steps after rendering", pipelineJobID)
 	}
 	if len(rendered) == 0 {
-		// In dry-run mode a job may contain only skip_dry_run steps. Persist a harmless
+		// A dry run or false step conditions may skip every step. Persist a harmless
 		// placeholder script so queue validation does not reject an empty script.
-		rendered = append(rendered, "echo [dry-run] all steps skipped")
+		if dryRun {
+			rendered = append(rendered, "echo [dry-run] all steps skipped")
+		} else {
+			rendered = append(rendered, "echo all steps skipped")
+		}
 	}
 	for stepIndex := range stepPlan {
 		stepPlan[stepIndex].Index = stepIndex + 1
@@ -241,9 +315,28 @@ func (s *stateStore) buildPendingPipelineJobMatrixEntry(
 	if len(originalMatrixEntries) > 0 {
 		metadata.Set(domain.ExecutionMetadataMatrixIndex, strconv.Itoa(matrixIndex))
 	}
-	if selection != nil && selection.DryRun {
+	if dryRun {
 		metadata.SetFlag(domain.ExecutionMetadataDryRun, true)
 	}
+	if jobSkipReason != "" {
+		metadata.Set(domain.ExecutionMetadataConditionSkipped, jobSkipReason)
+	}
+	if retry != nil && retry.MaxAttempts > 1 {
+		metadata.Set(domain.ExecutionMetadataRetryMaxAttempts, strconv.Itoa(retry.MaxAttempts))
+		metadata.Set(domain.ExecutionMetadataRetryBackoffSeconds, strconv.Itoa(retry.BackoffSeconds))
+		metadata.Set(domain.ExecutionMetadataRetryOn, strings.Join(retry.EffectiveOn(), ","))
+	}
+	if shard.total > 0 {
+		metadata.Set(domain.ExecutionMetadataShardIndex, strconv.Itoa(shard.index))
+		metadata.Set(domain.ExecutionMetadataShardTotal, strconv.Itoa(shard.total))
+		if len(shard.tests) > 0 {
+			testsJSON, err := json.Marshal(shard.tests)
+			if err != nil {
+				return nil, fmt.Errorf("pipeline job %q: encode shard tests: %w", pipelineJobID, err)
+			}
+			metadata.Set(domain.ExecutionMetadataShardTestsJSON, string(testsJSON))
+		}
+	}
 	if name := matrixVars["name"]; name != "" {
 		metadata.Set(domain.ExecutionMetadataMatrixName, name)
 		metadata.Set(domain.ExecutionMetadataBuildTarget, name)
@@ -293,9 +386,13 @@ func (s *stateStore) buildPendingPipelineJobMatrixEntry(
 	if len(missingNeeds) > 0 {
 		metadata.Set(domain.ExecutionMetadataMissingNeedsJobIDs, strings.Join(missingNeeds, ","))
 	}
-	if selection != nil && selection.DryRun {
+	if dryRun {
 		env["CIWI_DRY_RUN"] = "1"
 	}
+	if shard.total > 0 {
+		env["CIWI_SHARD_INDEX"] = strconv.Itoa(shard.index)
+		env["CIWI_SHARD_TOTAL"] = strconv.Itoa(shard.total)
+	}
 	if runCtx.VersionRaw != "" {
 		env["CIWI_PIPELINE_VERSION_RAW"] = runCtx.VersionRaw
 	}
@@ -317,7 +414,7 @@ func (s *stateStore) buildPendingPipelineJobMatrixEntry(
 	}
 	env["CIWI_PIPELINE_SOURCE_REPO"] = p.SourceRepo
 	var dependencyArtifactJobIDs []string
-	if !opts.dependencyBlocked && !opts.blocked {
+	if !opts.dependencyBlocked && !opts.blocked && jobSkipReason == "" {
 		depJobIDs, resolveErr := resolveDependencyArtifactJobIDs(artifactSources, depCtx)
 		if resolveErr != nil {
 			return nil, fmt.Errorf("pipeline job %q: %w", pipelineJobID, resolveErr)
@@ -342,7 +439,9 @@ func (s *stateStore) buildPendingPipelineJobMatrixEntry(
 
 	requiredCaps := cloneMap(runsOn)
 	for k := range requiredCaps {
-		if strings.HasPrefix(k, "container_") {
+		// container_runtime is the only container setting an agent must
+		// advertise; the rest only shape the container it starts.
+		if strings.HasPrefix(k, "container_") && k != "container_runtime" {
 			delete(requiredCaps, k)
 		}
 	}
@@ -392,9 +491,48 @@ func (s *stateStore) buildPendingPipelineJobMatrixEntry(
 		artifactGlobs:            append([]string(nil), artifacts...),
 		dependencyArtifactJobIDs: append([]string(nil), dependencyArtifactJobIDs...),
 		caches:                   cloneJobCachesFromPersisted(caches),
+		services:                 renderJobServices(services, renderVars),
 		sourceRepo:               p.SourceRepo,
 		sourceRef:                sourceRef,
+		sourceCheckout:           protocol.SourceCheckoutOptions(p.SourceCheckout).Clone(),
 		metadata:                 metadata,
 		stepPlan:                 stepPlan,
 	}, nil
 }
+
+// pipelineJobShard is one of the executions a sharded matrix entry runs as.
+// A job without shards runs as a single shard with a zero total.
+type pipelineJobShard struct {
+	index int
+	total int
+	tests []domain.ShardTest
+}
+
+const shardHistoryExecutionLimit = 20
+
+// pipelineJobShards splits a matrix entry into its shards and hands each the
+// tests that balance the durations recorded in the job's test history.
+func (s *stateStore) pipelineJobShards(p store.PersistedPipeline, pj store.PersistedPipelineJob, matrixVars map[string]string) []pipelineJobShard {
+	if pj.Shards <= 0 {
+		return []pipelineJobShard{{}}
+	}
+	var runs []domain.TestCaseRun
+	if s.db != nil {
+		scope := domain.TestHistoryScope{
+			ProjectID: p.ProjectID, PipelineID: p.PipelineID, PipelineJobID: pj.ID, MatrixName: matrixVars["name"],
+		}
+		var err error
+		if runs, err = s.db.ListTestCaseRuns(scope, shardHistoryExecutionLimit*pj.Shards); err != nil {
+			// Without history every shard still runs; the tests are just not
+			// assigned up front.
+			slog.Warn("list test history for shards", "pipeline_id", p.PipelineID, "pipeline_job_id", pj.ID, "error", err)
+			runs = nil
+		}
+	}
+	balanced := domain.BalanceTestShards(runs, pj.Shards)
+	shards := make([]pipelineJobShard, 0, pj.Shards)
+	for index := range pj.Shards {
+		shards = append(shards, pipelineJobShard{index: index, total: pj.Shards, tests: balanced[index]})
+	}
+	return shards
+}
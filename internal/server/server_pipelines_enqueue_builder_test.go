@@ -4,6 +4,7 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"testing"
 
@@ -199,6 +200,117 @@ pipelines:
 	}
 }
 
+func TestEnqueuePersistedPipelineEvaluatesConditions(t *testing.T) {
+	s, p := loadPipelineForEnqueueBuilderTest(t, []byte(`
+version: 1
+project:
+  name: ciwi
+pipelines:
+  - id: release
+    jobs:
+      - id: build
+        if: matrix.os == 'linux'
+        runs_on:
+          os: linux
+        timeout_seconds: 30
+        matrix:
+          include:
+            - os: linux
+            - os: windows
+        steps:
+          - run: echo build
+          - run: echo publish
+            if: ciwi.pipeline == 'nightly'
+          - run: echo dumps
+            if: failure()
+          - run: echo cleanup
+            if: always()
+      - id: docs
+        if: ciwi.dry_run
+        runs_on:
+          os: linux
+        timeout_seconds: 30
+        steps:
+          - run: echo docs
+      - id: deploy
+        needs: [docs]
+        runs_on:
+          os: linux
+        timeout_seconds: 30
+        steps:
+          - run: echo deploy
+      - id: verify
+        needs: [build]
+        runs_on:
+          os: linux
+        timeout_seconds: 30
+        steps:
+          - run: echo verify
+`), "conditions")
+
+	resp, err := s.enqueuePersistedPipeline(p, nil)
+	if err != nil {
+		t.Fatalf("enqueue pipeline: %v", err)
+	}
+	jobs := map[string]protocol.JobExecution{}
+	for _, id := range resp.JobExecutionIDs {
+		job, err := s.db.GetJobExecution(id)
+		if err != nil {
+			t.Fatalf("get enqueued job: %v", err)
+		}
+		key := job.Metadata.Value(domain.ExecutionMetadataPipelineJobID)
+		if name := job.Metadata.Value(domain.ExecutionMetadataMatrixVariablePrefix + "os"); name != "" {
+			key += "/" + name
+		}
+		jobs[key] = job
+	}
+
+	linux := jobs["build/linux"]
+	if linux.Status != protocol.JobExecutionStatusQueued || len(linux.StepPlan) != 4 {
+		t.Fatalf("linux build = status %q steps %+v", linux.Status, linux.StepPlan)
+	}
+	if step := linux.StepPlan[1]; step.Kind != "condition_skip" || step.SkipReason != "condition not met: ciwi.pipeline == 'nightly'" || strings.Contains(linux.Script, "echo publish") {
+		t.Fatalf("expected publish step to be skipped, got %+v script=%q", step, linux.Script)
+	}
+	if step := linux.StepPlan[2]; step.RunWhen != protocol.JobStepRunWhenFailure || step.SkipReason != "condition not met: failure()" {
+		t.Fatalf("expected failure-only step, got %+v", step)
+	}
+	if step := linux.StepPlan[3]; step.RunWhen != protocol.JobStepRunWhenAlways || step.SkipReason != "" {
+		t.Fatalf("expected always step, got %+v", step)
+	}
+	if step := linux.StepPlan[0]; step.RunWhen != "" || step.Kind != "" {
+		t.Fatalf("expected unconditional build step, got %+v", step)
+	}
+
+	for key, reason := range map[string]string{
+		"build/windows": "condition not met: matrix.os == 'linux'",
+		"docs":          "condition not met: ciwi.dry_run",
+		"deploy":        "required job docs was skipped",
+	} {
+		job := jobs[key]
+		if job.Status != protocol.JobExecutionStatusSkipped || job.Error != reason || !job.StartedUTC.IsZero() {
+			t.Fatalf("%s = status %q error %q", key, job.Status, job.Error)
+		}
+	}
+	verify := jobs["verify"]
+	if verify.Status != protocol.JobExecutionStatusQueued || !verify.Metadata.Flag(domain.ExecutionMetadataNeedsBlocked) {
+		t.Fatalf("verify should wait for the linux build, got status %q metadata %v", verify.Status, verify.Metadata)
+	}
+	if _, err := s.db.UpdateJobExecutionStatus(linux.ID, protocol.JobExecutionStatusUpdateRequest{AgentID: "agent-1", Status: protocol.JobExecutionStatusSucceeded}); err != nil {
+		t.Fatalf("finish linux build: %v", err)
+	}
+	if err := s.reconcileBlockedJobExecutions(); err != nil {
+		t.Fatalf("reconcile: %v", err)
+	}
+	verify, err = s.db.GetJobExecution(verify.ID)
+	if err != nil {
+		t.Fatalf("get verify job: %v", err)
+	}
+	if verify.Status != protocol.JobExecutionStatusQueued || verify.Metadata.Flag(domain.ExecutionMetadataNeedsBlocked) {
+		t.Fatalf("skipped matrix entries must not block verify, got status %q metadata %v", verify.Status, verify.Metadata)
+	}
+}
+
 func TestEnqueuePersistedPipelineTestStepSeparatesDisplayAndReportNames(t *testing.T) {
 	s, p := loadPipelineForEnqueueBuilderTest(t, []byte(`
 version: 1
@@ -940,3 +1052,300 @@ func loadPipelineForEnqueueBuilderTest(t *testing.T, yaml []byte, source string)
 	}
 	return s, p
 }
+
+func TestEnqueuePersistedPipelineCarriesRetryPolicies(t *testing.T) {
+	s, p := loadPipelineForEnqueueBuilderTest(t, []byte(`
+version: 1
+project:
+  name: ciwi
+pipelines:
+  - id: release
+    jobs:
+      - id: sign
+        runs_on:
+          os: windows
+        timeout_seconds: 30
+        retry:
+          max_attempts: 3
+          backoff_seconds: 20
+          on: [timeout, agent_lost]
+        steps:
+          - run: echo build
+          - run: echo sign
+            retry:
+              max_attempts: 2
+              backoff_seconds: 5
+`), "retry")
+
+	resp, err := s.enqueuePersistedPipeline(p, nil)
+	if err != nil {
+		t.Fatalf("enqueue pipeline: %v", err)
+	}
+	job, err := s.db.GetJobExecution(resp.JobExecutionIDs[0])
+	if err != nil {
+		t.Fatalf("get job: %v", err)
+	}
+	for key, want := range map[string]string{
+		domain.ExecutionMetadataRetryMaxAttempts:    "3",
+		domain.ExecutionMetadataRetryBackoffSeconds: "20",
+		domain.ExecutionMetadataRetryOn:             "timeout,agent_lost",
+	} {
+		if got := job.Metadata.Value(key); got != want {
+			t.Fatalf("metadata %s = %q, want %q", key, got, want)
+		}
+	}
+	if build := job.StepPlan[0]; build.RetryMaxAttempts != 0 {
+		t.Fatalf("expected no retry on build step, got %+v", build)
+	}
+	if sign := job.StepPlan[1]; sign.RetryMaxAttempts != 2 || sign.RetryBackoffSeconds != 5 {
+		t.Fatalf("expected sign step retry policy, got %+v", sign)
+	}
+}
+
+func TestPendingJobsSplitShardsWithBalancedTests(t *testing.T) {
+	s, pipeline := loadPipelineForEnqueueBuilderTest(t, []byte(`
+version: 1
+project:
+  name: ciwi
+pipelines:
+  - id: build
+    jobs:
+      - id: unit
+        runs_on: {os: linux}
+        shards: 2
+        timeout_seconds: 30
+        steps:
+          - test:
+              command: go test -json ./... > test.json
+              report: test.json
+`), "shards")
+
+	previous, err := s.db.CreateJobExecution(protocol.CreateJobExecutionRequest{
+		Script: "go test ./...", TimeoutSeconds: 30,
+		Metadata: map[string]string{
+			domain.ExecutionMetadataProjectID:     strconv.FormatInt(pipeline.ProjectID, 10),
+			domain.ExecutionMetadataPipelineID:    "build",
+			domain.ExecutionMetadataPipelineJobID: "unit",
+		},
+	})
+	if err != nil {
+		t.Fatalf("create previous job: %v", err)
+	}
+	if err := s.db.SaveJobExecutionTestReport(previous.ID, protocol.JobExecutionTestReport{Suites: []protocol.TestSuiteReport{{
+		Format: "go-test-json",
+		Cases: []protocol.TestCase{
+			{Package: "pkg", Name: "TestSlow", Status: "pass", DurationSeconds: 9},
+			{Package: "pkg", Name: "TestMedium", Status: "pass", DurationSeconds: 5},
+			{Package: "pkg", Name: "TestFast", Status: "pass", DurationSeconds: 4},
+		},
+	}}}); err != nil {
+		t.Fatalf("save previous report: %v", err)
+	}
+
+	_, pending, err := s.preparePendingPipelineJobs(pipeline, nil, enqueuePipelineOptions{
+		forcedRun: &pipelineRunContext{},
+		forcedDep: &pipelineDependencyContext{},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pending) != 2 {
+		t.Fatalf("pending jobs = %d, want one per shard", len(pending))
+	}
+	wantTests := [][]string{{"TestSlow"}, {"TestFast", "TestMedium"}}
+	for index, job := range pending {
+		shardIndex, total, ok := job.metadata.Shard()
+		if !ok || shardIndex != index || total != 2 {
+			t.Fatalf("pending[%d] shard = %d/%d ok=%v", index, shardIndex, total, ok)
+		}
+		if job.env["CIWI_SHARD_INDEX"] != strconv.Itoa(index) || job.env["CIWI_SHARD_TOTAL"] != "2" {
+			t.Fatalf("pending[%d] env = %v", index, job.env)
+		}
+		tests, err := job.metadata.ShardTests()
+		if err != nil {
+			t.Fatal(err)
+		}
+		names := make([]string, 0, len(tests))
+		for _, test := range tests {
+			names = append(names, test.Name)
+		}
+		if strings.Join(names, ",") != strings.Join(wantTests[index], ",") {
+			t.Fatalf("pending[%d] tests = %v, want %v", index, names, wantTests[index])
+		}
+	}
+}
+
+func TestPendingJobsCarryPriorities(t *testing.T) {
+	s, pipeline := loadPipelineForEnqueueBuilderTest(t, []byte(`
+version: 1
+project:
+  name: ciwi
+pipelines:
+  - id: release
+    priority: 20
+    jobs:
+      - id: package
+        runs_on: {os: linux}
+        timeout_seconds: 30
+        steps:
+          - run: ./package
+      - id: publish
+        runs_on: {os: linux}
+        priority: 80
+        timeout_seconds: 30
+        steps:
+          - run: ./publish
+`), "priorities")
+
+	prepare := func(selection *protocol.RunPipelineSelectionRequest) ([]pendingJob, error) {
+		_, pending, err := s.preparePendingPipelineJobs(pipeline, selection, enqueuePipelineOptions{
+			forcedRun: &pipelineRunContext{},
+			forcedDep: &pipelineDependencyContext{},
+		})
+		return pending, err
+	}
+	pending, err := prepare(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pending) != 2 || pending[0].metadata.Priority() != 20 || pending[1].metadata.Priority() != 80 {
+		t.Fatalf("expected configured priorities 20 and 80, got %+v", pending)
+	}
+
+	override := -50
+	pending, err = prepare(&protocol.RunPipelineSelectionRequest{Priority: &override})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, job := range pending {
+		if job.metadata.Priority() != -50 {
+			t.Fatalf("expected the run override to win, got %+v", job.metadata)
+		}
+	}
+
+	override = 500
+	if _, err := prepare(&protocol.RunPipelineSelectionRequest{Priority: &override}); err == nil || !strings.Contains(err.Error(), "priority must be between -100 and 100") {
+		t.Fatalf("expected out-of-range override rejection, got %v", err)
+	}
+}
+
+func TestPendingJobsCarrySourceCheckoutOptions(t *testing.T) {
+	repoURL, _, _ := createTestRemoteGitRepo(t)
+	s, pipeline := loadPipelineForEnqueueBuilderTest(t, []byte(`
+version: 1
+project:
+  name: ciwi
+pipelines:
+  - id: build
+    vcs_source:
+      repo: `+repoURL+`
+      depth: 50
+      lfs: true
+    jobs:
+      - id: compile
+        runs_on: {os: linux}
+        timeout_seconds: 30
+        steps:
+          - run: go build ./...
+`), "checkout-options")
+
+	_, pending, err := s.preparePendingPipelineJobs(pipeline, nil, enqueuePipelineOptions{
+		forcedRun: &pipelineRunContext{},
+		forcedDep: &pipelineDependencyContext{},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ids, err := s.persistPendingJobs(pending)
+	if err != nil {
+		t.Fatalf("persist pending jobs: %v", err)
+	}
+	job, err := s.db.GetJobExecution(ids[0])
+	if err != nil {
+		t.Fatalf("get job: %v", err)
+	}
+	if job.Source == nil || job.Source.Summary() != "depth=50 submodules=none lfs=on sparse=off fetch_tags=off" {
+		t.Fatalf("expected the job source to carry the checkout options, got %+v", job.Source)
+	}
+}
+
+func TestPendingJobsRequireOnlyTheContainerRuntime(t *testing.T) {
+	s, pipeline := loadPipelineForEnqueueBuilderTest(t, []byte(`
+version: 1
+project:
+  name: ciwi
+pipelines:
+  - id: build
+    jobs:
+      - id: compile
+        runs_on:
+          os: linux
+          container_image: ubuntu:24.04
+          container_runtime: podman
+          container_devices: /dev/kvm
+        timeout_seconds: 30
+        steps:
+          - run: make
+`), "container-runtime")
+
+	_, pending, err := s.preparePendingPipelineJobs(pipeline, nil, enqueuePipelineOptions{
+		forcedRun: &pipelineRunContext{},
+		forcedDep: &pipelineDependencyContext{},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	caps := pending[0].requiredCaps
+	if caps["container_runtime"] != "podman" || caps["container_image"] != "" || caps["container_devices"] != "" {
+		t.Fatalf("expected only container_runtime among the container settings to be required, got %v", caps)
+	}
+	if got := pending[0].metadata[domain.ExecutionMetadataRuntimeContainerDevices]; got != "/dev/kvm" {
+		t.Fatalf("expected container devices in the execution metadata, got %q", got)
+	}
+}
+
+func TestPendingJobsRenderServicesPerMatrixEntry(t *testing.T) {
+	s, pipeline := loadPipelineForEnqueueBuilderTest(t, []byte(`
+version: 1
+project:
+  name: ciwi
+pipelines:
+  - id: build
+    jobs:
+      - id: integration
+        runs_on:
+          os: linux
+        matrix:
+          include:
+            - pg: "15"
+            - pg: "16"
+        timeout_seconds: 30
+        services:
+          - name: postgres
+            image: postgres:{{pg}}
+            env:
+              POSTGRES_PASSWORD: ci-{{pg}}
+            ports: [5432]
+            health_check: pg_isready
+        steps:
+          - run: make integration
+`), "services")
+
+	_, pending, err := s.preparePendingPipelineJobs(pipeline, nil, enqueuePipelineOptions{
+		forcedRun: &pipelineRunContext{},
+		forcedDep: &pipelineDependencyContext{},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pending) != 2 {
+		t.Fatalf("expected one pending job per matrix entry, got %d", len(pending))
+	}
+	for i, pg := range []string{"15", "16"} {
+		services := pending[i].services
+		if len(services) != 1 || services[0].Name != "postgres" || services[0].Image != "postgres:"+pg ||
+			services[0].Env["POSTGRES_PASSWORD"] != "ci-"+pg || services[0].HealthCheck != "pg_isready" || services[0].Ports[0] != 5432 {
+			t.Fatalf("unexpected services for pg %s: %+v", pg, services)
+		}
+	}
+}
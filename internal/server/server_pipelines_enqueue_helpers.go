@@ -7,9 +7,11 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/izzyreal/ciwi/internal/condition"
 	"github.com/izzyreal/ciwi/internal/config"
 	"github.com/izzyreal/ciwi/internal/domain"
 	"github.com/izzyreal/ciwi/internal/protocol"
+	"github.com/izzyreal/ciwi/internal/store"
 )
 
 func normalizePipelineJobNeeds(in []string) []string {
@@ -123,20 +125,25 @@ func cloneJobStepPlan(in []protocol.JobStepPlanItem) []protocol.JobStepPlanItem
 			secrets = append([]protocol.ProjectSecretSpec(nil), step.VaultSecrets...)
 		}
 		out = append(out, protocol.JobStepPlanItem{
-			Index:           step.Index,
-			Total:           step.Total,
-			Name:            step.Name,
-			YAMLLiteral:     step.YAMLLiteral,
-			Script:          step.Script,
-			Kind:            step.Kind,
-			Env:             cloneMap(step.Env),
-			VaultConnection: step.VaultConnection,
-			VaultSecrets:    secrets,
-			TestName:        step.TestName,
-			TestFormat:      step.TestFormat,
-			TestReport:      step.TestReport,
-			CoverageFormat:  step.CoverageFormat,
-			CoverageReport:  step.CoverageReport,
+			Index:               step.Index,
+			Total:               step.Total,
+			Name:                step.Name,
+			YAMLLiteral:         step.YAMLLiteral,
+			Script:              step.Script,
+			Kind:                step.Kind,
+			Env:                 cloneMap(step.Env),
+			VaultConnection:     step.VaultConnection,
+			VaultSecrets:        secrets,
+			TestName:            step.TestName,
+			TestFormat:          step.TestFormat,
+			TestReport:          step.TestReport,
+			CoverageFormat:      step.CoverageFormat,
+			CoverageReport:      step.CoverageReport,
+			CoverageGate:        step.CoverageGate,
+			RunWhen:             step.RunWhen,
+			SkipReason:          step.SkipReason,
+			RetryMaxAttempts:    step.RetryMaxAttempts,
+			RetryBackoffSeconds: step.RetryBackoffSeconds,
 		})
 	}
 	return out
@@ -236,6 +243,26 @@ func pipelineStepYAMLLiteral(step config.PipelineJobStep) string {
 		if strings.TrimSpace(step.Test.CoverageReport) != "" {
 			lines = append(lines, "test.coverage_report: "+strings.TrimSpace(step.Test.CoverageReport))
 		}
+		if gate := step.Test.CoverageGate; gate != nil {
+			if gate.MinPercent != nil {
+				lines = append(lines, fmt.Sprintf("test.coverage_gate.min_percent: %g", *gate.MinPercent))
+			}
+			if gate.MaxDrop != nil {
+				lines = append(lines, fmt.Sprintf("test.coverage_gate.max_drop: %g", *gate.MaxDrop))
+			}
+		}
+		if step.Test.Shards > 0 {
+			lines = append(lines, fmt.Sprintf("test.shards: %d", step.Test.Shards))
+		}
+	}
+	if strings.TrimSpace(step.If) != "" {
+		lines = append(lines, "if: "+strings.TrimSpace(step.If))
+	}
+	if step.Retry != nil {
+		lines = append(lines, fmt.Sprintf("retry.max_attempts: %d", step.Retry.MaxAttempts))
+		if step.Retry.BackoffSeconds > 0 {
+			lines = append(lines, fmt.Sprintf("retry.backoff_seconds: %d", step.Retry.BackoffSeconds))
+		}
 	}
 	if step.SkipDryRun {
 		lines = append(lines, "skip_dry_run: true")
@@ -266,3 +293,89 @@ func cloneJobCachesFromPersisted(in []config.PipelineJobCacheSpec) []protocol.Jo
 	}
 	return out
 }
+
+// renderJobServices resolves matrix and version placeholders in the image
+// and environment of each service, like it does for step env.
+func renderJobServices(in []config.PipelineJobServiceSpec, renderVars map[string]string) []protocol.JobServiceSpec {
+	if len(in) == 0 {
+		return nil
+	}
+	out := make([]protocol.JobServiceSpec, 0, len(in))
+	for _, svc := range in {
+		var env map[string]string
+		if len(svc.Env) > 0 {
+			env = make(map[string]string, len(svc.Env))
+			for k, v := range svc.Env {
+				env[k] = renderTemplate(v, renderVars)
+			}
+		}
+		out = append(out, protocol.JobServiceSpec{
+			Name:                 strings.TrimSpace(svc.Name),
+			Image:                strings.TrimSpace(renderTemplate(svc.Image, renderVars)),
+			Env:                  env,
+			Ports:                append([]int(nil), svc.Ports...),
+			HealthCheck:          strings.TrimSpace(renderTemplate(svc.HealthCheck, renderVars)),
+			HealthTimeoutSeconds: svc.HealthTimeoutSeconds,
+		})
+	}
+	return out
+}
+
+// pipelineConditionVars lists the values `if:` expressions of one matrix entry
+// can reference.
+func pipelineConditionVars(p store.PersistedPipeline, matrixVars map[string]string, runCtx pipelineRunContext, dryRun bool) map[string]string {
+	vars := map[string]string{
+		"ciwi.dry_run":       strconv.FormatBool(dryRun),
+		"ciwi.pipeline":      p.PipelineID,
+		"ciwi.project":       p.ProjectName,
+		"ciwi.source_commit": runCtx.SourceRefResolved,
+		"ciwi.source_ref":    strings.TrimSpace(p.SourceRef),
+		"ciwi.source_repo":   p.SourceRepo,
+		"ciwi.tag_prefix":    runCtx.TagPrefix,
+		"ciwi.version":       runCtx.Version,
+		"ciwi.version_raw":   runCtx.VersionRaw,
+	}
+	if raw := strings.TrimSpace(runCtx.SourceRefRaw); raw != "" {
+		vars["ciwi.source_ref"] = raw
+	}
+	for key, value := range matrixVars {
+		vars["matrix."+key] = value
+	}
+	return vars
+}
+
+// planStepCondition resolves a step's `if:` into when the agent should run
+// it. Everything but the outcome of earlier steps is known when the job is
+// queued, so evaluating for both outcomes captures the whole condition.
+func planStepCondition(source string, vars map[string]string) (runWhen string, skip bool, err error) {
+	expr, err := condition.Parse(source)
+	if err != nil {
+		return "", false, err
+	}
+	onSuccess := expr.Evaluate(condition.Context{Vars: vars})
+	onFailure := expr.UsesStatus() && expr.Evaluate(condition.Context{Vars: vars, Failed: true})
+	switch {
+	case onSuccess && onFailure:
+		return protocol.JobStepRunWhenAlways, false, nil
+	case onFailure:
+		return protocol.JobStepRunWhenFailure, false, nil
+	case onSuccess:
+		return "", false, nil
+	}
+	return "", true, nil
+}
+
+func coverageGateFromConfig(gate *config.CoverageGate) *protocol.CoverageGate {
+	if gate == nil {
+		return nil
+	}
+	return &protocol.CoverageGate{MinPercent: gate.MinPercent, MaxDrop: gate.MaxDrop}
+}
+
+func applyStepRetryPolicy(step *protocol.JobStepPlanItem, retry *config.RetryPolicy) {
+	if retry == nil || retry.MaxAttempts <= 1 {
+		return
+	}
+	step.RetryMaxAttempts = retry.MaxAttempts
+	step.RetryBackoffSeconds = retry.BackoffSeconds
+}
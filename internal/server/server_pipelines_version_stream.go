@@ -1,6 +1,7 @@
 package server
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -41,7 +42,7 @@ func (s *stateStore) streamVersionResolve(w http.ResponseWriter, p store.Persist
 		send("done", "error", depErr.Error(), nil)
 		return
 	}
-	runCtx, runErr := resolvePipelineRunContextWithReporter(p, depCtx, func(step, status, message string) {
+	runCtx, runErr := resolvePipelineRunContextWithReporter(s.withRepoCredential(context.Background(), p.SourceRepo), p, depCtx, func(step, status, message string) {
 		send(step, status, message, nil)
 	})
 	if runErr != nil {
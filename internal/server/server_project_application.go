@@ -42,6 +42,16 @@ func (a projectMutatorAdapter) ExecuteProjectAction(ctx context.Context, request
 	}
 }
 
+func (a projectMutatorAdapter) SetProjectWebhookSecret(ctx context.Context, projectID int64, secret string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if err := a.state.projectWebhookStore().SetProjectWebhookSecret(projectID, secret); err != nil {
+		return projectActionError("set project webhook secret", err)
+	}
+	return nil
+}
+
 func (a projectMutatorAdapter) ImportProject(ctx context.Context, request application.ImportProjectRequest) (application.ImportProjectResult, error) {
 	if _, err := exec.LookPath("git"); err != nil {
 		return application.ImportProjectResult{}, application.WrapInternal("find git", err)
@@ -53,7 +63,7 @@ func (a projectMutatorAdapter) ImportProject(ctx context.Context, request applic
 	defer os.RemoveAll(tmpDir)
 	importCtx, cancel := context.WithTimeout(ctx, 2*time.Minute)
 	defer cancel()
-	fetch, err := fetchProjectConfigAndIcon(importCtx, tmpDir, request.RepoURL, request.RepoRef, request.ConfigFile)
+	fetch, err := fetchProjectConfigAndIcon(a.state.withRepoCredential(importCtx, request.RepoURL), tmpDir, request.RepoURL, request.RepoRef, request.ConfigFile)
 	if err != nil {
 		return application.ImportProjectResult{}, application.NewError(application.ErrorInvalidArgument, err.Error(), err)
 	}
@@ -37,7 +37,7 @@ func (s *stateStore) warmProjectIconsOnStartup(ctx context.Context) {
 			fetchCtx, cancel := context.WithTimeout(ctx, 2*time.Minute)
 			defer cancel()
 
-			fetchRes, err := fetchProjectConfigAndIcon(fetchCtx, tmpDir, p.RepoURL, p.RepoRef, configFile)
+			fetchRes, err := fetchProjectConfigAndIcon(s.withRepoCredential(fetchCtx, p.RepoURL), tmpDir, p.RepoURL, p.RepoRef, configFile)
 			if err != nil {
 				slog.Warn("startup icon warmup fetch failed", "project", p.Name, "error", err)
 				return
@@ -10,11 +10,46 @@ import (
 )
 
 type projectDetailsViewResponse struct {
-	Project           frontPageProjectResponse         `json:"project"`
-	Pipelines         []projectPipelineDetailsResponse `json:"pipelines"`
-	StructureFilters  []projectStructureFilterResponse `json:"structure_filters"`
-	HistoryExecutions []executionCardResponse          `json:"history_executions"`
-	HistoryEmpty      bool                             `json:"history_empty"`
+	Project               frontPageProjectResponse         `json:"project"`
+	Pipelines             []projectPipelineDetailsResponse `json:"pipelines"`
+	StructureFilters      []projectStructureFilterResponse `json:"structure_filters"`
+	HistoryExecutions     []executionCardResponse          `json:"history_executions"`
+	HistoryEmpty          bool                             `json:"history_empty"`
+	TestQuarantine        []testQuarantineEntryResponse    `json:"test_quarantine"`
+	TestQuarantineEmpty   bool                             `json:"test_quarantine_empty"`
+	TestQuarantineNotices []string                         `json:"test_quarantine_notices"`
+	CoverageTrends        []coverageTrendResponse          `json:"coverage_trends"`
+	CoverageTrendsEmpty   bool                             `json:"coverage_trends_empty"`
+}
+
+type coverageTrendResponse struct {
+	Key     string                       `json:"key"`
+	Label   string                       `json:"label"`
+	Summary string                       `json:"summary"`
+	Tone    string                       `json:"tone"`
+	Points  []coverageTrendPointResponse `json:"points"`
+}
+
+type coverageTrendPointResponse struct {
+	JobExecutionID string          `json:"job_execution_id"`
+	PercentLabel   string          `json:"percent_label"`
+	DeltaLabel     string          `json:"delta_label"`
+	DateLabel      string          `json:"date_label"`
+	Tone           string          `json:"tone"`
+	Progress       domain.Progress `json:"progress"`
+}
+
+type testQuarantineEntryResponse struct {
+	ID           int64  `json:"id"`
+	ProjectID    int64  `json:"project_id"`
+	Package      string `json:"package"`
+	Name         string `json:"name"`
+	TestLabel    string `json:"test_label"`
+	Owner        string `json:"owner"`
+	Reason       string `json:"reason"`
+	ExpiresLabel string `json:"expires_label"`
+	Expired      bool   `json:"expired"`
+	Tone         string `json:"tone"`
 }
 
 type projectStructureFilterResponse struct {
@@ -46,6 +81,8 @@ type projectPipelineDetailsResponse struct {
 	Jobs              []projectJobDetailsResponse `json:"jobs"`
 	SummaryLabel      string                      `json:"summary_label"`
 	GraphSummaryLabel string                      `json:"graph_summary_label"`
+	TriggerLabel      string                      `json:"trigger_label"`
+	TriggerError      string                      `json:"trigger_error"`
 }
 
 type projectJobDetailsResponse struct {
@@ -128,6 +165,8 @@ func projectDetailsToResponse(view presentation.ProjectDetailsView) projectDetai
 			JobsCount: pipeline.JobsCount, SupportsDryRun: pipeline.SupportsDryRun, Jobs: jobs,
 			SummaryLabel:      pipeline.SummaryLabel,
 			GraphSummaryLabel: pipeline.GraphSummary,
+			TriggerLabel:      pipeline.TriggerLabel,
+			TriggerError:      pipeline.TriggerError,
 		})
 	}
 	structureFilters := make([]projectStructureFilterResponse, 0, len(view.StructureFilters))
@@ -141,6 +180,30 @@ func projectDetailsToResponse(view presentation.ProjectDetailsView) projectDetai
 			ShowChainStructure: filter.ShowChainStructure, ShowPipelineStructure: filter.ShowPipelineStructure,
 		})
 	}
+	quarantine := make([]testQuarantineEntryResponse, 0, len(view.TestQuarantine))
+	for _, entry := range view.TestQuarantine {
+		quarantine = append(quarantine, testQuarantineEntryResponse{
+			ID: entry.ID, ProjectID: entry.ProjectID, Package: entry.Package, Name: entry.Name, TestLabel: entry.TestLabel,
+			Owner: entry.Owner, Reason: entry.Reason, ExpiresLabel: entry.ExpiresLabel, Expired: entry.Expired, Tone: entry.Tone,
+		})
+	}
+	coverageTrends := make([]coverageTrendResponse, 0, len(view.CoverageTrends))
+	for _, trend := range view.CoverageTrends {
+		points := make([]coverageTrendPointResponse, 0, len(trend.Points))
+		for _, point := range trend.Points {
+			points = append(points, coverageTrendPointResponse{
+				JobExecutionID: point.JobExecutionID, PercentLabel: point.PercentLabel, DeltaLabel: point.DeltaLabel,
+				DateLabel: point.DateLabel, Tone: point.Tone, Progress: point.Progress,
+			})
+		}
+		coverageTrends = append(coverageTrends, coverageTrendResponse{
+			Key: trend.Key, Label: trend.Label, Summary: trend.Summary, Tone: trend.Tone, Points: points,
+		})
+	}
 	history := executionCardsToResponse(view.HistoryExecutions, false)
-	return projectDetailsViewResponse{Project: project, Pipelines: pipelines, StructureFilters: structureFilters, HistoryExecutions: history, HistoryEmpty: len(history) == 0}
+	return projectDetailsViewResponse{
+		Project: project, Pipelines: pipelines, StructureFilters: structureFilters, HistoryExecutions: history, HistoryEmpty: len(history) == 0,
+		TestQuarantine: quarantine, TestQuarantineEmpty: len(quarantine) == 0, TestQuarantineNotices: append([]string{}, view.TestQuarantineNotices...),
+		CoverageTrends: coverageTrends, CoverageTrendsEmpty: len(coverageTrends) == 0,
+	}
 }
@@ -80,6 +80,10 @@ func (s *stateStore) projectByIDHandler(w http.ResponseWriter, r *http.Request)
 			return
 		}
 	}
+	if len(parts) == 3 && parts[1] == "test-quarantine" {
+		s.projectTestQuarantineEntryHandler(w, r, projectID, parts[2])
+		return
+	}
 	if len(parts) == 4 && parts[1] == "pipeline-chains" {
 		s.pipelineChainActionHandler(w, r, projectID, parts[2], parts[3])
 		return
@@ -90,6 +94,12 @@ func (s *stateStore) projectByIDHandler(w http.ResponseWriter, r *http.Request)
 		return
 	}
 	switch parts[1] {
+	case "webhook-secret":
+		s.projectWebhookSecretHandler(w, r, projectID)
+		return
+	case "test-quarantine":
+		s.projectTestQuarantineHandler(w, r, projectID)
+		return
 	case "icon":
 		if r.Method != http.MethodGet {
 			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
@@ -178,7 +188,7 @@ func (s *stateStore) reloadProjectFromRepo(ctx context.Context, project protocol
 	reloadCtx, cancel := context.WithTimeout(ctx, 2*time.Minute)
 	defer cancel()
 
-	fetchRes, err := fetchProjectConfigAndIcon(reloadCtx, tmpDir, project.RepoURL, project.RepoRef, configFile)
+	fetchRes, err := fetchProjectConfigAndIcon(s.withRepoCredential(reloadCtx, project.RepoURL), tmpDir, project.RepoURL, project.RepoRef, configFile)
 	if err != nil {
 		return err
 	}
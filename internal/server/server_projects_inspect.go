@@ -339,6 +339,8 @@ func persistedPipelineJobToConfigJob(j store.PersistedPipelineJob) config.Pipeli
 		TimeoutSeconds:  j.TimeoutSeconds,
 		Artifacts:       append([]string(nil), j.Artifacts...),
 		Caches:          append([]config.PipelineJobCacheSpec(nil), j.Caches...),
+		Services:        append([]config.PipelineJobServiceSpec(nil), j.Services...),
+		Shards:          j.Shards,
 		Steps:           clonePipelineJobSteps(j.Steps),
 	}
 	if len(j.MatrixInclude) > 0 {
@@ -347,6 +349,11 @@ func persistedPipelineJobToConfigJob(j store.PersistedPipelineJob) config.Pipeli
 			spec.Matrix.Include = append(spec.Matrix.Include, cloneMap(row))
 		}
 	}
+	if j.Priority != 0 {
+		// The stored priority already includes the pipeline's.
+		priority := j.Priority
+		spec.Priority = &priority
+	}
 	if len(j.RequiresTools) > 0 || len(j.RequiresContainerTools) > 0 {
 		spec.Requires = config.PipelineJobRequirements{
 			Tools: cloneMap(j.RequiresTools),
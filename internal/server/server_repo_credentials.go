@@ -0,0 +1,213 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/izzyreal/ciwi/internal/application"
+	"github.com/izzyreal/ciwi/internal/gitauth"
+	"github.com/izzyreal/ciwi/internal/protocol"
+	"github.com/izzyreal/ciwi/internal/server/webhook"
+)
+
+type repoCredentialsResponse struct {
+	Credentials []protocol.RepoCredential `json:"credentials"`
+}
+
+type repoCredentialResponse struct {
+	Credential protocol.RepoCredential `json:"credential"`
+}
+
+type repoCredentialDeleteResponse struct {
+	Deleted bool  `json:"deleted"`
+	ID      int64 `json:"id"`
+}
+
+type repoCredentialRepositoryAdapter struct {
+	state *stateStore
+}
+
+func (a repoCredentialRepositoryAdapter) ListRepoCredentials(context.Context) ([]application.RepoCredential, error) {
+	items, err := a.state.repoCredentialStore().ListRepoCredentials()
+	if err != nil {
+		return nil, application.WrapInternal("list repo credentials", err)
+	}
+	credentials := make([]application.RepoCredential, 0, len(items))
+	for _, item := range items {
+		credentials = append(credentials, repoCredentialFromProtocol(item))
+	}
+	return credentials, nil
+}
+
+func (a repoCredentialRepositoryAdapter) UpsertRepoCredential(_ context.Context, req application.UpsertRepoCredentialRequest) (application.RepoCredential, error) {
+	item, err := a.state.repoCredentialStore().UpsertRepoCredential(protocol.UpsertRepoCredentialRequest{
+		Name: req.Name, URLPrefix: req.URLPrefix, Kind: req.Kind, Username: req.Username, SecretEnv: req.SecretEnv,
+		VaultConnection: req.VaultConnection, VaultSecret: vaultSecretRefToProtocol(req.VaultSecret), KnownHosts: req.KnownHosts,
+	})
+	if err != nil {
+		return application.RepoCredential{}, application.NewError(application.ErrorInvalidArgument, err.Error(), err)
+	}
+	return repoCredentialFromProtocol(item), nil
+}
+
+func (a repoCredentialRepositoryAdapter) DeleteRepoCredential(_ context.Context, id int64) error {
+	if err := a.state.repoCredentialStore().DeleteRepoCredential(id); err != nil {
+		return application.NewError(application.ErrorNotFound, err.Error(), err)
+	}
+	return nil
+}
+
+func (s *stateStore) repoCredentialsHandler(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		credentials, err := s.app().repoCredentials.List(r.Context())
+		if err != nil {
+			http.Error(w, err.Error(), applicationErrorHTTPStatus(err))
+			return
+		}
+		items := make([]protocol.RepoCredential, 0, len(credentials))
+		for _, credential := range credentials {
+			items = append(items, repoCredentialToProtocol(credential))
+		}
+		writeJSON(w, http.StatusOK, repoCredentialsResponse{Credentials: items})
+	case http.MethodPost:
+		var req protocol.UpsertRepoCredentialRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "invalid JSON body", http.StatusBadRequest)
+			return
+		}
+		credential, err := s.app().repoCredentials.Upsert(r.Context(), application.UpsertRepoCredentialRequest{
+			Name: req.Name, URLPrefix: req.URLPrefix, Kind: req.Kind, Username: req.Username, SecretEnv: req.SecretEnv,
+			VaultConnection: req.VaultConnection, VaultSecret: vaultSecretRefFromProtocol(req.VaultSecret), KnownHosts: req.KnownHosts,
+		})
+		if err != nil {
+			http.Error(w, err.Error(), applicationErrorHTTPStatus(err))
+			return
+		}
+		writeJSON(w, http.StatusCreated, repoCredentialResponse{Credential: repoCredentialToProtocol(credential)})
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (s *stateStore) repoCredentialByIDHandler(w http.ResponseWriter, r *http.Request) {
+	rel := strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/v1/repo-credentials/"), "/")
+	id, err := strconv.ParseInt(rel, 10, 64)
+	if err != nil || id <= 0 {
+		http.Error(w, "invalid repo credential id", http.StatusBadRequest)
+		return
+	}
+	if r.Method != http.MethodDelete {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if err := s.app().repoCredentials.Delete(r.Context(), id); err != nil {
+		http.Error(w, err.Error(), applicationErrorHTTPStatus(err))
+		return
+	}
+	writeJSON(w, http.StatusOK, repoCredentialDeleteResponse{Deleted: true, ID: id})
+}
+
+func repoCredentialFromProtocol(item protocol.RepoCredential) application.RepoCredential {
+	return application.RepoCredential{
+		ID: item.ID, Name: item.Name, URLPrefix: item.URLPrefix, Kind: item.Kind, Username: item.Username, SecretEnv: item.SecretEnv,
+		VaultConnection: item.VaultConnection, VaultSecret: vaultSecretRefFromProtocol(item.VaultSecret), KnownHosts: item.KnownHosts,
+	}
+}
+
+func repoCredentialToProtocol(credential application.RepoCredential) protocol.RepoCredential {
+	return protocol.RepoCredential{
+		ID: credential.ID, Name: credential.Name, URLPrefix: credential.URLPrefix, Kind: credential.Kind, Username: credential.Username,
+		SecretEnv: credential.SecretEnv, VaultConnection: credential.VaultConnection,
+		VaultSecret: vaultSecretRefToProtocol(credential.VaultSecret), KnownHosts: credential.KnownHosts,
+	}
+}
+
+func vaultSecretRefFromProtocol(spec *protocol.ProjectSecretSpec) *application.VaultSecretRef {
+	if spec == nil {
+		return nil
+	}
+	return &application.VaultSecretRef{Name: spec.Name, Mount: spec.Mount, Path: spec.Path, Key: spec.Key, KVVersion: spec.KVVersion}
+}
+
+func vaultSecretRefToProtocol(ref *application.VaultSecretRef) *protocol.ProjectSecretSpec {
+	if ref == nil {
+		return nil
+	}
+	return &protocol.ProjectSecretSpec{Name: ref.Name, Mount: ref.Mount, Path: ref.Path, Key: ref.Key, KVVersion: ref.KVVersion}
+}
+
+// matchRepoCredential picks the credential with the longest url_prefix that
+// covers repoURL. Both sides are compared in their normalized host/path form,
+// and the prefix must end at a path boundary, so org does not cover org-other
+// and a host does not cover a longer host name that starts with it.
+func matchRepoCredential(creds []protocol.RepoCredential, repoURL string) (protocol.RepoCredential, bool) {
+	repo := webhook.NormalizeRepoURL(repoURL)
+	var (
+		best    protocol.RepoCredential
+		bestLen int
+		found   bool
+	)
+	for _, cred := range creds {
+		prefix := strings.TrimSuffix(webhook.NormalizeRepoURL(cred.URLPrefix), "/")
+		if prefix == "" || !strings.HasPrefix(repo, prefix) || (len(repo) > len(prefix) && repo[len(prefix)] != '/') {
+			continue
+		}
+		if !found || len(prefix) > bestLen {
+			best, bestLen, found = cred, len(prefix), true
+		}
+	}
+	return best, found
+}
+
+// resolveRepoCredential returns the secret-bearing credential for repoURL, or
+// nil when no configured credential covers it.
+func (s *stateStore) resolveRepoCredential(ctx context.Context, repoURL string) (*protocol.SourceCredential, error) {
+	if strings.TrimSpace(repoURL) == "" {
+		return nil, nil
+	}
+	creds, err := s.repoCredentialStore().ListRepoCredentials()
+	if err != nil {
+		return nil, err
+	}
+	cred, ok := matchRepoCredential(creds, repoURL)
+	if !ok {
+		return nil, nil
+	}
+	secret := ""
+	if env := strings.TrimSpace(cred.SecretEnv); env != "" {
+		if !application.ValidRepoCredentialSecretEnv(env) {
+			return nil, fmt.Errorf("repo credential %q: secret_env %s must start with %s", cred.Name, env, application.RepoCredentialSecretEnvPrefix)
+		}
+		secret = os.Getenv(env)
+		if strings.TrimSpace(secret) == "" {
+			return nil, fmt.Errorf("repo credential %q: environment variable %s is empty", cred.Name, env)
+		}
+	} else {
+		conn, err := s.vaultStore().GetVaultConnectionByName(cred.VaultConnection)
+		if err != nil {
+			return nil, fmt.Errorf("repo credential %q: %w", cred.Name, err)
+		}
+		if cred.VaultSecret == nil {
+			return nil, fmt.Errorf("repo credential %q has no vault_secret", cred.Name)
+		}
+		secret, err = s.readVaultSecret(ctx, conn, *cred.VaultSecret)
+		if err != nil {
+			return nil, fmt.Errorf("repo credential %q: %w", cred.Name, wrapJobSecretResolutionError(conn.Name, err))
+		}
+	}
+	return &protocol.SourceCredential{Kind: cred.Kind, Username: cred.Username, Secret: secret, KnownHosts: cred.KnownHosts}, nil
+}
+
+// withRepoCredential binds the git commands run under ctx to the credential
+// configured for repoURL. The credential is only looked up once git runs.
+func (s *stateStore) withRepoCredential(ctx context.Context, repoURL string) context.Context {
+	return gitauth.WithProvider(ctx, repoURL, func() (*protocol.SourceCredential, error) {
+		return s.resolveRepoCredential(ctx, repoURL)
+	})
+}
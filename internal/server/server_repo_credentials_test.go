@@ -0,0 +1,284 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/cgi"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/izzyreal/ciwi/internal/application"
+	"github.com/izzyreal/ciwi/internal/protocol"
+)
+
+func TestRepoCredentialsAPI(t *testing.T) {
+	ts, s := newTestHTTPServerWithState(t)
+	defer ts.Close()
+	client := ts.Client()
+
+	for _, body := range []map[string]any{
+		{},
+		{"name": "org", "url_prefix": "https://git.example.com/org/", "kind": "password", "secret_env": "TOKEN"},
+		{"name": "org", "url_prefix": "https://git.example.com/org/", "kind": "https_token"},
+		{"name": "org", "url_prefix": "https://git.example.com/org/", "kind": "https_token", "secret_env": "TOKEN", "vault_connection": "vault"},
+		{"name": "org", "url_prefix": "https://git.example.com/org/", "kind": "https_token", "secret_env": "TOKEN", "known_hosts": "git.example.com ssh-ed25519 AAAA"},
+		{"name": "org", "url_prefix": "https://git.example.com/org/", "kind": "https_token", "secret_env": "CIWI_VAULT_SECRET_ID"},
+	} {
+		resp := mustJSONRequest(t, client, http.MethodPost, ts.URL+"/api/v1/repo-credentials", body)
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Fatalf("expected %v to be rejected, got status=%d body=%s", body, resp.StatusCode, readBody(t, resp))
+		}
+	}
+
+	t.Setenv("CIWI_REPO_CRED_ORG", "org-token")
+	t.Setenv("CIWI_REPO_CRED_APP", "app-token")
+	var created struct {
+		Credential protocol.RepoCredential `json:"credential"`
+	}
+	for _, body := range []map[string]any{
+		{"name": "org", "url_prefix": "https://git.example.com/org/", "kind": "https_token", "secret_env": "CIWI_REPO_CRED_ORG"},
+		{"name": "app", "url_prefix": "https://git.example.com/org/app", "kind": "https_token", "username": "ci", "secret_env": "CIWI_REPO_CRED_APP"},
+	} {
+		resp := mustJSONRequest(t, client, http.MethodPost, ts.URL+"/api/v1/repo-credentials", body)
+		if resp.StatusCode != http.StatusCreated {
+			t.Fatalf("create repo credential status=%d body=%s", resp.StatusCode, readBody(t, resp))
+		}
+		decodeJSONBody(t, resp, &created)
+	}
+
+	listResp := mustJSONRequest(t, client, http.MethodGet, ts.URL+"/api/v1/repo-credentials", nil)
+	body := readBody(t, listResp)
+	if listResp.StatusCode != http.StatusOK || !strings.Contains(body, `"secret_env":"CIWI_REPO_CRED_APP"`) || strings.Contains(body, "app-token") {
+		t.Fatalf("expected the list to name the secret source without the secret, status=%d body=%s", listResp.StatusCode, body)
+	}
+
+	cred, err := s.resolveRepoCredential(context.Background(), "https://git.example.com/org/app.git")
+	if err != nil || cred == nil || cred.Secret != "app-token" || cred.Username != "ci" {
+		t.Fatalf("expected the longest prefix to win, got %+v err=%v", cred, err)
+	}
+	cred, err = s.resolveRepoCredential(context.Background(), "https://git.example.com/org/lib.git")
+	if err != nil || cred == nil || cred.Secret != "org-token" {
+		t.Fatalf("expected the org credential, got %+v err=%v", cred, err)
+	}
+	for _, repo := range []string{
+		"https://git.example.com/other/lib.git",
+		"https://git.example.com/org-other/lib.git",
+	} {
+		if cred, err := s.resolveRepoCredential(context.Background(), repo); err != nil || cred != nil {
+			t.Fatalf("expected no credential for %s, got %+v err=%v", repo, cred, err)
+		}
+	}
+
+	deleteResp := mustJSONRequest(t, client, http.MethodDelete, ts.URL+"/api/v1/repo-credentials/"+int64ToString(created.Credential.ID), nil)
+	if deleteResp.StatusCode != http.StatusOK {
+		t.Fatalf("delete repo credential status=%d body=%s", deleteResp.StatusCode, readBody(t, deleteResp))
+	}
+	cred, err = s.resolveRepoCredential(context.Background(), "https://git.example.com/org/app.git")
+	if err != nil || cred == nil || cred.Secret != "org-token" {
+		t.Fatalf("expected the org credential once the app credential is gone, got %+v err=%v", cred, err)
+	}
+
+	// Rows stored before secret_env needed its prefix are not resolved.
+	t.Setenv("CIWI_VAULT_SECRET_ID", "vault-secret")
+	if _, err := s.db.UpsertRepoCredential(protocol.UpsertRepoCredentialRequest{
+		Name: "legacy", URLPrefix: "https://git.example.com/legacy/", Kind: protocol.RepoCredentialHTTPSToken, SecretEnv: "CIWI_VAULT_SECRET_ID",
+	}); err != nil {
+		t.Fatalf("UpsertRepoCredential: %v", err)
+	}
+	if cred, err := s.resolveRepoCredential(context.Background(), "https://git.example.com/legacy/app.git"); err == nil || cred != nil {
+		t.Fatalf("expected an unprefixed secret_env to be refused, got %+v err=%v", cred, err)
+	}
+}
+
+func TestMatchRepoCredentialStopsAtPathAndHostBoundaries(t *testing.T) {
+	creds := []protocol.RepoCredential{
+		{Name: "org", URLPrefix: "https://git.example.com/org"},
+		{Name: "host", URLPrefix: "https://git.example.com"},
+		{Name: "deploy", URLPrefix: "git@github.com:example/"},
+	}
+	for repo, want := range map[string]string{
+		"https://git.example.com/org/app.git":          "org",
+		"https://git.example.com/org":                  "org",
+		"https://GIT.example.com/org/app/":             "org",
+		"git@git.example.com:org/app.git":              "org",
+		"https://git.example.com/org-other/app.git":    "host",
+		"https://git.example.com.evil.net/org/app.git": "",
+		"https://git.example.community/org/app.git":    "",
+		"ssh://git@github.com/example/app.git":         "deploy",
+		"git@github.com:example-fork/app.git":          "",
+	} {
+		cred, ok := matchRepoCredential(creds, repo)
+		if got := cred.Name; ok != (want != "") || got != want {
+			t.Fatalf("matchRepoCredential(%q) = %q, %t; want %q", repo, got, ok, want)
+		}
+	}
+}
+
+func TestRepoCredentialFromVault(t *testing.T) {
+	ts, s := newTestHTTPServerWithState(t)
+	defer ts.Close()
+
+	vaultAPI := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/v1/auth/approle/login"):
+			_, _ = w.Write([]byte(`{"auth":{"client_token":"token-123456","lease_duration":3600}}`))
+		case r.Method == http.MethodGet && strings.HasSuffix(r.URL.Path, "/v1/kv/data/ci/deploy"):
+			_, _ = w.Write([]byte(`{"data":{"data":{"private_key":"-----KEY-----"}}}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer vaultAPI.Close()
+	t.Setenv("CIWI_VAULT_SECRET_ID", "sid-123")
+	if _, err := s.db.UpsertVaultConnection(protocol.UpsertVaultConnectionRequest{
+		Name: "main-vault", URL: vaultAPI.URL, RoleID: "role-1", SecretIDEnv: "CIWI_VAULT_SECRET_ID",
+	}); err != nil {
+		t.Fatalf("UpsertVaultConnection: %v", err)
+	}
+	resp := mustJSONRequest(t, ts.Client(), http.MethodPost, ts.URL+"/api/v1/repo-credentials", map[string]any{
+		"name": "deploy", "url_prefix": "git@git.example.com:", "kind": "ssh_key",
+		"vault_connection": "main-vault", "vault_secret": map[string]any{"path": "ci/deploy", "key": "private_key"},
+	})
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("create repo credential status=%d body=%s", resp.StatusCode, readBody(t, resp))
+	}
+	cred, err := s.resolveRepoCredential(context.Background(), "git@git.example.com:org/app.git")
+	if err != nil || cred == nil || cred.Kind != protocol.RepoCredentialSSHKey || cred.Secret != "-----KEY-----" {
+		t.Fatalf("expected the deploy key from Vault, got %+v err=%v", cred, err)
+	}
+}
+
+func TestJobSourceCredentialOnlyGoesToTheLeasingAgent(t *testing.T) {
+	_, s := newTestHTTPServerWithState(t)
+	ts := httptest.NewServer(buildRouter(s, s.artifactsDir))
+	defer ts.Close()
+	client := ts.Client()
+
+	t.Setenv("CIWI_REPO_CRED_TEST", "s3cret")
+	if _, err := s.db.UpsertRepoCredential(protocol.UpsertRepoCredentialRequest{
+		Name: "org", URLPrefix: "https://git.example.com/org/", Kind: protocol.RepoCredentialHTTPSToken, SecretEnv: "CIWI_REPO_CRED_TEST",
+	}); err != nil {
+		t.Fatalf("UpsertRepoCredential: %v", err)
+	}
+	caps := map[string]string{"executor": "script", "shells": "posix"}
+	s.mu.Lock()
+	for _, id := range []string{"agent-a", "agent-b"} {
+		s.agents[id] = agentState{Hostname: id, OS: "linux", Arch: "amd64", Authorized: true, Capabilities: caps, LastSeenUTC: time.Now().UTC()}
+	}
+	s.mu.Unlock()
+	job, err := s.db.CreateJobExecution(protocol.CreateJobExecutionRequest{
+		Script: "make", RequiredCapabilities: caps, TimeoutSeconds: 60,
+		Source: &protocol.SourceSpec{Repo: "https://git.example.com/org/app.git", Ref: "main"},
+	})
+	if err != nil {
+		t.Fatalf("CreateJobExecution: %v", err)
+	}
+	credentials := map[string]string{}
+	for _, id := range []string{"agent-a", "agent-b"} {
+		token, err := s.app().agentCredentials.CreateEnrollmentToken(context.Background(), application.CreateEnrollmentTokenRequest{Description: id})
+		if err != nil {
+			t.Fatalf("CreateEnrollmentToken: %v", err)
+		}
+		enrolled, err := s.app().agentCredentials.Enroll(context.Background(), application.EnrollAgentRequest{AgentID: id, Token: token.Secret})
+		if err != nil {
+			t.Fatalf("Enroll %s: %v", id, err)
+		}
+		credentials[id] = enrolled.Credential
+	}
+	requestCredential := func(agentID, credential string) *http.Response {
+		body, _ := json.Marshal(map[string]any{"agent_id": agentID})
+		req, _ := http.NewRequest(http.MethodPost, ts.URL+"/api/v1/jobs/"+job.ID+"/source-credential", bytes.NewReader(body))
+		req.Header.Set(protocol.AgentIDHeader, agentID)
+		if credential != "" {
+			req.Header.Set("Authorization", "Bearer "+credential)
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatalf("source credential request: %v", err)
+		}
+		return resp
+	}
+
+	if resp := requestCredential("agent-a", credentials["agent-a"]); resp.StatusCode != http.StatusConflict {
+		t.Fatalf("expected a queued job to hand out no credential, got status=%d body=%s", resp.StatusCode, readBody(t, resp))
+	}
+	if leased, err := s.db.LeaseJobExecution("agent-a", caps); err != nil || leased == nil || leased.ID != job.ID {
+		t.Fatalf("LeaseJobExecution: %+v err=%v", leased, err)
+	}
+	if resp := requestCredential("agent-b", credentials["agent-b"]); resp.StatusCode != http.StatusConflict {
+		t.Fatalf("expected another agent to be refused, got status=%d body=%s", resp.StatusCode, readBody(t, resp))
+	}
+	if resp := requestCredential("agent-a", ""); resp.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("expected the leasing agent without its credential to be refused, got status=%d body=%s", resp.StatusCode, readBody(t, resp))
+	}
+	resp := requestCredential("agent-a", credentials["agent-a"])
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("source credential status=%d body=%s", resp.StatusCode, readBody(t, resp))
+	}
+	var payload protocol.JobSourceCredentialResponse
+	decodeJSONBody(t, resp, &payload)
+	if payload.Credential == nil || payload.Credential.Secret != "s3cret" || payload.Credential.Kind != protocol.RepoCredentialHTTPSToken {
+		t.Fatalf("unexpected source credential payload: %+v", payload)
+	}
+
+	if _, err := s.db.UpdateJobExecutionStatus(job.ID, protocol.JobExecutionStatusUpdateRequest{AgentID: "agent-a", Status: protocol.JobExecutionStatusSucceeded}); err != nil {
+		t.Fatalf("UpdateJobExecutionStatus: %v", err)
+	}
+	if resp := requestCredential("agent-a", credentials["agent-a"]); resp.StatusCode != http.StatusConflict {
+		t.Fatalf("expected a finished job to hand out no credential, got status=%d body=%s", resp.StatusCode, readBody(t, resp))
+	}
+}
+
+func TestRepoCredentialAuthenticatesServerGitCalls(t *testing.T) {
+	execPath, err := exec.Command("git", "--exec-path").Output()
+	if err != nil {
+		t.Skipf("git not available: %v", err)
+	}
+	backend := filepath.Join(strings.TrimSpace(string(execPath)), "git-http-backend")
+	if _, err := os.Stat(backend); err != nil {
+		t.Skipf("git http-backend not available: %v", err)
+	}
+	repoPath, _, _ := createTestRemoteGitRepo(t)
+	gitServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if user, pass, ok := r.BasicAuth(); !ok || user != "ci" || pass != "s3cret" {
+			w.Header().Set("WWW-Authenticate", `Basic realm="git"`)
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		handler := &cgi.Handler{
+			Path: backend,
+			Root: "/git",
+			Env:  []string{"GIT_PROJECT_ROOT=" + filepath.Dir(repoPath), "GIT_HTTP_EXPORT_ALL=1"},
+		}
+		handler.ServeHTTP(w, r)
+	}))
+	defer gitServer.Close()
+	repoURL := gitServer.URL + "/git/" + filepath.Base(repoPath)
+
+	ts, s := newTestHTTPServerWithState(t)
+	defer ts.Close()
+	if _, err := buildSourceRefsViewContext(s.withRepoCredential(context.Background(), repoURL), repoURL, "main"); err == nil {
+		t.Fatalf("expected the private repository to refuse anonymous access")
+	}
+	t.Setenv("CIWI_REPO_CRED_TEST", "s3cret")
+	if _, err := s.db.UpsertRepoCredential(protocol.UpsertRepoCredentialRequest{
+		Name: "local", URLPrefix: gitServer.URL + "/", Kind: protocol.RepoCredentialHTTPSToken, Username: "ci", SecretEnv: "CIWI_REPO_CRED_TEST",
+	}); err != nil {
+		t.Fatalf("UpsertRepoCredential: %v", err)
+	}
+	refs, err := buildSourceRefsViewContext(s.withRepoCredential(context.Background(), repoURL), repoURL, "main")
+	if err != nil || refs.DefaultRef != "refs/heads/main" || len(refs.Refs) != 2 {
+		t.Fatalf("expected the credential to list the branches, got %+v err=%v", refs, err)
+	}
+	sha, err := resolveSourceRefFromRepo(s.withRepoCredential(context.Background(), repoURL), repoURL, "refs/heads/feature/one-off")
+	if err != nil || len(sha) != 40 {
+		t.Fatalf("expected the credential to resolve a ref by cloning, got %q err=%v", sha, err)
+	}
+}
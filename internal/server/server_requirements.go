@@ -22,22 +22,22 @@ func (s *stateStore) schedulingAgentSnapshots(now time.Time) []requirements.Agen
 }
 
 func (s *stateStore) schedulingAgentSnapshotsContext(ctx context.Context, now time.Time) ([]requirements.AgentSnapshot, error) {
-	busy, err := s.agentJobExecutionStore().ListActiveJobExecutionAgentIDsContext(ctx)
+	activeJobs, err := s.agentJobExecutionStore().CountActiveJobExecutionsByAgentContext(ctx)
 	if err != nil {
 		if ctx.Err() != nil {
 			return nil, ctx.Err()
 		}
-		busy = map[string]bool{}
+		activeJobs = map[string]int{}
 	}
 	s.mu.Lock()
 	defer s.mu.Unlock()
 	snapshots := make([]requirements.AgentSnapshot, 0, len(s.agents))
 	for id, agent := range s.agents {
 		snapshots = append(snapshots, requirements.AgentSnapshot{
-			ID: id, OS: agent.OS, Arch: agent.Arch, Capabilities: cloneMap(agent.Capabilities),
+			ID: id, OS: agent.OS, Arch: agent.Arch, Capabilities: cloneMap(agent.Capabilities), Labels: agent.effectiveLabels(),
 			Freshness: classifyAgentFreshness(agent.LastSeenUTC, now), Authorized: agent.Authorized,
 			Deactivated: agent.Deactivated, Updating: agent.UpdateInProgress || s.agentLeasePendingUpdateReasonLocked(id, agent) != "",
-			Busy: busy[id],
+			Slots: agent.jobSlots(), ActiveJobs: activeJobs[id],
 		})
 	}
 	return snapshots, nil
@@ -11,6 +11,7 @@ import (
 func buildRouter(s *stateStore, artifactsDir string) http.Handler {
 	r := chi.NewRouter()
 	r.Use(middleware.Recoverer)
+	r.Use(s.authMiddleware)
 
 	// UI/static
 	r.HandleFunc("/", webui.Handler)
@@ -26,6 +27,7 @@ func buildRouter(s *stateStore, artifactsDir string) http.Handler {
 	r.HandleFunc("/agents/*", webui.Handler)
 	r.HandleFunc("/projects/*", webui.Handler)
 	r.HandleFunc("/jobs/*", webui.Handler)
+	r.HandleFunc("/login", webui.Handler)
 
 	// Health/info
 	r.Get("/healthz", healthzHandler)
@@ -40,11 +42,23 @@ func buildRouter(s *stateStore, artifactsDir string) http.Handler {
 	r.Get("/api/v1/views/agents", s.agentsViewHandler)
 	r.Get("/api/v1/views/agents/*", s.agentDetailsViewHandler)
 
+	// Accounts
+	r.Post("/api/v1/auth/login", s.loginHandler)
+	r.Post("/api/v1/auth/logout", s.logoutHandler)
+	r.Get("/api/v1/auth/session", s.authSessionHandler)
+	r.HandleFunc("/api/v1/auth/users", s.usersHandler)
+	r.HandleFunc("/api/v1/auth/users/*", s.userByIDHandler)
+	r.HandleFunc("/api/v1/auth/tokens", s.apiTokensHandler)
+	r.HandleFunc("/api/v1/auth/tokens/*", s.apiTokenByIDHandler)
+
 	// Agent API
 	r.Post("/api/v1/heartbeat", s.heartbeatHandler)
 	r.Get("/api/v1/agents", s.listAgentsHandler)
 	r.HandleFunc("/api/v1/agents/*", s.agentByIDHandler)
 	r.Post("/api/v1/agent/lease", s.leaseJobHandler)
+	r.Post("/api/v1/agent/enroll", s.agentEnrollHandler)
+	r.HandleFunc("/api/v1/agent-enrollment-tokens", s.agentEnrollmentTokensHandler)
+	r.HandleFunc("/api/v1/agent-enrollment-tokens/*", s.agentEnrollmentTokenByIDHandler)
 
 	// Project/pipeline APIs
 	r.Post("/api/v1/projects/import", s.importProjectHandler)
@@ -53,10 +67,13 @@ func buildRouter(s *stateStore, artifactsDir string) http.Handler {
 	r.HandleFunc("/api/v1/projects", s.listProjectsHandler)
 	r.HandleFunc("/api/v1/projects/*", s.projectByIDHandler)
 	r.HandleFunc("/api/v1/pipelines/*", s.pipelineByIDHandler)
+	r.Post("/api/v1/hooks/{projectId}", s.projectWebhookHandler)
 
 	// Vault APIs
 	r.HandleFunc("/api/v1/vault/connections", s.vaultConnectionsHandler)
 	r.HandleFunc("/api/v1/vault/connections/*", s.vaultConnectionByIDHandler)
+	r.HandleFunc("/api/v1/repo-credentials", s.repoCredentialsHandler)
+	r.HandleFunc("/api/v1/repo-credentials/*", s.repoCredentialByIDHandler)
 
 	// Job APIs
 	r.Get("/api/v1/job-queue/layout", s.jobQueueLayoutHandler)
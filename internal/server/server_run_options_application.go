@@ -48,7 +48,7 @@ func (a runOptionsAdapter) pipelineOptions(ctx context.Context, pipeline store.P
 		}
 	}
 	if request.IncludeSourceRefs {
-		refs, err := buildSourceRefsViewContext(ctx, result.SourceRepo, strings.TrimSpace(pipeline.SourceRef))
+		refs, err := buildSourceRefsViewContext(a.state.withRepoCredential(ctx, result.SourceRepo), result.SourceRepo, strings.TrimSpace(pipeline.SourceRef))
 		if err != nil {
 			return application.RunOptions{}, application.NewError(application.ErrorInvalidArgument, err.Error(), err)
 		}
@@ -100,7 +100,7 @@ func (a runOptionsAdapter) chainOptions(ctx context.Context, chain store.Persist
 		}
 	}
 	if request.IncludeSourceRefs {
-		refs, refsErr := buildSourceRefsViewContext(ctx, result.SourceRepo, strings.TrimSpace(first.SourceRef))
+		refs, refsErr := buildSourceRefsViewContext(a.state.withRepoCredential(ctx, result.SourceRepo), result.SourceRepo, strings.TrimSpace(first.SourceRef))
 		if refsErr != nil {
 			return application.RunOptions{}, application.NewError(application.ErrorInvalidArgument, refsErr.Error(), refsErr)
 		}
@@ -17,6 +17,14 @@ func (s *stateStore) runOptionsViewHandler(w http.ResponseWriter, r *http.Reques
 		IncludeSourceRefs: true, IncludeEligibleAgents: true,
 		AllowMissingSourceRepo: true,
 	}
+	if raw := strings.TrimSpace(r.URL.Query().Get("priority")); raw != "" {
+		priority, err := strconv.Atoi(raw)
+		if err != nil {
+			http.Error(w, "invalid priority", http.StatusBadRequest)
+			return
+		}
+		request.Priority = &priority
+	}
 	var err error
 	switch {
 	case len(parts) == 2 && parts[0] == "pipelines":
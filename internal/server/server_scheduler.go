@@ -0,0 +1,136 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/izzyreal/ciwi/internal/application"
+	"github.com/izzyreal/ciwi/internal/config"
+	"github.com/izzyreal/ciwi/internal/schedule"
+	"github.com/izzyreal/ciwi/internal/store"
+)
+
+const (
+	schedulerTickInterval = 15 * time.Second
+	// scheduleFireGrace is how late a fire may be noticed and still count as
+	// on time rather than missed, e.g. right after a restart.
+	scheduleFireGrace       = 2 * time.Minute
+	scheduleMaxMissedScan   = 100000
+	pipelineTriggerSchedule = "schedule"
+)
+
+// scheduler starts runs of scheduled pipelines and pipeline chains. Fires
+// that were missed while the server was down are dropped or collapsed into a
+// single run according to the schedule's catch_up policy.
+type scheduler struct {
+	store   scheduleTriggerStore
+	enqueue func(ctx context.Context, trigger store.PersistedScheduleTrigger, fire time.Time) error
+}
+
+func (s *stateStore) newScheduler() *scheduler {
+	return &scheduler{
+		store: s.scheduleTriggerStore(),
+		enqueue: func(ctx context.Context, trigger store.PersistedScheduleTrigger, fire time.Time) error {
+			// The key makes a fire enqueue at most once, even if the state
+			// update after a successful enqueue is lost.
+			key := fmt.Sprintf("schedule:%s:%d:%s:%d", trigger.TargetKind, trigger.ProjectID, trigger.TargetID, fire.Unix())
+			ctx = application.WithSystemCaller(ctx, "scheduler")
+			if trigger.TargetKind == store.ScheduleTargetChain {
+				_, err := s.app().pipelineChains.RunPipelineChain(ctx, application.RunPipelineChainRequest{
+					ProjectID: trigger.ProjectID, ChainID: trigger.TargetID,
+					DryRun: trigger.Schedule.DryRun, SourceRef: trigger.Schedule.SourceRef,
+					Trigger: pipelineTriggerSchedule, IdempotencyKey: key,
+				})
+				return err
+			}
+			_, err := s.app().pipelines.RunPipeline(ctx, application.RunPipelineRequest{
+				PipelineDBID: trigger.PipelineDBID,
+				DryRun:       trigger.Schedule.DryRun, SourceRef: trigger.Schedule.SourceRef,
+				Trigger: pipelineTriggerSchedule, IdempotencyKey: key,
+			})
+			return err
+		},
+	}
+}
+
+// runDue enqueues every scheduled target with a fire in (last check, now].
+// A target seen for the first time only records a baseline, and a fire that
+// fails to enqueue is retried on the next pass.
+func (sc *scheduler) runDue(ctx context.Context, now time.Time) error {
+	triggers, err := sc.store.ListScheduleTriggers()
+	if err != nil {
+		return err
+	}
+	for _, trigger := range triggers {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		rec := store.ScheduleCheckRecord{CheckedUTC: now}
+		if !trigger.LastCheckedUTC.IsZero() {
+			fire, missed, err := latestScheduleFire(trigger.Schedule, trigger.LastCheckedUTC, now)
+			switch {
+			case err != nil:
+				slog.Error("invalid schedule", "project", trigger.ProjectName, "target", trigger.Name, "error", err)
+			case fire.IsZero():
+			case now.Sub(fire) > scheduleFireGrace && trigger.Schedule.EffectiveCatchUp() == config.ScheduleCatchUpSkip:
+				slog.Info("skipping missed scheduled fires", "project", trigger.ProjectName, "target", trigger.Name, "missed", missed, "latest", fire)
+			default:
+				rec.FiredUTC = fire
+				if err := sc.enqueue(ctx, trigger, fire); err != nil {
+					// Keep the previous check time so the next pass retries
+					// the fire.
+					rec.CheckedUTC = trigger.LastCheckedUTC
+					rec.Error = fmt.Sprintf("enqueue run for %s: %v", fire.Format(time.RFC3339), err)
+					slog.Error("scheduled enqueue failed", "project", trigger.ProjectName, "target", trigger.Name, "fire", fire, "error", err)
+				} else {
+					slog.Info("schedule enqueued run", "project", trigger.ProjectName, "target", trigger.Name, "fire", fire, "fires", missed)
+				}
+			}
+		}
+		if err := sc.store.RecordScheduleCheck(trigger.ProjectID, trigger.TargetKind, trigger.TargetID, rec); err != nil {
+			return fmt.Errorf("record schedule check for %s/%s: %w", trigger.ProjectName, trigger.Name, err)
+		}
+	}
+	return nil
+}
+
+// latestScheduleFire returns the last fire of sched in (after, now] and how
+// many fires fell into that window.
+func latestScheduleFire(sched config.Schedule, after, now time.Time) (time.Time, int, error) {
+	cron, err := schedule.Parse(sched.Cron)
+	if err != nil {
+		return time.Time{}, 0, err
+	}
+	loc, err := schedule.Location(sched.Timezone)
+	if err != nil {
+		return time.Time{}, 0, err
+	}
+	var latest time.Time
+	count := 0
+	for next := cron.Next(after.In(loc)); !next.IsZero() && !next.After(now) && count < scheduleMaxMissedScan; next = cron.Next(next) {
+		latest = next
+		count++
+	}
+	if latest.IsZero() {
+		return latest, 0, nil
+	}
+	return latest.UTC(), count, nil
+}
+
+func (s *stateStore) runSchedulerLoop(ctx context.Context) {
+	sched := s.newScheduler()
+	ticker := time.NewTicker(schedulerTickInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := sched.runDue(ctx, time.Now().UTC()); err != nil && ctx.Err() == nil {
+				slog.Error("scheduler pass failed", "error", err)
+			}
+		}
+	}
+}
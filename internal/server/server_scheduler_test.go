@@ -0,0 +1,155 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/izzyreal/ciwi/internal/config"
+	"github.com/izzyreal/ciwi/internal/store"
+)
+
+type fakeScheduleTriggerStore struct {
+	triggers []store.PersistedScheduleTrigger
+	records  map[string]store.ScheduleCheckRecord
+}
+
+func (f *fakeScheduleTriggerStore) ListScheduleTriggers() ([]store.PersistedScheduleTrigger, error) {
+	return append([]store.PersistedScheduleTrigger(nil), f.triggers...), nil
+}
+
+func (f *fakeScheduleTriggerStore) RecordScheduleCheck(projectID int64, targetKind, targetID string, rec store.ScheduleCheckRecord) error {
+	if f.records == nil {
+		f.records = map[string]store.ScheduleCheckRecord{}
+	}
+	f.records[targetKind+":"+targetID] = rec
+	for i := range f.triggers {
+		t := &f.triggers[i]
+		if t.ProjectID != projectID || t.TargetKind != targetKind || t.TargetID != targetID {
+			continue
+		}
+		t.LastCheckedUTC = rec.CheckedUTC
+		if !rec.FiredUTC.IsZero() {
+			t.LastFireUTC = rec.FiredUTC
+			t.LastError = rec.Error
+		}
+	}
+	return nil
+}
+
+func TestSchedulerRecordsBaselineThenFiresOnTime(t *testing.T) {
+	fakeStore := &fakeScheduleTriggerStore{triggers: []store.PersistedScheduleTrigger{
+		{ProjectID: 1, ProjectName: "ciwi", TargetKind: store.ScheduleTargetPipeline, TargetID: "build", Name: "build", PipelineDBID: 3, Schedule: config.Schedule{Cron: "0 * * * *"}},
+		{ProjectID: 1, ProjectName: "ciwi", TargetKind: store.ScheduleTargetChain, TargetID: "chain-abc", Name: "release", Schedule: config.Schedule{Cron: "30 * * * *", DryRun: true}},
+	}}
+	var enqueued []string
+	sched := &scheduler{
+		store: fakeStore,
+		enqueue: func(_ context.Context, trigger store.PersistedScheduleTrigger, fire time.Time) error {
+			enqueued = append(enqueued, trigger.Name+"@"+fire.Format("15:04"))
+			return nil
+		},
+	}
+	now := time.Date(2026, 5, 1, 9, 59, 50, 0, time.UTC)
+	if err := sched.runDue(context.Background(), now); err != nil {
+		t.Fatalf("first pass: %v", err)
+	}
+	if len(enqueued) != 0 {
+		t.Fatalf("first pass must only record a baseline, got %v", enqueued)
+	}
+	if err := sched.runDue(context.Background(), now.Add(15*time.Second)); err != nil {
+		t.Fatalf("second pass: %v", err)
+	}
+	if err := sched.runDue(context.Background(), now.Add(30*time.Second)); err != nil {
+		t.Fatalf("third pass: %v", err)
+	}
+	if strings.Join(enqueued, ",") != "build@10:00" {
+		t.Fatalf("expected a single on-time fire, got %v", enqueued)
+	}
+	if got := fakeStore.triggers[0]; !got.LastFireUTC.Equal(time.Date(2026, 5, 1, 10, 0, 0, 0, time.UTC)) || got.LastError != "" {
+		t.Fatalf("unexpected fire state: %+v", got)
+	}
+	if err := sched.runDue(context.Background(), time.Date(2026, 5, 1, 10, 30, 5, 0, time.UTC)); err != nil {
+		t.Fatalf("chain pass: %v", err)
+	}
+	if strings.Join(enqueued, ",") != "build@10:00,release@10:30" {
+		t.Fatalf("expected chain fire, got %v", enqueued)
+	}
+}
+
+func TestSchedulerAppliesCatchUpPolicyAfterDowntime(t *testing.T) {
+	lastChecked := time.Date(2026, 5, 1, 6, 0, 0, 0, time.UTC)
+	fakeStore := &fakeScheduleTriggerStore{triggers: []store.PersistedScheduleTrigger{
+		{ProjectID: 1, TargetKind: store.ScheduleTargetPipeline, TargetID: "skip", Name: "skip", Schedule: config.Schedule{Cron: "0 * * * *"}, LastCheckedUTC: lastChecked},
+		{ProjectID: 1, TargetKind: store.ScheduleTargetPipeline, TargetID: "once", Name: "once", Schedule: config.Schedule{Cron: "0 * * * *", CatchUp: config.ScheduleCatchUpOnce}, LastCheckedUTC: lastChecked},
+	}}
+	var enqueued []string
+	enqueueErr := errors.New("dependency not satisfied")
+	sched := &scheduler{
+		store: fakeStore,
+		enqueue: func(_ context.Context, trigger store.PersistedScheduleTrigger, fire time.Time) error {
+			enqueued = append(enqueued, trigger.Name+"@"+fire.Format("15:04"))
+			return enqueueErr
+		},
+	}
+	if err := sched.runDue(context.Background(), time.Date(2026, 5, 1, 9, 20, 0, 0, time.UTC)); err != nil {
+		t.Fatalf("pass: %v", err)
+	}
+	if strings.Join(enqueued, ",") != "once@09:00" {
+		t.Fatalf("expected only the catch-up once target to run for its latest missed fire, got %v", enqueued)
+	}
+	if rec := fakeStore.records["pipeline:skip"]; !rec.FiredUTC.IsZero() || !rec.CheckedUTC.Equal(time.Date(2026, 5, 1, 9, 20, 0, 0, time.UTC)) {
+		t.Fatalf("skipped fires must only advance the check time, got %+v", rec)
+	}
+	if rec := fakeStore.records["pipeline:once"]; !strings.Contains(rec.Error, "dependency not satisfied") {
+		t.Fatalf("expected enqueue error to be recorded, got %+v", rec)
+	}
+
+	// Shortly after a restart an on-time fire still runs under the skip policy.
+	if err := sched.runDue(context.Background(), time.Date(2026, 5, 1, 10, 1, 0, 0, time.UTC)); err != nil {
+		t.Fatalf("pass: %v", err)
+	}
+	if strings.Join(enqueued, ",") != "once@09:00,skip@10:00,once@10:00" {
+		t.Fatalf("unexpected runs after restart: %v", enqueued)
+	}
+}
+
+func TestSchedulerRetriesFireAfterEnqueueFailure(t *testing.T) {
+	lastChecked := time.Date(2026, 5, 1, 9, 59, 50, 0, time.UTC)
+	fakeStore := &fakeScheduleTriggerStore{triggers: []store.PersistedScheduleTrigger{
+		{ProjectID: 1, TargetKind: store.ScheduleTargetPipeline, TargetID: "build", Name: "build", Schedule: config.Schedule{Cron: "0 * * * *"}, LastCheckedUTC: lastChecked},
+	}}
+	var enqueued []string
+	failures := 1
+	sched := &scheduler{
+		store: fakeStore,
+		enqueue: func(_ context.Context, trigger store.PersistedScheduleTrigger, fire time.Time) error {
+			if failures > 0 {
+				failures--
+				return errors.New("database is locked")
+			}
+			enqueued = append(enqueued, trigger.Name+"@"+fire.Format("15:04"))
+			return nil
+		},
+	}
+	if err := sched.runDue(context.Background(), time.Date(2026, 5, 1, 10, 0, 5, 0, time.UTC)); err != nil {
+		t.Fatalf("first pass: %v", err)
+	}
+	if rec := fakeStore.records["pipeline:build"]; !rec.CheckedUTC.Equal(lastChecked) || !strings.Contains(rec.Error, "database is locked") {
+		t.Fatalf("failed enqueue must keep the previous check time, got %+v", rec)
+	}
+	if len(enqueued) != 0 {
+		t.Fatalf("expected nothing enqueued yet, got %v", enqueued)
+	}
+	if err := sched.runDue(context.Background(), time.Date(2026, 5, 1, 10, 0, 20, 0, time.UTC)); err != nil {
+		t.Fatalf("second pass: %v", err)
+	}
+	if strings.Join(enqueued, ",") != "build@10:00" {
+		t.Fatalf("expected the failed fire to be enqueued on the next pass, got %v", enqueued)
+	}
+	if got := fakeStore.triggers[0]; !got.LastCheckedUTC.Equal(time.Date(2026, 5, 1, 10, 0, 20, 0, time.UTC)) || got.LastError != "" {
+		t.Fatalf("unexpected state after retry: %+v", got)
+	}
+}
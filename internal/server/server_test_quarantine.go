@@ -0,0 +1,76 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/izzyreal/ciwi/internal/application"
+	"github.com/izzyreal/ciwi/internal/domain"
+	"github.com/izzyreal/ciwi/internal/protocol"
+)
+
+func (s *stateStore) projectTestQuarantineHandler(w http.ResponseWriter, r *http.Request, projectID int64) {
+	quarantine := s.app().testQuarantine
+	switch r.Method {
+	case http.MethodGet:
+		entries, err := quarantine.List(r.Context(), projectID)
+		if err != nil {
+			http.Error(w, err.Error(), applicationErrorHTTPStatus(err))
+			return
+		}
+		now := time.Now().UTC()
+		response := protocol.TestQuarantineResponse{Entries: make([]protocol.TestQuarantineEntry, 0, len(entries))}
+		for _, entry := range entries {
+			response.Entries = append(response.Entries, testQuarantineEntryToProtocol(entry, now))
+		}
+		writeJSON(w, http.StatusOK, response)
+	case http.MethodPost:
+		var req protocol.CreateTestQuarantineRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "invalid JSON body", http.StatusBadRequest)
+			return
+		}
+		entry, err := quarantine.Add(r.Context(), application.AddTestQuarantineRequest{
+			ProjectID: projectID, Package: req.Package, Name: req.Name, Owner: req.Owner, Reason: req.Reason,
+			Expires: req.ExpiresUTC, IdempotencyKey: strings.TrimSpace(r.Header.Get("Idempotency-Key")),
+		})
+		if err != nil {
+			http.Error(w, err.Error(), applicationErrorHTTPStatus(err))
+			return
+		}
+		writeJSON(w, http.StatusCreated, testQuarantineEntryToProtocol(entry, time.Now().UTC()))
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (s *stateStore) projectTestQuarantineEntryHandler(w http.ResponseWriter, r *http.Request, projectID int64, rawID string) {
+	entryID, err := strconv.ParseInt(rawID, 10, 64)
+	if err != nil || entryID <= 0 {
+		http.Error(w, "invalid quarantine entry id", http.StatusBadRequest)
+		return
+	}
+	if r.Method != http.MethodDelete {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if _, err := s.app().testQuarantine.Remove(r.Context(), application.RemoveTestQuarantineRequest{
+		ProjectID: projectID, ID: entryID, IdempotencyKey: strings.TrimSpace(r.Header.Get("Idempotency-Key")),
+	}); err != nil {
+		http.Error(w, err.Error(), applicationErrorHTTPStatus(err))
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func testQuarantineEntryToProtocol(entry domain.TestQuarantineEntry, now time.Time) protocol.TestQuarantineEntry {
+	return protocol.TestQuarantineEntry{
+		ID: entry.ID, ProjectID: entry.ProjectID, Package: entry.Package, Name: entry.Name,
+		Owner: entry.Owner, Reason: entry.Reason, CreatedBy: entry.CreatedBy,
+		CreatedUTC: formatOptionalUTC(entry.CreatedUTC), ExpiresUTC: formatOptionalUTC(entry.ExpiresUTC),
+		Expired: entry.Expired(now),
+	}
+}
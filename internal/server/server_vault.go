@@ -3,7 +3,6 @@ package server
 import (
 	"context"
 	"encoding/json"
-	"fmt"
 	"net/http"
 	"strconv"
 	"strings"
@@ -12,53 +11,85 @@ import (
 	"github.com/izzyreal/ciwi/internal/protocol"
 )
 
-func (s *stateStore) ListVaultConnections(_ context.Context) ([]protocol.VaultConnection, error) {
-	return s.vaultStore().ListVaultConnections()
+type vaultBackendAdapter struct {
+	state *stateStore
 }
 
-func (s *stateStore) UpsertVaultConnection(_ context.Context, req protocol.UpsertVaultConnectionRequest) (protocol.VaultConnection, error) {
-	if strings.TrimSpace(req.Name) == "" || strings.TrimSpace(req.URL) == "" || strings.TrimSpace(req.RoleID) == "" || strings.TrimSpace(req.SecretIDEnv) == "" {
-		return protocol.VaultConnection{}, application.NewError(application.ErrorInvalidArgument, "name, url, role_id and secret_id_env are required", nil)
-	}
-	item, err := s.vaultStore().UpsertVaultConnection(req)
+func (a vaultBackendAdapter) ListVaultConnections(context.Context) ([]application.VaultConnection, error) {
+	items, err := a.state.vaultStore().ListVaultConnections()
 	if err != nil {
-		return protocol.VaultConnection{}, application.NewError(application.ErrorInvalidArgument, err.Error(), err)
+		return nil, application.WrapInternal("list vault connections", err)
+	}
+	connections := make([]application.VaultConnection, 0, len(items))
+	for _, item := range items {
+		connections = append(connections, vaultConnectionFromProtocol(item))
 	}
-	s.app().changes.Publish(application.ChangeVault)
-	return item, nil
+	return connections, nil
 }
 
-func (s *stateStore) DeleteVaultConnection(_ context.Context, id int64) error {
-	if id <= 0 {
-		return application.NewError(application.ErrorInvalidArgument, "invalid vault connection id", nil)
+func (a vaultBackendAdapter) UpsertVaultConnection(_ context.Context, req application.UpsertVaultConnectionRequest) (application.VaultConnection, error) {
+	item, err := a.state.vaultStore().UpsertVaultConnection(protocol.UpsertVaultConnectionRequest{
+		Name: req.Name, URL: req.URL, AuthMethod: req.AuthMethod, AppRoleMount: req.AppRoleMount, RoleID: req.RoleID,
+		SecretIDEnv: req.SecretIDEnv, Namespace: req.Namespace, KVDefaultMount: req.KVDefaultMount, KVDefaultVer: req.KVDefaultVersion,
+	})
+	if err != nil {
+		return application.VaultConnection{}, application.NewError(application.ErrorInvalidArgument, err.Error(), err)
 	}
-	if err := s.vaultStore().DeleteVaultConnection(id); err != nil {
+	return vaultConnectionFromProtocol(item), nil
+}
+
+func (a vaultBackendAdapter) DeleteVaultConnection(_ context.Context, id int64) error {
+	if err := a.state.vaultStore().DeleteVaultConnection(id); err != nil {
 		return application.NewError(application.ErrorNotFound, err.Error(), err)
 	}
-	s.app().changes.Publish(application.ChangeVault)
 	return nil
 }
 
-func (s *stateStore) TestVaultConnection(ctx context.Context, id int64, secretIDOverride string) (protocol.TestVaultConnectionResponse, error) {
-	conn, err := s.vaultStore().GetVaultConnectionByID(id)
+func (a vaultBackendAdapter) TestVaultConnection(ctx context.Context, req application.TestVaultConnectionRequest) (application.TestVaultConnectionResult, error) {
+	conn, err := a.state.vaultStore().GetVaultConnectionByID(req.ID)
 	if err != nil {
-		return protocol.TestVaultConnectionResponse{}, application.NewError(application.ErrorNotFound, err.Error(), err)
+		return application.TestVaultConnectionResult{}, application.NewError(application.ErrorNotFound, err.Error(), err)
 	}
-	token, err := s.getVaultToken(ctx, conn, secretIDOverride)
+	token, err := a.state.getVaultToken(ctx, conn, req.SecretIDOverride)
 	if err != nil {
-		return protocol.TestVaultConnectionResponse{OK: false, Message: err.Error()}, nil
+		return application.TestVaultConnectionResult{OK: false, Message: err.Error()}, nil
+	}
+	if secret := req.TestSecret; secret != nil {
+		spec := protocol.ProjectSecretSpec{Name: secret.Name, Mount: secret.Mount, Path: secret.Path, Key: secret.Key, KVVersion: secret.KVVersion}
+		if _, err := a.state.readVaultSecret(ctx, conn, spec); err != nil {
+			return application.TestVaultConnectionResult{OK: false, Message: err.Error()}, nil
+		}
+	}
+	return application.TestVaultConnectionResult{OK: true, Message: "vault auth ok, token=" + token[:minInt(8, len(token))] + "..."}, nil
+}
+
+func vaultConnectionFromProtocol(item protocol.VaultConnection) application.VaultConnection {
+	return application.VaultConnection{
+		ID: item.ID, Name: item.Name, URL: item.URL, AuthMethod: item.AuthMethod, AppRoleMount: item.AppRoleMount, RoleID: item.RoleID,
+		SecretIDEnv: item.SecretIDEnv, Namespace: item.Namespace, KVDefaultMount: item.KVDefaultMount, KVDefaultVersion: item.KVDefaultVer,
+	}
+}
+
+func vaultConnectionToProtocol(connection application.VaultConnection) protocol.VaultConnection {
+	return protocol.VaultConnection{
+		ID: connection.ID, Name: connection.Name, URL: connection.URL, AuthMethod: connection.AuthMethod, AppRoleMount: connection.AppRoleMount,
+		RoleID: connection.RoleID, SecretIDEnv: connection.SecretIDEnv, Namespace: connection.Namespace,
+		KVDefaultMount: connection.KVDefaultMount, KVDefaultVer: connection.KVDefaultVersion,
 	}
-	return protocol.TestVaultConnectionResponse{OK: true, Message: fmt.Sprintf("vault auth ok, token=%s...", token[:minInt(8, len(token))])}, nil
 }
 
 func (s *stateStore) vaultConnectionsHandler(w http.ResponseWriter, r *http.Request) {
 	switch r.Method {
 	case http.MethodGet:
-		items, err := s.vaultStore().ListVaultConnections()
+		connections, err := s.app().vault.List(r.Context())
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			http.Error(w, err.Error(), applicationErrorHTTPStatus(err))
 			return
 		}
+		items := make([]protocol.VaultConnection, 0, len(connections))
+		for _, connection := range connections {
+			items = append(items, vaultConnectionToProtocol(connection))
+		}
 		writeJSON(w, http.StatusOK, vaultConnectionsResponse{Connections: items})
 	case http.MethodPost:
 		var req protocol.UpsertVaultConnectionRequest
@@ -66,17 +97,15 @@ func (s *stateStore) vaultConnectionsHandler(w http.ResponseWriter, r *http.Requ
 			http.Error(w, "invalid JSON body", http.StatusBadRequest)
 			return
 		}
-		if strings.TrimSpace(req.Name) == "" || strings.TrimSpace(req.URL) == "" || strings.TrimSpace(req.RoleID) == "" || strings.TrimSpace(req.SecretIDEnv) == "" {
-			http.Error(w, "name, url, role_id and secret_id_env are required", http.StatusBadRequest)
-			return
-		}
-		item, err := s.vaultStore().UpsertVaultConnection(req)
+		connection, err := s.app().vault.Upsert(r.Context(), application.UpsertVaultConnectionRequest{
+			Name: req.Name, URL: req.URL, AuthMethod: req.AuthMethod, AppRoleMount: req.AppRoleMount, RoleID: req.RoleID,
+			SecretIDEnv: req.SecretIDEnv, Namespace: req.Namespace, KVDefaultMount: req.KVDefaultMount, KVDefaultVersion: req.KVDefaultVer,
+		})
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusBadRequest)
+			http.Error(w, err.Error(), applicationErrorHTTPStatus(err))
 			return
 		}
-		s.app().changes.Publish(application.ChangeVault)
-		writeJSON(w, http.StatusCreated, vaultConnectionResponse{Connection: item})
+		writeJSON(w, http.StatusCreated, vaultConnectionResponse{Connection: vaultConnectionToProtocol(connection)})
 	default:
 		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
 	}
@@ -99,11 +128,10 @@ func (s *stateStore) vaultConnectionByIDHandler(w http.ResponseWriter, r *http.R
 			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
 			return
 		}
-		if err := s.vaultStore().DeleteVaultConnection(id); err != nil {
-			http.Error(w, err.Error(), http.StatusNotFound)
+		if err := s.app().vault.Delete(r.Context(), id); err != nil {
+			http.Error(w, err.Error(), applicationErrorHTTPStatus(err))
 			return
 		}
-		s.app().changes.Publish(application.ChangeVault)
 		writeJSON(w, http.StatusOK, vaultConnectionDeleteResponse{Deleted: true, ID: id})
 		return
 	}
@@ -116,23 +144,16 @@ func (s *stateStore) vaultConnectionByIDHandler(w http.ResponseWriter, r *http.R
 		http.Error(w, "invalid JSON body", http.StatusBadRequest)
 		return
 	}
-	conn, err := s.vaultStore().GetVaultConnectionByID(id)
-	if err != nil {
-		http.Error(w, err.Error(), http.StatusNotFound)
-		return
+	test := application.TestVaultConnectionRequest{ID: id, SecretIDOverride: req.SecretIDOverride}
+	if secret := req.TestSecret; secret != nil {
+		test.TestSecret = &application.VaultSecretRef{Name: secret.Name, Mount: secret.Mount, Path: secret.Path, Key: secret.Key, KVVersion: secret.KVVersion}
 	}
-	token, err := s.getVaultToken(r.Context(), conn, req.SecretIDOverride)
+	result, err := s.app().vault.Test(r.Context(), test)
 	if err != nil {
-		writeJSON(w, http.StatusOK, protocol.TestVaultConnectionResponse{OK: false, Message: err.Error()})
+		http.Error(w, err.Error(), applicationErrorHTTPStatus(err))
 		return
 	}
-	if req.TestSecret != nil {
-		if _, err := s.readVaultSecret(r.Context(), conn, *req.TestSecret); err != nil {
-			writeJSON(w, http.StatusOK, protocol.TestVaultConnectionResponse{OK: false, Message: err.Error()})
-			return
-		}
-	}
-	writeJSON(w, http.StatusOK, protocol.TestVaultConnectionResponse{OK: true, Message: "vault auth ok, token=" + token[:minInt(8, len(token))] + "..."})
+	writeJSON(w, http.StatusOK, protocol.TestVaultConnectionResponse{OK: result.OK, Message: result.Message})
 }
 
 func minInt(a, b int) int {
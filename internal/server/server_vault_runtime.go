@@ -76,6 +76,22 @@ func transientVaultSchedulingReason(err error) (string, bool) {
 	return fmt.Sprintf("Waiting for Vault connection %s: %s", connection, detail), true
 }
 
+// jobNeedsSecrets reports whether a step that will run has secret
+// placeholders in its env.
+func jobNeedsSecrets(job protocol.JobExecution) bool {
+	for _, step := range job.StepPlan {
+		if kind := strings.TrimSpace(step.Kind); kind == "dryrun_skip" || kind == "condition_skip" {
+			continue
+		}
+		for _, value := range step.Env {
+			if secretPlaceholderRE.MatchString(value) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 func (s *stateStore) resolveJobSecrets(ctx context.Context, job *protocol.JobExecution) error {
 	if job == nil {
 		return nil
@@ -92,7 +108,7 @@ func (s *stateStore) resolveJobSecrets(ctx context.Context, job *protocol.JobExe
 	hasSecrets := false
 	for i := range job.StepPlan {
 		step := &job.StepPlan[i]
-		if strings.TrimSpace(step.Kind) == "dryrun_skip" {
+		if kind := strings.TrimSpace(step.Kind); kind == "dryrun_skip" || kind == "condition_skip" {
 			continue
 		}
 		if len(step.Env) == 0 {
@@ -93,7 +93,8 @@ func TestResolveJobSecretsAndVaultRuntime(t *testing.T) {
 }
 
 func TestSealedVaultKeepsSecretJobQueuedWithSchedulingReason(t *testing.T) {
-	ts, s := newTestHTTPServerWithState(t)
+	_, s := newTestHTTPServerWithState(t)
+	ts := httptest.NewServer(buildRouter(s, s.artifactsDir))
 	defer ts.Close()
 
 	var sealed atomic.Bool
@@ -142,10 +143,12 @@ func TestSealedVaultKeepsSecretJobQueuedWithSchedulingReason(t *testing.T) {
 		LastSeenUTC:  time.Now().UTC(),
 	}
 	s.mu.Unlock()
-
-	lease := mustJSONRequest(t, ts.Client(), http.MethodPost, ts.URL+"/api/v1/agent/lease", map[string]any{
+	leaseRequest := map[string]any{
 		"agent_id": "agent-mac", "capabilities": map[string]string{"executor": "script", "shells": "posix", "os": "darwin", "arch": "arm64"},
-	})
+	}
+	credential := enrollTestAgent(t, s, "agent-mac")
+
+	lease := mustAgentJSONRequest(t, ts.Client(), http.MethodPost, ts.URL+"/api/v1/agent/lease", "agent-mac", credential, leaseRequest)
 	if lease.StatusCode != http.StatusOK {
 		t.Fatalf("lease status=%d body=%s", lease.StatusCode, readBody(t, lease))
 	}
@@ -183,9 +186,7 @@ func TestSealedVaultKeepsSecretJobQueuedWithSchedulingReason(t *testing.T) {
 	}); err != nil {
 		t.Fatalf("expire retry blocker: %v", err)
 	}
-	retry := mustJSONRequest(t, ts.Client(), http.MethodPost, ts.URL+"/api/v1/agent/lease", map[string]any{
-		"agent_id": "agent-mac", "capabilities": map[string]string{"executor": "script", "shells": "posix", "os": "darwin", "arch": "arm64"},
-	})
+	retry := mustAgentJSONRequest(t, ts.Client(), http.MethodPost, ts.URL+"/api/v1/agent/lease", "agent-mac", credential, leaseRequest)
 	if retry.StatusCode != http.StatusOK {
 		t.Fatalf("retry lease status=%d body=%s", retry.StatusCode, readBody(t, retry))
 	}
@@ -205,6 +206,54 @@ func TestSealedVaultKeepsSecretJobQueuedWithSchedulingReason(t *testing.T) {
 	}
 }
 
+func TestSecretJobIsNotLeasedToAnUnenrolledAgent(t *testing.T) {
+	_, s := newTestHTTPServerWithState(t)
+	ts := httptest.NewServer(buildRouter(s, s.artifactsDir))
+	defer ts.Close()
+
+	job, err := s.db.CreateJobExecution(protocol.CreateJobExecutionRequest{
+		Script:               "deploy",
+		RequiredCapabilities: map[string]string{"os": "linux", "arch": "amd64", "shell": "posix"},
+		StepPlan: []protocol.JobStepPlanItem{{
+			Name: "Deploy", Script: "deploy", VaultConnection: "home-vault",
+			VaultSecrets: []protocol.ProjectSecretSpec{{Name: "token", Path: "ciwi", Key: "token"}},
+			Env:          map[string]string{"TOKEN": "{{ secret.token }}"},
+		}},
+	})
+	if err != nil {
+		t.Fatalf("create job: %v", err)
+	}
+	s.mu.Lock()
+	s.agents["agent-legacy"] = agentState{
+		Hostname: "legacy", OS: "linux", Arch: "amd64", Version: currentVersion(), Authorized: true,
+		Capabilities: map[string]string{"executor": "script", "shells": "posix", "os": "linux", "arch": "amd64"},
+		LastSeenUTC:  time.Now().UTC(),
+	}
+	s.mu.Unlock()
+
+	lease := mustJSONRequest(t, ts.Client(), http.MethodPost, ts.URL+"/api/v1/agent/lease", map[string]any{
+		"agent_id": "agent-legacy", "capabilities": map[string]string{"executor": "script", "shells": "posix", "os": "linux", "arch": "amd64"},
+	})
+	if lease.StatusCode != http.StatusOK {
+		t.Fatalf("lease status=%d body=%s", lease.StatusCode, readBody(t, lease))
+	}
+	var leasePayload struct {
+		Assigned bool   `json:"assigned"`
+		Message  string `json:"message"`
+	}
+	decodeJSONBody(t, lease, &leasePayload)
+	if leasePayload.Assigned || !strings.Contains(leasePayload.Message, "enrolled agent") {
+		t.Fatalf("unenrolled lease response = %+v", leasePayload)
+	}
+	stored, err := s.db.GetJobExecution(job.ID)
+	if err != nil {
+		t.Fatalf("get job: %v", err)
+	}
+	if stored.Status != protocol.JobExecutionStatusQueued || stored.LeasedByAgentID != "" {
+		t.Fatalf("secret job lifecycle = status %q leased by %q", stored.Status, stored.LeasedByAgentID)
+	}
+}
+
 func TestResolveJobSecretsNoopAndMissingSecret(t *testing.T) {
 	ts, s := newTestHTTPServerWithState(t)
 	defer ts.Close()
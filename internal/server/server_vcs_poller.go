@@ -0,0 +1,156 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/izzyreal/ciwi/internal/application"
+	"github.com/izzyreal/ciwi/internal/store"
+)
+
+const (
+	vcsPollerTickInterval  = 10 * time.Second
+	vcsPollDefaultInterval = 60 * time.Second
+	vcsPollResolveTimeout  = 30 * time.Second
+	vcsPollIntervalEnv     = "CIWI_VCS_POLL_INTERVAL_SECONDS"
+	pipelineTriggerVCS     = "vcs"
+)
+
+// vcsPoller starts runs of trigger: vcs pipelines when the commit behind
+// their vcs_source ref changes. The first observation of a repo/ref only
+// records a baseline, so enabling the trigger never replays old commits.
+type vcsPoller struct {
+	store           vcsTriggerStore
+	resolveCommit   func(ctx context.Context, repoURL, ref string) (string, error)
+	enqueue         func(ctx context.Context, pipeline store.PersistedVCSTrigger, commit string) error
+	defaultInterval time.Duration
+}
+
+func (s *stateStore) newVCSPoller() *vcsPoller {
+	return &vcsPoller{
+		store: s.vcsTriggerStore(),
+		resolveCommit: func(ctx context.Context, repoURL, ref string) (string, error) {
+			return resolveRemoteCommitContext(s.withRepoCredential(ctx, repoURL), repoURL, ref)
+		},
+		enqueue: func(ctx context.Context, pipeline store.PersistedVCSTrigger, commit string) error {
+			_, err := s.app().pipelines.RunPipeline(application.WithSystemCaller(ctx, "vcs poller"), application.RunPipelineRequest{
+				PipelineDBID: pipeline.PipelineDBID,
+				Trigger:      pipelineTriggerVCS,
+				SourceCommit: commit,
+			})
+			return err
+		},
+		defaultInterval: vcsPollIntervalFromEnv(),
+	}
+}
+
+func vcsPollIntervalFromEnv() time.Duration {
+	raw := strings.TrimSpace(envOrDefault(vcsPollIntervalEnv, ""))
+	if raw == "" {
+		return vcsPollDefaultInterval
+	}
+	seconds, err := strconv.Atoi(raw)
+	if err != nil || seconds <= 0 {
+		slog.Warn("ignoring invalid vcs poll interval", "env", vcsPollIntervalEnv, "value", raw)
+		return vcsPollDefaultInterval
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+func (p *vcsPoller) interval(pipeline store.PersistedVCSTrigger) time.Duration {
+	if pipeline.State.PollIntervalSeconds > 0 {
+		return time.Duration(pipeline.State.PollIntervalSeconds) * time.Second
+	}
+	if p.defaultInterval > 0 {
+		return p.defaultInterval
+	}
+	return vcsPollDefaultInterval
+}
+
+// pollDue polls every vcs-triggered pipeline whose interval has elapsed.
+// Pipelines watching the same repo and ref share a single remote lookup.
+func (p *vcsPoller) pollDue(ctx context.Context, now time.Time) error {
+	pipelines, err := p.store.ListVCSTriggeredPipelines()
+	if err != nil {
+		return err
+	}
+	type lookup struct {
+		commit string
+		err    error
+	}
+	lookups := map[string]lookup{}
+	for _, pipeline := range pipelines {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		last := pipeline.State.LastPolledUTC
+		if !last.IsZero() && now.Sub(last) < p.interval(pipeline) {
+			continue
+		}
+		key := pipeline.SourceRepo + "\x00" + pipeline.SourceRef
+		result, ok := lookups[key]
+		if !ok {
+			resolveCtx, cancel := context.WithTimeout(ctx, vcsPollResolveTimeout)
+			result.commit, result.err = p.resolveCommit(resolveCtx, pipeline.SourceRepo, pipeline.SourceRef)
+			cancel()
+			lookups[key] = result
+		}
+		rec := store.VCSPollRecord{Repo: pipeline.SourceRepo, Ref: pipeline.SourceRef, PolledUTC: now}
+		if result.err != nil {
+			rec.Error = result.err.Error()
+		} else {
+			rec.Commit = result.commit
+			if p.shouldTrigger(pipeline, result.commit) {
+				if err := p.enqueue(ctx, pipeline, result.commit); err != nil {
+					// Keep the previous seen commit so the next poll retries.
+					rec.Commit = ""
+					rec.Error = fmt.Sprintf("enqueue run for %s: %v", shortCommit(result.commit), err)
+					slog.Error("vcs trigger enqueue failed", "project", pipeline.ProjectName, "pipeline", pipeline.PipelineID, "commit", result.commit, "error", err)
+				} else {
+					rec.TriggeredCommit = result.commit
+					slog.Info("vcs trigger enqueued run", "project", pipeline.ProjectName, "pipeline", pipeline.PipelineID, "commit", result.commit)
+				}
+			}
+		}
+		if err := p.store.RecordVCSPoll(pipeline.PipelineDBID, rec); err != nil {
+			return fmt.Errorf("record vcs poll for %s/%s: %w", pipeline.ProjectName, pipeline.PipelineID, err)
+		}
+	}
+	return nil
+}
+
+func (p *vcsPoller) shouldTrigger(pipeline store.PersistedVCSTrigger, commit string) bool {
+	if pipeline.WatchedRepo != pipeline.SourceRepo || pipeline.WatchedRef != pipeline.SourceRef {
+		return false
+	}
+	seen := strings.TrimSpace(pipeline.State.LastSeenCommit)
+	return seen != "" && !strings.EqualFold(seen, commit)
+}
+
+func (s *stateStore) runVCSPollerLoop(ctx context.Context) {
+	poller := s.newVCSPoller()
+	ticker := time.NewTicker(vcsPollerTickInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := poller.pollDue(ctx, time.Now().UTC()); err != nil && ctx.Err() == nil {
+				slog.Error("vcs poll pass failed", "error", err)
+			}
+		}
+	}
+}
+
+func shortCommit(commit string) string {
+	commit = strings.TrimSpace(commit)
+	if len(commit) > 12 {
+		return commit[:12]
+	}
+	return commit
+}
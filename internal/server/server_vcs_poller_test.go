@@ -0,0 +1,217 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/izzyreal/ciwi/internal/config"
+	"github.com/izzyreal/ciwi/internal/domain"
+	"github.com/izzyreal/ciwi/internal/store"
+)
+
+type fakeVCSTriggerStore struct {
+	pipelines []store.PersistedVCSTrigger
+	records   map[int64]store.VCSPollRecord
+}
+
+func (f *fakeVCSTriggerStore) ListVCSTriggeredPipelines() ([]store.PersistedVCSTrigger, error) {
+	return append([]store.PersistedVCSTrigger(nil), f.pipelines...), nil
+}
+
+func (f *fakeVCSTriggerStore) RecordVCSPoll(pipelineDBID int64, rec store.VCSPollRecord) error {
+	if f.records == nil {
+		f.records = map[int64]store.VCSPollRecord{}
+	}
+	f.records[pipelineDBID] = rec
+	for i := range f.pipelines {
+		p := &f.pipelines[i]
+		if p.PipelineDBID != pipelineDBID {
+			continue
+		}
+		p.WatchedRepo, p.WatchedRef = rec.Repo, rec.Ref
+		p.State.LastPolledUTC = rec.PolledUTC
+		p.State.LastPollError = rec.Error
+		if rec.Commit != "" {
+			p.State.LastSeenCommit = rec.Commit
+		}
+	}
+	return nil
+}
+
+func TestVCSPollerRecordsBaselineThenEnqueuesNewCommits(t *testing.T) {
+	repo := "https://example.invalid/repo.git"
+	fakeStore := &fakeVCSTriggerStore{pipelines: []store.PersistedVCSTrigger{
+		{PipelineDBID: 1, ProjectName: "ciwi", PipelineID: "build", SourceRepo: repo, SourceRef: "main"},
+		{PipelineDBID: 2, ProjectName: "ciwi", PipelineID: "lint", SourceRepo: repo, SourceRef: "main"},
+	}}
+	head := "1111111111111111111111111111111111111111"
+	lookups := 0
+	var enqueued []string
+	poller := &vcsPoller{
+		store: fakeStore,
+		resolveCommit: func(_ context.Context, repoURL, ref string) (string, error) {
+			lookups++
+			return head, nil
+		},
+		enqueue: func(_ context.Context, pipeline store.PersistedVCSTrigger, commit string) error {
+			enqueued = append(enqueued, pipeline.PipelineID+"@"+commit)
+			return nil
+		},
+		defaultInterval: time.Minute,
+	}
+	now := time.Date(2026, 5, 1, 10, 0, 0, 0, time.UTC)
+	if err := poller.pollDue(context.Background(), now); err != nil {
+		t.Fatalf("first poll: %v", err)
+	}
+	if len(enqueued) != 0 || lookups != 1 {
+		t.Fatalf("first poll should only record a shared baseline, enqueued=%v lookups=%d", enqueued, lookups)
+	}
+	if got := fakeStore.pipelines[0].State.LastSeenCommit; got != head {
+		t.Fatalf("expected baseline commit %s, got %q", head, got)
+	}
+
+	head = "2222222222222222222222222222222222222222"
+	if err := poller.pollDue(context.Background(), now.Add(30*time.Second)); err != nil {
+		t.Fatalf("early poll: %v", err)
+	}
+	if len(enqueued) != 0 || lookups != 1 {
+		t.Fatalf("poll before interval elapsed must be skipped, enqueued=%v lookups=%d", enqueued, lookups)
+	}
+
+	if err := poller.pollDue(context.Background(), now.Add(time.Minute)); err != nil {
+		t.Fatalf("second poll: %v", err)
+	}
+	if strings.Join(enqueued, ",") != "build@"+head+",lint@"+head {
+		t.Fatalf("unexpected enqueued runs: %v", enqueued)
+	}
+	if rec := fakeStore.records[1]; rec.TriggeredCommit != head || rec.Error != "" {
+		t.Fatalf("expected triggered record, got %+v", rec)
+	}
+}
+
+func TestVCSPollerPersistsResolveAndEnqueueErrors(t *testing.T) {
+	fakeStore := &fakeVCSTriggerStore{pipelines: []store.PersistedVCSTrigger{{
+		PipelineDBID: 7, ProjectName: "ciwi", PipelineID: "build",
+		SourceRepo: "https://example.invalid/repo.git", SourceRef: "main",
+		WatchedRepo: "https://example.invalid/repo.git", WatchedRef: "main",
+	}}}
+	fakeStore.pipelines[0].State.LastSeenCommit = "aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"
+	resolveErr := errors.New("remote unreachable")
+	poller := &vcsPoller{
+		store: fakeStore,
+		resolveCommit: func(context.Context, string, string) (string, error) {
+			if resolveErr != nil {
+				return "", resolveErr
+			}
+			return "bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb", nil
+		},
+		enqueue: func(context.Context, store.PersistedVCSTrigger, string) error {
+			return errors.New("dependency not satisfied")
+		},
+	}
+	now := time.Date(2026, 5, 1, 10, 0, 0, 0, time.UTC)
+	if err := poller.pollDue(context.Background(), now); err != nil {
+		t.Fatalf("poll: %v", err)
+	}
+	if rec := fakeStore.records[7]; rec.Error != "remote unreachable" || rec.Commit != "" {
+		t.Fatalf("expected resolve error record, got %+v", rec)
+	}
+
+	resolveErr = nil
+	if err := poller.pollDue(context.Background(), now.Add(vcsPollDefaultInterval)); err != nil {
+		t.Fatalf("poll: %v", err)
+	}
+	rec := fakeStore.records[7]
+	if !strings.Contains(rec.Error, "enqueue run for bbbbbbbbbbbb: dependency not satisfied") || rec.TriggeredCommit != "" {
+		t.Fatalf("expected enqueue error record, got %+v", rec)
+	}
+	if rec.Commit != "" || fakeStore.pipelines[0].State.LastSeenCommit != "aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa" {
+		t.Fatalf("failed enqueue must keep the previous seen commit, got %+v", rec)
+	}
+
+	enqueued := 0
+	poller.enqueue = func(context.Context, store.PersistedVCSTrigger, string) error {
+		enqueued++
+		return nil
+	}
+	if err := poller.pollDue(context.Background(), now.Add(2*vcsPollDefaultInterval)); err != nil {
+		t.Fatalf("poll: %v", err)
+	}
+	if rec := fakeStore.records[7]; enqueued != 1 || rec.TriggeredCommit != "bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb" || rec.Error != "" {
+		t.Fatalf("expected the next poll to retry the commit, enqueued=%d record=%+v", enqueued, rec)
+	}
+}
+
+func TestResolveRemoteCommitContext(t *testing.T) {
+	repo := t.TempDir()
+	runGit(t, repo, "init", "-b", "main")
+	runGit(t, repo, "config", "user.name", "ciwi-test")
+	runGit(t, repo, "config", "user.email", "ciwi-test@local")
+	if err := os.WriteFile(filepath.Join(repo, "README"), []byte("one\n"), 0o644); err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+	runGit(t, repo, "add", "README")
+	runGit(t, repo, "commit", "-m", "one")
+	first := strings.TrimSpace(runGit(t, repo, "rev-parse", "HEAD"))
+	runGit(t, repo, "tag", "-a", "v1", "-m", "release v1")
+	runGit(t, repo, "commit", "--allow-empty", "-m", "two")
+	second := strings.TrimSpace(runGit(t, repo, "rev-parse", "HEAD"))
+
+	for ref, want := range map[string]string{"": second, "main": second, "refs/heads/main": second, "v1": first, first: first} {
+		got, err := resolveRemoteCommitContext(context.Background(), repo, ref)
+		if err != nil {
+			t.Fatalf("resolve %q: %v", ref, err)
+		}
+		if got != want {
+			t.Fatalf("resolve %q = %s, want %s", ref, got, want)
+		}
+	}
+	if _, err := resolveRemoteCommitContext(context.Background(), repo, "missing"); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Fatalf("expected missing ref error, got %v", err)
+	}
+}
+
+func TestEnqueueTriggeredPipelinePinsSourceCommit(t *testing.T) {
+	_, s := newTestHTTPServerWithState(t)
+	raw := strings.Replace(testConfigYAML, "trigger: manual", "trigger: vcs", 1)
+	cfg, err := config.Parse([]byte(raw), "vcs-trigger")
+	if err != nil {
+		t.Fatalf("parse config: %v", err)
+	}
+	if err := s.db.LoadConfig(cfg, "ciwi-project.yaml", "https://github.com/izzyreal/ciwi.git", "main", "ciwi-project.yaml"); err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	p, err := s.db.GetPipelineByProjectAndID("ciwi", "build")
+	if err != nil {
+		t.Fatalf("get pipeline: %v", err)
+	}
+	commit := "3333333333333333333333333333333333333333"
+	resp, err := s.enqueueTriggeredPipeline(p, nil, pipelineTrigger{Kind: pipelineTriggerVCS, Commit: commit})
+	if err != nil {
+		t.Fatalf("enqueue triggered pipeline: %v", err)
+	}
+	if resp.Enqueued != 2 {
+		t.Fatalf("expected both matrix entries enqueued, got %+v", resp)
+	}
+	for _, id := range resp.JobExecutionIDs {
+		job, err := s.db.GetJobExecution(id)
+		if err != nil {
+			t.Fatalf("get job: %v", err)
+		}
+		meta := domain.ExecutionMetadata(job.Metadata)
+		if job.Source == nil || job.Source.Ref != commit {
+			t.Fatalf("expected job source pinned to %s, got %+v", commit, job.Source)
+		}
+		if meta.Value(domain.ExecutionMetadataTrigger) != pipelineTriggerVCS || meta.Value(domain.ExecutionMetadataTriggerCommit) != commit {
+			t.Fatalf("unexpected trigger metadata: %v", job.Metadata)
+		}
+		if meta.Value(domain.ExecutionMetadataPipelineSourceRefRaw) != "main" {
+			t.Fatalf("expected raw source ref to stay on the branch, got %v", job.Metadata)
+		}
+	}
+}
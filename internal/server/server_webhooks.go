@@ -0,0 +1,190 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/izzyreal/ciwi/internal/application"
+	"github.com/izzyreal/ciwi/internal/protocol"
+	"github.com/izzyreal/ciwi/internal/server/webhook"
+	"github.com/izzyreal/ciwi/internal/store"
+)
+
+const (
+	webhookMaxBodyBytes    = 10 << 20
+	pipelineTriggerWebhook = "webhook"
+)
+
+type webhookRunResponse struct {
+	PipelineID      string   `json:"pipeline_id"`
+	JobExecutionIDs []string `json:"job_execution_ids,omitempty"`
+	Error           string   `json:"error,omitempty"`
+}
+
+type webhookResponse struct {
+	Ignored     string               `json:"ignored,omitempty"`
+	Event       string               `json:"event,omitempty"`
+	Ref         string               `json:"ref,omitempty"`
+	Commit      string               `json:"commit,omitempty"`
+	Reloaded    bool                 `json:"reloaded"`
+	ReloadError string               `json:"reload_error,omitempty"`
+	Runs        []webhookRunResponse `json:"runs"`
+}
+
+type projectWebhookSecretResponse struct {
+	ProjectID int64  `json:"project_id"`
+	Secret    string `json:"secret"`
+	HookPath  string `json:"hook_path"`
+}
+
+func (s *stateStore) projectWebhookHandler(w http.ResponseWriter, r *http.Request) {
+	projectID, err := strconv.ParseInt(strings.TrimSpace(chi.URLParam(r, "projectId")), 10, 64)
+	if err != nil || projectID <= 0 {
+		http.Error(w, "invalid project id", http.StatusBadRequest)
+		return
+	}
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, webhookMaxBodyBytes))
+	if err != nil {
+		http.Error(w, "read webhook body: "+err.Error(), http.StatusRequestEntityTooLarge)
+		return
+	}
+	secret, err := s.projectWebhookStore().GetProjectWebhookSecret(projectID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	if strings.TrimSpace(secret) == "" {
+		http.Error(w, "webhook is not enabled for this project", http.StatusForbidden)
+		return
+	}
+	if err := webhook.Verify(r.Header, body, secret); err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+	event, err := webhook.Parse(r.Header, body)
+	if errors.Is(err, webhook.ErrIgnoredEvent) {
+		writeJSON(w, http.StatusAccepted, webhookResponse{Ignored: err.Error(), Runs: []webhookRunResponse{}})
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if event.Deleted {
+		writeJSON(w, http.StatusAccepted, webhookResponse{Ignored: "ref deleted", Event: event.Kind, Ref: event.Ref, Runs: []webhookRunResponse{}})
+		return
+	}
+	response := s.handleWebhookEvent(r, projectID, event, webhookDeliveryID(r.Header))
+	writeJSON(w, http.StatusOK, response)
+}
+
+// handleWebhookEvent reloads the project when its config file changed on the
+// tracked ref, then enqueues every vcs-triggered pipeline that watches the
+// pushed repo and ref, pinned to the pushed commit.
+func (s *stateStore) handleWebhookEvent(r *http.Request, projectID int64, event webhook.Event, deliveryID string) webhookResponse {
+	// The signature authenticated the delivery; it acts without a user.
+	ctx := application.WithSystemCaller(r.Context(), "webhook")
+	response := webhookResponse{Event: event.Kind, Ref: event.Ref, Commit: event.Commit, Runs: []webhookRunResponse{}}
+	project, err := s.projectStore().GetProjectByID(projectID)
+	if err != nil {
+		response.ReloadError = err.Error()
+		return response
+	}
+	if webhookShouldReloadProject(project, event) && r.URL.Query().Get("reload") != "false" {
+		if _, err := s.app().projectCommands.Execute(ctx, application.ProjectActionRequest{
+			ProjectID: projectID, Action: application.ProjectActionReload,
+		}); err != nil {
+			response.ReloadError = err.Error()
+			slog.Error("webhook project reload failed", "project", project.Name, "error", err)
+		} else {
+			response.Reloaded = true
+		}
+	}
+	detail, err := s.projectStore().GetProjectDetail(projectID)
+	if err != nil {
+		response.ReloadError = strings.TrimSpace(response.ReloadError + "; " + err.Error())
+		return response
+	}
+	for _, pipeline := range detail.Pipelines {
+		if pipeline.Trigger != pipelineTriggerVCS || !event.MatchesRepo(pipeline.SourceRepo) || !event.MatchesRef(pipeline.SourceRef) {
+			continue
+		}
+		run := webhookRunResponse{PipelineID: pipeline.PipelineID}
+		key := ""
+		if deliveryID != "" {
+			key = fmt.Sprintf("webhook:%s:%d", deliveryID, pipeline.ID)
+		}
+		result, err := s.app().pipelines.RunPipeline(ctx, application.RunPipelineRequest{
+			PipelineDBID: pipeline.ID, Trigger: pipelineTriggerWebhook, SourceCommit: event.Commit, IdempotencyKey: key,
+		})
+		rec := store.VCSPollRecord{Repo: pipeline.SourceRepo, Ref: pipeline.SourceRef, Commit: event.Commit, PolledUTC: time.Now().UTC()}
+		if err != nil {
+			// Keep the previous seen commit so the poller retries the push.
+			rec.Commit = ""
+			run.Error = err.Error()
+			rec.Error = fmt.Sprintf("enqueue run for %s: %v", shortCommit(event.Commit), err)
+		} else {
+			run.JobExecutionIDs = append([]string(nil), result.JobExecutionIDs...)
+			rec.TriggeredCommit = event.Commit
+		}
+		// Recording a triggered commit keeps the poller from triggering it
+		// again on its next pass.
+		if err := s.vcsTriggerStore().RecordVCSPoll(pipeline.ID, rec); err != nil {
+			slog.Error("record webhook trigger state failed", "project", project.Name, "pipeline", pipeline.PipelineID, "error", err)
+		}
+		response.Runs = append(response.Runs, run)
+	}
+	return response
+}
+
+func webhookShouldReloadProject(project protocol.ProjectSummary, event webhook.Event) bool {
+	if project.SourceKind == protocol.ProjectSourceManagedYAML || event.Kind != webhook.KindPush {
+		return false
+	}
+	if !event.MatchesRepo(project.RepoURL) || !event.MatchesRef(project.RepoRef) {
+		return false
+	}
+	configFile := strings.TrimSpace(project.ConfigFile)
+	if configFile == "" {
+		configFile = "ciwi-project.yaml"
+	}
+	return event.Changed(configFile)
+}
+
+func webhookDeliveryID(header http.Header) string {
+	for _, name := range []string{"X-GitHub-Delivery", "X-Gitea-Delivery", "X-Gogs-Delivery", "X-Gitlab-Event-UUID"} {
+		if value := strings.TrimSpace(header.Get(name)); value != "" && len(value) <= 128 {
+			return value
+		}
+	}
+	return ""
+}
+
+func (s *stateStore) projectWebhookSecretHandler(w http.ResponseWriter, r *http.Request, projectID int64) {
+	switch r.Method {
+	case http.MethodPost:
+		result, err := s.app().projectCommands.SetWebhookSecret(r.Context(), application.ProjectWebhookSecretRequest{ProjectID: projectID})
+		if err != nil {
+			http.Error(w, err.Error(), applicationErrorHTTPStatus(err))
+			return
+		}
+		writeJSON(w, http.StatusOK, projectWebhookSecretResponse{
+			ProjectID: projectID, Secret: result.Secret, HookPath: "/api/v1/hooks/" + strconv.FormatInt(projectID, 10),
+		})
+	case http.MethodDelete:
+		if _, err := s.app().projectCommands.SetWebhookSecret(r.Context(), application.ProjectWebhookSecretRequest{ProjectID: projectID, Clear: true}); err != nil {
+			http.Error(w, err.Error(), applicationErrorHTTPStatus(err))
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
@@ -0,0 +1,148 @@
+package server
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/izzyreal/ciwi/internal/config"
+	"github.com/izzyreal/ciwi/internal/domain"
+	"github.com/izzyreal/ciwi/internal/protocol"
+	"github.com/izzyreal/ciwi/internal/server/webhook"
+)
+
+func newWebhookTestServer(t *testing.T) (*httptest.Server, *stateStore, int64) {
+	t.Helper()
+	_, s := newTestHTTPServerWithState(t)
+	raw := strings.Replace(testConfigYAML, "trigger: manual", "trigger: vcs", 1)
+	cfg, err := config.Parse([]byte(raw), "webhook")
+	if err != nil {
+		t.Fatalf("parse config: %v", err)
+	}
+	if err := s.db.LoadConfig(cfg, "ciwi-project.yaml", "https://github.com/izzyreal/ciwi.git", "main", "ciwi-project.yaml"); err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	project, err := s.db.GetProjectByName("ciwi")
+	if err != nil {
+		t.Fatalf("get project: %v", err)
+	}
+	r := chi.NewRouter()
+	r.Post("/api/v1/hooks/{projectId}", s.projectWebhookHandler)
+	r.HandleFunc("/api/v1/projects/*", s.projectByIDHandler)
+	ts := httptest.NewServer(r)
+	t.Cleanup(ts.Close)
+	return ts, s, project.ID
+}
+
+func postWebhook(t *testing.T, url, secret, body string, extra map[string]string) *http.Response {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewBufferString(body))
+	if err != nil {
+		t.Fatalf("new request: %v", err)
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	_, _ = mac.Write([]byte(body))
+	req.Header.Set("X-Hub-Signature-256", "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	req.Header.Set("X-GitHub-Event", "push")
+	for k, v := range extra {
+		req.Header.Set(k, v)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("post webhook: %v", err)
+	}
+	return resp
+}
+
+func TestProjectWebhookEnqueuesMatchingVCSPipelines(t *testing.T) {
+	ts, s, projectID := newWebhookTestServer(t)
+	hookURL := ts.URL + "/api/v1/hooks/" + int64ToString(projectID)
+	commit := "5555555555555555555555555555555555555555"
+	body := `{"ref":"refs/heads/main","after":"` + commit + `","repository":{"clone_url":"https://github.com/izzyreal/ciwi.git","default_branch":"main"},"commits":[{"modified":["main.go"]}]}`
+
+	resp := postWebhook(t, hookURL, "anything", body, nil)
+	if resp.StatusCode != http.StatusForbidden {
+		t.Fatalf("expected disabled webhook to be forbidden, got %d: %s", resp.StatusCode, readBody(t, resp))
+	}
+	_ = resp.Body.Close()
+
+	secretResp := mustJSONRequest(t, ts.Client(), http.MethodPost, ts.URL+"/api/v1/projects/"+int64ToString(projectID)+"/webhook-secret", nil)
+	var secret projectWebhookSecretResponse
+	decodeJSONBody(t, secretResp, &secret)
+	if len(secret.Secret) != 64 || secret.HookPath != "/api/v1/hooks/"+int64ToString(projectID) {
+		t.Fatalf("unexpected webhook secret response: %+v", secret)
+	}
+
+	resp = postWebhook(t, hookURL, "wrong", body, nil)
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("expected bad signature to be rejected, got %d", resp.StatusCode)
+	}
+	_ = resp.Body.Close()
+
+	other := strings.Replace(body, "refs/heads/main", "refs/heads/feature", 1)
+	var ignored webhookResponse
+	decodeJSONBody(t, postWebhook(t, hookURL, secret.Secret, other, nil), &ignored)
+	if len(ignored.Runs) != 0 {
+		t.Fatalf("push to another branch must not trigger runs: %+v", ignored)
+	}
+
+	var result webhookResponse
+	decodeJSONBody(t, postWebhook(t, hookURL, secret.Secret, body, map[string]string{"X-GitHub-Delivery": "delivery-1"}), &result)
+	if result.Reloaded || len(result.Runs) != 1 || result.Runs[0].PipelineID != "build" || len(result.Runs[0].JobExecutionIDs) != 2 {
+		t.Fatalf("unexpected webhook result: %+v", result)
+	}
+	job, err := s.db.GetJobExecution(result.Runs[0].JobExecutionIDs[0])
+	if err != nil {
+		t.Fatalf("get job: %v", err)
+	}
+	meta := domain.ExecutionMetadata(job.Metadata)
+	if job.Source == nil || job.Source.Ref != commit || meta.Value(domain.ExecutionMetadataTrigger) != pipelineTriggerWebhook {
+		t.Fatalf("expected job pinned to pushed commit, got source=%+v meta=%v", job.Source, job.Metadata)
+	}
+
+	// Redelivery of the same webhook must not enqueue the run twice.
+	var redelivered webhookResponse
+	decodeJSONBody(t, postWebhook(t, hookURL, secret.Secret, body, map[string]string{"X-GitHub-Delivery": "delivery-1"}), &redelivered)
+	if len(redelivered.Runs) != 1 || strings.Join(redelivered.Runs[0].JobExecutionIDs, ",") != strings.Join(result.Runs[0].JobExecutionIDs, ",") {
+		t.Fatalf("expected idempotent redelivery, got %+v", redelivered)
+	}
+
+	triggers, err := s.db.ListVCSTriggeredPipelines()
+	if err != nil {
+		t.Fatalf("list vcs triggers: %v", err)
+	}
+	if len(triggers) != 1 || triggers[0].State.LastSeenCommit != commit || triggers[0].State.LastTriggeredCommit != commit {
+		t.Fatalf("expected webhook to advance poll state, got %+v", triggers)
+	}
+}
+
+func TestWebhookShouldReloadProjectOnlyForConfigChangesOnTrackedRef(t *testing.T) {
+	project := protocol.ProjectSummary{RepoURL: "https://github.com/izzyreal/ciwi.git", RepoRef: "main"}
+	event := webhook.Event{
+		Kind: webhook.KindPush, Ref: "refs/heads/main", RepoURLs: []string{"https://github.com/izzyreal/ciwi"},
+		ChangedFiles: []string{"ciwi-project.yaml"},
+	}
+	if !webhookShouldReloadProject(project, event) {
+		t.Fatalf("expected config change on tracked ref to reload")
+	}
+	event.Ref = "refs/heads/feature"
+	if webhookShouldReloadProject(project, event) {
+		t.Fatalf("config change on another ref must not reload")
+	}
+	event.Ref = "refs/heads/main"
+	event.ChangedFiles = []string{"main.go"}
+	if webhookShouldReloadProject(project, event) {
+		t.Fatalf("unrelated change must not reload")
+	}
+	project.SourceKind = protocol.ProjectSourceManagedYAML
+	event.ChangedFiles = []string{"ciwi-project.yaml"}
+	if webhookShouldReloadProject(project, event) {
+		t.Fatalf("managed YAML projects must not reload from VCS")
+	}
+}
@@ -0,0 +1,227 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+const (
+	KindPush = "push"
+	KindTag  = "tag"
+)
+
+var (
+	// ErrUnsigned means the request carried none of the supported signature
+	// headers.
+	ErrUnsigned = errors.New("webhook request is not signed")
+	// ErrBadSignature means a signature was present but did not match.
+	ErrBadSignature = errors.New("webhook signature mismatch")
+	// ErrIgnoredEvent means the request was authentic but is not a push or
+	// tag event, e.g. a ping.
+	ErrIgnoredEvent = errors.New("webhook event ignored")
+)
+
+// Event is a push or tag event normalized across GitHub, Gitea/Forgejo and
+// GitLab payload shapes.
+type Event struct {
+	Kind          string
+	Ref           string
+	Commit        string
+	Deleted       bool
+	RepoURLs      []string
+	DefaultBranch string
+	ChangedFiles  []string
+}
+
+// Verify authenticates a webhook request against the shared secret. GitHub
+// and Gitea sign the body with HMAC-SHA256; GitLab sends the secret itself
+// as a token.
+func Verify(header http.Header, body []byte, secret string) error {
+	if secret == "" {
+		return ErrBadSignature
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	_, _ = mac.Write(body)
+	expected := mac.Sum(nil)
+	signed := false
+	for _, name := range []string{"X-Hub-Signature-256", "X-Gitea-Signature", "X-Gogs-Signature"} {
+		value := strings.TrimSpace(header.Get(name))
+		if value == "" {
+			continue
+		}
+		signed = true
+		got, err := hex.DecodeString(strings.TrimPrefix(value, "sha256="))
+		if err == nil && hmac.Equal(got, expected) {
+			return nil
+		}
+	}
+	if token := header.Get("X-Gitlab-Token"); token != "" {
+		signed = true
+		if subtle.ConstantTimeCompare([]byte(token), []byte(secret)) == 1 {
+			return nil
+		}
+	}
+	if !signed {
+		return ErrUnsigned
+	}
+	return ErrBadSignature
+}
+
+type payload struct {
+	Ref         string `json:"ref"`
+	After       string `json:"after"`
+	CheckoutSHA string `json:"checkout_sha"`
+	Deleted     bool   `json:"deleted"`
+	Repository  struct {
+		CloneURL      string `json:"clone_url"`
+		SSHURL        string `json:"ssh_url"`
+		HTMLURL       string `json:"html_url"`
+		GitHTTPURL    string `json:"git_http_url"`
+		GitSSHURL     string `json:"git_ssh_url"`
+		Homepage      string `json:"homepage"`
+		DefaultBranch string `json:"default_branch"`
+	} `json:"repository"`
+	Project struct {
+		GitHTTPURL    string `json:"git_http_url"`
+		GitSSHURL     string `json:"git_ssh_url"`
+		WebURL        string `json:"web_url"`
+		DefaultBranch string `json:"default_branch"`
+	} `json:"project"`
+	Commits []struct {
+		Added    []string `json:"added"`
+		Modified []string `json:"modified"`
+		Removed  []string `json:"removed"`
+	} `json:"commits"`
+}
+
+// Parse decodes a push or tag event. Other event types return
+// ErrIgnoredEvent.
+func Parse(header http.Header, body []byte) (Event, error) {
+	if name := eventName(header); name != "" && name != "push" && name != "push hook" && name != "tag push hook" {
+		return Event{}, fmt.Errorf("%w: %s", ErrIgnoredEvent, name)
+	}
+	var p payload
+	if err := json.Unmarshal(body, &p); err != nil {
+		return Event{}, fmt.Errorf("decode webhook payload: %w", err)
+	}
+	ev := Event{Ref: strings.TrimSpace(p.Ref), Commit: strings.ToLower(strings.TrimSpace(p.After))}
+	if ev.Ref == "" {
+		return Event{}, fmt.Errorf("%w: payload has no ref", ErrIgnoredEvent)
+	}
+	if !strings.HasPrefix(ev.Ref, "refs/") {
+		return Event{}, fmt.Errorf("%w: ref %q is not fully qualified", ErrIgnoredEvent, ev.Ref)
+	}
+	ev.Kind = KindPush
+	if strings.HasPrefix(ev.Ref, "refs/tags/") {
+		ev.Kind = KindTag
+	}
+	// GitLab reports the peeled commit of annotated tags as checkout_sha.
+	if sha := strings.ToLower(strings.TrimSpace(p.CheckoutSHA)); sha != "" {
+		ev.Commit = sha
+	}
+	ev.Deleted = p.Deleted || strings.Trim(ev.Commit, "0") == ""
+	for _, raw := range []string{
+		p.Repository.CloneURL, p.Repository.SSHURL, p.Repository.HTMLURL, p.Repository.GitHTTPURL, p.Repository.GitSSHURL, p.Repository.Homepage,
+		p.Project.GitHTTPURL, p.Project.GitSSHURL, p.Project.WebURL,
+	} {
+		if raw = strings.TrimSpace(raw); raw != "" {
+			ev.RepoURLs = append(ev.RepoURLs, raw)
+		}
+	}
+	ev.DefaultBranch = strings.TrimSpace(p.Repository.DefaultBranch)
+	if ev.DefaultBranch == "" {
+		ev.DefaultBranch = strings.TrimSpace(p.Project.DefaultBranch)
+	}
+	seen := map[string]struct{}{}
+	for _, c := range p.Commits {
+		for _, files := range [][]string{c.Added, c.Modified, c.Removed} {
+			for _, f := range files {
+				f = strings.TrimPrefix(strings.TrimSpace(f), "./")
+				if _, ok := seen[f]; ok || f == "" {
+					continue
+				}
+				seen[f] = struct{}{}
+				ev.ChangedFiles = append(ev.ChangedFiles, f)
+			}
+		}
+	}
+	return ev, nil
+}
+
+func eventName(header http.Header) string {
+	for _, name := range []string{"X-GitHub-Event", "X-Gitea-Event", "X-Gogs-Event", "X-Gitlab-Event"} {
+		if value := strings.TrimSpace(header.Get(name)); value != "" {
+			return strings.ToLower(value)
+		}
+	}
+	return ""
+}
+
+// MatchesRepo reports whether repoURL names the repository of the event,
+// ignoring scheme, credentials, a trailing ".git" and scp-style ssh syntax.
+func (e Event) MatchesRepo(repoURL string) bool {
+	want := NormalizeRepoURL(repoURL)
+	if want == "" {
+		return false
+	}
+	for _, candidate := range e.RepoURLs {
+		if NormalizeRepoURL(candidate) == want {
+			return true
+		}
+	}
+	return false
+}
+
+// MatchesRef reports whether a configured source ref selects the pushed ref.
+// An empty ref follows the repository default branch.
+func (e Event) MatchesRef(ref string) bool {
+	ref = strings.TrimSpace(ref)
+	switch {
+	case ref == "":
+		return e.DefaultBranch != "" && e.Ref == "refs/heads/"+e.DefaultBranch
+	case strings.HasPrefix(ref, "refs/"):
+		return e.Ref == ref
+	default:
+		return e.Ref == "refs/heads/"+ref || e.Ref == "refs/tags/"+ref
+	}
+}
+
+// Changed reports whether the event touched path. Payloads without file
+// lists (e.g. tag pushes) never report changes.
+func (e Event) Changed(path string) bool {
+	path = strings.TrimPrefix(strings.TrimSpace(path), "./")
+	for _, f := range e.ChangedFiles {
+		if f == path {
+			return true
+		}
+	}
+	return false
+}
+
+func NormalizeRepoURL(raw string) string {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return ""
+	}
+	if !strings.Contains(raw, "://") {
+		// scp-like syntax: git@host:org/repo.git
+		if at := strings.Index(raw, "@"); at >= 0 {
+			raw = raw[at+1:]
+		}
+		raw = "ssh://" + strings.Replace(raw, ":", "/", 1)
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		return strings.ToLower(strings.TrimSuffix(strings.TrimSuffix(raw, "/"), ".git"))
+	}
+	path := strings.TrimSuffix(strings.TrimSuffix(u.Path, "/"), ".git")
+	return strings.ToLower(u.Hostname()) + "/" + strings.ToLower(strings.TrimPrefix(path, "/"))
+}
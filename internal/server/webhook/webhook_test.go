@@ -0,0 +1,113 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"testing"
+)
+
+const giteaPushPayload = `{
+  "ref": "refs/heads/main",
+  "before": "1111111111111111111111111111111111111111",
+  "after": "2222222222222222222222222222222222222222",
+  "commits": [
+    {"added": [], "modified": ["ciwi-project.yaml", "main.go"], "removed": []},
+    {"added": ["docs/new.md"], "modified": ["main.go"], "removed": []}
+  ],
+  "repository": {
+    "clone_url": "https://gitea.example.com/team/app.git",
+    "ssh_url": "git@gitea.example.com:team/app.git",
+    "html_url": "https://gitea.example.com/team/app",
+    "default_branch": "main"
+  }
+}`
+
+func sign(secret, body string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	_, _ = mac.Write([]byte(body))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestVerifyAcceptsForgeSignatures(t *testing.T) {
+	body := []byte(giteaPushPayload)
+	cases := map[string]http.Header{
+		"github": {"X-Hub-Signature-256": []string{"sha256=" + sign("s3cret", giteaPushPayload)}},
+		"gitea":  {"X-Gitea-Signature": []string{sign("s3cret", giteaPushPayload)}},
+		"gitlab": {"X-Gitlab-Token": []string{"s3cret"}},
+	}
+	for name, header := range cases {
+		if err := Verify(header, body, "s3cret"); err != nil {
+			t.Fatalf("%s signature rejected: %v", name, err)
+		}
+		if err := Verify(header, body, "other"); !errors.Is(err, ErrBadSignature) {
+			t.Fatalf("%s signature with wrong secret: got %v", name, err)
+		}
+	}
+	if err := Verify(http.Header{}, body, "s3cret"); !errors.Is(err, ErrUnsigned) {
+		t.Fatalf("expected unsigned error, got %v", err)
+	}
+	tampered := http.Header{"X-Gitea-Signature": []string{sign("s3cret", giteaPushPayload)}}
+	if err := Verify(tampered, append(body, ' '), "s3cret"); !errors.Is(err, ErrBadSignature) {
+		t.Fatalf("expected tampered body to be rejected, got %v", err)
+	}
+}
+
+func TestParseGiteaPush(t *testing.T) {
+	ev, err := Parse(http.Header{"X-Gitea-Event": []string{"push"}}, []byte(giteaPushPayload))
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if ev.Kind != KindPush || ev.Ref != "refs/heads/main" || ev.Commit != "2222222222222222222222222222222222222222" || ev.Deleted {
+		t.Fatalf("unexpected event: %+v", ev)
+	}
+	if len(ev.ChangedFiles) != 3 || !ev.Changed("ciwi-project.yaml") || ev.Changed("README.md") {
+		t.Fatalf("unexpected changed files: %v", ev.ChangedFiles)
+	}
+	for _, repo := range []string{"https://gitea.example.com/team/app", "ssh://git@gitea.example.com/team/app.git", "git@gitea.example.com:team/app.git", "HTTPS://Gitea.example.com/team/app.git/"} {
+		if !ev.MatchesRepo(repo) {
+			t.Fatalf("expected repo %q to match", repo)
+		}
+	}
+	if ev.MatchesRepo("https://gitea.example.com/team/other.git") {
+		t.Fatalf("unexpected match for other repo")
+	}
+	for ref, want := range map[string]bool{"": true, "main": true, "refs/heads/main": true, "develop": false, "refs/tags/main": false} {
+		if got := ev.MatchesRef(ref); got != want {
+			t.Fatalf("MatchesRef(%q) = %v, want %v", ref, got, want)
+		}
+	}
+}
+
+func TestParseGitLabTagPushUsesCheckoutSHA(t *testing.T) {
+	body := `{
+	  "object_kind": "tag_push",
+	  "ref": "refs/tags/v1.2.0",
+	  "after": "3333333333333333333333333333333333333333",
+	  "checkout_sha": "4444444444444444444444444444444444444444",
+	  "project": {"git_http_url": "https://gitlab.example.com/team/app.git", "default_branch": "main"},
+	  "commits": []
+	}`
+	ev, err := Parse(http.Header{"X-Gitlab-Event": []string{"Tag Push Hook"}}, []byte(body))
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if ev.Kind != KindTag || ev.Commit != "4444444444444444444444444444444444444444" || !ev.MatchesRef("v1.2.0") || !ev.MatchesRepo("https://gitlab.example.com/team/app") {
+		t.Fatalf("unexpected tag event: %+v", ev)
+	}
+}
+
+func TestParseIgnoresNonPushEventsAndFlagsDeletes(t *testing.T) {
+	if _, err := Parse(http.Header{"X-GitHub-Event": []string{"ping"}}, []byte(`{"zen":"hi"}`)); !errors.Is(err, ErrIgnoredEvent) {
+		t.Fatalf("expected ping to be ignored, got %v", err)
+	}
+	ev, err := Parse(http.Header{"X-GitHub-Event": []string{"push"}}, []byte(`{"ref":"refs/heads/old","after":"0000000000000000000000000000000000000000","deleted":true}`))
+	if err != nil {
+		t.Fatalf("parse delete: %v", err)
+	}
+	if !ev.Deleted {
+		t.Fatalf("expected branch deletion to be flagged: %+v", ev)
+	}
+}
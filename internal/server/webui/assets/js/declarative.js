@@ -283,11 +283,12 @@
 	if (['projects', 'queued_executions', 'history_executions', 'pipelines', 'visible_pipelines', 'pipeline_chains',
 	  'structure_filters', 'timeline', 'job_properties', 'cache_statistics', 'release_summary', 'output_groups',
 	  'rows', 'nodes', 'filters', 'children', 'issues', 'agents', 'requirements', 'executions', 'sections', 'jobs',
-	  'steps', 'depends_on', 'needs', 'themes', 'connection_modes', 'modes', 'source_refs', 'eligible_agents',
-	  'shells', 'connections', 'update_versions', 'rollback_versions'].includes(field)) return [];
+	  'steps', 'depends_on', 'needs', 'themes', 'connection_modes', 'modes', 'source_refs', 'eligible_agents', 'priority_options',
+	  'shells', 'connections', 'update_versions', 'rollback_versions', 'test_quarantine', 'test_quarantine_notices',
+	  'coverage_trends', 'points'].includes(field)) return [];
 	if (['progress', 'server', 'project', 'agent', 'selected_timeline_item', 'scheduling_diagnosis',
 	  'host_tool_requirements', 'container_tool_requirements', 'run_context', 'artifacts', 'test_report',
-	  'coverage_report', 'structure_root'].includes(field)) return {};
+	  'coverage_report', 'structure_root', 'quarantine_form'].includes(field)) return {};
 	return '';
   }
 
@@ -301,10 +302,10 @@
   function semanticTone(value) {
     switch (String(value || '').trim().toLowerCase()) {
       case 'succeeded': case 'success': case 'passed': case 'complete': case 'completed': case 'online': return 'success';
-      case 'failed': case 'failure': case 'error': case 'cancelled': case 'canceled': case 'offline': return 'danger';
-      case 'warning': case 'queued': case 'waiting': case 'pending': case 'not reached': case 'stale': case 'deactivated': return 'warning';
+      case 'failed': case 'failure': case 'error': case 'offline': return 'danger';
+      case 'warning': case 'cancelled': case 'canceled': case 'queued': case 'waiting': case 'pending': case 'not reached': case 'stale': case 'deactivated': return 'warning';
       case 'accent': case 'running': case 'leased': case 'in progress': case 'active': return 'accent';
-      case 'muted': return 'muted';
+      case 'muted': case 'skipped': return 'muted';
       default: return 'muted';
     }
   }
@@ -355,6 +356,27 @@
     if (layout.maxHeight) element.style.maxHeight = /^\d+$/.test(layout.maxHeight) ? layout.maxHeight + 'px' : layout.maxHeight;
   }
 
+  function agentActionBody(args) {
+	const body = {action: args.action};
+	if (args.slots !== undefined) body.slots = Number(args.slots);
+	if (args.labels !== undefined) body.labels = agentLabelsFromText(args.labels);
+	return body;
+  }
+
+  // agentLabelsFromText reads key=value pairs separated by commas or line
+  // breaks; the server validates keys and values.
+  function agentLabelsFromText(text) {
+	const labels = {};
+	String(text || '').split(/[,\n]/).forEach(pair => {
+	  const trimmed = pair.trim();
+	  if (!trimmed) return;
+	  const separator = trimmed.indexOf('=');
+	  if (separator < 0) labels[trimmed] = '';
+	  else labels[trimmed.slice(0, separator).trim()] = trimmed.slice(separator + 1).trim();
+	});
+	return labels;
+  }
+
   function runSelectionFromArguments(args) {
     return {
       pipeline_job_id: args.pipelineJobId || '',
@@ -362,6 +384,7 @@
       source_ref: args.sourceRef || '',
       agent_id: args.agentId || '',
       execution_mode: args.executionMode || '',
+      priority: args.priority ? Number(args.priority) : undefined,
     };
   }
 
@@ -467,7 +490,7 @@
 	return themeContractPromise;
   }
 
-  function runOptionsViewURL(sourceRef, agentID, routeMatch = currentRouteMatch) {
+  function runOptionsViewURL(sourceRef, agentID, priority, routeMatch = currentRouteMatch) {
     let path = '';
 	if (routeMatch && routeMatch.route.name === 'pipeline-run-options') path = '/api/v1/views/run-options/pipelines/' + encodeURIComponent(routeMatch.params.pipelineId);
     if (routeMatch && routeMatch.route.name === 'legacy-pipeline-run-options') path = '/api/v1/views/run-options/pipelines/' + encodeURIComponent(routeMatch.params.pipelineId);
@@ -476,6 +499,7 @@
     const query = new URLSearchParams();
     if (sourceRef) query.set('source_ref', sourceRef);
     if (agentID) query.set('agent_id', agentID);
+    if (priority) query.set('priority', priority);
     return path + (query.size ? '?' + query.toString() : '');
   }
 
@@ -643,14 +667,15 @@
 		  if (args.field === 'sourceRef') {
 		    options.selected_source_ref = args.value || '';
 		    options.selected_agent_id = '';
-		  } else if (args.field === 'agentId') {
-		    options.selected_agent_id = args.value || '';
+		  } else if (args.field === 'agentId' || args.field === 'priority') {
+		    if (args.field === 'agentId') options.selected_agent_id = args.value || '';
+		    else options.selected_priority = args.value || '';
 		    renderCurrent();
 		    return;
 		  } else {
 		    throw new Error('Unsupported run option');
 		  }
-		  const response = await fetch(runOptionsViewURL(options.selected_source_ref, options.selected_agent_id), {signal: runtime.signal});
+		  const response = await fetch(runOptionsViewURL(options.selected_source_ref, options.selected_agent_id, options.selected_priority), {signal: runtime.signal});
 		  if (!response.ok) throw new Error(await response.text());
 		  const refreshedOptions = viewBindings.markBrowserViewReady(await response.json());
 		  currentData = {runOptions: refreshedOptions, client: viewBindings.browserClientBinding()};
@@ -784,12 +809,13 @@
 		  const response = await fetch('/api/v1/agents/' + encodeURIComponent(args.agentId) + '/actions', {
 		    method: 'POST',
 		    headers: ciwiActionHeaders(runtime, {'Content-Type': 'application/json'}),
-		    body: JSON.stringify({action: args.action}),
+		    body: JSON.stringify(agentActionBody(args)),
 		    signal: runtime.signal,
 		  });
 		  if (!response.ok) throw new Error(await response.text());
 		  const result = await response.json();
 		  showResponseMessageNotice(result);
+		  if (args.action === 'set-labels' && currentData && currentData.agentDetails) currentData.agentDetails.labels_draft_edited = false;
 		  if (args.action === 'delete' && args.successRoute) {
 			await navigateBrowser(args.successRoute, {replace: true});
 			navigatedAfterSuccess = true;
@@ -806,6 +832,37 @@
 		  if (!response.ok) throw new Error(await response.text());
 		  await refresh();
 		}
+		else if (action.command === 'set-agent-labels-draft') {
+		  const details = currentData && currentData.agentDetails;
+		  if (!details) throw new Error('Agent details are unavailable');
+		  details.labels_draft = args.value || '';
+		  details.labels_draft_edited = true;
+		}
+		else if (action.command === 'set-test-quarantine-field') {
+		  const form = currentData && currentData.projectDetails && currentData.projectDetails.quarantine_form;
+		  if (!form || !Object.prototype.hasOwnProperty.call(form, args.field)) throw new Error('Test quarantine form is unavailable');
+		  form[args.field] = args.value || '';
+		}
+		else if (action.command === 'quarantine-test') {
+		  const response = await fetch('/api/v1/projects/' + encodeURIComponent(args.projectId) + '/test-quarantine', {
+			method: 'POST', headers: ciwiActionHeaders(runtime, {'Content-Type': 'application/json'}),
+			body: JSON.stringify({
+			  package: args.package || '', name: args.name || '', owner: args.owner || '',
+			  reason: args.reason || '', expires_utc: args.expires || '',
+			}), signal: runtime.signal,
+		  });
+		  if (!response.ok) throw new Error(await response.text());
+		  const details = currentData && currentData.projectDetails;
+		  if (details) details.quarantine_form = viewBindings.emptyTestQuarantineForm();
+		  await refresh();
+		}
+		else if (action.command === 'remove-test-quarantine') {
+		  const response = await fetch('/api/v1/projects/' + encodeURIComponent(args.projectId) + '/test-quarantine/' + encodeURIComponent(args.id), {
+			method: 'DELETE', headers: ciwiActionHeaders(runtime), signal: runtime.signal,
+		  });
+		  if (!response.ok) throw new Error(await response.text());
+		  await refresh();
+		}
 		else if (action.command === 'set-project-import-field') {
 		  const settings = currentData && currentData.settings;
 		  if (!settings) throw new Error('Settings are unavailable');
@@ -2007,7 +2064,7 @@
 	  if (managedYAMLMatch && routeName === 'managed-yaml-new') viewURL = '';
 	  if (agentScriptMatch) viewURL = '/api/v1/views/agents/' + encodeURIComponent(nextRouteMatch.params.agentId);
 	  if (vaultMatch) viewURL = '/api/v1/vault/connections';
-	  if (runOptionsMatch) viewURL = runOptionsViewURL('', '', nextRouteMatch);
+	  if (runOptionsMatch) viewURL = runOptionsViewURL('', '', '', nextRouteMatch);
 	  const viewPromise = viewURL
 		? fetch(viewURL)
 		: Promise.resolve(new Response('{}', {status: 200, headers: {'Content-Type': 'application/json'}}));
@@ -2045,6 +2102,7 @@
       let view = responseView;
 	  if (managedYAMLMatch) view = viewBindings.managedYAMLBinding(responseView);
 	  if (agentScriptMatch) view = viewBindings.agentScriptBinding(responseView, nextRouteMatch.params.agentId);
+	  if (agentDetailsMatch) viewBindings.decorateAgentDetails(view);
 	  if (vaultMatch) view = viewBindings.vaultBinding(responseView);
 	  if (projectMatch) {
 		viewBindings.decorateProjectDetails(view);
@@ -2119,7 +2177,7 @@
 		const previousSelectionID = sameJob && previousJob.selected_timeline_item
 		  ? String(previousJob.selected_timeline_item.id || '') : '';
 		view.selected_timeline_item = timeline.find(item => String(item.id || '') === previousSelectionID)
-		  || timeline.find(item => ['running', 'in progress', 'failed'].includes(String(item.status || '').toLowerCase()))
+		  || timeline.find(item => ['running', 'in progress', 'failed', 'cancelled'].includes(String(item.status || '').toLowerCase()))
 		  || timeline[0]
 		  || {id:'', title:'No execution steps reported', description:'', status:'', status_label:'', duration:'', exit_code:'', error:''};
       }
@@ -31,6 +31,15 @@
 	const previousFilter = previousProject && String(previousProject.id) === String(project.id)
 	  ? String(getCurrentData().projectDetails.structure_filter || 'all-pipelines')
 	  : 'all-pipelines';
+	const previousForm = previousProject && String(previousProject.id) === String(project.id)
+	  ? getCurrentData().projectDetails.quarantine_form
+	  : null;
+	view.quarantine_form = Object.assign(emptyTestQuarantineForm(), previousForm || {});
+	view.test_quarantine = Array.isArray(view.test_quarantine) ? view.test_quarantine : [];
+	view.test_quarantine_notices = Array.isArray(view.test_quarantine_notices) ? view.test_quarantine_notices : [];
+	view.test_quarantine_empty = view.test_quarantine.length === 0;
+	view.coverage_trends = Array.isArray(view.coverage_trends) ? view.coverage_trends : [];
+	view.coverage_trends_empty = view.coverage_trends.length === 0;
 	project.project_icon = Number(project.id || 0) > 0 ? '/api/v1/projects/' + encodeURIComponent(project.id) + '/icon' : '';
 	view.loading = false;
 	view.ready = true;
@@ -38,6 +47,18 @@
 	applyProjectStructureFilter(view, previousFilter);
     }
 
+    function decorateAgentDetails(view) {
+	const agent = (view && view.agent) || {};
+	const previous = getCurrentData() && getCurrentData().agentDetails;
+	const keepDraft = !!(previous && previous.labels_draft_edited && previous.agent && previous.agent.id === agent.id);
+	view.labels_draft = keepDraft ? previous.labels_draft : String(agent.assigned_labels || '');
+	view.labels_draft_edited = keepDraft;
+    }
+
+    function emptyTestQuarantineForm() {
+	return {package: '', name: '', owner: '', reason: '', expires: ''};
+    }
+
     function projectDetailsLoadingBinding(projectID) {
 	const id = String(projectID || '');
 	const currentProject = getCurrentData() && getCurrentData().projectDetails && getCurrentData().projectDetails.project;
@@ -58,6 +79,8 @@
 	  structure_root: {id: 'project:' + id + ':loading', label: project.name, meta: '', runnable: false, project_id: id, chain_id: ''},
 	  show_chain_structure: false, show_pipeline_structure: false,
 	  history_executions: [], history_empty: true,
+	  test_quarantine: [], test_quarantine_empty: true, test_quarantine_notices: [], quarantine_form: emptyTestQuarantineForm(),
+	  coverage_trends: [], coverage_trends_empty: true,
 	  loading: true, ready: false, load_error: '',
 	};
     }
@@ -104,7 +127,7 @@
 	if (routeName.includes('run-options')) return Object.assign(loading, {
 	  project_id: Number(params.projectId || 0), pipeline_db_id: Number(params.pipelineId || 0),
 	  chain_id: String(params.chainId || ''), target_label: 'Run options', target_kind: 'loading',
-	  source_refs: [], eligible_agents: [], selected_source_ref: '', selected_agent_id: '',
+	  source_refs: [], eligible_agents: [], priority_options: [], selected_source_ref: '', selected_agent_id: '', selected_priority: '',
 	});
 	return loading;
     }
@@ -184,6 +207,8 @@
     return {
       decorateFrontPageProjects,
       decorateProjectDetails,
+      decorateAgentDetails,
+      emptyTestQuarantineForm,
       browserLoadingBinding,
       browserClientBinding,
       markBrowserViewReady,
@@ -25,6 +25,7 @@ type embeddedAsset struct {
 var staticRoutes = map[string]embeddedAsset{
 	"/favicon.ico":            {"assets/ciwi-favicon.png", "image/png", true, false},
 	"/ciwi-favicon.png":       {"assets/ciwi-favicon.png", "image/png", true, false},
+	"/login":                  {"assets/pages/login.html", "text/html; charset=utf-8", false, false},
 	"/ui/icons.svg":           {"assets/tabler-icons.svg", "image/svg+xml", true, false},
 	"/ui/theme.js":            {"assets/js/theme.js", "application/javascript; charset=utf-8", true, true},
 	"/ui/heartbeat.js":        {"assets/js/heartbeat.js", "application/javascript; charset=utf-8", true, true},
@@ -29,7 +29,7 @@ func TestContextAwareFrontPageReadsCancelWhileSQLiteConnectionBusy(t *testing.T)
 			return err
 		}},
 		{name: "active agents", read: func(ctx context.Context, store *Store) error {
-			_, err := store.ListActiveJobExecutionAgentIDsContext(ctx)
+			_, err := store.CountActiveJobExecutionsByAgentContext(ctx)
 			return err
 		}},
 	}
@@ -567,6 +567,58 @@ pipelines:
 	}
 }
 
+func TestStoreRepoCredentialLifecycle(t *testing.T) {
+	s := openTestStore(t)
+	cred, err := s.UpsertRepoCredential(protocol.UpsertRepoCredentialRequest{
+		Name:      "github-org",
+		URLPrefix: "https://github.com/example/",
+		Kind:      protocol.RepoCredentialHTTPSToken,
+		SecretEnv: "CIWI_GITHUB_TOKEN",
+	})
+	if err != nil {
+		t.Fatalf("UpsertRepoCredential: %v", err)
+	}
+	if cred.ID <= 0 || cred.URLPrefix != "https://github.com/example/" || cred.VaultSecret != nil {
+		t.Fatalf("unexpected repo credential: %+v", cred)
+	}
+
+	updated, err := s.UpsertRepoCredential(protocol.UpsertRepoCredentialRequest{
+		Name:            "github-org",
+		URLPrefix:       "git@github.com:example/",
+		Kind:            protocol.RepoCredentialSSHKey,
+		VaultConnection: "ciwi-vault",
+		VaultSecret:     &protocol.ProjectSecretSpec{Path: "ci/deploy-key", Key: "private_key"},
+		KnownHosts:      "github.com ssh-ed25519 AAAA",
+	})
+	if err != nil {
+		t.Fatalf("UpsertRepoCredential update: %v", err)
+	}
+	if updated.ID != cred.ID || updated.Kind != protocol.RepoCredentialSSHKey || updated.SecretEnv != "" {
+		t.Fatalf("expected the credential to be updated in place, got %+v", updated)
+	}
+	if updated.VaultSecret == nil || updated.VaultSecret.Path != "ci/deploy-key" || updated.VaultSecret.Key != "private_key" {
+		t.Fatalf("expected the vault secret to round trip, got %+v", updated.VaultSecret)
+	}
+
+	list, err := s.ListRepoCredentials()
+	if err != nil {
+		t.Fatalf("ListRepoCredentials: %v", err)
+	}
+	if len(list) != 1 || list[0].KnownHosts != "github.com ssh-ed25519 AAAA" {
+		t.Fatalf("unexpected repo credential list: %+v", list)
+	}
+
+	if err := s.DeleteRepoCredential(cred.ID); err != nil {
+		t.Fatalf("DeleteRepoCredential: %v", err)
+	}
+	if _, err := s.GetRepoCredentialByID(cred.ID); err == nil {
+		t.Fatalf("expected deleted repo credential lookup to fail")
+	}
+	if err := s.DeleteRepoCredential(cred.ID); err == nil {
+		t.Fatalf("expected deleting missing repo credential to fail")
+	}
+}
+
 func TestStoreProjectLookupNotFound(t *testing.T) {
 	s := openTestStore(t)
 	if _, err := s.GetProjectByID(42); err == nil {
@@ -0,0 +1,162 @@
+package store
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/izzyreal/ciwi/internal/domain"
+)
+
+const enrollmentTokenColumns = `id, description, token_prefix, created_by, created_utc, expires_utc, used_utc, used_by_agent_id`
+
+func (s *Store) CreateEnrollmentToken(token domain.AgentEnrollmentToken, tokenHash string) (domain.AgentEnrollmentToken, error) {
+	created := token.CreatedUTC
+	if created.IsZero() {
+		created = time.Now().UTC()
+	}
+	result, err := s.db.Exec(`
+		INSERT INTO agent_enrollment_tokens (description, token_prefix, token_hash, created_by, created_utc, expires_utc)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`, strings.TrimSpace(token.Description), token.Prefix, tokenHash, strings.TrimSpace(token.CreatedBy),
+		created.UTC().Format(time.RFC3339Nano), token.ExpiresUTC.UTC().Format(time.RFC3339Nano))
+	if err != nil {
+		return domain.AgentEnrollmentToken{}, fmt.Errorf("create enrollment token: %w", err)
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return domain.AgentEnrollmentToken{}, fmt.Errorf("create enrollment token id: %w", err)
+	}
+	token.ID = id
+	token.CreatedUTC = created
+	return token, nil
+}
+
+func (s *Store) ListEnrollmentTokens() ([]domain.AgentEnrollmentToken, error) {
+	rows, err := s.db.Query(`SELECT ` + enrollmentTokenColumns + ` FROM agent_enrollment_tokens ORDER BY id DESC`)
+	if err != nil {
+		return nil, fmt.Errorf("list enrollment tokens: %w", err)
+	}
+	defer rows.Close()
+	var out []domain.AgentEnrollmentToken
+	for rows.Next() {
+		var token domain.AgentEnrollmentToken
+		var created, expires string
+		var used sql.NullString
+		if err := rows.Scan(&token.ID, &token.Description, &token.Prefix, &token.CreatedBy, &created, &expires, &used, &token.UsedByAgentID); err != nil {
+			return nil, fmt.Errorf("scan enrollment token: %w", err)
+		}
+		token.CreatedUTC = parseStoredTime(created)
+		token.ExpiresUTC = parseStoredTime(expires)
+		token.UsedUTC = parseNullableStoredTime(used)
+		out = append(out, token)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate enrollment tokens: %w", err)
+	}
+	return out, nil
+}
+
+func (s *Store) DeleteEnrollmentToken(id int64) (bool, error) {
+	result, err := s.db.Exec(`DELETE FROM agent_enrollment_tokens WHERE id = ?`, id)
+	if err != nil {
+		return false, fmt.Errorf("delete enrollment token: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("delete enrollment token rows affected: %w", err)
+	}
+	return rows > 0, nil
+}
+
+// EnrollAgent consumes an unused, unexpired enrollment token and binds
+// credentialHash to agentID in one transaction, so a token can never enroll
+// two agents.
+func (s *Store) EnrollAgent(tokenHash, agentID, credentialHash string, now time.Time) (bool, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return false, fmt.Errorf("begin enroll agent: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+	var id int64
+	var expires string
+	err = tx.QueryRow(`
+		SELECT id, expires_utc FROM agent_enrollment_tokens WHERE token_hash = ? AND used_utc IS NULL
+	`, tokenHash).Scan(&id, &expires)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("find enrollment token: %w", err)
+	}
+	if !parseStoredTime(expires).After(now) {
+		return false, nil
+	}
+	at := now.UTC().Format(time.RFC3339Nano)
+	result, err := tx.Exec(`
+		UPDATE agent_enrollment_tokens SET used_utc = ?, used_by_agent_id = ? WHERE id = ? AND used_utc IS NULL
+	`, at, agentID, id)
+	if err != nil {
+		return false, fmt.Errorf("consume enrollment token: %w", err)
+	}
+	if rows, err := result.RowsAffected(); err == nil && rows == 0 {
+		return false, nil
+	}
+	if _, err := tx.Exec(`
+		INSERT INTO agent_credentials (agent_id, secret_hash, created_utc, revoked_utc) VALUES (?, ?, ?, NULL)
+		ON CONFLICT(agent_id) DO UPDATE SET secret_hash = excluded.secret_hash, created_utc = excluded.created_utc, revoked_utc = NULL
+	`, agentID, credentialHash, at); err != nil {
+		return false, fmt.Errorf("store agent credential: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("commit enroll agent: %w", err)
+	}
+	return true, nil
+}
+
+// GetAgentCredential returns the credential record of an agent and its
+// secret hash, which is empty once revoked.
+func (s *Store) GetAgentCredential(agentID string) (domain.AgentCredential, string, bool, error) {
+	credential := domain.AgentCredential{AgentID: agentID}
+	var secretHash, revoked sql.NullString
+	var created string
+	err := s.db.QueryRow(`
+		SELECT secret_hash, created_utc, revoked_utc FROM agent_credentials WHERE agent_id = ?
+	`, agentID).Scan(&secretHash, &created, &revoked)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.AgentCredential{}, "", false, nil
+	}
+	if err != nil {
+		return domain.AgentCredential{}, "", false, fmt.Errorf("get agent credential: %w", err)
+	}
+	credential.CreatedUTC = parseStoredTime(created)
+	credential.RevokedUTC = parseNullableStoredTime(revoked)
+	return credential, secretHash.String, true, nil
+}
+
+// AgentEnrollmentInUse reports whether an enrollment token was ever kept or
+// an agent holds a credential record, revoked or not.
+func (s *Store) AgentEnrollmentInUse() (bool, error) {
+	var inUse bool
+	if err := s.db.QueryRow(`
+		SELECT EXISTS (SELECT 1 FROM agent_enrollment_tokens) OR EXISTS (SELECT 1 FROM agent_credentials)
+	`).Scan(&inUse); err != nil {
+		return false, fmt.Errorf("check agent enrollment: %w", err)
+	}
+	return inUse, nil
+}
+
+// RevokeAgentCredential clears the agent's secret. Agents without a record
+// get a revoked one, which locks the ID until it enrolls.
+func (s *Store) RevokeAgentCredential(agentID string, at time.Time) error {
+	stamp := at.UTC().Format(time.RFC3339Nano)
+	if _, err := s.db.Exec(`
+		INSERT INTO agent_credentials (agent_id, secret_hash, created_utc, revoked_utc) VALUES (?, NULL, ?, ?)
+		ON CONFLICT(agent_id) DO UPDATE SET secret_hash = NULL, revoked_utc = excluded.revoked_utc
+	`, agentID, stamp, stamp); err != nil {
+		return fmt.Errorf("revoke agent credential: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package store
+
+import (
+	"testing"
+	"time"
+
+	"github.com/izzyreal/ciwi/internal/domain"
+)
+
+func TestStoreEnrollmentTokensAreSingleUse(t *testing.T) {
+	s := openTestStore(t)
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	if inUse, err := s.AgentEnrollmentInUse(); err != nil || inUse {
+		t.Fatalf("expected a fresh store not to use enrollment, inUse=%v err=%v", inUse, err)
+	}
+	token, err := s.CreateEnrollmentToken(domain.AgentEnrollmentToken{Description: "rack 1", Prefix: "ciwi_enroll_ab", CreatedUTC: now, ExpiresUTC: now.Add(time.Hour)}, "token-hash")
+	if err != nil {
+		t.Fatalf("create enrollment token: %v", err)
+	}
+	if inUse, err := s.AgentEnrollmentInUse(); err != nil || !inUse {
+		t.Fatalf("expected an enrollment token to put enrollment in use, inUse=%v err=%v", inUse, err)
+	}
+	if ok, err := s.EnrollAgent("token-hash", "agent-a", "cred-a", now.Add(2*time.Hour)); err != nil || ok {
+		t.Fatalf("expected expired token to be rejected, ok=%v err=%v", ok, err)
+	}
+	if ok, err := s.EnrollAgent("token-hash", "agent-a", "cred-a", now.Add(time.Minute)); err != nil || !ok {
+		t.Fatalf("enroll agent: ok=%v err=%v", ok, err)
+	}
+	if ok, err := s.EnrollAgent("token-hash", "agent-b", "cred-b", now.Add(time.Minute)); err != nil || ok {
+		t.Fatalf("expected used token to be rejected, ok=%v err=%v", ok, err)
+	}
+	tokens, err := s.ListEnrollmentTokens()
+	if err != nil || len(tokens) != 1 || tokens[0].ID != token.ID || tokens[0].UsedByAgentID != "agent-a" || tokens[0].UsedUTC.IsZero() {
+		t.Fatalf("unexpected tokens: %+v err=%v", tokens, err)
+	}
+	credential, hash, found, err := s.GetAgentCredential("agent-a")
+	if err != nil || !found || hash != "cred-a" || credential.Revoked() {
+		t.Fatalf("unexpected credential: %+v hash=%q found=%v err=%v", credential, hash, found, err)
+	}
+}
+
+func TestStoreRevokeAgentCredentialKeepsAgentBound(t *testing.T) {
+	s := openTestStore(t)
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	if err := s.RevokeAgentCredential("never-enrolled", now); err != nil {
+		t.Fatalf("revoke unknown agent: %v", err)
+	}
+	credential, hash, found, err := s.GetAgentCredential("never-enrolled")
+	if err != nil || !found || hash != "" || !credential.Revoked() {
+		t.Fatalf("expected revoked record, got %+v hash=%q found=%v err=%v", credential, hash, found, err)
+	}
+	if _, err := s.CreateEnrollmentToken(domain.AgentEnrollmentToken{Prefix: "p", ExpiresUTC: now.Add(time.Hour)}, "token-hash"); err != nil {
+		t.Fatalf("create enrollment token: %v", err)
+	}
+	if ok, err := s.EnrollAgent("token-hash", "never-enrolled", "cred", now); err != nil || !ok {
+		t.Fatalf("re-enroll: ok=%v err=%v", ok, err)
+	}
+	credential, hash, _, _ = s.GetAgentCredential("never-enrolled")
+	if hash != "cred" || credential.Revoked() {
+		t.Fatalf("expected re-enrollment to restore credential, got %+v hash=%q", credential, hash)
+	}
+}
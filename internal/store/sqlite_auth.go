@@ -0,0 +1,361 @@
+package store
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/izzyreal/ciwi/internal/domain"
+)
+
+var (
+	ErrUsernameTaken = errors.New("username is already taken")
+	// ErrUsersExist is returned when a first user is created after another
+	// one already exists.
+	ErrUsersExist = errors.New("a user already exists")
+)
+
+const userColumns = `id, username, role, created_utc, last_login_utc`
+
+func (s *Store) CountUsers() (int, error) {
+	var count int
+	if err := s.db.QueryRow(`SELECT COUNT(*) FROM users`).Scan(&count); err != nil {
+		return 0, fmt.Errorf("count users: %w", err)
+	}
+	return count, nil
+}
+
+func (s *Store) ListUsers() ([]domain.User, error) {
+	rows, err := s.db.Query(`SELECT ` + userColumns + ` FROM users ORDER BY username COLLATE NOCASE`)
+	if err != nil {
+		return nil, fmt.Errorf("list users: %w", err)
+	}
+	defer rows.Close()
+	var out []domain.User
+	for rows.Next() {
+		user, err := scanUser(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, user)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate users: %w", err)
+	}
+	return out, nil
+}
+
+func (s *Store) GetUser(id int64) (domain.User, bool, error) {
+	user, err := scanUser(s.db.QueryRow(`SELECT `+userColumns+` FROM users WHERE id = ?`, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.User{}, false, nil
+	}
+	if err != nil {
+		return domain.User{}, false, err
+	}
+	return user, true, nil
+}
+
+// GetUserCredentials returns a user and its encoded password hash.
+// Usernames match case-insensitively.
+func (s *Store) GetUserCredentials(username string) (domain.User, string, bool, error) {
+	var passwordHash string
+	var user domain.User
+	var role string
+	var created string
+	var lastLogin sql.NullString
+	err := s.db.QueryRow(`
+		SELECT id, username, role, created_utc, last_login_utc, password_hash FROM users WHERE username = ?
+	`, strings.TrimSpace(username)).Scan(&user.ID, &user.Username, &role, &created, &lastLogin, &passwordHash)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.User{}, "", false, nil
+	}
+	if err != nil {
+		return domain.User{}, "", false, fmt.Errorf("get user credentials: %w", err)
+	}
+	user.Role = domain.Role(role)
+	user.CreatedUTC = parseStoredTime(created)
+	user.LastLoginUTC = parseNullableStoredTime(lastLogin)
+	return user, passwordHash, true, nil
+}
+
+// CreateUser inserts a user. With first set the insert only succeeds while
+// the users table is empty, which makes bootstrapping the first admin safe
+// against concurrent requests.
+func (s *Store) CreateUser(user domain.User, passwordHash string, first bool) (domain.User, error) {
+	created := user.CreatedUTC
+	if created.IsZero() {
+		created = time.Now().UTC()
+	}
+	query := `INSERT INTO users (username, role, password_hash, created_utc) VALUES (?, ?, ?, ?)`
+	if first {
+		query = `INSERT INTO users (username, role, password_hash, created_utc)
+			SELECT ?, ?, ?, ? WHERE NOT EXISTS (SELECT 1 FROM users)`
+	}
+	result, err := s.db.Exec(query, strings.TrimSpace(user.Username), string(user.Role), passwordHash, created.UTC().Format(time.RFC3339Nano))
+	if err != nil {
+		if strings.Contains(err.Error(), "UNIQUE constraint failed") {
+			return domain.User{}, ErrUsernameTaken
+		}
+		return domain.User{}, fmt.Errorf("create user: %w", err)
+	}
+	if rows, err := result.RowsAffected(); err == nil && rows == 0 {
+		return domain.User{}, ErrUsersExist
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return domain.User{}, fmt.Errorf("create user id: %w", err)
+	}
+	out, found, err := s.GetUser(id)
+	if err != nil {
+		return domain.User{}, err
+	}
+	if !found {
+		return domain.User{}, fmt.Errorf("created user %d not found", id)
+	}
+	return out, nil
+}
+
+// UpdateUser sets the role of a user and, unless passwordHash is empty, its
+// password. Changing the password signs the user out everywhere.
+func (s *Store) UpdateUser(id int64, role domain.Role, passwordHash string) (domain.User, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return domain.User{}, fmt.Errorf("begin update user: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+	result, err := tx.Exec(`UPDATE users SET role = ? WHERE id = ?`, string(role), id)
+	if err != nil {
+		return domain.User{}, fmt.Errorf("update user: %w", err)
+	}
+	if rows, err := result.RowsAffected(); err == nil && rows == 0 {
+		return domain.User{}, fmt.Errorf("user not found")
+	}
+	if passwordHash != "" {
+		if _, err := tx.Exec(`UPDATE users SET password_hash = ? WHERE id = ?`, passwordHash, id); err != nil {
+			return domain.User{}, fmt.Errorf("update user password: %w", err)
+		}
+		if _, err := tx.Exec(`DELETE FROM user_sessions WHERE user_id = ?`, id); err != nil {
+			return domain.User{}, fmt.Errorf("end user sessions: %w", err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return domain.User{}, fmt.Errorf("commit update user: %w", err)
+	}
+	user, _, err := s.GetUser(id)
+	return user, err
+}
+
+// DeleteUser removes a user together with its sessions and API tokens.
+func (s *Store) DeleteUser(id int64) error {
+	if _, err := s.db.Exec(`DELETE FROM users WHERE id = ?`, id); err != nil {
+		return fmt.Errorf("delete user: %w", err)
+	}
+	return nil
+}
+
+// RecordLogin stores a new session and the login time. Expired sessions of
+// every user are dropped on the way.
+func (s *Store) RecordLogin(userID int64, sessionHash string, at, expires time.Time) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("begin login: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+	if err := deleteExpiredSessions(tx, at); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(`
+		INSERT INTO user_sessions (token_hash, user_id, created_utc, expires_utc) VALUES (?, ?, ?, ?)
+	`, sessionHash, userID, at.UTC().Format(time.RFC3339Nano), expires.UTC().Format(time.RFC3339Nano)); err != nil {
+		return fmt.Errorf("create session: %w", err)
+	}
+	if _, err := tx.Exec(`UPDATE users SET last_login_utc = ? WHERE id = ?`, at.UTC().Format(time.RFC3339Nano), userID); err != nil {
+		return fmt.Errorf("record login: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit login: %w", err)
+	}
+	return nil
+}
+
+func deleteExpiredSessions(tx *sql.Tx, now time.Time) error {
+	rows, err := tx.Query(`SELECT token_hash, expires_utc FROM user_sessions`)
+	if err != nil {
+		return fmt.Errorf("list sessions: %w", err)
+	}
+	var expired []string
+	for rows.Next() {
+		var hash, expires string
+		if err := rows.Scan(&hash, &expires); err != nil {
+			rows.Close()
+			return fmt.Errorf("scan session: %w", err)
+		}
+		if !parseStoredTime(expires).After(now) {
+			expired = append(expired, hash)
+		}
+	}
+	if err := rows.Close(); err != nil {
+		return fmt.Errorf("iterate sessions: %w", err)
+	}
+	for _, hash := range expired {
+		if _, err := tx.Exec(`DELETE FROM user_sessions WHERE token_hash = ?`, hash); err != nil {
+			return fmt.Errorf("delete expired session: %w", err)
+		}
+	}
+	return nil
+}
+
+func (s *Store) FindSessionUser(sessionHash string, now time.Time) (domain.User, bool, error) {
+	var userID int64
+	var expires string
+	err := s.db.QueryRow(`SELECT user_id, expires_utc FROM user_sessions WHERE token_hash = ?`, sessionHash).Scan(&userID, &expires)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.User{}, false, nil
+	}
+	if err != nil {
+		return domain.User{}, false, fmt.Errorf("find session: %w", err)
+	}
+	if !parseStoredTime(expires).After(now) {
+		return domain.User{}, false, nil
+	}
+	return s.GetUser(userID)
+}
+
+func (s *Store) DeleteSession(sessionHash string) error {
+	if _, err := s.db.Exec(`DELETE FROM user_sessions WHERE token_hash = ?`, sessionHash); err != nil {
+		return fmt.Errorf("delete session: %w", err)
+	}
+	return nil
+}
+
+func (s *Store) CreateAPIToken(token domain.APIToken, tokenHash string) (domain.APIToken, error) {
+	created := token.CreatedUTC
+	if created.IsZero() {
+		created = time.Now().UTC()
+	}
+	result, err := s.db.Exec(`
+		INSERT INTO api_tokens (user_id, name, token_prefix, token_hash, created_utc, expires_utc) VALUES (?, ?, ?, ?, ?, ?)
+	`, token.UserID, strings.TrimSpace(token.Name), token.Prefix, tokenHash, created.UTC().Format(time.RFC3339Nano), nullableTime(token.ExpiresUTC))
+	if err != nil {
+		return domain.APIToken{}, fmt.Errorf("create api token: %w", err)
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return domain.APIToken{}, fmt.Errorf("create api token id: %w", err)
+	}
+	token.ID = id
+	token.CreatedUTC = created
+	return token, nil
+}
+
+// FindAPITokenUser resolves an unexpired API token and records its use.
+func (s *Store) FindAPITokenUser(tokenHash string, now time.Time) (domain.User, domain.APIToken, bool, error) {
+	token, err := scanAPIToken(s.db.QueryRow(`SELECT `+apiTokenColumns+` FROM api_tokens WHERE token_hash = ?`, tokenHash))
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.User{}, domain.APIToken{}, false, nil
+	}
+	if err != nil {
+		return domain.User{}, domain.APIToken{}, false, err
+	}
+	if !token.ExpiresUTC.IsZero() && !token.ExpiresUTC.After(now) {
+		return domain.User{}, domain.APIToken{}, false, nil
+	}
+	user, found, err := s.GetUser(token.UserID)
+	if err != nil || !found {
+		return domain.User{}, domain.APIToken{}, false, err
+	}
+	// Only write the last use time once a minute to keep API traffic from
+	// turning into a write per request.
+	if token.LastUsedUTC.IsZero() || now.Sub(token.LastUsedUTC) >= time.Minute {
+		if _, err := s.db.Exec(`UPDATE api_tokens SET last_used_utc = ? WHERE id = ?`, now.UTC().Format(time.RFC3339Nano), token.ID); err != nil {
+			return domain.User{}, domain.APIToken{}, false, fmt.Errorf("record api token use: %w", err)
+		}
+		token.LastUsedUTC = now.UTC()
+	}
+	return user, token, true, nil
+}
+
+func (s *Store) ListAPITokens(userID int64) ([]domain.APIToken, error) {
+	rows, err := s.db.Query(`SELECT `+apiTokenColumns+` FROM api_tokens WHERE user_id = ? ORDER BY id`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("list api tokens: %w", err)
+	}
+	defer rows.Close()
+	var out []domain.APIToken
+	for rows.Next() {
+		token, err := scanAPIToken(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, token)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate api tokens: %w", err)
+	}
+	return out, nil
+}
+
+func (s *Store) DeleteAPIToken(userID, tokenID int64) (bool, error) {
+	result, err := s.db.Exec(`DELETE FROM api_tokens WHERE id = ? AND user_id = ?`, tokenID, userID)
+	if err != nil {
+		return false, fmt.Errorf("delete api token: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("delete api token rows affected: %w", err)
+	}
+	return rows > 0, nil
+}
+
+const apiTokenColumns = `id, user_id, name, token_prefix, created_utc, last_used_utc, expires_utc`
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanUser(row rowScanner) (domain.User, error) {
+	var user domain.User
+	var role, created string
+	var lastLogin sql.NullString
+	if err := row.Scan(&user.ID, &user.Username, &role, &created, &lastLogin); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.User{}, err
+		}
+		return domain.User{}, fmt.Errorf("scan user: %w", err)
+	}
+	user.Role = domain.Role(role)
+	user.CreatedUTC = parseStoredTime(created)
+	user.LastLoginUTC = parseNullableStoredTime(lastLogin)
+	return user, nil
+}
+
+func scanAPIToken(row rowScanner) (domain.APIToken, error) {
+	var token domain.APIToken
+	var created string
+	var lastUsed, expires sql.NullString
+	if err := row.Scan(&token.ID, &token.UserID, &token.Name, &token.Prefix, &created, &lastUsed, &expires); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.APIToken{}, err
+		}
+		return domain.APIToken{}, fmt.Errorf("scan api token: %w", err)
+	}
+	token.CreatedUTC = parseStoredTime(created)
+	token.LastUsedUTC = parseNullableStoredTime(lastUsed)
+	token.ExpiresUTC = parseNullableStoredTime(expires)
+	return token, nil
+}
+
+func parseStoredTime(raw string) time.Time {
+	t, _ := time.Parse(time.RFC3339Nano, raw)
+	return t
+}
+
+func parseNullableStoredTime(raw sql.NullString) time.Time {
+	if !raw.Valid {
+		return time.Time{}
+	}
+	return parseStoredTime(raw.String)
+}
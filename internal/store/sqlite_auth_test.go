@@ -0,0 +1,89 @@
+package store
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/izzyreal/ciwi/internal/domain"
+)
+
+func TestStoreBootstrapsOnlyOneFirstUser(t *testing.T) {
+	s := openTestStore(t)
+	admin, err := s.CreateUser(domain.User{Username: "Alice", Role: domain.RoleAdmin}, "hash-a", true)
+	if err != nil {
+		t.Fatalf("create first user: %v", err)
+	}
+	if admin.ID == 0 || admin.Role != domain.RoleAdmin || admin.CreatedUTC.IsZero() {
+		t.Fatalf("unexpected first user: %+v", admin)
+	}
+	if _, err := s.CreateUser(domain.User{Username: "mallory", Role: domain.RoleAdmin}, "hash-m", true); !errors.Is(err, ErrUsersExist) {
+		t.Fatalf("expected second bootstrap to fail with ErrUsersExist, got %v", err)
+	}
+	if _, err := s.CreateUser(domain.User{Username: "alice", Role: domain.RoleViewer}, "hash-b", false); !errors.Is(err, ErrUsernameTaken) {
+		t.Fatalf("expected case-insensitive duplicate to fail, got %v", err)
+	}
+	user, hash, found, err := s.GetUserCredentials("ALICE")
+	if err != nil || !found || user.ID != admin.ID || hash != "hash-a" {
+		t.Fatalf("credentials lookup: user=%+v hash=%q found=%v err=%v", user, hash, found, err)
+	}
+}
+
+func TestStoreSessionsExpireAndEndOnPasswordChange(t *testing.T) {
+	s := openTestStore(t)
+	user, err := s.CreateUser(domain.User{Username: "bob", Role: domain.RoleOperator}, "hash", true)
+	if err != nil {
+		t.Fatalf("create user: %v", err)
+	}
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	if err := s.RecordLogin(user.ID, "session-1", now, now.Add(time.Hour)); err != nil {
+		t.Fatalf("record login: %v", err)
+	}
+	got, found, err := s.FindSessionUser("session-1", now.Add(30*time.Minute))
+	if err != nil || !found || got.ID != user.ID || !got.LastLoginUTC.Equal(now) {
+		t.Fatalf("find live session: %+v found=%v err=%v", got, found, err)
+	}
+	if _, found, _ := s.FindSessionUser("session-1", now.Add(2*time.Hour)); found {
+		t.Fatal("expected expired session to be rejected")
+	}
+	if _, err := s.UpdateUser(user.ID, domain.RoleOperator, "new-hash"); err != nil {
+		t.Fatalf("update password: %v", err)
+	}
+	if _, found, _ := s.FindSessionUser("session-1", now.Add(time.Minute)); found {
+		t.Fatal("expected password change to end sessions")
+	}
+}
+
+func TestStoreAPITokensAreScopedToTheirUser(t *testing.T) {
+	s := openTestStore(t)
+	owner, err := s.CreateUser(domain.User{Username: "owner", Role: domain.RoleAdmin}, "hash", true)
+	if err != nil {
+		t.Fatalf("create owner: %v", err)
+	}
+	other, err := s.CreateUser(domain.User{Username: "other", Role: domain.RoleViewer}, "hash", false)
+	if err != nil {
+		t.Fatalf("create other: %v", err)
+	}
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	token, err := s.CreateAPIToken(domain.APIToken{UserID: owner.ID, Name: "ci", Prefix: "ciwi_1234", CreatedUTC: now, ExpiresUTC: now.Add(24 * time.Hour)}, "token-hash")
+	if err != nil {
+		t.Fatalf("create token: %v", err)
+	}
+	user, found, foundOK, err := s.FindAPITokenUser("token-hash", now.Add(time.Hour))
+	if err != nil || !foundOK || user.ID != owner.ID || found.ID != token.ID || found.LastUsedUTC.IsZero() {
+		t.Fatalf("find token: user=%+v token=%+v found=%v err=%v", user, found, foundOK, err)
+	}
+	if _, _, ok, _ := s.FindAPITokenUser("token-hash", now.Add(48*time.Hour)); ok {
+		t.Fatal("expected expired token to be rejected")
+	}
+	if deleted, err := s.DeleteAPIToken(other.ID, token.ID); err != nil || deleted {
+		t.Fatalf("expected other user not to revoke token, deleted=%v err=%v", deleted, err)
+	}
+	if err := s.DeleteUser(owner.ID); err != nil {
+		t.Fatalf("delete owner: %v", err)
+	}
+	tokens, err := s.ListAPITokens(owner.ID)
+	if err != nil || len(tokens) != 0 {
+		t.Fatalf("expected tokens to be removed with their user, got %+v err=%v", tokens, err)
+	}
+}
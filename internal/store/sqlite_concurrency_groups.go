@@ -0,0 +1,95 @@
+package store
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/izzyreal/ciwi/internal/config"
+	"github.com/izzyreal/ciwi/internal/protocol"
+)
+
+// ListConcurrencyGroupExecutions returns the executions of every holder of a
+// concurrency group that still has a queued, leased or running execution,
+// oldest first.
+func (s *Store) ListConcurrencyGroupExecutions(group string) ([]protocol.JobExecution, error) {
+	group = strings.TrimSpace(group)
+	if group == "" {
+		return nil, nil
+	}
+	rows, err := s.db.Query(`
+		SELECT id, script, env_json, required_capabilities_json, timeout_seconds, artifact_globs_json, dependency_artifact_job_ids_json, caches_json, services_json, source_repo, source_ref, source_checkout_json, metadata_json, step_plan_json,
+		       status, created_utc, started_utc, finished_utc, leased_by_agent_id, leased_utc, exit_code, error_text, cache_stats_json, runtime_capabilities_json, current_step_text
+		FROM job_executions
+		WHERE TRIM(json_extract(metadata_json, '$.concurrency_group')) = ?
+		  AND COALESCE(NULLIF(TRIM(json_extract(metadata_json, '$.concurrency_holder')), ''), id) IN (
+		    SELECT COALESCE(NULLIF(TRIM(json_extract(metadata_json, '$.concurrency_holder')), ''), id)
+		    FROM job_executions
+		    WHERE TRIM(json_extract(metadata_json, '$.concurrency_group')) = ?
+		      AND status IN (?, ?, ?)
+		  )
+		ORDER BY created_utc ASC, id ASC
+	`, group, group, protocol.JobExecutionStatusQueued, protocol.JobExecutionStatusLeased, protocol.JobExecutionStatusRunning)
+	if err != nil {
+		return nil, fmt.Errorf("list concurrency group jobs: %w", err)
+	}
+	defer rows.Close()
+
+	jobs := []protocol.JobExecution{}
+	for rows.Next() {
+		job, err := scanJobExecution(rows)
+		if err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, job)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate concurrency group jobs: %w", err)
+	}
+	return jobs, nil
+}
+
+// concurrencyGroupCache loads each concurrency group at most once while one
+// lease walks the queue.
+type concurrencyGroupCache struct {
+	store  *Store
+	groups map[string][]protocol.JobExecution
+}
+
+func (c *concurrencyGroupCache) blocked(job protocol.JobExecution) (bool, error) {
+	group, _, ok := job.Metadata.ConcurrencyGroup()
+	if !ok {
+		return false, nil
+	}
+	executions, loaded := c.groups[group]
+	if !loaded {
+		var err error
+		if executions, err = c.store.ListConcurrencyGroupExecutions(group); err != nil {
+			return false, err
+		}
+		if c.groups == nil {
+			c.groups = map[string][]protocol.JobExecution{}
+		}
+		c.groups[group] = executions
+	}
+	return len(protocol.ConcurrencyGroupBlockers(job, executions)) > 0, nil
+}
+
+func encodeConcurrency(concurrency *config.Concurrency) string {
+	if concurrency == nil {
+		return ""
+	}
+	encoded, _ := json.Marshal(concurrency)
+	return string(encoded)
+}
+
+func decodeConcurrency(raw string) *config.Concurrency {
+	if strings.TrimSpace(raw) == "" {
+		return nil
+	}
+	var concurrency config.Concurrency
+	if err := json.Unmarshal([]byte(raw), &concurrency); err != nil || strings.TrimSpace(concurrency.Group) == "" {
+		return nil
+	}
+	return &concurrency
+}
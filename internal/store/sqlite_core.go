@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"strings"
+	"sync"
 	"time"
 
 	_ "modernc.org/sqlite"
@@ -15,6 +16,9 @@ import (
 
 type Store struct {
 	db *sql.DB
+	// leaseMu serializes leases so two agents cannot both take the last slot
+	// of a concurrency group.
+	leaseMu sync.Mutex
 }
 
 type PersistedPipeline struct {
@@ -26,8 +30,11 @@ type PersistedPipeline struct {
 	DependsOn   []string
 	SourceRepo  string
 	SourceRef   string
-	Versioning  config.PipelineVersioning
-	Jobs        []PersistedPipelineJob
+	// SourceCheckout holds the vcs_source checkout options.
+	SourceCheckout config.CheckoutOptions
+	Versioning     config.PipelineVersioning
+	Concurrency    *config.Concurrency
+	Jobs           []PersistedPipelineJob
 }
 
 type PersistedPipelineChain struct {
@@ -38,10 +45,13 @@ type PersistedPipelineChain struct {
 	ChainName   string
 	Pipelines   []string
 	Position    int
+	Schedule    *config.Schedule
 }
 
 type PersistedPipelineJob struct {
 	ID                     string
+	If                     string
+	Retry                  *config.RetryPolicy
 	Needs                  []string
 	ArtifactSources        []config.PipelineJobArtifactSource
 	RunsOn                 map[string]string
@@ -50,7 +60,11 @@ type PersistedPipelineJob struct {
 	TimeoutSeconds         int
 	Artifacts              []string
 	Caches                 []config.PipelineJobCacheSpec
+	Services               []config.PipelineJobServiceSpec
 	MatrixInclude          []map[string]string
+	Shards                 int
+	Priority               int
+	Concurrency            *config.Concurrency
 	Steps                  []config.PipelineJobStep
 	Position               int
 }
@@ -292,13 +306,19 @@ func replaceProjectDefinition(tx *sql.Tx, projectID int64, cfg config.File, now
 			requiresCapsJSON := "{}"
 			artifactsJSON, _ := json.Marshal(j.Artifacts)
 			cachesJSON, _ := json.Marshal(config.EffectivePipelineJobCaches(j))
+			servicesJSON, _ := json.Marshal(j.Services)
 			matrixJSON, _ := json.Marshal(j.Matrix.Include)
 			stepsJSON, _ := json.Marshal(j.Steps)
+			retryJSON := ""
+			if j.Retry != nil {
+				encoded, _ := json.Marshal(j.Retry)
+				retryJSON = string(encoded)
+			}
 
 			if _, err := tx.Exec(`
-				INSERT INTO pipeline_jobs (pipeline_id, job_id, position, needs_json, artifact_sources_json, runs_on_json, requires_tools_json, requires_container_tools_json, requires_capabilities_json, timeout_seconds, artifacts_json, caches_json, matrix_json, steps_json)
-				VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
-			`, pipelineDBID, j.ID, i, string(needsJSON), string(artifactSourcesJSON), string(runsOnJSON), string(requiresToolsJSON), string(requiresContainerToolsJSON), string(requiresCapsJSON), j.TimeoutSeconds, string(artifactsJSON), string(cachesJSON), string(matrixJSON), string(stepsJSON)); err != nil {
+				INSERT INTO pipeline_jobs (pipeline_id, job_id, position, if_expr, retry_json, needs_json, artifact_sources_json, runs_on_json, requires_tools_json, requires_container_tools_json, requires_capabilities_json, timeout_seconds, artifacts_json, caches_json, services_json, matrix_json, shards, priority, concurrency_json, steps_json)
+				VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+			`, pipelineDBID, j.ID, i, strings.TrimSpace(j.If), retryJSON, string(needsJSON), string(artifactSourcesJSON), string(runsOnJSON), string(requiresToolsJSON), string(requiresContainerToolsJSON), string(requiresCapsJSON), j.TimeoutSeconds, string(artifactsJSON), string(cachesJSON), string(servicesJSON), string(matrixJSON), config.EffectivePipelineJobShards(j), config.EffectivePipelineJobPriority(p.Priority, j), encodeConcurrency(j.Concurrency), string(stepsJSON)); err != nil {
 				return fmt.Errorf("insert pipeline job: %w", err)
 			}
 		}
@@ -310,12 +330,15 @@ func replaceProjectDefinition(tx *sql.Tx, projectID int64, cfg config.File, now
 		pipelines := pipelinechain.NormalizePipelines(ch.Pipelines)
 		pipelinesJSON, _ := json.Marshal(pipelines)
 		if _, err := tx.Exec(`
-			INSERT INTO pipeline_chains (project_id, chain_id, chain_name, position, pipelines_json, created_utc, updated_utc)
-			VALUES (?, ?, ?, ?, ?, ?, ?)
-		`, projectID, pipelinechain.ID(pipelines), pipelinechain.DisplayName(ch.Name, pipelines), position, string(pipelinesJSON), now, now); err != nil {
+			INSERT INTO pipeline_chains (project_id, chain_id, chain_name, position, pipelines_json, schedule_json, created_utc, updated_utc)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		`, projectID, pipelinechain.ID(pipelines), pipelinechain.DisplayName(ch.Name, pipelines), position, string(pipelinesJSON), encodeSchedule(ch.Schedule), now, now); err != nil {
 			return fmt.Errorf("insert pipeline chain: %w", err)
 		}
 	}
+	if err := pruneStaleScheduleState(tx, projectID); err != nil {
+		return err
+	}
 
 	return nil
 }
@@ -0,0 +1,112 @@
+package store
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/izzyreal/ciwi/internal/domain"
+	"github.com/izzyreal/ciwi/internal/protocol"
+)
+
+// indexCoverageResult replaces the coverage total of one execution. Only
+// executions of a pipeline job are indexed, like test case results.
+func indexCoverageResult(tx *sql.Tx, jobID string, report protocol.JobExecutionTestReport) error {
+	if _, err := tx.Exec(`DELETE FROM coverage_results WHERE job_execution_id = ?`, jobID); err != nil {
+		return fmt.Errorf("clear coverage result: %w", err)
+	}
+	if report.Coverage == nil {
+		return nil
+	}
+	history, ok, err := jobExecutionHistoryScope(tx, jobID)
+	if err != nil || !ok {
+		return err
+	}
+	coverage := report.Coverage
+	total, covered := coverage.TotalStatements, coverage.CoveredStatements
+	if total == 0 {
+		total, covered = coverage.TotalLines, coverage.CoveredLines
+	}
+	gateFailed := coverage.Gate != nil && !coverage.Gate.Passed
+	scope := history.scope
+	if _, err := tx.Exec(`
+		INSERT INTO coverage_results (job_execution_id, project_id, pipeline_id, pipeline_job_id, matrix_name, source_ref, percent, covered_count, total_count, gate_failed, created_utc)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, jobID, scope.ProjectID, scope.PipelineID, scope.PipelineJobID, scope.MatrixName,
+		history.metadata.Value(domain.ExecutionMetadataPipelineSourceRefRaw),
+		coverage.Percent, covered, total, gateFailed, history.createdUTC); err != nil {
+		return fmt.Errorf("insert coverage result: %w", err)
+	}
+	return nil
+}
+
+// CoverageBaseline returns the coverage of the newest successful execution in
+// scope on sourceRef, other than excludeJobID.
+func (s *Store) CoverageBaseline(scope domain.TestHistoryScope, sourceRef, excludeJobID string) (domain.CoverageRun, bool, error) {
+	rows, err := s.db.Query(`
+		SELECT c.job_execution_id, c.project_id, c.pipeline_id, c.pipeline_job_id, c.matrix_name, c.source_ref, j.status,
+			c.percent, c.covered_count, c.total_count, c.gate_failed, c.created_utc
+		FROM coverage_results c
+		JOIN job_executions j ON j.id = c.job_execution_id
+		WHERE c.project_id = ? AND c.pipeline_id = ? AND c.pipeline_job_id = ? AND c.matrix_name = ?
+		  AND c.source_ref = ? AND c.job_execution_id <> ? AND j.status = ?
+		ORDER BY c.created_utc DESC, c.job_execution_id DESC
+		LIMIT 1
+	`, scope.ProjectID, scope.PipelineID, scope.PipelineJobID, scope.MatrixName,
+		strings.TrimSpace(sourceRef), excludeJobID, protocol.JobExecutionStatusSucceeded)
+	if err != nil {
+		return domain.CoverageRun{}, false, fmt.Errorf("find coverage baseline: %w", err)
+	}
+	runs, err := scanCoverageRuns(rows)
+	if err != nil || len(runs) == 0 {
+		return domain.CoverageRun{}, false, err
+	}
+	return runs[0], true, nil
+}
+
+// ListCoverageRuns returns the newest coverage totals of every pipeline job
+// and matrix entry of a project, at most perScopeLimit per scope, ordered by
+// scope and newest first within it.
+func (s *Store) ListCoverageRuns(projectID int64, perScopeLimit int) ([]domain.CoverageRun, error) {
+	if perScopeLimit <= 0 {
+		perScopeLimit = 20
+	}
+	rows, err := s.db.Query(`
+		SELECT job_execution_id, project_id, pipeline_id, pipeline_job_id, matrix_name, source_ref, status,
+			percent, covered_count, total_count, gate_failed, created_utc
+		FROM (
+			SELECT c.*, j.status AS status, ROW_NUMBER() OVER (
+				PARTITION BY c.pipeline_id, c.pipeline_job_id, c.matrix_name
+				ORDER BY c.created_utc DESC, c.job_execution_id DESC
+			) AS position
+			FROM coverage_results c
+			JOIN job_executions j ON j.id = c.job_execution_id
+			WHERE c.project_id = ?
+		)
+		WHERE position <= ?
+		ORDER BY pipeline_id, pipeline_job_id, matrix_name, position
+	`, projectID, perScopeLimit)
+	if err != nil {
+		return nil, fmt.Errorf("list coverage runs: %w", err)
+	}
+	return scanCoverageRuns(rows)
+}
+
+func scanCoverageRuns(rows *sql.Rows) ([]domain.CoverageRun, error) {
+	defer rows.Close()
+	runs := []domain.CoverageRun{}
+	for rows.Next() {
+		var run domain.CoverageRun
+		var createdUTC string
+		if err := rows.Scan(&run.JobExecutionID, &run.Scope.ProjectID, &run.Scope.PipelineID, &run.Scope.PipelineJobID, &run.Scope.MatrixName,
+			&run.SourceRef, &run.Status, &run.Percent, &run.Covered, &run.Total, &run.GateFailed, &createdUTC); err != nil {
+			return nil, fmt.Errorf("scan coverage run: %w", err)
+		}
+		run.CreatedUTC = parseStoredTime(createdUTC)
+		runs = append(runs, run)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate coverage runs: %w", err)
+	}
+	return runs, nil
+}
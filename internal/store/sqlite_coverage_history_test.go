@@ -0,0 +1,74 @@
+package store
+
+import (
+	"testing"
+
+	"github.com/izzyreal/ciwi/internal/domain"
+	"github.com/izzyreal/ciwi/internal/protocol"
+)
+
+func TestCoverageResultsTrackBaselinesAndTrends(t *testing.T) {
+	s := openTestStore(t)
+	scope := domain.TestHistoryScope{ProjectID: 7, PipelineID: "build", PipelineJobID: "unit", MatrixName: "linux"}
+	createCoveredJob := func(matrix, ref, status string, percent float64) protocol.JobExecution {
+		t.Helper()
+		job, err := s.CreateJobExecution(protocol.CreateJobExecutionRequest{
+			Script: "go test ./...", TimeoutSeconds: 30,
+			Metadata: map[string]string{
+				domain.ExecutionMetadataProjectID:            "7",
+				domain.ExecutionMetadataPipelineID:           "build",
+				domain.ExecutionMetadataPipelineJobID:        "unit",
+				domain.ExecutionMetadataMatrixName:           matrix,
+				domain.ExecutionMetadataPipelineSourceRefRaw: ref,
+			},
+		})
+		if err != nil {
+			t.Fatalf("create job: %v", err)
+		}
+		report := protocol.JobExecutionTestReport{Coverage: &protocol.CoverageReport{
+			Format: "lcov", TotalLines: 200, CoveredLines: int(percent * 2), Percent: percent,
+		}}
+		if err := s.SaveJobExecutionTestReport(job.ID, report); err != nil {
+			t.Fatalf("save report: %v", err)
+		}
+		if status != "" {
+			if _, err := s.UpdateJobExecutionStatus(job.ID, protocol.JobExecutionStatusUpdateRequest{AgentID: "agent-1", Status: status}); err != nil {
+				t.Fatalf("finish job: %v", err)
+			}
+		}
+		return job
+	}
+	baseline := createCoveredJob("linux", "refs/heads/main", protocol.JobExecutionStatusSucceeded, 80)
+	createCoveredJob("linux", "refs/heads/main", protocol.JobExecutionStatusFailed, 60)
+	createCoveredJob("linux", "refs/heads/feature", protocol.JobExecutionStatusSucceeded, 90)
+	createCoveredJob("windows", "refs/heads/main", protocol.JobExecutionStatusSucceeded, 70)
+	current := createCoveredJob("linux", "refs/heads/main", "", 79)
+
+	run, found, err := s.CoverageBaseline(scope, "refs/heads/main", current.ID)
+	if err != nil || !found {
+		t.Fatalf("expected a baseline, got found=%v err=%v", found, err)
+	}
+	if run.JobExecutionID != baseline.ID || run.Percent != 80 || run.Covered != 160 || run.Total != 200 {
+		t.Fatalf("baseline = %+v", run)
+	}
+	if _, found, err := s.CoverageBaseline(scope, "refs/heads/release", current.ID); err != nil || found {
+		t.Fatalf("expected no baseline on another branch, got found=%v err=%v", found, err)
+	}
+
+	runs, err := s.ListCoverageRuns(7, 3)
+	if err != nil {
+		t.Fatalf("list coverage runs: %v", err)
+	}
+	if len(runs) != 4 || runs[0].JobExecutionID != current.ID || runs[0].Status != protocol.JobExecutionStatusQueued || runs[3].Scope.MatrixName != "windows" {
+		t.Fatalf("expected the three newest linux runs and the windows run, got %+v", runs)
+	}
+
+	// A report without coverage drops the execution from the trend.
+	if err := s.SaveJobExecutionTestReport(current.ID, protocol.JobExecutionTestReport{}); err != nil {
+		t.Fatalf("replace report: %v", err)
+	}
+	runs, _ = s.ListCoverageRuns(7, 10)
+	if len(runs) != 4 {
+		t.Fatalf("expected the replaced report to drop its coverage, got %+v", runs)
+	}
+}
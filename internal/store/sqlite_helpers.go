@@ -15,18 +15,18 @@ import (
 
 func scanJobExecution(scanner interface{ Scan(dest ...any) error }) (protocol.JobExecution, error) {
 	var (
-		job                                                                                                                                             protocol.JobExecution
-		envJSON, requiredJSON, artifactGlobsJSON, dependencyArtifactJobIDsJSON, cachesJSON, metadataJSON, stepPlanJSON, cacheStatsJSON, runtimeCapsJSON string
-		sourceRepo, sourceRef                                                                                                                           sql.NullString
-		createdUTC                                                                                                                                      string
-		startedUTC, finishedUTC                                                                                                                         sql.NullString
-		leasedByAgentID, leasedUTC                                                                                                                      sql.NullString
-		exitCode                                                                                                                                        sql.NullInt64
-		errorText, currentStepText                                                                                                                      sql.NullString
+		job                                                                                                                                                           protocol.JobExecution
+		envJSON, requiredJSON, artifactGlobsJSON, dependencyArtifactJobIDsJSON, cachesJSON, servicesJSON, metadataJSON, stepPlanJSON, cacheStatsJSON, runtimeCapsJSON string
+		sourceRepo, sourceRef, sourceCheckoutJSON                                                                                                                     sql.NullString
+		createdUTC                                                                                                                                                    string
+		startedUTC, finishedUTC                                                                                                                                       sql.NullString
+		leasedByAgentID, leasedUTC                                                                                                                                    sql.NullString
+		exitCode                                                                                                                                                      sql.NullInt64
+		errorText, currentStepText                                                                                                                                    sql.NullString
 	)
 
 	if err := scanner.Scan(
-		&job.ID, &job.Script, &envJSON, &requiredJSON, &job.TimeoutSeconds, &artifactGlobsJSON, &dependencyArtifactJobIDsJSON, &cachesJSON, &sourceRepo, &sourceRef, &metadataJSON, &stepPlanJSON,
+		&job.ID, &job.Script, &envJSON, &requiredJSON, &job.TimeoutSeconds, &artifactGlobsJSON, &dependencyArtifactJobIDsJSON, &cachesJSON, &servicesJSON, &sourceRepo, &sourceRef, &sourceCheckoutJSON, &metadataJSON, &stepPlanJSON,
 		&job.Status, &createdUTC, &startedUTC, &finishedUTC, &leasedByAgentID, &leasedUTC, &exitCode, &errorText, &cacheStatsJSON, &runtimeCapsJSON, &currentStepText,
 	); err != nil {
 		return protocol.JobExecution{}, err
@@ -37,13 +37,14 @@ func scanJobExecution(scanner interface{ Scan(dest ...any) error }) (protocol.Jo
 	_ = json.Unmarshal([]byte(artifactGlobsJSON), &job.ArtifactGlobs)
 	_ = json.Unmarshal([]byte(dependencyArtifactJobIDsJSON), &job.DependencyArtifactJobIDs)
 	_ = json.Unmarshal([]byte(cachesJSON), &job.Caches)
+	_ = json.Unmarshal([]byte(servicesJSON), &job.Services)
 	_ = json.Unmarshal([]byte(metadataJSON), &job.Metadata)
 	_ = json.Unmarshal([]byte(stepPlanJSON), &job.StepPlan)
 	_ = json.Unmarshal([]byte(cacheStatsJSON), &job.CacheStats)
 	_ = json.Unmarshal([]byte(runtimeCapsJSON), &job.RuntimeCapabilities)
 
 	if sourceRepo.Valid && sourceRepo.String != "" {
-		job.Source = &protocol.SourceSpec{Repo: sourceRepo.String, Ref: sourceRef.String}
+		job.Source = &protocol.SourceSpec{Repo: sourceRepo.String, Ref: sourceRef.String, SourceCheckoutOptions: decodeSourceCheckoutOptions(sourceCheckoutJSON.String)}
 	}
 	if createdUTC != "" {
 		if t, err := time.Parse(time.RFC3339Nano, createdUTC); err == nil {
@@ -139,6 +140,19 @@ func cloneJobCaches(in []protocol.JobCacheSpec) []protocol.JobCacheSpec {
 	return out
 }
 
+func cloneJobServices(in []protocol.JobServiceSpec) []protocol.JobServiceSpec {
+	if len(in) == 0 {
+		return nil
+	}
+	out := make([]protocol.JobServiceSpec, 0, len(in))
+	for _, svc := range in {
+		svc.Env = cloneMap(svc.Env)
+		svc.Ports = append([]int(nil), svc.Ports...)
+		out = append(out, svc)
+	}
+	return out
+}
+
 func cloneJobCachesFromConfig(in []config.PipelineJobCacheSpec) []protocol.JobCacheSpec {
 	if len(in) == 0 {
 		return nil
@@ -157,7 +171,25 @@ func cloneSource(in *protocol.SourceSpec) *protocol.SourceSpec {
 	if in == nil {
 		return nil
 	}
-	return &protocol.SourceSpec{Repo: in.Repo, Ref: in.Ref}
+	return &protocol.SourceSpec{Repo: in.Repo, Ref: in.Ref, SourceCheckoutOptions: in.SourceCheckoutOptions.Clone()}
+}
+
+// encodeSourceCheckoutOptions stores the default checkout as an empty string,
+// like the rows written before checkout options existed.
+func encodeSourceCheckoutOptions(options protocol.SourceCheckoutOptions) string {
+	if options.IsZero() {
+		return ""
+	}
+	encoded, _ := json.Marshal(options)
+	return string(encoded)
+}
+
+func decodeSourceCheckoutOptions(raw string) protocol.SourceCheckoutOptions {
+	var options protocol.SourceCheckoutOptions
+	if strings.TrimSpace(raw) != "" {
+		_ = json.Unmarshal([]byte(raw), &options)
+	}
+	return options
 }
 
 func cloneJobStepPlan(in []protocol.JobStepPlanItem) []protocol.JobStepPlanItem {
@@ -171,19 +203,24 @@ func cloneJobStepPlan(in []protocol.JobStepPlanItem) []protocol.JobStepPlanItem
 			secrets = append([]protocol.ProjectSecretSpec(nil), step.VaultSecrets...)
 		}
 		out = append(out, protocol.JobStepPlanItem{
-			Index:           step.Index,
-			Total:           step.Total,
-			Name:            step.Name,
-			Script:          step.Script,
-			Kind:            step.Kind,
-			Env:             cloneMap(step.Env),
-			VaultConnection: step.VaultConnection,
-			VaultSecrets:    secrets,
-			TestName:        step.TestName,
-			TestFormat:      step.TestFormat,
-			TestReport:      step.TestReport,
-			CoverageFormat:  step.CoverageFormat,
-			CoverageReport:  step.CoverageReport,
+			Index:               step.Index,
+			Total:               step.Total,
+			Name:                step.Name,
+			Script:              step.Script,
+			Kind:                step.Kind,
+			Env:                 cloneMap(step.Env),
+			VaultConnection:     step.VaultConnection,
+			VaultSecrets:        secrets,
+			TestName:            step.TestName,
+			TestFormat:          step.TestFormat,
+			TestReport:          step.TestReport,
+			CoverageFormat:      step.CoverageFormat,
+			CoverageReport:      step.CoverageReport,
+			CoverageGate:        step.CoverageGate,
+			RunWhen:             step.RunWhen,
+			SkipReason:          step.SkipReason,
+			RetryMaxAttempts:    step.RetryMaxAttempts,
+			RetryBackoffSeconds: step.RetryBackoffSeconds,
 		})
 	}
 	return out
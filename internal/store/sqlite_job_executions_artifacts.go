@@ -78,18 +78,32 @@ func (s *Store) SaveJobExecutionTestReport(jobID string, report protocol.JobExec
 	}
 	now := time.Now().UTC().Format(time.RFC3339Nano)
 	if err := retrySQLiteBusy(func() error {
-		_, err := s.db.Exec(`
-			INSERT INTO job_execution_test_reports (job_execution_id, report_json, total_count, passed_count, failed_count, skipped_count, created_utc)
-			VALUES (?, ?, ?, ?, ?, ?, ?)
+		tx, err := s.db.Begin()
+		if err != nil {
+			return fmt.Errorf("begin tx: %w", err)
+		}
+		defer func() { _ = tx.Rollback() }()
+		if _, err := tx.Exec(`
+			INSERT INTO job_execution_test_reports (job_execution_id, report_json, total_count, passed_count, failed_count, skipped_count, quarantined_count, created_utc)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?)
 			ON CONFLICT(job_execution_id) DO UPDATE SET
 				report_json=excluded.report_json,
 				total_count=excluded.total_count,
 				passed_count=excluded.passed_count,
 				failed_count=excluded.failed_count,
 				skipped_count=excluded.skipped_count,
+				quarantined_count=excluded.quarantined_count,
 				created_utc=excluded.created_utc
-		`, jobID, string(reportJSON), report.Total, report.Passed, report.Failed, report.Skipped, now)
-		return err
+		`, jobID, string(reportJSON), report.Total, report.Passed, report.Failed, report.Skipped, report.Quarantined, now); err != nil {
+			return err
+		}
+		if err := indexTestCaseResults(tx, jobID, report); err != nil {
+			return err
+		}
+		if err := indexCoverageResult(tx, jobID, report); err != nil {
+			return err
+		}
+		return tx.Commit()
 	}); err != nil {
 		return fmt.Errorf("save test report: %w", err)
 	}
@@ -121,7 +135,7 @@ func (s *Store) ListJobExecutionTestSummaries(ctx context.Context, jobIDs []stri
 			arguments[i] = jobID
 		}
 		rows, err := s.db.QueryContext(ctx, `
-			SELECT job_execution_id, total_count, passed_count, failed_count, skipped_count
+			SELECT job_execution_id, total_count, passed_count, failed_count, skipped_count, quarantined_count
 			FROM job_execution_test_reports
 			WHERE job_execution_id IN (`+placeholders+`)
 		`, arguments...)
@@ -131,7 +145,7 @@ func (s *Store) ListJobExecutionTestSummaries(ctx context.Context, jobIDs []stri
 		for rows.Next() {
 			var jobID string
 			var summary protocol.JobExecutionTestSummary
-			if err := rows.Scan(&jobID, &summary.Total, &summary.Passed, &summary.Failed, &summary.Skipped); err != nil {
+			if err := rows.Scan(&jobID, &summary.Total, &summary.Passed, &summary.Failed, &summary.Skipped, &summary.Quarantined); err != nil {
 				_ = rows.Close()
 				return nil, fmt.Errorf("scan test summary: %w", err)
 			}
@@ -73,19 +73,21 @@ func insertJobExecution(tx *sql.Tx, req protocol.CreateJobExecutionRequest, jobI
 	artifactGlobsJSON, _ := json.Marshal(req.ArtifactGlobs)
 	dependencyArtifactJobIDsJSON, _ := json.Marshal(dependencyArtifactJobIDs)
 	cachesJSON, _ := json.Marshal(req.Caches)
+	servicesJSON, _ := json.Marshal(req.Services)
 	metadataJSON, _ := json.Marshal(req.Metadata)
 	stepPlanJSON, _ := json.Marshal(req.StepPlan)
 
-	var sourceRepo, sourceRef string
+	var sourceRepo, sourceRef, sourceCheckoutJSON string
 	if req.Source != nil {
 		sourceRepo = req.Source.Repo
 		sourceRef = req.Source.Ref
+		sourceCheckoutJSON = encodeSourceCheckoutOptions(req.Source.SourceCheckoutOptions)
 	}
 
 	if _, err := tx.Exec(`
-		INSERT INTO job_executions (id, script, env_json, required_capabilities_json, timeout_seconds, artifact_globs_json, dependency_artifact_job_ids_json, caches_json, source_repo, source_ref, metadata_json, step_plan_json, status, created_utc, interactive_log_version)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
-	`, jobID, req.Script, string(envJSON), string(requiredJSON), req.TimeoutSeconds, string(artifactGlobsJSON), string(dependencyArtifactJobIDsJSON), string(cachesJSON), sourceRepo, sourceRef, string(metadataJSON), string(stepPlanJSON), protocol.JobExecutionStatusQueued, now.Format(time.RFC3339Nano), domain.InteractiveJobLogVersion); err != nil {
+		INSERT INTO job_executions (id, script, env_json, required_capabilities_json, timeout_seconds, artifact_globs_json, dependency_artifact_job_ids_json, caches_json, services_json, source_repo, source_ref, source_checkout_json, metadata_json, step_plan_json, status, created_utc, interactive_log_version)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, jobID, req.Script, string(envJSON), string(requiredJSON), req.TimeoutSeconds, string(artifactGlobsJSON), string(dependencyArtifactJobIDsJSON), string(cachesJSON), string(servicesJSON), sourceRepo, sourceRef, sourceCheckoutJSON, string(metadataJSON), string(stepPlanJSON), protocol.JobExecutionStatusQueued, now.Format(time.RFC3339Nano), domain.InteractiveJobLogVersion); err != nil {
 		return protocol.JobExecution{}, err
 	}
 
@@ -98,6 +100,7 @@ func insertJobExecution(tx *sql.Tx, req protocol.CreateJobExecutionRequest, jobI
 		ArtifactGlobs:            append([]string(nil), req.ArtifactGlobs...),
 		DependencyArtifactJobIDs: append([]string(nil), dependencyArtifactJobIDs...),
 		Caches:                   cloneJobCaches(req.Caches),
+		Services:                 cloneJobServices(req.Services),
 		Source:                   cloneSource(req.Source),
 		Metadata:                 cloneMap(req.Metadata),
 		StepPlan:                 cloneJobStepPlan(req.StepPlan),
@@ -112,7 +115,7 @@ func (s *Store) ListJobExecutions() ([]protocol.JobExecution, error) {
 
 func (s *Store) ListJobExecutionsContext(ctx context.Context) ([]protocol.JobExecution, error) {
 	rows, err := s.db.QueryContext(ctx, `
-		SELECT id, script, env_json, required_capabilities_json, timeout_seconds, artifact_globs_json, dependency_artifact_job_ids_json, caches_json, source_repo, source_ref, metadata_json, step_plan_json,
+		SELECT id, script, env_json, required_capabilities_json, timeout_seconds, artifact_globs_json, dependency_artifact_job_ids_json, caches_json, services_json, source_repo, source_ref, source_checkout_json, metadata_json, step_plan_json,
 		       status, created_utc, started_utc, finished_utc, leased_by_agent_id, leased_utc, exit_code, error_text, cache_stats_json, runtime_capabilities_json, current_step_text
 		FROM job_executions
 		ORDER BY created_utc DESC, id DESC
@@ -138,25 +141,48 @@ func (s *Store) ListJobExecutionsContext(ctx context.Context) ([]protocol.JobExe
 
 func (s *Store) GetJobExecution(id string) (protocol.JobExecution, error) {
 	row := s.db.QueryRow(`
-		SELECT id, script, env_json, required_capabilities_json, timeout_seconds, artifact_globs_json, dependency_artifact_job_ids_json, caches_json, source_repo, source_ref, metadata_json, step_plan_json,
+		SELECT id, script, env_json, required_capabilities_json, timeout_seconds, artifact_globs_json, dependency_artifact_job_ids_json, caches_json, services_json, source_repo, source_ref, source_checkout_json, metadata_json, step_plan_json,
 		       status, created_utc, started_utc, finished_utc, leased_by_agent_id, leased_utc, exit_code, error_text, cache_stats_json, runtime_capabilities_json, current_step_text
 		FROM job_executions WHERE id = ?
 	`, id)
 	job, err := scanJobExecution(row)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return protocol.JobExecution{}, fmt.Errorf("job not found")
+			return protocol.JobExecution{}, fmt.Errorf("job not found: %w", domain.ErrJobExecutionNotFound)
 		}
 		return protocol.JobExecution{}, err
 	}
 	return job, nil
 }
 
+// PreferredAgentEligible reports whether job may keep waiting for
+// preferredAgentID, the agent it prefers.
+type PreferredAgentEligible func(preferredAgentID string, job protocol.JobExecution) bool
+
+// LeaseJobExecution leases the first queued execution the agent can run, in
+// the order of protocol.SortQueuedJobExecutions, skipping executions whose
+// concurrency group is full and executions that still wait for the agent they
+// prefer.
 func (s *Store) LeaseJobExecution(agentID string, agentCaps map[string]string) (*protocol.JobExecution, error) {
+	return s.LeaseJobExecutionWithAffinity(agentID, agentCaps, nil)
+}
+
+// LeaseJobExecutionWithAffinity is LeaseJobExecution that only leaves a job
+// to its preferred agent while preferredEligible accepts that agent. A nil
+// preferredEligible accepts every preferred agent.
+func (s *Store) LeaseJobExecutionWithAffinity(agentID string, agentCaps map[string]string, preferredEligible PreferredAgentEligible) (*protocol.JobExecution, error) {
+	s.leaseMu.Lock()
+	defer s.leaseMu.Unlock()
 	jobs, err := s.ListQueuedJobExecutions()
 	if err != nil {
 		return nil, err
 	}
+	activeByProject, err := s.CountActiveJobExecutionsByProject()
+	if err != nil {
+		return nil, err
+	}
+	protocol.SortQueuedJobExecutions(jobs, activeByProject)
+	groups := concurrencyGroupCache{store: s}
 
 	for _, job := range jobs {
 		if job.Metadata.Flag(domain.ExecutionMetadataChainBlocked) {
@@ -165,6 +191,9 @@ func (s *Store) LeaseJobExecution(agentID string, agentCaps map[string]string) (
 		if job.Metadata.Flag(domain.ExecutionMetadataNeedsBlocked) {
 			continue
 		}
+		if job.Metadata.Value(domain.ExecutionMetadataConditionSkipped) != "" {
+			continue
+		}
 		if job.Metadata.Flag(protocol.JobSchedulingBlockedMetadataKey) {
 			retryUTC, parseErr := time.Parse(time.RFC3339Nano, job.Metadata.Value(protocol.JobSchedulingRetryUTCMetadataKey))
 			if parseErr != nil || time.Now().UTC().Before(retryUTC) {
@@ -174,6 +203,20 @@ func (s *Store) LeaseJobExecution(agentID string, agentCaps map[string]string) (
 		if !capabilitiesMatch(agentCaps, job.RequiredCapabilities) {
 			continue
 		}
+		blocked, err := groups.blocked(job)
+		if err != nil {
+			return nil, err
+		}
+		if blocked {
+			continue
+		}
+		held, err := s.holdForPreferredAgent(job, agentID, preferredEligible)
+		if err != nil {
+			return nil, err
+		}
+		if held {
+			continue
+		}
 
 		now := time.Now().UTC().Format(time.RFC3339Nano)
 		res, err := s.db.Exec(`
@@ -198,47 +241,105 @@ func (s *Store) LeaseJobExecution(agentID string, agentCaps map[string]string) (
 	return nil, nil
 }
 
+// holdForPreferredAgent reports whether agentID has to leave job to the agent
+// it prefers. The first such refusal starts the wait; a preferred agent that
+// is not eligible is not waited for.
+func (s *Store) holdForPreferredAgent(job protocol.JobExecution, agentID string, preferredEligible PreferredAgentEligible) (bool, error) {
+	preferred, until, started := protocol.AgentAffinityWait(job)
+	if preferred == "" || preferred == strings.TrimSpace(agentID) {
+		return false, nil
+	}
+	if preferredEligible != nil && !preferredEligible(preferred, job) {
+		return false, nil
+	}
+	now := time.Now().UTC()
+	if !started {
+		if _, err := s.MergeJobExecutionMetadata(job.ID, map[string]string{
+			domain.ExecutionMetadataAffinityWaitingSinceUTC: now.Format(time.RFC3339Nano),
+		}); err != nil {
+			return false, fmt.Errorf("start preferred agent wait: %w", err)
+		}
+		return true, nil
+	}
+	return now.Before(until), nil
+}
+
+// LastSucceededAgentForAffinityKey returns the agent that most recently ran
+// an execution of the affinity key successfully, or "" when none did.
+func (s *Store) LastSucceededAgentForAffinityKey(key string) (string, error) {
+	key = strings.TrimSpace(key)
+	if key == "" {
+		return "", nil
+	}
+	var agentID string
+	err := s.db.QueryRow(`
+		SELECT TRIM(leased_by_agent_id)
+		FROM job_executions
+		WHERE json_extract(metadata_json, '$.affinity_key') = ?
+		  AND status = ?
+		  AND TRIM(COALESCE(leased_by_agent_id, '')) <> ''
+		ORDER BY finished_utc DESC, id DESC
+		LIMIT 1
+	`, key, protocol.JobExecutionStatusSucceeded).Scan(&agentID)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("find last agent for affinity key: %w", err)
+	}
+	return agentID, nil
+}
+
 func (s *Store) AgentHasActiveJobExecution(agentID string) (bool, error) {
+	count, err := s.CountAgentActiveJobExecutions(agentID)
+	return count > 0, err
+}
+
+// CountAgentActiveJobExecutions returns how many of the agent's job slots
+// are occupied by leased or running jobs.
+func (s *Store) CountAgentActiveJobExecutions(agentID string) (int, error) {
 	agentID = strings.TrimSpace(agentID)
 	if agentID == "" {
-		return false, fmt.Errorf("agent id is required")
+		return 0, fmt.Errorf("agent id is required")
 	}
-	var count int64
+	var count int
 	if err := s.db.QueryRow(`
 		SELECT COUNT(1)
 		FROM job_executions
 		WHERE leased_by_agent_id = ?
 		  AND status IN (?, ?)
 	`, agentID, protocol.JobExecutionStatusLeased, protocol.JobExecutionStatusRunning).Scan(&count); err != nil {
-		return false, fmt.Errorf("check active jobs for agent: %w", err)
+		return 0, fmt.Errorf("check active jobs for agent: %w", err)
 	}
-	return count > 0, nil
+	return count, nil
 }
 
-func (s *Store) ListActiveJobExecutionAgentIDs() (map[string]bool, error) {
-	return s.ListActiveJobExecutionAgentIDsContext(context.Background())
+func (s *Store) CountActiveJobExecutionsByAgent() (map[string]int, error) {
+	return s.CountActiveJobExecutionsByAgentContext(context.Background())
 }
 
-func (s *Store) ListActiveJobExecutionAgentIDsContext(ctx context.Context) (map[string]bool, error) {
+func (s *Store) CountActiveJobExecutionsByAgentContext(ctx context.Context) (map[string]int, error) {
 	rows, err := s.db.QueryContext(ctx, `
-		SELECT DISTINCT leased_by_agent_id
+		SELECT leased_by_agent_id, COUNT(1)
 		FROM job_executions
 		WHERE status IN (?, ?)
 		  AND leased_by_agent_id IS NOT NULL
 		  AND TRIM(leased_by_agent_id) <> ''
+		GROUP BY leased_by_agent_id
 	`, protocol.JobExecutionStatusLeased, protocol.JobExecutionStatusRunning)
 	if err != nil {
 		return nil, fmt.Errorf("list agents with active jobs: %w", err)
 	}
 	defer rows.Close()
-	result := map[string]bool{}
+	result := map[string]int{}
 	for rows.Next() {
 		var agentID string
-		if err := rows.Scan(&agentID); err != nil {
+		var count int
+		if err := rows.Scan(&agentID, &count); err != nil {
 			return nil, fmt.Errorf("scan agent with active job: %w", err)
 		}
 		if agentID = strings.TrimSpace(agentID); agentID != "" {
-			result[agentID] = true
+			result[agentID] += count
 		}
 	}
 	if err := rows.Err(); err != nil {
@@ -247,9 +348,37 @@ func (s *Store) ListActiveJobExecutionAgentIDsContext(ctx context.Context) (map[
 	return result, nil
 }
 
+// CountActiveJobExecutionsByProject returns how many leased or running
+// executions each project has, keyed by protocol.JobExecutionProjectKey.
+func (s *Store) CountActiveJobExecutionsByProject() (map[string]int, error) {
+	rows, err := s.db.Query(`
+		SELECT COALESCE(TRIM(json_extract(metadata_json, '$.project_id')), ''), COUNT(1)
+		FROM job_executions
+		WHERE status IN (?, ?)
+		GROUP BY 1
+	`, protocol.JobExecutionStatusLeased, protocol.JobExecutionStatusRunning)
+	if err != nil {
+		return nil, fmt.Errorf("count active jobs by project: %w", err)
+	}
+	defer rows.Close()
+	result := map[string]int{}
+	for rows.Next() {
+		var projectID string
+		var count int
+		if err := rows.Scan(&projectID, &count); err != nil {
+			return nil, fmt.Errorf("scan active jobs by project: %w", err)
+		}
+		result[projectID] += count
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate active jobs by project: %w", err)
+	}
+	return result, nil
+}
+
 func (s *Store) ListQueuedJobExecutions() ([]protocol.JobExecution, error) {
 	rows, err := s.db.Query(`
-		SELECT id, script, env_json, required_capabilities_json, timeout_seconds, artifact_globs_json, dependency_artifact_job_ids_json, caches_json, source_repo, source_ref, metadata_json, step_plan_json,
+		SELECT id, script, env_json, required_capabilities_json, timeout_seconds, artifact_globs_json, dependency_artifact_job_ids_json, caches_json, services_json, source_repo, source_ref, source_checkout_json, metadata_json, step_plan_json,
 		       status, created_utc, started_utc, finished_utc, leased_by_agent_id, leased_utc, exit_code, error_text, cache_stats_json, runtime_capabilities_json, current_step_text
 		FROM job_executions WHERE status = ?
 		ORDER BY created_utc ASC, id ASC
@@ -272,3 +401,110 @@ func (s *Store) ListQueuedJobExecutions() ([]protocol.JobExecution, error) {
 	}
 	return jobs, nil
 }
+
+// ListAgentActiveJobExecutions returns the leased and running executions of
+// one agent.
+func (s *Store) ListAgentActiveJobExecutions(agentID string) ([]protocol.JobExecution, error) {
+	agentID = strings.TrimSpace(agentID)
+	if agentID == "" {
+		return nil, fmt.Errorf("agent id is required")
+	}
+	rows, err := s.db.Query(`
+		SELECT id, script, env_json, required_capabilities_json, timeout_seconds, artifact_globs_json, dependency_artifact_job_ids_json, caches_json, services_json, source_repo, source_ref, source_checkout_json, metadata_json, step_plan_json,
+		       status, created_utc, started_utc, finished_utc, leased_by_agent_id, leased_utc, exit_code, error_text, cache_stats_json, runtime_capabilities_json, current_step_text
+		FROM job_executions
+		WHERE leased_by_agent_id = ?
+		  AND status IN (?, ?)
+		ORDER BY created_utc ASC, id ASC
+	`, agentID, protocol.JobExecutionStatusLeased, protocol.JobExecutionStatusRunning)
+	if err != nil {
+		return nil, fmt.Errorf("list active jobs for agent: %w", err)
+	}
+	defer rows.Close()
+
+	jobs := []protocol.JobExecution{}
+	for rows.Next() {
+		job, err := scanJobExecution(rows)
+		if err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, job)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate active jobs for agent: %w", err)
+	}
+	return jobs, nil
+}
+
+// ListRetryPendingJobExecutions returns the failed executions whose retry
+// policy allows another attempt and that are still the latest attempt of
+// their job. Whether the failure kind qualifies is left to the caller.
+func (s *Store) ListRetryPendingJobExecutions() ([]protocol.JobExecution, error) {
+	rows, err := s.db.Query(`
+		SELECT id, script, env_json, required_capabilities_json, timeout_seconds, artifact_globs_json, dependency_artifact_job_ids_json, caches_json, services_json, source_repo, source_ref, source_checkout_json, metadata_json, step_plan_json,
+		       status, created_utc, started_utc, finished_utc, leased_by_agent_id, leased_utc, exit_code, error_text, cache_stats_json, runtime_capabilities_json, current_step_text
+		FROM job_executions AS failed
+		WHERE status = ?
+		  AND CAST(json_extract(metadata_json, '$.retry_max_attempts') AS INTEGER) >
+		      MAX(COALESCE(CAST(json_extract(metadata_json, '$.retry_attempt') AS INTEGER), 1), 1)
+		  AND NOT EXISTS (
+		    SELECT 1
+		    FROM job_executions AS later
+		    WHERE COALESCE(NULLIF(TRIM(json_extract(later.metadata_json, '$.attempt_root_job_id')), ''), later.id) =
+		          COALESCE(NULLIF(TRIM(json_extract(failed.metadata_json, '$.attempt_root_job_id')), ''), failed.id)
+		      AND (later.created_utc > failed.created_utc OR (later.created_utc = failed.created_utc AND later.id > failed.id))
+		  )
+		ORDER BY created_utc DESC, id DESC
+	`, protocol.JobExecutionStatusFailed)
+	if err != nil {
+		return nil, fmt.Errorf("list jobs pending retry: %w", err)
+	}
+	defer rows.Close()
+
+	jobs := []protocol.JobExecution{}
+	for rows.Next() {
+		job, err := scanJobExecution(rows)
+		if err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, job)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate jobs pending retry: %w", err)
+	}
+	return jobs, nil
+}
+
+// ListPipelineJobExecutionsContext returns every execution of one job of a
+// pipeline run, including its matrix entries, shards and reruns.
+func (s *Store) ListPipelineJobExecutionsContext(ctx context.Context, pipelineRunID, pipelineJobID string) ([]protocol.JobExecution, error) {
+	pipelineRunID, pipelineJobID = strings.TrimSpace(pipelineRunID), strings.TrimSpace(pipelineJobID)
+	if pipelineRunID == "" || pipelineJobID == "" {
+		return nil, nil
+	}
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT id, script, env_json, required_capabilities_json, timeout_seconds, artifact_globs_json, dependency_artifact_job_ids_json, caches_json, services_json, source_repo, source_ref, source_checkout_json, metadata_json, step_plan_json,
+		       status, created_utc, started_utc, finished_utc, leased_by_agent_id, leased_utc, exit_code, error_text, cache_stats_json, runtime_capabilities_json, current_step_text
+		FROM job_executions
+		WHERE TRIM(json_extract(metadata_json, '$.pipeline_run_id')) = ?
+		  AND TRIM(json_extract(metadata_json, '$.pipeline_job_id')) = ?
+		ORDER BY created_utc ASC, id ASC
+	`, pipelineRunID, pipelineJobID)
+	if err != nil {
+		return nil, fmt.Errorf("list pipeline job executions: %w", err)
+	}
+	defer rows.Close()
+
+	jobs := []protocol.JobExecution{}
+	for rows.Next() {
+		job, err := scanJobExecution(rows)
+		if err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, job)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate pipeline job executions: %w", err)
+	}
+	return jobs, nil
+}
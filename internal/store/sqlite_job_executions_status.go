@@ -79,7 +79,8 @@ func (s *Store) UpdateJobExecutionStatus(jobID string, req protocol.JobExecution
 	}
 
 	if protocol.IsTerminalJobExecutionStatus(status) {
-		if job.StartedUTC.IsZero() {
+		// Skipped jobs never ran, so they keep an empty start time.
+		if job.StartedUTC.IsZero() && status != protocol.JobExecutionStatusSkipped {
 			started = sql.NullString{String: now.Format(time.RFC3339Nano), Valid: true}
 		}
 		finished = sql.NullString{String: now.Format(time.RFC3339Nano), Valid: true}
@@ -90,17 +91,15 @@ func (s *Store) UpdateJobExecutionStatus(jobID string, req protocol.JobExecution
 	}
 
 	where := "id = ?"
-	args := []any{status, nullStringValue(started), nullStringValue(finished), nullIntValue(exitCode), errorText, cacheStatsJSON, runtimeCapsJSON, currentStep}
-	if status == protocol.JobExecutionStatusRunning {
-		// Never allow a running heartbeat/log-stream update to overwrite a terminal state.
-		where = "id = ? AND status NOT IN (?, ?)"
-		args = append(args, jobID, protocol.JobExecutionStatusSucceeded, protocol.JobExecutionStatusFailed)
-	} else if protocol.IsTerminalJobExecutionStatus(status) {
-		// First terminal status wins under races; later terminal writes become no-ops.
-		where = "id = ? AND status NOT IN (?, ?)"
-		args = append(args, jobID, protocol.JobExecutionStatusSucceeded, protocol.JobExecutionStatusFailed)
-	} else {
-		args = append(args, jobID)
+	args := []any{status, nullStringValue(started), nullStringValue(finished), nullIntValue(exitCode), errorText, cacheStatsJSON, runtimeCapsJSON, currentStep, jobID}
+	if status == protocol.JobExecutionStatusRunning || protocol.IsTerminalJobExecutionStatus(status) {
+		// Never allow a running heartbeat/log-stream update to overwrite a
+		// terminal state, and let the first terminal status win under races.
+		terminal := protocol.TerminalJobExecutionStatuses()
+		where = "id = ? AND status NOT IN (" + strings.TrimSuffix(strings.Repeat("?, ", len(terminal)), ", ") + ")"
+		for _, terminalStatus := range terminal {
+			args = append(args, terminalStatus)
+		}
 	}
 
 	var res sql.Result
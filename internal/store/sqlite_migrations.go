@@ -2,11 +2,14 @@ package store
 
 import (
 	"database/sql"
+	"encoding/json"
 	"fmt"
 	"time"
+
+	"github.com/izzyreal/ciwi/internal/protocol"
 )
 
-const currentSchemaVersion = 3
+const currentSchemaVersion = 20
 
 type schemaMigration struct {
 	version int
@@ -30,6 +33,408 @@ var schemaMigrations = []schemaMigration{
 		name:    "add indexed interactive job logs",
 		apply:   migrateInteractiveJobLogs,
 	},
+	{
+		version: 4,
+		name:    "add vcs trigger poll state",
+		apply:   migrateVCSTriggerPollState,
+	},
+	{
+		version: 5,
+		name:    "add project webhook secrets",
+		apply:   migrateProjectWebhookSecrets,
+	},
+	{
+		version: 6,
+		name:    "add schedule triggers",
+		apply:   migrateScheduleTriggers,
+	},
+	{
+		version: 7,
+		name:    "add users, sessions and api tokens",
+		apply:   migrateUserAccounts,
+	},
+	{
+		version: 8,
+		name:    "add agent enrollment tokens and credentials",
+		apply:   migrateAgentCredentials,
+	},
+	{
+		version: 9,
+		name:    "reclassify cancelled and skipped job executions",
+		apply:   migrateCancelledAndSkippedStatuses,
+	},
+	{
+		version: 10,
+		name:    "add pipeline job conditions",
+		apply:   migratePipelineJobConditions,
+	},
+	{
+		version: 11,
+		name:    "add pipeline job retry policies",
+		apply:   migratePipelineJobRetryPolicies,
+	},
+	{
+		version: 12,
+		name:    "index test case results for test history",
+		apply:   migrateTestCaseHistory,
+	},
+	{
+		version: 13,
+		name:    "add test quarantine",
+		apply:   migrateTestQuarantine,
+	},
+	{
+		version: 14,
+		name:    "index coverage results for coverage trends",
+		apply:   migrateCoverageHistory,
+	},
+	{
+		version: 15,
+		name:    "add pipeline job shards",
+		apply:   migratePipelineJobShards,
+	},
+	{
+		version: 16,
+		name:    "add pipeline job priorities",
+		apply:   migratePipelineJobPriority,
+	},
+	{
+		version: 17,
+		name:    "add concurrency groups",
+		apply:   migrateConcurrencyGroups,
+	},
+	{
+		version: 18,
+		name:    "add source checkout options",
+		apply:   migrateSourceCheckoutOptions,
+	},
+	{
+		version: 19,
+		name:    "add repository credentials",
+		apply:   migrateRepoCredentials,
+	},
+	{
+		version: 20,
+		name:    "add job services",
+		apply:   migrateJobServices,
+	},
+}
+
+func migratePipelineJobShards(tx *sql.Tx) error {
+	return addColumnIfMissing(tx, "pipeline_jobs", "shards", "INTEGER NOT NULL DEFAULT 0")
+}
+
+func migratePipelineJobPriority(tx *sql.Tx) error {
+	return addColumnIfMissing(tx, "pipeline_jobs", "priority", "INTEGER NOT NULL DEFAULT 0")
+}
+
+func migrateConcurrencyGroups(tx *sql.Tx) error {
+	if err := addColumnIfMissing(tx, "pipelines", "concurrency_json", "TEXT NOT NULL DEFAULT ''"); err != nil {
+		return err
+	}
+	return addColumnIfMissing(tx, "pipeline_jobs", "concurrency_json", "TEXT NOT NULL DEFAULT ''")
+}
+
+func migrateSourceCheckoutOptions(tx *sql.Tx) error {
+	if err := addColumnIfMissing(tx, "pipelines", "source_checkout_json", "TEXT NOT NULL DEFAULT ''"); err != nil {
+		return err
+	}
+	return addColumnIfMissing(tx, "job_executions", "source_checkout_json", "TEXT NOT NULL DEFAULT ''")
+}
+
+func migrateRepoCredentials(tx *sql.Tx) error {
+	if _, err := tx.Exec(`CREATE TABLE IF NOT EXISTS repo_credentials (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		name TEXT NOT NULL UNIQUE,
+		url_prefix TEXT NOT NULL,
+		kind TEXT NOT NULL,
+		username TEXT NOT NULL DEFAULT '',
+		secret_env TEXT NOT NULL DEFAULT '',
+		vault_connection TEXT NOT NULL DEFAULT '',
+		vault_secret_json TEXT NOT NULL DEFAULT '',
+		known_hosts TEXT NOT NULL DEFAULT '',
+		created_utc TEXT NOT NULL,
+		updated_utc TEXT NOT NULL
+	)`); err != nil {
+		return fmt.Errorf("create repo credentials table: %w", err)
+	}
+	return nil
+}
+
+func migrateJobServices(tx *sql.Tx) error {
+	if err := addColumnIfMissing(tx, "pipeline_jobs", "services_json", "TEXT NOT NULL DEFAULT ''"); err != nil {
+		return err
+	}
+	return addColumnIfMissing(tx, "job_executions", "services_json", "TEXT NOT NULL DEFAULT ''")
+}
+
+// migrateCoverageHistory creates the per-run coverage totals behind coverage
+// trends and gates and fills them from the reports stored before.
+func migrateCoverageHistory(tx *sql.Tx) error {
+	for _, statement := range []string{
+		`CREATE TABLE IF NOT EXISTS coverage_results (
+			job_execution_id TEXT PRIMARY KEY,
+			project_id INTEGER NOT NULL,
+			pipeline_id TEXT NOT NULL,
+			pipeline_job_id TEXT NOT NULL,
+			matrix_name TEXT NOT NULL DEFAULT '',
+			source_ref TEXT NOT NULL DEFAULT '',
+			percent REAL NOT NULL,
+			covered_count INTEGER NOT NULL DEFAULT 0,
+			total_count INTEGER NOT NULL DEFAULT 0,
+			gate_failed INTEGER NOT NULL DEFAULT 0,
+			created_utc TEXT NOT NULL,
+			FOREIGN KEY(job_execution_id) REFERENCES job_executions(id) ON DELETE CASCADE
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_coverage_results_scope ON coverage_results(project_id, pipeline_id, pipeline_job_id, matrix_name, created_utc)`,
+	} {
+		if _, err := tx.Exec(statement); err != nil {
+			return fmt.Errorf("create coverage history table: %w", err)
+		}
+	}
+	reports, err := listStoredTestReports(tx)
+	if err != nil {
+		return err
+	}
+	for jobID, report := range reports {
+		if err := indexCoverageResult(tx, jobID, report); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func migrateTestQuarantine(tx *sql.Tx) error {
+	if err := addColumnIfMissing(tx, "job_execution_test_reports", "quarantined_count", "INTEGER NOT NULL DEFAULT 0"); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(`CREATE TABLE IF NOT EXISTS test_quarantine (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		project_id INTEGER NOT NULL,
+		package TEXT NOT NULL DEFAULT '',
+		name TEXT NOT NULL,
+		owner TEXT NOT NULL,
+		reason TEXT NOT NULL DEFAULT '',
+		created_by TEXT NOT NULL DEFAULT '',
+		created_utc TEXT NOT NULL,
+		expires_utc TEXT NOT NULL,
+		FOREIGN KEY(project_id) REFERENCES projects(id) ON DELETE CASCADE
+	)`); err != nil {
+		return fmt.Errorf("create test quarantine table: %w", err)
+	}
+	if _, err := tx.Exec(`CREATE INDEX IF NOT EXISTS idx_test_quarantine_project ON test_quarantine(project_id)`); err != nil {
+		return fmt.Errorf("create test quarantine index: %w", err)
+	}
+	return nil
+}
+
+// migrateTestCaseHistory creates the per-case index behind test history and
+// fills it from the reports that were stored before it existed.
+func migrateTestCaseHistory(tx *sql.Tx) error {
+	for _, statement := range []string{
+		`CREATE TABLE IF NOT EXISTS test_case_results (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			job_execution_id TEXT NOT NULL,
+			project_id INTEGER NOT NULL,
+			pipeline_id TEXT NOT NULL,
+			pipeline_job_id TEXT NOT NULL,
+			matrix_name TEXT NOT NULL DEFAULT '',
+			attempt_root_job_id TEXT NOT NULL,
+			source_commit TEXT NOT NULL DEFAULT '',
+			package TEXT NOT NULL DEFAULT '',
+			name TEXT NOT NULL,
+			status TEXT NOT NULL,
+			duration_seconds REAL NOT NULL DEFAULT 0,
+			created_utc TEXT NOT NULL,
+			FOREIGN KEY(job_execution_id) REFERENCES job_executions(id) ON DELETE CASCADE
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_test_case_results_scope ON test_case_results(project_id, pipeline_id, pipeline_job_id, matrix_name, created_utc)`,
+		`CREATE INDEX IF NOT EXISTS idx_test_case_results_job ON test_case_results(job_execution_id)`,
+	} {
+		if _, err := tx.Exec(statement); err != nil {
+			return fmt.Errorf("create test case history table: %w", err)
+		}
+	}
+	reports, err := listStoredTestReports(tx)
+	if err != nil {
+		return err
+	}
+	for jobID, report := range reports {
+		if err := indexTestCaseResults(tx, jobID, report); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// listStoredTestReports decodes every stored test report for migrations
+// that index them. Reports that no longer decode are skipped.
+func listStoredTestReports(tx *sql.Tx) (map[string]protocol.JobExecutionTestReport, error) {
+	rows, err := tx.Query(`SELECT job_execution_id, report_json FROM job_execution_test_reports`)
+	if err != nil {
+		return nil, fmt.Errorf("list stored test reports: %w", err)
+	}
+	reports := map[string]protocol.JobExecutionTestReport{}
+	for rows.Next() {
+		var jobID, reportJSON string
+		if err := rows.Scan(&jobID, &reportJSON); err != nil {
+			_ = rows.Close()
+			return nil, fmt.Errorf("scan stored test report: %w", err)
+		}
+		var report protocol.JobExecutionTestReport
+		if json.Unmarshal([]byte(reportJSON), &report) == nil {
+			reports[jobID] = report
+		}
+	}
+	if err := rows.Close(); err != nil {
+		return nil, fmt.Errorf("close stored test reports: %w", err)
+	}
+	return reports, nil
+}
+
+func migratePipelineJobRetryPolicies(tx *sql.Tx) error {
+	return addColumnIfMissing(tx, "pipeline_jobs", "retry_json", "TEXT NOT NULL DEFAULT ''")
+}
+
+func migratePipelineJobConditions(tx *sql.Tx) error {
+	return addColumnIfMissing(tx, "pipeline_jobs", "if_expr", "TEXT NOT NULL DEFAULT ''")
+}
+
+// migrateCancelledAndSkippedStatuses moves executions that were recorded as
+// failed before cancelled and skipped existed. Jobs that never ran because an
+// upstream job or pipeline failed carry a "cancelled: " reason or the
+// chain_cancelled flag; explicit cancellations say who cancelled them.
+func migrateCancelledAndSkippedStatuses(tx *sql.Tx) error {
+	if _, err := tx.Exec(`
+		UPDATE job_executions SET status = 'skipped'
+		WHERE status = 'failed'
+		  AND (error_text LIKE 'cancelled: %' OR json_extract(metadata_json, '$.chain_cancelled') = '1')
+	`); err != nil {
+		return fmt.Errorf("reclassify skipped job executions: %w", err)
+	}
+	if _, err := tx.Exec(`
+		UPDATE job_executions SET status = 'cancelled'
+		WHERE status = 'failed'
+		  AND (error_text = 'cancelled' OR error_text LIKE 'cancelled by %' OR COALESCE(json_extract(metadata_json, '$.cancelled_by'), '') <> '')
+	`); err != nil {
+		return fmt.Errorf("reclassify cancelled job executions: %w", err)
+	}
+	return nil
+}
+
+func migrateUserAccounts(tx *sql.Tx) error {
+	for _, statement := range []string{
+		`CREATE TABLE IF NOT EXISTS users (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			username TEXT NOT NULL UNIQUE COLLATE NOCASE,
+			role TEXT NOT NULL,
+			password_hash TEXT NOT NULL,
+			created_utc TEXT NOT NULL,
+			last_login_utc TEXT
+		)`,
+		`CREATE TABLE IF NOT EXISTS user_sessions (
+			token_hash TEXT PRIMARY KEY,
+			user_id INTEGER NOT NULL,
+			created_utc TEXT NOT NULL,
+			expires_utc TEXT NOT NULL,
+			FOREIGN KEY(user_id) REFERENCES users(id) ON DELETE CASCADE
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_user_sessions_user ON user_sessions(user_id)`,
+		`CREATE TABLE IF NOT EXISTS api_tokens (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			user_id INTEGER NOT NULL,
+			name TEXT NOT NULL,
+			token_prefix TEXT NOT NULL,
+			token_hash TEXT NOT NULL UNIQUE,
+			created_utc TEXT NOT NULL,
+			last_used_utc TEXT,
+			expires_utc TEXT,
+			FOREIGN KEY(user_id) REFERENCES users(id) ON DELETE CASCADE
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_api_tokens_user ON api_tokens(user_id)`,
+	} {
+		if _, err := tx.Exec(statement); err != nil {
+			return fmt.Errorf("create user account tables: %w", err)
+		}
+	}
+	return nil
+}
+
+func migrateAgentCredentials(tx *sql.Tx) error {
+	for _, statement := range []string{
+		`CREATE TABLE IF NOT EXISTS agent_enrollment_tokens (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			description TEXT NOT NULL DEFAULT '',
+			token_prefix TEXT NOT NULL,
+			token_hash TEXT NOT NULL UNIQUE,
+			created_by TEXT NOT NULL DEFAULT '',
+			created_utc TEXT NOT NULL,
+			expires_utc TEXT NOT NULL,
+			used_utc TEXT,
+			used_by_agent_id TEXT NOT NULL DEFAULT ''
+		)`,
+		// secret_hash is cleared on revocation; the row stays so the agent
+		// ID keeps requiring a credential.
+		`CREATE TABLE IF NOT EXISTS agent_credentials (
+			agent_id TEXT PRIMARY KEY,
+			secret_hash TEXT,
+			created_utc TEXT NOT NULL,
+			revoked_utc TEXT
+		)`,
+	} {
+		if _, err := tx.Exec(statement); err != nil {
+			return fmt.Errorf("create agent credential tables: %w", err)
+		}
+	}
+	return nil
+}
+
+func migrateScheduleTriggers(tx *sql.Tx) error {
+	if err := addColumnIfMissing(tx, "pipelines", "schedule_json", "TEXT NOT NULL DEFAULT ''"); err != nil {
+		return err
+	}
+	if err := addColumnIfMissing(tx, "pipeline_chains", "schedule_json", "TEXT NOT NULL DEFAULT ''"); err != nil {
+		return err
+	}
+	// Chains are re-inserted on every project reload, so state is keyed by
+	// the stable pipeline or chain id rather than a row id.
+	if _, err := tx.Exec(`CREATE TABLE IF NOT EXISTS schedule_trigger_state (
+		project_id INTEGER NOT NULL,
+		target_kind TEXT NOT NULL,
+		target_id TEXT NOT NULL,
+		last_checked_utc TEXT,
+		last_fire_utc TEXT,
+		last_error TEXT NOT NULL DEFAULT '',
+		PRIMARY KEY(project_id, target_kind, target_id),
+		FOREIGN KEY(project_id) REFERENCES projects(id) ON DELETE CASCADE
+	)`); err != nil {
+		return fmt.Errorf("create schedule trigger state table: %w", err)
+	}
+	return nil
+}
+
+func migrateProjectWebhookSecrets(tx *sql.Tx) error {
+	return addColumnIfMissing(tx, "projects", "webhook_secret", "TEXT NOT NULL DEFAULT ''")
+}
+
+func migrateVCSTriggerPollState(tx *sql.Tx) error {
+	if err := addColumnIfMissing(tx, "pipelines", "vcs_poll_interval_seconds", "INTEGER NOT NULL DEFAULT 0"); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(`CREATE TABLE IF NOT EXISTS pipeline_vcs_poll_state (
+		pipeline_id INTEGER PRIMARY KEY,
+		watched_repo TEXT NOT NULL DEFAULT '',
+		watched_ref TEXT NOT NULL DEFAULT '',
+		last_seen_commit TEXT NOT NULL DEFAULT '',
+		last_polled_utc TEXT,
+		last_poll_error TEXT NOT NULL DEFAULT '',
+		last_triggered_commit TEXT NOT NULL DEFAULT '',
+		last_triggered_utc TEXT,
+		FOREIGN KEY(pipeline_id) REFERENCES pipelines(id) ON DELETE CASCADE
+	)`); err != nil {
+		return fmt.Errorf("create vcs poll state table: %w", err)
+	}
+	return nil
 }
 
 func migrateInteractiveJobLogs(tx *sql.Tx) error {
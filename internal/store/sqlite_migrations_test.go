@@ -170,3 +170,53 @@ func TestTestReportSummaryMigrationBackfillsExistingReports(t *testing.T) {
 		t.Fatalf("backfilled summary = %d/%d/%d/%d", total, passed, failed, skipped)
 	}
 }
+
+func TestCancelledAndSkippedMigrationReclassifiesFailedJobs(t *testing.T) {
+	db, err := sql.Open("sqlite", filepath.Join(t.TempDir(), "status-migration.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	if _, err := db.Exec(`CREATE TABLE job_executions (
+		id TEXT PRIMARY KEY,
+		status TEXT NOT NULL,
+		error_text TEXT,
+		metadata_json TEXT NOT NULL
+	)`); err != nil {
+		t.Fatal(err)
+	}
+	rows := []struct{ id, status, errorText, metadata, want string }{
+		{"user-cancel", "failed", "cancelled by user", `{}`, "cancelled"},
+		{"deactivated", "failed", "cancelled by alice: agent a1 deactivated", `{"cancelled_by":"alice"}`, "cancelled"},
+		{"needs", "failed", "cancelled: required job build failed", `{}`, "skipped"},
+		{"chain", "failed", "cancelled: upstream pipeline build failed", `{"chain_cancelled":"1"}`, "skipped"},
+		{"chain-bind", "failed", "artifact source missing", `{"chain_cancelled":"1"}`, "skipped"},
+		{"real-failure", "failed", "exit status 2", `{}`, "failed"},
+		{"success", "succeeded", "", `{}`, "succeeded"},
+	}
+	for _, row := range rows {
+		if _, err := db.Exec(`INSERT INTO job_executions VALUES (?, ?, ?, ?)`, row.id, row.status, row.errorText, row.metadata); err != nil {
+			t.Fatal(err)
+		}
+	}
+	tx, err := db.Begin()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := migrateCancelledAndSkippedStatuses(tx); err != nil {
+		_ = tx.Rollback()
+		t.Fatal(err)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatal(err)
+	}
+	for _, row := range rows {
+		var status string
+		if err := db.QueryRow(`SELECT status FROM job_executions WHERE id = ?`, row.id).Scan(&status); err != nil {
+			t.Fatal(err)
+		}
+		if status != row.want {
+			t.Fatalf("%s status = %q, want %q", row.id, status, row.want)
+		}
+	}
+}
@@ -0,0 +1,39 @@
+package store
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// SetProjectWebhookSecret replaces the shared secret used to authenticate
+// inbound push webhooks. An empty secret disables the webhook.
+func (s *Store) SetProjectWebhookSecret(projectID int64, secret string) error {
+	res, err := s.db.Exec(`
+		UPDATE projects
+		SET webhook_secret = ?, updated_utc = ?
+		WHERE id = ?
+	`, secret, time.Now().UTC().Format(time.RFC3339Nano), projectID)
+	if err != nil {
+		return fmt.Errorf("set project webhook secret: %w", err)
+	}
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("set project webhook secret: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("project not found")
+	}
+	return nil
+}
+
+func (s *Store) GetProjectWebhookSecret(projectID int64) (string, error) {
+	var secret string
+	if err := s.db.QueryRow(`SELECT webhook_secret FROM projects WHERE id = ?`, projectID).Scan(&secret); err != nil {
+		if err == sql.ErrNoRows {
+			return "", fmt.Errorf("project not found")
+		}
+		return "", fmt.Errorf("get project webhook secret: %w", err)
+	}
+	return secret, nil
+}
@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"encoding/json"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/izzyreal/ciwi/internal/config"
@@ -56,16 +57,20 @@ func upsertPipeline(tx *sql.Tx, projectID int64, p config.Pipeline, now string)
 	versioningJSON, _ := json.Marshal(p.Versioning)
 	repo := ""
 	ref := ""
+	pollInterval := 0
+	sourceCheckoutJSON := ""
 	if src := p.VCSSource; src != nil {
 		repo = src.Repo
 		ref = src.Ref
+		pollInterval = src.PollIntervalSeconds
+		sourceCheckoutJSON = encodeSourceCheckoutOptions(protocol.SourceCheckoutOptions(src.CheckoutOptions))
 	}
 	if _, err := tx.Exec(`
-		INSERT INTO pipelines (project_id, pipeline_id, trigger_mode, depends_on_json, source_repo, source_ref, versioning_json, created_utc, updated_utc)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+		INSERT INTO pipelines (project_id, pipeline_id, trigger_mode, depends_on_json, source_repo, source_ref, source_checkout_json, vcs_poll_interval_seconds, versioning_json, schedule_json, concurrency_json, created_utc, updated_utc)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 		ON CONFLICT(project_id, pipeline_id)
-		DO UPDATE SET trigger_mode=excluded.trigger_mode, depends_on_json=excluded.depends_on_json, source_repo=excluded.source_repo, source_ref=excluded.source_ref, versioning_json=excluded.versioning_json, updated_utc=excluded.updated_utc
-	`, projectID, p.ID, p.Trigger, string(dependsOnJSON), repo, ref, string(versioningJSON), now, now); err != nil {
+		DO UPDATE SET trigger_mode=excluded.trigger_mode, depends_on_json=excluded.depends_on_json, source_repo=excluded.source_repo, source_ref=excluded.source_ref, source_checkout_json=excluded.source_checkout_json, vcs_poll_interval_seconds=excluded.vcs_poll_interval_seconds, versioning_json=excluded.versioning_json, schedule_json=excluded.schedule_json, concurrency_json=excluded.concurrency_json, updated_utc=excluded.updated_utc
+	`, projectID, p.ID, p.Trigger, string(dependsOnJSON), repo, ref, sourceCheckoutJSON, pollInterval, string(versioningJSON), encodeSchedule(p.Schedule), encodeConcurrency(p.Concurrency), now, now); err != nil {
 		return 0, fmt.Errorf("upsert pipeline: %w", err)
 	}
 
@@ -374,7 +379,7 @@ func (s *Store) GetProjectDetail(id int64) (protocol.ProjectDetail, error) {
 	}
 
 	rows, err := s.db.Query(`
-		SELECT id, pipeline_id, trigger_mode, depends_on_json, source_repo, source_ref, versioning_json
+		SELECT id, pipeline_id, trigger_mode, depends_on_json, source_repo, source_ref, vcs_poll_interval_seconds, versioning_json, schedule_json
 		FROM pipelines
 		WHERE project_id = ?
 		ORDER BY pipeline_id
@@ -394,14 +399,19 @@ func (s *Store) GetProjectDetail(id int64) (protocol.ProjectDetail, error) {
 		UpdatedUTC:   project.UpdatedUTC,
 	}
 
+	pollIntervals := map[int64]int{}
+	schedules := map[int64]*config.Schedule{}
 	for rows.Next() {
 		var p protocol.PipelineDetail
-		var dependsOnJSON, versioningJSON string
-		if err := rows.Scan(&p.ID, &p.PipelineID, &p.Trigger, &dependsOnJSON, &p.SourceRepo, &p.SourceRef, &versioningJSON); err != nil {
+		var dependsOnJSON, versioningJSON, scheduleJSON string
+		var pollInterval int
+		if err := rows.Scan(&p.ID, &p.PipelineID, &p.Trigger, &dependsOnJSON, &p.SourceRepo, &p.SourceRef, &pollInterval, &versioningJSON, &scheduleJSON); err != nil {
 			return protocol.ProjectDetail{}, fmt.Errorf("scan pipeline: %w", err)
 		}
 		_ = json.Unmarshal([]byte(dependsOnJSON), &p.DependsOn)
 		_ = json.Unmarshal([]byte(versioningJSON), &p.Versioning)
+		pollIntervals[p.ID] = pollInterval
+		schedules[p.ID] = decodeSchedule(scheduleJSON)
 		detail.Pipelines = append(detail.Pipelines, p)
 	}
 	if err := rows.Err(); err != nil {
@@ -411,12 +421,27 @@ func (s *Store) GetProjectDetail(id int64) (protocol.ProjectDetail, error) {
 		return protocol.ProjectDetail{}, fmt.Errorf("close pipelines rows: %w", err)
 	}
 
+	now := time.Now()
 	for i := range detail.Pipelines {
 		persistedJobs, err := s.listPipelineJobs(detail.Pipelines[i].ID)
 		if err != nil {
 			return protocol.ProjectDetail{}, err
 		}
 		detail.Pipelines[i].Jobs = pipelineJobDetailsFromPersisted(persistedJobs)
+		if detail.Pipelines[i].Trigger == "vcs" {
+			trigger, err := s.getPipelineVCSTrigger(detail.Pipelines[i].ID, pollIntervals[detail.Pipelines[i].ID])
+			if err != nil {
+				return protocol.ProjectDetail{}, err
+			}
+			detail.Pipelines[i].VCSTrigger = trigger
+		}
+		if detail.Pipelines[i].Trigger == "schedule" {
+			sched, err := s.getScheduleDetail(id, ScheduleTargetPipeline, detail.Pipelines[i].PipelineID, schedules[detail.Pipelines[i].ID], now)
+			if err != nil {
+				return protocol.ProjectDetail{}, err
+			}
+			detail.Pipelines[i].Schedule = sched
+		}
 	}
 	chains, err := s.listPipelineChainsByProjectID(id)
 	if err != nil {
@@ -452,12 +477,17 @@ func (s *Store) GetProjectDetail(id int64) (protocol.ProjectDetail, error) {
 				supports = true
 			}
 		}
+		sched, err := s.getScheduleDetail(id, ScheduleTargetChain, ch.ChainID, ch.Schedule, now)
+		if err != nil {
+			return protocol.ProjectDetail{}, err
+		}
 		detail.PipelineChains = append(detail.PipelineChains, protocol.PipelineChainSummary{
 			ID:                ch.ChainID,
 			Name:              ch.ChainName,
 			Pipelines:         append([]string(nil), ch.Pipelines...),
 			SupportsDryRun:    supports,
 			VersionPipelineID: versionPipelineID,
+			Schedule:          sched,
 		})
 	}
 
@@ -493,7 +523,7 @@ func (s *Store) pipelineSupportsDryRun(pipelineDBID int64) (bool, error) {
 
 func (s *Store) listPipelineChainsByProjectID(projectID int64) ([]PersistedPipelineChain, error) {
 	rows, err := s.db.Query(`
-		SELECT pc.id, pc.project_id, p.name, pc.chain_id, pc.chain_name, pc.position, pc.pipelines_json
+		SELECT pc.id, pc.project_id, p.name, pc.chain_id, pc.chain_name, pc.position, pc.pipelines_json, pc.schedule_json
 		FROM pipeline_chains pc
 		JOIN projects p ON p.id = pc.project_id
 		WHERE pc.project_id = ?
@@ -506,11 +536,12 @@ func (s *Store) listPipelineChainsByProjectID(projectID int64) ([]PersistedPipel
 	out := make([]PersistedPipelineChain, 0)
 	for rows.Next() {
 		var ch PersistedPipelineChain
-		var pipelinesJSON string
-		if err := rows.Scan(&ch.DBID, &ch.ProjectID, &ch.ProjectName, &ch.ChainID, &ch.ChainName, &ch.Position, &pipelinesJSON); err != nil {
+		var pipelinesJSON, scheduleJSON string
+		if err := rows.Scan(&ch.DBID, &ch.ProjectID, &ch.ProjectName, &ch.ChainID, &ch.ChainName, &ch.Position, &pipelinesJSON, &scheduleJSON); err != nil {
 			return nil, fmt.Errorf("scan pipeline chain: %w", err)
 		}
 		_ = json.Unmarshal([]byte(pipelinesJSON), &ch.Pipelines)
+		ch.Schedule = decodeSchedule(scheduleJSON)
 		out = append(out, ch)
 	}
 	if err := rows.Err(); err != nil {
@@ -531,6 +562,8 @@ func pipelineJobDetailsFromPersisted(persistedJobs []PersistedPipelineJob) []pro
 			RequiresTools:   cloneMap(j.RequiresTools),
 			Artifacts:       append([]string(nil), j.Artifacts...),
 			Caches:          cloneJobCachesFromConfig(j.Caches),
+			Shards:          j.Shards,
+			Priority:        j.Priority,
 		}
 		d.Steps = make([]protocol.PipelineStep, 0, len(j.Steps))
 		for _, step := range j.Steps {
@@ -544,6 +577,7 @@ func pipelineJobDetailsFromPersisted(persistedJobs []PersistedPipelineJob) []pro
 					TestReport:     step.Test.Report,
 					CoverageFormat: step.Test.CoverageFormat,
 					CoverageReport: step.Test.CoverageReport,
+					CoverageGate:   coverageGateFromConfig(step.Test.CoverageGate),
 					SkipDryRun:     step.SkipDryRun,
 					Env:            cloneMap(step.Env),
 				})
@@ -588,13 +622,13 @@ func cloneArtifactSourcesToProtocol(in []config.PipelineJobArtifactSource) []pro
 func (s *Store) GetPipelineByDBID(id int64) (PersistedPipeline, error) {
 	var p PersistedPipeline
 	row := s.db.QueryRow(`
-		SELECT pl.id, pl.project_id, p.name, pl.pipeline_id, pl.trigger_mode, pl.depends_on_json, pl.source_repo, pl.source_ref, pl.versioning_json
+		SELECT pl.id, pl.project_id, p.name, pl.pipeline_id, pl.trigger_mode, pl.depends_on_json, pl.source_repo, pl.source_ref, pl.source_checkout_json, pl.versioning_json, pl.concurrency_json
 		FROM pipelines pl
 		JOIN projects p ON p.id = pl.project_id
 		WHERE pl.id = ?
 	`, id)
-	var dependsOnJSON, versioningJSON string
-	if err := row.Scan(&p.DBID, &p.ProjectID, &p.ProjectName, &p.PipelineID, &p.Trigger, &dependsOnJSON, &p.SourceRepo, &p.SourceRef, &versioningJSON); err != nil {
+	var dependsOnJSON, sourceCheckoutJSON, versioningJSON, concurrencyJSON string
+	if err := row.Scan(&p.DBID, &p.ProjectID, &p.ProjectName, &p.PipelineID, &p.Trigger, &dependsOnJSON, &p.SourceRepo, &p.SourceRef, &sourceCheckoutJSON, &versioningJSON, &concurrencyJSON); err != nil {
 		if err == sql.ErrNoRows {
 			return p, fmt.Errorf("pipeline not found")
 		}
@@ -602,6 +636,8 @@ func (s *Store) GetPipelineByDBID(id int64) (PersistedPipeline, error) {
 	}
 	_ = json.Unmarshal([]byte(dependsOnJSON), &p.DependsOn)
 	_ = json.Unmarshal([]byte(versioningJSON), &p.Versioning)
+	p.SourceCheckout = config.CheckoutOptions(decodeSourceCheckoutOptions(sourceCheckoutJSON))
+	p.Concurrency = decodeConcurrency(concurrencyJSON)
 
 	jobs, err := s.listPipelineJobs(p.DBID)
 	if err != nil {
@@ -647,25 +683,26 @@ func (s *Store) GetPipelineByProjectIDAndID(projectID int64, pipelineID string)
 func (s *Store) GetPipelineChain(projectID int64, chainID string) (PersistedPipelineChain, error) {
 	var ch PersistedPipelineChain
 	row := s.db.QueryRow(`
-		SELECT pc.id, pc.project_id, p.name, pc.chain_id, pc.chain_name, pc.position, pc.pipelines_json
+		SELECT pc.id, pc.project_id, p.name, pc.chain_id, pc.chain_name, pc.position, pc.pipelines_json, pc.schedule_json
 		FROM pipeline_chains pc
 		JOIN projects p ON p.id = pc.project_id
 		WHERE pc.project_id = ? AND pc.chain_id = ?
 	`, projectID, chainID)
-	var pipelinesJSON string
-	if err := row.Scan(&ch.DBID, &ch.ProjectID, &ch.ProjectName, &ch.ChainID, &ch.ChainName, &ch.Position, &pipelinesJSON); err != nil {
+	var pipelinesJSON, scheduleJSON string
+	if err := row.Scan(&ch.DBID, &ch.ProjectID, &ch.ProjectName, &ch.ChainID, &ch.ChainName, &ch.Position, &pipelinesJSON, &scheduleJSON); err != nil {
 		if err == sql.ErrNoRows {
 			return ch, fmt.Errorf("pipeline chain not found")
 		}
 		return ch, fmt.Errorf("get pipeline chain: %w", err)
 	}
 	_ = json.Unmarshal([]byte(pipelinesJSON), &ch.Pipelines)
+	ch.Schedule = decodeSchedule(scheduleJSON)
 	return ch, nil
 }
 
 func (s *Store) listPipelineJobs(pipelineDBID int64) ([]PersistedPipelineJob, error) {
 	rows, err := s.db.Query(`
-		SELECT job_id, position, needs_json, artifact_sources_json, runs_on_json, requires_tools_json, requires_container_tools_json, requires_capabilities_json, timeout_seconds, artifacts_json, caches_json, matrix_json, steps_json
+		SELECT job_id, position, if_expr, retry_json, needs_json, artifact_sources_json, runs_on_json, requires_tools_json, requires_container_tools_json, requires_capabilities_json, timeout_seconds, artifacts_json, caches_json, services_json, matrix_json, shards, priority, concurrency_json, steps_json
 		FROM pipeline_jobs
 		WHERE pipeline_id = ?
 		ORDER BY position
@@ -678,10 +715,16 @@ func (s *Store) listPipelineJobs(pipelineDBID int64) ([]PersistedPipelineJob, er
 	jobs := []PersistedPipelineJob{}
 	for rows.Next() {
 		var j PersistedPipelineJob
-		var needsJSON, artifactSourcesJSON, runsOnJSON, requiresToolsJSON, requiresContainerToolsJSON, requiresCapsJSON, artifactsJSON, cachesJSON, matrixJSON, stepsJSON string
-		if err := rows.Scan(&j.ID, &j.Position, &needsJSON, &artifactSourcesJSON, &runsOnJSON, &requiresToolsJSON, &requiresContainerToolsJSON, &requiresCapsJSON, &j.TimeoutSeconds, &artifactsJSON, &cachesJSON, &matrixJSON, &stepsJSON); err != nil {
+		var retryJSON, needsJSON, artifactSourcesJSON, runsOnJSON, requiresToolsJSON, requiresContainerToolsJSON, requiresCapsJSON, artifactsJSON, cachesJSON, servicesJSON, matrixJSON, concurrencyJSON, stepsJSON string
+		if err := rows.Scan(&j.ID, &j.Position, &j.If, &retryJSON, &needsJSON, &artifactSourcesJSON, &runsOnJSON, &requiresToolsJSON, &requiresContainerToolsJSON, &requiresCapsJSON, &j.TimeoutSeconds, &artifactsJSON, &cachesJSON, &servicesJSON, &matrixJSON, &j.Shards, &j.Priority, &concurrencyJSON, &stepsJSON); err != nil {
 			return nil, fmt.Errorf("scan pipeline job: %w", err)
 		}
+		if strings.TrimSpace(retryJSON) != "" {
+			var retry config.RetryPolicy
+			if err := json.Unmarshal([]byte(retryJSON), &retry); err == nil {
+				j.Retry = &retry
+			}
+		}
 		_ = json.Unmarshal([]byte(needsJSON), &j.Needs)
 		_ = json.Unmarshal([]byte(artifactSourcesJSON), &j.ArtifactSources)
 		_ = json.Unmarshal([]byte(runsOnJSON), &j.RunsOn)
@@ -689,7 +732,9 @@ func (s *Store) listPipelineJobs(pipelineDBID int64) ([]PersistedPipelineJob, er
 		_ = json.Unmarshal([]byte(requiresContainerToolsJSON), &j.RequiresContainerTools)
 		_ = json.Unmarshal([]byte(artifactsJSON), &j.Artifacts)
 		_ = json.Unmarshal([]byte(cachesJSON), &j.Caches)
+		_ = json.Unmarshal([]byte(servicesJSON), &j.Services)
 		_ = json.Unmarshal([]byte(matrixJSON), &j.MatrixInclude)
+		j.Concurrency = decodeConcurrency(concurrencyJSON)
 		if err := json.Unmarshal([]byte(stepsJSON), &j.Steps); err != nil {
 			return nil, fmt.Errorf("decode pipeline job %q steps: %w", j.ID, err)
 		}
@@ -700,3 +745,10 @@ func (s *Store) listPipelineJobs(pipelineDBID int64) ([]PersistedPipelineJob, er
 	}
 	return jobs, nil
 }
+
+func coverageGateFromConfig(gate *config.CoverageGate) *protocol.CoverageGate {
+	if gate == nil {
+		return nil
+	}
+	return &protocol.CoverageGate{MinPercent: gate.MinPercent, MaxDrop: gate.MaxDrop}
+}
@@ -0,0 +1,105 @@
+package store
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/izzyreal/ciwi/internal/protocol"
+)
+
+const repoCredentialColumns = `id, name, url_prefix, kind, username, secret_env, vault_connection, vault_secret_json, known_hosts`
+
+func (s *Store) UpsertRepoCredential(req protocol.UpsertRepoCredentialRequest) (protocol.RepoCredential, error) {
+	now := time.Now().UTC().Format(time.RFC3339Nano)
+	vaultSecret := ""
+	if req.VaultSecret != nil {
+		raw, err := json.Marshal(req.VaultSecret)
+		if err != nil {
+			return protocol.RepoCredential{}, fmt.Errorf("encode repo credential vault secret: %w", err)
+		}
+		vaultSecret = string(raw)
+	}
+	if _, err := s.db.Exec(`
+		INSERT INTO repo_credentials (name, url_prefix, kind, username, secret_env, vault_connection, vault_secret_json, known_hosts, created_utc, updated_utc)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(name) DO UPDATE SET
+			url_prefix=excluded.url_prefix,
+			kind=excluded.kind,
+			username=excluded.username,
+			secret_env=excluded.secret_env,
+			vault_connection=excluded.vault_connection,
+			vault_secret_json=excluded.vault_secret_json,
+			known_hosts=excluded.known_hosts,
+			updated_utc=excluded.updated_utc
+	`, strings.TrimSpace(req.Name), strings.TrimSpace(req.URLPrefix), strings.TrimSpace(req.Kind), strings.TrimSpace(req.Username),
+		strings.TrimSpace(req.SecretEnv), strings.TrimSpace(req.VaultConnection), vaultSecret, req.KnownHosts, now, now); err != nil {
+		return protocol.RepoCredential{}, fmt.Errorf("upsert repo credential: %w", err)
+	}
+	return s.getRepoCredential(`name = ?`, strings.TrimSpace(req.Name))
+}
+
+func (s *Store) ListRepoCredentials() ([]protocol.RepoCredential, error) {
+	rows, err := s.db.Query(`SELECT ` + repoCredentialColumns + ` FROM repo_credentials ORDER BY name`)
+	if err != nil {
+		return nil, fmt.Errorf("list repo credentials: %w", err)
+	}
+	defer rows.Close()
+	out := []protocol.RepoCredential{}
+	for rows.Next() {
+		c, err := scanRepoCredential(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate repo credentials: %w", err)
+	}
+	return out, nil
+}
+
+func (s *Store) GetRepoCredentialByID(id int64) (protocol.RepoCredential, error) {
+	return s.getRepoCredential(`id = ?`, id)
+}
+
+func (s *Store) DeleteRepoCredential(id int64) error {
+	res, err := s.db.Exec(`DELETE FROM repo_credentials WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("delete repo credential: %w", err)
+	}
+	n, _ := res.RowsAffected()
+	if n == 0 {
+		return fmt.Errorf("repo credential not found")
+	}
+	return nil
+}
+
+func (s *Store) getRepoCredential(where string, arg any) (protocol.RepoCredential, error) {
+	c, err := scanRepoCredential(s.db.QueryRow(`SELECT `+repoCredentialColumns+` FROM repo_credentials WHERE `+where, arg))
+	if err == sql.ErrNoRows {
+		return protocol.RepoCredential{}, fmt.Errorf("repo credential not found")
+	}
+	return c, err
+}
+
+func scanRepoCredential(row interface{ Scan(...any) error }) (protocol.RepoCredential, error) {
+	var c protocol.RepoCredential
+	var vaultSecret string
+	if err := row.Scan(&c.ID, &c.Name, &c.URLPrefix, &c.Kind, &c.Username, &c.SecretEnv, &c.VaultConnection, &vaultSecret, &c.KnownHosts); err != nil {
+		if err == sql.ErrNoRows {
+			return protocol.RepoCredential{}, err
+		}
+		return protocol.RepoCredential{}, fmt.Errorf("scan repo credential: %w", err)
+	}
+	if vaultSecret != "" {
+		var spec protocol.ProjectSecretSpec
+		if err := json.Unmarshal([]byte(vaultSecret), &spec); err != nil {
+			return protocol.RepoCredential{}, fmt.Errorf("decode repo credential vault secret: %w", err)
+		}
+		c.VaultSecret = &spec
+	}
+	return c, nil
+}
@@ -2,12 +2,14 @@ package store
 
 import (
 	"errors"
+	"strconv"
 	"strings"
 	"sync"
 	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/izzyreal/ciwi/internal/domain"
 	"github.com/izzyreal/ciwi/internal/protocol"
 )
 
@@ -214,6 +216,33 @@ func TestStoreIgnoresLateRunningAfterSucceeded(t *testing.T) {
 	}
 }
 
+func TestStoreKeepsCancelledAgainstLateAgentUpdates(t *testing.T) {
+	s := openTestStore(t)
+
+	job, err := s.CreateJobExecution(protocol.CreateJobExecutionRequest{Script: "sleep 60", TimeoutSeconds: 30})
+	if err != nil {
+		t.Fatalf("create job: %v", err)
+	}
+	if _, err := s.UpdateJobExecutionStatus(job.ID, protocol.JobExecutionStatusUpdateRequest{AgentID: "agent-1", Status: "running"}); err != nil {
+		t.Fatalf("mark running: %v", err)
+	}
+	cancelled, err := s.UpdateJobExecutionStatus(job.ID, protocol.JobExecutionStatusUpdateRequest{
+		AgentID: "agent-1", Status: protocol.JobExecutionStatusCancelled, Error: "cancelled by user",
+	})
+	if err != nil || cancelled.Status != protocol.JobExecutionStatusCancelled || cancelled.FinishedUTC.IsZero() {
+		t.Fatalf("cancel job = %+v, err=%v", cancelled, err)
+	}
+	for _, status := range []string{"running", "failed"} {
+		got, err := s.UpdateJobExecutionStatus(job.ID, protocol.JobExecutionStatusUpdateRequest{AgentID: "agent-1", Status: status, Error: "signal: interrupt"})
+		if err != nil {
+			t.Fatalf("late %s update: %v", status, err)
+		}
+		if got.Status != protocol.JobExecutionStatusCancelled || got.Error != "cancelled by user" {
+			t.Fatalf("late %s update overwrote cancellation: %+v", status, got)
+		}
+	}
+}
+
 func TestStoreAgentHasActiveJob(t *testing.T) {
 	s := openTestStore(t)
 
@@ -542,3 +571,110 @@ func TestStoreFailTimedOutRunningJobExecutions(t *testing.T) {
 		t.Fatalf("expected timeout control event, got %+v", events)
 	}
 }
+
+func TestStoreLeaseOrdersByPriorityThenFairShareThenAge(t *testing.T) {
+	s := openTestStore(t)
+	create := func(projectID, priority string) string {
+		t.Helper()
+		metadata := map[string]string{domain.ExecutionMetadataProjectID: projectID}
+		if priority != "" {
+			metadata[domain.ExecutionMetadataPriority] = priority
+		}
+		job, err := s.CreateJobExecution(protocol.CreateJobExecutionRequest{Script: "echo hi", TimeoutSeconds: 30, Metadata: metadata})
+		if err != nil {
+			t.Fatalf("create job: %v", err)
+		}
+		return job.ID
+	}
+	first := create("1", "")
+	second := create("1", "")
+	other := create("2", "")
+	urgent := create("2", "50")
+
+	for i, want := range []string{urgent, first, second, other} {
+		leased, err := s.LeaseJobExecution("agent-"+strconv.Itoa(i), nil)
+		if err != nil {
+			t.Fatalf("lease %d: %v", i, err)
+		}
+		if leased == nil || leased.ID != want {
+			t.Fatalf("lease %d: expected %s, got %+v", i, want, leased)
+		}
+	}
+}
+
+func TestStoreLeaseRespectsConcurrencyGroups(t *testing.T) {
+	s := openTestStore(t)
+	create := func(holder string) string {
+		t.Helper()
+		job, err := s.CreateJobExecution(protocol.CreateJobExecutionRequest{Script: "./deploy", TimeoutSeconds: 30, Metadata: map[string]string{
+			domain.ExecutionMetadataConcurrencyGroup:      "deploy-homelab",
+			domain.ExecutionMetadataConcurrencyMaxRunning: "1",
+			domain.ExecutionMetadataConcurrencyHolder:     holder,
+		}})
+		if err != nil {
+			t.Fatalf("create job: %v", err)
+		}
+		return job.ID
+	}
+	first := create("run-1")
+	second := create("run-2")
+
+	leased, err := s.LeaseJobExecution("agent-a", nil)
+	if err != nil || leased == nil || leased.ID != first {
+		t.Fatalf("expected %s to be leased first, got %+v err=%v", first, leased, err)
+	}
+	if leased, err := s.LeaseJobExecution("agent-b", nil); err != nil || leased != nil {
+		t.Fatalf("expected the full group to block %s, got %+v err=%v", second, leased, err)
+	}
+	if _, err := s.UpdateJobExecutionStatus(first, protocol.JobExecutionStatusUpdateRequest{AgentID: "agent-a", Status: protocol.JobExecutionStatusSucceeded}); err != nil {
+		t.Fatalf("finish job: %v", err)
+	}
+	leased, err = s.LeaseJobExecution("agent-b", nil)
+	if err != nil || leased == nil || leased.ID != second {
+		t.Fatalf("expected %s once the group freed up, got %+v err=%v", second, leased, err)
+	}
+}
+
+func TestStoreLeaseWaitsForPreferredAgent(t *testing.T) {
+	s := openTestStore(t)
+	create := func(waitingSince string) string {
+		t.Helper()
+		job, err := s.CreateJobExecution(protocol.CreateJobExecutionRequest{Script: "go build", TimeoutSeconds: 30, Metadata: map[string]string{
+			domain.ExecutionMetadataAffinityKey:             "1/ci/build//",
+			domain.ExecutionMetadataAffinityAgentID:         "agent-a",
+			domain.ExecutionMetadataAffinityWaitSeconds:     "60",
+			domain.ExecutionMetadataAffinityWaitingSinceUTC: waitingSince,
+		}})
+		if err != nil {
+			t.Fatalf("create job: %v", err)
+		}
+		return job.ID
+	}
+	warm := create("")
+	if leased, err := s.LeaseJobExecution("agent-b", nil); err != nil || leased != nil {
+		t.Fatalf("expected agent-b to leave %s to agent-a, got %+v err=%v", warm, leased, err)
+	}
+	job, err := s.GetJobExecution(warm)
+	if err != nil {
+		t.Fatalf("get job: %v", err)
+	}
+	if _, until, started := protocol.AgentAffinityWait(job); !started || !until.After(time.Now()) {
+		t.Fatalf("expected the refusal to start the wait, got until=%s started=%v", until, started)
+	}
+	leased, err := s.LeaseJobExecution("agent-a", nil)
+	if err != nil || leased == nil || leased.ID != warm {
+		t.Fatalf("expected agent-a to take %s, got %+v err=%v", warm, leased, err)
+	}
+	if _, err := s.UpdateJobExecutionStatus(warm, protocol.JobExecutionStatusUpdateRequest{AgentID: "agent-a", Status: protocol.JobExecutionStatusSucceeded}); err != nil {
+		t.Fatalf("finish job: %v", err)
+	}
+	if agentID, err := s.LastSucceededAgentForAffinityKey("1/ci/build//"); err != nil || agentID != "agent-a" {
+		t.Fatalf("expected agent-a as the last agent, got %q err=%v", agentID, err)
+	}
+
+	expired := create(time.Now().UTC().Add(-2 * time.Minute).Format(time.RFC3339Nano))
+	leased, err = s.LeaseJobExecution("agent-b", nil)
+	if err != nil || leased == nil || leased.ID != expired {
+		t.Fatalf("expected agent-b to take %s once the wait ran out, got %+v err=%v", expired, leased, err)
+	}
+}
@@ -0,0 +1,196 @@
+package store
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/izzyreal/ciwi/internal/config"
+	"github.com/izzyreal/ciwi/internal/protocol"
+	"github.com/izzyreal/ciwi/internal/schedule"
+)
+
+const (
+	ScheduleTargetPipeline = "pipeline"
+	ScheduleTargetChain    = "chain"
+)
+
+// PersistedScheduleTrigger is a pipeline with trigger: schedule or a pipeline
+// chain with a schedule, together with the state the scheduler recorded for
+// it.
+type PersistedScheduleTrigger struct {
+	ProjectID   int64
+	ProjectName string
+	TargetKind  string
+	// TargetID is the pipeline id or the chain id.
+	TargetID string
+	Name     string
+	// PipelineDBID is only set for pipelines.
+	PipelineDBID   int64
+	Schedule       config.Schedule
+	LastCheckedUTC time.Time
+	LastFireUTC    time.Time
+	LastError      string
+}
+
+// ScheduleCheckRecord is the outcome of one scheduler pass over a target.
+// FiredUTC is the scheduled time of the fire that was acted on, if any.
+type ScheduleCheckRecord struct {
+	CheckedUTC time.Time
+	FiredUTC   time.Time
+	Error      string
+}
+
+func (s *Store) ListScheduleTriggers() ([]PersistedScheduleTrigger, error) {
+	rows, err := s.db.Query(`
+		SELECT pl.project_id, p.name, 'pipeline', pl.pipeline_id, pl.pipeline_id, pl.id, pl.schedule_json,
+			st.last_checked_utc, st.last_fire_utc, st.last_error
+		FROM pipelines pl
+		JOIN projects p ON p.id = pl.project_id
+		LEFT JOIN schedule_trigger_state st ON st.project_id = pl.project_id AND st.target_kind = 'pipeline' AND st.target_id = pl.pipeline_id
+		WHERE pl.trigger_mode = 'schedule' AND pl.schedule_json <> ''
+		UNION ALL
+		SELECT pc.project_id, p.name, 'chain', pc.chain_id, pc.chain_name, 0, pc.schedule_json,
+			st.last_checked_utc, st.last_fire_utc, st.last_error
+		FROM pipeline_chains pc
+		JOIN projects p ON p.id = pc.project_id
+		LEFT JOIN schedule_trigger_state st ON st.project_id = pc.project_id AND st.target_kind = 'chain' AND st.target_id = pc.chain_id
+		WHERE pc.schedule_json <> ''
+		ORDER BY 1, 3 DESC, 4
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("list schedule triggers: %w", err)
+	}
+	defer rows.Close()
+	var out []PersistedScheduleTrigger
+	for rows.Next() {
+		var t PersistedScheduleTrigger
+		var scheduleJSON string
+		var state scheduleStateColumns
+		if err := rows.Scan(&t.ProjectID, &t.ProjectName, &t.TargetKind, &t.TargetID, &t.Name, &t.PipelineDBID, &scheduleJSON,
+			&state.lastCheckedUTC, &state.lastFireUTC, &state.lastError); err != nil {
+			return nil, fmt.Errorf("scan schedule trigger: %w", err)
+		}
+		sched := decodeSchedule(scheduleJSON)
+		if sched == nil {
+			continue
+		}
+		t.Schedule = *sched
+		t.LastCheckedUTC, t.LastFireUTC, t.LastError = state.values()
+		out = append(out, t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate schedule triggers: %w", err)
+	}
+	return out, nil
+}
+
+// RecordScheduleCheck stores the outcome of a scheduler pass. The last error
+// only changes when a fire was acted on, so quiet passes keep it visible.
+func (s *Store) RecordScheduleCheck(projectID int64, targetKind, targetID string, rec ScheduleCheckRecord) error {
+	checked := rec.CheckedUTC
+	if checked.IsZero() {
+		checked = time.Now().UTC()
+	}
+	fired := !rec.FiredUTC.IsZero()
+	if _, err := s.db.Exec(`
+		INSERT INTO schedule_trigger_state (project_id, target_kind, target_id, last_checked_utc, last_fire_utc, last_error)
+		VALUES (?, ?, ?, ?, ?, ?)
+		ON CONFLICT(project_id, target_kind, target_id) DO UPDATE SET
+			last_checked_utc = excluded.last_checked_utc,
+			last_fire_utc = COALESCE(excluded.last_fire_utc, schedule_trigger_state.last_fire_utc),
+			last_error = CASE WHEN ? THEN excluded.last_error ELSE schedule_trigger_state.last_error END
+	`, projectID, targetKind, targetID, nullableTime(checked), nullableTime(rec.FiredUTC), strings.TrimSpace(rec.Error), fired); err != nil {
+		return fmt.Errorf("record schedule check: %w", err)
+	}
+	return nil
+}
+
+func (s *Store) getScheduleDetail(projectID int64, targetKind, targetID string, sched *config.Schedule, now time.Time) (*protocol.PipelineSchedule, error) {
+	if sched == nil {
+		return nil, nil
+	}
+	out := &protocol.PipelineSchedule{
+		Cron: sched.Cron, Timezone: sched.Timezone, SourceRef: sched.SourceRef, DryRun: sched.DryRun, CatchUp: sched.EffectiveCatchUp(),
+	}
+	var state scheduleStateColumns
+	err := s.db.QueryRow(`
+		SELECT last_checked_utc, last_fire_utc, last_error
+		FROM schedule_trigger_state WHERE project_id = ? AND target_kind = ? AND target_id = ?
+	`, projectID, targetKind, targetID).Scan(&state.lastCheckedUTC, &state.lastFireUTC, &state.lastError)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, fmt.Errorf("get schedule state: %w", err)
+	}
+	_, out.LastFireUTC, out.LastError = state.values()
+	out.NextFireUTC = NextScheduleFire(*sched, now)
+	return out, nil
+}
+
+// NextScheduleFire returns the first fire of sched after now in UTC, or the
+// zero time when the schedule is invalid or never fires.
+func NextScheduleFire(sched config.Schedule, now time.Time) time.Time {
+	cron, err := schedule.Parse(sched.Cron)
+	if err != nil {
+		return time.Time{}
+	}
+	loc, err := schedule.Location(sched.Timezone)
+	if err != nil {
+		return time.Time{}
+	}
+	next := cron.Next(now.In(loc))
+	if next.IsZero() {
+		return next
+	}
+	return next.UTC()
+}
+
+type scheduleStateColumns struct {
+	lastCheckedUTC, lastFireUTC, lastError sql.NullString
+}
+
+func (c scheduleStateColumns) values() (lastChecked, lastFire time.Time, lastError string) {
+	if c.lastCheckedUTC.Valid {
+		lastChecked, _ = time.Parse(time.RFC3339Nano, c.lastCheckedUTC.String)
+	}
+	if c.lastFireUTC.Valid {
+		lastFire, _ = time.Parse(time.RFC3339Nano, c.lastFireUTC.String)
+	}
+	return lastChecked, lastFire, c.lastError.String
+}
+
+// pruneStaleScheduleState drops scheduler state of pipelines and chains that
+// are no longer scheduled after a project reload.
+func pruneStaleScheduleState(tx *sql.Tx, projectID int64) error {
+	if _, err := tx.Exec(`
+		DELETE FROM schedule_trigger_state
+		WHERE project_id = ?
+			AND NOT (target_kind = 'pipeline' AND target_id IN (
+				SELECT pipeline_id FROM pipelines WHERE project_id = ? AND trigger_mode = 'schedule' AND schedule_json <> ''))
+			AND NOT (target_kind = 'chain' AND target_id IN (
+				SELECT chain_id FROM pipeline_chains WHERE project_id = ? AND schedule_json <> ''))
+	`, projectID, projectID, projectID); err != nil {
+		return fmt.Errorf("prune schedule trigger state: %w", err)
+	}
+	return nil
+}
+
+func encodeSchedule(sched *config.Schedule) string {
+	if sched == nil {
+		return ""
+	}
+	encoded, _ := json.Marshal(sched)
+	return string(encoded)
+}
+
+func decodeSchedule(raw string) *config.Schedule {
+	if strings.TrimSpace(raw) == "" {
+		return nil
+	}
+	var sched config.Schedule
+	if err := json.Unmarshal([]byte(raw), &sched); err != nil || strings.TrimSpace(sched.Cron) == "" {
+		return nil
+	}
+	return &sched
+}
@@ -0,0 +1,102 @@
+package store
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/izzyreal/ciwi/internal/config"
+)
+
+func loadScheduleTriggerConfig(t *testing.T, s *Store, withChainSchedule bool) {
+	t.Helper()
+	raw := strings.Replace(testConfigYAML, "trigger: manual", "trigger: schedule\n    schedule:\n      cron: \"0 3 * * *\"\n      timezone: UTC\n      catch_up: once", 1)
+	raw += "pipeline_chains:\n  - name: nightly\n    pipelines: [build]\n"
+	if withChainSchedule {
+		raw += "    schedule:\n      cron: \"@hourly\"\n      dry_run: true\n"
+	}
+	cfg, err := config.Parse([]byte(raw), "schedule-trigger")
+	if err != nil {
+		t.Fatalf("parse config: %v", err)
+	}
+	if err := s.LoadConfig(cfg, "ciwi-project.yaml", "https://github.com/izzyreal/ciwi.git", "main", "ciwi-project.yaml"); err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+}
+
+func TestStoreRecordsScheduleTriggerState(t *testing.T) {
+	s := openTestStore(t)
+	loadScheduleTriggerConfig(t, s, true)
+
+	triggers, err := s.ListScheduleTriggers()
+	if err != nil {
+		t.Fatalf("list schedule triggers: %v", err)
+	}
+	if len(triggers) != 2 {
+		t.Fatalf("expected pipeline and chain schedule triggers, got %+v", triggers)
+	}
+	pipeline, chain := triggers[0], triggers[1]
+	if pipeline.TargetKind != ScheduleTargetPipeline || pipeline.TargetID != "build" || pipeline.PipelineDBID == 0 || pipeline.Schedule.CatchUp != "once" {
+		t.Fatalf("unexpected pipeline trigger: %+v", pipeline)
+	}
+	if chain.TargetKind != ScheduleTargetChain || chain.Name != "nightly" || !chain.Schedule.DryRun || !chain.LastCheckedUTC.IsZero() {
+		t.Fatalf("unexpected chain trigger: %+v", chain)
+	}
+
+	fired := time.Date(2026, 5, 1, 3, 0, 0, 0, time.UTC)
+	if err := s.RecordScheduleCheck(pipeline.ProjectID, pipeline.TargetKind, pipeline.TargetID, ScheduleCheckRecord{CheckedUTC: fired.Add(10 * time.Second), FiredUTC: fired, Error: "queue full"}); err != nil {
+		t.Fatalf("record check: %v", err)
+	}
+	if err := s.RecordScheduleCheck(pipeline.ProjectID, pipeline.TargetKind, pipeline.TargetID, ScheduleCheckRecord{CheckedUTC: fired.Add(time.Minute)}); err != nil {
+		t.Fatalf("record quiet check: %v", err)
+	}
+	triggers, err = s.ListScheduleTriggers()
+	if err != nil {
+		t.Fatalf("list schedule triggers: %v", err)
+	}
+	if got := triggers[0]; !got.LastCheckedUTC.Equal(fired.Add(time.Minute)) || !got.LastFireUTC.Equal(fired) || got.LastError != "queue full" {
+		t.Fatalf("quiet check must keep the last fire and error, got %+v", got)
+	}
+
+	detail, err := s.GetProjectDetail(pipeline.ProjectID)
+	if err != nil {
+		t.Fatalf("get project detail: %v", err)
+	}
+	sched := detail.Pipelines[0].Schedule
+	if sched == nil || sched.Cron != "0 3 * * *" || sched.NextFireUTC.IsZero() || sched.NextFireUTC.Hour() != 3 || sched.LastError != "queue full" {
+		t.Fatalf("unexpected pipeline schedule detail: %+v", sched)
+	}
+	if chainSched := detail.PipelineChains[0].Schedule; chainSched == nil || chainSched.CatchUp != config.ScheduleCatchUpSkip || chainSched.NextFireUTC.Minute() != 0 {
+		t.Fatalf("unexpected chain schedule detail: %+v", chainSched)
+	}
+}
+
+func TestStorePrunesScheduleStateOnReload(t *testing.T) {
+	s := openTestStore(t)
+	loadScheduleTriggerConfig(t, s, true)
+	triggers, err := s.ListScheduleTriggers()
+	if err != nil {
+		t.Fatalf("list schedule triggers: %v", err)
+	}
+	for _, trigger := range triggers {
+		if err := s.RecordScheduleCheck(trigger.ProjectID, trigger.TargetKind, trigger.TargetID, ScheduleCheckRecord{CheckedUTC: time.Now().UTC()}); err != nil {
+			t.Fatalf("record check: %v", err)
+		}
+	}
+
+	loadScheduleTriggerConfig(t, s, false)
+	triggers, err = s.ListScheduleTriggers()
+	if err != nil {
+		t.Fatalf("list schedule triggers: %v", err)
+	}
+	if len(triggers) != 1 || triggers[0].TargetKind != ScheduleTargetPipeline || triggers[0].LastCheckedUTC.IsZero() {
+		t.Fatalf("expected only the pipeline trigger to keep its state, got %+v", triggers)
+	}
+	var stale int
+	if err := s.db.QueryRow(`SELECT COUNT(*) FROM schedule_trigger_state WHERE target_kind = 'chain'`).Scan(&stale); err != nil {
+		t.Fatalf("count chain state: %v", err)
+	}
+	if stale != 0 {
+		t.Fatalf("expected unscheduled chain state to be pruned, found %d rows", stale)
+	}
+}
@@ -185,3 +185,97 @@ func TestFailTimedOutRunningJobExecutionsDefaults(t *testing.T) {
 		t.Fatalf("expected timeout control event, got %+v", events)
 	}
 }
+
+func TestListStatusFilteredJobExecutions(t *testing.T) {
+	s := openTestStore(t)
+	create := func(metadata map[string]string) protocol.JobExecution {
+		t.Helper()
+		job, err := s.CreateJobExecution(protocol.CreateJobExecutionRequest{Script: "echo hi", TimeoutSeconds: 30, Metadata: metadata})
+		if err != nil {
+			t.Fatalf("CreateJobExecution: %v", err)
+		}
+		return job
+	}
+	setStatus := func(job protocol.JobExecution, statuses ...string) {
+		t.Helper()
+		for _, status := range statuses {
+			if _, err := s.UpdateJobExecutionStatus(job.ID, protocol.JobExecutionStatusUpdateRequest{AgentID: "agent-a", Status: status}); err != nil {
+				t.Fatalf("UpdateJobExecutionStatus %s: %v", status, err)
+			}
+		}
+	}
+	ids := func(jobs []protocol.JobExecution, err error) string {
+		t.Helper()
+		if err != nil {
+			t.Fatal(err)
+		}
+		out := make([]string, 0, len(jobs))
+		for _, job := range jobs {
+			out = append(out, job.ID)
+		}
+		return strings.Join(out, ",")
+	}
+
+	running := create(nil)
+	if leased, err := s.LeaseJobExecution("agent-a", nil); err != nil || leased == nil || leased.ID != running.ID {
+		t.Fatalf("LeaseJobExecution = %+v, %v", leased, err)
+	}
+	setStatus(running, protocol.JobExecutionStatusRunning)
+	create(nil)
+	if got := ids(s.ListAgentActiveJobExecutions("agent-a")); got != running.ID {
+		t.Fatalf("agent-a active jobs = %q, want %q", got, running.ID)
+	}
+	if got := ids(s.ListAgentActiveJobExecutions("agent-b")); got != "" {
+		t.Fatalf("agent-b active jobs = %q, want none", got)
+	}
+	if _, err := s.ListAgentActiveJobExecutions(" "); err == nil {
+		t.Fatalf("expected an error for an empty agent id")
+	}
+
+	first := create(map[string]string{"retry_max_attempts": "2"})
+	setStatus(first, protocol.JobExecutionStatusRunning, protocol.JobExecutionStatusFailed)
+	if _, err := s.db.Exec(`UPDATE job_executions SET created_utc = ? WHERE id = ?`, time.Now().UTC().Add(-time.Minute).Format(time.RFC3339Nano), first.ID); err != nil {
+		t.Fatalf("backdate created_utc: %v", err)
+	}
+	setStatus(create(nil), protocol.JobExecutionStatusRunning, protocol.JobExecutionStatusFailed)
+	if got := ids(s.ListRetryPendingJobExecutions()); got != first.ID {
+		t.Fatalf("pending retries = %q, want %q", got, first.ID)
+	}
+	second := create(map[string]string{"retry_max_attempts": "2", "retry_attempt": "2", "attempt_root_job_id": first.ID})
+	if got := ids(s.ListRetryPendingJobExecutions()); got != "" {
+		t.Fatalf("pending retries after the retry was queued = %q, want none", got)
+	}
+	setStatus(second, protocol.JobExecutionStatusRunning, protocol.JobExecutionStatusFailed)
+	if got := ids(s.ListRetryPendingJobExecutions()); got != "" {
+		t.Fatalf("pending retries after the last attempt failed = %q, want none", got)
+	}
+}
+
+func TestListPipelineJobExecutionsContext(t *testing.T) {
+	s := openTestStore(t)
+	create := func(runID, jobID string) protocol.JobExecution {
+		t.Helper()
+		job, err := s.CreateJobExecution(protocol.CreateJobExecutionRequest{Script: "echo hi", TimeoutSeconds: 30, Metadata: map[string]string{
+			"pipeline_run_id": runID, "pipeline_job_id": jobID,
+		}})
+		if err != nil {
+			t.Fatalf("CreateJobExecution: %v", err)
+		}
+		return job
+	}
+	first := create("run-1", "unit")
+	second := create("run-1", "unit")
+	create("run-1", "lint")
+	create("run-2", "unit")
+
+	jobs, err := s.ListPipelineJobExecutionsContext(t.Context(), "run-1", "unit")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(jobs) != 2 || jobs[0].ID != first.ID || jobs[1].ID != second.ID {
+		t.Fatalf("expected both executions of run-1/unit, got %+v", jobs)
+	}
+	if jobs, err := s.ListPipelineJobExecutionsContext(t.Context(), "", "unit"); err != nil || len(jobs) != 0 {
+		t.Fatalf("expected no executions without a run id, got %+v, %v", jobs, err)
+	}
+}
@@ -506,6 +506,121 @@ pipelines:
 	}
 }
 
+func TestStorePersistsSourceCheckoutOptions(t *testing.T) {
+	s := openTestStore(t)
+	cfg, err := config.Parse([]byte(`
+version: 1
+project:
+  name: ciwi
+pipelines:
+  - id: build
+    vcs_source:
+      repo: https://github.com/izzyreal/ciwi.git
+      depth: 0
+      submodules: recursive
+      sparse: [internal]
+      fetch_tags: true
+    jobs:
+      - id: compile
+        timeout_seconds: 30
+        steps:
+          - run: go build ./...
+`), "checkout-options-config")
+	if err != nil {
+		t.Fatalf("parse config: %v", err)
+	}
+	if err := s.LoadConfig(cfg, "ciwi-project.yaml", "https://github.com/izzyreal/ciwi.git", "main", "ciwi-project.yaml"); err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	pipeline, err := s.GetPipelineByProjectAndID("ciwi", "build")
+	if err != nil {
+		t.Fatalf("get pipeline: %v", err)
+	}
+	options := pipeline.SourceCheckout
+	if options.Depth == nil || *options.Depth != 0 || options.Submodules != "recursive" || !options.FetchTags || strings.Join(options.Sparse, ",") != "internal" {
+		t.Fatalf("unexpected persisted checkout options: %+v", options)
+	}
+
+	created, err := s.CreateJobExecution(protocol.CreateJobExecutionRequest{
+		Script: "go build ./...", TimeoutSeconds: 30,
+		Source: &protocol.SourceSpec{Repo: pipeline.SourceRepo, SourceCheckoutOptions: protocol.SourceCheckoutOptions(options)},
+	})
+	if err != nil {
+		t.Fatalf("create job: %v", err)
+	}
+	job, err := s.GetJobExecution(created.ID)
+	if err != nil {
+		t.Fatalf("get job: %v", err)
+	}
+	if job.Source == nil || job.Source.Summary() != "depth=full submodules=recursive lfs=off sparse=internal fetch_tags=on" {
+		t.Fatalf("unexpected job source: %+v", job.Source)
+	}
+
+	plain, err := s.CreateJobExecution(protocol.CreateJobExecutionRequest{Script: "true", TimeoutSeconds: 30, Source: &protocol.SourceSpec{Repo: pipeline.SourceRepo}})
+	if err != nil {
+		t.Fatalf("create plain job: %v", err)
+	}
+	if job, err = s.GetJobExecution(plain.ID); err != nil || job.Source == nil || !job.Source.IsZero() {
+		t.Fatalf("expected the default checkout to round-trip, got %+v err=%v", job.Source, err)
+	}
+}
+
+func TestStorePersistsJobServices(t *testing.T) {
+	s := openTestStore(t)
+	cfg, err := config.Parse([]byte(`
+version: 1
+project:
+  name: ciwi
+pipelines:
+  - id: build
+    jobs:
+      - id: integration
+        timeout_seconds: 30
+        services:
+          - name: postgres
+            image: postgres:16
+            env:
+              POSTGRES_PASSWORD: ci
+            ports: [5432]
+            health_check: pg_isready
+        steps:
+          - run: make integration
+`), "services-config")
+	if err != nil {
+		t.Fatalf("parse config: %v", err)
+	}
+	if err := s.LoadConfig(cfg, "ciwi-project.yaml", "https://github.com/izzyreal/ciwi.git", "main", "ciwi-project.yaml"); err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	pipeline, err := s.GetPipelineByProjectAndID("ciwi", "build")
+	if err != nil {
+		t.Fatalf("get pipeline: %v", err)
+	}
+	services := pipeline.Jobs[0].Services
+	if len(services) != 1 || services[0].Image != "postgres:16" || services[0].Env["POSTGRES_PASSWORD"] != "ci" || services[0].HealthCheck != "pg_isready" {
+		t.Fatalf("unexpected persisted services: %+v", services)
+	}
+
+	created, err := s.CreateJobExecution(protocol.CreateJobExecutionRequest{
+		Script: "make integration", TimeoutSeconds: 30,
+		Services: []protocol.JobServiceSpec{{Name: "postgres", Image: "postgres:16", Ports: []int{5432}, HealthCheck: "pg_isready"}},
+	})
+	if err != nil {
+		t.Fatalf("create job: %v", err)
+	}
+	job, err := s.GetJobExecution(created.ID)
+	if err != nil {
+		t.Fatalf("get job: %v", err)
+	}
+	if len(job.Services) != 1 || job.Services[0].Name != "postgres" || len(job.Services[0].Ports) != 1 || job.Services[0].Ports[0] != 5432 {
+		t.Fatalf("unexpected job services: %+v", job.Services)
+	}
+	leased, err := s.LeaseJobExecution("agent-1", nil)
+	if err != nil || leased == nil || len(leased.Services) != 1 {
+		t.Fatalf("expected the lease to carry the services, got %+v err=%v", leased, err)
+	}
+}
+
 func TestStoreJobQueueAndHistoryOperations(t *testing.T) {
 	s := openTestStore(t)
 
@@ -0,0 +1,122 @@
+package store
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/izzyreal/ciwi/internal/domain"
+	"github.com/izzyreal/ciwi/internal/protocol"
+)
+
+// historyScope is the pipeline job an execution belongs to, as recorded in
+// its metadata when it was queued.
+type historyScope struct {
+	scope      domain.TestHistoryScope
+	metadata   domain.ExecutionMetadata
+	createdUTC string
+}
+
+// jobExecutionHistoryScope reads the scope that test and coverage history
+// correlate runs by. Executions outside a pipeline job, such as ad-hoc agent
+// scripts, have no scope and report ok=false.
+func jobExecutionHistoryScope(tx *sql.Tx, jobID string) (historyScope, bool, error) {
+	var metadataJSON, createdUTC string
+	if err := tx.QueryRow(`SELECT metadata_json, created_utc FROM job_executions WHERE id = ?`, jobID).Scan(&metadataJSON, &createdUTC); err != nil {
+		if err == sql.ErrNoRows {
+			return historyScope{}, false, nil
+		}
+		return historyScope{}, false, fmt.Errorf("read job execution scope: %w", err)
+	}
+	var metadata domain.ExecutionMetadata
+	if strings.TrimSpace(metadataJSON) != "" {
+		if err := json.Unmarshal([]byte(metadataJSON), &metadata); err != nil {
+			return historyScope{}, false, fmt.Errorf("decode job execution metadata: %w", err)
+		}
+	}
+	scope, ok := domain.TestHistoryScopeOf(metadata)
+	if !ok {
+		return historyScope{}, false, nil
+	}
+	return historyScope{
+		scope:      scope,
+		metadata:   metadata,
+		createdUTC: createdUTC,
+	}, true, nil
+}
+
+// indexTestCaseResults replaces the per-case history rows of one execution.
+func indexTestCaseResults(tx *sql.Tx, jobID string, report protocol.JobExecutionTestReport) error {
+	if _, err := tx.Exec(`DELETE FROM test_case_results WHERE job_execution_id = ?`, jobID); err != nil {
+		return fmt.Errorf("clear test case results: %w", err)
+	}
+	history, ok, err := jobExecutionHistoryScope(tx, jobID)
+	if err != nil || !ok {
+		return err
+	}
+	scope, metadata := history.scope, history.metadata
+	attemptRoot := metadata.Value(domain.ExecutionMetadataAttemptRootJobID)
+	if attemptRoot == "" {
+		attemptRoot = jobID
+	}
+	for _, suite := range report.Suites {
+		for _, testCase := range suite.Cases {
+			name := strings.TrimSpace(testCase.Name)
+			if name == "" {
+				continue
+			}
+			if _, err := tx.Exec(`
+				INSERT INTO test_case_results (job_execution_id, project_id, pipeline_id, pipeline_job_id, matrix_name, attempt_root_job_id, source_commit, package, name, status, duration_seconds, created_utc)
+				VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+			`, jobID, scope.ProjectID, scope.PipelineID, scope.PipelineJobID, scope.MatrixName, attemptRoot,
+				metadata.Value(domain.ExecutionMetadataPipelineSourceRefResolved),
+				strings.TrimSpace(testCase.Package), name, strings.ToLower(strings.TrimSpace(testCase.Status)),
+				testCase.DurationSeconds, history.createdUTC); err != nil {
+				return fmt.Errorf("insert test case result: %w", err)
+			}
+		}
+	}
+	return nil
+}
+
+// ListTestCaseRuns returns the test case results of the newest executions in
+// scope, newest first. executionLimit bounds the number of executions, not
+// rows, so every case of an included execution is returned.
+func (s *Store) ListTestCaseRuns(scope domain.TestHistoryScope, executionLimit int) ([]domain.TestCaseRun, error) {
+	if executionLimit <= 0 {
+		executionLimit = 50
+	}
+	rows, err := s.db.Query(`
+		SELECT job_execution_id, attempt_root_job_id, source_commit, package, name, status, duration_seconds, created_utc
+		FROM test_case_results
+		WHERE project_id = ? AND pipeline_id = ? AND pipeline_job_id = ? AND matrix_name = ?
+		  AND job_execution_id IN (
+			SELECT job_execution_id FROM test_case_results
+			WHERE project_id = ? AND pipeline_id = ? AND pipeline_job_id = ? AND matrix_name = ?
+			GROUP BY job_execution_id
+			ORDER BY MAX(created_utc) DESC, job_execution_id DESC
+			LIMIT ?
+		  )
+		ORDER BY created_utc DESC, job_execution_id DESC, id
+	`, scope.ProjectID, scope.PipelineID, scope.PipelineJobID, scope.MatrixName,
+		scope.ProjectID, scope.PipelineID, scope.PipelineJobID, scope.MatrixName, executionLimit)
+	if err != nil {
+		return nil, fmt.Errorf("list test case runs: %w", err)
+	}
+	defer rows.Close()
+	runs := []domain.TestCaseRun{}
+	for rows.Next() {
+		var run domain.TestCaseRun
+		var createdUTC string
+		if err := rows.Scan(&run.JobExecutionID, &run.AttemptRootID, &run.SourceCommit, &run.Package, &run.Name, &run.Status, &run.DurationSeconds, &createdUTC); err != nil {
+			return nil, fmt.Errorf("scan test case run: %w", err)
+		}
+		run.CreatedUTC = parseStoredTime(createdUTC)
+		runs = append(runs, run)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate test case runs: %w", err)
+	}
+	return runs, nil
+}
@@ -0,0 +1,64 @@
+package store
+
+import (
+	"testing"
+
+	"github.com/izzyreal/ciwi/internal/domain"
+	"github.com/izzyreal/ciwi/internal/protocol"
+)
+
+func TestSaveJobExecutionTestReportIndexesCasesByScope(t *testing.T) {
+	s := openTestStore(t)
+	createReportedJob := func(matrix, commit string, cases ...protocol.TestCase) protocol.JobExecution {
+		t.Helper()
+		job, err := s.CreateJobExecution(protocol.CreateJobExecutionRequest{
+			Script: "go test ./...", TimeoutSeconds: 30,
+			Metadata: map[string]string{
+				domain.ExecutionMetadataProjectID:                 "7",
+				domain.ExecutionMetadataPipelineID:                "build",
+				domain.ExecutionMetadataPipelineJobID:             "unit",
+				domain.ExecutionMetadataMatrixName:                matrix,
+				domain.ExecutionMetadataPipelineSourceRefResolved: commit,
+			},
+		})
+		if err != nil {
+			t.Fatalf("create job: %v", err)
+		}
+		report := protocol.JobExecutionTestReport{Suites: []protocol.TestSuiteReport{{Format: "go-test-json", Cases: cases}}}
+		if err := s.SaveJobExecutionTestReport(job.ID, report); err != nil {
+			t.Fatalf("save report: %v", err)
+		}
+		return job
+	}
+	older := createReportedJob("linux", "abc", protocol.TestCase{Package: "p", Name: "TestA", Status: "FAIL", DurationSeconds: 1.5})
+	newer := createReportedJob("linux", "abc",
+		protocol.TestCase{Package: "p", Name: "TestA", Status: "pass"},
+		protocol.TestCase{Package: "p", Name: "", Status: "pass"},
+	)
+	createReportedJob("windows", "abc", protocol.TestCase{Package: "p", Name: "TestA", Status: "fail"})
+
+	runs, err := s.ListTestCaseRuns(domain.TestHistoryScope{ProjectID: 7, PipelineID: "build", PipelineJobID: "unit", MatrixName: "linux"}, 10)
+	if err != nil {
+		t.Fatalf("list runs: %v", err)
+	}
+	if len(runs) != 2 || runs[0].JobExecutionID != newer.ID || runs[1].JobExecutionID != older.ID {
+		t.Fatalf("expected the two linux runs newest first, got %+v", runs)
+	}
+	if runs[1].Status != "fail" || runs[1].DurationSeconds != 1.5 || runs[1].SourceCommit != "abc" || runs[1].AttemptRootID != older.ID {
+		t.Fatalf("unexpected indexed run %+v", runs[1])
+	}
+
+	limited, err := s.ListTestCaseRuns(domain.TestHistoryScope{ProjectID: 7, PipelineID: "build", PipelineJobID: "unit", MatrixName: "linux"}, 1)
+	if err != nil || len(limited) != 1 || limited[0].JobExecutionID != newer.ID {
+		t.Fatalf("expected the newest execution only, got %+v (%v)", limited, err)
+	}
+
+	// Saving a report again replaces the indexed rows of that execution.
+	if err := s.SaveJobExecutionTestReport(older.ID, protocol.JobExecutionTestReport{}); err != nil {
+		t.Fatalf("replace report: %v", err)
+	}
+	runs, _ = s.ListTestCaseRuns(domain.TestHistoryScope{ProjectID: 7, PipelineID: "build", PipelineJobID: "unit", MatrixName: "linux"}, 10)
+	if len(runs) != 1 {
+		t.Fatalf("expected replaced report to drop its rows, got %+v", runs)
+	}
+}
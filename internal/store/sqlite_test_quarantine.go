@@ -0,0 +1,71 @@
+package store
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/izzyreal/ciwi/internal/domain"
+)
+
+const testQuarantineColumns = `id, project_id, package, name, owner, reason, created_by, created_utc, expires_utc`
+
+func (s *Store) CreateTestQuarantineEntry(entry domain.TestQuarantineEntry) (domain.TestQuarantineEntry, error) {
+	created := entry.CreatedUTC
+	if created.IsZero() {
+		created = time.Now().UTC()
+	}
+	result, err := s.db.Exec(`
+		INSERT INTO test_quarantine (project_id, package, name, owner, reason, created_by, created_utc, expires_utc)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	`, entry.ProjectID, strings.TrimSpace(entry.Package), strings.TrimSpace(entry.Name), strings.TrimSpace(entry.Owner),
+		strings.TrimSpace(entry.Reason), strings.TrimSpace(entry.CreatedBy),
+		created.UTC().Format(time.RFC3339Nano), entry.ExpiresUTC.UTC().Format(time.RFC3339Nano))
+	if err != nil {
+		return domain.TestQuarantineEntry{}, fmt.Errorf("create test quarantine entry: %w", err)
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return domain.TestQuarantineEntry{}, fmt.Errorf("create test quarantine entry id: %w", err)
+	}
+	entry.ID = id
+	entry.CreatedUTC = created
+	return entry, nil
+}
+
+// ListTestQuarantineEntries returns the quarantine of a project, expired
+// entries included, soonest expiry first.
+func (s *Store) ListTestQuarantineEntries(projectID int64) ([]domain.TestQuarantineEntry, error) {
+	rows, err := s.db.Query(`SELECT `+testQuarantineColumns+` FROM test_quarantine WHERE project_id = ? ORDER BY expires_utc, id`, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("list test quarantine: %w", err)
+	}
+	defer rows.Close()
+	var out []domain.TestQuarantineEntry
+	for rows.Next() {
+		var entry domain.TestQuarantineEntry
+		var created, expires string
+		if err := rows.Scan(&entry.ID, &entry.ProjectID, &entry.Package, &entry.Name, &entry.Owner, &entry.Reason, &entry.CreatedBy, &created, &expires); err != nil {
+			return nil, fmt.Errorf("scan test quarantine entry: %w", err)
+		}
+		entry.CreatedUTC = parseStoredTime(created)
+		entry.ExpiresUTC = parseStoredTime(expires)
+		out = append(out, entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate test quarantine: %w", err)
+	}
+	return out, nil
+}
+
+func (s *Store) DeleteTestQuarantineEntry(projectID, id int64) (bool, error) {
+	result, err := s.db.Exec(`DELETE FROM test_quarantine WHERE project_id = ? AND id = ?`, projectID, id)
+	if err != nil {
+		return false, fmt.Errorf("delete test quarantine entry: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("delete test quarantine entry rows affected: %w", err)
+	}
+	return rows > 0, nil
+}
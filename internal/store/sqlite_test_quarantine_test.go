@@ -0,0 +1,96 @@
+package store
+
+import (
+	"testing"
+	"time"
+
+	"github.com/izzyreal/ciwi/internal/config"
+	"github.com/izzyreal/ciwi/internal/domain"
+	"github.com/izzyreal/ciwi/internal/protocol"
+)
+
+func TestTestQuarantineEntriesAreScopedToTheirProject(t *testing.T) {
+	s := openTestStore(t)
+	cfg, err := config.Parse([]byte(`
+version: 1
+project:
+  name: flaky
+pipelines:
+  - id: build
+    jobs:
+      - id: unit
+        timeout_seconds: 30
+        steps:
+          - run: go test ./...
+`), "test-quarantine")
+	if err != nil {
+		t.Fatalf("parse config: %v", err)
+	}
+	if err := s.LoadConfig(cfg, "flaky.yaml", "", "", ""); err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	project, err := s.GetProjectByName("flaky")
+	if err != nil {
+		t.Fatalf("get project: %v", err)
+	}
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	later, err := s.CreateTestQuarantineEntry(domain.TestQuarantineEntry{
+		ProjectID: project.ID, Package: "p", Name: "TestLater", Owner: "alice", CreatedUTC: now, ExpiresUTC: now.Add(48 * time.Hour),
+	})
+	if err != nil {
+		t.Fatalf("create entry: %v", err)
+	}
+	sooner, err := s.CreateTestQuarantineEntry(domain.TestQuarantineEntry{
+		ProjectID: project.ID, Name: "TestSooner", Owner: "bob", Reason: "races on CI", CreatedBy: "admin",
+		CreatedUTC: now, ExpiresUTC: now.Add(time.Hour),
+	})
+	if err != nil {
+		t.Fatalf("create entry: %v", err)
+	}
+
+	entries, err := s.ListTestQuarantineEntries(project.ID)
+	if err != nil {
+		t.Fatalf("list entries: %v", err)
+	}
+	if len(entries) != 2 || entries[0].ID != sooner.ID || entries[1].ID != later.ID {
+		t.Fatalf("expected entries soonest expiry first, got %+v", entries)
+	}
+	if got := entries[0]; got.Reason != "races on CI" || got.CreatedBy != "admin" || !got.ExpiresUTC.Equal(now.Add(time.Hour)) {
+		t.Fatalf("unexpected stored entry %+v", got)
+	}
+	if other, err := s.ListTestQuarantineEntries(project.ID + 1); err != nil || len(other) != 0 {
+		t.Fatalf("expected no entries for another project, got %+v (%v)", other, err)
+	}
+
+	if deleted, err := s.DeleteTestQuarantineEntry(project.ID+1, sooner.ID); err != nil || deleted {
+		t.Fatalf("expected delete through another project to miss, deleted=%v err=%v", deleted, err)
+	}
+	if deleted, err := s.DeleteTestQuarantineEntry(project.ID, sooner.ID); err != nil || !deleted {
+		t.Fatalf("expected delete, deleted=%v err=%v", deleted, err)
+	}
+	if err := s.DeleteProjectByID(project.ID); err != nil {
+		t.Fatalf("delete project: %v", err)
+	}
+	if entries, err := s.ListTestQuarantineEntries(project.ID); err != nil || len(entries) != 0 {
+		t.Fatalf("expected project deletion to drop its quarantine, got %+v (%v)", entries, err)
+	}
+}
+
+func TestJobExecutionTestSummaryKeepsQuarantinedFailuresApart(t *testing.T) {
+	s := openTestStore(t)
+	job, err := s.CreateJobExecution(protocol.CreateJobExecutionRequest{Script: "go test ./...", TimeoutSeconds: 30})
+	if err != nil {
+		t.Fatalf("create job: %v", err)
+	}
+	report := protocol.JobExecutionTestReport{Total: 3, Passed: 1, Failed: 1, Quarantined: 1}
+	if err := s.SaveJobExecutionTestReport(job.ID, report); err != nil {
+		t.Fatalf("save test report: %v", err)
+	}
+	summaries, err := s.ListJobExecutionTestSummaries(t.Context(), []string{job.ID})
+	if err != nil {
+		t.Fatalf("list test summaries: %v", err)
+	}
+	if summary := summaries[job.ID]; summary.Failed != 1 || summary.Quarantined != 1 {
+		t.Fatalf("summary = %+v", summary)
+	}
+}
@@ -0,0 +1,138 @@
+package store
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/izzyreal/ciwi/internal/protocol"
+)
+
+// PersistedVCSTrigger is a pipeline with trigger: vcs together with the poll
+// state recorded for the repository and ref it currently watches.
+type PersistedVCSTrigger struct {
+	PipelineDBID int64
+	ProjectName  string
+	PipelineID   string
+	SourceRepo   string
+	SourceRef    string
+	// WatchedRepo and WatchedRef identify what State was observed against.
+	// When they differ from SourceRepo/SourceRef the state is stale.
+	WatchedRepo string
+	WatchedRef  string
+	State       protocol.PipelineVCSTrigger
+}
+
+// VCSPollRecord is the outcome of a single poll of a vcs-triggered pipeline.
+type VCSPollRecord struct {
+	Repo            string
+	Ref             string
+	Commit          string
+	Error           string
+	TriggeredCommit string
+	PolledUTC       time.Time
+}
+
+func (s *Store) ListVCSTriggeredPipelines() ([]PersistedVCSTrigger, error) {
+	rows, err := s.db.Query(`
+		SELECT pl.id, p.name, pl.pipeline_id, pl.source_repo, pl.source_ref, pl.vcs_poll_interval_seconds,
+			st.watched_repo, st.watched_ref, st.last_seen_commit, st.last_polled_utc, st.last_poll_error, st.last_triggered_commit, st.last_triggered_utc
+		FROM pipelines pl
+		JOIN projects p ON p.id = pl.project_id
+		LEFT JOIN pipeline_vcs_poll_state st ON st.pipeline_id = pl.id
+		WHERE pl.trigger_mode = 'vcs' AND TRIM(COALESCE(pl.source_repo, '')) <> ''
+		ORDER BY pl.id
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("list vcs triggered pipelines: %w", err)
+	}
+	defer rows.Close()
+	var out []PersistedVCSTrigger
+	for rows.Next() {
+		var t PersistedVCSTrigger
+		var sourceRepo, sourceRef sql.NullString
+		var state vcsPollStateColumns
+		if err := rows.Scan(&t.PipelineDBID, &t.ProjectName, &t.PipelineID, &sourceRepo, &sourceRef, &t.State.PollIntervalSeconds,
+			&state.watchedRepo, &state.watchedRef, &state.lastSeenCommit, &state.lastPolledUTC, &state.lastPollError, &state.lastTriggeredCommit, &state.lastTriggeredUTC); err != nil {
+			return nil, fmt.Errorf("scan vcs triggered pipeline: %w", err)
+		}
+		t.SourceRepo = strings.TrimSpace(sourceRepo.String)
+		t.SourceRef = strings.TrimSpace(sourceRef.String)
+		t.WatchedRepo = state.watchedRepo.String
+		t.WatchedRef = state.watchedRef.String
+		state.applyTo(&t.State)
+		out = append(out, t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate vcs triggered pipelines: %w", err)
+	}
+	return out, nil
+}
+
+// RecordVCSPoll stores the outcome of a poll. A failed poll keeps the last
+// seen commit so that the next successful poll still detects the change,
+// unless the watched repository or ref changed in the meantime.
+func (s *Store) RecordVCSPoll(pipelineDBID int64, rec VCSPollRecord) error {
+	polled := rec.PolledUTC
+	if polled.IsZero() {
+		polled = time.Now().UTC()
+	}
+	var triggeredUTC sql.NullString
+	if strings.TrimSpace(rec.TriggeredCommit) != "" {
+		triggeredUTC = nullableTime(polled)
+	}
+	if _, err := s.db.Exec(`
+		INSERT INTO pipeline_vcs_poll_state (pipeline_id, watched_repo, watched_ref, last_seen_commit, last_polled_utc, last_poll_error, last_triggered_commit, last_triggered_utc)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(pipeline_id) DO UPDATE SET
+			last_seen_commit = CASE
+				WHEN excluded.last_seen_commit <> '' THEN excluded.last_seen_commit
+				WHEN excluded.watched_repo <> pipeline_vcs_poll_state.watched_repo OR excluded.watched_ref <> pipeline_vcs_poll_state.watched_ref THEN ''
+				ELSE pipeline_vcs_poll_state.last_seen_commit END,
+			watched_repo = excluded.watched_repo,
+			watched_ref = excluded.watched_ref,
+			last_polled_utc = excluded.last_polled_utc,
+			last_poll_error = excluded.last_poll_error,
+			last_triggered_commit = CASE WHEN excluded.last_triggered_commit <> '' THEN excluded.last_triggered_commit ELSE pipeline_vcs_poll_state.last_triggered_commit END,
+			last_triggered_utc = COALESCE(excluded.last_triggered_utc, pipeline_vcs_poll_state.last_triggered_utc)
+	`, pipelineDBID, strings.TrimSpace(rec.Repo), strings.TrimSpace(rec.Ref), strings.TrimSpace(rec.Commit), nullableTime(polled),
+		strings.TrimSpace(rec.Error), strings.TrimSpace(rec.TriggeredCommit), triggeredUTC); err != nil {
+		return fmt.Errorf("record vcs poll: %w", err)
+	}
+	return nil
+}
+
+func (s *Store) getPipelineVCSTrigger(pipelineDBID int64, pollIntervalSeconds int) (*protocol.PipelineVCSTrigger, error) {
+	out := &protocol.PipelineVCSTrigger{PollIntervalSeconds: pollIntervalSeconds}
+	var state vcsPollStateColumns
+	err := s.db.QueryRow(`
+		SELECT watched_repo, watched_ref, last_seen_commit, last_polled_utc, last_poll_error, last_triggered_commit, last_triggered_utc
+		FROM pipeline_vcs_poll_state WHERE pipeline_id = ?
+	`, pipelineDBID).Scan(&state.watchedRepo, &state.watchedRef, &state.lastSeenCommit, &state.lastPolledUTC, &state.lastPollError, &state.lastTriggeredCommit, &state.lastTriggeredUTC)
+	if err == sql.ErrNoRows {
+		return out, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get vcs poll state: %w", err)
+	}
+	state.applyTo(out)
+	return out, nil
+}
+
+type vcsPollStateColumns struct {
+	watchedRepo, watchedRef, lastSeenCommit, lastPollError, lastTriggeredCommit sql.NullString
+	lastPolledUTC, lastTriggeredUTC                                             sql.NullString
+}
+
+func (c vcsPollStateColumns) applyTo(out *protocol.PipelineVCSTrigger) {
+	out.LastSeenCommit = c.lastSeenCommit.String
+	out.LastPollError = c.lastPollError.String
+	out.LastTriggeredCommit = c.lastTriggeredCommit.String
+	if c.lastPolledUTC.Valid {
+		out.LastPolledUTC, _ = time.Parse(time.RFC3339Nano, c.lastPolledUTC.String)
+	}
+	if c.lastTriggeredUTC.Valid {
+		out.LastTriggeredUTC, _ = time.Parse(time.RFC3339Nano, c.lastTriggeredUTC.String)
+	}
+}
@@ -0,0 +1,83 @@
+package store
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/izzyreal/ciwi/internal/config"
+)
+
+func loadVCSTriggerConfig(t *testing.T, s *Store, ref string) {
+	t.Helper()
+	raw := strings.Replace(testConfigYAML, "trigger: manual", "trigger: vcs", 1)
+	raw = strings.Replace(raw, "      ref: main\n", "      ref: "+ref+"\n      poll_interval_seconds: 30\n", 1)
+	cfg, err := config.Parse([]byte(raw), "vcs-trigger")
+	if err != nil {
+		t.Fatalf("parse config: %v", err)
+	}
+	if err := s.LoadConfig(cfg, "ciwi-project.yaml", "https://github.com/izzyreal/ciwi.git", "main", "ciwi-project.yaml"); err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+}
+
+func TestStoreRecordsVCSPollState(t *testing.T) {
+	s := openTestStore(t)
+	loadVCSTriggerConfig(t, s, "main")
+
+	triggers, err := s.ListVCSTriggeredPipelines()
+	if err != nil {
+		t.Fatalf("list vcs triggers: %v", err)
+	}
+	if len(triggers) != 1 || triggers[0].PipelineID != "build" || triggers[0].SourceRef != "main" || triggers[0].State.PollIntervalSeconds != 30 {
+		t.Fatalf("unexpected vcs triggers: %+v", triggers)
+	}
+	if triggers[0].State.LastSeenCommit != "" || !triggers[0].State.LastPolledUTC.IsZero() {
+		t.Fatalf("expected empty initial poll state, got %+v", triggers[0].State)
+	}
+	pipelineDBID := triggers[0].PipelineDBID
+	polled := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	if err := s.RecordVCSPoll(pipelineDBID, VCSPollRecord{Repo: triggers[0].SourceRepo, Ref: "main", Commit: "aaa", TriggeredCommit: "aaa", PolledUTC: polled}); err != nil {
+		t.Fatalf("record poll: %v", err)
+	}
+	if err := s.RecordVCSPoll(pipelineDBID, VCSPollRecord{Repo: triggers[0].SourceRepo, Ref: "main", Error: "ls-remote failed", PolledUTC: polled.Add(time.Minute)}); err != nil {
+		t.Fatalf("record failed poll: %v", err)
+	}
+
+	detail, err := s.GetProjectDetail(mustProjectIDByName(t, s, "ciwi"))
+	if err != nil {
+		t.Fatalf("get project detail: %v", err)
+	}
+	state := detail.Pipelines[0].VCSTrigger
+	if state == nil {
+		t.Fatalf("expected vcs trigger state on pipeline detail")
+	}
+	if state.LastSeenCommit != "aaa" || state.LastPollError != "ls-remote failed" || state.PollIntervalSeconds != 30 {
+		t.Fatalf("unexpected poll state: %+v", state)
+	}
+	if state.LastTriggeredCommit != "aaa" || !state.LastTriggeredUTC.Equal(polled) || !state.LastPolledUTC.Equal(polled.Add(time.Minute)) {
+		t.Fatalf("unexpected poll timestamps: %+v", state)
+	}
+
+	// Pointing the pipeline at another ref invalidates the baseline commit.
+	loadVCSTriggerConfig(t, s, "release")
+	if err := s.RecordVCSPoll(pipelineDBID, VCSPollRecord{Repo: triggers[0].SourceRepo, Ref: "release", Error: "unreachable"}); err != nil {
+		t.Fatalf("record poll after ref change: %v", err)
+	}
+	triggers, err = s.ListVCSTriggeredPipelines()
+	if err != nil {
+		t.Fatalf("list vcs triggers: %v", err)
+	}
+	if len(triggers) != 1 || triggers[0].PipelineDBID != pipelineDBID || triggers[0].State.LastSeenCommit != "" || triggers[0].WatchedRef != "release" {
+		t.Fatalf("expected baseline reset after ref change, got %+v", triggers)
+	}
+}
+
+func mustProjectIDByName(t *testing.T, s *Store, name string) int64 {
+	t.Helper()
+	project, err := s.GetProjectByName(name)
+	if err != nil {
+		t.Fatalf("get project %q: %v", name, err)
+	}
+	return project.ID
+}
@@ -32,6 +32,8 @@ const (
 	StatusCode_STATUS_CODE_UNAVAILABLE         StatusCode = 5
 	StatusCode_STATUS_CODE_UNSUPPORTED         StatusCode = 6
 	StatusCode_STATUS_CODE_INTERNAL            StatusCode = 7
+	StatusCode_STATUS_CODE_UNAUTHENTICATED     StatusCode = 8
+	StatusCode_STATUS_CODE_PERMISSION_DENIED   StatusCode = 9
 )
 
 // Enum value maps for StatusCode.
@@ -45,6 +47,8 @@ var (
 		5: "STATUS_CODE_UNAVAILABLE",
 		6: "STATUS_CODE_UNSUPPORTED",
 		7: "STATUS_CODE_INTERNAL",
+		8: "STATUS_CODE_UNAUTHENTICATED",
+		9: "STATUS_CODE_PERMISSION_DENIED",
 	}
 	StatusCode_value = map[string]int32{
 		"STATUS_CODE_UNSPECIFIED":         0,
@@ -55,6 +59,8 @@ var (
 		"STATUS_CODE_UNAVAILABLE":         5,
 		"STATUS_CODE_UNSUPPORTED":         6,
 		"STATUS_CODE_INTERNAL":            7,
+		"STATUS_CODE_UNAUTHENTICATED":     8,
+		"STATUS_CODE_PERMISSION_DENIED":   9,
 	}
 )
 
@@ -254,6 +260,7 @@ type Hello struct {
 	ClientName    string                 `protobuf:"bytes,1,opt,name=client_name,json=clientName,proto3" json:"client_name,omitempty"`
 	ClientVersion string                 `protobuf:"bytes,2,opt,name=client_version,json=clientVersion,proto3" json:"client_version,omitempty"`
 	Capabilities  []string               `protobuf:"bytes,3,rep,name=capabilities,proto3" json:"capabilities,omitempty"`
+	ApiToken      string                 `protobuf:"bytes,4,opt,name=api_token,json=apiToken,proto3" json:"api_token,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -309,6 +316,13 @@ func (x *Hello) GetCapabilities() []string {
 	return nil
 }
 
+func (x *Hello) GetApiToken() string {
+	if x != nil {
+		return x.ApiToken
+	}
+	return ""
+}
+
 type Welcome struct {
 	state                protoimpl.MessageState `protogen:"open.v1"`
 	ServerName           string                 `protobuf:"bytes,1,opt,name=server_name,json=serverName,proto3" json:"server_name,omitempty"`
@@ -1289,6 +1303,9 @@ type ProjectDetailsView struct {
 	ProjectIcon            []byte                    `protobuf:"bytes,4,opt,name=project_icon,json=projectIcon,proto3" json:"project_icon,omitempty"`
 	ProjectIconContentType string                    `protobuf:"bytes,5,opt,name=project_icon_content_type,json=projectIconContentType,proto3" json:"project_icon_content_type,omitempty"`
 	StructureFilters       []*ProjectStructureFilter `protobuf:"bytes,6,rep,name=structure_filters,json=structureFilters,proto3" json:"structure_filters,omitempty"`
+	TestQuarantine         []*TestQuarantineEntry    `protobuf:"bytes,7,rep,name=test_quarantine,json=testQuarantine,proto3" json:"test_quarantine,omitempty"`
+	TestQuarantineNotices  []string                  `protobuf:"bytes,8,rep,name=test_quarantine_notices,json=testQuarantineNotices,proto3" json:"test_quarantine_notices,omitempty"`
+	CoverageTrends         []*CoverageTrend          `protobuf:"bytes,9,rep,name=coverage_trends,json=coverageTrends,proto3" json:"coverage_trends,omitempty"`
 	unknownFields          protoimpl.UnknownFields
 	sizeCache              protoimpl.SizeCache
 }
@@ -1365,32 +1382,52 @@ func (x *ProjectDetailsView) GetStructureFilters() []*ProjectStructureFilter {
 	return nil
 }
 
-type ProjectStructureFilter struct {
-	state                 protoimpl.MessageState `protogen:"open.v1"`
-	Value                 string                 `protobuf:"bytes,1,opt,name=value,proto3" json:"value,omitempty"`
-	Label                 string                 `protobuf:"bytes,2,opt,name=label,proto3" json:"label,omitempty"`
-	PipelineIds           []string               `protobuf:"bytes,3,rep,name=pipeline_ids,json=pipelineIds,proto3" json:"pipeline_ids,omitempty"`
-	Root                  *ProjectStructureRoot  `protobuf:"bytes,4,opt,name=root,proto3" json:"root,omitempty"`
-	ShowChainStructure    bool                   `protobuf:"varint,5,opt,name=show_chain_structure,json=showChainStructure,proto3" json:"show_chain_structure,omitempty"`
-	ShowPipelineStructure bool                   `protobuf:"varint,6,opt,name=show_pipeline_structure,json=showPipelineStructure,proto3" json:"show_pipeline_structure,omitempty"`
-	unknownFields         protoimpl.UnknownFields
-	sizeCache             protoimpl.SizeCache
+func (x *ProjectDetailsView) GetTestQuarantine() []*TestQuarantineEntry {
+	if x != nil {
+		return x.TestQuarantine
+	}
+	return nil
 }
 
-func (x *ProjectStructureFilter) Reset() {
-	*x = ProjectStructureFilter{}
+func (x *ProjectDetailsView) GetTestQuarantineNotices() []string {
+	if x != nil {
+		return x.TestQuarantineNotices
+	}
+	return nil
+}
+
+func (x *ProjectDetailsView) GetCoverageTrends() []*CoverageTrend {
+	if x != nil {
+		return x.CoverageTrends
+	}
+	return nil
+}
+
+type CoverageTrend struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Key           string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Label         string                 `protobuf:"bytes,2,opt,name=label,proto3" json:"label,omitempty"`
+	Summary       string                 `protobuf:"bytes,3,opt,name=summary,proto3" json:"summary,omitempty"`
+	Tone          string                 `protobuf:"bytes,4,opt,name=tone,proto3" json:"tone,omitempty"`
+	Points        []*CoverageTrendPoint  `protobuf:"bytes,5,rep,name=points,proto3" json:"points,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CoverageTrend) Reset() {
+	*x = CoverageTrend{}
 	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[16]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ProjectStructureFilter) String() string {
+func (x *CoverageTrend) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ProjectStructureFilter) ProtoMessage() {}
+func (*CoverageTrend) ProtoMessage() {}
 
-func (x *ProjectStructureFilter) ProtoReflect() protoreflect.Message {
+func (x *CoverageTrend) ProtoReflect() protoreflect.Message {
 	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[16]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1402,79 +1439,72 @@ func (x *ProjectStructureFilter) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ProjectStructureFilter.ProtoReflect.Descriptor instead.
-func (*ProjectStructureFilter) Descriptor() ([]byte, []int) {
+// Deprecated: Use CoverageTrend.ProtoReflect.Descriptor instead.
+func (*CoverageTrend) Descriptor() ([]byte, []int) {
 	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{16}
 }
 
-func (x *ProjectStructureFilter) GetValue() string {
+func (x *CoverageTrend) GetKey() string {
 	if x != nil {
-		return x.Value
+		return x.Key
 	}
 	return ""
 }
 
-func (x *ProjectStructureFilter) GetLabel() string {
+func (x *CoverageTrend) GetLabel() string {
 	if x != nil {
 		return x.Label
 	}
 	return ""
 }
 
-func (x *ProjectStructureFilter) GetPipelineIds() []string {
-	if x != nil {
-		return x.PipelineIds
-	}
-	return nil
-}
-
-func (x *ProjectStructureFilter) GetRoot() *ProjectStructureRoot {
+func (x *CoverageTrend) GetSummary() string {
 	if x != nil {
-		return x.Root
+		return x.Summary
 	}
-	return nil
+	return ""
 }
 
-func (x *ProjectStructureFilter) GetShowChainStructure() bool {
+func (x *CoverageTrend) GetTone() string {
 	if x != nil {
-		return x.ShowChainStructure
+		return x.Tone
 	}
-	return false
+	return ""
 }
 
-func (x *ProjectStructureFilter) GetShowPipelineStructure() bool {
+func (x *CoverageTrend) GetPoints() []*CoverageTrendPoint {
 	if x != nil {
-		return x.ShowPipelineStructure
+		return x.Points
 	}
-	return false
+	return nil
 }
 
-type ProjectStructureRoot struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	Label         string                 `protobuf:"bytes,2,opt,name=label,proto3" json:"label,omitempty"`
-	Meta          string                 `protobuf:"bytes,3,opt,name=meta,proto3" json:"meta,omitempty"`
-	Runnable      bool                   `protobuf:"varint,4,opt,name=runnable,proto3" json:"runnable,omitempty"`
-	ProjectId     int64                  `protobuf:"varint,5,opt,name=project_id,json=projectId,proto3" json:"project_id,omitempty"`
-	ChainId       string                 `protobuf:"bytes,6,opt,name=chain_id,json=chainId,proto3" json:"chain_id,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+type CoverageTrendPoint struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	JobExecutionId string                 `protobuf:"bytes,1,opt,name=job_execution_id,json=jobExecutionId,proto3" json:"job_execution_id,omitempty"`
+	PercentLabel   string                 `protobuf:"bytes,2,opt,name=percent_label,json=percentLabel,proto3" json:"percent_label,omitempty"`
+	DeltaLabel     string                 `protobuf:"bytes,3,opt,name=delta_label,json=deltaLabel,proto3" json:"delta_label,omitempty"`
+	DateLabel      string                 `protobuf:"bytes,4,opt,name=date_label,json=dateLabel,proto3" json:"date_label,omitempty"`
+	Tone           string                 `protobuf:"bytes,5,opt,name=tone,proto3" json:"tone,omitempty"`
+	Progress       *Progress              `protobuf:"bytes,6,opt,name=progress,proto3" json:"progress,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
 }
 
-func (x *ProjectStructureRoot) Reset() {
-	*x = ProjectStructureRoot{}
+func (x *CoverageTrendPoint) Reset() {
+	*x = CoverageTrendPoint{}
 	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[17]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ProjectStructureRoot) String() string {
+func (x *CoverageTrendPoint) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ProjectStructureRoot) ProtoMessage() {}
+func (*CoverageTrendPoint) ProtoMessage() {}
 
-func (x *ProjectStructureRoot) ProtoReflect() protoreflect.Message {
+func (x *CoverageTrendPoint) ProtoReflect() protoreflect.Message {
 	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[17]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1486,83 +1516,83 @@ func (x *ProjectStructureRoot) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ProjectStructureRoot.ProtoReflect.Descriptor instead.
-func (*ProjectStructureRoot) Descriptor() ([]byte, []int) {
+// Deprecated: Use CoverageTrendPoint.ProtoReflect.Descriptor instead.
+func (*CoverageTrendPoint) Descriptor() ([]byte, []int) {
 	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{17}
 }
 
-func (x *ProjectStructureRoot) GetId() string {
+func (x *CoverageTrendPoint) GetJobExecutionId() string {
 	if x != nil {
-		return x.Id
+		return x.JobExecutionId
 	}
 	return ""
 }
 
-func (x *ProjectStructureRoot) GetLabel() string {
+func (x *CoverageTrendPoint) GetPercentLabel() string {
 	if x != nil {
-		return x.Label
+		return x.PercentLabel
 	}
 	return ""
 }
 
-func (x *ProjectStructureRoot) GetMeta() string {
+func (x *CoverageTrendPoint) GetDeltaLabel() string {
 	if x != nil {
-		return x.Meta
+		return x.DeltaLabel
 	}
 	return ""
 }
 
-func (x *ProjectStructureRoot) GetRunnable() bool {
+func (x *CoverageTrendPoint) GetDateLabel() string {
 	if x != nil {
-		return x.Runnable
+		return x.DateLabel
 	}
-	return false
+	return ""
 }
 
-func (x *ProjectStructureRoot) GetProjectId() int64 {
+func (x *CoverageTrendPoint) GetTone() string {
 	if x != nil {
-		return x.ProjectId
+		return x.Tone
 	}
-	return 0
+	return ""
 }
 
-func (x *ProjectStructureRoot) GetChainId() string {
+func (x *CoverageTrendPoint) GetProgress() *Progress {
 	if x != nil {
-		return x.ChainId
+		return x.Progress
 	}
-	return ""
+	return nil
 }
 
-type ProjectPipelineDetails struct {
-	state             protoimpl.MessageState `protogen:"open.v1"`
-	Id                int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
-	PipelineId        string                 `protobuf:"bytes,2,opt,name=pipeline_id,json=pipelineId,proto3" json:"pipeline_id,omitempty"`
-	Trigger           string                 `protobuf:"bytes,3,opt,name=trigger,proto3" json:"trigger,omitempty"`
-	DependsOn         []string               `protobuf:"bytes,4,rep,name=depends_on,json=dependsOn,proto3" json:"depends_on,omitempty"`
-	Dependencies      string                 `protobuf:"bytes,5,opt,name=dependencies,proto3" json:"dependencies,omitempty"`
-	JobsCount         uint32                 `protobuf:"varint,6,opt,name=jobs_count,json=jobsCount,proto3" json:"jobs_count,omitempty"`
-	SupportsDryRun    bool                   `protobuf:"varint,7,opt,name=supports_dry_run,json=supportsDryRun,proto3" json:"supports_dry_run,omitempty"`
-	Jobs              []*ProjectJobDetails   `protobuf:"bytes,8,rep,name=jobs,proto3" json:"jobs,omitempty"`
-	SummaryLabel      string                 `protobuf:"bytes,9,opt,name=summary_label,json=summaryLabel,proto3" json:"summary_label,omitempty"`
-	GraphSummaryLabel string                 `protobuf:"bytes,10,opt,name=graph_summary_label,json=graphSummaryLabel,proto3" json:"graph_summary_label,omitempty"`
-	unknownFields     protoimpl.UnknownFields
-	sizeCache         protoimpl.SizeCache
+type TestQuarantineEntry struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	ProjectId     int64                  `protobuf:"varint,2,opt,name=project_id,json=projectId,proto3" json:"project_id,omitempty"`
+	Package       string                 `protobuf:"bytes,3,opt,name=package,proto3" json:"package,omitempty"`
+	Name          string                 `protobuf:"bytes,4,opt,name=name,proto3" json:"name,omitempty"`
+	TestLabel     string                 `protobuf:"bytes,5,opt,name=test_label,json=testLabel,proto3" json:"test_label,omitempty"`
+	Owner         string                 `protobuf:"bytes,6,opt,name=owner,proto3" json:"owner,omitempty"`
+	Reason        string                 `protobuf:"bytes,7,opt,name=reason,proto3" json:"reason,omitempty"`
+	ExpiresLabel  string                 `protobuf:"bytes,8,opt,name=expires_label,json=expiresLabel,proto3" json:"expires_label,omitempty"`
+	Expired       bool                   `protobuf:"varint,9,opt,name=expired,proto3" json:"expired,omitempty"`
+	Tone          string                 `protobuf:"bytes,10,opt,name=tone,proto3" json:"tone,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ProjectPipelineDetails) Reset() {
-	*x = ProjectPipelineDetails{}
+func (x *TestQuarantineEntry) Reset() {
+	*x = TestQuarantineEntry{}
 	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[18]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ProjectPipelineDetails) String() string {
+func (x *TestQuarantineEntry) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ProjectPipelineDetails) ProtoMessage() {}
+func (*TestQuarantineEntry) ProtoMessage() {}
 
-func (x *ProjectPipelineDetails) ProtoReflect() protoreflect.Message {
+func (x *TestQuarantineEntry) ProtoReflect() protoreflect.Message {
 	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[18]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1574,114 +1604,107 @@ func (x *ProjectPipelineDetails) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ProjectPipelineDetails.ProtoReflect.Descriptor instead.
-func (*ProjectPipelineDetails) Descriptor() ([]byte, []int) {
+// Deprecated: Use TestQuarantineEntry.ProtoReflect.Descriptor instead.
+func (*TestQuarantineEntry) Descriptor() ([]byte, []int) {
 	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{18}
 }
 
-func (x *ProjectPipelineDetails) GetId() int64 {
+func (x *TestQuarantineEntry) GetId() int64 {
 	if x != nil {
 		return x.Id
 	}
 	return 0
 }
 
-func (x *ProjectPipelineDetails) GetPipelineId() string {
+func (x *TestQuarantineEntry) GetProjectId() int64 {
 	if x != nil {
-		return x.PipelineId
+		return x.ProjectId
 	}
-	return ""
+	return 0
 }
 
-func (x *ProjectPipelineDetails) GetTrigger() string {
+func (x *TestQuarantineEntry) GetPackage() string {
 	if x != nil {
-		return x.Trigger
+		return x.Package
 	}
 	return ""
 }
 
-func (x *ProjectPipelineDetails) GetDependsOn() []string {
+func (x *TestQuarantineEntry) GetName() string {
 	if x != nil {
-		return x.DependsOn
+		return x.Name
 	}
-	return nil
+	return ""
 }
 
-func (x *ProjectPipelineDetails) GetDependencies() string {
+func (x *TestQuarantineEntry) GetTestLabel() string {
 	if x != nil {
-		return x.Dependencies
+		return x.TestLabel
 	}
 	return ""
 }
 
-func (x *ProjectPipelineDetails) GetJobsCount() uint32 {
+func (x *TestQuarantineEntry) GetOwner() string {
 	if x != nil {
-		return x.JobsCount
+		return x.Owner
 	}
-	return 0
+	return ""
 }
 
-func (x *ProjectPipelineDetails) GetSupportsDryRun() bool {
+func (x *TestQuarantineEntry) GetReason() string {
 	if x != nil {
-		return x.SupportsDryRun
+		return x.Reason
 	}
-	return false
+	return ""
 }
 
-func (x *ProjectPipelineDetails) GetJobs() []*ProjectJobDetails {
+func (x *TestQuarantineEntry) GetExpiresLabel() string {
 	if x != nil {
-		return x.Jobs
+		return x.ExpiresLabel
 	}
-	return nil
+	return ""
 }
 
-func (x *ProjectPipelineDetails) GetSummaryLabel() string {
+func (x *TestQuarantineEntry) GetExpired() bool {
 	if x != nil {
-		return x.SummaryLabel
+		return x.Expired
 	}
-	return ""
+	return false
 }
 
-func (x *ProjectPipelineDetails) GetGraphSummaryLabel() string {
+func (x *TestQuarantineEntry) GetTone() string {
 	if x != nil {
-		return x.GraphSummaryLabel
+		return x.Tone
 	}
 	return ""
 }
 
-type ProjectJobDetails struct {
-	state          protoimpl.MessageState `protogen:"open.v1"`
-	Id             string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	Needs          []string               `protobuf:"bytes,2,rep,name=needs,proto3" json:"needs,omitempty"`
-	NeedsLabel     string                 `protobuf:"bytes,3,opt,name=needs_label,json=needsLabel,proto3" json:"needs_label,omitempty"`
-	RunsOnLabel    string                 `protobuf:"bytes,4,opt,name=runs_on_label,json=runsOnLabel,proto3" json:"runs_on_label,omitempty"`
-	ToolsLabel     string                 `protobuf:"bytes,5,opt,name=tools_label,json=toolsLabel,proto3" json:"tools_label,omitempty"`
-	TimeoutSeconds uint32                 `protobuf:"varint,6,opt,name=timeout_seconds,json=timeoutSeconds,proto3" json:"timeout_seconds,omitempty"`
-	MatrixCount    uint32                 `protobuf:"varint,7,opt,name=matrix_count,json=matrixCount,proto3" json:"matrix_count,omitempty"`
-	StepsCount     uint32                 `protobuf:"varint,8,opt,name=steps_count,json=stepsCount,proto3" json:"steps_count,omitempty"`
-	Steps          []*ProjectStepDetails  `protobuf:"bytes,9,rep,name=steps,proto3" json:"steps,omitempty"`
-	SupportsDryRun bool                   `protobuf:"varint,10,opt,name=supports_dry_run,json=supportsDryRun,proto3" json:"supports_dry_run,omitempty"`
-	SummaryLabel   string                 `protobuf:"bytes,11,opt,name=summary_label,json=summaryLabel,proto3" json:"summary_label,omitempty"`
-	TimeoutLabel   string                 `protobuf:"bytes,12,opt,name=timeout_label,json=timeoutLabel,proto3" json:"timeout_label,omitempty"`
-	MatrixLabel    string                 `protobuf:"bytes,13,opt,name=matrix_label,json=matrixLabel,proto3" json:"matrix_label,omitempty"`
-	unknownFields  protoimpl.UnknownFields
-	sizeCache      protoimpl.SizeCache
+type AddTestQuarantineRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ProjectId     int64                  `protobuf:"varint,1,opt,name=project_id,json=projectId,proto3" json:"project_id,omitempty"`
+	Package       string                 `protobuf:"bytes,2,opt,name=package,proto3" json:"package,omitempty"`
+	Name          string                 `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	Owner         string                 `protobuf:"bytes,4,opt,name=owner,proto3" json:"owner,omitempty"`
+	Reason        string                 `protobuf:"bytes,5,opt,name=reason,proto3" json:"reason,omitempty"`
+	Expires       string                 `protobuf:"bytes,6,opt,name=expires,proto3" json:"expires,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ProjectJobDetails) Reset() {
-	*x = ProjectJobDetails{}
+func (x *AddTestQuarantineRequest) Reset() {
+	*x = AddTestQuarantineRequest{}
 	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[19]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ProjectJobDetails) String() string {
+func (x *AddTestQuarantineRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ProjectJobDetails) ProtoMessage() {}
+func (*AddTestQuarantineRequest) ProtoMessage() {}
 
-func (x *ProjectJobDetails) ProtoReflect() protoreflect.Message {
+func (x *AddTestQuarantineRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[19]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1693,131 +1716,184 @@ func (x *ProjectJobDetails) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ProjectJobDetails.ProtoReflect.Descriptor instead.
-func (*ProjectJobDetails) Descriptor() ([]byte, []int) {
+// Deprecated: Use AddTestQuarantineRequest.ProtoReflect.Descriptor instead.
+func (*AddTestQuarantineRequest) Descriptor() ([]byte, []int) {
 	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{19}
 }
 
-func (x *ProjectJobDetails) GetId() string {
+func (x *AddTestQuarantineRequest) GetProjectId() int64 {
 	if x != nil {
-		return x.Id
+		return x.ProjectId
 	}
-	return ""
+	return 0
 }
 
-func (x *ProjectJobDetails) GetNeeds() []string {
+func (x *AddTestQuarantineRequest) GetPackage() string {
 	if x != nil {
-		return x.Needs
+		return x.Package
 	}
-	return nil
+	return ""
 }
 
-func (x *ProjectJobDetails) GetNeedsLabel() string {
+func (x *AddTestQuarantineRequest) GetName() string {
 	if x != nil {
-		return x.NeedsLabel
+		return x.Name
 	}
 	return ""
 }
 
-func (x *ProjectJobDetails) GetRunsOnLabel() string {
+func (x *AddTestQuarantineRequest) GetOwner() string {
 	if x != nil {
-		return x.RunsOnLabel
+		return x.Owner
 	}
 	return ""
 }
 
-func (x *ProjectJobDetails) GetToolsLabel() string {
+func (x *AddTestQuarantineRequest) GetReason() string {
 	if x != nil {
-		return x.ToolsLabel
+		return x.Reason
 	}
 	return ""
 }
 
-func (x *ProjectJobDetails) GetTimeoutSeconds() uint32 {
+func (x *AddTestQuarantineRequest) GetExpires() string {
 	if x != nil {
-		return x.TimeoutSeconds
+		return x.Expires
 	}
-	return 0
+	return ""
 }
 
-func (x *ProjectJobDetails) GetMatrixCount() uint32 {
+type RemoveTestQuarantineRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ProjectId     int64                  `protobuf:"varint,1,opt,name=project_id,json=projectId,proto3" json:"project_id,omitempty"`
+	Id            int64                  `protobuf:"varint,2,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RemoveTestQuarantineRequest) Reset() {
+	*x = RemoveTestQuarantineRequest{}
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RemoveTestQuarantineRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RemoveTestQuarantineRequest) ProtoMessage() {}
+
+func (x *RemoveTestQuarantineRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[20]
 	if x != nil {
-		return x.MatrixCount
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return 0
+	return mi.MessageOf(x)
 }
 
-func (x *ProjectJobDetails) GetStepsCount() uint32 {
+// Deprecated: Use RemoveTestQuarantineRequest.ProtoReflect.Descriptor instead.
+func (*RemoveTestQuarantineRequest) Descriptor() ([]byte, []int) {
+	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *RemoveTestQuarantineRequest) GetProjectId() int64 {
 	if x != nil {
-		return x.StepsCount
+		return x.ProjectId
 	}
 	return 0
 }
 
-func (x *ProjectJobDetails) GetSteps() []*ProjectStepDetails {
+func (x *RemoveTestQuarantineRequest) GetId() int64 {
 	if x != nil {
-		return x.Steps
+		return x.Id
 	}
-	return nil
+	return 0
 }
 
-func (x *ProjectJobDetails) GetSupportsDryRun() bool {
-	if x != nil {
-		return x.SupportsDryRun
-	}
-	return false
+type RemoveTestQuarantineResult struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ProjectId     int64                  `protobuf:"varint,1,opt,name=project_id,json=projectId,proto3" json:"project_id,omitempty"`
+	Id            int64                  `protobuf:"varint,2,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ProjectJobDetails) GetSummaryLabel() string {
+func (x *RemoveTestQuarantineResult) Reset() {
+	*x = RemoveTestQuarantineResult{}
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RemoveTestQuarantineResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RemoveTestQuarantineResult) ProtoMessage() {}
+
+func (x *RemoveTestQuarantineResult) ProtoReflect() protoreflect.Message {
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[21]
 	if x != nil {
-		return x.SummaryLabel
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return ""
+	return mi.MessageOf(x)
 }
 
-func (x *ProjectJobDetails) GetTimeoutLabel() string {
+// Deprecated: Use RemoveTestQuarantineResult.ProtoReflect.Descriptor instead.
+func (*RemoveTestQuarantineResult) Descriptor() ([]byte, []int) {
+	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *RemoveTestQuarantineResult) GetProjectId() int64 {
 	if x != nil {
-		return x.TimeoutLabel
+		return x.ProjectId
 	}
-	return ""
+	return 0
 }
 
-func (x *ProjectJobDetails) GetMatrixLabel() string {
+func (x *RemoveTestQuarantineResult) GetId() int64 {
 	if x != nil {
-		return x.MatrixLabel
+		return x.Id
 	}
-	return ""
+	return 0
 }
 
-type ProjectStepDetails struct {
-	state            protoimpl.MessageState `protogen:"open.v1"`
-	Index            uint32                 `protobuf:"varint,1,opt,name=index,proto3" json:"index,omitempty"`
-	Position         uint32                 `protobuf:"varint,2,opt,name=position,proto3" json:"position,omitempty"`
-	Name             string                 `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
-	Type             string                 `protobuf:"bytes,4,opt,name=type,proto3" json:"type,omitempty"`
-	Command          string                 `protobuf:"bytes,5,opt,name=command,proto3" json:"command,omitempty"`
-	SkipDryRun       bool                   `protobuf:"varint,6,opt,name=skip_dry_run,json=skipDryRun,proto3" json:"skip_dry_run,omitempty"`
-	Environment      []string               `protobuf:"bytes,7,rep,name=environment,proto3" json:"environment,omitempty"`
-	EnvironmentLabel string                 `protobuf:"bytes,8,opt,name=environment_label,json=environmentLabel,proto3" json:"environment_label,omitempty"`
-	unknownFields    protoimpl.UnknownFields
-	sizeCache        protoimpl.SizeCache
+type ProjectStructureFilter struct {
+	state                 protoimpl.MessageState `protogen:"open.v1"`
+	Value                 string                 `protobuf:"bytes,1,opt,name=value,proto3" json:"value,omitempty"`
+	Label                 string                 `protobuf:"bytes,2,opt,name=label,proto3" json:"label,omitempty"`
+	PipelineIds           []string               `protobuf:"bytes,3,rep,name=pipeline_ids,json=pipelineIds,proto3" json:"pipeline_ids,omitempty"`
+	Root                  *ProjectStructureRoot  `protobuf:"bytes,4,opt,name=root,proto3" json:"root,omitempty"`
+	ShowChainStructure    bool                   `protobuf:"varint,5,opt,name=show_chain_structure,json=showChainStructure,proto3" json:"show_chain_structure,omitempty"`
+	ShowPipelineStructure bool                   `protobuf:"varint,6,opt,name=show_pipeline_structure,json=showPipelineStructure,proto3" json:"show_pipeline_structure,omitempty"`
+	unknownFields         protoimpl.UnknownFields
+	sizeCache             protoimpl.SizeCache
 }
 
-func (x *ProjectStepDetails) Reset() {
-	*x = ProjectStepDetails{}
-	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[20]
+func (x *ProjectStructureFilter) Reset() {
+	*x = ProjectStructureFilter{}
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[22]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ProjectStepDetails) String() string {
+func (x *ProjectStructureFilter) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ProjectStepDetails) ProtoMessage() {}
+func (*ProjectStructureFilter) ProtoMessage() {}
 
-func (x *ProjectStepDetails) ProtoReflect() protoreflect.Message {
-	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[20]
+func (x *ProjectStructureFilter) ProtoReflect() protoreflect.Message {
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[22]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1828,90 +1904,80 @@ func (x *ProjectStepDetails) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ProjectStepDetails.ProtoReflect.Descriptor instead.
-func (*ProjectStepDetails) Descriptor() ([]byte, []int) {
-	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{20}
-}
-
-func (x *ProjectStepDetails) GetIndex() uint32 {
-	if x != nil {
-		return x.Index
-	}
-	return 0
-}
-
-func (x *ProjectStepDetails) GetPosition() uint32 {
-	if x != nil {
-		return x.Position
-	}
-	return 0
+// Deprecated: Use ProjectStructureFilter.ProtoReflect.Descriptor instead.
+func (*ProjectStructureFilter) Descriptor() ([]byte, []int) {
+	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{22}
 }
 
-func (x *ProjectStepDetails) GetName() string {
+func (x *ProjectStructureFilter) GetValue() string {
 	if x != nil {
-		return x.Name
+		return x.Value
 	}
 	return ""
 }
 
-func (x *ProjectStepDetails) GetType() string {
+func (x *ProjectStructureFilter) GetLabel() string {
 	if x != nil {
-		return x.Type
+		return x.Label
 	}
 	return ""
 }
 
-func (x *ProjectStepDetails) GetCommand() string {
+func (x *ProjectStructureFilter) GetPipelineIds() []string {
 	if x != nil {
-		return x.Command
+		return x.PipelineIds
 	}
-	return ""
+	return nil
 }
 
-func (x *ProjectStepDetails) GetSkipDryRun() bool {
+func (x *ProjectStructureFilter) GetRoot() *ProjectStructureRoot {
 	if x != nil {
-		return x.SkipDryRun
+		return x.Root
 	}
-	return false
+	return nil
 }
 
-func (x *ProjectStepDetails) GetEnvironment() []string {
+func (x *ProjectStructureFilter) GetShowChainStructure() bool {
 	if x != nil {
-		return x.Environment
+		return x.ShowChainStructure
 	}
-	return nil
+	return false
 }
 
-func (x *ProjectStepDetails) GetEnvironmentLabel() string {
+func (x *ProjectStructureFilter) GetShowPipelineStructure() bool {
 	if x != nil {
-		return x.EnvironmentLabel
+		return x.ShowPipelineStructure
 	}
-	return ""
+	return false
 }
 
-type GetProjectDetailsRequest struct {
-	state              protoimpl.MessageState `protogen:"open.v1"`
-	ProjectId          int64                  `protobuf:"varint,1,opt,name=project_id,json=projectId,proto3" json:"project_id,omitempty"`
-	IncludeProjectIcon bool                   `protobuf:"varint,2,opt,name=include_project_icon,json=includeProjectIcon,proto3" json:"include_project_icon,omitempty"`
-	unknownFields      protoimpl.UnknownFields
-	sizeCache          protoimpl.SizeCache
+type ProjectStructureRoot struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Label         string                 `protobuf:"bytes,2,opt,name=label,proto3" json:"label,omitempty"`
+	Meta          string                 `protobuf:"bytes,3,opt,name=meta,proto3" json:"meta,omitempty"`
+	Runnable      bool                   `protobuf:"varint,4,opt,name=runnable,proto3" json:"runnable,omitempty"`
+	ProjectId     int64                  `protobuf:"varint,5,opt,name=project_id,json=projectId,proto3" json:"project_id,omitempty"`
+	ChainId       string                 `protobuf:"bytes,6,opt,name=chain_id,json=chainId,proto3" json:"chain_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetProjectDetailsRequest) Reset() {
-	*x = GetProjectDetailsRequest{}
-	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[21]
+func (x *ProjectStructureRoot) Reset() {
+	*x = ProjectStructureRoot{}
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[23]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetProjectDetailsRequest) String() string {
+func (x *ProjectStructureRoot) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetProjectDetailsRequest) ProtoMessage() {}
+func (*ProjectStructureRoot) ProtoMessage() {}
 
-func (x *GetProjectDetailsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[21]
+func (x *ProjectStructureRoot) ProtoReflect() protoreflect.Message {
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[23]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1922,48 +1988,86 @@ func (x *GetProjectDetailsRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetProjectDetailsRequest.ProtoReflect.Descriptor instead.
-func (*GetProjectDetailsRequest) Descriptor() ([]byte, []int) {
-	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{21}
+// Deprecated: Use ProjectStructureRoot.ProtoReflect.Descriptor instead.
+func (*ProjectStructureRoot) Descriptor() ([]byte, []int) {
+	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{23}
 }
 
-func (x *GetProjectDetailsRequest) GetProjectId() int64 {
+func (x *ProjectStructureRoot) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *ProjectStructureRoot) GetLabel() string {
+	if x != nil {
+		return x.Label
+	}
+	return ""
+}
+
+func (x *ProjectStructureRoot) GetMeta() string {
+	if x != nil {
+		return x.Meta
+	}
+	return ""
+}
+
+func (x *ProjectStructureRoot) GetRunnable() bool {
+	if x != nil {
+		return x.Runnable
+	}
+	return false
+}
+
+func (x *ProjectStructureRoot) GetProjectId() int64 {
 	if x != nil {
 		return x.ProjectId
 	}
 	return 0
 }
 
-func (x *GetProjectDetailsRequest) GetIncludeProjectIcon() bool {
+func (x *ProjectStructureRoot) GetChainId() string {
 	if x != nil {
-		return x.IncludeProjectIcon
+		return x.ChainId
 	}
-	return false
+	return ""
 }
 
-type GetJobDetailsRequest struct {
-	state              protoimpl.MessageState `protogen:"open.v1"`
-	JobExecutionId     string                 `protobuf:"bytes,1,opt,name=job_execution_id,json=jobExecutionId,proto3" json:"job_execution_id,omitempty"`
-	IncludeProjectIcon bool                   `protobuf:"varint,2,opt,name=include_project_icon,json=includeProjectIcon,proto3" json:"include_project_icon,omitempty"`
-	unknownFields      protoimpl.UnknownFields
-	sizeCache          protoimpl.SizeCache
+type ProjectPipelineDetails struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	Id                int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	PipelineId        string                 `protobuf:"bytes,2,opt,name=pipeline_id,json=pipelineId,proto3" json:"pipeline_id,omitempty"`
+	Trigger           string                 `protobuf:"bytes,3,opt,name=trigger,proto3" json:"trigger,omitempty"`
+	DependsOn         []string               `protobuf:"bytes,4,rep,name=depends_on,json=dependsOn,proto3" json:"depends_on,omitempty"`
+	Dependencies      string                 `protobuf:"bytes,5,opt,name=dependencies,proto3" json:"dependencies,omitempty"`
+	JobsCount         uint32                 `protobuf:"varint,6,opt,name=jobs_count,json=jobsCount,proto3" json:"jobs_count,omitempty"`
+	SupportsDryRun    bool                   `protobuf:"varint,7,opt,name=supports_dry_run,json=supportsDryRun,proto3" json:"supports_dry_run,omitempty"`
+	Jobs              []*ProjectJobDetails   `protobuf:"bytes,8,rep,name=jobs,proto3" json:"jobs,omitempty"`
+	SummaryLabel      string                 `protobuf:"bytes,9,opt,name=summary_label,json=summaryLabel,proto3" json:"summary_label,omitempty"`
+	GraphSummaryLabel string                 `protobuf:"bytes,10,opt,name=graph_summary_label,json=graphSummaryLabel,proto3" json:"graph_summary_label,omitempty"`
+	TriggerLabel      string                 `protobuf:"bytes,11,opt,name=trigger_label,json=triggerLabel,proto3" json:"trigger_label,omitempty"`
+	TriggerError      string                 `protobuf:"bytes,12,opt,name=trigger_error,json=triggerError,proto3" json:"trigger_error,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
 }
 
-func (x *GetJobDetailsRequest) Reset() {
-	*x = GetJobDetailsRequest{}
-	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[22]
+func (x *ProjectPipelineDetails) Reset() {
+	*x = ProjectPipelineDetails{}
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[24]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetJobDetailsRequest) String() string {
+func (x *ProjectPipelineDetails) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetJobDetailsRequest) ProtoMessage() {}
+func (*ProjectPipelineDetails) ProtoMessage() {}
 
-func (x *GetJobDetailsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[22]
+func (x *ProjectPipelineDetails) ProtoReflect() protoreflect.Message {
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[24]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1974,384 +2078,1126 @@ func (x *GetJobDetailsRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetJobDetailsRequest.ProtoReflect.Descriptor instead.
-func (*GetJobDetailsRequest) Descriptor() ([]byte, []int) {
-	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{22}
+// Deprecated: Use ProjectPipelineDetails.ProtoReflect.Descriptor instead.
+func (*ProjectPipelineDetails) Descriptor() ([]byte, []int) {
+	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{24}
 }
 
-func (x *GetJobDetailsRequest) GetJobExecutionId() string {
+func (x *ProjectPipelineDetails) GetId() int64 {
 	if x != nil {
-		return x.JobExecutionId
+		return x.Id
 	}
-	return ""
+	return 0
 }
 
-func (x *GetJobDetailsRequest) GetIncludeProjectIcon() bool {
+func (x *ProjectPipelineDetails) GetPipelineId() string {
 	if x != nil {
-		return x.IncludeProjectIcon
+		return x.PipelineId
 	}
-	return false
+	return ""
 }
 
-type JobDetailsView struct {
-	state                     protoimpl.MessageState `protogen:"open.v1"`
-	Id                        string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	Title                     string                 `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
-	Context                   string                 `protobuf:"bytes,3,opt,name=context,proto3" json:"context,omitempty"`
-	Status                    string                 `protobuf:"bytes,4,opt,name=status,proto3" json:"status,omitempty"`
-	StatusLabel               string                 `protobuf:"bytes,5,opt,name=status_label,json=statusLabel,proto3" json:"status_label,omitempty"`
-	CurrentStep               string                 `protobuf:"bytes,6,opt,name=current_step,json=currentStep,proto3" json:"current_step,omitempty"`
-	Agent                     string                 `protobuf:"bytes,7,opt,name=agent,proto3" json:"agent,omitempty"`
-	Mode                      string                 `protobuf:"bytes,8,opt,name=mode,proto3" json:"mode,omitempty"`
-	Created                   string                 `protobuf:"bytes,9,opt,name=created,proto3" json:"created,omitempty"`
-	Started                   string                 `protobuf:"bytes,10,opt,name=started,proto3" json:"started,omitempty"`
-	Finished                  string                 `protobuf:"bytes,11,opt,name=finished,proto3" json:"finished,omitempty"`
-	Duration                  string                 `protobuf:"bytes,12,opt,name=duration,proto3" json:"duration,omitempty"`
-	ExitCode                  string                 `protobuf:"bytes,13,opt,name=exit_code,json=exitCode,proto3" json:"exit_code,omitempty"`
-	Error                     string                 `protobuf:"bytes,14,opt,name=error,proto3" json:"error,omitempty"`
-	Timeline                  []*JobTimelineItem     `protobuf:"bytes,15,rep,name=timeline,proto3" json:"timeline,omitempty"`
-	CanCancel                 bool                   `protobuf:"varint,16,opt,name=can_cancel,json=canCancel,proto3" json:"can_cancel,omitempty"`
-	CanRerun                  bool                   `protobuf:"varint,17,opt,name=can_rerun,json=canRerun,proto3" json:"can_rerun,omitempty"`
-	OutputGroups              []*JobOutputGroup      `protobuf:"bytes,18,rep,name=output_groups,json=outputGroups,proto3" json:"output_groups,omitempty"`
-	SchedulingDiagnosis       *SchedulingDiagnosis   `protobuf:"bytes,19,opt,name=scheduling_diagnosis,json=schedulingDiagnosis,proto3" json:"scheduling_diagnosis,omitempty"`
-	Progress                  *Progress              `protobuf:"bytes,20,opt,name=progress,proto3" json:"progress,omitempty"`
-	ProjectId                 int64                  `protobuf:"varint,21,opt,name=project_id,json=projectId,proto3" json:"project_id,omitempty"`
-	ProjectIcon               []byte                 `protobuf:"bytes,22,opt,name=project_icon,json=projectIcon,proto3" json:"project_icon,omitempty"`
-	ProjectIconContentType    string                 `protobuf:"bytes,23,opt,name=project_icon_content_type,json=projectIconContentType,proto3" json:"project_icon_content_type,omitempty"`
-	JobProperties             []*JobDetailRow        `protobuf:"bytes,24,rep,name=job_properties,json=jobProperties,proto3" json:"job_properties,omitempty"`
-	CacheStatistics           []*JobDetailRow        `protobuf:"bytes,25,rep,name=cache_statistics,json=cacheStatistics,proto3" json:"cache_statistics,omitempty"`
-	CacheStatisticsEmpty      string                 `protobuf:"bytes,26,opt,name=cache_statistics_empty,json=cacheStatisticsEmpty,proto3" json:"cache_statistics_empty,omitempty"`
-	HostToolRequirements      *ToolRequirements      `protobuf:"bytes,27,opt,name=host_tool_requirements,json=hostToolRequirements,proto3" json:"host_tool_requirements,omitempty"`
-	ContainerToolRequirements *ToolRequirements      `protobuf:"bytes,28,opt,name=container_tool_requirements,json=containerToolRequirements,proto3" json:"container_tool_requirements,omitempty"`
-	ReleaseSummary            []*JobDetailRow        `protobuf:"bytes,29,rep,name=release_summary,json=releaseSummary,proto3" json:"release_summary,omitempty"`
-	HasReleaseSummary         bool                   `protobuf:"varint,30,opt,name=has_release_summary,json=hasReleaseSummary,proto3" json:"has_release_summary,omitempty"`
-	RunContext                *JobRunContext         `protobuf:"bytes,31,opt,name=run_context,json=runContext,proto3" json:"run_context,omitempty"`
-	Artifacts                 *ReportDetails         `protobuf:"bytes,32,opt,name=artifacts,proto3" json:"artifacts,omitempty"`
-	TestReport                *ReportDetails         `protobuf:"bytes,33,opt,name=test_report,json=testReport,proto3" json:"test_report,omitempty"`
-	CoverageReport            *ReportDetails         `protobuf:"bytes,34,opt,name=coverage_report,json=coverageReport,proto3" json:"coverage_report,omitempty"`
-	InteractiveLogAvailable   bool                   `protobuf:"varint,35,opt,name=interactive_log_available,json=interactiveLogAvailable,proto3" json:"interactive_log_available,omitempty"`
-	InteractiveLogVersion     int32                  `protobuf:"varint,36,opt,name=interactive_log_version,json=interactiveLogVersion,proto3" json:"interactive_log_version,omitempty"`
-	LegacyLogNotice           string                 `protobuf:"bytes,37,opt,name=legacy_log_notice,json=legacyLogNotice,proto3" json:"legacy_log_notice,omitempty"`
-	unknownFields             protoimpl.UnknownFields
-	sizeCache                 protoimpl.SizeCache
-}
-
-func (x *JobDetailsView) Reset() {
-	*x = JobDetailsView{}
-	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[23]
-	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-	ms.StoreMessageInfo(mi)
-}
-
-func (x *JobDetailsView) String() string {
-	return protoimpl.X.MessageStringOf(x)
-}
-
-func (*JobDetailsView) ProtoMessage() {}
-
-func (x *JobDetailsView) ProtoReflect() protoreflect.Message {
-	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[23]
+func (x *ProjectPipelineDetails) GetTrigger() string {
 	if x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
+		return x.Trigger
 	}
-	return mi.MessageOf(x)
-}
-
-// Deprecated: Use JobDetailsView.ProtoReflect.Descriptor instead.
-func (*JobDetailsView) Descriptor() ([]byte, []int) {
-	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{23}
+	return ""
 }
 
-func (x *JobDetailsView) GetId() string {
+func (x *ProjectPipelineDetails) GetDependsOn() []string {
 	if x != nil {
-		return x.Id
+		return x.DependsOn
 	}
-	return ""
+	return nil
 }
 
-func (x *JobDetailsView) GetTitle() string {
+func (x *ProjectPipelineDetails) GetDependencies() string {
 	if x != nil {
-		return x.Title
+		return x.Dependencies
 	}
 	return ""
 }
 
-func (x *JobDetailsView) GetContext() string {
+func (x *ProjectPipelineDetails) GetJobsCount() uint32 {
 	if x != nil {
-		return x.Context
+		return x.JobsCount
 	}
-	return ""
+	return 0
 }
 
-func (x *JobDetailsView) GetStatus() string {
+func (x *ProjectPipelineDetails) GetSupportsDryRun() bool {
 	if x != nil {
-		return x.Status
+		return x.SupportsDryRun
 	}
-	return ""
+	return false
 }
 
-func (x *JobDetailsView) GetStatusLabel() string {
+func (x *ProjectPipelineDetails) GetJobs() []*ProjectJobDetails {
 	if x != nil {
-		return x.StatusLabel
+		return x.Jobs
 	}
-	return ""
+	return nil
 }
 
-func (x *JobDetailsView) GetCurrentStep() string {
+func (x *ProjectPipelineDetails) GetSummaryLabel() string {
 	if x != nil {
-		return x.CurrentStep
+		return x.SummaryLabel
 	}
 	return ""
 }
 
-func (x *JobDetailsView) GetAgent() string {
+func (x *ProjectPipelineDetails) GetGraphSummaryLabel() string {
 	if x != nil {
-		return x.Agent
+		return x.GraphSummaryLabel
 	}
 	return ""
 }
 
-func (x *JobDetailsView) GetMode() string {
+func (x *ProjectPipelineDetails) GetTriggerLabel() string {
 	if x != nil {
-		return x.Mode
+		return x.TriggerLabel
 	}
 	return ""
 }
 
-func (x *JobDetailsView) GetCreated() string {
+func (x *ProjectPipelineDetails) GetTriggerError() string {
 	if x != nil {
-		return x.Created
+		return x.TriggerError
 	}
 	return ""
 }
 
-func (x *JobDetailsView) GetStarted() string {
-	if x != nil {
-		return x.Started
-	}
-	return ""
+type ProjectJobDetails struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Id             string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Needs          []string               `protobuf:"bytes,2,rep,name=needs,proto3" json:"needs,omitempty"`
+	NeedsLabel     string                 `protobuf:"bytes,3,opt,name=needs_label,json=needsLabel,proto3" json:"needs_label,omitempty"`
+	RunsOnLabel    string                 `protobuf:"bytes,4,opt,name=runs_on_label,json=runsOnLabel,proto3" json:"runs_on_label,omitempty"`
+	ToolsLabel     string                 `protobuf:"bytes,5,opt,name=tools_label,json=toolsLabel,proto3" json:"tools_label,omitempty"`
+	TimeoutSeconds uint32                 `protobuf:"varint,6,opt,name=timeout_seconds,json=timeoutSeconds,proto3" json:"timeout_seconds,omitempty"`
+	MatrixCount    uint32                 `protobuf:"varint,7,opt,name=matrix_count,json=matrixCount,proto3" json:"matrix_count,omitempty"`
+	StepsCount     uint32                 `protobuf:"varint,8,opt,name=steps_count,json=stepsCount,proto3" json:"steps_count,omitempty"`
+	Steps          []*ProjectStepDetails  `protobuf:"bytes,9,rep,name=steps,proto3" json:"steps,omitempty"`
+	SupportsDryRun bool                   `protobuf:"varint,10,opt,name=supports_dry_run,json=supportsDryRun,proto3" json:"supports_dry_run,omitempty"`
+	SummaryLabel   string                 `protobuf:"bytes,11,opt,name=summary_label,json=summaryLabel,proto3" json:"summary_label,omitempty"`
+	TimeoutLabel   string                 `protobuf:"bytes,12,opt,name=timeout_label,json=timeoutLabel,proto3" json:"timeout_label,omitempty"`
+	MatrixLabel    string                 `protobuf:"bytes,13,opt,name=matrix_label,json=matrixLabel,proto3" json:"matrix_label,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
 }
 
-func (x *JobDetailsView) GetFinished() string {
-	if x != nil {
-		return x.Finished
-	}
-	return ""
+func (x *ProjectJobDetails) Reset() {
+	*x = ProjectJobDetails{}
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
 
-func (x *JobDetailsView) GetDuration() string {
-	if x != nil {
-		return x.Duration
-	}
-	return ""
+func (x *ProjectJobDetails) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
 
-func (x *JobDetailsView) GetExitCode() string {
+func (*ProjectJobDetails) ProtoMessage() {}
+
+func (x *ProjectJobDetails) ProtoReflect() protoreflect.Message {
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[25]
 	if x != nil {
-		return x.ExitCode
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return ""
+	return mi.MessageOf(x)
 }
 
-func (x *JobDetailsView) GetError() string {
+// Deprecated: Use ProjectJobDetails.ProtoReflect.Descriptor instead.
+func (*ProjectJobDetails) Descriptor() ([]byte, []int) {
+	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *ProjectJobDetails) GetId() string {
 	if x != nil {
-		return x.Error
+		return x.Id
 	}
 	return ""
 }
 
-func (x *JobDetailsView) GetTimeline() []*JobTimelineItem {
+func (x *ProjectJobDetails) GetNeeds() []string {
 	if x != nil {
-		return x.Timeline
+		return x.Needs
 	}
 	return nil
 }
 
-func (x *JobDetailsView) GetCanCancel() bool {
+func (x *ProjectJobDetails) GetNeedsLabel() string {
 	if x != nil {
-		return x.CanCancel
+		return x.NeedsLabel
 	}
-	return false
+	return ""
 }
 
-func (x *JobDetailsView) GetCanRerun() bool {
+func (x *ProjectJobDetails) GetRunsOnLabel() string {
 	if x != nil {
-		return x.CanRerun
+		return x.RunsOnLabel
 	}
-	return false
+	return ""
 }
 
-func (x *JobDetailsView) GetOutputGroups() []*JobOutputGroup {
+func (x *ProjectJobDetails) GetToolsLabel() string {
 	if x != nil {
-		return x.OutputGroups
+		return x.ToolsLabel
 	}
-	return nil
+	return ""
 }
 
-func (x *JobDetailsView) GetSchedulingDiagnosis() *SchedulingDiagnosis {
+func (x *ProjectJobDetails) GetTimeoutSeconds() uint32 {
 	if x != nil {
-		return x.SchedulingDiagnosis
+		return x.TimeoutSeconds
 	}
-	return nil
+	return 0
 }
 
-func (x *JobDetailsView) GetProgress() *Progress {
+func (x *ProjectJobDetails) GetMatrixCount() uint32 {
 	if x != nil {
-		return x.Progress
+		return x.MatrixCount
 	}
-	return nil
+	return 0
 }
 
-func (x *JobDetailsView) GetProjectId() int64 {
+func (x *ProjectJobDetails) GetStepsCount() uint32 {
 	if x != nil {
-		return x.ProjectId
+		return x.StepsCount
 	}
 	return 0
 }
 
-func (x *JobDetailsView) GetProjectIcon() []byte {
+func (x *ProjectJobDetails) GetSteps() []*ProjectStepDetails {
 	if x != nil {
-		return x.ProjectIcon
+		return x.Steps
 	}
 	return nil
 }
 
-func (x *JobDetailsView) GetProjectIconContentType() string {
+func (x *ProjectJobDetails) GetSupportsDryRun() bool {
 	if x != nil {
-		return x.ProjectIconContentType
+		return x.SupportsDryRun
 	}
-	return ""
+	return false
 }
 
-func (x *JobDetailsView) GetJobProperties() []*JobDetailRow {
+func (x *ProjectJobDetails) GetSummaryLabel() string {
 	if x != nil {
-		return x.JobProperties
+		return x.SummaryLabel
 	}
-	return nil
+	return ""
 }
 
-func (x *JobDetailsView) GetCacheStatistics() []*JobDetailRow {
+func (x *ProjectJobDetails) GetTimeoutLabel() string {
 	if x != nil {
-		return x.CacheStatistics
+		return x.TimeoutLabel
 	}
-	return nil
+	return ""
 }
 
-func (x *JobDetailsView) GetCacheStatisticsEmpty() string {
+func (x *ProjectJobDetails) GetMatrixLabel() string {
 	if x != nil {
-		return x.CacheStatisticsEmpty
+		return x.MatrixLabel
 	}
 	return ""
 }
 
-func (x *JobDetailsView) GetHostToolRequirements() *ToolRequirements {
-	if x != nil {
-		return x.HostToolRequirements
-	}
-	return nil
+type ProjectStepDetails struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	Index            uint32                 `protobuf:"varint,1,opt,name=index,proto3" json:"index,omitempty"`
+	Position         uint32                 `protobuf:"varint,2,opt,name=position,proto3" json:"position,omitempty"`
+	Name             string                 `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	Type             string                 `protobuf:"bytes,4,opt,name=type,proto3" json:"type,omitempty"`
+	Command          string                 `protobuf:"bytes,5,opt,name=command,proto3" json:"command,omitempty"`
+	SkipDryRun       bool                   `protobuf:"varint,6,opt,name=skip_dry_run,json=skipDryRun,proto3" json:"skip_dry_run,omitempty"`
+	Environment      []string               `protobuf:"bytes,7,rep,name=environment,proto3" json:"environment,omitempty"`
+	EnvironmentLabel string                 `protobuf:"bytes,8,opt,name=environment_label,json=environmentLabel,proto3" json:"environment_label,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
 }
 
-func (x *JobDetailsView) GetContainerToolRequirements() *ToolRequirements {
-	if x != nil {
-		return x.ContainerToolRequirements
-	}
+func (x *ProjectStepDetails) Reset() {
+	*x = ProjectStepDetails{}
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProjectStepDetails) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProjectStepDetails) ProtoMessage() {}
+
+func (x *ProjectStepDetails) ProtoReflect() protoreflect.Message {
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProjectStepDetails.ProtoReflect.Descriptor instead.
+func (*ProjectStepDetails) Descriptor() ([]byte, []int) {
+	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *ProjectStepDetails) GetIndex() uint32 {
+	if x != nil {
+		return x.Index
+	}
+	return 0
+}
+
+func (x *ProjectStepDetails) GetPosition() uint32 {
+	if x != nil {
+		return x.Position
+	}
+	return 0
+}
+
+func (x *ProjectStepDetails) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ProjectStepDetails) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *ProjectStepDetails) GetCommand() string {
+	if x != nil {
+		return x.Command
+	}
+	return ""
+}
+
+func (x *ProjectStepDetails) GetSkipDryRun() bool {
+	if x != nil {
+		return x.SkipDryRun
+	}
+	return false
+}
+
+func (x *ProjectStepDetails) GetEnvironment() []string {
+	if x != nil {
+		return x.Environment
+	}
+	return nil
+}
+
+func (x *ProjectStepDetails) GetEnvironmentLabel() string {
+	if x != nil {
+		return x.EnvironmentLabel
+	}
+	return ""
+}
+
+type GetProjectDetailsRequest struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	ProjectId          int64                  `protobuf:"varint,1,opt,name=project_id,json=projectId,proto3" json:"project_id,omitempty"`
+	IncludeProjectIcon bool                   `protobuf:"varint,2,opt,name=include_project_icon,json=includeProjectIcon,proto3" json:"include_project_icon,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *GetProjectDetailsRequest) Reset() {
+	*x = GetProjectDetailsRequest{}
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetProjectDetailsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetProjectDetailsRequest) ProtoMessage() {}
+
+func (x *GetProjectDetailsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetProjectDetailsRequest.ProtoReflect.Descriptor instead.
+func (*GetProjectDetailsRequest) Descriptor() ([]byte, []int) {
+	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *GetProjectDetailsRequest) GetProjectId() int64 {
+	if x != nil {
+		return x.ProjectId
+	}
+	return 0
+}
+
+func (x *GetProjectDetailsRequest) GetIncludeProjectIcon() bool {
+	if x != nil {
+		return x.IncludeProjectIcon
+	}
+	return false
+}
+
+type GetJobDetailsRequest struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	JobExecutionId     string                 `protobuf:"bytes,1,opt,name=job_execution_id,json=jobExecutionId,proto3" json:"job_execution_id,omitempty"`
+	IncludeProjectIcon bool                   `protobuf:"varint,2,opt,name=include_project_icon,json=includeProjectIcon,proto3" json:"include_project_icon,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *GetJobDetailsRequest) Reset() {
+	*x = GetJobDetailsRequest{}
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetJobDetailsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetJobDetailsRequest) ProtoMessage() {}
+
+func (x *GetJobDetailsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetJobDetailsRequest.ProtoReflect.Descriptor instead.
+func (*GetJobDetailsRequest) Descriptor() ([]byte, []int) {
+	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *GetJobDetailsRequest) GetJobExecutionId() string {
+	if x != nil {
+		return x.JobExecutionId
+	}
+	return ""
+}
+
+func (x *GetJobDetailsRequest) GetIncludeProjectIcon() bool {
+	if x != nil {
+		return x.IncludeProjectIcon
+	}
+	return false
+}
+
+type JobDetailsView struct {
+	state                     protoimpl.MessageState `protogen:"open.v1"`
+	Id                        string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Title                     string                 `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
+	Context                   string                 `protobuf:"bytes,3,opt,name=context,proto3" json:"context,omitempty"`
+	Status                    string                 `protobuf:"bytes,4,opt,name=status,proto3" json:"status,omitempty"`
+	StatusLabel               string                 `protobuf:"bytes,5,opt,name=status_label,json=statusLabel,proto3" json:"status_label,omitempty"`
+	CurrentStep               string                 `protobuf:"bytes,6,opt,name=current_step,json=currentStep,proto3" json:"current_step,omitempty"`
+	Agent                     string                 `protobuf:"bytes,7,opt,name=agent,proto3" json:"agent,omitempty"`
+	Mode                      string                 `protobuf:"bytes,8,opt,name=mode,proto3" json:"mode,omitempty"`
+	Created                   string                 `protobuf:"bytes,9,opt,name=created,proto3" json:"created,omitempty"`
+	Started                   string                 `protobuf:"bytes,10,opt,name=started,proto3" json:"started,omitempty"`
+	Finished                  string                 `protobuf:"bytes,11,opt,name=finished,proto3" json:"finished,omitempty"`
+	Duration                  string                 `protobuf:"bytes,12,opt,name=duration,proto3" json:"duration,omitempty"`
+	ExitCode                  string                 `protobuf:"bytes,13,opt,name=exit_code,json=exitCode,proto3" json:"exit_code,omitempty"`
+	Error                     string                 `protobuf:"bytes,14,opt,name=error,proto3" json:"error,omitempty"`
+	Timeline                  []*JobTimelineItem     `protobuf:"bytes,15,rep,name=timeline,proto3" json:"timeline,omitempty"`
+	CanCancel                 bool                   `protobuf:"varint,16,opt,name=can_cancel,json=canCancel,proto3" json:"can_cancel,omitempty"`
+	CanRerun                  bool                   `protobuf:"varint,17,opt,name=can_rerun,json=canRerun,proto3" json:"can_rerun,omitempty"`
+	OutputGroups              []*JobOutputGroup      `protobuf:"bytes,18,rep,name=output_groups,json=outputGroups,proto3" json:"output_groups,omitempty"`
+	SchedulingDiagnosis       *SchedulingDiagnosis   `protobuf:"bytes,19,opt,name=scheduling_diagnosis,json=schedulingDiagnosis,proto3" json:"scheduling_diagnosis,omitempty"`
+	Progress                  *Progress              `protobuf:"bytes,20,opt,name=progress,proto3" json:"progress,omitempty"`
+	ProjectId                 int64                  `protobuf:"varint,21,opt,name=project_id,json=projectId,proto3" json:"project_id,omitempty"`
+	ProjectIcon               []byte                 `protobuf:"bytes,22,opt,name=project_icon,json=projectIcon,proto3" json:"project_icon,omitempty"`
+	ProjectIconContentType    string                 `protobuf:"bytes,23,opt,name=project_icon_content_type,json=projectIconContentType,proto3" json:"project_icon_content_type,omitempty"`
+	JobProperties             []*JobDetailRow        `protobuf:"bytes,24,rep,name=job_properties,json=jobProperties,proto3" json:"job_properties,omitempty"`
+	CacheStatistics           []*JobDetailRow        `protobuf:"bytes,25,rep,name=cache_statistics,json=cacheStatistics,proto3" json:"cache_statistics,omitempty"`
+	CacheStatisticsEmpty      string                 `protobuf:"bytes,26,opt,name=cache_statistics_empty,json=cacheStatisticsEmpty,proto3" json:"cache_statistics_empty,omitempty"`
+	HostToolRequirements      *ToolRequirements      `protobuf:"bytes,27,opt,name=host_tool_requirements,json=hostToolRequirements,proto3" json:"host_tool_requirements,omitempty"`
+	ContainerToolRequirements *ToolRequirements      `protobuf:"bytes,28,opt,name=container_tool_requirements,json=containerToolRequirements,proto3" json:"container_tool_requirements,omitempty"`
+	ReleaseSummary            []*JobDetailRow        `protobuf:"bytes,29,rep,name=release_summary,json=releaseSummary,proto3" json:"release_summary,omitempty"`
+	HasReleaseSummary         bool                   `protobuf:"varint,30,opt,name=has_release_summary,json=hasReleaseSummary,proto3" json:"has_release_summary,omitempty"`
+	RunContext                *JobRunContext         `protobuf:"bytes,31,opt,name=run_context,json=runContext,proto3" json:"run_context,omitempty"`
+	Artifacts                 *ReportDetails         `protobuf:"bytes,32,opt,name=artifacts,proto3" json:"artifacts,omitempty"`
+	TestReport                *ReportDetails         `protobuf:"bytes,33,opt,name=test_report,json=testReport,proto3" json:"test_report,omitempty"`
+	CoverageReport            *ReportDetails         `protobuf:"bytes,34,opt,name=coverage_report,json=coverageReport,proto3" json:"coverage_report,omitempty"`
+	InteractiveLogAvailable   bool                   `protobuf:"varint,35,opt,name=interactive_log_available,json=interactiveLogAvailable,proto3" json:"interactive_log_available,omitempty"`
+	InteractiveLogVersion     int32                  `protobuf:"varint,36,opt,name=interactive_log_version,json=interactiveLogVersion,proto3" json:"interactive_log_version,omitempty"`
+	LegacyLogNotice           string                 `protobuf:"bytes,37,opt,name=legacy_log_notice,json=legacyLogNotice,proto3" json:"legacy_log_notice,omitempty"`
+	unknownFields             protoimpl.UnknownFields
+	sizeCache                 protoimpl.SizeCache
+}
+
+func (x *JobDetailsView) Reset() {
+	*x = JobDetailsView{}
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *JobDetailsView) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*JobDetailsView) ProtoMessage() {}
+
+func (x *JobDetailsView) ProtoReflect() protoreflect.Message {
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use JobDetailsView.ProtoReflect.Descriptor instead.
+func (*JobDetailsView) Descriptor() ([]byte, []int) {
+	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *JobDetailsView) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *JobDetailsView) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *JobDetailsView) GetContext() string {
+	if x != nil {
+		return x.Context
+	}
+	return ""
+}
+
+func (x *JobDetailsView) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *JobDetailsView) GetStatusLabel() string {
+	if x != nil {
+		return x.StatusLabel
+	}
+	return ""
+}
+
+func (x *JobDetailsView) GetCurrentStep() string {
+	if x != nil {
+		return x.CurrentStep
+	}
+	return ""
+}
+
+func (x *JobDetailsView) GetAgent() string {
+	if x != nil {
+		return x.Agent
+	}
+	return ""
+}
+
+func (x *JobDetailsView) GetMode() string {
+	if x != nil {
+		return x.Mode
+	}
+	return ""
+}
+
+func (x *JobDetailsView) GetCreated() string {
+	if x != nil {
+		return x.Created
+	}
+	return ""
+}
+
+func (x *JobDetailsView) GetStarted() string {
+	if x != nil {
+		return x.Started
+	}
+	return ""
+}
+
+func (x *JobDetailsView) GetFinished() string {
+	if x != nil {
+		return x.Finished
+	}
+	return ""
+}
+
+func (x *JobDetailsView) GetDuration() string {
+	if x != nil {
+		return x.Duration
+	}
+	return ""
+}
+
+func (x *JobDetailsView) GetExitCode() string {
+	if x != nil {
+		return x.ExitCode
+	}
+	return ""
+}
+
+func (x *JobDetailsView) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *JobDetailsView) GetTimeline() []*JobTimelineItem {
+	if x != nil {
+		return x.Timeline
+	}
+	return nil
+}
+
+func (x *JobDetailsView) GetCanCancel() bool {
+	if x != nil {
+		return x.CanCancel
+	}
+	return false
+}
+
+func (x *JobDetailsView) GetCanRerun() bool {
+	if x != nil {
+		return x.CanRerun
+	}
+	return false
+}
+
+func (x *JobDetailsView) GetOutputGroups() []*JobOutputGroup {
+	if x != nil {
+		return x.OutputGroups
+	}
+	return nil
+}
+
+func (x *JobDetailsView) GetSchedulingDiagnosis() *SchedulingDiagnosis {
+	if x != nil {
+		return x.SchedulingDiagnosis
+	}
+	return nil
+}
+
+func (x *JobDetailsView) GetProgress() *Progress {
+	if x != nil {
+		return x.Progress
+	}
+	return nil
+}
+
+func (x *JobDetailsView) GetProjectId() int64 {
+	if x != nil {
+		return x.ProjectId
+	}
+	return 0
+}
+
+func (x *JobDetailsView) GetProjectIcon() []byte {
+	if x != nil {
+		return x.ProjectIcon
+	}
+	return nil
+}
+
+func (x *JobDetailsView) GetProjectIconContentType() string {
+	if x != nil {
+		return x.ProjectIconContentType
+	}
+	return ""
+}
+
+func (x *JobDetailsView) GetJobProperties() []*JobDetailRow {
+	if x != nil {
+		return x.JobProperties
+	}
+	return nil
+}
+
+func (x *JobDetailsView) GetCacheStatistics() []*JobDetailRow {
+	if x != nil {
+		return x.CacheStatistics
+	}
+	return nil
+}
+
+func (x *JobDetailsView) GetCacheStatisticsEmpty() string {
+	if x != nil {
+		return x.CacheStatisticsEmpty
+	}
+	return ""
+}
+
+func (x *JobDetailsView) GetHostToolRequirements() *ToolRequirements {
+	if x != nil {
+		return x.HostToolRequirements
+	}
+	return nil
+}
+
+func (x *JobDetailsView) GetContainerToolRequirements() *ToolRequirements {
+	if x != nil {
+		return x.ContainerToolRequirements
+	}
+	return nil
+}
+
+func (x *JobDetailsView) GetReleaseSummary() []*JobDetailRow {
+	if x != nil {
+		return x.ReleaseSummary
+	}
+	return nil
+}
+
+func (x *JobDetailsView) GetHasReleaseSummary() bool {
+	if x != nil {
+		return x.HasReleaseSummary
+	}
+	return false
+}
+
+func (x *JobDetailsView) GetRunContext() *JobRunContext {
+	if x != nil {
+		return x.RunContext
+	}
+	return nil
+}
+
+func (x *JobDetailsView) GetArtifacts() *ReportDetails {
+	if x != nil {
+		return x.Artifacts
+	}
+	return nil
+}
+
+func (x *JobDetailsView) GetTestReport() *ReportDetails {
+	if x != nil {
+		return x.TestReport
+	}
+	return nil
+}
+
+func (x *JobDetailsView) GetCoverageReport() *ReportDetails {
+	if x != nil {
+		return x.CoverageReport
+	}
+	return nil
+}
+
+func (x *JobDetailsView) GetInteractiveLogAvailable() bool {
+	if x != nil {
+		return x.InteractiveLogAvailable
+	}
+	return false
+}
+
+func (x *JobDetailsView) GetInteractiveLogVersion() int32 {
+	if x != nil {
+		return x.InteractiveLogVersion
+	}
+	return 0
+}
+
+func (x *JobDetailsView) GetLegacyLogNotice() string {
+	if x != nil {
+		return x.LegacyLogNotice
+	}
+	return ""
+}
+
+type SchedulingDiagnosis struct {
+	state                 protoimpl.MessageState       `protogen:"open.v1"`
+	State                 string                       `protobuf:"bytes,1,opt,name=state,proto3" json:"state,omitempty"`
+	Summary               string                       `protobuf:"bytes,2,opt,name=summary,proto3" json:"summary,omitempty"`
+	Requirements          []string                     `protobuf:"bytes,3,rep,name=requirements,proto3" json:"requirements,omitempty"`
+	Agents                []*SchedulingAgentAssessment `protobuf:"bytes,4,rep,name=agents,proto3" json:"agents,omitempty"`
+	RequirementsLabel     string                       `protobuf:"bytes,5,opt,name=requirements_label,json=requirementsLabel,proto3" json:"requirements_label,omitempty"`
+	AdditionalAgentsLabel string                       `protobuf:"bytes,6,opt,name=additional_agents_label,json=additionalAgentsLabel,proto3" json:"additional_agents_label,omitempty"`
+	unknownFields         protoimpl.UnknownFields
+	sizeCache             protoimpl.SizeCache
+}
+
+func (x *SchedulingDiagnosis) Reset() {
+	*x = SchedulingDiagnosis{}
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SchedulingDiagnosis) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SchedulingDiagnosis) ProtoMessage() {}
+
+func (x *SchedulingDiagnosis) ProtoReflect() protoreflect.Message {
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SchedulingDiagnosis.ProtoReflect.Descriptor instead.
+func (*SchedulingDiagnosis) Descriptor() ([]byte, []int) {
+	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *SchedulingDiagnosis) GetState() string {
+	if x != nil {
+		return x.State
+	}
+	return ""
+}
+
+func (x *SchedulingDiagnosis) GetSummary() string {
+	if x != nil {
+		return x.Summary
+	}
+	return ""
+}
+
+func (x *SchedulingDiagnosis) GetRequirements() []string {
+	if x != nil {
+		return x.Requirements
+	}
 	return nil
 }
 
-func (x *JobDetailsView) GetReleaseSummary() []*JobDetailRow {
-	if x != nil {
-		return x.ReleaseSummary
-	}
-	return nil
+func (x *SchedulingDiagnosis) GetAgents() []*SchedulingAgentAssessment {
+	if x != nil {
+		return x.Agents
+	}
+	return nil
+}
+
+func (x *SchedulingDiagnosis) GetRequirementsLabel() string {
+	if x != nil {
+		return x.RequirementsLabel
+	}
+	return ""
+}
+
+func (x *SchedulingDiagnosis) GetAdditionalAgentsLabel() string {
+	if x != nil {
+		return x.AdditionalAgentsLabel
+	}
+	return ""
+}
+
+type SchedulingAgentAssessment struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	AgentId         string                 `protobuf:"bytes,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
+	Status          string                 `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
+	Details         string                 `protobuf:"bytes,3,opt,name=details,proto3" json:"details,omitempty"`
+	Tone            string                 `protobuf:"bytes,4,opt,name=tone,proto3" json:"tone,omitempty"`
+	CapabilityMatch bool                   `protobuf:"varint,5,opt,name=capability_match,json=capabilityMatch,proto3" json:"capability_match,omitempty"`
+	Available       bool                   `protobuf:"varint,6,opt,name=available,proto3" json:"available,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *SchedulingAgentAssessment) Reset() {
+	*x = SchedulingAgentAssessment{}
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SchedulingAgentAssessment) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SchedulingAgentAssessment) ProtoMessage() {}
+
+func (x *SchedulingAgentAssessment) ProtoReflect() protoreflect.Message {
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SchedulingAgentAssessment.ProtoReflect.Descriptor instead.
+func (*SchedulingAgentAssessment) Descriptor() ([]byte, []int) {
+	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *SchedulingAgentAssessment) GetAgentId() string {
+	if x != nil {
+		return x.AgentId
+	}
+	return ""
+}
+
+func (x *SchedulingAgentAssessment) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *SchedulingAgentAssessment) GetDetails() string {
+	if x != nil {
+		return x.Details
+	}
+	return ""
+}
+
+func (x *SchedulingAgentAssessment) GetTone() string {
+	if x != nil {
+		return x.Tone
+	}
+	return ""
+}
+
+func (x *SchedulingAgentAssessment) GetCapabilityMatch() bool {
+	if x != nil {
+		return x.CapabilityMatch
+	}
+	return false
+}
+
+func (x *SchedulingAgentAssessment) GetAvailable() bool {
+	if x != nil {
+		return x.Available
+	}
+	return false
+}
+
+type ControlExecutionRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	JobExecutionId string                 `protobuf:"bytes,1,opt,name=job_execution_id,json=jobExecutionId,proto3" json:"job_execution_id,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ControlExecutionRequest) Reset() {
+	*x = ControlExecutionRequest{}
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ControlExecutionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ControlExecutionRequest) ProtoMessage() {}
+
+func (x *ControlExecutionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ControlExecutionRequest.ProtoReflect.Descriptor instead.
+func (*ControlExecutionRequest) Descriptor() ([]byte, []int) {
+	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *ControlExecutionRequest) GetJobExecutionId() string {
+	if x != nil {
+		return x.JobExecutionId
+	}
+	return ""
+}
+
+type CancelExecutionResult struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	JobExecutionId string                 `protobuf:"bytes,1,opt,name=job_execution_id,json=jobExecutionId,proto3" json:"job_execution_id,omitempty"`
+	Status         string                 `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *CancelExecutionResult) Reset() {
+	*x = CancelExecutionResult{}
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelExecutionResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
 
-func (x *JobDetailsView) GetHasReleaseSummary() bool {
+func (*CancelExecutionResult) ProtoMessage() {}
+
+func (x *CancelExecutionResult) ProtoReflect() protoreflect.Message {
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[33]
 	if x != nil {
-		return x.HasReleaseSummary
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return false
+	return mi.MessageOf(x)
 }
 
-func (x *JobDetailsView) GetRunContext() *JobRunContext {
-	if x != nil {
-		return x.RunContext
-	}
-	return nil
+// Deprecated: Use CancelExecutionResult.ProtoReflect.Descriptor instead.
+func (*CancelExecutionResult) Descriptor() ([]byte, []int) {
+	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{33}
 }
 
-func (x *JobDetailsView) GetArtifacts() *ReportDetails {
+func (x *CancelExecutionResult) GetJobExecutionId() string {
 	if x != nil {
-		return x.Artifacts
+		return x.JobExecutionId
 	}
-	return nil
+	return ""
 }
 
-func (x *JobDetailsView) GetTestReport() *ReportDetails {
+func (x *CancelExecutionResult) GetStatus() string {
 	if x != nil {
-		return x.TestReport
+		return x.Status
 	}
-	return nil
+	return ""
 }
 
-func (x *JobDetailsView) GetCoverageReport() *ReportDetails {
+type RerunExecutionResult struct {
+	state                  protoimpl.MessageState `protogen:"open.v1"`
+	OriginalJobExecutionId string                 `protobuf:"bytes,1,opt,name=original_job_execution_id,json=originalJobExecutionId,proto3" json:"original_job_execution_id,omitempty"`
+	JobExecutionId         string                 `protobuf:"bytes,2,opt,name=job_execution_id,json=jobExecutionId,proto3" json:"job_execution_id,omitempty"`
+	Status                 string                 `protobuf:"bytes,3,opt,name=status,proto3" json:"status,omitempty"`
+	unknownFields          protoimpl.UnknownFields
+	sizeCache              protoimpl.SizeCache
+}
+
+func (x *RerunExecutionResult) Reset() {
+	*x = RerunExecutionResult{}
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RerunExecutionResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RerunExecutionResult) ProtoMessage() {}
+
+func (x *RerunExecutionResult) ProtoReflect() protoreflect.Message {
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[34]
 	if x != nil {
-		return x.CoverageReport
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return nil
+	return mi.MessageOf(x)
 }
 
-func (x *JobDetailsView) GetInteractiveLogAvailable() bool {
+// Deprecated: Use RerunExecutionResult.ProtoReflect.Descriptor instead.
+func (*RerunExecutionResult) Descriptor() ([]byte, []int) {
+	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *RerunExecutionResult) GetOriginalJobExecutionId() string {
 	if x != nil {
-		return x.InteractiveLogAvailable
+		return x.OriginalJobExecutionId
 	}
-	return false
+	return ""
 }
 
-func (x *JobDetailsView) GetInteractiveLogVersion() int32 {
+func (x *RerunExecutionResult) GetJobExecutionId() string {
 	if x != nil {
-		return x.InteractiveLogVersion
+		return x.JobExecutionId
 	}
-	return 0
+	return ""
 }
 
-func (x *JobDetailsView) GetLegacyLogNotice() string {
+func (x *RerunExecutionResult) GetStatus() string {
 	if x != nil {
-		return x.LegacyLogNotice
+		return x.Status
 	}
 	return ""
 }
 
-type SchedulingDiagnosis struct {
-	state                 protoimpl.MessageState       `protogen:"open.v1"`
-	State                 string                       `protobuf:"bytes,1,opt,name=state,proto3" json:"state,omitempty"`
-	Summary               string                       `protobuf:"bytes,2,opt,name=summary,proto3" json:"summary,omitempty"`
-	Requirements          []string                     `protobuf:"bytes,3,rep,name=requirements,proto3" json:"requirements,omitempty"`
-	Agents                []*SchedulingAgentAssessment `protobuf:"bytes,4,rep,name=agents,proto3" json:"agents,omitempty"`
-	RequirementsLabel     string                       `protobuf:"bytes,5,opt,name=requirements_label,json=requirementsLabel,proto3" json:"requirements_label,omitempty"`
-	AdditionalAgentsLabel string                       `protobuf:"bytes,6,opt,name=additional_agents_label,json=additionalAgentsLabel,proto3" json:"additional_agents_label,omitempty"`
-	unknownFields         protoimpl.UnknownFields
-	sizeCache             protoimpl.SizeCache
+type JobTimelineItem struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Kind          string                 `protobuf:"bytes,2,opt,name=kind,proto3" json:"kind,omitempty"`
+	Title         string                 `protobuf:"bytes,3,opt,name=title,proto3" json:"title,omitempty"`
+	Description   string                 `protobuf:"bytes,4,opt,name=description,proto3" json:"description,omitempty"`
+	Status        string                 `protobuf:"bytes,5,opt,name=status,proto3" json:"status,omitempty"`
+	StatusLabel   string                 `protobuf:"bytes,6,opt,name=status_label,json=statusLabel,proto3" json:"status_label,omitempty"`
+	Duration      string                 `protobuf:"bytes,7,opt,name=duration,proto3" json:"duration,omitempty"`
+	ExitCode      string                 `protobuf:"bytes,8,opt,name=exit_code,json=exitCode,proto3" json:"exit_code,omitempty"`
+	Error         string                 `protobuf:"bytes,9,opt,name=error,proto3" json:"error,omitempty"`
+	Progress      *Progress              `protobuf:"bytes,10,opt,name=progress,proto3" json:"progress,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
-func (x *SchedulingDiagnosis) Reset() {
-	*x = SchedulingDiagnosis{}
-	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[24]
+func (x *JobTimelineItem) Reset() {
+	*x = JobTimelineItem{}
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[35]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *SchedulingDiagnosis) String() string {
+func (x *JobTimelineItem) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SchedulingDiagnosis) ProtoMessage() {}
+func (*JobTimelineItem) ProtoMessage() {}
 
-func (x *SchedulingDiagnosis) ProtoReflect() protoreflect.Message {
-	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[24]
+func (x *JobTimelineItem) ProtoReflect() protoreflect.Message {
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[35]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2362,80 +3208,119 @@ func (x *SchedulingDiagnosis) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SchedulingDiagnosis.ProtoReflect.Descriptor instead.
-func (*SchedulingDiagnosis) Descriptor() ([]byte, []int) {
-	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{24}
+// Deprecated: Use JobTimelineItem.ProtoReflect.Descriptor instead.
+func (*JobTimelineItem) Descriptor() ([]byte, []int) {
+	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{35}
 }
 
-func (x *SchedulingDiagnosis) GetState() string {
+func (x *JobTimelineItem) GetId() string {
 	if x != nil {
-		return x.State
+		return x.Id
 	}
 	return ""
 }
 
-func (x *SchedulingDiagnosis) GetSummary() string {
+func (x *JobTimelineItem) GetKind() string {
 	if x != nil {
-		return x.Summary
+		return x.Kind
 	}
 	return ""
 }
 
-func (x *SchedulingDiagnosis) GetRequirements() []string {
+func (x *JobTimelineItem) GetTitle() string {
 	if x != nil {
-		return x.Requirements
+		return x.Title
 	}
-	return nil
+	return ""
 }
 
-func (x *SchedulingDiagnosis) GetAgents() []*SchedulingAgentAssessment {
+func (x *JobTimelineItem) GetDescription() string {
 	if x != nil {
-		return x.Agents
+		return x.Description
 	}
-	return nil
+	return ""
 }
 
-func (x *SchedulingDiagnosis) GetRequirementsLabel() string {
+func (x *JobTimelineItem) GetStatus() string {
 	if x != nil {
-		return x.RequirementsLabel
+		return x.Status
 	}
 	return ""
 }
 
-func (x *SchedulingDiagnosis) GetAdditionalAgentsLabel() string {
+func (x *JobTimelineItem) GetStatusLabel() string {
 	if x != nil {
-		return x.AdditionalAgentsLabel
+		return x.StatusLabel
 	}
 	return ""
 }
 
-type SchedulingAgentAssessment struct {
+func (x *JobTimelineItem) GetDuration() string {
+	if x != nil {
+		return x.Duration
+	}
+	return ""
+}
+
+func (x *JobTimelineItem) GetExitCode() string {
+	if x != nil {
+		return x.ExitCode
+	}
+	return ""
+}
+
+func (x *JobTimelineItem) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *JobTimelineItem) GetProgress() *Progress {
+	if x != nil {
+		return x.Progress
+	}
+	return nil
+}
+
+type JobOutputGroup struct {
 	state           protoimpl.MessageState `protogen:"open.v1"`
-	AgentId         string                 `protobuf:"bytes,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
-	Status          string                 `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
-	Details         string                 `protobuf:"bytes,3,opt,name=details,proto3" json:"details,omitempty"`
-	Tone            string                 `protobuf:"bytes,4,opt,name=tone,proto3" json:"tone,omitempty"`
-	CapabilityMatch bool                   `protobuf:"varint,5,opt,name=capability_match,json=capabilityMatch,proto3" json:"capability_match,omitempty"`
-	Available       bool                   `protobuf:"varint,6,opt,name=available,proto3" json:"available,omitempty"`
+	Id              string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	StateKey        string                 `protobuf:"bytes,2,opt,name=state_key,json=stateKey,proto3" json:"state_key,omitempty"`
+	Kind            string                 `protobuf:"bytes,3,opt,name=kind,proto3" json:"kind,omitempty"`
+	Title           string                 `protobuf:"bytes,4,opt,name=title,proto3" json:"title,omitempty"`
+	CommandSummary  string                 `protobuf:"bytes,5,opt,name=command_summary,json=commandSummary,proto3" json:"command_summary,omitempty"`
+	Status          string                 `protobuf:"bytes,6,opt,name=status,proto3" json:"status,omitempty"`
+	StatusLabel     string                 `protobuf:"bytes,7,opt,name=status_label,json=statusLabel,proto3" json:"status_label,omitempty"`
+	Reached         bool                   `protobuf:"varint,8,opt,name=reached,proto3" json:"reached,omitempty"`
+	Started         string                 `protobuf:"bytes,9,opt,name=started,proto3" json:"started,omitempty"`
+	Duration        string                 `protobuf:"bytes,10,opt,name=duration,proto3" json:"duration,omitempty"`
+	ExitCode        string                 `protobuf:"bytes,11,opt,name=exit_code,json=exitCode,proto3" json:"exit_code,omitempty"`
+	Error           string                 `protobuf:"bytes,12,opt,name=error,proto3" json:"error,omitempty"`
+	Details         string                 `protobuf:"bytes,13,opt,name=details,proto3" json:"details,omitempty"`
+	YamlLiteral     string                 `protobuf:"bytes,14,opt,name=yaml_literal,json=yamlLiteral,proto3" json:"yaml_literal,omitempty"`
+	ExpandedCommand string                 `protobuf:"bytes,15,opt,name=expanded_command,json=expandedCommand,proto3" json:"expanded_command,omitempty"`
+	Progress        *Progress              `protobuf:"bytes,16,opt,name=progress,proto3" json:"progress,omitempty"`
+	DefaultExpanded bool                   `protobuf:"varint,17,opt,name=default_expanded,json=defaultExpanded,proto3" json:"default_expanded,omitempty"`
 	unknownFields   protoimpl.UnknownFields
 	sizeCache       protoimpl.SizeCache
 }
 
-func (x *SchedulingAgentAssessment) Reset() {
-	*x = SchedulingAgentAssessment{}
-	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[25]
+func (x *JobOutputGroup) Reset() {
+	*x = JobOutputGroup{}
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[36]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *SchedulingAgentAssessment) String() string {
+func (x *JobOutputGroup) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SchedulingAgentAssessment) ProtoMessage() {}
+func (*JobOutputGroup) ProtoMessage() {}
 
-func (x *SchedulingAgentAssessment) ProtoReflect() protoreflect.Message {
-	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[25]
+func (x *JobOutputGroup) ProtoReflect() protoreflect.Message {
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[36]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2446,120 +3331,153 @@ func (x *SchedulingAgentAssessment) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SchedulingAgentAssessment.ProtoReflect.Descriptor instead.
-func (*SchedulingAgentAssessment) Descriptor() ([]byte, []int) {
-	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{25}
+// Deprecated: Use JobOutputGroup.ProtoReflect.Descriptor instead.
+func (*JobOutputGroup) Descriptor() ([]byte, []int) {
+	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *JobOutputGroup) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *JobOutputGroup) GetStateKey() string {
+	if x != nil {
+		return x.StateKey
+	}
+	return ""
+}
+
+func (x *JobOutputGroup) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
 }
 
-func (x *SchedulingAgentAssessment) GetAgentId() string {
+func (x *JobOutputGroup) GetTitle() string {
 	if x != nil {
-		return x.AgentId
+		return x.Title
 	}
 	return ""
 }
 
-func (x *SchedulingAgentAssessment) GetStatus() string {
+func (x *JobOutputGroup) GetCommandSummary() string {
 	if x != nil {
-		return x.Status
+		return x.CommandSummary
 	}
 	return ""
 }
 
-func (x *SchedulingAgentAssessment) GetDetails() string {
+func (x *JobOutputGroup) GetStatus() string {
 	if x != nil {
-		return x.Details
+		return x.Status
 	}
 	return ""
 }
 
-func (x *SchedulingAgentAssessment) GetTone() string {
+func (x *JobOutputGroup) GetStatusLabel() string {
 	if x != nil {
-		return x.Tone
+		return x.StatusLabel
 	}
 	return ""
 }
 
-func (x *SchedulingAgentAssessment) GetCapabilityMatch() bool {
+func (x *JobOutputGroup) GetReached() bool {
 	if x != nil {
-		return x.CapabilityMatch
+		return x.Reached
 	}
 	return false
 }
 
-func (x *SchedulingAgentAssessment) GetAvailable() bool {
+func (x *JobOutputGroup) GetStarted() string {
 	if x != nil {
-		return x.Available
+		return x.Started
 	}
-	return false
+	return ""
 }
 
-type ControlExecutionRequest struct {
-	state          protoimpl.MessageState `protogen:"open.v1"`
-	JobExecutionId string                 `protobuf:"bytes,1,opt,name=job_execution_id,json=jobExecutionId,proto3" json:"job_execution_id,omitempty"`
-	unknownFields  protoimpl.UnknownFields
-	sizeCache      protoimpl.SizeCache
+func (x *JobOutputGroup) GetDuration() string {
+	if x != nil {
+		return x.Duration
+	}
+	return ""
 }
 
-func (x *ControlExecutionRequest) Reset() {
-	*x = ControlExecutionRequest{}
-	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[26]
-	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-	ms.StoreMessageInfo(mi)
+func (x *JobOutputGroup) GetExitCode() string {
+	if x != nil {
+		return x.ExitCode
+	}
+	return ""
 }
 
-func (x *ControlExecutionRequest) String() string {
-	return protoimpl.X.MessageStringOf(x)
+func (x *JobOutputGroup) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
 }
 
-func (*ControlExecutionRequest) ProtoMessage() {}
-
-func (x *ControlExecutionRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[26]
+func (x *JobOutputGroup) GetDetails() string {
 	if x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
+		return x.Details
 	}
-	return mi.MessageOf(x)
+	return ""
 }
 
-// Deprecated: Use ControlExecutionRequest.ProtoReflect.Descriptor instead.
-func (*ControlExecutionRequest) Descriptor() ([]byte, []int) {
-	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{26}
+func (x *JobOutputGroup) GetYamlLiteral() string {
+	if x != nil {
+		return x.YamlLiteral
+	}
+	return ""
 }
 
-func (x *ControlExecutionRequest) GetJobExecutionId() string {
+func (x *JobOutputGroup) GetExpandedCommand() string {
 	if x != nil {
-		return x.JobExecutionId
+		return x.ExpandedCommand
 	}
 	return ""
 }
 
-type CancelExecutionResult struct {
+func (x *JobOutputGroup) GetProgress() *Progress {
+	if x != nil {
+		return x.Progress
+	}
+	return nil
+}
+
+func (x *JobOutputGroup) GetDefaultExpanded() bool {
+	if x != nil {
+		return x.DefaultExpanded
+	}
+	return false
+}
+
+type WatchJobOutputRequest struct {
 	state          protoimpl.MessageState `protogen:"open.v1"`
 	JobExecutionId string                 `protobuf:"bytes,1,opt,name=job_execution_id,json=jobExecutionId,proto3" json:"job_execution_id,omitempty"`
-	Status         string                 `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
+	AfterEventId   int64                  `protobuf:"varint,2,opt,name=after_event_id,json=afterEventId,proto3" json:"after_event_id,omitempty"`
 	unknownFields  protoimpl.UnknownFields
 	sizeCache      protoimpl.SizeCache
 }
 
-func (x *CancelExecutionResult) Reset() {
-	*x = CancelExecutionResult{}
-	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[27]
+func (x *WatchJobOutputRequest) Reset() {
+	*x = WatchJobOutputRequest{}
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[37]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *CancelExecutionResult) String() string {
+func (x *WatchJobOutputRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CancelExecutionResult) ProtoMessage() {}
+func (*WatchJobOutputRequest) ProtoMessage() {}
 
-func (x *CancelExecutionResult) ProtoReflect() protoreflect.Message {
-	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[27]
+func (x *WatchJobOutputRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[37]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2570,49 +3488,51 @@ func (x *CancelExecutionResult) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CancelExecutionResult.ProtoReflect.Descriptor instead.
-func (*CancelExecutionResult) Descriptor() ([]byte, []int) {
-	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{27}
+// Deprecated: Use WatchJobOutputRequest.ProtoReflect.Descriptor instead.
+func (*WatchJobOutputRequest) Descriptor() ([]byte, []int) {
+	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{37}
 }
 
-func (x *CancelExecutionResult) GetJobExecutionId() string {
+func (x *WatchJobOutputRequest) GetJobExecutionId() string {
 	if x != nil {
 		return x.JobExecutionId
 	}
 	return ""
 }
 
-func (x *CancelExecutionResult) GetStatus() string {
+func (x *WatchJobOutputRequest) GetAfterEventId() int64 {
 	if x != nil {
-		return x.Status
+		return x.AfterEventId
 	}
-	return ""
+	return 0
 }
 
-type RerunExecutionResult struct {
-	state                  protoimpl.MessageState `protogen:"open.v1"`
-	OriginalJobExecutionId string                 `protobuf:"bytes,1,opt,name=original_job_execution_id,json=originalJobExecutionId,proto3" json:"original_job_execution_id,omitempty"`
-	JobExecutionId         string                 `protobuf:"bytes,2,opt,name=job_execution_id,json=jobExecutionId,proto3" json:"job_execution_id,omitempty"`
-	Status                 string                 `protobuf:"bytes,3,opt,name=status,proto3" json:"status,omitempty"`
-	unknownFields          protoimpl.UnknownFields
-	sizeCache              protoimpl.SizeCache
+type JobOutputBatch struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	JobExecutionId string                 `protobuf:"bytes,1,opt,name=job_execution_id,json=jobExecutionId,proto3" json:"job_execution_id,omitempty"`
+	NextEventId    int64                  `protobuf:"varint,2,opt,name=next_event_id,json=nextEventId,proto3" json:"next_event_id,omitempty"`
+	Events         []*JobOutputEvent      `protobuf:"bytes,3,rep,name=events,proto3" json:"events,omitempty"`
+	HasMore        bool                   `protobuf:"varint,4,opt,name=has_more,json=hasMore,proto3" json:"has_more,omitempty"`
+	Terminal       bool                   `protobuf:"varint,5,opt,name=terminal,proto3" json:"terminal,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
 }
 
-func (x *RerunExecutionResult) Reset() {
-	*x = RerunExecutionResult{}
-	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[28]
+func (x *JobOutputBatch) Reset() {
+	*x = JobOutputBatch{}
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[38]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *RerunExecutionResult) String() string {
+func (x *JobOutputBatch) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*RerunExecutionResult) ProtoMessage() {}
+func (*JobOutputBatch) ProtoMessage() {}
 
-func (x *RerunExecutionResult) ProtoReflect() protoreflect.Message {
-	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[28]
+func (x *JobOutputBatch) ProtoReflect() protoreflect.Message {
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[38]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2623,186 +3543,152 @@ func (x *RerunExecutionResult) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use RerunExecutionResult.ProtoReflect.Descriptor instead.
-func (*RerunExecutionResult) Descriptor() ([]byte, []int) {
-	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{28}
+// Deprecated: Use JobOutputBatch.ProtoReflect.Descriptor instead.
+func (*JobOutputBatch) Descriptor() ([]byte, []int) {
+	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{38}
 }
 
-func (x *RerunExecutionResult) GetOriginalJobExecutionId() string {
+func (x *JobOutputBatch) GetJobExecutionId() string {
 	if x != nil {
-		return x.OriginalJobExecutionId
+		return x.JobExecutionId
 	}
 	return ""
 }
 
-func (x *RerunExecutionResult) GetJobExecutionId() string {
+func (x *JobOutputBatch) GetNextEventId() int64 {
 	if x != nil {
-		return x.JobExecutionId
+		return x.NextEventId
 	}
-	return ""
+	return 0
 }
 
-func (x *RerunExecutionResult) GetStatus() string {
+func (x *JobOutputBatch) GetEvents() []*JobOutputEvent {
 	if x != nil {
-		return x.Status
+		return x.Events
 	}
-	return ""
-}
-
-type JobTimelineItem struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	Kind          string                 `protobuf:"bytes,2,opt,name=kind,proto3" json:"kind,omitempty"`
-	Title         string                 `protobuf:"bytes,3,opt,name=title,proto3" json:"title,omitempty"`
-	Description   string                 `protobuf:"bytes,4,opt,name=description,proto3" json:"description,omitempty"`
-	Status        string                 `protobuf:"bytes,5,opt,name=status,proto3" json:"status,omitempty"`
-	StatusLabel   string                 `protobuf:"bytes,6,opt,name=status_label,json=statusLabel,proto3" json:"status_label,omitempty"`
-	Duration      string                 `protobuf:"bytes,7,opt,name=duration,proto3" json:"duration,omitempty"`
-	ExitCode      string                 `protobuf:"bytes,8,opt,name=exit_code,json=exitCode,proto3" json:"exit_code,omitempty"`
-	Error         string                 `protobuf:"bytes,9,opt,name=error,proto3" json:"error,omitempty"`
-	Progress      *Progress              `protobuf:"bytes,10,opt,name=progress,proto3" json:"progress,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
-}
-
-func (x *JobTimelineItem) Reset() {
-	*x = JobTimelineItem{}
-	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[29]
-	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-	ms.StoreMessageInfo(mi)
+	return nil
 }
 
-func (x *JobTimelineItem) String() string {
-	return protoimpl.X.MessageStringOf(x)
+func (x *JobOutputBatch) GetHasMore() bool {
+	if x != nil {
+		return x.HasMore
+	}
+	return false
 }
 
-func (*JobTimelineItem) ProtoMessage() {}
-
-func (x *JobTimelineItem) ProtoReflect() protoreflect.Message {
-	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[29]
+func (x *JobOutputBatch) GetTerminal() bool {
 	if x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
+		return x.Terminal
 	}
-	return mi.MessageOf(x)
+	return false
 }
 
-// Deprecated: Use JobTimelineItem.ProtoReflect.Descriptor instead.
-func (*JobTimelineItem) Descriptor() ([]byte, []int) {
-	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{29}
+type JobOutputEvent struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	EventId       int64                  `protobuf:"varint,1,opt,name=event_id,json=eventId,proto3" json:"event_id,omitempty"`
+	Type          string                 `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"`
+	ItemId        string                 `protobuf:"bytes,3,opt,name=item_id,json=itemId,proto3" json:"item_id,omitempty"`
+	Text          string                 `protobuf:"bytes,4,opt,name=text,proto3" json:"text,omitempty"`
+	Error         string                 `protobuf:"bytes,5,opt,name=error,proto3" json:"error,omitempty"`
+	ExitCode      string                 `protobuf:"bytes,6,opt,name=exit_code,json=exitCode,proto3" json:"exit_code,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
-func (x *JobTimelineItem) GetId() string {
-	if x != nil {
-		return x.Id
-	}
-	return ""
+func (x *JobOutputEvent) Reset() {
+	*x = JobOutputEvent{}
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[39]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
-
-func (x *JobTimelineItem) GetKind() string {
-	if x != nil {
-		return x.Kind
-	}
-	return ""
+
+func (x *JobOutputEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
 
-func (x *JobTimelineItem) GetTitle() string {
+func (*JobOutputEvent) ProtoMessage() {}
+
+func (x *JobOutputEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[39]
 	if x != nil {
-		return x.Title
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return ""
+	return mi.MessageOf(x)
 }
 
-func (x *JobTimelineItem) GetDescription() string {
-	if x != nil {
-		return x.Description
-	}
-	return ""
+// Deprecated: Use JobOutputEvent.ProtoReflect.Descriptor instead.
+func (*JobOutputEvent) Descriptor() ([]byte, []int) {
+	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{39}
 }
 
-func (x *JobTimelineItem) GetStatus() string {
+func (x *JobOutputEvent) GetEventId() int64 {
 	if x != nil {
-		return x.Status
+		return x.EventId
 	}
-	return ""
+	return 0
 }
 
-func (x *JobTimelineItem) GetStatusLabel() string {
+func (x *JobOutputEvent) GetType() string {
 	if x != nil {
-		return x.StatusLabel
+		return x.Type
 	}
 	return ""
 }
 
-func (x *JobTimelineItem) GetDuration() string {
+func (x *JobOutputEvent) GetItemId() string {
 	if x != nil {
-		return x.Duration
+		return x.ItemId
 	}
 	return ""
 }
 
-func (x *JobTimelineItem) GetExitCode() string {
+func (x *JobOutputEvent) GetText() string {
 	if x != nil {
-		return x.ExitCode
+		return x.Text
 	}
 	return ""
 }
 
-func (x *JobTimelineItem) GetError() string {
+func (x *JobOutputEvent) GetError() string {
 	if x != nil {
 		return x.Error
 	}
 	return ""
 }
 
-func (x *JobTimelineItem) GetProgress() *Progress {
+func (x *JobOutputEvent) GetExitCode() string {
 	if x != nil {
-		return x.Progress
+		return x.ExitCode
 	}
-	return nil
+	return ""
 }
 
-type JobOutputGroup struct {
-	state           protoimpl.MessageState `protogen:"open.v1"`
-	Id              string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	StateKey        string                 `protobuf:"bytes,2,opt,name=state_key,json=stateKey,proto3" json:"state_key,omitempty"`
-	Kind            string                 `protobuf:"bytes,3,opt,name=kind,proto3" json:"kind,omitempty"`
-	Title           string                 `protobuf:"bytes,4,opt,name=title,proto3" json:"title,omitempty"`
-	CommandSummary  string                 `protobuf:"bytes,5,opt,name=command_summary,json=commandSummary,proto3" json:"command_summary,omitempty"`
-	Status          string                 `protobuf:"bytes,6,opt,name=status,proto3" json:"status,omitempty"`
-	StatusLabel     string                 `protobuf:"bytes,7,opt,name=status_label,json=statusLabel,proto3" json:"status_label,omitempty"`
-	Reached         bool                   `protobuf:"varint,8,opt,name=reached,proto3" json:"reached,omitempty"`
-	Started         string                 `protobuf:"bytes,9,opt,name=started,proto3" json:"started,omitempty"`
-	Duration        string                 `protobuf:"bytes,10,opt,name=duration,proto3" json:"duration,omitempty"`
-	ExitCode        string                 `protobuf:"bytes,11,opt,name=exit_code,json=exitCode,proto3" json:"exit_code,omitempty"`
-	Error           string                 `protobuf:"bytes,12,opt,name=error,proto3" json:"error,omitempty"`
-	Details         string                 `protobuf:"bytes,13,opt,name=details,proto3" json:"details,omitempty"`
-	YamlLiteral     string                 `protobuf:"bytes,14,opt,name=yaml_literal,json=yamlLiteral,proto3" json:"yaml_literal,omitempty"`
-	ExpandedCommand string                 `protobuf:"bytes,15,opt,name=expanded_command,json=expandedCommand,proto3" json:"expanded_command,omitempty"`
-	Progress        *Progress              `protobuf:"bytes,16,opt,name=progress,proto3" json:"progress,omitempty"`
-	DefaultExpanded bool                   `protobuf:"varint,17,opt,name=default_expanded,json=defaultExpanded,proto3" json:"default_expanded,omitempty"`
-	unknownFields   protoimpl.UnknownFields
-	sizeCache       protoimpl.SizeCache
+type JobLogDescriptorRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	JobExecutionId string                 `protobuf:"bytes,1,opt,name=job_execution_id,json=jobExecutionId,proto3" json:"job_execution_id,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
 }
 
-func (x *JobOutputGroup) Reset() {
-	*x = JobOutputGroup{}
-	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[30]
+func (x *JobLogDescriptorRequest) Reset() {
+	*x = JobLogDescriptorRequest{}
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[40]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *JobOutputGroup) String() string {
+func (x *JobLogDescriptorRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*JobOutputGroup) ProtoMessage() {}
+func (*JobLogDescriptorRequest) ProtoMessage() {}
 
-func (x *JobOutputGroup) ProtoReflect() protoreflect.Message {
-	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[30]
+func (x *JobLogDescriptorRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[40]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2813,153 +3699,169 @@ func (x *JobOutputGroup) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use JobOutputGroup.ProtoReflect.Descriptor instead.
-func (*JobOutputGroup) Descriptor() ([]byte, []int) {
-	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{30}
+// Deprecated: Use JobLogDescriptorRequest.ProtoReflect.Descriptor instead.
+func (*JobLogDescriptorRequest) Descriptor() ([]byte, []int) {
+	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{40}
 }
 
-func (x *JobOutputGroup) GetId() string {
+func (x *JobLogDescriptorRequest) GetJobExecutionId() string {
 	if x != nil {
-		return x.Id
+		return x.JobExecutionId
 	}
 	return ""
 }
 
-func (x *JobOutputGroup) GetStateKey() string {
-	if x != nil {
-		return x.StateKey
-	}
-	return ""
+type JobLogPageRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	JobExecutionId string                 `protobuf:"bytes,1,opt,name=job_execution_id,json=jobExecutionId,proto3" json:"job_execution_id,omitempty"`
+	ItemId         string                 `protobuf:"bytes,2,opt,name=item_id,json=itemId,proto3" json:"item_id,omitempty"`
+	Mode           JobLogPageMode         `protobuf:"varint,3,opt,name=mode,proto3,enum=ciwi.native.v1.JobLogPageMode" json:"mode,omitempty"`
+	Cursor         int64                  `protobuf:"varint,4,opt,name=cursor,proto3" json:"cursor,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
 }
 
-func (x *JobOutputGroup) GetKind() string {
-	if x != nil {
-		return x.Kind
-	}
-	return ""
+func (x *JobLogPageRequest) Reset() {
+	*x = JobLogPageRequest{}
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[41]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
 
-func (x *JobOutputGroup) GetTitle() string {
-	if x != nil {
-		return x.Title
-	}
-	return ""
+func (x *JobLogPageRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
 
-func (x *JobOutputGroup) GetCommandSummary() string {
+func (*JobLogPageRequest) ProtoMessage() {}
+
+func (x *JobLogPageRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[41]
 	if x != nil {
-		return x.CommandSummary
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return ""
+	return mi.MessageOf(x)
 }
 
-func (x *JobOutputGroup) GetStatus() string {
+// Deprecated: Use JobLogPageRequest.ProtoReflect.Descriptor instead.
+func (*JobLogPageRequest) Descriptor() ([]byte, []int) {
+	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{41}
+}
+
+func (x *JobLogPageRequest) GetJobExecutionId() string {
 	if x != nil {
-		return x.Status
+		return x.JobExecutionId
 	}
 	return ""
 }
 
-func (x *JobOutputGroup) GetStatusLabel() string {
+func (x *JobLogPageRequest) GetItemId() string {
 	if x != nil {
-		return x.StatusLabel
+		return x.ItemId
 	}
 	return ""
 }
 
-func (x *JobOutputGroup) GetReached() bool {
+func (x *JobLogPageRequest) GetMode() JobLogPageMode {
 	if x != nil {
-		return x.Reached
+		return x.Mode
 	}
-	return false
+	return JobLogPageMode_JOB_LOG_PAGE_MODE_UNSPECIFIED
 }
 
-func (x *JobOutputGroup) GetStarted() string {
+func (x *JobLogPageRequest) GetCursor() int64 {
 	if x != nil {
-		return x.Started
+		return x.Cursor
 	}
-	return ""
+	return 0
 }
 
-func (x *JobOutputGroup) GetDuration() string {
-	if x != nil {
-		return x.Duration
-	}
-	return ""
+type JobLogSearchRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	JobExecutionId string                 `protobuf:"bytes,1,opt,name=job_execution_id,json=jobExecutionId,proto3" json:"job_execution_id,omitempty"`
+	Query          string                 `protobuf:"bytes,2,opt,name=query,proto3" json:"query,omitempty"`
+	SelectedIndex  int64                  `protobuf:"varint,3,opt,name=selected_index,json=selectedIndex,proto3" json:"selected_index,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
 }
 
-func (x *JobOutputGroup) GetExitCode() string {
-	if x != nil {
-		return x.ExitCode
-	}
-	return ""
+func (x *JobLogSearchRequest) Reset() {
+	*x = JobLogSearchRequest{}
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[42]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
 
-func (x *JobOutputGroup) GetError() string {
-	if x != nil {
-		return x.Error
-	}
-	return ""
+func (x *JobLogSearchRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
 
-func (x *JobOutputGroup) GetDetails() string {
+func (*JobLogSearchRequest) ProtoMessage() {}
+
+func (x *JobLogSearchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[42]
 	if x != nil {
-		return x.Details
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return ""
+	return mi.MessageOf(x)
 }
 
-func (x *JobOutputGroup) GetYamlLiteral() string {
-	if x != nil {
-		return x.YamlLiteral
-	}
-	return ""
+// Deprecated: Use JobLogSearchRequest.ProtoReflect.Descriptor instead.
+func (*JobLogSearchRequest) Descriptor() ([]byte, []int) {
+	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{42}
 }
 
-func (x *JobOutputGroup) GetExpandedCommand() string {
+func (x *JobLogSearchRequest) GetJobExecutionId() string {
 	if x != nil {
-		return x.ExpandedCommand
+		return x.JobExecutionId
 	}
 	return ""
 }
 
-func (x *JobOutputGroup) GetProgress() *Progress {
+func (x *JobLogSearchRequest) GetQuery() string {
 	if x != nil {
-		return x.Progress
+		return x.Query
 	}
-	return nil
+	return ""
 }
 
-func (x *JobOutputGroup) GetDefaultExpanded() bool {
+func (x *JobLogSearchRequest) GetSelectedIndex() int64 {
 	if x != nil {
-		return x.DefaultExpanded
+		return x.SelectedIndex
 	}
-	return false
+	return 0
 }
 
-type WatchJobOutputRequest struct {
+type WatchJobLogRequest struct {
 	state          protoimpl.MessageState `protogen:"open.v1"`
 	JobExecutionId string                 `protobuf:"bytes,1,opt,name=job_execution_id,json=jobExecutionId,proto3" json:"job_execution_id,omitempty"`
-	AfterEventId   int64                  `protobuf:"varint,2,opt,name=after_event_id,json=afterEventId,proto3" json:"after_event_id,omitempty"`
+	AfterChunkId   int64                  `protobuf:"varint,2,opt,name=after_chunk_id,json=afterChunkId,proto3" json:"after_chunk_id,omitempty"`
 	unknownFields  protoimpl.UnknownFields
 	sizeCache      protoimpl.SizeCache
 }
 
-func (x *WatchJobOutputRequest) Reset() {
-	*x = WatchJobOutputRequest{}
-	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[31]
+func (x *WatchJobLogRequest) Reset() {
+	*x = WatchJobLogRequest{}
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[43]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *WatchJobOutputRequest) String() string {
+func (x *WatchJobLogRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*WatchJobOutputRequest) ProtoMessage() {}
+func (*WatchJobLogRequest) ProtoMessage() {}
 
-func (x *WatchJobOutputRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[31]
+func (x *WatchJobLogRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[43]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2970,51 +3872,52 @@ func (x *WatchJobOutputRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use WatchJobOutputRequest.ProtoReflect.Descriptor instead.
-func (*WatchJobOutputRequest) Descriptor() ([]byte, []int) {
-	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{31}
+// Deprecated: Use WatchJobLogRequest.ProtoReflect.Descriptor instead.
+func (*WatchJobLogRequest) Descriptor() ([]byte, []int) {
+	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{43}
 }
 
-func (x *WatchJobOutputRequest) GetJobExecutionId() string {
+func (x *WatchJobLogRequest) GetJobExecutionId() string {
 	if x != nil {
 		return x.JobExecutionId
 	}
 	return ""
 }
 
-func (x *WatchJobOutputRequest) GetAfterEventId() int64 {
+func (x *WatchJobLogRequest) GetAfterChunkId() int64 {
 	if x != nil {
-		return x.AfterEventId
+		return x.AfterChunkId
 	}
 	return 0
 }
 
-type JobOutputBatch struct {
+type JobLogDescriptor struct {
 	state          protoimpl.MessageState `protogen:"open.v1"`
 	JobExecutionId string                 `protobuf:"bytes,1,opt,name=job_execution_id,json=jobExecutionId,proto3" json:"job_execution_id,omitempty"`
-	NextEventId    int64                  `protobuf:"varint,2,opt,name=next_event_id,json=nextEventId,proto3" json:"next_event_id,omitempty"`
-	Events         []*JobOutputEvent      `protobuf:"bytes,3,rep,name=events,proto3" json:"events,omitempty"`
-	HasMore        bool                   `protobuf:"varint,4,opt,name=has_more,json=hasMore,proto3" json:"has_more,omitempty"`
-	Terminal       bool                   `protobuf:"varint,5,opt,name=terminal,proto3" json:"terminal,omitempty"`
+	Version        int32                  `protobuf:"varint,2,opt,name=version,proto3" json:"version,omitempty"`
+	Available      bool                   `protobuf:"varint,3,opt,name=available,proto3" json:"available,omitempty"`
+	Terminal       bool                   `protobuf:"varint,4,opt,name=terminal,proto3" json:"terminal,omitempty"`
+	LatestChunkId  int64                  `protobuf:"varint,5,opt,name=latest_chunk_id,json=latestChunkId,proto3" json:"latest_chunk_id,omitempty"`
+	Streams        []*JobLogStream        `protobuf:"bytes,6,rep,name=streams,proto3" json:"streams,omitempty"`
 	unknownFields  protoimpl.UnknownFields
 	sizeCache      protoimpl.SizeCache
 }
 
-func (x *JobOutputBatch) Reset() {
-	*x = JobOutputBatch{}
-	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[32]
+func (x *JobLogDescriptor) Reset() {
+	*x = JobLogDescriptor{}
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[44]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *JobOutputBatch) String() string {
+func (x *JobLogDescriptor) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*JobOutputBatch) ProtoMessage() {}
+func (*JobLogDescriptor) ProtoMessage() {}
 
-func (x *JobOutputBatch) ProtoReflect() protoreflect.Message {
-	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[32]
+func (x *JobLogDescriptor) ProtoReflect() protoreflect.Message {
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[44]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3025,73 +3928,79 @@ func (x *JobOutputBatch) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use JobOutputBatch.ProtoReflect.Descriptor instead.
-func (*JobOutputBatch) Descriptor() ([]byte, []int) {
-	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{32}
+// Deprecated: Use JobLogDescriptor.ProtoReflect.Descriptor instead.
+func (*JobLogDescriptor) Descriptor() ([]byte, []int) {
+	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{44}
 }
 
-func (x *JobOutputBatch) GetJobExecutionId() string {
+func (x *JobLogDescriptor) GetJobExecutionId() string {
 	if x != nil {
 		return x.JobExecutionId
 	}
 	return ""
 }
 
-func (x *JobOutputBatch) GetNextEventId() int64 {
+func (x *JobLogDescriptor) GetVersion() int32 {
 	if x != nil {
-		return x.NextEventId
+		return x.Version
 	}
 	return 0
 }
 
-func (x *JobOutputBatch) GetEvents() []*JobOutputEvent {
+func (x *JobLogDescriptor) GetAvailable() bool {
 	if x != nil {
-		return x.Events
+		return x.Available
 	}
-	return nil
+	return false
 }
 
-func (x *JobOutputBatch) GetHasMore() bool {
+func (x *JobLogDescriptor) GetTerminal() bool {
 	if x != nil {
-		return x.HasMore
+		return x.Terminal
 	}
 	return false
 }
 
-func (x *JobOutputBatch) GetTerminal() bool {
+func (x *JobLogDescriptor) GetLatestChunkId() int64 {
 	if x != nil {
-		return x.Terminal
+		return x.LatestChunkId
 	}
-	return false
+	return 0
 }
 
-type JobOutputEvent struct {
+func (x *JobLogDescriptor) GetStreams() []*JobLogStream {
+	if x != nil {
+		return x.Streams
+	}
+	return nil
+}
+
+type JobLogStream struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	EventId       int64                  `protobuf:"varint,1,opt,name=event_id,json=eventId,proto3" json:"event_id,omitempty"`
-	Type          string                 `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"`
-	ItemId        string                 `protobuf:"bytes,3,opt,name=item_id,json=itemId,proto3" json:"item_id,omitempty"`
-	Text          string                 `protobuf:"bytes,4,opt,name=text,proto3" json:"text,omitempty"`
-	Error         string                 `protobuf:"bytes,5,opt,name=error,proto3" json:"error,omitempty"`
-	ExitCode      string                 `protobuf:"bytes,6,opt,name=exit_code,json=exitCode,proto3" json:"exit_code,omitempty"`
+	ItemId        string                 `protobuf:"bytes,1,opt,name=item_id,json=itemId,proto3" json:"item_id,omitempty"`
+	FirstChunkId  int64                  `protobuf:"varint,2,opt,name=first_chunk_id,json=firstChunkId,proto3" json:"first_chunk_id,omitempty"`
+	LastChunkId   int64                  `protobuf:"varint,3,opt,name=last_chunk_id,json=lastChunkId,proto3" json:"last_chunk_id,omitempty"`
+	ChunkCount    int64                  `protobuf:"varint,4,opt,name=chunk_count,json=chunkCount,proto3" json:"chunk_count,omitempty"`
+	ByteCount     int64                  `protobuf:"varint,5,opt,name=byte_count,json=byteCount,proto3" json:"byte_count,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *JobOutputEvent) Reset() {
-	*x = JobOutputEvent{}
-	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[33]
+func (x *JobLogStream) Reset() {
+	*x = JobLogStream{}
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[45]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *JobOutputEvent) String() string {
+func (x *JobLogStream) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*JobOutputEvent) ProtoMessage() {}
+func (*JobLogStream) ProtoMessage() {}
 
-func (x *JobOutputEvent) ProtoReflect() protoreflect.Message {
-	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[33]
+func (x *JobLogStream) ProtoReflect() protoreflect.Message {
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[45]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3102,122 +4011,75 @@ func (x *JobOutputEvent) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use JobOutputEvent.ProtoReflect.Descriptor instead.
-func (*JobOutputEvent) Descriptor() ([]byte, []int) {
-	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{33}
-}
-
-func (x *JobOutputEvent) GetEventId() int64 {
-	if x != nil {
-		return x.EventId
-	}
-	return 0
-}
-
-func (x *JobOutputEvent) GetType() string {
-	if x != nil {
-		return x.Type
-	}
-	return ""
+// Deprecated: Use JobLogStream.ProtoReflect.Descriptor instead.
+func (*JobLogStream) Descriptor() ([]byte, []int) {
+	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{45}
 }
 
-func (x *JobOutputEvent) GetItemId() string {
+func (x *JobLogStream) GetItemId() string {
 	if x != nil {
 		return x.ItemId
 	}
 	return ""
 }
 
-func (x *JobOutputEvent) GetText() string {
-	if x != nil {
-		return x.Text
-	}
-	return ""
-}
-
-func (x *JobOutputEvent) GetError() string {
+func (x *JobLogStream) GetFirstChunkId() int64 {
 	if x != nil {
-		return x.Error
+		return x.FirstChunkId
 	}
-	return ""
+	return 0
 }
 
-func (x *JobOutputEvent) GetExitCode() string {
+func (x *JobLogStream) GetLastChunkId() int64 {
 	if x != nil {
-		return x.ExitCode
+		return x.LastChunkId
 	}
-	return ""
-}
-
-type JobLogDescriptorRequest struct {
-	state          protoimpl.MessageState `protogen:"open.v1"`
-	JobExecutionId string                 `protobuf:"bytes,1,opt,name=job_execution_id,json=jobExecutionId,proto3" json:"job_execution_id,omitempty"`
-	unknownFields  protoimpl.UnknownFields
-	sizeCache      protoimpl.SizeCache
-}
-
-func (x *JobLogDescriptorRequest) Reset() {
-	*x = JobLogDescriptorRequest{}
-	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[34]
-	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-	ms.StoreMessageInfo(mi)
-}
-
-func (x *JobLogDescriptorRequest) String() string {
-	return protoimpl.X.MessageStringOf(x)
+	return 0
 }
 
-func (*JobLogDescriptorRequest) ProtoMessage() {}
-
-func (x *JobLogDescriptorRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[34]
+func (x *JobLogStream) GetChunkCount() int64 {
 	if x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
+		return x.ChunkCount
 	}
-	return mi.MessageOf(x)
-}
-
-// Deprecated: Use JobLogDescriptorRequest.ProtoReflect.Descriptor instead.
-func (*JobLogDescriptorRequest) Descriptor() ([]byte, []int) {
-	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{34}
+	return 0
 }
 
-func (x *JobLogDescriptorRequest) GetJobExecutionId() string {
+func (x *JobLogStream) GetByteCount() int64 {
 	if x != nil {
-		return x.JobExecutionId
+		return x.ByteCount
 	}
-	return ""
+	return 0
 }
 
-type JobLogPageRequest struct {
+type JobLogPage struct {
 	state          protoimpl.MessageState `protogen:"open.v1"`
 	JobExecutionId string                 `protobuf:"bytes,1,opt,name=job_execution_id,json=jobExecutionId,proto3" json:"job_execution_id,omitempty"`
 	ItemId         string                 `protobuf:"bytes,2,opt,name=item_id,json=itemId,proto3" json:"item_id,omitempty"`
-	Mode           JobLogPageMode         `protobuf:"varint,3,opt,name=mode,proto3,enum=ciwi.native.v1.JobLogPageMode" json:"mode,omitempty"`
-	Cursor         int64                  `protobuf:"varint,4,opt,name=cursor,proto3" json:"cursor,omitempty"`
+	Chunks         []*JobLogChunk         `protobuf:"bytes,3,rep,name=chunks,proto3" json:"chunks,omitempty"`
+	FirstCursor    int64                  `protobuf:"varint,4,opt,name=first_cursor,json=firstCursor,proto3" json:"first_cursor,omitempty"`
+	LastCursor     int64                  `protobuf:"varint,5,opt,name=last_cursor,json=lastCursor,proto3" json:"last_cursor,omitempty"`
+	HasBefore      bool                   `protobuf:"varint,6,opt,name=has_before,json=hasBefore,proto3" json:"has_before,omitempty"`
+	HasAfter       bool                   `protobuf:"varint,7,opt,name=has_after,json=hasAfter,proto3" json:"has_after,omitempty"`
+	Terminal       bool                   `protobuf:"varint,8,opt,name=terminal,proto3" json:"terminal,omitempty"`
 	unknownFields  protoimpl.UnknownFields
 	sizeCache      protoimpl.SizeCache
 }
 
-func (x *JobLogPageRequest) Reset() {
-	*x = JobLogPageRequest{}
-	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[35]
+func (x *JobLogPage) Reset() {
+	*x = JobLogPage{}
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[46]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *JobLogPageRequest) String() string {
+func (x *JobLogPage) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*JobLogPageRequest) ProtoMessage() {}
+func (*JobLogPage) ProtoMessage() {}
 
-func (x *JobLogPageRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[35]
+func (x *JobLogPage) ProtoReflect() protoreflect.Message {
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[46]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3228,122 +4090,93 @@ func (x *JobLogPageRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use JobLogPageRequest.ProtoReflect.Descriptor instead.
-func (*JobLogPageRequest) Descriptor() ([]byte, []int) {
-	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{35}
+// Deprecated: Use JobLogPage.ProtoReflect.Descriptor instead.
+func (*JobLogPage) Descriptor() ([]byte, []int) {
+	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{46}
 }
 
-func (x *JobLogPageRequest) GetJobExecutionId() string {
+func (x *JobLogPage) GetJobExecutionId() string {
 	if x != nil {
 		return x.JobExecutionId
 	}
 	return ""
 }
 
-func (x *JobLogPageRequest) GetItemId() string {
+func (x *JobLogPage) GetItemId() string {
 	if x != nil {
 		return x.ItemId
 	}
 	return ""
 }
 
-func (x *JobLogPageRequest) GetMode() JobLogPageMode {
-	if x != nil {
-		return x.Mode
-	}
-	return JobLogPageMode_JOB_LOG_PAGE_MODE_UNSPECIFIED
-}
-
-func (x *JobLogPageRequest) GetCursor() int64 {
+func (x *JobLogPage) GetChunks() []*JobLogChunk {
 	if x != nil {
-		return x.Cursor
+		return x.Chunks
 	}
-	return 0
-}
-
-type JobLogSearchRequest struct {
-	state          protoimpl.MessageState `protogen:"open.v1"`
-	JobExecutionId string                 `protobuf:"bytes,1,opt,name=job_execution_id,json=jobExecutionId,proto3" json:"job_execution_id,omitempty"`
-	Query          string                 `protobuf:"bytes,2,opt,name=query,proto3" json:"query,omitempty"`
-	SelectedIndex  int64                  `protobuf:"varint,3,opt,name=selected_index,json=selectedIndex,proto3" json:"selected_index,omitempty"`
-	unknownFields  protoimpl.UnknownFields
-	sizeCache      protoimpl.SizeCache
-}
-
-func (x *JobLogSearchRequest) Reset() {
-	*x = JobLogSearchRequest{}
-	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[36]
-	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-	ms.StoreMessageInfo(mi)
-}
-
-func (x *JobLogSearchRequest) String() string {
-	return protoimpl.X.MessageStringOf(x)
+	return nil
 }
 
-func (*JobLogSearchRequest) ProtoMessage() {}
-
-func (x *JobLogSearchRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[36]
+func (x *JobLogPage) GetFirstCursor() int64 {
 	if x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
+		return x.FirstCursor
 	}
-	return mi.MessageOf(x)
+	return 0
 }
 
-// Deprecated: Use JobLogSearchRequest.ProtoReflect.Descriptor instead.
-func (*JobLogSearchRequest) Descriptor() ([]byte, []int) {
-	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{36}
+func (x *JobLogPage) GetLastCursor() int64 {
+	if x != nil {
+		return x.LastCursor
+	}
+	return 0
 }
 
-func (x *JobLogSearchRequest) GetJobExecutionId() string {
+func (x *JobLogPage) GetHasBefore() bool {
 	if x != nil {
-		return x.JobExecutionId
+		return x.HasBefore
 	}
-	return ""
+	return false
 }
 
-func (x *JobLogSearchRequest) GetQuery() string {
+func (x *JobLogPage) GetHasAfter() bool {
 	if x != nil {
-		return x.Query
+		return x.HasAfter
 	}
-	return ""
+	return false
 }
 
-func (x *JobLogSearchRequest) GetSelectedIndex() int64 {
+func (x *JobLogPage) GetTerminal() bool {
 	if x != nil {
-		return x.SelectedIndex
+		return x.Terminal
 	}
-	return 0
+	return false
 }
 
-type WatchJobLogRequest struct {
-	state          protoimpl.MessageState `protogen:"open.v1"`
-	JobExecutionId string                 `protobuf:"bytes,1,opt,name=job_execution_id,json=jobExecutionId,proto3" json:"job_execution_id,omitempty"`
-	AfterChunkId   int64                  `protobuf:"varint,2,opt,name=after_chunk_id,json=afterChunkId,proto3" json:"after_chunk_id,omitempty"`
-	unknownFields  protoimpl.UnknownFields
-	sizeCache      protoimpl.SizeCache
+type JobLogChunk struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	ItemId        string                 `protobuf:"bytes,2,opt,name=item_id,json=itemId,proto3" json:"item_id,omitempty"`
+	Text          string                 `protobuf:"bytes,3,opt,name=text,proto3" json:"text,omitempty"`
+	ByteCount     int32                  `protobuf:"varint,4,opt,name=byte_count,json=byteCount,proto3" json:"byte_count,omitempty"`
+	RuneCount     int32                  `protobuf:"varint,5,opt,name=rune_count,json=runeCount,proto3" json:"rune_count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
-func (x *WatchJobLogRequest) Reset() {
-	*x = WatchJobLogRequest{}
-	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[37]
+func (x *JobLogChunk) Reset() {
+	*x = JobLogChunk{}
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[47]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *WatchJobLogRequest) String() string {
+func (x *JobLogChunk) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*WatchJobLogRequest) ProtoMessage() {}
+func (*JobLogChunk) ProtoMessage() {}
 
-func (x *WatchJobLogRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[37]
+func (x *JobLogChunk) ProtoReflect() protoreflect.Message {
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[47]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3354,52 +4187,72 @@ func (x *WatchJobLogRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use WatchJobLogRequest.ProtoReflect.Descriptor instead.
-func (*WatchJobLogRequest) Descriptor() ([]byte, []int) {
-	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{37}
+// Deprecated: Use JobLogChunk.ProtoReflect.Descriptor instead.
+func (*JobLogChunk) Descriptor() ([]byte, []int) {
+	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{47}
 }
 
-func (x *WatchJobLogRequest) GetJobExecutionId() string {
+func (x *JobLogChunk) GetId() int64 {
 	if x != nil {
-		return x.JobExecutionId
+		return x.Id
+	}
+	return 0
+}
+
+func (x *JobLogChunk) GetItemId() string {
+	if x != nil {
+		return x.ItemId
 	}
 	return ""
 }
 
-func (x *WatchJobLogRequest) GetAfterChunkId() int64 {
+func (x *JobLogChunk) GetText() string {
 	if x != nil {
-		return x.AfterChunkId
+		return x.Text
+	}
+	return ""
+}
+
+func (x *JobLogChunk) GetByteCount() int32 {
+	if x != nil {
+		return x.ByteCount
 	}
 	return 0
 }
 
-type JobLogDescriptor struct {
+func (x *JobLogChunk) GetRuneCount() int32 {
+	if x != nil {
+		return x.RuneCount
+	}
+	return 0
+}
+
+type JobLogSearchResult struct {
 	state          protoimpl.MessageState `protogen:"open.v1"`
 	JobExecutionId string                 `protobuf:"bytes,1,opt,name=job_execution_id,json=jobExecutionId,proto3" json:"job_execution_id,omitempty"`
-	Version        int32                  `protobuf:"varint,2,opt,name=version,proto3" json:"version,omitempty"`
-	Available      bool                   `protobuf:"varint,3,opt,name=available,proto3" json:"available,omitempty"`
-	Terminal       bool                   `protobuf:"varint,4,opt,name=terminal,proto3" json:"terminal,omitempty"`
-	LatestChunkId  int64                  `protobuf:"varint,5,opt,name=latest_chunk_id,json=latestChunkId,proto3" json:"latest_chunk_id,omitempty"`
-	Streams        []*JobLogStream        `protobuf:"bytes,6,rep,name=streams,proto3" json:"streams,omitempty"`
+	Query          string                 `protobuf:"bytes,2,opt,name=query,proto3" json:"query,omitempty"`
+	SelectedIndex  int64                  `protobuf:"varint,3,opt,name=selected_index,json=selectedIndex,proto3" json:"selected_index,omitempty"`
+	TotalMatches   int64                  `protobuf:"varint,4,opt,name=total_matches,json=totalMatches,proto3" json:"total_matches,omitempty"`
+	Match          *JobLogMatch           `protobuf:"bytes,5,opt,name=match,proto3" json:"match,omitempty"`
 	unknownFields  protoimpl.UnknownFields
 	sizeCache      protoimpl.SizeCache
 }
 
-func (x *JobLogDescriptor) Reset() {
-	*x = JobLogDescriptor{}
-	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[38]
+func (x *JobLogSearchResult) Reset() {
+	*x = JobLogSearchResult{}
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[48]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *JobLogDescriptor) String() string {
+func (x *JobLogSearchResult) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*JobLogDescriptor) ProtoMessage() {}
+func (*JobLogSearchResult) ProtoMessage() {}
 
-func (x *JobLogDescriptor) ProtoReflect() protoreflect.Message {
-	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[38]
+func (x *JobLogSearchResult) ProtoReflect() protoreflect.Message {
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[48]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3410,79 +4263,71 @@ func (x *JobLogDescriptor) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use JobLogDescriptor.ProtoReflect.Descriptor instead.
-func (*JobLogDescriptor) Descriptor() ([]byte, []int) {
-	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{38}
+// Deprecated: Use JobLogSearchResult.ProtoReflect.Descriptor instead.
+func (*JobLogSearchResult) Descriptor() ([]byte, []int) {
+	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{48}
 }
 
-func (x *JobLogDescriptor) GetJobExecutionId() string {
+func (x *JobLogSearchResult) GetJobExecutionId() string {
 	if x != nil {
 		return x.JobExecutionId
 	}
 	return ""
 }
 
-func (x *JobLogDescriptor) GetVersion() int32 {
-	if x != nil {
-		return x.Version
-	}
-	return 0
-}
-
-func (x *JobLogDescriptor) GetAvailable() bool {
+func (x *JobLogSearchResult) GetQuery() string {
 	if x != nil {
-		return x.Available
+		return x.Query
 	}
-	return false
+	return ""
 }
 
-func (x *JobLogDescriptor) GetTerminal() bool {
+func (x *JobLogSearchResult) GetSelectedIndex() int64 {
 	if x != nil {
-		return x.Terminal
+		return x.SelectedIndex
 	}
-	return false
+	return 0
 }
 
-func (x *JobLogDescriptor) GetLatestChunkId() int64 {
+func (x *JobLogSearchResult) GetTotalMatches() int64 {
 	if x != nil {
-		return x.LatestChunkId
+		return x.TotalMatches
 	}
 	return 0
 }
 
-func (x *JobLogDescriptor) GetStreams() []*JobLogStream {
+func (x *JobLogSearchResult) GetMatch() *JobLogMatch {
 	if x != nil {
-		return x.Streams
+		return x.Match
 	}
 	return nil
 }
 
-type JobLogStream struct {
+type JobLogMatch struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	ItemId        string                 `protobuf:"bytes,1,opt,name=item_id,json=itemId,proto3" json:"item_id,omitempty"`
-	FirstChunkId  int64                  `protobuf:"varint,2,opt,name=first_chunk_id,json=firstChunkId,proto3" json:"first_chunk_id,omitempty"`
-	LastChunkId   int64                  `protobuf:"varint,3,opt,name=last_chunk_id,json=lastChunkId,proto3" json:"last_chunk_id,omitempty"`
-	ChunkCount    int64                  `protobuf:"varint,4,opt,name=chunk_count,json=chunkCount,proto3" json:"chunk_count,omitempty"`
-	ByteCount     int64                  `protobuf:"varint,5,opt,name=byte_count,json=byteCount,proto3" json:"byte_count,omitempty"`
+	ChunkId       int64                  `protobuf:"varint,2,opt,name=chunk_id,json=chunkId,proto3" json:"chunk_id,omitempty"`
+	StartRune     int32                  `protobuf:"varint,3,opt,name=start_rune,json=startRune,proto3" json:"start_rune,omitempty"`
+	EndRune       int32                  `protobuf:"varint,4,opt,name=end_rune,json=endRune,proto3" json:"end_rune,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *JobLogStream) Reset() {
-	*x = JobLogStream{}
-	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[39]
+func (x *JobLogMatch) Reset() {
+	*x = JobLogMatch{}
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[49]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *JobLogStream) String() string {
+func (x *JobLogMatch) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*JobLogStream) ProtoMessage() {}
+func (*JobLogMatch) ProtoMessage() {}
 
-func (x *JobLogStream) ProtoReflect() protoreflect.Message {
-	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[39]
+func (x *JobLogMatch) ProtoReflect() protoreflect.Message {
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[49]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3493,75 +4338,65 @@ func (x *JobLogStream) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use JobLogStream.ProtoReflect.Descriptor instead.
-func (*JobLogStream) Descriptor() ([]byte, []int) {
-	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{39}
+// Deprecated: Use JobLogMatch.ProtoReflect.Descriptor instead.
+func (*JobLogMatch) Descriptor() ([]byte, []int) {
+	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{49}
 }
 
-func (x *JobLogStream) GetItemId() string {
+func (x *JobLogMatch) GetItemId() string {
 	if x != nil {
 		return x.ItemId
 	}
 	return ""
 }
 
-func (x *JobLogStream) GetFirstChunkId() int64 {
-	if x != nil {
-		return x.FirstChunkId
-	}
-	return 0
-}
-
-func (x *JobLogStream) GetLastChunkId() int64 {
+func (x *JobLogMatch) GetChunkId() int64 {
 	if x != nil {
-		return x.LastChunkId
+		return x.ChunkId
 	}
 	return 0
 }
 
-func (x *JobLogStream) GetChunkCount() int64 {
+func (x *JobLogMatch) GetStartRune() int32 {
 	if x != nil {
-		return x.ChunkCount
+		return x.StartRune
 	}
 	return 0
 }
 
-func (x *JobLogStream) GetByteCount() int64 {
+func (x *JobLogMatch) GetEndRune() int32 {
 	if x != nil {
-		return x.ByteCount
+		return x.EndRune
 	}
 	return 0
 }
 
-type JobLogPage struct {
-	state          protoimpl.MessageState `protogen:"open.v1"`
-	JobExecutionId string                 `protobuf:"bytes,1,opt,name=job_execution_id,json=jobExecutionId,proto3" json:"job_execution_id,omitempty"`
-	ItemId         string                 `protobuf:"bytes,2,opt,name=item_id,json=itemId,proto3" json:"item_id,omitempty"`
-	Chunks         []*JobLogChunk         `protobuf:"bytes,3,rep,name=chunks,proto3" json:"chunks,omitempty"`
-	FirstCursor    int64                  `protobuf:"varint,4,opt,name=first_cursor,json=firstCursor,proto3" json:"first_cursor,omitempty"`
-	LastCursor     int64                  `protobuf:"varint,5,opt,name=last_cursor,json=lastCursor,proto3" json:"last_cursor,omitempty"`
-	HasBefore      bool                   `protobuf:"varint,6,opt,name=has_before,json=hasBefore,proto3" json:"has_before,omitempty"`
-	HasAfter       bool                   `protobuf:"varint,7,opt,name=has_after,json=hasAfter,proto3" json:"has_after,omitempty"`
-	Terminal       bool                   `protobuf:"varint,8,opt,name=terminal,proto3" json:"terminal,omitempty"`
-	unknownFields  protoimpl.UnknownFields
-	sizeCache      protoimpl.SizeCache
+type GetTestHistoryRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ProjectId     int64                  `protobuf:"varint,1,opt,name=project_id,json=projectId,proto3" json:"project_id,omitempty"`
+	PipelineId    string                 `protobuf:"bytes,2,opt,name=pipeline_id,json=pipelineId,proto3" json:"pipeline_id,omitempty"`
+	PipelineJobId string                 `protobuf:"bytes,3,opt,name=pipeline_job_id,json=pipelineJobId,proto3" json:"pipeline_job_id,omitempty"`
+	MatrixName    string                 `protobuf:"bytes,4,opt,name=matrix_name,json=matrixName,proto3" json:"matrix_name,omitempty"`
+	Query         string                 `protobuf:"bytes,5,opt,name=query,proto3" json:"query,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
-func (x *JobLogPage) Reset() {
-	*x = JobLogPage{}
-	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[40]
+func (x *GetTestHistoryRequest) Reset() {
+	*x = GetTestHistoryRequest{}
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[50]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *JobLogPage) String() string {
+func (x *GetTestHistoryRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*JobLogPage) ProtoMessage() {}
+func (*GetTestHistoryRequest) ProtoMessage() {}
 
-func (x *JobLogPage) ProtoReflect() protoreflect.Message {
-	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[40]
+func (x *GetTestHistoryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[50]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3572,93 +4407,75 @@ func (x *JobLogPage) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use JobLogPage.ProtoReflect.Descriptor instead.
-func (*JobLogPage) Descriptor() ([]byte, []int) {
-	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{40}
-}
-
-func (x *JobLogPage) GetJobExecutionId() string {
-	if x != nil {
-		return x.JobExecutionId
-	}
-	return ""
-}
-
-func (x *JobLogPage) GetItemId() string {
-	if x != nil {
-		return x.ItemId
-	}
-	return ""
-}
-
-func (x *JobLogPage) GetChunks() []*JobLogChunk {
-	if x != nil {
-		return x.Chunks
-	}
-	return nil
+// Deprecated: Use GetTestHistoryRequest.ProtoReflect.Descriptor instead.
+func (*GetTestHistoryRequest) Descriptor() ([]byte, []int) {
+	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{50}
 }
 
-func (x *JobLogPage) GetFirstCursor() int64 {
+func (x *GetTestHistoryRequest) GetProjectId() int64 {
 	if x != nil {
-		return x.FirstCursor
+		return x.ProjectId
 	}
 	return 0
 }
 
-func (x *JobLogPage) GetLastCursor() int64 {
+func (x *GetTestHistoryRequest) GetPipelineId() string {
 	if x != nil {
-		return x.LastCursor
+		return x.PipelineId
 	}
-	return 0
+	return ""
 }
 
-func (x *JobLogPage) GetHasBefore() bool {
+func (x *GetTestHistoryRequest) GetPipelineJobId() string {
 	if x != nil {
-		return x.HasBefore
+		return x.PipelineJobId
 	}
-	return false
+	return ""
 }
 
-func (x *JobLogPage) GetHasAfter() bool {
+func (x *GetTestHistoryRequest) GetMatrixName() string {
 	if x != nil {
-		return x.HasAfter
+		return x.MatrixName
 	}
-	return false
+	return ""
 }
 
-func (x *JobLogPage) GetTerminal() bool {
+func (x *GetTestHistoryRequest) GetQuery() string {
 	if x != nil {
-		return x.Terminal
+		return x.Query
 	}
-	return false
+	return ""
 }
 
-type JobLogChunk struct {
+type TestHistoryView struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
-	ItemId        string                 `protobuf:"bytes,2,opt,name=item_id,json=itemId,proto3" json:"item_id,omitempty"`
-	Text          string                 `protobuf:"bytes,3,opt,name=text,proto3" json:"text,omitempty"`
-	ByteCount     int32                  `protobuf:"varint,4,opt,name=byte_count,json=byteCount,proto3" json:"byte_count,omitempty"`
-	RuneCount     int32                  `protobuf:"varint,5,opt,name=rune_count,json=runeCount,proto3" json:"rune_count,omitempty"`
+	ProjectId     int64                  `protobuf:"varint,1,opt,name=project_id,json=projectId,proto3" json:"project_id,omitempty"`
+	PipelineId    string                 `protobuf:"bytes,2,opt,name=pipeline_id,json=pipelineId,proto3" json:"pipeline_id,omitempty"`
+	PipelineJobId string                 `protobuf:"bytes,3,opt,name=pipeline_job_id,json=pipelineJobId,proto3" json:"pipeline_job_id,omitempty"`
+	MatrixName    string                 `protobuf:"bytes,4,opt,name=matrix_name,json=matrixName,proto3" json:"matrix_name,omitempty"`
+	Title         string                 `protobuf:"bytes,5,opt,name=title,proto3" json:"title,omitempty"`
+	Summary       string                 `protobuf:"bytes,6,opt,name=summary,proto3" json:"summary,omitempty"`
+	EmptyLabel    string                 `protobuf:"bytes,7,opt,name=empty_label,json=emptyLabel,proto3" json:"empty_label,omitempty"`
+	Tests         []*TestHistoryTest     `protobuf:"bytes,8,rep,name=tests,proto3" json:"tests,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *JobLogChunk) Reset() {
-	*x = JobLogChunk{}
-	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[41]
+func (x *TestHistoryView) Reset() {
+	*x = TestHistoryView{}
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[51]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *JobLogChunk) String() string {
+func (x *TestHistoryView) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*JobLogChunk) ProtoMessage() {}
+func (*TestHistoryView) ProtoMessage() {}
 
-func (x *JobLogChunk) ProtoReflect() protoreflect.Message {
-	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[41]
+func (x *TestHistoryView) ProtoReflect() protoreflect.Message {
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[51]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3669,72 +4486,103 @@ func (x *JobLogChunk) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use JobLogChunk.ProtoReflect.Descriptor instead.
-func (*JobLogChunk) Descriptor() ([]byte, []int) {
-	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{41}
+// Deprecated: Use TestHistoryView.ProtoReflect.Descriptor instead.
+func (*TestHistoryView) Descriptor() ([]byte, []int) {
+	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{51}
 }
 
-func (x *JobLogChunk) GetId() int64 {
+func (x *TestHistoryView) GetProjectId() int64 {
 	if x != nil {
-		return x.Id
+		return x.ProjectId
 	}
 	return 0
 }
 
-func (x *JobLogChunk) GetItemId() string {
+func (x *TestHistoryView) GetPipelineId() string {
 	if x != nil {
-		return x.ItemId
+		return x.PipelineId
 	}
 	return ""
 }
 
-func (x *JobLogChunk) GetText() string {
+func (x *TestHistoryView) GetPipelineJobId() string {
 	if x != nil {
-		return x.Text
+		return x.PipelineJobId
 	}
 	return ""
 }
 
-func (x *JobLogChunk) GetByteCount() int32 {
+func (x *TestHistoryView) GetMatrixName() string {
 	if x != nil {
-		return x.ByteCount
+		return x.MatrixName
 	}
-	return 0
+	return ""
 }
 
-func (x *JobLogChunk) GetRuneCount() int32 {
+func (x *TestHistoryView) GetTitle() string {
 	if x != nil {
-		return x.RuneCount
+		return x.Title
 	}
-	return 0
+	return ""
 }
 
-type JobLogSearchResult struct {
-	state          protoimpl.MessageState `protogen:"open.v1"`
-	JobExecutionId string                 `protobuf:"bytes,1,opt,name=job_execution_id,json=jobExecutionId,proto3" json:"job_execution_id,omitempty"`
-	Query          string                 `protobuf:"bytes,2,opt,name=query,proto3" json:"query,omitempty"`
-	SelectedIndex  int64                  `protobuf:"varint,3,opt,name=selected_index,json=selectedIndex,proto3" json:"selected_index,omitempty"`
-	TotalMatches   int64                  `protobuf:"varint,4,opt,name=total_matches,json=totalMatches,proto3" json:"total_matches,omitempty"`
-	Match          *JobLogMatch           `protobuf:"bytes,5,opt,name=match,proto3" json:"match,omitempty"`
-	unknownFields  protoimpl.UnknownFields
-	sizeCache      protoimpl.SizeCache
+func (x *TestHistoryView) GetSummary() string {
+	if x != nil {
+		return x.Summary
+	}
+	return ""
 }
 
-func (x *JobLogSearchResult) Reset() {
-	*x = JobLogSearchResult{}
-	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[42]
+func (x *TestHistoryView) GetEmptyLabel() string {
+	if x != nil {
+		return x.EmptyLabel
+	}
+	return ""
+}
+
+func (x *TestHistoryView) GetTests() []*TestHistoryTest {
+	if x != nil {
+		return x.Tests
+	}
+	return nil
+}
+
+type TestHistoryTest struct {
+	state                protoimpl.MessageState `protogen:"open.v1"`
+	Key                  string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Package              string                 `protobuf:"bytes,2,opt,name=package,proto3" json:"package,omitempty"`
+	Name                 string                 `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	Status               string                 `protobuf:"bytes,4,opt,name=status,proto3" json:"status,omitempty"`
+	StatusLabel          string                 `protobuf:"bytes,5,opt,name=status_label,json=statusLabel,proto3" json:"status_label,omitempty"`
+	Tone                 string                 `protobuf:"bytes,6,opt,name=tone,proto3" json:"tone,omitempty"`
+	Badge                string                 `protobuf:"bytes,7,opt,name=badge,proto3" json:"badge,omitempty"`
+	BadgeLabel           string                 `protobuf:"bytes,8,opt,name=badge_label,json=badgeLabel,proto3" json:"badge_label,omitempty"`
+	RunsLabel            string                 `protobuf:"bytes,9,opt,name=runs_label,json=runsLabel,proto3" json:"runs_label,omitempty"`
+	StreakLabel          string                 `protobuf:"bytes,10,opt,name=streak_label,json=streakLabel,proto3" json:"streak_label,omitempty"`
+	FlakinessScore       float64                `protobuf:"fixed64,11,opt,name=flakiness_score,json=flakinessScore,proto3" json:"flakiness_score,omitempty"`
+	FlakinessLabel       string                 `protobuf:"bytes,12,opt,name=flakiness_label,json=flakinessLabel,proto3" json:"flakiness_label,omitempty"`
+	DurationLabel        string                 `protobuf:"bytes,13,opt,name=duration_label,json=durationLabel,proto3" json:"duration_label,omitempty"`
+	RecentStatuses       []string               `protobuf:"bytes,14,rep,name=recent_statuses,json=recentStatuses,proto3" json:"recent_statuses,omitempty"`
+	LatestJobExecutionId string                 `protobuf:"bytes,15,opt,name=latest_job_execution_id,json=latestJobExecutionId,proto3" json:"latest_job_execution_id,omitempty"`
+	unknownFields        protoimpl.UnknownFields
+	sizeCache            protoimpl.SizeCache
+}
+
+func (x *TestHistoryTest) Reset() {
+	*x = TestHistoryTest{}
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[52]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *JobLogSearchResult) String() string {
+func (x *TestHistoryTest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*JobLogSearchResult) ProtoMessage() {}
+func (*TestHistoryTest) ProtoMessage() {}
 
-func (x *JobLogSearchResult) ProtoReflect() protoreflect.Message {
-	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[42]
+func (x *TestHistoryTest) ProtoReflect() protoreflect.Message {
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[52]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3745,112 +4593,114 @@ func (x *JobLogSearchResult) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use JobLogSearchResult.ProtoReflect.Descriptor instead.
-func (*JobLogSearchResult) Descriptor() ([]byte, []int) {
-	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{42}
+// Deprecated: Use TestHistoryTest.ProtoReflect.Descriptor instead.
+func (*TestHistoryTest) Descriptor() ([]byte, []int) {
+	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{52}
 }
 
-func (x *JobLogSearchResult) GetJobExecutionId() string {
+func (x *TestHistoryTest) GetKey() string {
 	if x != nil {
-		return x.JobExecutionId
+		return x.Key
 	}
 	return ""
 }
 
-func (x *JobLogSearchResult) GetQuery() string {
+func (x *TestHistoryTest) GetPackage() string {
 	if x != nil {
-		return x.Query
+		return x.Package
 	}
 	return ""
 }
 
-func (x *JobLogSearchResult) GetSelectedIndex() int64 {
+func (x *TestHistoryTest) GetName() string {
 	if x != nil {
-		return x.SelectedIndex
+		return x.Name
 	}
-	return 0
+	return ""
 }
 
-func (x *JobLogSearchResult) GetTotalMatches() int64 {
+func (x *TestHistoryTest) GetStatus() string {
 	if x != nil {
-		return x.TotalMatches
+		return x.Status
 	}
-	return 0
+	return ""
 }
 
-func (x *JobLogSearchResult) GetMatch() *JobLogMatch {
+func (x *TestHistoryTest) GetStatusLabel() string {
 	if x != nil {
-		return x.Match
+		return x.StatusLabel
 	}
-	return nil
+	return ""
 }
 
-type JobLogMatch struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	ItemId        string                 `protobuf:"bytes,1,opt,name=item_id,json=itemId,proto3" json:"item_id,omitempty"`
-	ChunkId       int64                  `protobuf:"varint,2,opt,name=chunk_id,json=chunkId,proto3" json:"chunk_id,omitempty"`
-	StartRune     int32                  `protobuf:"varint,3,opt,name=start_rune,json=startRune,proto3" json:"start_rune,omitempty"`
-	EndRune       int32                  `protobuf:"varint,4,opt,name=end_rune,json=endRune,proto3" json:"end_rune,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+func (x *TestHistoryTest) GetTone() string {
+	if x != nil {
+		return x.Tone
+	}
+	return ""
 }
 
-func (x *JobLogMatch) Reset() {
-	*x = JobLogMatch{}
-	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[43]
-	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-	ms.StoreMessageInfo(mi)
+func (x *TestHistoryTest) GetBadge() string {
+	if x != nil {
+		return x.Badge
+	}
+	return ""
 }
 
-func (x *JobLogMatch) String() string {
-	return protoimpl.X.MessageStringOf(x)
+func (x *TestHistoryTest) GetBadgeLabel() string {
+	if x != nil {
+		return x.BadgeLabel
+	}
+	return ""
 }
 
-func (*JobLogMatch) ProtoMessage() {}
+func (x *TestHistoryTest) GetRunsLabel() string {
+	if x != nil {
+		return x.RunsLabel
+	}
+	return ""
+}
 
-func (x *JobLogMatch) ProtoReflect() protoreflect.Message {
-	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[43]
+func (x *TestHistoryTest) GetStreakLabel() string {
 	if x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
+		return x.StreakLabel
 	}
-	return mi.MessageOf(x)
+	return ""
 }
 
-// Deprecated: Use JobLogMatch.ProtoReflect.Descriptor instead.
-func (*JobLogMatch) Descriptor() ([]byte, []int) {
-	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{43}
+func (x *TestHistoryTest) GetFlakinessScore() float64 {
+	if x != nil {
+		return x.FlakinessScore
+	}
+	return 0
 }
 
-func (x *JobLogMatch) GetItemId() string {
+func (x *TestHistoryTest) GetFlakinessLabel() string {
 	if x != nil {
-		return x.ItemId
+		return x.FlakinessLabel
 	}
 	return ""
 }
 
-func (x *JobLogMatch) GetChunkId() int64 {
+func (x *TestHistoryTest) GetDurationLabel() string {
 	if x != nil {
-		return x.ChunkId
+		return x.DurationLabel
 	}
-	return 0
+	return ""
 }
 
-func (x *JobLogMatch) GetStartRune() int32 {
+func (x *TestHistoryTest) GetRecentStatuses() []string {
 	if x != nil {
-		return x.StartRune
+		return x.RecentStatuses
 	}
-	return 0
+	return nil
 }
 
-func (x *JobLogMatch) GetEndRune() int32 {
+func (x *TestHistoryTest) GetLatestJobExecutionId() string {
 	if x != nil {
-		return x.EndRune
+		return x.LatestJobExecutionId
 	}
-	return 0
+	return ""
 }
 
 type ExecutionSummary struct {
@@ -3860,13 +4710,15 @@ type ExecutionSummary struct {
 	Failed        uint32                 `protobuf:"varint,3,opt,name=failed,proto3" json:"failed,omitempty"`
 	InProgress    uint32                 `protobuf:"varint,4,opt,name=in_progress,json=inProgress,proto3" json:"in_progress,omitempty"`
 	Waiting       uint32                 `protobuf:"varint,5,opt,name=waiting,proto3" json:"waiting,omitempty"`
+	Cancelled     uint32                 `protobuf:"varint,6,opt,name=cancelled,proto3" json:"cancelled,omitempty"`
+	Skipped       uint32                 `protobuf:"varint,7,opt,name=skipped,proto3" json:"skipped,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *ExecutionSummary) Reset() {
 	*x = ExecutionSummary{}
-	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[44]
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[53]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -3878,7 +4730,7 @@ func (x *ExecutionSummary) String() string {
 func (*ExecutionSummary) ProtoMessage() {}
 
 func (x *ExecutionSummary) ProtoReflect() protoreflect.Message {
-	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[44]
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[53]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3891,7 +4743,7 @@ func (x *ExecutionSummary) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ExecutionSummary.ProtoReflect.Descriptor instead.
 func (*ExecutionSummary) Descriptor() ([]byte, []int) {
-	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{44}
+	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{53}
 }
 
 func (x *ExecutionSummary) GetTotalJobs() uint32 {
@@ -3929,6 +4781,20 @@ func (x *ExecutionSummary) GetWaiting() uint32 {
 	return 0
 }
 
+func (x *ExecutionSummary) GetCancelled() uint32 {
+	if x != nil {
+		return x.Cancelled
+	}
+	return 0
+}
+
+func (x *ExecutionSummary) GetSkipped() uint32 {
+	if x != nil {
+		return x.Skipped
+	}
+	return 0
+}
+
 type ExecutionCardSummary struct {
 	state              protoimpl.MessageState  `protogen:"open.v1"`
 	Key                string                  `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
@@ -3948,7 +4814,7 @@ type ExecutionCardSummary struct {
 
 func (x *ExecutionCardSummary) Reset() {
 	*x = ExecutionCardSummary{}
-	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[45]
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[54]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -3960,7 +4826,7 @@ func (x *ExecutionCardSummary) String() string {
 func (*ExecutionCardSummary) ProtoMessage() {}
 
 func (x *ExecutionCardSummary) ProtoReflect() protoreflect.Message {
-	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[45]
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[54]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3973,7 +4839,7 @@ func (x *ExecutionCardSummary) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ExecutionCardSummary.ProtoReflect.Descriptor instead.
 func (*ExecutionCardSummary) Descriptor() ([]byte, []int) {
-	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{45}
+	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{54}
 }
 
 func (x *ExecutionCardSummary) GetKey() string {
@@ -4065,7 +4931,7 @@ type ExecutionCardSection struct {
 
 func (x *ExecutionCardSection) Reset() {
 	*x = ExecutionCardSection{}
-	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[46]
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[55]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -4077,7 +4943,7 @@ func (x *ExecutionCardSection) String() string {
 func (*ExecutionCardSection) ProtoMessage() {}
 
 func (x *ExecutionCardSection) ProtoReflect() protoreflect.Message {
-	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[46]
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[55]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -4090,7 +4956,7 @@ func (x *ExecutionCardSection) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ExecutionCardSection.ProtoReflect.Descriptor instead.
 func (*ExecutionCardSection) Descriptor() ([]byte, []int) {
-	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{46}
+	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{55}
 }
 
 func (x *ExecutionCardSection) GetKey() string {
@@ -4147,7 +5013,7 @@ type ExecutionCardJob struct {
 
 func (x *ExecutionCardJob) Reset() {
 	*x = ExecutionCardJob{}
-	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[47]
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[56]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -4159,7 +5025,7 @@ func (x *ExecutionCardJob) String() string {
 func (*ExecutionCardJob) ProtoMessage() {}
 
 func (x *ExecutionCardJob) ProtoReflect() protoreflect.Message {
-	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[47]
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[56]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -4172,7 +5038,7 @@ func (x *ExecutionCardJob) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ExecutionCardJob.ProtoReflect.Descriptor instead.
 func (*ExecutionCardJob) Descriptor() ([]byte, []int) {
-	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{47}
+	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{56}
 }
 
 func (x *ExecutionCardJob) GetId() string {
@@ -4313,7 +5179,7 @@ type Progress struct {
 
 func (x *Progress) Reset() {
 	*x = Progress{}
-	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[48]
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[57]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -4325,7 +5191,7 @@ func (x *Progress) String() string {
 func (*Progress) ProtoMessage() {}
 
 func (x *Progress) ProtoReflect() protoreflect.Message {
-	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[48]
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[57]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -4338,7 +5204,7 @@ func (x *Progress) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Progress.ProtoReflect.Descriptor instead.
 func (*Progress) Descriptor() ([]byte, []int) {
-	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{48}
+	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{57}
 }
 
 func (x *Progress) GetState() string {
@@ -4378,13 +5244,14 @@ type RunPipelineSelection struct {
 	SourceRef     string                 `protobuf:"bytes,5,opt,name=source_ref,json=sourceRef,proto3" json:"source_ref,omitempty"`
 	AgentId       string                 `protobuf:"bytes,6,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
 	ExecutionMode string                 `protobuf:"bytes,7,opt,name=execution_mode,json=executionMode,proto3" json:"execution_mode,omitempty"`
+	Priority      *int32                 `protobuf:"varint,8,opt,name=priority,proto3,oneof" json:"priority,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *RunPipelineSelection) Reset() {
 	*x = RunPipelineSelection{}
-	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[49]
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[58]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -4396,7 +5263,7 @@ func (x *RunPipelineSelection) String() string {
 func (*RunPipelineSelection) ProtoMessage() {}
 
 func (x *RunPipelineSelection) ProtoReflect() protoreflect.Message {
-	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[49]
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[58]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -4409,7 +5276,7 @@ func (x *RunPipelineSelection) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RunPipelineSelection.ProtoReflect.Descriptor instead.
 func (*RunPipelineSelection) Descriptor() ([]byte, []int) {
-	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{49}
+	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{58}
 }
 
 func (x *RunPipelineSelection) GetPipelineJobId() string {
@@ -4461,6 +5328,13 @@ func (x *RunPipelineSelection) GetExecutionMode() string {
 	return ""
 }
 
+func (x *RunPipelineSelection) GetPriority() int32 {
+	if x != nil && x.Priority != nil {
+		return *x.Priority
+	}
+	return 0
+}
+
 type RunPipelineRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	PipelineDbId  int64                  `protobuf:"varint,1,opt,name=pipeline_db_id,json=pipelineDbId,proto3" json:"pipeline_db_id,omitempty"`
@@ -4471,7 +5345,7 @@ type RunPipelineRequest struct {
 
 func (x *RunPipelineRequest) Reset() {
 	*x = RunPipelineRequest{}
-	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[50]
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[59]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -4483,7 +5357,7 @@ func (x *RunPipelineRequest) String() string {
 func (*RunPipelineRequest) ProtoMessage() {}
 
 func (x *RunPipelineRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[50]
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[59]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -4496,7 +5370,7 @@ func (x *RunPipelineRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RunPipelineRequest.ProtoReflect.Descriptor instead.
 func (*RunPipelineRequest) Descriptor() ([]byte, []int) {
-	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{50}
+	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{59}
 }
 
 func (x *RunPipelineRequest) GetPipelineDbId() int64 {
@@ -4525,7 +5399,7 @@ type RunPipelineResult struct {
 
 func (x *RunPipelineResult) Reset() {
 	*x = RunPipelineResult{}
-	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[51]
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[60]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -4537,7 +5411,7 @@ func (x *RunPipelineResult) String() string {
 func (*RunPipelineResult) ProtoMessage() {}
 
 func (x *RunPipelineResult) ProtoReflect() protoreflect.Message {
-	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[51]
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[60]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -4550,7 +5424,7 @@ func (x *RunPipelineResult) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RunPipelineResult.ProtoReflect.Descriptor instead.
 func (*RunPipelineResult) Descriptor() ([]byte, []int) {
-	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{51}
+	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{60}
 }
 
 func (x *RunPipelineResult) GetProjectName() string {
@@ -4592,7 +5466,7 @@ type RunPipelineChainRequest struct {
 
 func (x *RunPipelineChainRequest) Reset() {
 	*x = RunPipelineChainRequest{}
-	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[52]
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[61]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -4604,7 +5478,7 @@ func (x *RunPipelineChainRequest) String() string {
 func (*RunPipelineChainRequest) ProtoMessage() {}
 
 func (x *RunPipelineChainRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[52]
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[61]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -4617,7 +5491,7 @@ func (x *RunPipelineChainRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RunPipelineChainRequest.ProtoReflect.Descriptor instead.
 func (*RunPipelineChainRequest) Descriptor() ([]byte, []int) {
-	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{52}
+	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{61}
 }
 
 func (x *RunPipelineChainRequest) GetProjectId() int64 {
@@ -4654,7 +5528,7 @@ type RunPipelineChainResult struct {
 
 func (x *RunPipelineChainResult) Reset() {
 	*x = RunPipelineChainResult{}
-	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[53]
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[62]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -4666,7 +5540,7 @@ func (x *RunPipelineChainResult) String() string {
 func (*RunPipelineChainResult) ProtoMessage() {}
 
 func (x *RunPipelineChainResult) ProtoReflect() protoreflect.Message {
-	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[53]
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[62]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -4679,7 +5553,7 @@ func (x *RunPipelineChainResult) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RunPipelineChainResult.ProtoReflect.Descriptor instead.
 func (*RunPipelineChainResult) Descriptor() ([]byte, []int) {
-	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{53}
+	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{62}
 }
 
 func (x *RunPipelineChainResult) GetProjectName() string {
@@ -4729,7 +5603,7 @@ type GetRunOptionsRequest struct {
 
 func (x *GetRunOptionsRequest) Reset() {
 	*x = GetRunOptionsRequest{}
-	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[54]
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[63]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -4741,7 +5615,7 @@ func (x *GetRunOptionsRequest) String() string {
 func (*GetRunOptionsRequest) ProtoMessage() {}
 
 func (x *GetRunOptionsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[54]
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[63]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -4754,7 +5628,7 @@ func (x *GetRunOptionsRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetRunOptionsRequest.ProtoReflect.Descriptor instead.
 func (*GetRunOptionsRequest) Descriptor() ([]byte, []int) {
-	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{54}
+	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{63}
 }
 
 func (x *GetRunOptionsRequest) GetPipelineDbId() int64 {
@@ -4795,7 +5669,7 @@ type RunOption struct {
 
 func (x *RunOption) Reset() {
 	*x = RunOption{}
-	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[55]
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[64]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -4807,7 +5681,7 @@ func (x *RunOption) String() string {
 func (*RunOption) ProtoMessage() {}
 
 func (x *RunOption) ProtoReflect() protoreflect.Message {
-	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[55]
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[64]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -4820,7 +5694,7 @@ func (x *RunOption) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RunOption.ProtoReflect.Descriptor instead.
 func (*RunOption) Descriptor() ([]byte, []int) {
-	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{55}
+	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{64}
 }
 
 func (x *RunOption) GetValue() string {
@@ -4852,13 +5726,15 @@ type RunOptionsView struct {
 	PendingJobs       uint32                 `protobuf:"varint,11,opt,name=pending_jobs,json=pendingJobs,proto3" json:"pending_jobs,omitempty"`
 	SelectedSourceRef string                 `protobuf:"bytes,12,opt,name=selected_source_ref,json=selectedSourceRef,proto3" json:"selected_source_ref,omitempty"`
 	SelectedAgentId   string                 `protobuf:"bytes,13,opt,name=selected_agent_id,json=selectedAgentId,proto3" json:"selected_agent_id,omitempty"`
+	PriorityOptions   []*RunOption           `protobuf:"bytes,14,rep,name=priority_options,json=priorityOptions,proto3" json:"priority_options,omitempty"`
+	SelectedPriority  string                 `protobuf:"bytes,15,opt,name=selected_priority,json=selectedPriority,proto3" json:"selected_priority,omitempty"`
 	unknownFields     protoimpl.UnknownFields
 	sizeCache         protoimpl.SizeCache
 }
 
 func (x *RunOptionsView) Reset() {
 	*x = RunOptionsView{}
-	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[56]
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[65]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -4870,7 +5746,7 @@ func (x *RunOptionsView) String() string {
 func (*RunOptionsView) ProtoMessage() {}
 
 func (x *RunOptionsView) ProtoReflect() protoreflect.Message {
-	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[56]
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[65]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -4883,7 +5759,7 @@ func (x *RunOptionsView) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RunOptionsView.ProtoReflect.Descriptor instead.
 func (*RunOptionsView) Descriptor() ([]byte, []int) {
-	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{56}
+	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{65}
 }
 
 func (x *RunOptionsView) GetTargetKind() string {
@@ -4977,6 +5853,20 @@ func (x *RunOptionsView) GetSelectedAgentId() string {
 	return ""
 }
 
+func (x *RunOptionsView) GetPriorityOptions() []*RunOption {
+	if x != nil {
+		return x.PriorityOptions
+	}
+	return nil
+}
+
+func (x *RunOptionsView) GetSelectedPriority() string {
+	if x != nil {
+		return x.SelectedPriority
+	}
+	return ""
+}
+
 type AgentSummary struct {
 	state             protoimpl.MessageState `protogen:"open.v1"`
 	Id                string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
@@ -5000,13 +5890,20 @@ type AgentSummary struct {
 	CanRunScript      bool                   `protobuf:"varint,19,opt,name=can_run_script,json=canRunScript,proto3" json:"can_run_script,omitempty"`
 	ScriptShells      []*AgentScriptShell    `protobuf:"bytes,20,rep,name=script_shells,json=scriptShells,proto3" json:"script_shells,omitempty"`
 	LastSeenUnixMs    int64                  `protobuf:"varint,21,opt,name=last_seen_unix_ms,json=lastSeenUnixMs,proto3" json:"last_seen_unix_ms,omitempty"`
+	Slots             uint32                 `protobuf:"varint,22,opt,name=slots,proto3" json:"slots,omitempty"`
+	ActiveJobs        uint32                 `protobuf:"varint,23,opt,name=active_jobs,json=activeJobs,proto3" json:"active_jobs,omitempty"`
+	SlotsLabel        string                 `protobuf:"bytes,24,opt,name=slots_label,json=slotsLabel,proto3" json:"slots_label,omitempty"`
+	SelectedSlots     string                 `protobuf:"bytes,25,opt,name=selected_slots,json=selectedSlots,proto3" json:"selected_slots,omitempty"`
+	SlotOptions       []*AgentSlotOption     `protobuf:"bytes,26,rep,name=slot_options,json=slotOptions,proto3" json:"slot_options,omitempty"`
+	LabelsLabel       string                 `protobuf:"bytes,27,opt,name=labels_label,json=labelsLabel,proto3" json:"labels_label,omitempty"`
+	AssignedLabels    string                 `protobuf:"bytes,28,opt,name=assigned_labels,json=assignedLabels,proto3" json:"assigned_labels,omitempty"`
 	unknownFields     protoimpl.UnknownFields
 	sizeCache         protoimpl.SizeCache
 }
 
 func (x *AgentSummary) Reset() {
 	*x = AgentSummary{}
-	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[57]
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[66]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -5018,7 +5915,7 @@ func (x *AgentSummary) String() string {
 func (*AgentSummary) ProtoMessage() {}
 
 func (x *AgentSummary) ProtoReflect() protoreflect.Message {
-	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[57]
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[66]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -5031,7 +5928,7 @@ func (x *AgentSummary) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use AgentSummary.ProtoReflect.Descriptor instead.
 func (*AgentSummary) Descriptor() ([]byte, []int) {
-	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{57}
+	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{66}
 }
 
 func (x *AgentSummary) GetId() string {
@@ -5181,6 +6078,55 @@ func (x *AgentSummary) GetLastSeenUnixMs() int64 {
 	return 0
 }
 
+func (x *AgentSummary) GetSlots() uint32 {
+	if x != nil {
+		return x.Slots
+	}
+	return 0
+}
+
+func (x *AgentSummary) GetActiveJobs() uint32 {
+	if x != nil {
+		return x.ActiveJobs
+	}
+	return 0
+}
+
+func (x *AgentSummary) GetSlotsLabel() string {
+	if x != nil {
+		return x.SlotsLabel
+	}
+	return ""
+}
+
+func (x *AgentSummary) GetSelectedSlots() string {
+	if x != nil {
+		return x.SelectedSlots
+	}
+	return ""
+}
+
+func (x *AgentSummary) GetSlotOptions() []*AgentSlotOption {
+	if x != nil {
+		return x.SlotOptions
+	}
+	return nil
+}
+
+func (x *AgentSummary) GetLabelsLabel() string {
+	if x != nil {
+		return x.LabelsLabel
+	}
+	return ""
+}
+
+func (x *AgentSummary) GetAssignedLabels() string {
+	if x != nil {
+		return x.AssignedLabels
+	}
+	return ""
+}
+
 type AgentScriptShell struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Value         string                 `protobuf:"bytes,1,opt,name=value,proto3" json:"value,omitempty"`
@@ -5192,7 +6138,7 @@ type AgentScriptShell struct {
 
 func (x *AgentScriptShell) Reset() {
 	*x = AgentScriptShell{}
-	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[58]
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[67]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -5204,7 +6150,7 @@ func (x *AgentScriptShell) String() string {
 func (*AgentScriptShell) ProtoMessage() {}
 
 func (x *AgentScriptShell) ProtoReflect() protoreflect.Message {
-	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[58]
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[67]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -5217,7 +6163,7 @@ func (x *AgentScriptShell) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use AgentScriptShell.ProtoReflect.Descriptor instead.
 func (*AgentScriptShell) Descriptor() ([]byte, []int) {
-	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{58}
+	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{67}
 }
 
 func (x *AgentScriptShell) GetValue() string {
@@ -5241,6 +6187,58 @@ func (x *AgentScriptShell) GetExampleScript() string {
 	return ""
 }
 
+type AgentSlotOption struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Value         string                 `protobuf:"bytes,1,opt,name=value,proto3" json:"value,omitempty"`
+	Label         string                 `protobuf:"bytes,2,opt,name=label,proto3" json:"label,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AgentSlotOption) Reset() {
+	*x = AgentSlotOption{}
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[68]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AgentSlotOption) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AgentSlotOption) ProtoMessage() {}
+
+func (x *AgentSlotOption) ProtoReflect() protoreflect.Message {
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[68]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AgentSlotOption.ProtoReflect.Descriptor instead.
+func (*AgentSlotOption) Descriptor() ([]byte, []int) {
+	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{68}
+}
+
+func (x *AgentSlotOption) GetValue() string {
+	if x != nil {
+		return x.Value
+	}
+	return ""
+}
+
+func (x *AgentSlotOption) GetLabel() string {
+	if x != nil {
+		return x.Label
+	}
+	return ""
+}
+
 type AgentsView struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Summary       string                 `protobuf:"bytes,1,opt,name=summary,proto3" json:"summary,omitempty"`
@@ -5251,7 +6249,7 @@ type AgentsView struct {
 
 func (x *AgentsView) Reset() {
 	*x = AgentsView{}
-	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[59]
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[69]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -5263,7 +6261,7 @@ func (x *AgentsView) String() string {
 func (*AgentsView) ProtoMessage() {}
 
 func (x *AgentsView) ProtoReflect() protoreflect.Message {
-	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[59]
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[69]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -5276,7 +6274,7 @@ func (x *AgentsView) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use AgentsView.ProtoReflect.Descriptor instead.
 func (*AgentsView) Descriptor() ([]byte, []int) {
-	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{59}
+	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{69}
 }
 
 func (x *AgentsView) GetSummary() string {
@@ -5302,7 +6300,7 @@ type GetAgentDetailsRequest struct {
 
 func (x *GetAgentDetailsRequest) Reset() {
 	*x = GetAgentDetailsRequest{}
-	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[60]
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[70]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -5314,7 +6312,7 @@ func (x *GetAgentDetailsRequest) String() string {
 func (*GetAgentDetailsRequest) ProtoMessage() {}
 
 func (x *GetAgentDetailsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[60]
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[70]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -5327,7 +6325,7 @@ func (x *GetAgentDetailsRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetAgentDetailsRequest.ProtoReflect.Descriptor instead.
 func (*GetAgentDetailsRequest) Descriptor() ([]byte, []int) {
-	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{60}
+	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{70}
 }
 
 func (x *GetAgentDetailsRequest) GetAgentId() string {
@@ -5346,7 +6344,7 @@ type AgentDetailsView struct {
 
 func (x *AgentDetailsView) Reset() {
 	*x = AgentDetailsView{}
-	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[61]
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[71]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -5358,7 +6356,7 @@ func (x *AgentDetailsView) String() string {
 func (*AgentDetailsView) ProtoMessage() {}
 
 func (x *AgentDetailsView) ProtoReflect() protoreflect.Message {
-	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[61]
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[71]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -5371,7 +6369,7 @@ func (x *AgentDetailsView) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use AgentDetailsView.ProtoReflect.Descriptor instead.
 func (*AgentDetailsView) Descriptor() ([]byte, []int) {
-	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{61}
+	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{71}
 }
 
 func (x *AgentDetailsView) GetAgent() *AgentSummary {
@@ -5385,13 +6383,15 @@ type AgentActionRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	AgentId       string                 `protobuf:"bytes,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
 	Action        string                 `protobuf:"bytes,2,opt,name=action,proto3" json:"action,omitempty"`
+	Slots         uint32                 `protobuf:"varint,3,opt,name=slots,proto3" json:"slots,omitempty"`
+	Labels        []*AgentLabel          `protobuf:"bytes,4,rep,name=labels,proto3" json:"labels,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *AgentActionRequest) Reset() {
 	*x = AgentActionRequest{}
-	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[62]
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[72]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -5403,7 +6403,7 @@ func (x *AgentActionRequest) String() string {
 func (*AgentActionRequest) ProtoMessage() {}
 
 func (x *AgentActionRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[62]
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[72]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -5416,7 +6416,7 @@ func (x *AgentActionRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use AgentActionRequest.ProtoReflect.Descriptor instead.
 func (*AgentActionRequest) Descriptor() ([]byte, []int) {
-	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{62}
+	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{72}
 }
 
 func (x *AgentActionRequest) GetAgentId() string {
@@ -5433,6 +6433,72 @@ func (x *AgentActionRequest) GetAction() string {
 	return ""
 }
 
+func (x *AgentActionRequest) GetSlots() uint32 {
+	if x != nil {
+		return x.Slots
+	}
+	return 0
+}
+
+func (x *AgentActionRequest) GetLabels() []*AgentLabel {
+	if x != nil {
+		return x.Labels
+	}
+	return nil
+}
+
+type AgentLabel struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Key           string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Value         string                 `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AgentLabel) Reset() {
+	*x = AgentLabel{}
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[73]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AgentLabel) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AgentLabel) ProtoMessage() {}
+
+func (x *AgentLabel) ProtoReflect() protoreflect.Message {
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[73]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AgentLabel.ProtoReflect.Descriptor instead.
+func (*AgentLabel) Descriptor() ([]byte, []int) {
+	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{73}
+}
+
+func (x *AgentLabel) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *AgentLabel) GetValue() string {
+	if x != nil {
+		return x.Value
+	}
+	return ""
+}
+
 type AgentActionResult struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Requested     bool                   `protobuf:"varint,1,opt,name=requested,proto3" json:"requested,omitempty"`
@@ -5445,7 +6511,7 @@ type AgentActionResult struct {
 
 func (x *AgentActionResult) Reset() {
 	*x = AgentActionResult{}
-	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[63]
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[74]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -5457,7 +6523,7 @@ func (x *AgentActionResult) String() string {
 func (*AgentActionResult) ProtoMessage() {}
 
 func (x *AgentActionResult) ProtoReflect() protoreflect.Message {
-	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[63]
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[74]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -5470,7 +6536,7 @@ func (x *AgentActionResult) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use AgentActionResult.ProtoReflect.Descriptor instead.
 func (*AgentActionResult) Descriptor() ([]byte, []int) {
-	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{63}
+	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{74}
 }
 
 func (x *AgentActionResult) GetRequested() bool {
@@ -5513,7 +6579,7 @@ type RunAgentScriptRequest struct {
 
 func (x *RunAgentScriptRequest) Reset() {
 	*x = RunAgentScriptRequest{}
-	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[64]
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[75]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -5525,7 +6591,7 @@ func (x *RunAgentScriptRequest) String() string {
 func (*RunAgentScriptRequest) ProtoMessage() {}
 
 func (x *RunAgentScriptRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[64]
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[75]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -5538,7 +6604,7 @@ func (x *RunAgentScriptRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RunAgentScriptRequest.ProtoReflect.Descriptor instead.
 func (*RunAgentScriptRequest) Descriptor() ([]byte, []int) {
-	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{64}
+	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{75}
 }
 
 func (x *RunAgentScriptRequest) GetAgentId() string {
@@ -5582,7 +6648,7 @@ type RunAgentScriptResult struct {
 
 func (x *RunAgentScriptResult) Reset() {
 	*x = RunAgentScriptResult{}
-	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[65]
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[76]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -5594,7 +6660,7 @@ func (x *RunAgentScriptResult) String() string {
 func (*RunAgentScriptResult) ProtoMessage() {}
 
 func (x *RunAgentScriptResult) ProtoReflect() protoreflect.Message {
-	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[65]
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[76]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -5607,7 +6673,7 @@ func (x *RunAgentScriptResult) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RunAgentScriptResult.ProtoReflect.Descriptor instead.
 func (*RunAgentScriptResult) Descriptor() ([]byte, []int) {
-	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{65}
+	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{76}
 }
 
 func (x *RunAgentScriptResult) GetQueued() bool {
@@ -5655,7 +6721,7 @@ type ProjectActionRequest struct {
 
 func (x *ProjectActionRequest) Reset() {
 	*x = ProjectActionRequest{}
-	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[66]
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[77]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -5667,7 +6733,7 @@ func (x *ProjectActionRequest) String() string {
 func (*ProjectActionRequest) ProtoMessage() {}
 
 func (x *ProjectActionRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[66]
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[77]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -5680,7 +6746,7 @@ func (x *ProjectActionRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ProjectActionRequest.ProtoReflect.Descriptor instead.
 func (*ProjectActionRequest) Descriptor() ([]byte, []int) {
-	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{66}
+	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{77}
 }
 
 func (x *ProjectActionRequest) GetProjectId() int64 {
@@ -5707,7 +6773,7 @@ type ProjectActionResult struct {
 
 func (x *ProjectActionResult) Reset() {
 	*x = ProjectActionResult{}
-	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[67]
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[78]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -5719,7 +6785,7 @@ func (x *ProjectActionResult) String() string {
 func (*ProjectActionResult) ProtoMessage() {}
 
 func (x *ProjectActionResult) ProtoReflect() protoreflect.Message {
-	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[67]
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[78]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -5732,7 +6798,7 @@ func (x *ProjectActionResult) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ProjectActionResult.ProtoReflect.Descriptor instead.
 func (*ProjectActionResult) Descriptor() ([]byte, []int) {
-	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{67}
+	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{78}
 }
 
 func (x *ProjectActionResult) GetProjectId() int64 {
@@ -5760,7 +6826,7 @@ type ImportProjectRequest struct {
 
 func (x *ImportProjectRequest) Reset() {
 	*x = ImportProjectRequest{}
-	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[68]
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[79]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -5772,7 +6838,7 @@ func (x *ImportProjectRequest) String() string {
 func (*ImportProjectRequest) ProtoMessage() {}
 
 func (x *ImportProjectRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[68]
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[79]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -5785,7 +6851,7 @@ func (x *ImportProjectRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ImportProjectRequest.ProtoReflect.Descriptor instead.
 func (*ImportProjectRequest) Descriptor() ([]byte, []int) {
-	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{68}
+	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{79}
 }
 
 func (x *ImportProjectRequest) GetRepoUrl() string {
@@ -5822,7 +6888,7 @@ type ImportProjectResult struct {
 
 func (x *ImportProjectResult) Reset() {
 	*x = ImportProjectResult{}
-	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[69]
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[80]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -5834,7 +6900,7 @@ func (x *ImportProjectResult) String() string {
 func (*ImportProjectResult) ProtoMessage() {}
 
 func (x *ImportProjectResult) ProtoReflect() protoreflect.Message {
-	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[69]
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[80]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -5847,7 +6913,7 @@ func (x *ImportProjectResult) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ImportProjectResult.ProtoReflect.Descriptor instead.
 func (*ImportProjectResult) Descriptor() ([]byte, []int) {
-	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{69}
+	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{80}
 }
 
 func (x *ImportProjectResult) GetProjectName() string {
@@ -5894,7 +6960,7 @@ type GetManagedYAMLRequest struct {
 
 func (x *GetManagedYAMLRequest) Reset() {
 	*x = GetManagedYAMLRequest{}
-	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[70]
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[81]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -5906,7 +6972,7 @@ func (x *GetManagedYAMLRequest) String() string {
 func (*GetManagedYAMLRequest) ProtoMessage() {}
 
 func (x *GetManagedYAMLRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[70]
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[81]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -5919,7 +6985,7 @@ func (x *GetManagedYAMLRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetManagedYAMLRequest.ProtoReflect.Descriptor instead.
 func (*GetManagedYAMLRequest) Descriptor() ([]byte, []int) {
-	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{70}
+	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{81}
 }
 
 func (x *GetManagedYAMLRequest) GetProjectId() int64 {
@@ -5940,7 +7006,7 @@ type ManagedYAMLRequest struct {
 
 func (x *ManagedYAMLRequest) Reset() {
 	*x = ManagedYAMLRequest{}
-	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[71]
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[82]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -5952,7 +7018,7 @@ func (x *ManagedYAMLRequest) String() string {
 func (*ManagedYAMLRequest) ProtoMessage() {}
 
 func (x *ManagedYAMLRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[71]
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[82]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -5965,7 +7031,7 @@ func (x *ManagedYAMLRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ManagedYAMLRequest.ProtoReflect.Descriptor instead.
 func (*ManagedYAMLRequest) Descriptor() ([]byte, []int) {
-	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{71}
+	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{82}
 }
 
 func (x *ManagedYAMLRequest) GetProjectId() int64 {
@@ -6003,7 +7069,7 @@ type ManagedYAMLDefinition struct {
 
 func (x *ManagedYAMLDefinition) Reset() {
 	*x = ManagedYAMLDefinition{}
-	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[72]
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[83]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -6015,7 +7081,7 @@ func (x *ManagedYAMLDefinition) String() string {
 func (*ManagedYAMLDefinition) ProtoMessage() {}
 
 func (x *ManagedYAMLDefinition) ProtoReflect() protoreflect.Message {
-	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[72]
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[83]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -6028,7 +7094,7 @@ func (x *ManagedYAMLDefinition) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ManagedYAMLDefinition.ProtoReflect.Descriptor instead.
 func (*ManagedYAMLDefinition) Descriptor() ([]byte, []int) {
-	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{72}
+	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{83}
 }
 
 func (x *ManagedYAMLDefinition) GetProjectId() int64 {
@@ -6091,7 +7157,7 @@ type VaultConnection struct {
 
 func (x *VaultConnection) Reset() {
 	*x = VaultConnection{}
-	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[73]
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[84]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -6103,7 +7169,7 @@ func (x *VaultConnection) String() string {
 func (*VaultConnection) ProtoMessage() {}
 
 func (x *VaultConnection) ProtoReflect() protoreflect.Message {
-	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[73]
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[84]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -6116,7 +7182,7 @@ func (x *VaultConnection) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use VaultConnection.ProtoReflect.Descriptor instead.
 func (*VaultConnection) Descriptor() ([]byte, []int) {
-	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{73}
+	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{84}
 }
 
 func (x *VaultConnection) GetId() int64 {
@@ -6198,7 +7264,7 @@ type VaultConnectionList struct {
 
 func (x *VaultConnectionList) Reset() {
 	*x = VaultConnectionList{}
-	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[74]
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[85]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -6210,7 +7276,7 @@ func (x *VaultConnectionList) String() string {
 func (*VaultConnectionList) ProtoMessage() {}
 
 func (x *VaultConnectionList) ProtoReflect() protoreflect.Message {
-	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[74]
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[85]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -6223,7 +7289,7 @@ func (x *VaultConnectionList) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use VaultConnectionList.ProtoReflect.Descriptor instead.
 func (*VaultConnectionList) Descriptor() ([]byte, []int) {
-	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{74}
+	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{85}
 }
 
 func (x *VaultConnectionList) GetConnections() []*VaultConnection {
@@ -6250,7 +7316,7 @@ type UpsertVaultConnectionRequest struct {
 
 func (x *UpsertVaultConnectionRequest) Reset() {
 	*x = UpsertVaultConnectionRequest{}
-	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[75]
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[86]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -6262,7 +7328,7 @@ func (x *UpsertVaultConnectionRequest) String() string {
 func (*UpsertVaultConnectionRequest) ProtoMessage() {}
 
 func (x *UpsertVaultConnectionRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[75]
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[86]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -6275,7 +7341,7 @@ func (x *UpsertVaultConnectionRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UpsertVaultConnectionRequest.ProtoReflect.Descriptor instead.
 func (*UpsertVaultConnectionRequest) Descriptor() ([]byte, []int) {
-	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{75}
+	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{86}
 }
 
 func (x *UpsertVaultConnectionRequest) GetName() string {
@@ -6350,7 +7416,7 @@ type VaultConnectionIDRequest struct {
 
 func (x *VaultConnectionIDRequest) Reset() {
 	*x = VaultConnectionIDRequest{}
-	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[76]
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[87]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -6362,7 +7428,7 @@ func (x *VaultConnectionIDRequest) String() string {
 func (*VaultConnectionIDRequest) ProtoMessage() {}
 
 func (x *VaultConnectionIDRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[76]
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[87]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -6375,7 +7441,7 @@ func (x *VaultConnectionIDRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use VaultConnectionIDRequest.ProtoReflect.Descriptor instead.
 func (*VaultConnectionIDRequest) Descriptor() ([]byte, []int) {
-	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{76}
+	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{87}
 }
 
 func (x *VaultConnectionIDRequest) GetId() int64 {
@@ -6395,7 +7461,7 @@ type TestVaultConnectionRequest struct {
 
 func (x *TestVaultConnectionRequest) Reset() {
 	*x = TestVaultConnectionRequest{}
-	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[77]
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[88]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -6407,7 +7473,7 @@ func (x *TestVaultConnectionRequest) String() string {
 func (*TestVaultConnectionRequest) ProtoMessage() {}
 
 func (x *TestVaultConnectionRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[77]
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[88]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -6420,7 +7486,7 @@ func (x *TestVaultConnectionRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use TestVaultConnectionRequest.ProtoReflect.Descriptor instead.
 func (*TestVaultConnectionRequest) Descriptor() ([]byte, []int) {
-	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{77}
+	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{88}
 }
 
 func (x *TestVaultConnectionRequest) GetId() int64 {
@@ -6447,7 +7513,7 @@ type TestVaultConnectionResult struct {
 
 func (x *TestVaultConnectionResult) Reset() {
 	*x = TestVaultConnectionResult{}
-	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[78]
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[89]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -6459,7 +7525,7 @@ func (x *TestVaultConnectionResult) String() string {
 func (*TestVaultConnectionResult) ProtoMessage() {}
 
 func (x *TestVaultConnectionResult) ProtoReflect() protoreflect.Message {
-	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[78]
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[89]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -6472,7 +7538,7 @@ func (x *TestVaultConnectionResult) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use TestVaultConnectionResult.ProtoReflect.Descriptor instead.
 func (*TestVaultConnectionResult) Descriptor() ([]byte, []int) {
-	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{78}
+	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{89}
 }
 
 func (x *TestVaultConnectionResult) GetOk() bool {
@@ -6499,7 +7565,7 @@ type DeleteVaultConnectionResult struct {
 
 func (x *DeleteVaultConnectionResult) Reset() {
 	*x = DeleteVaultConnectionResult{}
-	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[79]
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[90]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -6511,7 +7577,7 @@ func (x *DeleteVaultConnectionResult) String() string {
 func (*DeleteVaultConnectionResult) ProtoMessage() {}
 
 func (x *DeleteVaultConnectionResult) ProtoReflect() protoreflect.Message {
-	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[79]
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[90]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -6524,7 +7590,7 @@ func (x *DeleteVaultConnectionResult) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DeleteVaultConnectionResult.ProtoReflect.Descriptor instead.
 func (*DeleteVaultConnectionResult) Descriptor() ([]byte, []int) {
-	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{79}
+	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{90}
 }
 
 func (x *DeleteVaultConnectionResult) GetDeleted() bool {
@@ -6561,7 +7627,7 @@ type ServerUpdateStatus struct {
 
 func (x *ServerUpdateStatus) Reset() {
 	*x = ServerUpdateStatus{}
-	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[80]
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[91]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -6573,7 +7639,7 @@ func (x *ServerUpdateStatus) String() string {
 func (*ServerUpdateStatus) ProtoMessage() {}
 
 func (x *ServerUpdateStatus) ProtoReflect() protoreflect.Message {
-	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[80]
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[91]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -6586,7 +7652,7 @@ func (x *ServerUpdateStatus) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ServerUpdateStatus.ProtoReflect.Descriptor instead.
 func (*ServerUpdateStatus) Descriptor() ([]byte, []int) {
-	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{80}
+	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{91}
 }
 
 func (x *ServerUpdateStatus) GetCurrentVersion() string {
@@ -6688,7 +7754,7 @@ type ServerUpdateCheckResult struct {
 
 func (x *ServerUpdateCheckResult) Reset() {
 	*x = ServerUpdateCheckResult{}
-	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[81]
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[92]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -6700,7 +7766,7 @@ func (x *ServerUpdateCheckResult) String() string {
 func (*ServerUpdateCheckResult) ProtoMessage() {}
 
 func (x *ServerUpdateCheckResult) ProtoReflect() protoreflect.Message {
-	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[81]
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[92]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -6713,7 +7779,7 @@ func (x *ServerUpdateCheckResult) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ServerUpdateCheckResult.ProtoReflect.Descriptor instead.
 func (*ServerUpdateCheckResult) Descriptor() ([]byte, []int) {
-	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{81}
+	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{92}
 }
 
 func (x *ServerUpdateCheckResult) GetCurrentVersion() string {
@@ -6775,7 +7841,7 @@ type ServerUpdateVersions struct {
 
 func (x *ServerUpdateVersions) Reset() {
 	*x = ServerUpdateVersions{}
-	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[82]
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[93]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -6787,7 +7853,7 @@ func (x *ServerUpdateVersions) String() string {
 func (*ServerUpdateVersions) ProtoMessage() {}
 
 func (x *ServerUpdateVersions) ProtoReflect() protoreflect.Message {
-	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[82]
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[93]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -6800,7 +7866,7 @@ func (x *ServerUpdateVersions) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ServerUpdateVersions.ProtoReflect.Descriptor instead.
 func (*ServerUpdateVersions) Descriptor() ([]byte, []int) {
-	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{82}
+	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{93}
 }
 
 func (x *ServerUpdateVersions) GetVersions() []string {
@@ -6827,7 +7893,7 @@ type ServerUpdateActionRequest struct {
 
 func (x *ServerUpdateActionRequest) Reset() {
 	*x = ServerUpdateActionRequest{}
-	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[83]
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[94]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -6839,7 +7905,7 @@ func (x *ServerUpdateActionRequest) String() string {
 func (*ServerUpdateActionRequest) ProtoMessage() {}
 
 func (x *ServerUpdateActionRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[83]
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[94]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -6852,7 +7918,7 @@ func (x *ServerUpdateActionRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ServerUpdateActionRequest.ProtoReflect.Descriptor instead.
 func (*ServerUpdateActionRequest) Descriptor() ([]byte, []int) {
-	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{83}
+	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{94}
 }
 
 func (x *ServerUpdateActionRequest) GetAction() string {
@@ -6883,7 +7949,7 @@ type ServerUpdateActionResult struct {
 
 func (x *ServerUpdateActionResult) Reset() {
 	*x = ServerUpdateActionResult{}
-	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[84]
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[95]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -6895,7 +7961,7 @@ func (x *ServerUpdateActionResult) String() string {
 func (*ServerUpdateActionResult) ProtoMessage() {}
 
 func (x *ServerUpdateActionResult) ProtoReflect() protoreflect.Message {
-	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[84]
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[95]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -6908,7 +7974,7 @@ func (x *ServerUpdateActionResult) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ServerUpdateActionResult.ProtoReflect.Descriptor instead.
 func (*ServerUpdateActionResult) Descriptor() ([]byte, []int) {
-	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{84}
+	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{95}
 }
 
 func (x *ServerUpdateActionResult) GetUpdated() bool {
@@ -6961,7 +8027,7 @@ type ClearExecutionQueueRequest struct {
 
 func (x *ClearExecutionQueueRequest) Reset() {
 	*x = ClearExecutionQueueRequest{}
-	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[85]
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[96]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -6973,7 +8039,7 @@ func (x *ClearExecutionQueueRequest) String() string {
 func (*ClearExecutionQueueRequest) ProtoMessage() {}
 
 func (x *ClearExecutionQueueRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[85]
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[96]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -6986,7 +8052,7 @@ func (x *ClearExecutionQueueRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ClearExecutionQueueRequest.ProtoReflect.Descriptor instead.
 func (*ClearExecutionQueueRequest) Descriptor() ([]byte, []int) {
-	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{85}
+	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{96}
 }
 
 type ClearExecutionQueueResult struct {
@@ -6998,7 +8064,7 @@ type ClearExecutionQueueResult struct {
 
 func (x *ClearExecutionQueueResult) Reset() {
 	*x = ClearExecutionQueueResult{}
-	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[86]
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[97]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -7010,7 +8076,7 @@ func (x *ClearExecutionQueueResult) String() string {
 func (*ClearExecutionQueueResult) ProtoMessage() {}
 
 func (x *ClearExecutionQueueResult) ProtoReflect() protoreflect.Message {
-	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[86]
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[97]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -7023,7 +8089,7 @@ func (x *ClearExecutionQueueResult) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ClearExecutionQueueResult.ProtoReflect.Descriptor instead.
 func (*ClearExecutionQueueResult) Descriptor() ([]byte, []int) {
-	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{86}
+	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{97}
 }
 
 func (x *ClearExecutionQueueResult) GetCleared() int64 {
@@ -7043,7 +8109,7 @@ type FlushExecutionHistoryRequest struct {
 
 func (x *FlushExecutionHistoryRequest) Reset() {
 	*x = FlushExecutionHistoryRequest{}
-	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[87]
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[98]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -7055,7 +8121,7 @@ func (x *FlushExecutionHistoryRequest) String() string {
 func (*FlushExecutionHistoryRequest) ProtoMessage() {}
 
 func (x *FlushExecutionHistoryRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[87]
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[98]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -7068,7 +8134,7 @@ func (x *FlushExecutionHistoryRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use FlushExecutionHistoryRequest.ProtoReflect.Descriptor instead.
 func (*FlushExecutionHistoryRequest) Descriptor() ([]byte, []int) {
-	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{87}
+	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{98}
 }
 
 func (x *FlushExecutionHistoryRequest) GetAll() bool {
@@ -7094,7 +8160,7 @@ type FlushExecutionHistoryResult struct {
 
 func (x *FlushExecutionHistoryResult) Reset() {
 	*x = FlushExecutionHistoryResult{}
-	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[88]
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[99]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -7106,7 +8172,7 @@ func (x *FlushExecutionHistoryResult) String() string {
 func (*FlushExecutionHistoryResult) ProtoMessage() {}
 
 func (x *FlushExecutionHistoryResult) ProtoReflect() protoreflect.Message {
-	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[88]
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[99]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -7119,7 +8185,7 @@ func (x *FlushExecutionHistoryResult) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use FlushExecutionHistoryResult.ProtoReflect.Descriptor instead.
 func (*FlushExecutionHistoryResult) Descriptor() ([]byte, []int) {
-	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{88}
+	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{99}
 }
 
 func (x *FlushExecutionHistoryResult) GetFlushed() int64 {
@@ -7139,7 +8205,7 @@ type RemoveQueuedExecutionResult struct {
 
 func (x *RemoveQueuedExecutionResult) Reset() {
 	*x = RemoveQueuedExecutionResult{}
-	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[89]
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[100]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -7151,7 +8217,7 @@ func (x *RemoveQueuedExecutionResult) String() string {
 func (*RemoveQueuedExecutionResult) ProtoMessage() {}
 
 func (x *RemoveQueuedExecutionResult) ProtoReflect() protoreflect.Message {
-	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[89]
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[100]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -7164,7 +8230,7 @@ func (x *RemoveQueuedExecutionResult) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RemoveQueuedExecutionResult.ProtoReflect.Descriptor instead.
 func (*RemoveQueuedExecutionResult) Descriptor() ([]byte, []int) {
-	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{89}
+	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{100}
 }
 
 func (x *RemoveQueuedExecutionResult) GetJobExecutionId() string {
@@ -7190,7 +8256,7 @@ type CommandReceiptStatusRequest struct {
 
 func (x *CommandReceiptStatusRequest) Reset() {
 	*x = CommandReceiptStatusRequest{}
-	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[90]
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[101]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -7202,7 +8268,7 @@ func (x *CommandReceiptStatusRequest) String() string {
 func (*CommandReceiptStatusRequest) ProtoMessage() {}
 
 func (x *CommandReceiptStatusRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[90]
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[101]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -7215,7 +8281,7 @@ func (x *CommandReceiptStatusRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CommandReceiptStatusRequest.ProtoReflect.Descriptor instead.
 func (*CommandReceiptStatusRequest) Descriptor() ([]byte, []int) {
-	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{90}
+	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{101}
 }
 
 func (x *CommandReceiptStatusRequest) GetKey() string {
@@ -7239,7 +8305,7 @@ type CommandReceiptStatus struct {
 
 func (x *CommandReceiptStatus) Reset() {
 	*x = CommandReceiptStatus{}
-	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[91]
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[102]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -7251,7 +8317,7 @@ func (x *CommandReceiptStatus) String() string {
 func (*CommandReceiptStatus) ProtoMessage() {}
 
 func (x *CommandReceiptStatus) ProtoReflect() protoreflect.Message {
-	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[91]
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[102]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -7264,7 +8330,7 @@ func (x *CommandReceiptStatus) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CommandReceiptStatus.ProtoReflect.Descriptor instead.
 func (*CommandReceiptStatus) Descriptor() ([]byte, []int) {
-	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{91}
+	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{102}
 }
 
 func (x *CommandReceiptStatus) GetFound() bool {
@@ -7317,7 +8383,7 @@ type WatchChangesRequest struct {
 
 func (x *WatchChangesRequest) Reset() {
 	*x = WatchChangesRequest{}
-	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[92]
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[103]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -7329,7 +8395,7 @@ func (x *WatchChangesRequest) String() string {
 func (*WatchChangesRequest) ProtoMessage() {}
 
 func (x *WatchChangesRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[92]
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[103]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -7342,7 +8408,7 @@ func (x *WatchChangesRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use WatchChangesRequest.ProtoReflect.Descriptor instead.
 func (*WatchChangesRequest) Descriptor() ([]byte, []int) {
-	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{92}
+	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{103}
 }
 
 type ChangeEvent struct {
@@ -7359,7 +8425,7 @@ type ChangeEvent struct {
 
 func (x *ChangeEvent) Reset() {
 	*x = ChangeEvent{}
-	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[93]
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[104]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -7371,7 +8437,7 @@ func (x *ChangeEvent) String() string {
 func (*ChangeEvent) ProtoMessage() {}
 
 func (x *ChangeEvent) ProtoReflect() protoreflect.Message {
-	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[93]
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[104]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -7384,7 +8450,7 @@ func (x *ChangeEvent) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ChangeEvent.ProtoReflect.Descriptor instead.
 func (*ChangeEvent) Descriptor() ([]byte, []int) {
-	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{93}
+	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{104}
 }
 
 func (x *ChangeEvent) GetServerInstanceId() string {
@@ -7473,6 +8539,9 @@ type Request struct {
 	//	*Request_GetJobLogPage
 	//	*Request_SearchJobLog
 	//	*Request_WatchJobLog
+	//	*Request_GetTestHistory
+	//	*Request_AddTestQuarantine
+	//	*Request_RemoveTestQuarantine
 	Operation     isRequest_Operation `protobuf_oneof:"operation"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
@@ -7480,7 +8549,7 @@ type Request struct {
 
 func (x *Request) Reset() {
 	*x = Request{}
-	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[94]
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[105]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -7492,7 +8561,7 @@ func (x *Request) String() string {
 func (*Request) ProtoMessage() {}
 
 func (x *Request) ProtoReflect() protoreflect.Message {
-	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[94]
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[105]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -7505,7 +8574,7 @@ func (x *Request) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Request.ProtoReflect.Descriptor instead.
 func (*Request) Descriptor() ([]byte, []int) {
-	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{94}
+	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{105}
 }
 
 func (x *Request) GetMetadata() *RequestMetadata {
@@ -7873,6 +8942,33 @@ func (x *Request) GetWatchJobLog() *WatchJobLogRequest {
 	return nil
 }
 
+func (x *Request) GetGetTestHistory() *GetTestHistoryRequest {
+	if x != nil {
+		if x, ok := x.Operation.(*Request_GetTestHistory); ok {
+			return x.GetTestHistory
+		}
+	}
+	return nil
+}
+
+func (x *Request) GetAddTestQuarantine() *AddTestQuarantineRequest {
+	if x != nil {
+		if x, ok := x.Operation.(*Request_AddTestQuarantine); ok {
+			return x.AddTestQuarantine
+		}
+	}
+	return nil
+}
+
+func (x *Request) GetRemoveTestQuarantine() *RemoveTestQuarantineRequest {
+	if x != nil {
+		if x, ok := x.Operation.(*Request_RemoveTestQuarantine); ok {
+			return x.RemoveTestQuarantine
+		}
+	}
+	return nil
+}
+
 type isRequest_Operation interface {
 	isRequest_Operation()
 }
@@ -8033,6 +9129,18 @@ type Request_WatchJobLog struct {
 	WatchJobLog *WatchJobLogRequest `protobuf:"bytes,48,opt,name=watch_job_log,json=watchJobLog,proto3,oneof"`
 }
 
+type Request_GetTestHistory struct {
+	GetTestHistory *GetTestHistoryRequest `protobuf:"bytes,49,opt,name=get_test_history,json=getTestHistory,proto3,oneof"`
+}
+
+type Request_AddTestQuarantine struct {
+	AddTestQuarantine *AddTestQuarantineRequest `protobuf:"bytes,50,opt,name=add_test_quarantine,json=addTestQuarantine,proto3,oneof"`
+}
+
+type Request_RemoveTestQuarantine struct {
+	RemoveTestQuarantine *RemoveTestQuarantineRequest `protobuf:"bytes,51,opt,name=remove_test_quarantine,json=removeTestQuarantine,proto3,oneof"`
+}
+
 func (*Request_GetServerInfo) isRequest_Operation() {}
 
 func (*Request_ListProjects) isRequest_Operation() {}
@@ -8111,6 +9219,12 @@ func (*Request_SearchJobLog) isRequest_Operation() {}
 
 func (*Request_WatchJobLog) isRequest_Operation() {}
 
+func (*Request_GetTestHistory) isRequest_Operation() {}
+
+func (*Request_AddTestQuarantine) isRequest_Operation() {}
+
+func (*Request_RemoveTestQuarantine) isRequest_Operation() {}
+
 type Response struct {
 	state     protoimpl.MessageState `protogen:"open.v1"`
 	RequestId string                 `protobuf:"bytes,1,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
@@ -8153,6 +9267,9 @@ type Response struct {
 	//	*Response_JobLogDescriptor
 	//	*Response_JobLogPage
 	//	*Response_JobLogSearch
+	//	*Response_TestHistory
+	//	*Response_TestQuarantineEntry
+	//	*Response_RemoveTestQuarantine
 	Result        isResponse_Result `protobuf_oneof:"result"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
@@ -8160,7 +9277,7 @@ type Response struct {
 
 func (x *Response) Reset() {
 	*x = Response{}
-	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[95]
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[106]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -8172,7 +9289,7 @@ func (x *Response) String() string {
 func (*Response) ProtoMessage() {}
 
 func (x *Response) ProtoReflect() protoreflect.Message {
-	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[95]
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[106]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -8185,7 +9302,7 @@ func (x *Response) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Response.ProtoReflect.Descriptor instead.
 func (*Response) Descriptor() ([]byte, []int) {
-	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{95}
+	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{106}
 }
 
 func (x *Response) GetRequestId() string {
@@ -8535,6 +9652,33 @@ func (x *Response) GetJobLogSearch() *JobLogSearchResult {
 	return nil
 }
 
+func (x *Response) GetTestHistory() *TestHistoryView {
+	if x != nil {
+		if x, ok := x.Result.(*Response_TestHistory); ok {
+			return x.TestHistory
+		}
+	}
+	return nil
+}
+
+func (x *Response) GetTestQuarantineEntry() *TestQuarantineEntry {
+	if x != nil {
+		if x, ok := x.Result.(*Response_TestQuarantineEntry); ok {
+			return x.TestQuarantineEntry
+		}
+	}
+	return nil
+}
+
+func (x *Response) GetRemoveTestQuarantine() *RemoveTestQuarantineResult {
+	if x != nil {
+		if x, ok := x.Result.(*Response_RemoveTestQuarantine); ok {
+			return x.RemoveTestQuarantine
+		}
+	}
+	return nil
+}
+
 type isResponse_Result interface {
 	isResponse_Result()
 }
@@ -8687,6 +9831,18 @@ type Response_JobLogSearch struct {
 	JobLogSearch *JobLogSearchResult `protobuf:"bytes,46,opt,name=job_log_search,json=jobLogSearch,proto3,oneof"`
 }
 
+type Response_TestHistory struct {
+	TestHistory *TestHistoryView `protobuf:"bytes,47,opt,name=test_history,json=testHistory,proto3,oneof"`
+}
+
+type Response_TestQuarantineEntry struct {
+	TestQuarantineEntry *TestQuarantineEntry `protobuf:"bytes,48,opt,name=test_quarantine_entry,json=testQuarantineEntry,proto3,oneof"`
+}
+
+type Response_RemoveTestQuarantine struct {
+	RemoveTestQuarantine *RemoveTestQuarantineResult `protobuf:"bytes,49,opt,name=remove_test_quarantine,json=removeTestQuarantine,proto3,oneof"`
+}
+
 func (*Response_ServerInfo) isResponse_Result() {}
 
 func (*Response_ProjectList) isResponse_Result() {}
@@ -8761,6 +9917,12 @@ func (*Response_JobLogPage) isResponse_Result() {}
 
 func (*Response_JobLogSearch) isResponse_Result() {}
 
+func (*Response_TestHistory) isResponse_Result() {}
+
+func (*Response_TestQuarantineEntry) isResponse_Result() {}
+
+func (*Response_RemoveTestQuarantine) isResponse_Result() {}
+
 type ClientMessage struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
 	// Types that are valid to be assigned to Body:
@@ -8774,7 +9936,7 @@ type ClientMessage struct {
 
 func (x *ClientMessage) Reset() {
 	*x = ClientMessage{}
-	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[96]
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[107]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -8786,7 +9948,7 @@ func (x *ClientMessage) String() string {
 func (*ClientMessage) ProtoMessage() {}
 
 func (x *ClientMessage) ProtoReflect() protoreflect.Message {
-	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[96]
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[107]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -8799,7 +9961,7 @@ func (x *ClientMessage) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ClientMessage.ProtoReflect.Descriptor instead.
 func (*ClientMessage) Descriptor() ([]byte, []int) {
-	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{96}
+	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{107}
 }
 
 func (x *ClientMessage) GetBody() isClientMessage_Body {
@@ -8856,7 +10018,7 @@ type ServerMessage struct {
 
 func (x *ServerMessage) Reset() {
 	*x = ServerMessage{}
-	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[97]
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[108]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -8868,7 +10030,7 @@ func (x *ServerMessage) String() string {
 func (*ServerMessage) ProtoMessage() {}
 
 func (x *ServerMessage) ProtoReflect() protoreflect.Message {
-	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[97]
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[108]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -8881,7 +10043,7 @@ func (x *ServerMessage) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ServerMessage.ProtoReflect.Descriptor instead.
 func (*ServerMessage) Descriptor() ([]byte, []int) {
-	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{97}
+	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{108}
 }
 
 func (x *ServerMessage) GetBody() isServerMessage_Body {
@@ -8936,7 +10098,7 @@ type JobDetailRow struct {
 
 func (x *JobDetailRow) Reset() {
 	*x = JobDetailRow{}
-	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[98]
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[109]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -8948,7 +10110,7 @@ func (x *JobDetailRow) String() string {
 func (*JobDetailRow) ProtoMessage() {}
 
 func (x *JobDetailRow) ProtoReflect() protoreflect.Message {
-	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[98]
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[109]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -8961,7 +10123,7 @@ func (x *JobDetailRow) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use JobDetailRow.ProtoReflect.Descriptor instead.
 func (*JobDetailRow) Descriptor() ([]byte, []int) {
-	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{98}
+	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{109}
 }
 
 func (x *JobDetailRow) GetLabel() string {
@@ -8997,7 +10159,7 @@ type ToolRequirements struct {
 
 func (x *ToolRequirements) Reset() {
 	*x = ToolRequirements{}
-	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[99]
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[110]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -9009,7 +10171,7 @@ func (x *ToolRequirements) String() string {
 func (*ToolRequirements) ProtoMessage() {}
 
 func (x *ToolRequirements) ProtoReflect() protoreflect.Message {
-	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[99]
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[110]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -9022,7 +10184,7 @@ func (x *ToolRequirements) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ToolRequirements.ProtoReflect.Descriptor instead.
 func (*ToolRequirements) Descriptor() ([]byte, []int) {
-	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{99}
+	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{110}
 }
 
 func (x *ToolRequirements) GetEmptyLabel() string {
@@ -9070,7 +10232,7 @@ type ReportDetails struct {
 
 func (x *ReportDetails) Reset() {
 	*x = ReportDetails{}
-	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[100]
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[111]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -9082,7 +10244,7 @@ func (x *ReportDetails) String() string {
 func (*ReportDetails) ProtoMessage() {}
 
 func (x *ReportDetails) ProtoReflect() protoreflect.Message {
-	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[100]
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[111]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -9095,7 +10257,7 @@ func (x *ReportDetails) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ReportDetails.ProtoReflect.Descriptor instead.
 func (*ReportDetails) Descriptor() ([]byte, []int) {
-	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{100}
+	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{111}
 }
 
 func (x *ReportDetails) GetEmptyLabel() string {
@@ -9171,7 +10333,7 @@ type ReportFilter struct {
 
 func (x *ReportFilter) Reset() {
 	*x = ReportFilter{}
-	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[101]
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[112]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -9183,7 +10345,7 @@ func (x *ReportFilter) String() string {
 func (*ReportFilter) ProtoMessage() {}
 
 func (x *ReportFilter) ProtoReflect() protoreflect.Message {
-	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[101]
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[112]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -9196,7 +10358,7 @@ func (x *ReportFilter) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ReportFilter.ProtoReflect.Descriptor instead.
 func (*ReportFilter) Descriptor() ([]byte, []int) {
-	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{101}
+	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{112}
 }
 
 func (x *ReportFilter) GetValue() string {
@@ -9226,13 +10388,14 @@ type TreeNode struct {
 	DefaultExpanded bool                   `protobuf:"varint,10,opt,name=default_expanded,json=defaultExpanded,proto3" json:"default_expanded,omitempty"`
 	FilterValues    []string               `protobuf:"bytes,11,rep,name=filter_values,json=filterValues,proto3" json:"filter_values,omitempty"`
 	Children        []*TreeNode            `protobuf:"bytes,12,rep,name=children,proto3" json:"children,omitempty"`
+	Badge           string                 `protobuf:"bytes,13,opt,name=badge,proto3" json:"badge,omitempty"`
 	unknownFields   protoimpl.UnknownFields
 	sizeCache       protoimpl.SizeCache
 }
 
 func (x *TreeNode) Reset() {
 	*x = TreeNode{}
-	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[102]
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[113]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -9244,7 +10407,7 @@ func (x *TreeNode) String() string {
 func (*TreeNode) ProtoMessage() {}
 
 func (x *TreeNode) ProtoReflect() protoreflect.Message {
-	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[102]
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[113]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -9257,7 +10420,7 @@ func (x *TreeNode) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use TreeNode.ProtoReflect.Descriptor instead.
 func (*TreeNode) Descriptor() ([]byte, []int) {
-	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{102}
+	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{113}
 }
 
 func (x *TreeNode) GetKey() string {
@@ -9337,6 +10500,13 @@ func (x *TreeNode) GetChildren() []*TreeNode {
 	return nil
 }
 
+func (x *TreeNode) GetBadge() string {
+	if x != nil {
+		return x.Badge
+	}
+	return ""
+}
+
 type ArtifactDownloadRequest struct {
 	state             protoimpl.MessageState `protogen:"open.v1"`
 	JobExecutionId    string                 `protobuf:"bytes,1,opt,name=job_execution_id,json=jobExecutionId,proto3" json:"job_execution_id,omitempty"`
@@ -9352,7 +10522,7 @@ type ArtifactDownloadRequest struct {
 
 func (x *ArtifactDownloadRequest) Reset() {
 	*x = ArtifactDownloadRequest{}
-	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[103]
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[114]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -9364,7 +10534,7 @@ func (x *ArtifactDownloadRequest) String() string {
 func (*ArtifactDownloadRequest) ProtoMessage() {}
 
 func (x *ArtifactDownloadRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[103]
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[114]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -9377,7 +10547,7 @@ func (x *ArtifactDownloadRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ArtifactDownloadRequest.ProtoReflect.Descriptor instead.
 func (*ArtifactDownloadRequest) Descriptor() ([]byte, []int) {
-	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{103}
+	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{114}
 }
 
 func (x *ArtifactDownloadRequest) GetJobExecutionId() string {
@@ -9445,7 +10615,7 @@ type ArtifactDownloadChunk struct {
 
 func (x *ArtifactDownloadChunk) Reset() {
 	*x = ArtifactDownloadChunk{}
-	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[104]
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[115]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -9457,7 +10627,7 @@ func (x *ArtifactDownloadChunk) String() string {
 func (*ArtifactDownloadChunk) ProtoMessage() {}
 
 func (x *ArtifactDownloadChunk) ProtoReflect() protoreflect.Message {
-	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[104]
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[115]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -9470,7 +10640,7 @@ func (x *ArtifactDownloadChunk) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ArtifactDownloadChunk.ProtoReflect.Descriptor instead.
 func (*ArtifactDownloadChunk) Descriptor() ([]byte, []int) {
-	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{104}
+	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{115}
 }
 
 func (x *ArtifactDownloadChunk) GetToken() string {
@@ -9544,7 +10714,7 @@ type JobRunContext struct {
 
 func (x *JobRunContext) Reset() {
 	*x = JobRunContext{}
-	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[105]
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[116]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -9556,7 +10726,7 @@ func (x *JobRunContext) String() string {
 func (*JobRunContext) ProtoMessage() {}
 
 func (x *JobRunContext) ProtoReflect() protoreflect.Message {
-	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[105]
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[116]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -9569,7 +10739,7 @@ func (x *JobRunContext) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use JobRunContext.ProtoReflect.Descriptor instead.
 func (*JobRunContext) Descriptor() ([]byte, []int) {
-	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{105}
+	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{116}
 }
 
 func (x *JobRunContext) GetAvailable() bool {
@@ -9635,7 +10805,7 @@ type JobRunContextPipeline struct {
 
 func (x *JobRunContextPipeline) Reset() {
 	*x = JobRunContextPipeline{}
-	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[106]
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[117]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -9647,7 +10817,7 @@ func (x *JobRunContextPipeline) String() string {
 func (*JobRunContextPipeline) ProtoMessage() {}
 
 func (x *JobRunContextPipeline) ProtoReflect() protoreflect.Message {
-	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[106]
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[117]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -9660,7 +10830,7 @@ func (x *JobRunContextPipeline) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use JobRunContextPipeline.ProtoReflect.Descriptor instead.
 func (*JobRunContextPipeline) Descriptor() ([]byte, []int) {
-	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{106}
+	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{117}
 }
 
 func (x *JobRunContextPipeline) GetId() int64 {
@@ -9718,7 +10888,7 @@ type JobRunContextJob struct {
 
 func (x *JobRunContextJob) Reset() {
 	*x = JobRunContextJob{}
-	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[107]
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[118]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -9730,7 +10900,7 @@ func (x *JobRunContextJob) String() string {
 func (*JobRunContextJob) ProtoMessage() {}
 
 func (x *JobRunContextJob) ProtoReflect() protoreflect.Message {
-	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[107]
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[118]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -9743,7 +10913,7 @@ func (x *JobRunContextJob) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use JobRunContextJob.ProtoReflect.Descriptor instead.
 func (*JobRunContextJob) Descriptor() ([]byte, []int) {
-	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{107}
+	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{118}
 }
 
 func (x *JobRunContextJob) GetId() string {
@@ -9795,7 +10965,7 @@ type JobRunContextExecution struct {
 
 func (x *JobRunContextExecution) Reset() {
 	*x = JobRunContextExecution{}
-	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[108]
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[119]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -9807,7 +10977,7 @@ func (x *JobRunContextExecution) String() string {
 func (*JobRunContextExecution) ProtoMessage() {}
 
 func (x *JobRunContextExecution) ProtoReflect() protoreflect.Message {
-	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[108]
+	mi := &file_ciwi_native_v1_ciwi_proto_msgTypes[119]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -9820,7 +10990,7 @@ func (x *JobRunContextExecution) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use JobRunContextExecution.ProtoReflect.Descriptor instead.
 func (*JobRunContextExecution) Descriptor() ([]byte, []int) {
-	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{108}
+	return file_ciwi_native_v1_ciwi_proto_rawDescGZIP(), []int{119}
 }
 
 func (x *JobRunContextExecution) GetId() string {
@@ -9870,12 +11040,13 @@ var File_ciwi_native_v1_ciwi_proto protoreflect.FileDescriptor
 const file_ciwi_native_v1_ciwi_proto_rawDesc = "" +
 	"\n" +
 	"\x19ciwi/native/v1/ciwi.proto\x12\x0eciwi.native.v1\"\a\n" +
-	"\x05Empty\"s\n" +
+	"\x05Empty\"\x90\x01\n" +
 	"\x05Hello\x12\x1f\n" +
 	"\vclient_name\x18\x01 \x01(\tR\n" +
 	"clientName\x12%\n" +
 	"\x0eclient_version\x18\x02 \x01(\tR\rclientVersion\x12\"\n" +
-	"\fcapabilities\x18\x03 \x03(\tR\fcapabilities\"\xd9\x01\n" +
+	"\fcapabilities\x18\x03 \x03(\tR\fcapabilities\x12\x1b\n" +
+	"\tapi_token\x18\x04 \x01(\tR\bapiToken\"\xd9\x01\n" +
 	"\aWelcome\x12\x1f\n" +
 	"\vserver_name\x18\x01 \x01(\tR\n" +
 	"serverName\x12%\n" +
@@ -9968,14 +11139,62 @@ const file_ciwi_native_v1_ciwi_proto_rawDesc = "" +
 	"\x04data\x18\x02 \x01(\fR\x04data\x12!\n" +
 	"\fcontent_type\x18\x03 \x01(\tR\vcontentType\"D\n" +
 	"\x0fProjectIconList\x121\n" +
-	"\x05icons\x18\x01 \x03(\v2\x1b.ciwi.native.v1.ProjectIconR\x05icons\"\x9c\x03\n" +
+	"\x05icons\x18\x01 \x03(\v2\x1b.ciwi.native.v1.ProjectIconR\x05icons\"\xea\x04\n" +
 	"\x12ProjectDetailsView\x128\n" +
 	"\aproject\x18\x01 \x01(\v2\x1e.ciwi.native.v1.ProjectSummaryR\aproject\x12D\n" +
 	"\tpipelines\x18\x02 \x03(\v2&.ciwi.native.v1.ProjectPipelineDetailsR\tpipelines\x12S\n" +
 	"\x12history_executions\x18\x03 \x03(\v2$.ciwi.native.v1.ExecutionCardSummaryR\x11historyExecutions\x12!\n" +
 	"\fproject_icon\x18\x04 \x01(\fR\vprojectIcon\x129\n" +
 	"\x19project_icon_content_type\x18\x05 \x01(\tR\x16projectIconContentType\x12S\n" +
-	"\x11structure_filters\x18\x06 \x03(\v2&.ciwi.native.v1.ProjectStructureFilterR\x10structureFilters\"\x8b\x02\n" +
+	"\x11structure_filters\x18\x06 \x03(\v2&.ciwi.native.v1.ProjectStructureFilterR\x10structureFilters\x12L\n" +
+	"\x0ftest_quarantine\x18\a \x03(\v2#.ciwi.native.v1.TestQuarantineEntryR\x0etestQuarantine\x126\n" +
+	"\x17test_quarantine_notices\x18\b \x03(\tR\x15testQuarantineNotices\x12F\n" +
+	"\x0fcoverage_trends\x18\t \x03(\v2\x1d.ciwi.native.v1.CoverageTrendR\x0ecoverageTrends\"\xa1\x01\n" +
+	"\rCoverageTrend\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05label\x18\x02 \x01(\tR\x05label\x12\x18\n" +
+	"\asummary\x18\x03 \x01(\tR\asummary\x12\x12\n" +
+	"\x04tone\x18\x04 \x01(\tR\x04tone\x12:\n" +
+	"\x06points\x18\x05 \x03(\v2\".ciwi.native.v1.CoverageTrendPointR\x06points\"\xed\x01\n" +
+	"\x12CoverageTrendPoint\x12(\n" +
+	"\x10job_execution_id\x18\x01 \x01(\tR\x0ejobExecutionId\x12#\n" +
+	"\rpercent_label\x18\x02 \x01(\tR\fpercentLabel\x12\x1f\n" +
+	"\vdelta_label\x18\x03 \x01(\tR\n" +
+	"deltaLabel\x12\x1d\n" +
+	"\n" +
+	"date_label\x18\x04 \x01(\tR\tdateLabel\x12\x12\n" +
+	"\x04tone\x18\x05 \x01(\tR\x04tone\x124\n" +
+	"\bprogress\x18\x06 \x01(\v2\x18.ciwi.native.v1.ProgressR\bprogress\"\x92\x02\n" +
+	"\x13TestQuarantineEntry\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x1d\n" +
+	"\n" +
+	"project_id\x18\x02 \x01(\x03R\tprojectId\x12\x18\n" +
+	"\apackage\x18\x03 \x01(\tR\apackage\x12\x12\n" +
+	"\x04name\x18\x04 \x01(\tR\x04name\x12\x1d\n" +
+	"\n" +
+	"test_label\x18\x05 \x01(\tR\ttestLabel\x12\x14\n" +
+	"\x05owner\x18\x06 \x01(\tR\x05owner\x12\x16\n" +
+	"\x06reason\x18\a \x01(\tR\x06reason\x12#\n" +
+	"\rexpires_label\x18\b \x01(\tR\fexpiresLabel\x12\x18\n" +
+	"\aexpired\x18\t \x01(\bR\aexpired\x12\x12\n" +
+	"\x04tone\x18\n" +
+	" \x01(\tR\x04tone\"\xaf\x01\n" +
+	"\x18AddTestQuarantineRequest\x12\x1d\n" +
+	"\n" +
+	"project_id\x18\x01 \x01(\x03R\tprojectId\x12\x18\n" +
+	"\apackage\x18\x02 \x01(\tR\apackage\x12\x12\n" +
+	"\x04name\x18\x03 \x01(\tR\x04name\x12\x14\n" +
+	"\x05owner\x18\x04 \x01(\tR\x05owner\x12\x16\n" +
+	"\x06reason\x18\x05 \x01(\tR\x06reason\x12\x18\n" +
+	"\aexpires\x18\x06 \x01(\tR\aexpires\"L\n" +
+	"\x1bRemoveTestQuarantineRequest\x12\x1d\n" +
+	"\n" +
+	"project_id\x18\x01 \x01(\x03R\tprojectId\x12\x0e\n" +
+	"\x02id\x18\x02 \x01(\x03R\x02id\"K\n" +
+	"\x1aRemoveTestQuarantineResult\x12\x1d\n" +
+	"\n" +
+	"project_id\x18\x01 \x01(\x03R\tprojectId\x12\x0e\n" +
+	"\x02id\x18\x02 \x01(\x03R\x02id\"\x8b\x02\n" +
 	"\x16ProjectStructureFilter\x12\x14\n" +
 	"\x05value\x18\x01 \x01(\tR\x05value\x12\x14\n" +
 	"\x05label\x18\x02 \x01(\tR\x05label\x12!\n" +
@@ -9990,7 +11209,7 @@ const file_ciwi_native_v1_ciwi_proto_rawDesc = "" +
 	"\brunnable\x18\x04 \x01(\bR\brunnable\x12\x1d\n" +
 	"\n" +
 	"project_id\x18\x05 \x01(\x03R\tprojectId\x12\x19\n" +
-	"\bchain_id\x18\x06 \x01(\tR\achainId\"\xfb\x02\n" +
+	"\bchain_id\x18\x06 \x01(\tR\achainId\"\xc5\x03\n" +
 	"\x16ProjectPipelineDetails\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x1f\n" +
 	"\vpipeline_id\x18\x02 \x01(\tR\n" +
@@ -10005,7 +11224,9 @@ const file_ciwi_native_v1_ciwi_proto_rawDesc = "" +
 	"\x04jobs\x18\b \x03(\v2!.ciwi.native.v1.ProjectJobDetailsR\x04jobs\x12#\n" +
 	"\rsummary_label\x18\t \x01(\tR\fsummaryLabel\x12.\n" +
 	"\x13graph_summary_label\x18\n" +
-	" \x01(\tR\x11graphSummaryLabel\"\xdd\x03\n" +
+	" \x01(\tR\x11graphSummaryLabel\x12#\n" +
+	"\rtrigger_label\x18\v \x01(\tR\ftriggerLabel\x12#\n" +
+	"\rtrigger_error\x18\f \x01(\tR\ftriggerError\"\xdd\x03\n" +
 	"\x11ProjectJobDetails\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x14\n" +
 	"\x05needs\x18\x02 \x03(\tR\x05needs\x12\x1f\n" +
@@ -10214,7 +11435,48 @@ const file_ciwi_native_v1_ciwi_proto_rawDesc = "" +
 	"\bchunk_id\x18\x02 \x01(\x03R\achunkId\x12\x1d\n" +
 	"\n" +
 	"start_rune\x18\x03 \x01(\x05R\tstartRune\x12\x19\n" +
-	"\bend_rune\x18\x04 \x01(\x05R\aendRune\"\xa2\x01\n" +
+	"\bend_rune\x18\x04 \x01(\x05R\aendRune\"\xb6\x01\n" +
+	"\x15GetTestHistoryRequest\x12\x1d\n" +
+	"\n" +
+	"project_id\x18\x01 \x01(\x03R\tprojectId\x12\x1f\n" +
+	"\vpipeline_id\x18\x02 \x01(\tR\n" +
+	"pipelineId\x12&\n" +
+	"\x0fpipeline_job_id\x18\x03 \x01(\tR\rpipelineJobId\x12\x1f\n" +
+	"\vmatrix_name\x18\x04 \x01(\tR\n" +
+	"matrixName\x12\x14\n" +
+	"\x05query\x18\x05 \x01(\tR\x05query\"\xa2\x02\n" +
+	"\x0fTestHistoryView\x12\x1d\n" +
+	"\n" +
+	"project_id\x18\x01 \x01(\x03R\tprojectId\x12\x1f\n" +
+	"\vpipeline_id\x18\x02 \x01(\tR\n" +
+	"pipelineId\x12&\n" +
+	"\x0fpipeline_job_id\x18\x03 \x01(\tR\rpipelineJobId\x12\x1f\n" +
+	"\vmatrix_name\x18\x04 \x01(\tR\n" +
+	"matrixName\x12\x14\n" +
+	"\x05title\x18\x05 \x01(\tR\x05title\x12\x18\n" +
+	"\asummary\x18\x06 \x01(\tR\asummary\x12\x1f\n" +
+	"\vempty_label\x18\a \x01(\tR\n" +
+	"emptyLabel\x125\n" +
+	"\x05tests\x18\b \x03(\v2\x1f.ciwi.native.v1.TestHistoryTestR\x05tests\"\xf2\x03\n" +
+	"\x0fTestHistoryTest\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x18\n" +
+	"\apackage\x18\x02 \x01(\tR\apackage\x12\x12\n" +
+	"\x04name\x18\x03 \x01(\tR\x04name\x12\x16\n" +
+	"\x06status\x18\x04 \x01(\tR\x06status\x12!\n" +
+	"\fstatus_label\x18\x05 \x01(\tR\vstatusLabel\x12\x12\n" +
+	"\x04tone\x18\x06 \x01(\tR\x04tone\x12\x14\n" +
+	"\x05badge\x18\a \x01(\tR\x05badge\x12\x1f\n" +
+	"\vbadge_label\x18\b \x01(\tR\n" +
+	"badgeLabel\x12\x1d\n" +
+	"\n" +
+	"runs_label\x18\t \x01(\tR\trunsLabel\x12!\n" +
+	"\fstreak_label\x18\n" +
+	" \x01(\tR\vstreakLabel\x12'\n" +
+	"\x0fflakiness_score\x18\v \x01(\x01R\x0eflakinessScore\x12'\n" +
+	"\x0fflakiness_label\x18\f \x01(\tR\x0eflakinessLabel\x12%\n" +
+	"\x0eduration_label\x18\r \x01(\tR\rdurationLabel\x12'\n" +
+	"\x0frecent_statuses\x18\x0e \x03(\tR\x0erecentStatuses\x125\n" +
+	"\x17latest_job_execution_id\x18\x0f \x01(\tR\x14latestJobExecutionId\"\xda\x01\n" +
 	"\x10ExecutionSummary\x12\x1d\n" +
 	"\n" +
 	"total_jobs\x18\x01 \x01(\rR\ttotalJobs\x12\x1c\n" +
@@ -10222,7 +11484,9 @@ const file_ciwi_native_v1_ciwi_proto_rawDesc = "" +
 	"\x06failed\x18\x03 \x01(\rR\x06failed\x12\x1f\n" +
 	"\vin_progress\x18\x04 \x01(\rR\n" +
 	"inProgress\x12\x18\n" +
-	"\awaiting\x18\x05 \x01(\rR\awaiting\"\xc5\x03\n" +
+	"\awaiting\x18\x05 \x01(\rR\awaiting\x12\x1c\n" +
+	"\tcancelled\x18\x06 \x01(\rR\tcancelled\x12\x18\n" +
+	"\askipped\x18\a \x01(\rR\askipped\"\xc5\x03\n" +
 	"\x14ExecutionCardSummary\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\tR\x03key\x12\x12\n" +
 	"\x04kind\x18\x02 \x01(\tR\x04kind\x12\x14\n" +
@@ -10270,7 +11534,7 @@ const file_ciwi_native_v1_ciwi_proto_rawDesc = "" +
 	"\x05state\x18\x01 \x01(\tR\x05state\x12\x1a\n" +
 	"\bfraction\x18\x02 \x01(\x01R\bfraction\x12(\n" +
 	"\x10snapshot_unix_ms\x18\x03 \x01(\x03R\x0esnapshotUnixMs\x12\x1e\n" +
-	"\vrate_per_ms\x18\x04 \x01(\x01R\tratePerMs\"\x92\x02\n" +
+	"\vrate_per_ms\x18\x04 \x01(\x01R\tratePerMs\"\xc0\x02\n" +
 	"\x14RunPipelineSelection\x12&\n" +
 	"\x0fpipeline_job_id\x18\x01 \x01(\tR\rpipelineJobId\x12\x1f\n" +
 	"\vmatrix_name\x18\x02 \x01(\tR\n" +
@@ -10280,8 +11544,10 @@ const file_ciwi_native_v1_ciwi_proto_rawDesc = "" +
 	"\n" +
 	"source_ref\x18\x05 \x01(\tR\tsourceRef\x12\x19\n" +
 	"\bagent_id\x18\x06 \x01(\tR\aagentId\x12%\n" +
-	"\x0eexecution_mode\x18\a \x01(\tR\rexecutionModeB\x0f\n" +
-	"\r_matrix_index\"~\n" +
+	"\x0eexecution_mode\x18\a \x01(\tR\rexecutionMode\x12\x1f\n" +
+	"\bpriority\x18\b \x01(\x05H\x01R\bpriority\x88\x01\x01B\x0f\n" +
+	"\r_matrix_indexB\v\n" +
+	"\t_priority\"~\n" +
 	"\x12RunPipelineRequest\x12$\n" +
 	"\x0epipeline_db_id\x18\x01 \x01(\x03R\fpipelineDbId\x12B\n" +
 	"\tselection\x18\x02 \x01(\v2$.ciwi.native.v1.RunPipelineSelectionR\tselection\"\x9f\x01\n" +
@@ -10311,7 +11577,7 @@ const file_ciwi_native_v1_ciwi_proto_rawDesc = "" +
 	"\tselection\x18\x04 \x01(\v2$.ciwi.native.v1.RunPipelineSelectionR\tselection\"7\n" +
 	"\tRunOption\x12\x14\n" +
 	"\x05value\x18\x01 \x01(\tR\x05value\x12\x14\n" +
-	"\x05label\x18\x02 \x01(\tR\x05label\"\xac\x04\n" +
+	"\x05label\x18\x02 \x01(\tR\x05label\"\x9f\x05\n" +
 	"\x0eRunOptionsView\x12\x1f\n" +
 	"\vtarget_kind\x18\x01 \x01(\tR\n" +
 	"targetKind\x12!\n" +
@@ -10330,7 +11596,9 @@ const file_ciwi_native_v1_ciwi_proto_rawDesc = "" +
 	" \x03(\v2\x19.ciwi.native.v1.RunOptionR\x0eeligibleAgents\x12!\n" +
 	"\fpending_jobs\x18\v \x01(\rR\vpendingJobs\x12.\n" +
 	"\x13selected_source_ref\x18\f \x01(\tR\x11selectedSourceRef\x12*\n" +
-	"\x11selected_agent_id\x18\r \x01(\tR\x0fselectedAgentId\"\xdc\x05\n" +
+	"\x11selected_agent_id\x18\r \x01(\tR\x0fselectedAgentId\x12D\n" +
+	"\x10priority_options\x18\x0e \x03(\v2\x19.ciwi.native.v1.RunOptionR\x0fpriorityOptions\x12+\n" +
+	"\x11selected_priority\x18\x0f \x01(\tR\x10selectedPriority\"\xeb\a\n" +
 	"\fAgentSummary\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1a\n" +
 	"\bhostname\x18\x02 \x01(\tR\bhostname\x12\x1a\n" +
@@ -10360,11 +11628,23 @@ const file_ciwi_native_v1_ciwi_proto_rawDesc = "" +
 	"canContact\x12$\n" +
 	"\x0ecan_run_script\x18\x13 \x01(\bR\fcanRunScript\x12E\n" +
 	"\rscript_shells\x18\x14 \x03(\v2 .ciwi.native.v1.AgentScriptShellR\fscriptShells\x12)\n" +
-	"\x11last_seen_unix_ms\x18\x15 \x01(\x03R\x0elastSeenUnixMs\"e\n" +
+	"\x11last_seen_unix_ms\x18\x15 \x01(\x03R\x0elastSeenUnixMs\x12\x14\n" +
+	"\x05slots\x18\x16 \x01(\rR\x05slots\x12\x1f\n" +
+	"\vactive_jobs\x18\x17 \x01(\rR\n" +
+	"activeJobs\x12\x1f\n" +
+	"\vslots_label\x18\x18 \x01(\tR\n" +
+	"slotsLabel\x12%\n" +
+	"\x0eselected_slots\x18\x19 \x01(\tR\rselectedSlots\x12B\n" +
+	"\fslot_options\x18\x1a \x03(\v2\x1f.ciwi.native.v1.AgentSlotOptionR\vslotOptions\x12!\n" +
+	"\flabels_label\x18\x1b \x01(\tR\vlabelsLabel\x12'\n" +
+	"\x0fassigned_labels\x18\x1c \x01(\tR\x0eassignedLabels\"e\n" +
 	"\x10AgentScriptShell\x12\x14\n" +
 	"\x05value\x18\x01 \x01(\tR\x05value\x12\x14\n" +
 	"\x05label\x18\x02 \x01(\tR\x05label\x12%\n" +
-	"\x0eexample_script\x18\x03 \x01(\tR\rexampleScript\"\\\n" +
+	"\x0eexample_script\x18\x03 \x01(\tR\rexampleScript\"=\n" +
+	"\x0fAgentSlotOption\x12\x14\n" +
+	"\x05value\x18\x01 \x01(\tR\x05value\x12\x14\n" +
+	"\x05label\x18\x02 \x01(\tR\x05label\"\\\n" +
 	"\n" +
 	"AgentsView\x12\x18\n" +
 	"\asummary\x18\x01 \x01(\tR\asummary\x124\n" +
@@ -10372,10 +11652,16 @@ const file_ciwi_native_v1_ciwi_proto_rawDesc = "" +
 	"\x16GetAgentDetailsRequest\x12\x19\n" +
 	"\bagent_id\x18\x01 \x01(\tR\aagentId\"F\n" +
 	"\x10AgentDetailsView\x122\n" +
-	"\x05agent\x18\x01 \x01(\v2\x1c.ciwi.native.v1.AgentSummaryR\x05agent\"G\n" +
+	"\x05agent\x18\x01 \x01(\v2\x1c.ciwi.native.v1.AgentSummaryR\x05agent\"\x91\x01\n" +
 	"\x12AgentActionRequest\x12\x19\n" +
 	"\bagent_id\x18\x01 \x01(\tR\aagentId\x12\x16\n" +
-	"\x06action\x18\x02 \x01(\tR\x06action\"~\n" +
+	"\x06action\x18\x02 \x01(\tR\x06action\x12\x14\n" +
+	"\x05slots\x18\x03 \x01(\rR\x05slots\x122\n" +
+	"\x06labels\x18\x04 \x03(\v2\x1a.ciwi.native.v1.AgentLabelR\x06labels\"4\n" +
+	"\n" +
+	"AgentLabel\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value\"~\n" +
 	"\x11AgentActionResult\x12\x1c\n" +
 	"\trequested\x18\x01 \x01(\bR\trequested\x12\x19\n" +
 	"\bagent_id\x18\x02 \x01(\tR\aagentId\x12\x18\n" +
@@ -10533,7 +11819,7 @@ const file_ciwi_native_v1_ciwi_proto_rawDesc = "" +
 	"\x06topics\x18\x03 \x03(\x0e2\x1b.ciwi.native.v1.ChangeTopicR\x06topics\x12(\n" +
 	"\x10occurred_unix_ms\x18\x04 \x01(\x03R\x0eoccurredUnixMs\x12'\n" +
 	"\x0fresync_required\x18\x05 \x01(\bR\x0eresyncRequired\x12*\n" +
-	"\x11job_execution_ids\x18\x06 \x03(\tR\x0fjobExecutionIds\"\xc0\x1a\n" +
+	"\x11job_execution_ids\x18\x06 \x03(\tR\x0fjobExecutionIds\"\xd4\x1c\n" +
 	"\aRequest\x12;\n" +
 	"\bmetadata\x18\x01 \x01(\v2\x1f.ciwi.native.v1.RequestMetadataR\bmetadata\x12?\n" +
 	"\x0fget_server_info\x18\n" +
@@ -10575,8 +11861,11 @@ const file_ciwi_native_v1_ciwi_proto_rawDesc = "" +
 	"\x16get_job_log_descriptor\x18- \x01(\v2'.ciwi.native.v1.JobLogDescriptorRequestH\x00R\x13getJobLogDescriptor\x12L\n" +
 	"\x10get_job_log_page\x18. \x01(\v2!.ciwi.native.v1.JobLogPageRequestH\x00R\rgetJobLogPage\x12K\n" +
 	"\x0esearch_job_log\x18/ \x01(\v2#.ciwi.native.v1.JobLogSearchRequestH\x00R\fsearchJobLog\x12H\n" +
-	"\rwatch_job_log\x180 \x01(\v2\".ciwi.native.v1.WatchJobLogRequestH\x00R\vwatchJobLogB\v\n" +
-	"\toperation\"\xb7\x17\n" +
+	"\rwatch_job_log\x180 \x01(\v2\".ciwi.native.v1.WatchJobLogRequestH\x00R\vwatchJobLog\x12Q\n" +
+	"\x10get_test_history\x181 \x01(\v2%.ciwi.native.v1.GetTestHistoryRequestH\x00R\x0egetTestHistory\x12Z\n" +
+	"\x13add_test_quarantine\x182 \x01(\v2(.ciwi.native.v1.AddTestQuarantineRequestH\x00R\x11addTestQuarantine\x12c\n" +
+	"\x16remove_test_quarantine\x183 \x01(\v2+.ciwi.native.v1.RemoveTestQuarantineRequestH\x00R\x14removeTestQuarantineB\v\n" +
+	"\toperation\"\xbc\x19\n" +
 	"\bResponse\x12\x1d\n" +
 	"\n" +
 	"request_id\x18\x01 \x01(\tR\trequestId\x12=\n" +
@@ -10623,7 +11912,10 @@ const file_ciwi_native_v1_ciwi_proto_rawDesc = "" +
 	"\x12job_log_descriptor\x18, \x01(\v2 .ciwi.native.v1.JobLogDescriptorH\x00R\x10jobLogDescriptor\x12>\n" +
 	"\fjob_log_page\x18- \x01(\v2\x1a.ciwi.native.v1.JobLogPageH\x00R\n" +
 	"jobLogPage\x12J\n" +
-	"\x0ejob_log_search\x18. \x01(\v2\".ciwi.native.v1.JobLogSearchResultH\x00R\fjobLogSearchB\b\n" +
+	"\x0ejob_log_search\x18. \x01(\v2\".ciwi.native.v1.JobLogSearchResultH\x00R\fjobLogSearch\x12D\n" +
+	"\ftest_history\x18/ \x01(\v2\x1f.ciwi.native.v1.TestHistoryViewH\x00R\vtestHistory\x12Y\n" +
+	"\x15test_quarantine_entry\x180 \x01(\v2#.ciwi.native.v1.TestQuarantineEntryH\x00R\x13testQuarantineEntry\x12b\n" +
+	"\x16remove_test_quarantine\x181 \x01(\v2*.ciwi.native.v1.RemoveTestQuarantineResultH\x00R\x14removeTestQuarantineB\b\n" +
 	"\x06result\"{\n" +
 	"\rClientMessage\x12-\n" +
 	"\x05hello\x18\x01 \x01(\v2\x15.ciwi.native.v1.HelloH\x00R\x05hello\x123\n" +
@@ -10656,7 +11948,7 @@ const file_ciwi_native_v1_ciwi_proto_rawDesc = "" +
 	"\x10can_download_all\x18\t \x01(\bR\x0ecanDownloadAll\":\n" +
 	"\fReportFilter\x12\x14\n" +
 	"\x05value\x18\x01 \x01(\tR\x05value\x12\x14\n" +
-	"\x05label\x18\x02 \x01(\tR\x05label\"\xe3\x02\n" +
+	"\x05label\x18\x02 \x01(\tR\x05label\"\xf9\x02\n" +
 	"\bTreeNode\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
 	"\x05label\x18\x03 \x01(\tR\x05label\x12\x16\n" +
@@ -10671,7 +11963,8 @@ const file_ciwi_native_v1_ciwi_proto_rawDesc = "" +
 	"\x10default_expanded\x18\n" +
 	" \x01(\bR\x0fdefaultExpanded\x12#\n" +
 	"\rfilter_values\x18\v \x03(\tR\ffilterValues\x124\n" +
-	"\bchildren\x18\f \x03(\v2\x18.ciwi.native.v1.TreeNodeR\bchildrenJ\x04\b\x02\x10\x03\"\xe1\x01\n" +
+	"\bchildren\x18\f \x03(\v2\x18.ciwi.native.v1.TreeNodeR\bchildren\x12\x14\n" +
+	"\x05badge\x18\r \x01(\tR\x05badgeJ\x04\b\x02\x10\x03\"\xe1\x01\n" +
 	"\x17ArtifactDownloadRequest\x12(\n" +
 	"\x10job_execution_id\x18\x01 \x01(\tR\x0ejobExecutionId\x12\x12\n" +
 	"\x04kind\x18\x02 \x01(\tR\x04kind\x12\x12\n" +
@@ -10724,7 +12017,7 @@ const file_ciwi_native_v1_ciwi_proto_rawDesc = "" +
 	"\fmatrix_label\x18\x03 \x01(\tR\vmatrixLabel\x12#\n" +
 	"\rattempt_label\x18\x04 \x01(\tR\fattemptLabel\x12\x18\n" +
 	"\acurrent\x18\x05 \x01(\bR\acurrent\x12%\n" +
-	"\x0elatest_attempt\x18\x06 \x01(\bR\rlatestAttempt*\xf9\x01\n" +
+	"\x0elatest_attempt\x18\x06 \x01(\bR\rlatestAttempt*\xbd\x02\n" +
 	"\n" +
 	"StatusCode\x12\x1b\n" +
 	"\x17STATUS_CODE_UNSPECIFIED\x10\x00\x12 \n" +
@@ -10734,7 +12027,9 @@ const file_ciwi_native_v1_ciwi_proto_rawDesc = "" +
 	"\x1fSTATUS_CODE_FAILED_PRECONDITION\x10\x04\x12\x1b\n" +
 	"\x17STATUS_CODE_UNAVAILABLE\x10\x05\x12\x1b\n" +
 	"\x17STATUS_CODE_UNSUPPORTED\x10\x06\x12\x18\n" +
-	"\x14STATUS_CODE_INTERNAL\x10\a*\xc4\x01\n" +
+	"\x14STATUS_CODE_INTERNAL\x10\a\x12\x1f\n" +
+	"\x1bSTATUS_CODE_UNAUTHENTICATED\x10\b\x12!\n" +
+	"\x1dSTATUS_CODE_PERMISSION_DENIED\x10\t*\xc4\x01\n" +
 	"\x0eJobLogPageMode\x12!\n" +
 	"\x1dJOB_LOG_PAGE_MODE_UNSPECIFIED\x10\x00\x12\x1a\n" +
 	"\x16JOB_LOG_PAGE_MODE_HEAD\x10\x01\x12\x1a\n" +
@@ -10767,7 +12062,7 @@ func file_ciwi_native_v1_ciwi_proto_rawDescGZIP() []byte {
 }
 
 var file_ciwi_native_v1_ciwi_proto_enumTypes = make([]protoimpl.EnumInfo, 3)
-var file_ciwi_native_v1_ciwi_proto_msgTypes = make([]protoimpl.MessageInfo, 109)
+var file_ciwi_native_v1_ciwi_proto_msgTypes = make([]protoimpl.MessageInfo, 120)
 var file_ciwi_native_v1_ciwi_proto_goTypes = []any{
 	(StatusCode)(0),                      // 0: ciwi.native.v1.StatusCode
 	(JobLogPageMode)(0),                  // 1: ciwi.native.v1.JobLogPageMode
@@ -10788,99 +12083,110 @@ var file_ciwi_native_v1_ciwi_proto_goTypes = []any{
 	(*ProjectIcon)(nil),                  // 16: ciwi.native.v1.ProjectIcon
 	(*ProjectIconList)(nil),              // 17: ciwi.native.v1.ProjectIconList
 	(*ProjectDetailsView)(nil),           // 18: ciwi.native.v1.ProjectDetailsView
-	(*ProjectStructureFilter)(nil),       // 19: ciwi.native.v1.ProjectStructureFilter
-	(*ProjectStructureRoot)(nil),         // 20: ciwi.native.v1.ProjectStructureRoot
-	(*ProjectPipelineDetails)(nil),       // 21: ciwi.native.v1.ProjectPipelineDetails
-	(*ProjectJobDetails)(nil),            // 22: ciwi.native.v1.ProjectJobDetails
-	(*ProjectStepDetails)(nil),           // 23: ciwi.native.v1.ProjectStepDetails
-	(*GetProjectDetailsRequest)(nil),     // 24: ciwi.native.v1.GetProjectDetailsRequest
-	(*GetJobDetailsRequest)(nil),         // 25: ciwi.native.v1.GetJobDetailsRequest
-	(*JobDetailsView)(nil),               // 26: ciwi.native.v1.JobDetailsView
-	(*SchedulingDiagnosis)(nil),          // 27: ciwi.native.v1.SchedulingDiagnosis
-	(*SchedulingAgentAssessment)(nil),    // 28: ciwi.native.v1.SchedulingAgentAssessment
-	(*ControlExecutionRequest)(nil),      // 29: ciwi.native.v1.ControlExecutionRequest
-	(*CancelExecutionResult)(nil),        // 30: ciwi.native.v1.CancelExecutionResult
-	(*RerunExecutionResult)(nil),         // 31: ciwi.native.v1.RerunExecutionResult
-	(*JobTimelineItem)(nil),              // 32: ciwi.native.v1.JobTimelineItem
-	(*JobOutputGroup)(nil),               // 33: ciwi.native.v1.JobOutputGroup
-	(*WatchJobOutputRequest)(nil),        // 34: ciwi.native.v1.WatchJobOutputRequest
-	(*JobOutputBatch)(nil),               // 35: ciwi.native.v1.JobOutputBatch
-	(*JobOutputEvent)(nil),               // 36: ciwi.native.v1.JobOutputEvent
-	(*JobLogDescriptorRequest)(nil),      // 37: ciwi.native.v1.JobLogDescriptorRequest
-	(*JobLogPageRequest)(nil),            // 38: ciwi.native.v1.JobLogPageRequest
-	(*JobLogSearchRequest)(nil),          // 39: ciwi.native.v1.JobLogSearchRequest
-	(*WatchJobLogRequest)(nil),           // 40: ciwi.native.v1.WatchJobLogRequest
-	(*JobLogDescriptor)(nil),             // 41: ciwi.native.v1.JobLogDescriptor
-	(*JobLogStream)(nil),                 // 42: ciwi.native.v1.JobLogStream
-	(*JobLogPage)(nil),                   // 43: ciwi.native.v1.JobLogPage
-	(*JobLogChunk)(nil),                  // 44: ciwi.native.v1.JobLogChunk
-	(*JobLogSearchResult)(nil),           // 45: ciwi.native.v1.JobLogSearchResult
-	(*JobLogMatch)(nil),                  // 46: ciwi.native.v1.JobLogMatch
-	(*ExecutionSummary)(nil),             // 47: ciwi.native.v1.ExecutionSummary
-	(*ExecutionCardSummary)(nil),         // 48: ciwi.native.v1.ExecutionCardSummary
-	(*ExecutionCardSection)(nil),         // 49: ciwi.native.v1.ExecutionCardSection
-	(*ExecutionCardJob)(nil),             // 50: ciwi.native.v1.ExecutionCardJob
-	(*Progress)(nil),                     // 51: ciwi.native.v1.Progress
-	(*RunPipelineSelection)(nil),         // 52: ciwi.native.v1.RunPipelineSelection
-	(*RunPipelineRequest)(nil),           // 53: ciwi.native.v1.RunPipelineRequest
-	(*RunPipelineResult)(nil),            // 54: ciwi.native.v1.RunPipelineResult
-	(*RunPipelineChainRequest)(nil),      // 55: ciwi.native.v1.RunPipelineChainRequest
-	(*RunPipelineChainResult)(nil),       // 56: ciwi.native.v1.RunPipelineChainResult
-	(*GetRunOptionsRequest)(nil),         // 57: ciwi.native.v1.GetRunOptionsRequest
-	(*RunOption)(nil),                    // 58: ciwi.native.v1.RunOption
-	(*RunOptionsView)(nil),               // 59: ciwi.native.v1.RunOptionsView
-	(*AgentSummary)(nil),                 // 60: ciwi.native.v1.AgentSummary
-	(*AgentScriptShell)(nil),             // 61: ciwi.native.v1.AgentScriptShell
-	(*AgentsView)(nil),                   // 62: ciwi.native.v1.AgentsView
-	(*GetAgentDetailsRequest)(nil),       // 63: ciwi.native.v1.GetAgentDetailsRequest
-	(*AgentDetailsView)(nil),             // 64: ciwi.native.v1.AgentDetailsView
-	(*AgentActionRequest)(nil),           // 65: ciwi.native.v1.AgentActionRequest
-	(*AgentActionResult)(nil),            // 66: ciwi.native.v1.AgentActionResult
-	(*RunAgentScriptRequest)(nil),        // 67: ciwi.native.v1.RunAgentScriptRequest
-	(*RunAgentScriptResult)(nil),         // 68: ciwi.native.v1.RunAgentScriptResult
-	(*ProjectActionRequest)(nil),         // 69: ciwi.native.v1.ProjectActionRequest
-	(*ProjectActionResult)(nil),          // 70: ciwi.native.v1.ProjectActionResult
-	(*ImportProjectRequest)(nil),         // 71: ciwi.native.v1.ImportProjectRequest
-	(*ImportProjectResult)(nil),          // 72: ciwi.native.v1.ImportProjectResult
-	(*GetManagedYAMLRequest)(nil),        // 73: ciwi.native.v1.GetManagedYAMLRequest
-	(*ManagedYAMLRequest)(nil),           // 74: ciwi.native.v1.ManagedYAMLRequest
-	(*ManagedYAMLDefinition)(nil),        // 75: ciwi.native.v1.ManagedYAMLDefinition
-	(*VaultConnection)(nil),              // 76: ciwi.native.v1.VaultConnection
-	(*VaultConnectionList)(nil),          // 77: ciwi.native.v1.VaultConnectionList
-	(*UpsertVaultConnectionRequest)(nil), // 78: ciwi.native.v1.UpsertVaultConnectionRequest
-	(*VaultConnectionIDRequest)(nil),     // 79: ciwi.native.v1.VaultConnectionIDRequest
-	(*TestVaultConnectionRequest)(nil),   // 80: ciwi.native.v1.TestVaultConnectionRequest
-	(*TestVaultConnectionResult)(nil),    // 81: ciwi.native.v1.TestVaultConnectionResult
-	(*DeleteVaultConnectionResult)(nil),  // 82: ciwi.native.v1.DeleteVaultConnectionResult
-	(*ServerUpdateStatus)(nil),           // 83: ciwi.native.v1.ServerUpdateStatus
-	(*ServerUpdateCheckResult)(nil),      // 84: ciwi.native.v1.ServerUpdateCheckResult
-	(*ServerUpdateVersions)(nil),         // 85: ciwi.native.v1.ServerUpdateVersions
-	(*ServerUpdateActionRequest)(nil),    // 86: ciwi.native.v1.ServerUpdateActionRequest
-	(*ServerUpdateActionResult)(nil),     // 87: ciwi.native.v1.ServerUpdateActionResult
-	(*ClearExecutionQueueRequest)(nil),   // 88: ciwi.native.v1.ClearExecutionQueueRequest
-	(*ClearExecutionQueueResult)(nil),    // 89: ciwi.native.v1.ClearExecutionQueueResult
-	(*FlushExecutionHistoryRequest)(nil), // 90: ciwi.native.v1.FlushExecutionHistoryRequest
-	(*FlushExecutionHistoryResult)(nil),  // 91: ciwi.native.v1.FlushExecutionHistoryResult
-	(*RemoveQueuedExecutionResult)(nil),  // 92: ciwi.native.v1.RemoveQueuedExecutionResult
-	(*CommandReceiptStatusRequest)(nil),  // 93: ciwi.native.v1.CommandReceiptStatusRequest
-	(*CommandReceiptStatus)(nil),         // 94: ciwi.native.v1.CommandReceiptStatus
-	(*WatchChangesRequest)(nil),          // 95: ciwi.native.v1.WatchChangesRequest
-	(*ChangeEvent)(nil),                  // 96: ciwi.native.v1.ChangeEvent
-	(*Request)(nil),                      // 97: ciwi.native.v1.Request
-	(*Response)(nil),                     // 98: ciwi.native.v1.Response
-	(*ClientMessage)(nil),                // 99: ciwi.native.v1.ClientMessage
-	(*ServerMessage)(nil),                // 100: ciwi.native.v1.ServerMessage
-	(*JobDetailRow)(nil),                 // 101: ciwi.native.v1.JobDetailRow
-	(*ToolRequirements)(nil),             // 102: ciwi.native.v1.ToolRequirements
-	(*ReportDetails)(nil),                // 103: ciwi.native.v1.ReportDetails
-	(*ReportFilter)(nil),                 // 104: ciwi.native.v1.ReportFilter
-	(*TreeNode)(nil),                     // 105: ciwi.native.v1.TreeNode
-	(*ArtifactDownloadRequest)(nil),      // 106: ciwi.native.v1.ArtifactDownloadRequest
-	(*ArtifactDownloadChunk)(nil),        // 107: ciwi.native.v1.ArtifactDownloadChunk
-	(*JobRunContext)(nil),                // 108: ciwi.native.v1.JobRunContext
-	(*JobRunContextPipeline)(nil),        // 109: ciwi.native.v1.JobRunContextPipeline
-	(*JobRunContextJob)(nil),             // 110: ciwi.native.v1.JobRunContextJob
-	(*JobRunContextExecution)(nil),       // 111: ciwi.native.v1.JobRunContextExecution
+	(*CoverageTrend)(nil),                // 19: ciwi.native.v1.CoverageTrend
+	(*CoverageTrendPoint)(nil),           // 20: ciwi.native.v1.CoverageTrendPoint
+	(*TestQuarantineEntry)(nil),          // 21: ciwi.native.v1.TestQuarantineEntry
+	(*AddTestQuarantineRequest)(nil),     // 22: ciwi.native.v1.AddTestQuarantineRequest
+	(*RemoveTestQuarantineRequest)(nil),  // 23: ciwi.native.v1.RemoveTestQuarantineRequest
+	(*RemoveTestQuarantineResult)(nil),   // 24: ciwi.native.v1.RemoveTestQuarantineResult
+	(*ProjectStructureFilter)(nil),       // 25: ciwi.native.v1.ProjectStructureFilter
+	(*ProjectStructureRoot)(nil),         // 26: ciwi.native.v1.ProjectStructureRoot
+	(*ProjectPipelineDetails)(nil),       // 27: ciwi.native.v1.ProjectPipelineDetails
+	(*ProjectJobDetails)(nil),            // 28: ciwi.native.v1.ProjectJobDetails
+	(*ProjectStepDetails)(nil),           // 29: ciwi.native.v1.ProjectStepDetails
+	(*GetProjectDetailsRequest)(nil),     // 30: ciwi.native.v1.GetProjectDetailsRequest
+	(*GetJobDetailsRequest)(nil),         // 31: ciwi.native.v1.GetJobDetailsRequest
+	(*JobDetailsView)(nil),               // 32: ciwi.native.v1.JobDetailsView
+	(*SchedulingDiagnosis)(nil),          // 33: ciwi.native.v1.SchedulingDiagnosis
+	(*SchedulingAgentAssessment)(nil),    // 34: ciwi.native.v1.SchedulingAgentAssessment
+	(*ControlExecutionRequest)(nil),      // 35: ciwi.native.v1.ControlExecutionRequest
+	(*CancelExecutionResult)(nil),        // 36: ciwi.native.v1.CancelExecutionResult
+	(*RerunExecutionResult)(nil),         // 37: ciwi.native.v1.RerunExecutionResult
+	(*JobTimelineItem)(nil),              // 38: ciwi.native.v1.JobTimelineItem
+	(*JobOutputGroup)(nil),               // 39: ciwi.native.v1.JobOutputGroup
+	(*WatchJobOutputRequest)(nil),        // 40: ciwi.native.v1.WatchJobOutputRequest
+	(*JobOutputBatch)(nil),               // 41: ciwi.native.v1.JobOutputBatch
+	(*JobOutputEvent)(nil),               // 42: ciwi.native.v1.JobOutputEvent
+	(*JobLogDescriptorRequest)(nil),      // 43: ciwi.native.v1.JobLogDescriptorRequest
+	(*JobLogPageRequest)(nil),            // 44: ciwi.native.v1.JobLogPageRequest
+	(*JobLogSearchRequest)(nil),          // 45: ciwi.native.v1.JobLogSearchRequest
+	(*WatchJobLogRequest)(nil),           // 46: ciwi.native.v1.WatchJobLogRequest
+	(*JobLogDescriptor)(nil),             // 47: ciwi.native.v1.JobLogDescriptor
+	(*JobLogStream)(nil),                 // 48: ciwi.native.v1.JobLogStream
+	(*JobLogPage)(nil),                   // 49: ciwi.native.v1.JobLogPage
+	(*JobLogChunk)(nil),                  // 50: ciwi.native.v1.JobLogChunk
+	(*JobLogSearchResult)(nil),           // 51: ciwi.native.v1.JobLogSearchResult
+	(*JobLogMatch)(nil),                  // 52: ciwi.native.v1.JobLogMatch
+	(*GetTestHistoryRequest)(nil),        // 53: ciwi.native.v1.GetTestHistoryRequest
+	(*TestHistoryView)(nil),              // 54: ciwi.native.v1.TestHistoryView
+	(*TestHistoryTest)(nil),              // 55: ciwi.native.v1.TestHistoryTest
+	(*ExecutionSummary)(nil),             // 56: ciwi.native.v1.ExecutionSummary
+	(*ExecutionCardSummary)(nil),         // 57: ciwi.native.v1.ExecutionCardSummary
+	(*ExecutionCardSection)(nil),         // 58: ciwi.native.v1.ExecutionCardSection
+	(*ExecutionCardJob)(nil),             // 59: ciwi.native.v1.ExecutionCardJob
+	(*Progress)(nil),                     // 60: ciwi.native.v1.Progress
+	(*RunPipelineSelection)(nil),         // 61: ciwi.native.v1.RunPipelineSelection
+	(*RunPipelineRequest)(nil),           // 62: ciwi.native.v1.RunPipelineRequest
+	(*RunPipelineResult)(nil),            // 63: ciwi.native.v1.RunPipelineResult
+	(*RunPipelineChainRequest)(nil),      // 64: ciwi.native.v1.RunPipelineChainRequest
+	(*RunPipelineChainResult)(nil),       // 65: ciwi.native.v1.RunPipelineChainResult
+	(*GetRunOptionsRequest)(nil),         // 66: ciwi.native.v1.GetRunOptionsRequest
+	(*RunOption)(nil),                    // 67: ciwi.native.v1.RunOption
+	(*RunOptionsView)(nil),               // 68: ciwi.native.v1.RunOptionsView
+	(*AgentSummary)(nil),                 // 69: ciwi.native.v1.AgentSummary
+	(*AgentScriptShell)(nil),             // 70: ciwi.native.v1.AgentScriptShell
+	(*AgentSlotOption)(nil),              // 71: ciwi.native.v1.AgentSlotOption
+	(*AgentsView)(nil),                   // 72: ciwi.native.v1.AgentsView
+	(*GetAgentDetailsRequest)(nil),       // 73: ciwi.native.v1.GetAgentDetailsRequest
+	(*AgentDetailsView)(nil),             // 74: ciwi.native.v1.AgentDetailsView
+	(*AgentActionRequest)(nil),           // 75: ciwi.native.v1.AgentActionRequest
+	(*AgentLabel)(nil),                   // 76: ciwi.native.v1.AgentLabel
+	(*AgentActionResult)(nil),            // 77: ciwi.native.v1.AgentActionResult
+	(*RunAgentScriptRequest)(nil),        // 78: ciwi.native.v1.RunAgentScriptRequest
+	(*RunAgentScriptResult)(nil),         // 79: ciwi.native.v1.RunAgentScriptResult
+	(*ProjectActionRequest)(nil),         // 80: ciwi.native.v1.ProjectActionRequest
+	(*ProjectActionResult)(nil),          // 81: ciwi.native.v1.ProjectActionResult
+	(*ImportProjectRequest)(nil),         // 82: ciwi.native.v1.ImportProjectRequest
+	(*ImportProjectResult)(nil),          // 83: ciwi.native.v1.ImportProjectResult
+	(*GetManagedYAMLRequest)(nil),        // 84: ciwi.native.v1.GetManagedYAMLRequest
+	(*ManagedYAMLRequest)(nil),           // 85: ciwi.native.v1.ManagedYAMLRequest
+	(*ManagedYAMLDefinition)(nil),        // 86: ciwi.native.v1.ManagedYAMLDefinition
+	(*VaultConnection)(nil),              // 87: ciwi.native.v1.VaultConnection
+	(*VaultConnectionList)(nil),          // 88: ciwi.native.v1.VaultConnectionList
+	(*UpsertVaultConnectionRequest)(nil), // 89: ciwi.native.v1.UpsertVaultConnectionRequest
+	(*VaultConnectionIDRequest)(nil),     // 90: ciwi.native.v1.VaultConnectionIDRequest
+	(*TestVaultConnectionRequest)(nil),   // 91: ciwi.native.v1.TestVaultConnectionRequest
+	(*TestVaultConnectionResult)(nil),    // 92: ciwi.native.v1.TestVaultConnectionResult
+	(*DeleteVaultConnectionResult)(nil),  // 93: ciwi.native.v1.DeleteVaultConnectionResult
+	(*ServerUpdateStatus)(nil),           // 94: ciwi.native.v1.ServerUpdateStatus
+	(*ServerUpdateCheckResult)(nil),      // 95: ciwi.native.v1.ServerUpdateCheckResult
+	(*ServerUpdateVersions)(nil),         // 96: ciwi.native.v1.ServerUpdateVersions
+	(*ServerUpdateActionRequest)(nil),    // 97: ciwi.native.v1.ServerUpdateActionRequest
+	(*ServerUpdateActionResult)(nil),     // 98: ciwi.native.v1.ServerUpdateActionResult
+	(*ClearExecutionQueueRequest)(nil),   // 99: ciwi.native.v1.ClearExecutionQueueRequest
+	(*ClearExecutionQueueResult)(nil),    // 100: ciwi.native.v1.ClearExecutionQueueResult
+	(*FlushExecutionHistoryRequest)(nil), // 101: ciwi.native.v1.FlushExecutionHistoryRequest
+	(*FlushExecutionHistoryResult)(nil),  // 102: ciwi.native.v1.FlushExecutionHistoryResult
+	(*RemoveQueuedExecutionResult)(nil),  // 103: ciwi.native.v1.RemoveQueuedExecutionResult
+	(*CommandReceiptStatusRequest)(nil),  // 104: ciwi.native.v1.CommandReceiptStatusRequest
+	(*CommandReceiptStatus)(nil),         // 105: ciwi.native.v1.CommandReceiptStatus
+	(*WatchChangesRequest)(nil),          // 106: ciwi.native.v1.WatchChangesRequest
+	(*ChangeEvent)(nil),                  // 107: ciwi.native.v1.ChangeEvent
+	(*Request)(nil),                      // 108: ciwi.native.v1.Request
+	(*Response)(nil),                     // 109: ciwi.native.v1.Response
+	(*ClientMessage)(nil),                // 110: ciwi.native.v1.ClientMessage
+	(*ServerMessage)(nil),                // 111: ciwi.native.v1.ServerMessage
+	(*JobDetailRow)(nil),                 // 112: ciwi.native.v1.JobDetailRow
+	(*ToolRequirements)(nil),             // 113: ciwi.native.v1.ToolRequirements
+	(*ReportDetails)(nil),                // 114: ciwi.native.v1.ReportDetails
+	(*ReportFilter)(nil),                 // 115: ciwi.native.v1.ReportFilter
+	(*TreeNode)(nil),                     // 116: ciwi.native.v1.TreeNode
+	(*ArtifactDownloadRequest)(nil),      // 117: ciwi.native.v1.ArtifactDownloadRequest
+	(*ArtifactDownloadChunk)(nil),        // 118: ciwi.native.v1.ArtifactDownloadChunk
+	(*JobRunContext)(nil),                // 119: ciwi.native.v1.JobRunContext
+	(*JobRunContextPipeline)(nil),        // 120: ciwi.native.v1.JobRunContextPipeline
+	(*JobRunContextJob)(nil),             // 121: ciwi.native.v1.JobRunContextJob
+	(*JobRunContextExecution)(nil),       // 122: ciwi.native.v1.JobRunContextExecution
 }
 var file_ciwi_native_v1_ciwi_proto_depIdxs = []int32{
 	0,   // 0: ciwi.native.v1.ErrorStatus.code:type_name -> ciwi.native.v1.StatusCode
@@ -10889,147 +12195,161 @@ var file_ciwi_native_v1_ciwi_proto_depIdxs = []int32{
 	11,  // 3: ciwi.native.v1.ProjectList.projects:type_name -> ciwi.native.v1.ProjectSummary
 	8,   // 4: ciwi.native.v1.FrontPageView.server:type_name -> ciwi.native.v1.ServerInfo
 	11,  // 5: ciwi.native.v1.FrontPageView.projects:type_name -> ciwi.native.v1.ProjectSummary
-	48,  // 6: ciwi.native.v1.FrontPageView.queued_executions:type_name -> ciwi.native.v1.ExecutionCardSummary
-	48,  // 7: ciwi.native.v1.FrontPageView.history_executions:type_name -> ciwi.native.v1.ExecutionCardSummary
+	57,  // 6: ciwi.native.v1.FrontPageView.queued_executions:type_name -> ciwi.native.v1.ExecutionCardSummary
+	57,  // 7: ciwi.native.v1.FrontPageView.history_executions:type_name -> ciwi.native.v1.ExecutionCardSummary
 	16,  // 8: ciwi.native.v1.ProjectIconList.icons:type_name -> ciwi.native.v1.ProjectIcon
 	11,  // 9: ciwi.native.v1.ProjectDetailsView.project:type_name -> ciwi.native.v1.ProjectSummary
-	21,  // 10: ciwi.native.v1.ProjectDetailsView.pipelines:type_name -> ciwi.native.v1.ProjectPipelineDetails
-	48,  // 11: ciwi.native.v1.ProjectDetailsView.history_executions:type_name -> ciwi.native.v1.ExecutionCardSummary
-	19,  // 12: ciwi.native.v1.ProjectDetailsView.structure_filters:type_name -> ciwi.native.v1.ProjectStructureFilter
-	20,  // 13: ciwi.native.v1.ProjectStructureFilter.root:type_name -> ciwi.native.v1.ProjectStructureRoot
-	22,  // 14: ciwi.native.v1.ProjectPipelineDetails.jobs:type_name -> ciwi.native.v1.ProjectJobDetails
-	23,  // 15: ciwi.native.v1.ProjectJobDetails.steps:type_name -> ciwi.native.v1.ProjectStepDetails
-	32,  // 16: ciwi.native.v1.JobDetailsView.timeline:type_name -> ciwi.native.v1.JobTimelineItem
-	33,  // 17: ciwi.native.v1.JobDetailsView.output_groups:type_name -> ciwi.native.v1.JobOutputGroup
-	27,  // 18: ciwi.native.v1.JobDetailsView.scheduling_diagnosis:type_name -> ciwi.native.v1.SchedulingDiagnosis
-	51,  // 19: ciwi.native.v1.JobDetailsView.progress:type_name -> ciwi.native.v1.Progress
-	101, // 20: ciwi.native.v1.JobDetailsView.job_properties:type_name -> ciwi.native.v1.JobDetailRow
-	101, // 21: ciwi.native.v1.JobDetailsView.cache_statistics:type_name -> ciwi.native.v1.JobDetailRow
-	102, // 22: ciwi.native.v1.JobDetailsView.host_tool_requirements:type_name -> ciwi.native.v1.ToolRequirements
-	102, // 23: ciwi.native.v1.JobDetailsView.container_tool_requirements:type_name -> ciwi.native.v1.ToolRequirements
-	101, // 24: ciwi.native.v1.JobDetailsView.release_summary:type_name -> ciwi.native.v1.JobDetailRow
-	108, // 25: ciwi.native.v1.JobDetailsView.run_context:type_name -> ciwi.native.v1.JobRunContext
-	103, // 26: ciwi.native.v1.JobDetailsView.artifacts:type_name -> ciwi.native.v1.ReportDetails
-	103, // 27: ciwi.native.v1.JobDetailsView.test_report:type_name -> ciwi.native.v1.ReportDetails
-	103, // 28: ciwi.native.v1.JobDetailsView.coverage_report:type_name -> ciwi.native.v1.ReportDetails
-	28,  // 29: ciwi.native.v1.SchedulingDiagnosis.agents:type_name -> ciwi.native.v1.SchedulingAgentAssessment
-	51,  // 30: ciwi.native.v1.JobTimelineItem.progress:type_name -> ciwi.native.v1.Progress
-	51,  // 31: ciwi.native.v1.JobOutputGroup.progress:type_name -> ciwi.native.v1.Progress
-	36,  // 32: ciwi.native.v1.JobOutputBatch.events:type_name -> ciwi.native.v1.JobOutputEvent
-	1,   // 33: ciwi.native.v1.JobLogPageRequest.mode:type_name -> ciwi.native.v1.JobLogPageMode
-	42,  // 34: ciwi.native.v1.JobLogDescriptor.streams:type_name -> ciwi.native.v1.JobLogStream
-	44,  // 35: ciwi.native.v1.JobLogPage.chunks:type_name -> ciwi.native.v1.JobLogChunk
-	46,  // 36: ciwi.native.v1.JobLogSearchResult.match:type_name -> ciwi.native.v1.JobLogMatch
-	47,  // 37: ciwi.native.v1.ExecutionCardSummary.summary:type_name -> ciwi.native.v1.ExecutionSummary
-	49,  // 38: ciwi.native.v1.ExecutionCardSummary.sections:type_name -> ciwi.native.v1.ExecutionCardSection
-	51,  // 39: ciwi.native.v1.ExecutionCardSummary.progress:type_name -> ciwi.native.v1.Progress
-	50,  // 40: ciwi.native.v1.ExecutionCardSection.jobs:type_name -> ciwi.native.v1.ExecutionCardJob
-	51,  // 41: ciwi.native.v1.ExecutionCardSection.progress:type_name -> ciwi.native.v1.Progress
-	27,  // 42: ciwi.native.v1.ExecutionCardJob.scheduling_diagnosis:type_name -> ciwi.native.v1.SchedulingDiagnosis
-	51,  // 43: ciwi.native.v1.ExecutionCardJob.progress:type_name -> ciwi.native.v1.Progress
-	52,  // 44: ciwi.native.v1.RunPipelineRequest.selection:type_name -> ciwi.native.v1.RunPipelineSelection
-	52,  // 45: ciwi.native.v1.RunPipelineChainRequest.selection:type_name -> ciwi.native.v1.RunPipelineSelection
-	52,  // 46: ciwi.native.v1.GetRunOptionsRequest.selection:type_name -> ciwi.native.v1.RunPipelineSelection
-	58,  // 47: ciwi.native.v1.RunOptionsView.source_refs:type_name -> ciwi.native.v1.RunOption
-	58,  // 48: ciwi.native.v1.RunOptionsView.eligible_agents:type_name -> ciwi.native.v1.RunOption
-	61,  // 49: ciwi.native.v1.AgentSummary.script_shells:type_name -> ciwi.native.v1.AgentScriptShell
-	60,  // 50: ciwi.native.v1.AgentsView.agents:type_name -> ciwi.native.v1.AgentSummary
-	60,  // 51: ciwi.native.v1.AgentDetailsView.agent:type_name -> ciwi.native.v1.AgentSummary
-	76,  // 52: ciwi.native.v1.VaultConnectionList.connections:type_name -> ciwi.native.v1.VaultConnection
-	2,   // 53: ciwi.native.v1.ChangeEvent.topics:type_name -> ciwi.native.v1.ChangeTopic
-	6,   // 54: ciwi.native.v1.Request.metadata:type_name -> ciwi.native.v1.RequestMetadata
-	3,   // 55: ciwi.native.v1.Request.get_server_info:type_name -> ciwi.native.v1.Empty
-	3,   // 56: ciwi.native.v1.Request.list_projects:type_name -> ciwi.native.v1.Empty
-	14,  // 57: ciwi.native.v1.Request.get_front_page_view:type_name -> ciwi.native.v1.GetFrontPageViewRequest
-	53,  // 58: ciwi.native.v1.Request.run_pipeline:type_name -> ciwi.native.v1.RunPipelineRequest
-	95,  // 59: ciwi.native.v1.Request.watch_changes:type_name -> ciwi.native.v1.WatchChangesRequest
-	24,  // 60: ciwi.native.v1.Request.get_project_details:type_name -> ciwi.native.v1.GetProjectDetailsRequest
-	25,  // 61: ciwi.native.v1.Request.get_job_details:type_name -> ciwi.native.v1.GetJobDetailsRequest
-	34,  // 62: ciwi.native.v1.Request.watch_job_output:type_name -> ciwi.native.v1.WatchJobOutputRequest
-	88,  // 63: ciwi.native.v1.Request.clear_execution_queue:type_name -> ciwi.native.v1.ClearExecutionQueueRequest
-	90,  // 64: ciwi.native.v1.Request.flush_execution_history:type_name -> ciwi.native.v1.FlushExecutionHistoryRequest
-	29,  // 65: ciwi.native.v1.Request.cancel_execution:type_name -> ciwi.native.v1.ControlExecutionRequest
-	29,  // 66: ciwi.native.v1.Request.rerun_execution:type_name -> ciwi.native.v1.ControlExecutionRequest
-	55,  // 67: ciwi.native.v1.Request.run_pipeline_chain:type_name -> ciwi.native.v1.RunPipelineChainRequest
-	57,  // 68: ciwi.native.v1.Request.get_run_options:type_name -> ciwi.native.v1.GetRunOptionsRequest
-	3,   // 69: ciwi.native.v1.Request.get_agents_view:type_name -> ciwi.native.v1.Empty
-	65,  // 70: ciwi.native.v1.Request.agent_action:type_name -> ciwi.native.v1.AgentActionRequest
-	69,  // 71: ciwi.native.v1.Request.project_action:type_name -> ciwi.native.v1.ProjectActionRequest
-	71,  // 72: ciwi.native.v1.Request.import_project:type_name -> ciwi.native.v1.ImportProjectRequest
-	3,   // 73: ciwi.native.v1.Request.get_server_update_status:type_name -> ciwi.native.v1.Empty
-	3,   // 74: ciwi.native.v1.Request.check_server_updates:type_name -> ciwi.native.v1.Empty
-	3,   // 75: ciwi.native.v1.Request.list_server_update_versions:type_name -> ciwi.native.v1.Empty
-	86,  // 76: ciwi.native.v1.Request.server_update_action:type_name -> ciwi.native.v1.ServerUpdateActionRequest
-	29,  // 77: ciwi.native.v1.Request.remove_queued_execution:type_name -> ciwi.native.v1.ControlExecutionRequest
-	63,  // 78: ciwi.native.v1.Request.get_agent_details:type_name -> ciwi.native.v1.GetAgentDetailsRequest
-	93,  // 79: ciwi.native.v1.Request.get_command_receipt_status:type_name -> ciwi.native.v1.CommandReceiptStatusRequest
-	67,  // 80: ciwi.native.v1.Request.run_agent_script:type_name -> ciwi.native.v1.RunAgentScriptRequest
-	73,  // 81: ciwi.native.v1.Request.get_managed_yaml:type_name -> ciwi.native.v1.GetManagedYAMLRequest
-	74,  // 82: ciwi.native.v1.Request.validate_managed_yaml:type_name -> ciwi.native.v1.ManagedYAMLRequest
-	74,  // 83: ciwi.native.v1.Request.save_managed_yaml:type_name -> ciwi.native.v1.ManagedYAMLRequest
-	3,   // 84: ciwi.native.v1.Request.list_vault_connections:type_name -> ciwi.native.v1.Empty
-	78,  // 85: ciwi.native.v1.Request.upsert_vault_connection:type_name -> ciwi.native.v1.UpsertVaultConnectionRequest
-	80,  // 86: ciwi.native.v1.Request.test_vault_connection:type_name -> ciwi.native.v1.TestVaultConnectionRequest
-	79,  // 87: ciwi.native.v1.Request.delete_vault_connection:type_name -> ciwi.native.v1.VaultConnectionIDRequest
-	106, // 88: ciwi.native.v1.Request.download_artifact:type_name -> ciwi.native.v1.ArtifactDownloadRequest
-	15,  // 89: ciwi.native.v1.Request.get_project_icons:type_name -> ciwi.native.v1.GetProjectIconsRequest
-	37,  // 90: ciwi.native.v1.Request.get_job_log_descriptor:type_name -> ciwi.native.v1.JobLogDescriptorRequest
-	38,  // 91: ciwi.native.v1.Request.get_job_log_page:type_name -> ciwi.native.v1.JobLogPageRequest
-	39,  // 92: ciwi.native.v1.Request.search_job_log:type_name -> ciwi.native.v1.JobLogSearchRequest
-	40,  // 93: ciwi.native.v1.Request.watch_job_log:type_name -> ciwi.native.v1.WatchJobLogRequest
-	8,   // 94: ciwi.native.v1.Response.server_info:type_name -> ciwi.native.v1.ServerInfo
-	12,  // 95: ciwi.native.v1.Response.project_list:type_name -> ciwi.native.v1.ProjectList
-	13,  // 96: ciwi.native.v1.Response.front_page_view:type_name -> ciwi.native.v1.FrontPageView
-	54,  // 97: ciwi.native.v1.Response.run_pipeline:type_name -> ciwi.native.v1.RunPipelineResult
-	96,  // 98: ciwi.native.v1.Response.change:type_name -> ciwi.native.v1.ChangeEvent
-	7,   // 99: ciwi.native.v1.Response.error:type_name -> ciwi.native.v1.ErrorStatus
-	18,  // 100: ciwi.native.v1.Response.project_details:type_name -> ciwi.native.v1.ProjectDetailsView
-	26,  // 101: ciwi.native.v1.Response.job_details:type_name -> ciwi.native.v1.JobDetailsView
-	35,  // 102: ciwi.native.v1.Response.job_output:type_name -> ciwi.native.v1.JobOutputBatch
-	89,  // 103: ciwi.native.v1.Response.clear_execution_queue:type_name -> ciwi.native.v1.ClearExecutionQueueResult
-	91,  // 104: ciwi.native.v1.Response.flush_execution_history:type_name -> ciwi.native.v1.FlushExecutionHistoryResult
-	30,  // 105: ciwi.native.v1.Response.cancel_execution:type_name -> ciwi.native.v1.CancelExecutionResult
-	31,  // 106: ciwi.native.v1.Response.rerun_execution:type_name -> ciwi.native.v1.RerunExecutionResult
-	56,  // 107: ciwi.native.v1.Response.run_pipeline_chain:type_name -> ciwi.native.v1.RunPipelineChainResult
-	59,  // 108: ciwi.native.v1.Response.run_options:type_name -> ciwi.native.v1.RunOptionsView
-	62,  // 109: ciwi.native.v1.Response.agents_view:type_name -> ciwi.native.v1.AgentsView
-	66,  // 110: ciwi.native.v1.Response.agent_action:type_name -> ciwi.native.v1.AgentActionResult
-	70,  // 111: ciwi.native.v1.Response.project_action:type_name -> ciwi.native.v1.ProjectActionResult
-	72,  // 112: ciwi.native.v1.Response.import_project:type_name -> ciwi.native.v1.ImportProjectResult
-	83,  // 113: ciwi.native.v1.Response.server_update_status:type_name -> ciwi.native.v1.ServerUpdateStatus
-	84,  // 114: ciwi.native.v1.Response.server_update_check:type_name -> ciwi.native.v1.ServerUpdateCheckResult
-	85,  // 115: ciwi.native.v1.Response.server_update_versions:type_name -> ciwi.native.v1.ServerUpdateVersions
-	87,  // 116: ciwi.native.v1.Response.server_update_action:type_name -> ciwi.native.v1.ServerUpdateActionResult
-	92,  // 117: ciwi.native.v1.Response.remove_queued_execution:type_name -> ciwi.native.v1.RemoveQueuedExecutionResult
-	64,  // 118: ciwi.native.v1.Response.agent_details:type_name -> ciwi.native.v1.AgentDetailsView
-	94,  // 119: ciwi.native.v1.Response.command_receipt_status:type_name -> ciwi.native.v1.CommandReceiptStatus
-	68,  // 120: ciwi.native.v1.Response.run_agent_script:type_name -> ciwi.native.v1.RunAgentScriptResult
-	75,  // 121: ciwi.native.v1.Response.managed_yaml:type_name -> ciwi.native.v1.ManagedYAMLDefinition
-	77,  // 122: ciwi.native.v1.Response.vault_connection_list:type_name -> ciwi.native.v1.VaultConnectionList
-	76,  // 123: ciwi.native.v1.Response.vault_connection:type_name -> ciwi.native.v1.VaultConnection
-	81,  // 124: ciwi.native.v1.Response.test_vault_connection:type_name -> ciwi.native.v1.TestVaultConnectionResult
-	82,  // 125: ciwi.native.v1.Response.delete_vault_connection:type_name -> ciwi.native.v1.DeleteVaultConnectionResult
-	107, // 126: ciwi.native.v1.Response.artifact_download:type_name -> ciwi.native.v1.ArtifactDownloadChunk
-	17,  // 127: ciwi.native.v1.Response.project_icons:type_name -> ciwi.native.v1.ProjectIconList
-	41,  // 128: ciwi.native.v1.Response.job_log_descriptor:type_name -> ciwi.native.v1.JobLogDescriptor
-	43,  // 129: ciwi.native.v1.Response.job_log_page:type_name -> ciwi.native.v1.JobLogPage
-	45,  // 130: ciwi.native.v1.Response.job_log_search:type_name -> ciwi.native.v1.JobLogSearchResult
-	4,   // 131: ciwi.native.v1.ClientMessage.hello:type_name -> ciwi.native.v1.Hello
-	97,  // 132: ciwi.native.v1.ClientMessage.request:type_name -> ciwi.native.v1.Request
-	5,   // 133: ciwi.native.v1.ServerMessage.welcome:type_name -> ciwi.native.v1.Welcome
-	98,  // 134: ciwi.native.v1.ServerMessage.response:type_name -> ciwi.native.v1.Response
-	101, // 135: ciwi.native.v1.ReportDetails.rows:type_name -> ciwi.native.v1.JobDetailRow
-	105, // 136: ciwi.native.v1.ReportDetails.nodes:type_name -> ciwi.native.v1.TreeNode
-	104, // 137: ciwi.native.v1.ReportDetails.filters:type_name -> ciwi.native.v1.ReportFilter
-	105, // 138: ciwi.native.v1.TreeNode.children:type_name -> ciwi.native.v1.TreeNode
-	109, // 139: ciwi.native.v1.JobRunContext.pipelines:type_name -> ciwi.native.v1.JobRunContextPipeline
-	110, // 140: ciwi.native.v1.JobRunContextPipeline.jobs:type_name -> ciwi.native.v1.JobRunContextJob
-	111, // 141: ciwi.native.v1.JobRunContextJob.executions:type_name -> ciwi.native.v1.JobRunContextExecution
-	142, // [142:142] is the sub-list for method output_type
-	142, // [142:142] is the sub-list for method input_type
-	142, // [142:142] is the sub-list for extension type_name
-	142, // [142:142] is the sub-list for extension extendee
-	0,   // [0:142] is the sub-list for field type_name
+	27,  // 10: ciwi.native.v1.ProjectDetailsView.pipelines:type_name -> ciwi.native.v1.ProjectPipelineDetails
+	57,  // 11: ciwi.native.v1.ProjectDetailsView.history_executions:type_name -> ciwi.native.v1.ExecutionCardSummary
+	25,  // 12: ciwi.native.v1.ProjectDetailsView.structure_filters:type_name -> ciwi.native.v1.ProjectStructureFilter
+	21,  // 13: ciwi.native.v1.ProjectDetailsView.test_quarantine:type_name -> ciwi.native.v1.TestQuarantineEntry
+	19,  // 14: ciwi.native.v1.ProjectDetailsView.coverage_trends:type_name -> ciwi.native.v1.CoverageTrend
+	20,  // 15: ciwi.native.v1.CoverageTrend.points:type_name -> ciwi.native.v1.CoverageTrendPoint
+	60,  // 16: ciwi.native.v1.CoverageTrendPoint.progress:type_name -> ciwi.native.v1.Progress
+	26,  // 17: ciwi.native.v1.ProjectStructureFilter.root:type_name -> ciwi.native.v1.ProjectStructureRoot
+	28,  // 18: ciwi.native.v1.ProjectPipelineDetails.jobs:type_name -> ciwi.native.v1.ProjectJobDetails
+	29,  // 19: ciwi.native.v1.ProjectJobDetails.steps:type_name -> ciwi.native.v1.ProjectStepDetails
+	38,  // 20: ciwi.native.v1.JobDetailsView.timeline:type_name -> ciwi.native.v1.JobTimelineItem
+	39,  // 21: ciwi.native.v1.JobDetailsView.output_groups:type_name -> ciwi.native.v1.JobOutputGroup
+	33,  // 22: ciwi.native.v1.JobDetailsView.scheduling_diagnosis:type_name -> ciwi.native.v1.SchedulingDiagnosis
+	60,  // 23: ciwi.native.v1.JobDetailsView.progress:type_name -> ciwi.native.v1.Progress
+	112, // 24: ciwi.native.v1.JobDetailsView.job_properties:type_name -> ciwi.native.v1.JobDetailRow
+	112, // 25: ciwi.native.v1.JobDetailsView.cache_statistics:type_name -> ciwi.native.v1.JobDetailRow
+	113, // 26: ciwi.native.v1.JobDetailsView.host_tool_requirements:type_name -> ciwi.native.v1.ToolRequirements
+	113, // 27: ciwi.native.v1.JobDetailsView.container_tool_requirements:type_name -> ciwi.native.v1.ToolRequirements
+	112, // 28: ciwi.native.v1.JobDetailsView.release_summary:type_name -> ciwi.native.v1.JobDetailRow
+	119, // 29: ciwi.native.v1.JobDetailsView.run_context:type_name -> ciwi.native.v1.JobRunContext
+	114, // 30: ciwi.native.v1.JobDetailsView.artifacts:type_name -> ciwi.native.v1.ReportDetails
+	114, // 31: ciwi.native.v1.JobDetailsView.test_report:type_name -> ciwi.native.v1.ReportDetails
+	114, // 32: ciwi.native.v1.JobDetailsView.coverage_report:type_name -> ciwi.native.v1.ReportDetails
+	34,  // 33: ciwi.native.v1.SchedulingDiagnosis.agents:type_name -> ciwi.native.v1.SchedulingAgentAssessment
+	60,  // 34: ciwi.native.v1.JobTimelineItem.progress:type_name -> ciwi.native.v1.Progress
+	60,  // 35: ciwi.native.v1.JobOutputGroup.progress:type_name -> ciwi.native.v1.Progress
+	42,  // 36: ciwi.native.v1.JobOutputBatch.events:type_name -> ciwi.native.v1.JobOutputEvent
+	1,   // 37: ciwi.native.v1.JobLogPageRequest.mode:type_name -> ciwi.native.v1.JobLogPageMode
+	48,  // 38: ciwi.native.v1.JobLogDescriptor.streams:type_name -> ciwi.native.v1.JobLogStream
+	50,  // 39: ciwi.native.v1.JobLogPage.chunks:type_name -> ciwi.native.v1.JobLogChunk
+	52,  // 40: ciwi.native.v1.JobLogSearchResult.match:type_name -> ciwi.native.v1.JobLogMatch
+	55,  // 41: ciwi.native.v1.TestHistoryView.tests:type_name -> ciwi.native.v1.TestHistoryTest
+	56,  // 42: ciwi.native.v1.ExecutionCardSummary.summary:type_name -> ciwi.native.v1.ExecutionSummary
+	58,  // 43: ciwi.native.v1.ExecutionCardSummary.sections:type_name -> ciwi.native.v1.ExecutionCardSection
+	60,  // 44: ciwi.native.v1.ExecutionCardSummary.progress:type_name -> ciwi.native.v1.Progress
+	59,  // 45: ciwi.native.v1.ExecutionCardSection.jobs:type_name -> ciwi.native.v1.ExecutionCardJob
+	60,  // 46: ciwi.native.v1.ExecutionCardSection.progress:type_name -> ciwi.native.v1.Progress
+	33,  // 47: ciwi.native.v1.ExecutionCardJob.scheduling_diagnosis:type_name -> ciwi.native.v1.SchedulingDiagnosis
+	60,  // 48: ciwi.native.v1.ExecutionCardJob.progress:type_name -> ciwi.native.v1.Progress
+	61,  // 49: ciwi.native.v1.RunPipelineRequest.selection:type_name -> ciwi.native.v1.RunPipelineSelection
+	61,  // 50: ciwi.native.v1.RunPipelineChainRequest.selection:type_name -> ciwi.native.v1.RunPipelineSelection
+	61,  // 51: ciwi.native.v1.GetRunOptionsRequest.selection:type_name -> ciwi.native.v1.RunPipelineSelection
+	67,  // 52: ciwi.native.v1.RunOptionsView.source_refs:type_name -> ciwi.native.v1.RunOption
+	67,  // 53: ciwi.native.v1.RunOptionsView.eligible_agents:type_name -> ciwi.native.v1.RunOption
+	67,  // 54: ciwi.native.v1.RunOptionsView.priority_options:type_name -> ciwi.native.v1.RunOption
+	70,  // 55: ciwi.native.v1.AgentSummary.script_shells:type_name -> ciwi.native.v1.AgentScriptShell
+	71,  // 56: ciwi.native.v1.AgentSummary.slot_options:type_name -> ciwi.native.v1.AgentSlotOption
+	69,  // 57: ciwi.native.v1.AgentsView.agents:type_name -> ciwi.native.v1.AgentSummary
+	69,  // 58: ciwi.native.v1.AgentDetailsView.agent:type_name -> ciwi.native.v1.AgentSummary
+	76,  // 59: ciwi.native.v1.AgentActionRequest.labels:type_name -> ciwi.native.v1.AgentLabel
+	87,  // 60: ciwi.native.v1.VaultConnectionList.connections:type_name -> ciwi.native.v1.VaultConnection
+	2,   // 61: ciwi.native.v1.ChangeEvent.topics:type_name -> ciwi.native.v1.ChangeTopic
+	6,   // 62: ciwi.native.v1.Request.metadata:type_name -> ciwi.native.v1.RequestMetadata
+	3,   // 63: ciwi.native.v1.Request.get_server_info:type_name -> ciwi.native.v1.Empty
+	3,   // 64: ciwi.native.v1.Request.list_projects:type_name -> ciwi.native.v1.Empty
+	14,  // 65: ciwi.native.v1.Request.get_front_page_view:type_name -> ciwi.native.v1.GetFrontPageViewRequest
+	62,  // 66: ciwi.native.v1.Request.run_pipeline:type_name -> ciwi.native.v1.RunPipelineRequest
+	106, // 67: ciwi.native.v1.Request.watch_changes:type_name -> ciwi.native.v1.WatchChangesRequest
+	30,  // 68: ciwi.native.v1.Request.get_project_details:type_name -> ciwi.native.v1.GetProjectDetailsRequest
+	31,  // 69: ciwi.native.v1.Request.get_job_details:type_name -> ciwi.native.v1.GetJobDetailsRequest
+	40,  // 70: ciwi.native.v1.Request.watch_job_output:type_name -> ciwi.native.v1.WatchJobOutputRequest
+	99,  // 71: ciwi.native.v1.Request.clear_execution_queue:type_name -> ciwi.native.v1.ClearExecutionQueueRequest
+	101, // 72: ciwi.native.v1.Request.flush_execution_history:type_name -> ciwi.native.v1.FlushExecutionHistoryRequest
+	35,  // 73: ciwi.native.v1.Request.cancel_execution:type_name -> ciwi.native.v1.ControlExecutionRequest
+	35,  // 74: ciwi.native.v1.Request.rerun_execution:type_name -> ciwi.native.v1.ControlExecutionRequest
+	64,  // 75: ciwi.native.v1.Request.run_pipeline_chain:type_name -> ciwi.native.v1.RunPipelineChainRequest
+	66,  // 76: ciwi.native.v1.Request.get_run_options:type_name -> ciwi.native.v1.GetRunOptionsRequest
+	3,   // 77: ciwi.native.v1.Request.get_agents_view:type_name -> ciwi.native.v1.Empty
+	75,  // 78: ciwi.native.v1.Request.agent_action:type_name -> ciwi.native.v1.AgentActionRequest
+	80,  // 79: ciwi.native.v1.Request.project_action:type_name -> ciwi.native.v1.ProjectActionRequest
+	82,  // 80: ciwi.native.v1.Request.import_project:type_name -> ciwi.native.v1.ImportProjectRequest
+	3,   // 81: ciwi.native.v1.Request.get_server_update_status:type_name -> ciwi.native.v1.Empty
+	3,   // 82: ciwi.native.v1.Request.check_server_updates:type_name -> ciwi.native.v1.Empty
+	3,   // 83: ciwi.native.v1.Request.list_server_update_versions:type_name -> ciwi.native.v1.Empty
+	97,  // 84: ciwi.native.v1.Request.server_update_action:type_name -> ciwi.native.v1.ServerUpdateActionRequest
+	35,  // 85: ciwi.native.v1.Request.remove_queued_execution:type_name -> ciwi.native.v1.ControlExecutionRequest
+	73,  // 86: ciwi.native.v1.Request.get_agent_details:type_name -> ciwi.native.v1.GetAgentDetailsRequest
+	104, // 87: ciwi.native.v1.Request.get_command_receipt_status:type_name -> ciwi.native.v1.CommandReceiptStatusRequest
+	78,  // 88: ciwi.native.v1.Request.run_agent_script:type_name -> ciwi.native.v1.RunAgentScriptRequest
+	84,  // 89: ciwi.native.v1.Request.get_managed_yaml:type_name -> ciwi.native.v1.GetManagedYAMLRequest
+	85,  // 90: ciwi.native.v1.Request.validate_managed_yaml:type_name -> ciwi.native.v1.ManagedYAMLRequest
+	85,  // 91: ciwi.native.v1.Request.save_managed_yaml:type_name -> ciwi.native.v1.ManagedYAMLRequest
+	3,   // 92: ciwi.native.v1.Request.list_vault_connections:type_name -> ciwi.native.v1.Empty
+	89,  // 93: ciwi.native.v1.Request.upsert_vault_connection:type_name -> ciwi.native.v1.UpsertVaultConnectionRequest
+	91,  // 94: ciwi.native.v1.Request.test_vault_connection:type_name -> ciwi.native.v1.TestVaultConnectionRequest
+	90,  // 95: ciwi.native.v1.Request.delete_vault_connection:type_name -> ciwi.native.v1.VaultConnectionIDRequest
+	117, // 96: ciwi.native.v1.Request.download_artifact:type_name -> ciwi.native.v1.ArtifactDownloadRequest
+	15,  // 97: ciwi.native.v1.Request.get_project_icons:type_name -> ciwi.native.v1.GetProjectIconsRequest
+	43,  // 98: ciwi.native.v1.Request.get_job_log_descriptor:type_name -> ciwi.native.v1.JobLogDescriptorRequest
+	44,  // 99: ciwi.native.v1.Request.get_job_log_page:type_name -> ciwi.native.v1.JobLogPageRequest
+	45,  // 100: ciwi.native.v1.Request.search_job_log:type_name -> ciwi.native.v1.JobLogSearchRequest
+	46,  // 101: ciwi.native.v1.Request.watch_job_log:type_name -> ciwi.native.v1.WatchJobLogRequest
+	53,  // 102: ciwi.native.v1.Request.get_test_history:type_name -> ciwi.native.v1.GetTestHistoryRequest
+	22,  // 103: ciwi.native.v1.Request.add_test_quarantine:type_name -> ciwi.native.v1.AddTestQuarantineRequest
+	23,  // 104: ciwi.native.v1.Request.remove_test_quarantine:type_name -> ciwi.native.v1.RemoveTestQuarantineRequest
+	8,   // 105: ciwi.native.v1.Response.server_info:type_name -> ciwi.native.v1.ServerInfo
+	12,  // 106: ciwi.native.v1.Response.project_list:type_name -> ciwi.native.v1.ProjectList
+	13,  // 107: ciwi.native.v1.Response.front_page_view:type_name -> ciwi.native.v1.FrontPageView
+	63,  // 108: ciwi.native.v1.Response.run_pipeline:type_name -> ciwi.native.v1.RunPipelineResult
+	107, // 109: ciwi.native.v1.Response.change:type_name -> ciwi.native.v1.ChangeEvent
+	7,   // 110: ciwi.native.v1.Response.error:type_name -> ciwi.native.v1.ErrorStatus
+	18,  // 111: ciwi.native.v1.Response.project_details:type_name -> ciwi.native.v1.ProjectDetailsView
+	32,  // 112: ciwi.native.v1.Response.job_details:type_name -> ciwi.native.v1.JobDetailsView
+	41,  // 113: ciwi.native.v1.Response.job_output:type_name -> ciwi.native.v1.JobOutputBatch
+	100, // 114: ciwi.native.v1.Response.clear_execution_queue:type_name -> ciwi.native.v1.ClearExecutionQueueResult
+	102, // 115: ciwi.native.v1.Response.flush_execution_history:type_name -> ciwi.native.v1.FlushExecutionHistoryResult
+	36,  // 116: ciwi.native.v1.Response.cancel_execution:type_name -> ciwi.native.v1.CancelExecutionResult
+	37,  // 117: ciwi.native.v1.Response.rerun_execution:type_name -> ciwi.native.v1.RerunExecutionResult
+	65,  // 118: ciwi.native.v1.Response.run_pipeline_chain:type_name -> ciwi.native.v1.RunPipelineChainResult
+	68,  // 119: ciwi.native.v1.Response.run_options:type_name -> ciwi.native.v1.RunOptionsView
+	72,  // 120: ciwi.native.v1.Response.agents_view:type_name -> ciwi.native.v1.AgentsView
+	77,  // 121: ciwi.native.v1.Response.agent_action:type_name -> ciwi.native.v1.AgentActionResult
+	81,  // 122: ciwi.native.v1.Response.project_action:type_name -> ciwi.native.v1.ProjectActionResult
+	83,  // 123: ciwi.native.v1.Response.import_project:type_name -> ciwi.native.v1.ImportProjectResult
+	94,  // 124: ciwi.native.v1.Response.server_update_status:type_name -> ciwi.native.v1.ServerUpdateStatus
+	95,  // 125: ciwi.native.v1.Response.server_update_check:type_name -> ciwi.native.v1.ServerUpdateCheckResult
+	96,  // 126: ciwi.native.v1.Response.server_update_versions:type_name -> ciwi.native.v1.ServerUpdateVersions
+	98,  // 127: ciwi.native.v1.Response.server_update_action:type_name -> ciwi.native.v1.ServerUpdateActionResult
+	103, // 128: ciwi.native.v1.Response.remove_queued_execution:type_name -> ciwi.native.v1.RemoveQueuedExecutionResult
+	74,  // 129: ciwi.native.v1.Response.agent_details:type_name -> ciwi.native.v1.AgentDetailsView
+	105, // 130: ciwi.native.v1.Response.command_receipt_status:type_name -> ciwi.native.v1.CommandReceiptStatus
+	79,  // 131: ciwi.native.v1.Response.run_agent_script:type_name -> ciwi.native.v1.RunAgentScriptResult
+	86,  // 132: ciwi.native.v1.Response.managed_yaml:type_name -> ciwi.native.v1.ManagedYAMLDefinition
+	88,  // 133: ciwi.native.v1.Response.vault_connection_list:type_name -> ciwi.native.v1.VaultConnectionList
+	87,  // 134: ciwi.native.v1.Response.vault_connection:type_name -> ciwi.native.v1.VaultConnection
+	92,  // 135: ciwi.native.v1.Response.test_vault_connection:type_name -> ciwi.native.v1.TestVaultConnectionResult
+	93,  // 136: ciwi.native.v1.Response.delete_vault_connection:type_name -> ciwi.native.v1.DeleteVaultConnectionResult
+	118, // 137: ciwi.native.v1.Response.artifact_download:type_name -> ciwi.native.v1.ArtifactDownloadChunk
+	17,  // 138: ciwi.native.v1.Response.project_icons:type_name -> ciwi.native.v1.ProjectIconList
+	47,  // 139: ciwi.native.v1.Response.job_log_descriptor:type_name -> ciwi.native.v1.JobLogDescriptor
+	49,  // 140: ciwi.native.v1.Response.job_log_page:type_name -> ciwi.native.v1.JobLogPage
+	51,  // 141: ciwi.native.v1.Response.job_log_search:type_name -> ciwi.native.v1.JobLogSearchResult
+	54,  // 142: ciwi.native.v1.Response.test_history:type_name -> ciwi.native.v1.TestHistoryView
+	21,  // 143: ciwi.native.v1.Response.test_quarantine_entry:type_name -> ciwi.native.v1.TestQuarantineEntry
+	24,  // 144: ciwi.native.v1.Response.remove_test_quarantine:type_name -> ciwi.native.v1.RemoveTestQuarantineResult
+	4,   // 145: ciwi.native.v1.ClientMessage.hello:type_name -> ciwi.native.v1.Hello
+	108, // 146: ciwi.native.v1.ClientMessage.request:type_name -> ciwi.native.v1.Request
+	5,   // 147: ciwi.native.v1.ServerMessage.welcome:type_name -> ciwi.native.v1.Welcome
+	109, // 148: ciwi.native.v1.ServerMessage.response:type_name -> ciwi.native.v1.Response
+	112, // 149: ciwi.native.v1.ReportDetails.rows:type_name -> ciwi.native.v1.JobDetailRow
+	116, // 150: ciwi.native.v1.ReportDetails.nodes:type_name -> ciwi.native.v1.TreeNode
+	115, // 151: ciwi.native.v1.ReportDetails.filters:type_name -> ciwi.native.v1.ReportFilter
+	116, // 152: ciwi.native.v1.TreeNode.children:type_name -> ciwi.native.v1.TreeNode
+	120, // 153: ciwi.native.v1.JobRunContext.pipelines:type_name -> ciwi.native.v1.JobRunContextPipeline
+	121, // 154: ciwi.native.v1.JobRunContextPipeline.jobs:type_name -> ciwi.native.v1.JobRunContextJob
+	122, // 155: ciwi.native.v1.JobRunContextJob.executions:type_name -> ciwi.native.v1.JobRunContextExecution
+	156, // [156:156] is the sub-list for method output_type
+	156, // [156:156] is the sub-list for method input_type
+	156, // [156:156] is the sub-list for extension type_name
+	156, // [156:156] is the sub-list for extension extendee
+	0,   // [0:156] is the sub-list for field type_name
 }
 
 func init() { file_ciwi_native_v1_ciwi_proto_init() }
@@ -11037,8 +12357,8 @@ func file_ciwi_native_v1_ciwi_proto_init() {
 	if File_ciwi_native_v1_ciwi_proto != nil {
 		return
 	}
-	file_ciwi_native_v1_ciwi_proto_msgTypes[49].OneofWrappers = []any{}
-	file_ciwi_native_v1_ciwi_proto_msgTypes[94].OneofWrappers = []any{
+	file_ciwi_native_v1_ciwi_proto_msgTypes[58].OneofWrappers = []any{}
+	file_ciwi_native_v1_ciwi_proto_msgTypes[105].OneofWrappers = []any{
 		(*Request_GetServerInfo)(nil),
 		(*Request_ListProjects)(nil),
 		(*Request_GetFrontPageView)(nil),
@@ -11078,8 +12398,11 @@ func file_ciwi_native_v1_ciwi_proto_init() {
 		(*Request_GetJobLogPage)(nil),
 		(*Request_SearchJobLog)(nil),
 		(*Request_WatchJobLog)(nil),
+		(*Request_GetTestHistory)(nil),
+		(*Request_AddTestQuarantine)(nil),
+		(*Request_RemoveTestQuarantine)(nil),
 	}
-	file_ciwi_native_v1_ciwi_proto_msgTypes[95].OneofWrappers = []any{
+	file_ciwi_native_v1_ciwi_proto_msgTypes[106].OneofWrappers = []any{
 		(*Response_ServerInfo)(nil),
 		(*Response_ProjectList)(nil),
 		(*Response_FrontPageView)(nil),
@@ -11117,12 +12440,15 @@ func file_ciwi_native_v1_ciwi_proto_init() {
 		(*Response_JobLogDescriptor)(nil),
 		(*Response_JobLogPage)(nil),
 		(*Response_JobLogSearch)(nil),
+		(*Response_TestHistory)(nil),
+		(*Response_TestQuarantineEntry)(nil),
+		(*Response_RemoveTestQuarantine)(nil),
 	}
-	file_ciwi_native_v1_ciwi_proto_msgTypes[96].OneofWrappers = []any{
+	file_ciwi_native_v1_ciwi_proto_msgTypes[107].OneofWrappers = []any{
 		(*ClientMessage_Hello)(nil),
 		(*ClientMessage_Request)(nil),
 	}
-	file_ciwi_native_v1_ciwi_proto_msgTypes[97].OneofWrappers = []any{
+	file_ciwi_native_v1_ciwi_proto_msgTypes[108].OneofWrappers = []any{
 		(*ServerMessage_Welcome)(nil),
 		(*ServerMessage_Response)(nil),
 	}
@@ -11132,7 +12458,7 @@ func file_ciwi_native_v1_ciwi_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_ciwi_native_v1_ciwi_proto_rawDesc), len(file_ciwi_native_v1_ciwi_proto_rawDesc)),
 			NumEnums:      3,
-			NumMessages:   109,
+			NumMessages:   120,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
@@ -37,6 +37,19 @@ type Client struct {
 	projectIcons *ProjectIconCache
 }
 
+type apiTokenContextKey struct{}
+
+// WithAPIToken returns a context whose dials present token in the hello.
+// Servers that have user accounts reject sessions without a valid token.
+func WithAPIToken(ctx context.Context, token string) context.Context {
+	return context.WithValue(ctx, apiTokenContextKey{}, strings.TrimSpace(token))
+}
+
+func apiTokenFromContext(ctx context.Context) string {
+	token, _ := ctx.Value(apiTokenContextKey{}).(string)
+	return token
+}
+
 func Dial(ctx context.Context, address, clientName, clientVersion string) (*Client, error) {
 	return DialWithProjectIconCache(ctx, address, clientName, clientVersion, nil)
 }
@@ -107,6 +120,7 @@ func (c *Client) hello(ctx context.Context, clientName, clientVersion string) er
 	message := &cnpv1.ClientMessage{Body: &cnpv1.ClientMessage_Hello{Hello: &cnpv1.Hello{
 		ClientName: clientName, ClientVersion: clientVersion,
 		Capabilities: []string{"protobuf", "invalidation_stream", "job_output_stream", "job_log_v1"},
+		ApiToken:     apiTokenFromContext(ctx),
 	}}}
 	if err := cnp.Write(stream, message); err != nil {
 		stream.CancelRead()
@@ -389,6 +403,36 @@ func (c *Client) ProjectAction(ctx context.Context, projectID int64, action, ide
 	return nil, unexpectedResult(response)
 }
 
+func (c *Client) AddTestQuarantine(ctx context.Context, request *cnpv1.AddTestQuarantineRequest, idempotencyKey string) (*cnpv1.TestQuarantineEntry, error) {
+	if idempotencyKey == "" {
+		idempotencyKey = uuid.NewString()
+	}
+	response, err := c.call(ctx, &cnpv1.Request{Operation: &cnpv1.Request_AddTestQuarantine{AddTestQuarantine: request}}, idempotencyKey)
+	if err != nil {
+		return nil, err
+	}
+	if result := response.GetTestQuarantineEntry(); result != nil {
+		return result, nil
+	}
+	return nil, unexpectedResult(response)
+}
+
+func (c *Client) RemoveTestQuarantine(ctx context.Context, projectID, id int64, idempotencyKey string) (*cnpv1.RemoveTestQuarantineResult, error) {
+	if idempotencyKey == "" {
+		idempotencyKey = uuid.NewString()
+	}
+	response, err := c.call(ctx, &cnpv1.Request{Operation: &cnpv1.Request_RemoveTestQuarantine{
+		RemoveTestQuarantine: &cnpv1.RemoveTestQuarantineRequest{ProjectId: projectID, Id: id},
+	}}, idempotencyKey)
+	if err != nil {
+		return nil, err
+	}
+	if result := response.GetRemoveTestQuarantine(); result != nil {
+		return result, nil
+	}
+	return nil, unexpectedResult(response)
+}
+
 func (c *Client) ImportProject(ctx context.Context, request *cnpv1.ImportProjectRequest, idempotencyKey string) (*cnpv1.ImportProjectResult, error) {
 	if idempotencyKey == "" {
 		idempotencyKey = uuid.NewString()